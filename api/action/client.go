@@ -76,13 +76,31 @@ func (c *Client) ListCompleted(arg params.Entities) (params.ActionsByReceivers,
 	return results, err
 }
 
-// Cancel attempts to cancel a queued up Action from running.
-func (c *Client) Cancel(arg params.Actions) (params.ActionResults, error) {
+// Cancel attempts to cancel the Actions with the given tags, stopping
+// any that are already running.
+func (c *Client) Cancel(arg params.Entities) (params.ActionResults, error) {
 	results := params.ActionResults{}
 	err := c.facade.FacadeCall("Cancel", arg, &results)
 	return results, err
 }
 
+// EnqueueServiceActions takes a list of ServiceActions and queues each
+// up to be executed by every unit of the designated service, returning
+// the operation that tracks the progress of each.
+func (c *Client) EnqueueServiceActions(arg params.ServiceActions) (params.ActionOperationResults, error) {
+	results := params.ActionOperationResults{}
+	err := c.facade.FacadeCall("EnqueueServiceActions", arg, &results)
+	return results, err
+}
+
+// ActionOperations takes a list of action operation ids, or unique
+// prefixes of them, and returns the progress of each operation.
+func (c *Client) ActionOperations(arg params.ActionOperationIds) (params.ActionOperationResults, error) {
+	results := params.ActionOperationResults{}
+	err := c.facade.FacadeCall("ActionOperations", arg, &results)
+	return results, err
+}
+
 // servicesCharmActions is a batched query for the charm.Actions for a slice
 // of services by Entity.
 func (c *Client) servicesCharmActions(arg params.Entities) (params.ServicesCharmActionsResults, error) {
@@ -22,6 +22,7 @@ import (
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/jsoncodec"
+	"github.com/juju/juju/tracing"
 	"github.com/juju/juju/version"
 )
 
@@ -31,6 +32,18 @@ var logger = loggo.GetLogger("juju.api")
 // will run. It's a variable so it can be changed in tests.
 var PingPeriod = 1 * time.Minute
 
+var (
+	// rateLimitRetryDelay holds how long APICall waits before
+	// retrying a call rejected because the client was rate limited.
+	// The delay doubles with each retry.
+	rateLimitRetryDelay = 100 * time.Millisecond
+
+	// rateLimitMaxRetries holds the number of times APICall retries
+	// a call rejected because the client was rate limited, before
+	// returning the error.
+	rateLimitMaxRetries = 6
+)
+
 type State struct {
 	client *rpc.Conn
 	conn   *websocket.Conn
@@ -83,6 +96,10 @@ type State struct {
 	// certPool holds the cert pool that is used to authenticate the tls
 	// connections to the API.
 	certPool *x509.CertPool
+
+	// traceSpan, if non-nil, is the span on whose behalf API calls
+	// are traced.
+	traceSpan *tracing.Span
 }
 
 // Info encapsulates information about a server holding juju state and
@@ -127,6 +144,10 @@ type DialOpts struct {
 	// RetryDelay is the amount of time to wait between
 	// unsucssful connection attempts.
 	RetryDelay time.Duration
+
+	// TraceSpan, if non-nil, is the span on whose behalf the API
+	// calls made through the connection are traced.
+	TraceSpan *tracing.Span
 }
 
 // DefaultDialOpts returns a DialOpts representing the default
@@ -167,9 +188,10 @@ func open(info *Info, opts DialOpts, loginFunc func(st *State, tag, pwd, nonce s
 		serverRootAddress: conn.Config().Location.Host,
 		// why are the contents of the tag (username and password) written into the
 		// state structure BEFORE login ?!?
-		tag:      toString(info.Tag),
-		password: info.Password,
-		certPool: conn.Config().TlsConfig.RootCAs,
+		tag:       toString(info.Tag),
+		password:  info.Password,
+		certPool:  conn.Config().TlsConfig.RootCAs,
+		traceSpan: opts.TraceSpan,
 	}
 	if info.Tag != nil || info.Password != "" {
 		if err := loginFunc(st, info.Tag.String(), info.Password, info.Nonce); err != nil {
@@ -351,14 +373,29 @@ func (s *State) Ping() error {
 // This fills out the rpc.Request on the given facade, version for a given
 // object id, and the specific RPC method. It marshalls the Arguments, and will
 // unmarshall the result into the response object that is supplied.
+//
+// Calls rejected because the client is making requests faster than the
+// server allows are retried, with exponential backoff.
 func (s *State) APICall(facade string, version int, id, method string, args, response interface{}) error {
-	err := s.client.Call(rpc.Request{
-		Type:    facade,
-		Version: version,
-		Id:      id,
-		Action:  method,
-	}, args, response)
-	return params.ClientError(err)
+	delay := rateLimitRetryDelay
+	for retries := 0; ; retries++ {
+		err := params.ClientError(s.client.CallTraced(s.traceSpan, rpc.Request{
+			Type:    facade,
+			Version: version,
+			Id:      id,
+			Action:  method,
+		}, args, response))
+		if !params.IsCodeRateLimited(err) || retries >= rateLimitMaxRetries {
+			return err
+		}
+		logger.Debugf("%s.%s call rate limited, retrying in %v", facade, method, delay)
+		select {
+		case <-time.After(delay):
+		case <-s.closed:
+			return err
+		}
+		delay *= 2
+	}
 }
 
 func (s *State) Close() error {
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net"
 	"strconv"
+	"time"
 
 	"golang.org/x/net/websocket"
 
@@ -231,6 +232,52 @@ func (s *apiclientSuite) TestDialWebsocketStopped(c *gc.C) {
 	c.Assert(result, gc.IsNil)
 }
 
+// openRateLimited opens an API connection on which the admin user may
+// make one request per second.
+func (s *apiclientSuite) openRateLimited(c *gc.C) *api.State {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"api-request-rate-user": 1}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	st, err := api.Open(s.APIInfo(c), api.DialOpts{})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { st.Close() })
+	return st
+}
+
+func (s *apiclientSuite) TestAPICallRetriesWhenRateLimited(c *gc.C) {
+	s.PatchValue(api.RateLimitRetryDelay, 50*time.Millisecond)
+	st := s.openRateLimited(c)
+	// The second call is rejected until the allowance used by the
+	// first is refilled.
+	for i := 0; i < 2; i++ {
+		_, err := st.Client().EnvironmentGet()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *apiclientSuite) TestAPICallGivesUpWhenRateLimited(c *gc.C) {
+	s.PatchValue(api.RateLimitMaxRetries, 0)
+	st := s.openRateLimited(c)
+	_, err := st.Client().EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.Client().EnvironmentGet()
+	c.Assert(err, jc.Satisfies, params.IsCodeRateLimited)
+}
+
+func (s *apiclientSuite) TestAPICallDoesNotRetryTooManyWatchers(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"api-max-watchers": 1}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(api.RateLimitRetryDelay, time.Hour)
+	st, err := api.Open(s.APIInfo(c), api.DialOpts{})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	_, err = st.Client().WatchAll()
+	c.Assert(err, jc.ErrorIsNil)
+	// The call fails at once, rather than waiting to be retried.
+	_, err = st.Client().WatchAll()
+	c.Assert(err, jc.Satisfies, params.IsCodeTooManyWatchers)
+}
+
 func assertConnAddrForEnv(c *gc.C, conn *websocket.Conn, addr, envUUID, tail string) {
 	c.Assert(conn.RemoteAddr(), gc.Matches, "^wss://"+addr+"/environment/"+envUUID+tail+"$")
 }
@@ -0,0 +1,63 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/auditlog"
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type auditLogSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&auditLogSuite{})
+
+func (s *auditLogSuite) TestRecords(c *gc.C) {
+	since := time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+	filter := params.AuditRecordFilter{
+		User:  "user-bob",
+		Since: &since,
+		Limit: 10,
+	}
+	records := []params.AuditRecord{{
+		Timestamp: since,
+		User:      "user-bob",
+		Facade:    "Client",
+		Method:    "ServiceDeploy",
+	}}
+	var called bool
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		called = true
+		c.Check(objType, gc.Equals, "AuditLog")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "Records")
+		c.Check(arg, jc.DeepEquals, filter)
+		c.Assert(result, gc.FitsTypeOf, &params.AuditRecordResults{})
+		*(result.(*params.AuditRecordResults)) = params.AuditRecordResults{Results: records}
+		return nil
+	})
+	client := auditlog.NewClient(apiCaller)
+	found, err := client.Records(filter)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(found, jc.DeepEquals, records)
+}
+
+func (s *auditLogSuite) TestRecordsError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("boom")
+	})
+	client := auditlog.NewClient(apiCaller)
+	_, err := client.Records(params.AuditRecordFilter{})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -9,11 +9,18 @@ import (
 	"github.com/juju/juju/apiserver/params"
 )
 
-// Create sends a request to create a backup of juju's state.  It
-// returns the metadata associated with the resulting backup.
-func (c *Client) Create(notes string) (*params.BackupsMetadataResult, error) {
+// Create sends a request to create a backup of juju's state, stored in
+// the given backup target (or the environment's default target, if it
+// is empty).  The archive is encrypted to the armored OpenPGP public
+// key, or to the environment's backup encryption key if it is empty.
+// It returns the metadata associated with the resulting backup.
+func (c *Client) Create(notes, target, encryptionKey string) (*params.BackupsMetadataResult, error) {
 	var result params.BackupsMetadataResult
-	args := params.BackupsCreateArgs{Notes: notes}
+	args := params.BackupsCreateArgs{
+		Notes:         notes,
+		Target:        target,
+		EncryptionKey: encryptionKey,
+	}
 	if err := c.facade.FacadeCall("Create", args, &result); err != nil {
 		return nil, errors.Trace(err)
 	}
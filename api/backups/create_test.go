@@ -27,10 +27,13 @@ func (s *createSuite) TestCreate(c *gc.C) {
 			c.Assert(paramsIn, gc.FitsTypeOf, params.BackupsCreateArgs{})
 			p := paramsIn.(params.BackupsCreateArgs)
 			c.Check(p.Notes, gc.Equals, "important")
+			c.Check(p.Target, gc.Equals, "s3")
+			c.Check(p.EncryptionKey, gc.Equals, "<public key>")
 
 			if result, ok := resp.(*params.BackupsMetadataResult); ok {
 				*result = apiserverbackups.ResultFromMetadata(s.Meta)
 				result.Notes = p.Notes
+				result.Target = p.Target
 			} else {
 				c.Fatalf("wrong output structure")
 			}
@@ -39,9 +42,10 @@ func (s *createSuite) TestCreate(c *gc.C) {
 	)
 	defer cleanup()
 
-	result, err := s.client.Create("important")
+	result, err := s.client.Create("important", "s3", "<public key>")
 	c.Assert(err, jc.ErrorIsNil)
 
 	meta := backupstesting.UpdateNotes(s.Meta, "important")
+	meta.Target = "s3"
 	s.checkMetadataResult(c, result, meta)
 }
@@ -9,10 +9,12 @@ import (
 	"github.com/juju/juju/apiserver/params"
 )
 
-// List implements the API method.
-func (c *Client) List() (*params.BackupsListResult, error) {
+// List implements the API method. If scheduled is true, only the
+// scheduled backups are listed, along with the status of the backup
+// schedule.
+func (c *Client) List(scheduled bool) (*params.BackupsListResult, error) {
 	var result params.BackupsListResult
-	args := params.BackupsListArgs{}
+	args := params.BackupsListArgs{Scheduled: scheduled}
 	if err := c.facade.FacadeCall("List", args, &result); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -36,10 +36,29 @@ func (s *listSuite) TestList(c *gc.C) {
 	)
 	defer cleanup()
 
-	result, err := s.client.List()
+	result, err := s.client.List(false)
 	c.Assert(err, jc.ErrorIsNil)
 
 	c.Assert(result.List, gc.HasLen, 1)
 	resultItem := result.List[0]
 	s.checkMetadataResult(c, &resultItem, s.Meta)
 }
+
+func (s *listSuite) TestListScheduled(c *gc.C) {
+	cleanup := backups.PatchClientFacadeCall(s.client,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			c.Check(req, gc.Equals, "List")
+			c.Check(paramsIn, gc.Equals, params.BackupsListArgs{Scheduled: true})
+
+			result := resp.(*params.BackupsListResult)
+			result.Schedule = &params.BackupsScheduleStatus{Schedule: "@daily"}
+			return nil
+		},
+	)
+	defer cleanup()
+
+	result, err := s.client.List(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.List, gc.HasLen, 0)
+	c.Check(result.Schedule, jc.DeepEquals, &params.BackupsScheduleStatus{Schedule: "@daily"})
+}
@@ -62,6 +62,7 @@ func (s *baseSuite) checkMetadataResult(c *gc.C, result *params.BackupsMetadataR
 	c.Check(result.Size, gc.Equals, meta.Size())
 	c.Check(result.Stored, gc.Equals, stored)
 	c.Check(result.Notes, gc.Equals, meta.Notes)
+	c.Check(result.Target, gc.Equals, meta.Target)
 
 	c.Check(result.Environment, gc.Equals, meta.Origin.Environment)
 	c.Check(result.Machine, gc.Equals, meta.Origin.Machine)
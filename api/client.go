@@ -203,6 +203,20 @@ func (c *Client) UnitStatusHistory(kind params.HistoryKind, unitName string, siz
 	return &results, nil
 }
 
+// StatusHistory retrieves the status history of the unit, machine or
+// service named in args, filtered by the size and time range in args.
+func (c *Client) StatusHistory(args params.StatusHistory) (*UnitStatusHistory, error) {
+	var results UnitStatusHistory
+	err := c.facade.FacadeCall("StatusHistory", args, &results)
+	if err != nil {
+		if params.IsCodeNotImplemented(err) {
+			return &UnitStatusHistory{}, errors.NotImplementedf("StatusHistory")
+		}
+		return &UnitStatusHistory{}, errors.Trace(err)
+	}
+	return &results, nil
+}
+
 // LegacyMachineStatus holds just the instance-id of a machine.
 type LegacyMachineStatus struct {
 	InstanceId string // Not type instance.Id just to match original api.
@@ -301,6 +315,25 @@ func (c *Client) ServiceGet(service string) (*params.ServiceGetResults, error) {
 	return &results, err
 }
 
+// ServiceConfigHistory returns the recorded changes to the named
+// service's configuration, oldest first.
+func (c *Client) ServiceConfigHistory(service string) ([]params.ServiceConfigRevision, error) {
+	var results params.ServiceConfigHistoryResults
+	args := params.ServiceGet{ServiceName: service}
+	err := c.facade.FacadeCall("ServiceConfigHistory", args, &results)
+	return results.Revisions, err
+}
+
+// ServiceRevertConfig changes the named service's configuration back
+// to that recorded in the given revision of its config history.
+func (c *Client) ServiceRevertConfig(service string, revision int) error {
+	args := params.ServiceRevertConfig{
+		ServiceName: service,
+		Revision:    revision,
+	}
+	return c.facade.FacadeCall("ServiceRevertConfig", args, nil)
+}
+
 // AddRelation adds a relation between the specified endpoints and returns the relation info.
 func (c *Client) AddRelation(endpoints ...string) (*params.AddRelationResults, error) {
 	var addRelRes params.AddRelationResults
@@ -377,6 +410,23 @@ func (c *Client) ServiceExpose(service string) error {
 	return c.facade.FacadeCall("ServiceExpose", params, nil)
 }
 
+// ServiceExposeFrom changes the juju-managed firewall to expose any
+// ports that were also explicitly marked by units as open to the given
+// source CIDRs only.
+func (c *Client) ServiceExposeFrom(service string, sourceCIDRs []string) error {
+	if len(sourceCIDRs) == 0 {
+		return c.ServiceExpose(service)
+	}
+	if c.facade.BestAPIVersion() < 1 {
+		return errors.NotSupportedf("exposing services to specific CIDRs by this juju server")
+	}
+	params := params.ServiceExpose{
+		ServiceName: service,
+		SourceCIDRs: sourceCIDRs,
+	}
+	return c.facade.FacadeCall("ServiceExpose", params, nil)
+}
+
 // ServiceUnexpose changes the juju-managed firewall to unexpose any ports that
 // were also explicitly marked by units as open.
 func (c *Client) ServiceUnexpose(service string) error {
@@ -440,6 +490,19 @@ func (c *Client) ServiceSetCharm(serviceName string, charmUrl string, force bool
 	return c.facade.FacadeCall("ServiceSetCharm", args, nil)
 }
 
+// ServiceRollbackCharm changes the charm of the given service back to
+// the one it ran before its charm was last changed, and returns the
+// URL of that charm.
+func (c *Client) ServiceRollbackCharm(serviceName string) (*charm.URL, error) {
+	result := new(params.StringResult)
+	args := params.ServiceGet{ServiceName: serviceName}
+	err := c.facade.FacadeCall("ServiceRollbackCharm", args, &result)
+	if err != nil {
+		return nil, err
+	}
+	return charm.ParseURL(result.Result)
+}
+
 // ServiceGetCharmURL returns the charm URL the given service is
 // running at present.
 func (c *Client) ServiceGetCharmURL(serviceName string) (*charm.URL, error) {
@@ -568,13 +631,17 @@ func (c *Client) EnvironmentUUID() string {
 }
 
 // ShareEnvironment allows the given users access to the environment.
-func (c *Client) ShareEnvironment(users ...names.UserTag) error {
+// If access is empty the users are given write access, and the access
+// of users already sharing the environment is left unchanged;
+// otherwise all the users are given the specified access.
+func (c *Client) ShareEnvironment(access params.EnvironmentAccess, users ...names.UserTag) error {
 	var args params.ModifyEnvironUsers
 	for _, user := range users {
 		if &user != nil {
 			args.Changes = append(args.Changes, params.ModifyEnvironUser{
 				UserTag: user.String(),
 				Action:  params.AddEnvUser,
+				Access:  access,
 			})
 		}
 	}
@@ -689,6 +756,20 @@ func (c *Client) EnvironmentGet() (map[string]interface{}, error) {
 	return result.Config, err
 }
 
+// ExportBundle returns a bundle, in YAML format, describing the
+// services in the environment, the machines their units are placed on
+// and the relations between them.
+func (c *Client) ExportBundle() (string, error) {
+	var result params.StringResult
+	if err := c.facade.FacadeCall("ExportBundle", nil, &result); err != nil {
+		return "", err
+	}
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
+
 // EnvironmentSet sets the given key-value pairs in the environment.
 func (c *Client) EnvironmentSet(config map[string]interface{}) error {
 	args := params.EnvironmentSet{Config: config}
@@ -1082,6 +1163,16 @@ type DebugLogParams struct {
 	// Replay tells the server to start at the start of the log file rather
 	// than the end. If replay is true, backlog is ignored.
 	Replay bool
+	// StartTime, if set, tells the server to send only log messages
+	// recorded at or after this time. It implies Replay.
+	StartTime time.Time
+	// EndTime, if set, tells the server to send only log messages
+	// recorded at or before this time, and to close the connection
+	// once they have all been sent.
+	EndTime time.Time
+	// Format specifies how the server renders each log message. If
+	// empty, plain text is used.
+	Format params.DebugLogFormat
 }
 
 // WatchDebugLog returns a ReadCloser that the caller can read the log
@@ -1114,6 +1205,15 @@ func (c *Client) WatchDebugLog(args DebugLogParams) (io.ReadCloser, error) {
 	if args.Level != loggo.UNSPECIFIED {
 		attrs.Set("level", fmt.Sprint(args.Level))
 	}
+	if !args.StartTime.IsZero() {
+		attrs.Set("startTime", args.StartTime.UTC().Format(time.RFC3339Nano))
+	}
+	if !args.EndTime.IsZero() {
+		attrs.Set("endTime", args.EndTime.UTC().Format(time.RFC3339Nano))
+	}
+	if args.Format != "" {
+		attrs.Set("format", string(args.Format))
+	}
 	attrs["includeEntity"] = args.IncludeEntity
 	attrs["includeModule"] = args.IncludeModule
 	attrs["excludeEntity"] = args.ExcludeEntity
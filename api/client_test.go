@@ -15,6 +15,7 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -253,7 +254,7 @@ func (s *clientSuite) TestShareEnvironmentExistingUser(c *gc.C) {
 	)
 	defer cleanup()
 
-	err := client.ShareEnvironment(user.UserTag())
+	err := client.ShareEnvironment("", user.UserTag())
 	c.Assert(err, jc.ErrorIsNil)
 	logMsg := fmt.Sprintf("WARNING juju.api environment is already shared with %s", user.UserName())
 	c.Assert(c.GetTestLog(), jc.Contains, logMsg)
@@ -290,6 +291,9 @@ func (s *clientSuite) TestShareEnvironmentThreeUsers(c *gc.C) {
 				c.Assert(users.Changes[1].UserTag, gc.Equals, localUser.UserTag().String())
 				c.Assert(string(users.Changes[2].Action), gc.Equals, string(params.AddEnvUser))
 				c.Assert(users.Changes[2].UserTag, gc.Equals, newUserTag.String())
+				for _, change := range users.Changes {
+					c.Assert(change.Access, gc.Equals, params.EnvironmentReadAccess)
+				}
 			} else {
 				c.Log("wrong input structure")
 				c.Fail()
@@ -306,7 +310,7 @@ func (s *clientSuite) TestShareEnvironmentThreeUsers(c *gc.C) {
 	)
 	defer cleanup()
 
-	err := client.ShareEnvironment(existingUser.UserTag(), localUser.UserTag(), newUserTag)
+	err := client.ShareEnvironment(params.EnvironmentReadAccess, existingUser.UserTag(), localUser.UserTag(), newUserTag)
 	c.Assert(err, gc.ErrorMatches, `existing user`)
 }
 
@@ -460,6 +464,26 @@ func (s *clientSuite) TestParamsEncoded(c *gc.C) {
 	})
 }
 
+func (s *clientSuite) TestTimeRangeParamsEncoded(c *gc.C) {
+	s.PatchValue(api.WebsocketDialConfig, echoURL(c))
+
+	start := time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+	client := s.APIState.Client()
+	reader, err := client.WatchDebugLog(api.DebugLogParams{
+		StartTime: start,
+		EndTime:   start.Add(90 * time.Minute),
+		Format:    params.DebugLogFormatJSON,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	connectURL := connectURLFromReader(c, reader)
+	c.Assert(connectURL.Query(), jc.DeepEquals, url.Values{
+		"startTime": {"2015-07-01T12:00:00Z"},
+		"endTime":   {"2015-07-01T13:30:00Z"},
+		"format":    {"json"},
+	})
+}
+
 func (s *clientSuite) TestDebugLogRootPath(c *gc.C) {
 	s.PatchValue(api.WebsocketDialConfig, echoURL(c))
 
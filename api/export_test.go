@@ -17,6 +17,8 @@ var (
 	BestVersion           = bestVersion
 	FacadeVersions        = &facadeVersions
 	NewHTTPClient         = &newHTTPClient
+	RateLimitRetryDelay   = &rateLimitRetryDelay
+	RateLimitMaxRetries   = &rateLimitMaxRetries
 )
 
 // SetServerAddress allows changing the URL to the internal API server
@@ -15,11 +15,12 @@ var facadeVersions = map[string]int{
 	"Agent":                        1,
 	"AllWatcher":                   0,
 	"Annotations":                  1,
+	"AuditLog":                     1,
 	"Backups":                      0,
 	"Block":                        1,
 	"Charms":                       1,
 	"CharmRevisionUpdater":         0,
-	"Client":                       0,
+	"Client":                       1,
 	"Cleaner":                      1,
 	"Deployer":                     0,
 	"DiskManager":                  1,
@@ -27,7 +28,7 @@ var facadeVersions = map[string]int{
 	"Environment":                  0,
 	"EnvironmentManager":           1,
 	"FilesystemAttachmentsWatcher": 1,
-	"Firewaller":                   1,
+	"Firewaller":                   2,
 	"HighAvailability":             1,
 	"ImageManager":                 1,
 	"InstancePoller":               1,
@@ -52,7 +53,7 @@ var facadeVersions = map[string]int{
 	"StringsWatcher":               0,
 	"SystemManager":                1,
 	"Upgrader":                     0,
-	"Uniter":                       2,
+	"Uniter":                       3,
 	"UserManager":                  0,
 	"VolumeAttachmentsWatcher":     1,
 }
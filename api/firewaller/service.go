@@ -75,3 +75,30 @@ func (s *Service) IsExposed() (bool, error) {
 	}
 	return result.Result, nil
 }
+
+// ExposedCIDRs returns the CIDRs from which the open ports of the
+// service may be accessed when it is exposed, or nil if they may be
+// accessed from any address.
+func (s *Service) ExposedCIDRs() ([]string, error) {
+	if s.st.BestAPIVersion() < 2 {
+		// Servers before version 2 cannot restrict the CIDRs, and
+		// expose services to any address.
+		return nil, nil
+	}
+	var results params.StringsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("GetExposedCIDRs", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Result, nil
+}
@@ -8,6 +8,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	basetesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/firewaller"
 	"github.com/juju/juju/apiserver/params"
 	statetesting "github.com/juju/juju/state/testing"
@@ -94,3 +95,39 @@ func (s *serviceSuite) TestIsExposed(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(isExposed, jc.IsFalse)
 }
+
+func (s *serviceSuite) TestExposedCIDRs(c *gc.C) {
+	err := s.service.SetExposedFrom([]string{"10.0.0.0/8", "192.168.0.0/16"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	cidrs, err := s.apiService.ExposedCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, jc.DeepEquals, []string{"10.0.0.0/8", "192.168.0.0/16"})
+
+	err = s.service.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+
+	cidrs, err = s.apiService.ExposedCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.HasLen, 0)
+}
+
+func (s *serviceSuite) TestExposedCIDRsV1(c *gc.C) {
+	// Servers before version 2 expose services to any address.
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, args, response interface{}) error {
+		c.Check(objType, gc.Equals, "Firewaller")
+		c.Check(request, gc.Equals, "Life")
+		results := response.(*params.LifeResults)
+		results.Results = []params.LifeResult{{Life: params.Alive}}
+		return nil
+	})
+	st := firewaller.NewState(apiCaller)
+	apiUnit, err := st.Unit(names.NewUnitTag("wordpress/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	apiService, err := apiUnit.Service()
+	c.Assert(err, jc.ErrorIsNil)
+
+	cidrs, err := apiService.ExposedCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.IsNil)
+}
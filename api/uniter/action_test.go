@@ -4,6 +4,8 @@
 package uniter_test
 
 import (
+	"time"
+
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -64,6 +66,14 @@ func (s *actionSuite) TestAction(c *gc.C) {
 	}
 }
 
+func (s *actionSuite) TestActionTimeout(c *gc.C) {
+	action, err := s.uniterSuite.wordpressUnit.AddActionWithTimeout("fakeaction", nil, time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	retrievedAction, err := s.uniter.Action(action.ActionTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(retrievedAction.Timeout(), gc.Equals, time.Minute)
+}
+
 func (s *actionSuite) TestActionNotFound(c *gc.C) {
 	_, err := s.uniter.Action(names.NewActionTag("feedface-0123-4567-8901-2345deadbeef"))
 	c.Assert(err, gc.NotNil)
@@ -555,6 +555,38 @@ func (s *unitSuite) TestWatchActionNotifications(c *gc.C) {
 	wc.AssertClosed()
 }
 
+func (s *unitSuite) TestWatchActionCancellations(c *gc.C) {
+	w, err := s.apiUnit.WatchActionCancellations()
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.BackingState, w)
+
+	// Initial event.
+	wc.AssertChange()
+
+	action, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	_, err = s.wordpressUnit.CancelAction(action)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(action.Id())
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *unitSuite) TestWatchActionCancellationsV2NotImplemented(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV2)
+	apiUnit, err := s.uniter.Unit(s.wordpressUnit.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = apiUnit.WatchActionCancellations()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	c.Assert(err.Error(), gc.Equals, "WatchActionCancellations() (need V3+) not implemented")
+}
+
 func (s *unitSuite) TestWatchActionNotificationsError(c *gc.C) {
 	uniter.PatchUnitResponse(s, s.apiUnit, "WatchActionNotifications",
 		func(result interface{}) error {
@@ -0,0 +1,205 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"reflect"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/tracing"
+)
+
+// accessRoot restricts the API calls an environment user may make to
+// those allowed by their level of access to the environment.
+type accessRoot struct {
+	rpc.MethodFinder
+	user   names.UserTag
+	access accessFunc
+}
+
+// accessFunc returns the current access of a user to an environment.
+// It returns an error satisfying errors.IsNotFound if the user no
+// longer has any access.
+type accessFunc func() (state.EnvironmentAccess, error)
+
+// newAccessRoot returns a new accessRoot for the given environment
+// user. The user's access is checked on every call, so changes to it
+// apply to connections already made.
+func newAccessRoot(finder rpc.MethodFinder, user names.UserTag, access accessFunc) *accessRoot {
+	return &accessRoot{
+		MethodFinder: finder,
+		user:         user,
+		access:       access,
+	}
+}
+
+// envUserAccess returns an accessFunc reporting the access the given
+// user has to the environment.
+func envUserAccess(st *state.State, user names.UserTag) accessFunc {
+	return func() (state.EnvironmentAccess, error) {
+		envUser, err := st.EnvironmentUser(user)
+		if err != nil {
+			return "", err
+		}
+		return envUser.Access(), nil
+	}
+}
+
+// Kill implements rpc.Killer, passing the call on to the wrapped root.
+func (r *accessRoot) Kill() {
+	killRoot(r.MethodFinder)
+}
+
+// Cleanup implements rpc.Cleaner, passing the call on to the wrapped
+// root.
+func (r *accessRoot) Cleanup() {
+	cleanupRoot(r.MethodFinder)
+}
+
+// adminOnlyMethods holds, by facade, the methods that may only be
+// called by environment administrators: those that manage users, the
+// environment itself, its state servers and its backups, and those
+// that reveal the configuration of all its services at once.
+var adminOnlyMethods = map[string]set.Strings{
+	"AuditLog": set.NewStrings(
+		"Records",
+	),
+	"Backups": set.NewStrings(
+		"Create",
+		"FinishRestore",
+		"Info",
+		"List",
+		"PrepareRestore",
+		"Remove",
+		"Restore",
+	),
+	"Block": set.NewStrings(
+		"SwitchBlockOff",
+		"SwitchBlockOn",
+	),
+	"Client": set.NewStrings(
+		"AbortCurrentUpgrade",
+		"DestroyEnvironment",
+		"EnsureAvailability",
+		"EnvironmentSet",
+		"EnvironmentUnset",
+		"ExportBundle",
+		"SetEnvironAgentVersion",
+		"ShareEnvironment",
+	),
+	"EnvironmentManager": set.NewStrings(
+		"CreateEnvironment",
+	),
+	"HighAvailability": set.NewStrings(
+		"EnsureAvailability",
+	),
+	"KeyManager": set.NewStrings(
+		"AddKeys",
+		"DeleteKeys",
+		"ImportKeys",
+	),
+	"UserManager": set.NewStrings(
+		"AddUser",
+		"DisableUser",
+		"EnableUser",
+		"SetPassword",
+	),
+}
+
+// selfServiceMethods holds, by facade, the admin-only methods that
+// other users may still call on their own behalf.
+var selfServiceMethods = map[string]set.Strings{
+	"UserManager": set.NewStrings(
+		"SetPassword",
+	),
+}
+
+// FindMethod returns a permission denied error if the user's access to
+// the environment does not allow them to call the method.
+func (r *accessRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	// The lookup of the name is done first to return a not found error if the
+	// user is looking for a method that we just don't have.
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	access, err := r.access()
+	if errors.IsNotFound(err) {
+		return nil, common.ErrPerm
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if accessAllows(access, rootName, methodName) {
+		return caller, nil
+	}
+	if methods, ok := selfServiceMethods[rootName]; ok && methods.Contains(methodName) && access.Includes(state.EnvironmentReadAccess) {
+		return &selfServiceCaller{
+			MethodCaller: caller,
+			user:         r.user,
+		}, nil
+	}
+	return nil, common.ErrPerm
+}
+
+// selfServiceCaller wraps a MethodCaller, only allowing calls whose
+// arguments refer to no entity other than the calling user.
+type selfServiceCaller struct {
+	rpcreflect.MethodCaller
+	user names.UserTag
+}
+
+// Call is part of the rpcreflect.MethodCaller interface.
+func (c *selfServiceCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	if !arg.IsValid() {
+		return reflect.Value{}, common.ErrPerm
+	}
+	args, ok := arg.Interface().(params.EntityPasswords)
+	if !ok || len(args.Changes) == 0 {
+		return reflect.Value{}, common.ErrPerm
+	}
+	for _, change := range args.Changes {
+		if change.Tag != c.user.String() {
+			return reflect.Value{}, common.ErrPerm
+		}
+	}
+	return c.MethodCaller.Call(objId, arg)
+}
+
+// WithSpan implements rpc.TracingMethodCaller, checking the arguments
+// of the calls made on behalf of the span.
+func (c *selfServiceCaller) WithSpan(span *tracing.Span) rpcreflect.MethodCaller {
+	return &selfServiceCaller{
+		MethodCaller: rpc.CallerWithSpan(c.MethodCaller, span),
+		user:         c.user,
+	}
+}
+
+// accessAllows returns whether a user with the given access to an
+// environment may call the given facade method.
+func accessAllows(access state.EnvironmentAccess, facadeName, methodName string) bool {
+	switch access {
+	case state.EnvironmentAdminAccess:
+		return true
+	case state.EnvironmentWriteAccess:
+		return !isAdminOnlyCall(facadeName, methodName)
+	case state.EnvironmentReadAccess:
+		return isReadOnlyCall(facadeName, methodName) && !isAdminOnlyCall(facadeName, methodName)
+	}
+	return false
+}
+
+// isAdminOnlyCall returns whether only environment administrators may
+// call the given facade method.
+func isAdminOnlyCall(facadeName, methodName string) bool {
+	methods, ok := adminOnlyMethods[facadeName]
+	return ok && methods.Contains(methodName)
+}
@@ -0,0 +1,163 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"reflect"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type accessRootSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&accessRootSuite{})
+
+var testUser = names.NewUserTag("bob")
+
+func fixedAccess(access state.EnvironmentAccess) accessFunc {
+	return func() (state.EnvironmentAccess, error) {
+		return access, nil
+	}
+}
+
+func (s *accessRootSuite) TestAccessAllows(c *gc.C) {
+	for i, test := range []struct {
+		access  state.EnvironmentAccess
+		facade  string
+		method  string
+		allowed bool
+	}{
+		{state.EnvironmentReadAccess, "Client", "FullStatus", true},
+		{state.EnvironmentReadAccess, "AllWatcher", "Next", true},
+		{state.EnvironmentReadAccess, "Client", "ServiceDeploy", false},
+		{state.EnvironmentReadAccess, "Client", "ShareEnvironment", false},
+		{state.EnvironmentWriteAccess, "Client", "FullStatus", true},
+		{state.EnvironmentWriteAccess, "Client", "ServiceDeploy", true},
+		{state.EnvironmentWriteAccess, "Client", "ShareEnvironment", false},
+		{state.EnvironmentWriteAccess, "Client", "DestroyEnvironment", false},
+		{state.EnvironmentWriteAccess, "Client", "EnvironmentSet", false},
+		{state.EnvironmentWriteAccess, "Client", "SetEnvironAgentVersion", false},
+		{state.EnvironmentWriteAccess, "UserManager", "AddUser", false},
+		{state.EnvironmentWriteAccess, "UserManager", "DisableUser", false},
+		{state.EnvironmentWriteAccess, "EnvironmentManager", "CreateEnvironment", false},
+		{state.EnvironmentWriteAccess, "Backups", "Create", false},
+		{state.EnvironmentReadAccess, "Backups", "List", false},
+		{state.EnvironmentReadAccess, "AuditLog", "Records", false},
+		{state.EnvironmentReadAccess, "Client", "ExportBundle", false},
+		{state.EnvironmentWriteAccess, "Client", "ExportBundle", false},
+		{state.EnvironmentAdminAccess, "Client", "ExportBundle", true},
+		{state.EnvironmentAdminAccess, "Backups", "Restore", true},
+		{state.EnvironmentAdminAccess, "Client", "ServiceDeploy", true},
+		{state.EnvironmentAdminAccess, "Client", "ShareEnvironment", true},
+		{state.EnvironmentAdminAccess, "Client", "DestroyEnvironment", true},
+		{"", "Client", "FullStatus", false},
+	} {
+		c.Logf("test %d: %q %s.%s", i, test.access, test.facade, test.method)
+		c.Check(accessAllows(test.access, test.facade, test.method), gc.Equals, test.allowed)
+	}
+}
+
+func (s *accessRootSuite) TestFindMethodDenied(c *gc.C) {
+	root := newAccessRoot(&fakeMethodFinder{}, testUser, fixedAccess(state.EnvironmentReadAccess))
+	caller, err := root.FindMethod("Client", 0, "ServiceDeploy")
+	c.Assert(err, gc.Equals, common.ErrPerm)
+	c.Assert(caller, gc.IsNil)
+}
+
+func (s *accessRootSuite) TestFindMethodAllowed(c *gc.C) {
+	root := newAccessRoot(&fakeMethodFinder{}, testUser, fixedAccess(state.EnvironmentReadAccess))
+	caller, err := root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caller, gc.NotNil)
+}
+
+func (s *accessRootSuite) TestFindMethodChecksCurrentAccess(c *gc.C) {
+	access := state.EnvironmentWriteAccess
+	root := newAccessRoot(&fakeMethodFinder{}, testUser, func() (state.EnvironmentAccess, error) {
+		return access, nil
+	})
+	_, err := root.FindMethod("Client", 0, "ServiceDeploy")
+	c.Assert(err, jc.ErrorIsNil)
+
+	access = state.EnvironmentReadAccess
+	_, err = root.FindMethod("Client", 0, "ServiceDeploy")
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *accessRootSuite) TestFindMethodRemovedUser(c *gc.C) {
+	root := newAccessRoot(&fakeMethodFinder{}, testUser, func() (state.EnvironmentAccess, error) {
+		return "", errors.NotFoundf("environment user %q", testUser.Name())
+	})
+	_, err := root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *accessRootSuite) TestFindMethodAccessError(c *gc.C) {
+	root := newAccessRoot(&fakeMethodFinder{}, testUser, func() (state.EnvironmentAccess, error) {
+		return "", errors.New("mongo down")
+	})
+	_, err := root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, gc.ErrorMatches, "mongo down")
+}
+
+func (s *accessRootSuite) TestFindMethodNotFound(c *gc.C) {
+	root := newAccessRoot(&fakeMethodFinder{err: errors.New("no such method")}, testUser, fixedAccess(state.EnvironmentAdminAccess))
+	_, err := root.FindMethod("Client", 0, "Frobnicate")
+	c.Assert(err, gc.ErrorMatches, "no such method")
+}
+
+func (s *accessRootSuite) TestSetOwnPasswordWithReadAccess(c *gc.C) {
+	root := newAccessRoot(&fakeMethodFinder{}, testUser, fixedAccess(state.EnvironmentReadAccess))
+	caller, err := root.FindMethod("UserManager", 0, "SetPassword")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = caller.Call("", reflect.ValueOf(params.EntityPasswords{
+		Changes: []params.EntityPassword{{Tag: testUser.String(), Password: "secret"}},
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *accessRootSuite) TestSetOwnPasswordWithWriteAccess(c *gc.C) {
+	root := newAccessRoot(&fakeMethodFinder{}, testUser, fixedAccess(state.EnvironmentWriteAccess))
+	caller, err := root.FindMethod("UserManager", 0, "SetPassword")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = caller.Call("", reflect.ValueOf(params.EntityPasswords{
+		Changes: []params.EntityPassword{{Tag: testUser.String(), Password: "secret"}},
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = caller.Call("", reflect.ValueOf(params.EntityPasswords{
+		Changes: []params.EntityPassword{{Tag: names.NewUserTag("mary").String(), Password: "secret"}},
+	}))
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *accessRootSuite) TestSetOtherPasswordWithReadAccess(c *gc.C) {
+	root := newAccessRoot(&fakeMethodFinder{}, testUser, fixedAccess(state.EnvironmentReadAccess))
+	caller, err := root.FindMethod("UserManager", 0, "SetPassword")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = caller.Call("", reflect.ValueOf(params.EntityPasswords{
+		Changes: []params.EntityPassword{
+			{Tag: testUser.String(), Password: "secret"},
+			{Tag: names.NewUserTag("mary").String(), Password: "secret"},
+		},
+	}))
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *accessRootSuite) TestKillAndCleanupPassedOn(c *gc.C) {
+	finder := &fakeKillerCleaner{}
+	root := newAccessRoot(finder, testUser, fixedAccess(state.EnvironmentReadAccess))
+	root.Kill()
+	root.Cleanup()
+	c.Assert(finder.calls, jc.DeepEquals, []string{"Kill", "Cleanup"})
+}
@@ -4,6 +4,7 @@
 package action
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 
@@ -97,7 +98,7 @@ func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 			currentResult.Error = common.ServerError(err)
 			continue
 		}
-		enqueued, err := receiver.AddAction(action.Name, action.Parameters)
+		enqueued, err := receiver.AddActionWithTimeout(action.Name, action.Parameters, action.Timeout)
 		if err != nil {
 			currentResult.Error = common.ServerError(err)
 			continue
@@ -108,6 +109,79 @@ func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 	return response, nil
 }
 
+// EnqueueServiceActions takes a list of ServiceActions and queues each
+// up to be executed by every unit of the designated service, in
+// batches or at a scheduled time if requested. It returns the
+// operation that tracks the progress of each, or an error if there was
+// a problem queueing it up.
+func (a *ActionAPI) EnqueueServiceActions(arg params.ServiceActions) (params.ActionOperationResults, error) {
+	response := params.ActionOperationResults{Results: make([]params.ActionOperationResult, len(arg.Actions))}
+	for i, action := range arg.Actions {
+		currentResult := &response.Results[i]
+		serviceTag, err := names.ParseServiceTag(action.Service)
+		if err != nil {
+			currentResult.Error = common.ServerError(common.ErrBadId)
+			continue
+		}
+		service, err := a.state.Service(serviceTag.Id())
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		args := state.ActionOperationArgs{
+			BatchSize:     action.BatchSize,
+			StopOnFailure: action.StopOnFailure,
+			Timeout:       action.Timeout,
+		}
+		if action.Scheduled != nil {
+			args.Scheduled = *action.Scheduled
+		}
+		op, err := service.EnqueueActionOperation(action.Name, action.Parameters, args)
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		response.Results[i], err = makeActionOperationResult(op)
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+		}
+	}
+	return response, nil
+}
+
+// ActionOperations takes a list of action operation ids, or unique
+// prefixes of them, and returns the progress of each operation.
+func (a *ActionAPI) ActionOperations(arg params.ActionOperationIds) (params.ActionOperationResults, error) {
+	response := params.ActionOperationResults{Results: make([]params.ActionOperationResult, len(arg.Ids))}
+	for i, prefix := range arg.Ids {
+		currentResult := &response.Results[i]
+		ids, err := a.state.FindActionOperationIdsByPrefix(prefix)
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		switch len(ids) {
+		case 0:
+			currentResult.Error = common.ServerError(errors.NotFoundf("action operation %q", prefix))
+			continue
+		case 1:
+		default:
+			currentResult.Error = common.ServerError(errors.Errorf("prefix %q matches multiple action operations", prefix))
+			continue
+		}
+		op, err := a.state.ActionOperation(ids[0])
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		response.Results[i], err = makeActionOperationResult(op)
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+		}
+	}
+	return response, nil
+}
+
 // ListAll takes a list of Entities representing ActionReceivers and
 // returns all of the Actions that have been enqueued or run by each of
 // those Entities.
@@ -313,6 +387,7 @@ func makeActionResult(actionReceiverTag names.Tag, action *state.Action) params.
 			Tag:        action.ActionTag().String(),
 			Name:       action.Name(),
 			Parameters: action.Parameters(),
+			Timeout:    action.Timeout(),
 		},
 		Status:    string(action.Status()),
 		Message:   message,
@@ -322,3 +397,36 @@ func makeActionResult(actionReceiverTag names.Tag, action *state.Action) params.
 		Completed: action.Completed(),
 	}
 }
+
+// makeActionOperationResult converts a *state.ActionOperation, and the
+// progress of its units, to a params.ActionOperationResult.
+func makeActionOperationResult(op *state.ActionOperation) (params.ActionOperationResult, error) {
+	units, err := op.Units()
+	if err != nil {
+		return params.ActionOperationResult{}, err
+	}
+	result := params.ActionOperationResult{
+		Id:            op.Id(),
+		Service:       names.NewServiceTag(op.Service()).String(),
+		Name:          op.Name(),
+		Parameters:    op.Parameters(),
+		BatchSize:     op.BatchSize(),
+		StopOnFailure: op.StopOnFailure(),
+		Timeout:       op.Timeout(),
+		Enqueued:      op.Enqueued(),
+		Scheduled:     op.Scheduled(),
+		Completed:     op.Completed(),
+		Status:        string(op.Status()),
+		Units:         make([]params.ActionOperationUnit, len(units)),
+	}
+	for i, unit := range units {
+		result.Units[i] = params.ActionOperationUnit{
+			Unit:   names.NewUnitTag(unit.Unit).String(),
+			Status: string(unit.Status),
+		}
+		if unit.Action != nil {
+			result.Units[i].Action = unit.Action.ActionTag().String()
+		}
+	}
+	return result, nil
+}
@@ -712,3 +712,65 @@ func stringify(r params.ActionResult) string {
 	}
 	return fmt.Sprintf("%s-%s-%#v-%s-%s-%#v", a.Tag, a.Name, a.Parameters, r.Status, r.Message, r.Output)
 }
+
+func (s *actionSuite) TestEnqueueServiceActions(c *gc.C) {
+	factory := jujuFactory.NewFactory(s.State)
+	dummyUnit := factory.MakeUnit(c, &jujuFactory.UnitParams{
+		Service: s.dummy,
+		Machine: s.machine1,
+	})
+
+	arg := params.ServiceActions{
+		Actions: []params.ServiceAction{
+			// Good.
+			{Service: s.dummy.Tag().String(), Name: "snapshot", BatchSize: 1},
+			// Unit tag instead of Service tag.
+			{Service: dummyUnit.Tag().String(), Name: "snapshot"},
+			// Unknown action.
+			{Service: s.dummy.Tag().String(), Name: "bogus"},
+		},
+	}
+	res, err := s.action.EnqueueServiceActions(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 3)
+
+	c.Assert(res.Results[0].Error, gc.IsNil)
+	result := res.Results[0]
+	c.Assert(result.Id, gc.Not(gc.Equals), "")
+	c.Assert(result.Service, gc.Equals, s.dummy.Tag().String())
+	c.Assert(result.Name, gc.Equals, "snapshot")
+	c.Assert(result.BatchSize, gc.Equals, 1)
+	c.Assert(result.Status, gc.Equals, params.ActionRunning)
+	c.Assert(result.Units, gc.HasLen, 1)
+	c.Assert(result.Units[0].Unit, gc.Equals, dummyUnit.Tag().String())
+	c.Assert(result.Units[0].Action, gc.Not(gc.Equals), "")
+	c.Assert(result.Units[0].Status, gc.Equals, params.ActionPending)
+
+	c.Assert(res.Results[1].Error, gc.DeepEquals, &params.Error{Message: "id not found", Code: "not found"})
+	c.Assert(res.Results[2].Error, gc.ErrorMatches, `action "bogus" not defined on service "dummy"`)
+
+	actions, err := dummyUnit.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].ActionTag().String(), gc.Equals, result.Units[0].Action)
+}
+
+func (s *actionSuite) TestActionOperations(c *gc.C) {
+	factory := jujuFactory.NewFactory(s.State)
+	factory.MakeUnit(c, &jujuFactory.UnitParams{
+		Service: s.dummy,
+		Machine: s.machine1,
+	})
+	op, err := s.dummy.EnqueueActionOperation("snapshot", nil, state.ActionOperationArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	res, err := s.action.ActionOperations(params.ActionOperationIds{
+		Ids: []string{op.Id()[:7], "no-such-operation"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 2)
+	c.Assert(res.Results[0].Error, gc.IsNil)
+	c.Assert(res.Results[0].Id, gc.Equals, op.Id())
+	c.Assert(res.Results[0].Units, gc.HasLen, 1)
+	c.Assert(res.Results[1].Error, gc.ErrorMatches, `action operation "no-such-operation" not found`)
+}
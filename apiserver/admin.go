@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/apiserver/authentication"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/presence"
@@ -75,6 +76,7 @@ func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.Login
 		// Users are not rate limited, all other entities are
 		if !a.srv.limiter.Acquire() {
 			logger.Debugf("rate limiting for agent %s", req.AuthTag)
+			loginRateLimited.Inc()
 			return fail, common.ErrTryAgain
 		}
 		defer a.srv.limiter.Release()
@@ -179,11 +181,64 @@ func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.Login
 		loginResult.Facades = facades
 	}
 
+	if isUser && !serverOnlyLogin {
+		// Only allow the calls permitted by the user's level of
+		// access to the environment.
+		userTag := entity.Tag().(names.UserTag)
+		access := envUserAccess(a.root.state, userTag)
+		if _, err := access(); err != nil {
+			return fail, errors.Trace(err)
+		}
+		authedApi = newAccessRoot(authedApi, userTag, access)
+	}
+
+	if isUser {
+		// Record all calls made by users that may change the
+		// environment in its audit log.
+		authedApi = newAuditingRoot(authedApi, a.root.state, entity.Tag())
+	}
+
+	cfg, err := a.root.state.EnvironConfig()
+	if err != nil {
+		return fail, errors.Trace(err)
+	}
+	// Apply any configured request rate and watcher limits, before
+	// anything else is done with each call.
+	authedApi = a.limitedApi(authedApi, cfg, entity.Tag(), isUser)
+
+	// Record the requests made on behalf of traced clients, if the
+	// state server environment says where to export them. The
+	// setting in hosted environments is ignored, since their users
+	// must not choose where the API server writes or connects to.
+	srvCfg, err := a.srv.state.EnvironConfig()
+	if err != nil {
+		return fail, errors.Trace(err)
+	}
+	a.root.rpcConn.SetTracer(a.srv.tracers.get(srvCfg.TraceEndpoint()))
 	a.root.rpcConn.ServeFinder(authedApi, serverError)
 
 	return loginResult, nil
 }
 
+// limitedApi wraps the given API in a rateLimitedRoot if the
+// environment configuration limits the API requests made by the
+// logged in entity.
+func (a *admin) limitedApi(finder rpc.MethodFinder, cfg *config.Config, tag names.Tag, isUser bool) rpc.MethodFinder {
+	userRate, agentRate := cfg.APIRequestRates()
+	rate := agentRate
+	if isUser {
+		rate = userRate
+	}
+	maxWatchers := cfg.APIMaxWatchers()
+	if rate == 0 && maxWatchers == 0 {
+		return finder
+	}
+	bucket := a.srv.requestBuckets.get(tag.String(), rate)
+	release := a.srv.entityWatchers.add(tag.String(), a.root.resources)
+	watchers := a.srv.entityWatchers.counter(tag.String())
+	return newRateLimitedRoot(finder, bucket, watchers, maxWatchers, release)
+}
+
 // checkCredsOfStateServerMachine checks the special case of a state server
 // machine creating an API connection for a different environment so it can
 // run API workers for that environment to do things like provisioning
@@ -18,6 +18,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/usermanager"
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/params"
 	jujutesting "github.com/juju/juju/juju/testing"
@@ -365,6 +366,7 @@ func (s *loginSuite) TestLoginRateLimited(c *gc.C) {
 	defer cleanup()
 	delayChan, cleanup := apiserver.DelayLogins()
 	defer cleanup()
+	rejected := apiserver.LoginRateLimited.Value()
 
 	// Start enough concurrent Login requests so that we max out our
 	// LoginRateLimit. Do one extra so we know we are in overload
@@ -375,6 +377,7 @@ func (s *loginSuite) TestLoginRateLimited(c *gc.C) {
 	case <-time.After(coretesting.LongWait):
 		c.Fatalf("timed out waiting for login to get rejected.")
 	}
+	c.Check(apiserver.LoginRateLimited.Value(), gc.Equals, rejected+1)
 
 	// Let one request through, we should see that it succeeds without
 	// error, and then be able to start a new request, but it will block
@@ -488,6 +491,39 @@ func (s *loginSuite) TestUsersAreNotRateLimited(c *gc.C) {
 	}
 }
 
+func (s *loginSuite) TestWatcherLimitSharedAcrossConnections(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"api-max-watchers": 1}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	info.Tag = s.AdminUserTag(c)
+	info.Password = "dummy-secret"
+
+	st1, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st1.Close()
+	_, err = st1.Client().WatchAll()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Opening another connection does not get around the limit.
+	st2, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st2.Close()
+	_, err = st2.Client().WatchAll()
+	c.Assert(err, jc.Satisfies, params.IsCodeTooManyWatchers)
+
+	// Once the first connection is closed, its watcher no longer
+	// counts against the limit.
+	c.Assert(st1.Close(), jc.ErrorIsNil)
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		_, err = st2.Client().WatchAll()
+		if !params.IsCodeTooManyWatchers(err) {
+			break
+		}
+	}
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *loginSuite) TestNonEnvironUserLoginFails(c *gc.C) {
 	info, cleanup := s.setupServerWithValidator(c, nil)
 	defer cleanup()
@@ -498,6 +534,82 @@ func (s *loginSuite) TestNonEnvironUserLoginFails(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
 }
 
+func (s *loginSuite) TestReadOnlyEnvironUserCannotChangeEnvironment(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "dummy-password", NoEnvUser: true})
+	s.Factory.MakeEnvUser(c, &factory.EnvUserParams{
+		User:   user.UserTag().Username(),
+		Access: state.EnvironmentReadAccess,
+	})
+	info.Password = "dummy-password"
+	info.Tag = user.UserTag()
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	_, err = st.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = st.Client().ServiceExpose("wordpress")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(params.ErrCode(err), gc.Equals, params.CodeUnauthorized)
+}
+
+func (s *loginSuite) TestEnvironUserAccessChangeAppliesToConnection(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "dummy-password", NoEnvUser: true})
+	envUser := s.Factory.MakeEnvUser(c, &factory.EnvUserParams{
+		User:   user.UserTag().Username(),
+		Access: state.EnvironmentWriteAccess,
+	})
+	info.Password = "dummy-password"
+	info.Tag = user.UserTag()
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	err = st.Client().ServiceExpose("wordpress")
+	c.Assert(err, gc.ErrorMatches, `service "wordpress" not found`)
+
+	err = envUser.SetAccess(state.EnvironmentReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	err = st.Client().ServiceExpose("wordpress")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+
+	err = s.State.RemoveEnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.Client().Status(nil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *loginSuite) TestReadOnlyEnvironUserCanSetOwnPassword(c *gc.C) {
+	info, cleanup := s.setupServerWithValidator(c, nil)
+	defer cleanup()
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "dummy-password", NoEnvUser: true})
+	s.Factory.MakeEnvUser(c, &factory.EnvUserParams{
+		User:   user.UserTag().Username(),
+		Access: state.EnvironmentReadAccess,
+	})
+	other := s.Factory.MakeUser(c, &factory.UserParams{Name: "mary"})
+	info.Password = "dummy-password"
+	info.Tag = user.UserTag()
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	client := usermanager.NewClient(st)
+	err = client.SetPassword(user.Name(), "new-password")
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.PasswordValid("new-password"), jc.IsTrue)
+
+	err = client.SetPassword(other.Name(), "new-password")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 func (s *loginV0Suite) TestLoginReportsEnvironTag(c *gc.C) {
 	st, cleanup := s.setupServer(c)
 	defer cleanup()
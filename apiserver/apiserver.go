@@ -45,6 +45,9 @@ type Server struct {
 	dataDir           string
 	logDir            string
 	limiter           utils.Limiter
+	requestBuckets    *requestBuckets
+	entityWatchers    *entityWatchers
+	tracers           *tracers
 	validator         LoginValidator
 	adminApiFactories map[int]adminApiFactory
 
@@ -166,13 +169,16 @@ func NewServer(s *state.State, lis net.Listener, cfg ServerConfig) (*Server, err
 func newServer(s *state.State, lis *net.TCPListener, cfg ServerConfig) (*Server, error) {
 	logger.Infof("listening on %q", lis.Addr())
 	srv := &Server{
-		state:     s,
-		addr:      lis.Addr().(*net.TCPAddr), // cannot fail
-		tag:       cfg.Tag,
-		dataDir:   cfg.DataDir,
-		logDir:    cfg.LogDir,
-		limiter:   utils.NewLimiter(loginRateLimit),
-		validator: cfg.Validator,
+		state:          s,
+		addr:           lis.Addr().(*net.TCPAddr), // cannot fail
+		tag:            cfg.Tag,
+		dataDir:        cfg.DataDir,
+		logDir:         cfg.LogDir,
+		limiter:        utils.NewLimiter(loginRateLimit),
+		requestBuckets: newRequestBuckets(),
+		entityWatchers: newEntityWatchers(),
+		tracers:        newTracers(cfg.LogDir),
+		validator:      cfg.Validator,
 		adminApiFactories: map[int]adminApiFactory{
 			0: newAdminApiV0,
 			1: newAdminApiV1,
@@ -221,6 +227,7 @@ type requestNotifier struct {
 
 	mu   sync.Mutex
 	tag_ string
+	kind string
 }
 
 var globalCounter int64
@@ -229,13 +236,17 @@ func newRequestNotifier() *requestNotifier {
 	return &requestNotifier{
 		id:    atomic.AddInt64(&globalCounter, 1),
 		tag_:  "<unknown>",
+		kind:  unauthenticatedKind,
 		start: time.Now(),
 	}
 }
 
 func (n *requestNotifier) login(tag string) {
 	n.mu.Lock()
+	apiConnections.Dec(n.kind)
 	n.tag_ = tag
+	n.kind = connectionKind(tag)
+	apiConnections.Inc(n.kind)
 	n.mu.Unlock()
 }
 
@@ -275,10 +286,14 @@ func (n *requestNotifier) ServerReply(req rpc.Request, hdr *rpc.Header, body int
 }
 
 func (n *requestNotifier) join(req *http.Request) {
+	apiConnections.Inc(unauthenticatedKind)
 	logger.Infof("[%X] API connection from %s", n.id, req.RemoteAddr)
 }
 
 func (n *requestNotifier) leave() {
+	n.mu.Lock()
+	apiConnections.Dec(n.kind)
+	n.mu.Unlock()
 	logger.Infof("[%X] %s API connection terminated after %v", n.id, n.tag(), time.Since(n.start))
 }
 
@@ -299,6 +314,7 @@ func handleAll(mux *pat.PatternServeMux, pattern string, handler http.Handler) {
 
 func (srv *Server) run(lis net.Listener) {
 	defer srv.tomb.Done()
+	defer srv.tracers.closeAll()
 	defer srv.wg.Wait() // wait for any outstanding requests to complete.
 	srv.wg.Add(1)
 	go func() {
@@ -377,6 +393,12 @@ func (srv *Server) run(lis net.Listener) {
 			httpHandler{ssState: srv.state},
 		}},
 	)
+	handleAll(mux, "/metrics",
+		&metricsHandler{httpHandler{
+			ssState:            srv.state,
+			stateServerEnvOnly: true,
+		}},
+	)
 	handleAll(mux, "/", http.HandlerFunc(srv.apiHandler))
 
 	go func() {
@@ -423,11 +445,11 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifie
 	if loggo.GetLogger("juju.rpc.jsoncodec").EffectiveLogLevel() <= loggo.TRACE {
 		codec.SetLogging(true)
 	}
-	var notifier rpc.RequestNotifier
+	// Requests are always counted, but incur request
+	// logging overhead only if we know we'll need it.
+	notifier := newMetricsNotifier(nil)
 	if logger.EffectiveLogLevel() <= loggo.DEBUG {
-		// Incur request monitoring overhead only if we
-		// know we'll need it.
-		notifier = reqNotifier
+		notifier.next = reqNotifier
 	}
 	conn := rpc.NewConn(codec, notifier)
 
@@ -0,0 +1,315 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/tracing"
+)
+
+// maxAuditArgsLen is the maximum length of the argument summary
+// stored with each audit record.
+const maxAuditArgsLen = 1024
+
+// auditRecorder is implemented by types that can persist audit records.
+// It is satisfied by *state.State.
+type auditRecorder interface {
+	AddAuditRecord(rec state.AuditRecord) error
+}
+
+// auditingRoot records every call to a mutating facade method in the
+// environment's audit log.
+type auditingRoot struct {
+	rpc.MethodFinder
+	recorder auditRecorder
+	user     names.Tag
+}
+
+// newAuditingRoot returns a new auditingRoot which records calls
+// made by the given user.
+func newAuditingRoot(finder rpc.MethodFinder, recorder auditRecorder, user names.Tag) *auditingRoot {
+	return &auditingRoot{
+		MethodFinder: finder,
+		recorder:     recorder,
+		user:         user,
+	}
+}
+
+// Kill implements rpc.Killer, passing the call on to the wrapped root.
+func (r *auditingRoot) Kill() {
+	killRoot(r.MethodFinder)
+}
+
+// Cleanup implements rpc.Cleaner, passing the call on to the wrapped
+// root.
+func (r *auditingRoot) Cleanup() {
+	cleanupRoot(r.MethodFinder)
+}
+
+// FindMethod returns a MethodCaller which records the call in the
+// audit log, unless the method is known not to change the state of
+// the environment.
+func (r *auditingRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if isReadOnlyCall(rootName, methodName) {
+		return caller, nil
+	}
+	return &auditingCaller{
+		MethodCaller: caller,
+		recorder:     r.recorder,
+		record: state.AuditRecord{
+			User:    r.user.String(),
+			Facade:  rootName,
+			Version: version,
+			Method:  methodName,
+		},
+	}, nil
+}
+
+// auditingCaller wraps a MethodCaller, recording the outcome of each
+// call made through it.
+type auditingCaller struct {
+	rpcreflect.MethodCaller
+	recorder auditRecorder
+	record   state.AuditRecord
+}
+
+// Call is part of the rpcreflect.MethodCaller interface.
+func (c *auditingCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	result, err := c.MethodCaller.Call(objId, arg)
+
+	rec := c.record
+	rec.Timestamp = time.Now()
+	rec.Args = auditArgs(arg)
+	rec.Entities = auditEntities(arg)
+	if err != nil {
+		rec.Error = err.Error()
+	} else {
+		rec.Error = auditResultError(result)
+	}
+	if auditErr := c.recorder.AddAuditRecord(rec); auditErr != nil {
+		logger.Errorf("cannot record call to %s.%s by %s: %v", rec.Facade, rec.Method, rec.User, auditErr)
+	}
+	return result, err
+}
+
+// WithSpan implements rpc.TracingMethodCaller, auditing the calls made
+// on behalf of the span.
+func (c *auditingCaller) WithSpan(span *tracing.Span) rpcreflect.MethodCaller {
+	return &auditingCaller{
+		MethodCaller: rpc.CallerWithSpan(c.MethodCaller, span),
+		recorder:     c.recorder,
+		record:       c.record,
+	}
+}
+
+// auditArgs returns a summary of the given call arguments suitable for
+// storing in the audit log. Anything that looks like a secret is
+// redacted.
+func auditArgs(arg reflect.Value) string {
+	if !arg.IsValid() {
+		return ""
+	}
+	data, err := json.Marshal(arg.Interface())
+	if err != nil {
+		return ""
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return ""
+	}
+	if data, err = json.Marshal(redactSecrets(decoded)); err != nil {
+		return ""
+	}
+	summary := string(data)
+	if len(summary) > maxAuditArgsLen {
+		summary = summary[:maxAuditArgsLen-3] + "..."
+	}
+	return summary
+}
+
+// secretKeys holds lower-cased fragments of argument field names whose
+// values must not be recorded.
+var secretKeys = []string{
+	"password",
+	"credentials",
+	"macaroon",
+	"secret",
+	"key",
+	"token",
+}
+
+// configFields holds the names of argument fields that carry charm or
+// environment config. Config may hold secrets under any name, so its
+// values are not recorded; lists of option names are.
+var configFields = set.NewStrings(
+	"Config",
+	"ConfigYAML",
+	"Options",
+	"SettingsStrings",
+	"SettingsYAML",
+)
+
+func redactSecrets(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if isSecretKey(key) || isConfigValue(key, value) {
+				v[key] = "<redacted>"
+			} else {
+				v[key] = redactSecrets(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactSecrets(value)
+		}
+	}
+	return v
+}
+
+func isSecretKey(key string) bool {
+	lowerKey := strings.ToLower(key)
+	for _, secret := range secretKeys {
+		if strings.Contains(lowerKey, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+func isConfigValue(key string, value interface{}) bool {
+	if !configFields.Contains(key) {
+		return false
+	}
+	switch value.(type) {
+	case map[string]interface{}, string:
+		return true
+	}
+	return false
+}
+
+// auditEntities returns the tags of the entities referred to by the
+// given call arguments. Tags are found in string fields whose names end
+// in "Tag", and service names in fields called "ServiceName".
+func auditEntities(arg reflect.Value) []string {
+	found := set.NewStrings()
+	collectAuditEntities(arg, found)
+	if found.Size() == 0 {
+		return nil
+	}
+	return found.SortedValues()
+}
+
+func collectAuditEntities(v reflect.Value, found set.Strings) {
+	if !v.IsValid() {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			collectAuditEntities(v.Elem(), found)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			collectAuditEntities(v.Index(i), found)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				// Unexported field.
+				continue
+			}
+			value := v.Field(i)
+			if value.Kind() == reflect.String {
+				addAuditEntity(field.Name, value.String(), found)
+				continue
+			}
+			collectAuditEntities(value, found)
+		}
+	}
+}
+
+func addAuditEntity(fieldName, value string, found set.Strings) {
+	switch {
+	case value == "":
+	case strings.HasSuffix(fieldName, "Tag"):
+		if tag, err := names.ParseTag(value); err == nil {
+			found.Add(tag.String())
+		}
+	case fieldName == "ServiceName":
+		if names.IsValidService(value) {
+			found.Add(names.NewServiceTag(value).String())
+		}
+	}
+}
+
+// auditResultError returns the message of the first error reported in
+// the given call result, or the empty string if there is none. Bulk
+// calls report errors in their results rather than failing outright.
+func auditResultError(result reflect.Value) string {
+	if !result.IsValid() {
+		return ""
+	}
+	if err := findResultError(result, 0); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// maxResultErrorDepth limits how deeply findResultError descends into
+// a result value.
+const maxResultErrorDepth = 4
+
+var paramsErrorType = reflect.TypeOf(&params.Error{})
+
+func findResultError(v reflect.Value, depth int) *params.Error {
+	if depth > maxResultErrorDepth || !v.IsValid() {
+		return nil
+	}
+	if v.Type() == paramsErrorType {
+		if v.IsNil() {
+			return nil
+		}
+		return v.Interface().(*params.Error)
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			return findResultError(v.Elem(), depth+1)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := findResultError(v.Index(i), depth+1); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			if err := findResultError(v.Field(i), depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package auditlog implements the API facade used to query the
+// environment's audit log.
+package auditlog
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("AuditLog", 1, NewAPI)
+}
+
+// AuditLog defines the methods on the audit log API end point.
+type AuditLog interface {
+	// Records returns the audit records matching the given filter.
+	Records(params.AuditRecordFilter) (params.AuditRecordResults, error)
+}
+
+// API implements the AuditLog interface and is the concrete
+// implementation of the api end point.
+type API struct {
+	access     auditLogAccess
+	authorizer common.Authorizer
+}
+
+var _ AuditLog = (*API)(nil)
+
+// NewAPI returns a new audit log API facade.
+func NewAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	// The audit log records what every user has done to the
+	// environment, so only its administrators may read it.
+	user, ok := authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return nil, common.ErrPerm
+	}
+	envUser, err := st.EnvironmentUser(user)
+	if errors.IsNotFound(err) {
+		return nil, common.ErrPerm
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if envUser.Access() != state.EnvironmentAdminAccess {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		access:     getState(st),
+		authorizer: authorizer,
+	}, nil
+}
+
+var getState = func(st *state.State) auditLogAccess {
+	return stateShim{st}
+}
+
+// Records implements AuditLog.Records().
+func (a *API) Records(args params.AuditRecordFilter) (params.AuditRecordResults, error) {
+	filter := state.AuditRecordFilter{
+		User:   args.User,
+		Entity: args.Entity,
+		Limit:  args.Limit,
+	}
+	if args.User != "" {
+		tag, err := names.ParseTag(args.User)
+		if err != nil {
+			return params.AuditRecordResults{}, common.ServerError(err)
+		}
+		filter.User = tag.String()
+	}
+	if args.Entity != "" {
+		tag, err := names.ParseTag(args.Entity)
+		if err != nil {
+			return params.AuditRecordResults{}, common.ServerError(err)
+		}
+		filter.Entity = tag.String()
+	}
+	if args.Since != nil {
+		filter.Since = *args.Since
+	}
+	if args.Until != nil {
+		filter.Until = *args.Until
+	}
+	records, err := a.access.AuditRecords(filter)
+	if err != nil {
+		return params.AuditRecordResults{}, common.ServerError(err)
+	}
+	results := make([]params.AuditRecord, len(records))
+	for i, rec := range records {
+		results[i] = params.AuditRecord{
+			Timestamp: rec.Timestamp,
+			User:      rec.User,
+			Facade:    rec.Facade,
+			Version:   rec.Version,
+			Method:    rec.Method,
+			Args:      rec.Args,
+			Entities:  rec.Entities,
+			Error:     rec.Error,
+		}
+	}
+	return params.AuditRecordResults{Results: results}, nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/auditlog"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type auditLogSuite struct {
+	jujutesting.JujuConnSuite
+
+	api        *auditlog.API
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&auditLogSuite{})
+
+var baseTime = time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+
+func (s *auditLogSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.api, err = auditlog.NewAPI(s.State, common.NewResources(), s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	for i, rec := range []state.AuditRecord{{
+		User:     "user-admin",
+		Method:   "ServiceDeploy",
+		Entities: []string{"service-wordpress"},
+	}, {
+		User:     "user-bob",
+		Method:   "DestroyMachines",
+		Entities: []string{"machine-1"},
+		Error:    "machine 1 has unit wordpress/0 assigned",
+	}} {
+		rec.Facade = "Client"
+		rec.Timestamp = baseTime.Add(time.Duration(i) * time.Minute)
+		err := s.State.AddAuditRecord(rec)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *auditLogSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	authorizer := s.authorizer
+	authorizer.Tag = names.NewUnitTag("mysql/0")
+	api, err := auditlog.NewAPI(s.State, common.NewResources(), authorizer)
+	c.Assert(api, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *auditLogSuite) TestNewAPIRefusesNonAdmin(c *gc.C) {
+	for _, access := range []state.EnvironmentAccess{
+		state.EnvironmentReadAccess,
+		state.EnvironmentWriteAccess,
+	} {
+		envUser := s.Factory.MakeEnvUser(c, &factory.EnvUserParams{Access: access})
+		authorizer := s.authorizer
+		authorizer.Tag = envUser.UserTag()
+		api, err := auditlog.NewAPI(s.State, common.NewResources(), authorizer)
+		c.Assert(api, gc.IsNil)
+		c.Assert(err, gc.ErrorMatches, "permission denied")
+	}
+}
+
+func (s *auditLogSuite) TestNewAPIRefusesNonEnvironmentUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoEnvUser: true})
+	authorizer := s.authorizer
+	authorizer.Tag = user.UserTag()
+	api, err := auditlog.NewAPI(s.State, common.NewResources(), authorizer)
+	c.Assert(api, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *auditLogSuite) TestRecords(c *gc.C) {
+	// Bound the query so that any records made while setting up
+	// the suite are not included.
+	until := baseTime.Add(time.Hour)
+	results, err := s.api.Records(params.AuditRecordFilter{Until: &until})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.AuditRecordResults{
+		Results: []params.AuditRecord{{
+			Timestamp: baseTime,
+			User:      "user-admin",
+			Facade:    "Client",
+			Method:    "ServiceDeploy",
+			Entities:  []string{"service-wordpress"},
+		}, {
+			Timestamp: baseTime.Add(time.Minute),
+			User:      "user-bob",
+			Facade:    "Client",
+			Method:    "DestroyMachines",
+			Entities:  []string{"machine-1"},
+			Error:     "machine 1 has unit wordpress/0 assigned",
+		}},
+	})
+}
+
+func (s *auditLogSuite) TestRecordsFilterByUserAndEntity(c *gc.C) {
+	results, err := s.api.Records(params.AuditRecordFilter{User: "user-bob"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Method, gc.Equals, "DestroyMachines")
+
+	results, err = s.api.Records(params.AuditRecordFilter{Entity: "service-wordpress"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Method, gc.Equals, "ServiceDeploy")
+}
+
+func (s *auditLogSuite) TestRecordsFilterByTime(c *gc.C) {
+	since := baseTime.Add(30 * time.Second)
+	end := baseTime.Add(time.Hour)
+	results, err := s.api.Records(params.AuditRecordFilter{Since: &since, Until: &end})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Method, gc.Equals, "DestroyMachines")
+
+	until := since
+	results, err = s.api.Records(params.AuditRecordFilter{Until: &until})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Method, gc.Equals, "ServiceDeploy")
+}
+
+func (s *auditLogSuite) TestRecordsInvalidTag(c *gc.C) {
+	_, err := s.api.Records(params.AuditRecordFilter{User: "bob"})
+	c.Assert(err, gc.ErrorMatches, `"bob" is not a valid tag`)
+}
@@ -0,0 +1,14 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog
+
+import "github.com/juju/juju/state"
+
+type auditLogAccess interface {
+	AuditRecords(filter state.AuditRecordFilter) ([]state.AuditRecord, error)
+}
+
+type stateShim struct {
+	*state.State
+}
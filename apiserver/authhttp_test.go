@@ -47,6 +47,15 @@ func (s *authHttpSuite) baseURL(c *gc.C) *url.URL {
 	}
 }
 
+// makeEnvUser adds a user with the given access to the environment,
+// returning its tag and password.
+func (s *authHttpSuite) makeEnvUser(c *gc.C, access state.EnvironmentAccess) (string, string) {
+	password := "sekrit"
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: password, NoEnvUser: true})
+	s.Factory.MakeEnvUser(c, &factory.EnvUserParams{User: user.Name(), Access: access})
+	return user.Tag().String(), password
+}
+
 func (s *authHttpSuite) assertErrorResponse(c *gc.C, resp *http.Response, expCode int, expError string) {
 	body := assertResponse(c, resp, expCode, apihttp.CTypeJSON)
 	c.Check(jsonResponse(c, body).Error, gc.Matches, expError)
@@ -121,7 +130,7 @@ func (s *userAuthHttpSuite) setupOtherEnvironment(c *gc.C) *state.State {
 	envState := s.Factory.MakeEnvironment(c, nil)
 	s.AddCleanup(func(*gc.C) { envState.Close() })
 	user := s.Factory.MakeUser(c, nil)
-	_, err := envState.AddEnvironmentUser(user.UserTag(), s.userTag, "")
+	_, err := envState.AddEnvironmentUser(user.UserTag(), s.userTag, "", state.EnvironmentAdminAccess)
 	c.Assert(err, jc.ErrorIsNil)
 	s.userTag = user.UserTag()
 	s.password = "password"
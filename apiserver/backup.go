@@ -39,8 +39,10 @@ func (h *backupHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	}
 	defer stateWrapper.cleanup()
 
-	if err := stateWrapper.authenticateUser(req); err != nil {
-		h.authError(resp, h)
+	// Backups hold every secret in the environment, and restoring one
+	// replaces it, so both need administrative access.
+	if err := stateWrapper.authenticateUser(req, state.EnvironmentAdminAccess); err != nil {
+		h.userAuthError(resp, h, err)
 		return
 	}
 
@@ -90,6 +90,21 @@ func (s *backupsSuite) TestInvalidHTTPMethods(c *gc.C) {
 	}
 }
 
+func (s *backupsSuite) TestRequiresAdminAccess(c *gc.C) {
+	for _, access := range []state.EnvironmentAccess{
+		state.EnvironmentReadAccess,
+		state.EnvironmentWriteAccess,
+	} {
+		tag, password := s.makeEnvUser(c, access)
+		for _, method := range []string{"GET", "PUT"} {
+			c.Logf("%s by user with %s access", method, access)
+			resp, err := s.sendRequest(c, tag, password, method, s.backupURL(c), "", nil)
+			c.Assert(err, jc.ErrorIsNil)
+			s.checkErrorResponse(c, resp, http.StatusForbidden, "permission denied")
+		}
+	}
+}
+
 func (s *backupsSuite) TestAuthRequiresClientNotMachine(c *gc.C) {
 	// Add a machine and try to login.
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
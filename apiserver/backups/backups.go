@@ -100,6 +100,9 @@ func ResultFromMetadata(meta *backups.Metadata) params.BackupsMetadataResult {
 		result.Finished = *meta.Finished
 	}
 	result.Notes = meta.Notes
+	result.Target = meta.Target
+	result.Scheduled = meta.Scheduled
+	result.KeyFingerprint = meta.KeyFingerprint
 
 	result.Environment = meta.Origin.Environment
 	result.Machine = meta.Origin.Machine
@@ -123,6 +126,9 @@ func MetadataFromResult(result params.BackupsMetadataResult) *backups.Metadata {
 	meta.Origin.Hostname = result.Hostname
 	meta.Origin.Version = result.Version
 	meta.Notes = result.Notes
+	meta.Target = result.Target
+	meta.Scheduled = result.Scheduled
+	meta.KeyFingerprint = result.KeyFingerprint
 	meta.SetFileInfo(result.Size, result.Checksum, result.ChecksumFormat)
 	return meta
 }
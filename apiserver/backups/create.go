@@ -40,6 +40,27 @@ func (a *API) Create(args params.BackupsCreateArgs) (p params.BackupsMetadataRes
 	}
 	meta.Notes = args.Notes
 
+	cfg, err := a.st.EnvironConfig()
+	if err != nil {
+		return p, errors.Trace(err)
+	}
+	meta.Target = args.Target
+	if meta.Target == "" {
+		meta.Target = cfg.BackupTarget()
+	}
+	if err := cfg.CheckBackupTarget(meta.Target); err != nil {
+		return p, errors.Trace(err)
+	}
+	encryptionKey := args.EncryptionKey
+	if encryptionKey == "" {
+		encryptionKey, _ = cfg.BackupEncryptionKey()
+	}
+	if encryptionKey != "" {
+		if err := meta.SetEncryptionKey(encryptionKey); err != nil {
+			return p, errors.Trace(err)
+		}
+	}
+
 	err = backupsMethods.Create(meta, a.paths, dbInfo)
 	if err != nil {
 		return p, errors.Trace(err)
@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/juju/apiserver/backups"
 	"github.com/juju/juju/apiserver/params"
+	backupstesting "github.com/juju/juju/state/backups/testing"
 )
 
 func (s *backupsSuite) TestCreateOkay(c *gc.C) {
@@ -52,3 +53,88 @@ func (s *backupsSuite) TestCreateError(c *gc.C) {
 
 	c.Check(err, gc.ErrorMatches, "failed!")
 }
+
+func (s *backupsSuite) TestCreateTarget(c *gc.C) {
+	s.PatchValue(backups.WaitUntilReady,
+		func(*mgo.Session, int) error { return nil },
+	)
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"backup-local-dir": "/srv/backups",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	fake := s.setBackups(c, nil, "")
+
+	args := params.BackupsCreateArgs{Target: "local"}
+	result, err := s.api.Create(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Target, gc.Equals, "local")
+	c.Check(fake.MetaArg.Target, gc.Equals, "local")
+}
+
+func (s *backupsSuite) TestCreateDefaultTarget(c *gc.C) {
+	s.PatchValue(backups.WaitUntilReady,
+		func(*mgo.Session, int) error { return nil },
+	)
+	fake := s.setBackups(c, nil, "")
+
+	var args params.BackupsCreateArgs
+	result, err := s.api.Create(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Target, gc.Equals, "state")
+	c.Check(fake.MetaArg.Target, gc.Equals, "state")
+}
+
+func (s *backupsSuite) TestCreateTargetNotConfigured(c *gc.C) {
+	s.PatchValue(backups.WaitUntilReady,
+		func(*mgo.Session, int) error { return nil },
+	)
+	fake := s.setBackups(c, nil, "")
+
+	args := params.BackupsCreateArgs{Target: "sftp"}
+	_, err := s.api.Create(args)
+	c.Assert(err, gc.ErrorMatches, `backup target "sftp" requires backup-sftp-url to be set`)
+	c.Check(fake.Calls, gc.HasLen, 0)
+}
+
+func (s *backupsSuite) TestCreateEncrypted(c *gc.C) {
+	s.PatchValue(backups.WaitUntilReady,
+		func(*mgo.Session, int) error { return nil },
+	)
+	publicKey, _ := backupstesting.NewEncryptionKey(c)
+	fake := s.setBackups(c, nil, "")
+
+	args := params.BackupsCreateArgs{EncryptionKey: publicKey}
+	result, err := s.api.Create(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.KeyFingerprint, gc.Matches, "[0-9A-F]{40}")
+	c.Check(fake.MetaArg.KeyFingerprint, gc.Equals, result.KeyFingerprint)
+}
+
+func (s *backupsSuite) TestCreateEncryptedByDefault(c *gc.C) {
+	s.PatchValue(backups.WaitUntilReady,
+		func(*mgo.Session, int) error { return nil },
+	)
+	publicKey, _ := backupstesting.NewEncryptionKey(c)
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"backup-encryption-key": publicKey,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	fake := s.setBackups(c, nil, "")
+
+	var args params.BackupsCreateArgs
+	result, err := s.api.Create(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.KeyFingerprint, gc.Matches, "[0-9A-F]{40}")
+	c.Check(fake.MetaArg.KeyFingerprint, gc.Equals, result.KeyFingerprint)
+}
+
+func (s *backupsSuite) TestCreateEncryptionKeyInvalid(c *gc.C) {
+	s.PatchValue(backups.WaitUntilReady,
+		func(*mgo.Session, int) error { return nil },
+	)
+	s.setBackups(c, nil, "")
+
+	args := params.BackupsCreateArgs{EncryptionKey: "not a key"}
+	_, err := s.api.Create(args)
+	c.Check(err, gc.ErrorMatches, "cannot read OpenPGP key: .*")
+}
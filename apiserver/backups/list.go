@@ -7,24 +7,57 @@ import (
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state/backups"
 )
 
 // List provides the implementation of the API method.
 func (a *API) List(args params.BackupsListArgs) (params.BackupsListResult, error) {
 	var result params.BackupsListResult
 
-	backups, closer := newBackups(a.st)
+	backupsMethods, closer := newBackups(a.st)
 	defer closer.Close()
 
-	metaList, err := backups.List()
+	metaList, err := backupsMethods.List()
 	if err != nil {
 		return result, errors.Trace(err)
 	}
 
-	result.List = make([]params.BackupsMetadataResult, len(metaList))
-	for i, meta := range metaList {
-		result.List[i] = ResultFromMetadata(meta)
+	result.List = make([]params.BackupsMetadataResult, 0, len(metaList))
+	for _, meta := range metaList {
+		if args.Scheduled && !meta.Scheduled {
+			continue
+		}
+		result.List = append(result.List, ResultFromMetadata(meta))
+	}
+
+	if args.Scheduled {
+		result.Schedule, err = a.scheduleStatus()
+		if err != nil {
+			return result, errors.Trace(err)
+		}
 	}
 
 	return result, nil
 }
+
+// scheduleStatus returns the configured backup schedule along with
+// the outcome of the most recent scheduled backup.
+func (a *API) scheduleStatus() (*params.BackupsScheduleStatus, error) {
+	cfg, err := a.st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result params.BackupsScheduleStatus
+	result.Schedule, _ = cfg.BackupSchedule()
+
+	status, err := backups.GetScheduleStatus(a.st)
+	if errors.IsNotFound(err) {
+		return &result, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result.LastAttempt = status.LastAttempt
+	result.LastSuccess = status.LastSuccess
+	result.LastError = status.LastError
+	return &result, nil
+}
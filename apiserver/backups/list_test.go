@@ -6,12 +6,15 @@ package backups_test
 import (
 	"bytes"
 	"io/ioutil"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/backups"
 	"github.com/juju/juju/apiserver/params"
+	statebackups "github.com/juju/juju/state/backups"
+	backupstesting "github.com/juju/juju/state/backups/testing"
 )
 
 func (s *backupsSuite) TestListOkay(c *gc.C) {
@@ -36,3 +39,44 @@ func (s *backupsSuite) TestListError(c *gc.C) {
 
 	c.Check(err, gc.ErrorMatches, "failed!")
 }
+
+func (s *backupsSuite) TestListScheduled(c *gc.C) {
+	scheduled := backupstesting.NewMetadataStarted()
+	scheduled.Scheduled = true
+	fake := s.setBackups(c, s.meta, "")
+	fake.MetaList = append(fake.MetaList, scheduled)
+
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"backup-schedule": "@daily",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	lastAttempt := time.Date(2015, 7, 2, 0, 0, 0, 0, time.UTC)
+	lastSuccess := time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC)
+	err = statebackups.SetScheduleStatus(s.State, statebackups.ScheduleStatus{
+		LastAttempt: lastAttempt,
+		LastSuccess: lastSuccess,
+		LastError:   "disk full",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.api.List(params.BackupsListArgs{Scheduled: true})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result, gc.DeepEquals, params.BackupsListResult{
+		List: []params.BackupsMetadataResult{backups.ResultFromMetadata(scheduled)},
+		Schedule: &params.BackupsScheduleStatus{
+			Schedule:    "@daily",
+			LastAttempt: lastAttempt,
+			LastSuccess: lastSuccess,
+			LastError:   "disk full",
+		},
+	})
+}
+
+func (s *backupsSuite) TestListScheduledNeverRun(c *gc.C) {
+	s.setBackups(c, s.meta, "")
+
+	result, err := s.api.List(params.BackupsListArgs{Scheduled: true})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.List, gc.HasLen, 0)
+	c.Check(result.Schedule, jc.DeepEquals, &params.BackupsScheduleStatus{})
+}
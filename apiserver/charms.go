@@ -52,8 +52,8 @@ func (h *charmsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
 	case "POST":
-		if err := stateWrapper.authenticateUser(r); err != nil {
-			h.authError(w, h)
+		if err := stateWrapper.authenticateUser(r, state.EnvironmentWriteAccess); err != nil {
+			h.userAuthError(w, h, err)
 			return
 		}
 		// Add a local charm to the store provider.
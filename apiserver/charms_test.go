@@ -87,6 +87,18 @@ func (s *charmsSuite) TestAuthRequiresUser(c *gc.C) {
 	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected series=URL argument")
 }
 
+func (s *charmsSuite) TestPOSTRequiresWriteAccess(c *gc.C) {
+	tag, password := s.makeEnvUser(c, state.EnvironmentReadAccess)
+	resp, err := s.sendRequest(c, tag, password, "POST", s.charmsURI(c, "?series=quantal"), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusForbidden, "permission denied")
+
+	tag, password = s.makeEnvUser(c, state.EnvironmentWriteAccess)
+	resp, err = s.sendRequest(c, tag, password, "POST", s.charmsURI(c, ""), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, "expected series=URL argument")
+}
+
 func (s *charmsSuite) TestUploadRequiresSeries(c *gc.C) {
 	resp, err := s.authRequest(c, "POST", s.charmsURI(c, ""), "", nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,236 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
+)
+
+// bundleExport holds a bundle describing the services deployed in an
+// environment, in the format read by "juju deploy".
+type bundleExport struct {
+	Services  map[string]*serviceExport `yaml:"services"`
+	Machines  map[string]*machineExport `yaml:"machines,omitempty"`
+	Relations [][]string                `yaml:"relations,omitempty"`
+}
+
+type serviceExport struct {
+	Charm       string                 `yaml:"charm"`
+	NumUnits    int                    `yaml:"num_units,omitempty"`
+	To          []string               `yaml:"to,omitempty"`
+	Options     map[string]interface{} `yaml:"options,omitempty"`
+	Constraints string                 `yaml:"constraints,omitempty"`
+	Storage     map[string]string      `yaml:"storage,omitempty"`
+	Expose      bool                   `yaml:"expose,omitempty"`
+	ExposeFrom  []string               `yaml:"expose_from,omitempty"`
+}
+
+type machineExport struct {
+	Series      string `yaml:"series,omitempty"`
+	Constraints string `yaml:"constraints,omitempty"`
+}
+
+// ExportBundle returns a bundle, in YAML format, that describes the
+// services in the environment, the machines their units are placed
+// on and the relations between them. Machines in the bundle are
+// identified by the ids of the top level machines they describe.
+// Since the bundle holds the options of every service, which may
+// include credentials, only environment administrators may export it.
+func (c *Client) ExportBundle() (params.StringResult, error) {
+	bundle, err := exportBundle(c.api.state)
+	if err != nil {
+		return params.StringResult{}, errors.Trace(err)
+	}
+	data, err := goyaml.Marshal(bundle)
+	if err != nil {
+		return params.StringResult{}, errors.Trace(err)
+	}
+	return params.StringResult{Result: string(data)}, nil
+}
+
+func exportBundle(st *state.State) (*bundleExport, error) {
+	bundle := &bundleExport{
+		Services: make(map[string]*serviceExport),
+		Machines: make(map[string]*machineExport),
+	}
+	services, err := st.AllServices()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sort.Sort(servicesByName(services))
+	// containers maps the ids of containers hosting units to the
+	// placement directive of the first unit exported in each, so that
+	// later units are placed in the same container.
+	containers := make(map[string]string)
+	for _, service := range services {
+		export, err := exportService(service)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot export service %q", service.Name())
+		}
+		bundle.Services[service.Name()] = export
+		if !service.IsPrincipal() {
+			continue
+		}
+		units, err := service.AllUnits()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		sort.Sort(unitsByNumber(units))
+		export.NumUnits = len(units)
+		for i, unit := range units {
+			machineId, err := unit.AssignedMachineId()
+			if errors.IsNotAssigned(err) {
+				export.To = append(export.To, "new")
+				continue
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			hostId := state.TopParentId(machineId)
+			if _, ok := bundle.Machines[hostId]; !ok {
+				machine, err := exportMachine(st, hostId)
+				if err != nil {
+					return nil, errors.Annotatef(err, "cannot export machine %q", hostId)
+				}
+				bundle.Machines[hostId] = machine
+			}
+			placement := hostId
+			if containerType := state.ContainerTypeFromId(machineId); containerType != "" {
+				if shared, ok := containers[machineId]; ok {
+					placement = shared
+				} else {
+					containers[machineId] = fmt.Sprintf("%s/%d", service.Name(), i)
+					placement = fmt.Sprintf("%s:%s", containerType, hostId)
+				}
+			}
+			export.To = append(export.To, placement)
+		}
+	}
+	relations, err := st.AllRelations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, relation := range relations {
+		// The relation key holds its endpoints in a consistent order.
+		endpoints := strings.Fields(relation.String())
+		if len(endpoints) != 2 {
+			// Peer relations are established implicitly.
+			continue
+		}
+		bundle.Relations = append(bundle.Relations, endpoints)
+	}
+	sort.Sort(relationsByKey(bundle.Relations))
+	return bundle, nil
+}
+
+func exportService(service *state.Service) (*serviceExport, error) {
+	curl, _ := service.CharmURL()
+	if curl == nil {
+		return nil, errors.New("service has no charm")
+	}
+	if curl.Schema == "local" {
+		// Local charm revisions are assigned by the environment
+		// they are added to.
+		curl = curl.WithRevision(-1)
+	}
+	export := &serviceExport{
+		Charm:  curl.String(),
+		Expose: service.IsExposed(),
+	}
+	if export.Expose {
+		export.ExposeFrom = service.ExposedCIDRs()
+	}
+	settings, err := service.ConfigSettings()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(settings) > 0 {
+		export.Options = settings
+	}
+	if !service.IsPrincipal() {
+		return export, nil
+	}
+	cons, err := service.Constraints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !constraints.IsEmpty(&cons) {
+		export.Constraints = cons.String()
+	}
+	storageConstraints, err := service.StorageConstraints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for name, cons := range storageConstraints {
+		if export.Storage == nil {
+			export.Storage = make(map[string]string)
+		}
+		export.Storage[name] = storageConstraintsString(cons)
+	}
+	return export, nil
+}
+
+func exportMachine(st *state.State, id string) (*machineExport, error) {
+	machine, err := st.Machine(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	export := &machineExport{Series: machine.Series()}
+	cons, err := machine.Constraints()
+	if errors.IsNotFound(err) {
+		return export, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !constraints.IsEmpty(&cons) {
+		export.Constraints = cons.String()
+	}
+	return export, nil
+}
+
+// storageConstraintsString returns the given storage constraints in
+// the form accepted by storage.ParseConstraints.
+func storageConstraintsString(cons state.StorageConstraints) string {
+	var fields []string
+	if cons.Pool != "" {
+		fields = append(fields, cons.Pool)
+	}
+	fields = append(fields, fmt.Sprint(cons.Count), fmt.Sprintf("%dM", cons.Size))
+	return strings.Join(fields, ",")
+}
+
+type servicesByName []*state.Service
+
+func (s servicesByName) Len() int           { return len(s) }
+func (s servicesByName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s servicesByName) Less(i, j int) bool { return s[i].Name() < s[j].Name() }
+
+type unitsByNumber []*state.Unit
+
+func (s unitsByNumber) Len() int           { return len(s) }
+func (s unitsByNumber) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s unitsByNumber) Less(i, j int) bool { return unitNumber(s[i]) < unitNumber(s[j]) }
+
+func unitNumber(unit *state.Unit) int {
+	name := unit.Name()
+	n, _ := strconv.Atoi(name[strings.LastIndex(name, "/")+1:])
+	return n
+}
+
+type relationsByKey [][]string
+
+func (s relationsByKey) Len() int      { return len(s) }
+func (s relationsByKey) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s relationsByKey) Less(i, j int) bool {
+	return strings.Join(s[i], " ") < strings.Join(s[j], " ")
+}
@@ -0,0 +1,130 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type exportBundleSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&exportBundleSuite{})
+
+func (s *exportBundleSuite) addRelation(c *gc.C, endpoints ...string) {
+	eps, err := s.State.InferEndpoints(endpoints...)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *exportBundleSuite) assignUnit(c *gc.C, svc *state.Service, machine *state.Machine) {
+	unit, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	if machine != nil {
+		err = unit.AssignToMachine(machine)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *exportBundleSuite) TestExportBundle(c *gc.C) {
+	machine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.MustParse("mem=4G"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, machine.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err = wordpress.SetConstraints(constraints.MustParse("cpu-cores=2"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress.UpdateConfigSettings(charm.Settings{"blog-title": "Staging"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress.SetExposedFrom([]string{"10.0.0.0/8"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assignUnit(c, wordpress, machine)
+	s.assignUnit(c, wordpress, nil)
+
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	s.assignUnit(c, mysql, container)
+	s.assignUnit(c, mysql, container)
+
+	s.AddTestingService(c, "logging", s.AddTestingCharm(c, "logging"))
+	s.addRelation(c, "wordpress", "mysql")
+	s.addRelation(c, "logging:logging-directory", "wordpress:logging-dir")
+
+	data, err := s.APIState.Client().ExportBundle()
+	c.Assert(err, jc.ErrorIsNil)
+	var bundle map[string]interface{}
+	err = goyaml.Unmarshal([]byte(data), &bundle)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bundle, jc.DeepEquals, map[string]interface{}{
+		"services": map[interface{}]interface{}{
+			"logging": map[interface{}]interface{}{
+				"charm": "local:quantal/logging",
+			},
+			"mysql": map[interface{}]interface{}{
+				"charm":     "local:quantal/mysql",
+				"num_units": 2,
+				"to":        []interface{}{"lxc:0", "mysql/0"},
+			},
+			"wordpress": map[interface{}]interface{}{
+				"charm":       "local:quantal/wordpress",
+				"num_units":   2,
+				"to":          []interface{}{"0", "new"},
+				"options":     map[interface{}]interface{}{"blog-title": "Staging"},
+				"constraints": "cpu-cores=2",
+				"expose":      true,
+				"expose_from": []interface{}{"10.0.0.0/8"},
+			},
+		},
+		"machines": map[interface{}]interface{}{
+			"0": map[interface{}]interface{}{
+				"series":      "quantal",
+				"constraints": "mem=4096M",
+			},
+		},
+		"relations": []interface{}{
+			[]interface{}{"logging:logging-directory", "wordpress:logging-dir"},
+			[]interface{}{"wordpress:db", "mysql:server"},
+		},
+	})
+}
+
+func (s *exportBundleSuite) TestExportBundleStorage(c *gc.C) {
+	ch := s.AddTestingCharm(c, "storage-block")
+	s.AddTestingServiceWithStorage(c, "storage-block", ch, map[string]state.StorageConstraints{
+		"data": {Pool: "loop", Count: 1, Size: 1024},
+	})
+
+	data, err := s.APIState.Client().ExportBundle()
+	c.Assert(err, jc.ErrorIsNil)
+	var bundle struct {
+		Services map[string]struct {
+			Storage map[string]string
+		}
+	}
+	err = goyaml.Unmarshal([]byte(data), &bundle)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bundle.Services["storage-block"].Storage["data"], gc.Equals, "loop,1,1024M")
+}
+
+func (s *exportBundleSuite) TestExportBundleEmpty(c *gc.C) {
+	data, err := s.APIState.Client().ExportBundle()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.Equals, "services: {}\n")
+}
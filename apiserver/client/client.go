@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/apiserver/highavailability"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/apiserver/service"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/manual"
 	"github.com/juju/juju/instance"
@@ -28,6 +29,12 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Client", 0, NewClient)
+
+	// Version 1 has the same set of methods as 0, with the same
+	// signatures, but its ServiceExpose accepts the source CIDRs to
+	// expose a service to. Clients require version 1 to restrict the
+	// sources; otherwise they are compatible.
+	common.RegisterStandardFacade("Client", 1, NewClient)
 }
 
 var logger = loggo.GetLogger("juju.apiserver.client")
@@ -85,7 +92,7 @@ func (c *Client) ServiceSet(p params.ServiceSet) error {
 	if err != nil {
 		return err
 	}
-	return service.ServiceSetSettingsStrings(svc, p.Options)
+	return service.ServiceSetSettingsStrings(svc, p.Options, c.api.auth.GetAuthTag().String())
 }
 
 // NewServiceSetForClientAPI implements the server side of
@@ -101,7 +108,7 @@ func (c *Client) NewServiceSetForClientAPI(p params.ServiceSet) error {
 	if err != nil {
 		return err
 	}
-	return newServiceSetSettingsStringsForClientAPI(svc, p.Options)
+	return newServiceSetSettingsStringsForClientAPI(svc, p.Options, c.api.auth.GetAuthTag().String())
 }
 
 // ServiceUnset implements the server side of Client.ServiceUnset.
@@ -118,7 +125,7 @@ func (c *Client) ServiceUnset(p params.ServiceUnset) error {
 	for _, option := range p.Options {
 		settings[option] = nil
 	}
-	return svc.UpdateConfigSettings(settings)
+	return svc.UpdateConfigSettingsByUser(settings, c.api.auth.GetAuthTag().String())
 }
 
 // ServiceSetYAML implements the server side of Client.ServerSetYAML.
@@ -131,7 +138,7 @@ func (c *Client) ServiceSetYAML(p params.ServiceSetYAML) error {
 	if err != nil {
 		return err
 	}
-	return serviceSetSettingsYAML(svc, p.Config)
+	return serviceSetSettingsYAML(svc, p.Config, c.api.auth.GetAuthTag().String())
 }
 
 // ServiceCharmRelations implements the server side of Client.ServiceCharmRelations.
@@ -222,7 +229,9 @@ func (c *Client) PrivateAddress(p params.PrivateAddress) (results params.Private
 }
 
 // ServiceExpose changes the juju-managed firewall to expose any ports that
-// were also explicitly marked by units as open.
+// were also explicitly marked by units as open. If any source CIDRs are
+// given, the ports are exposed to those CIDRs only, replacing any the
+// service was already exposed to.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) ServiceExpose(args params.ServiceExpose) error {
 	if err := c.check.ChangeAllowed(); err != nil {
@@ -232,7 +241,34 @@ func (c *Client) ServiceExpose(args params.ServiceExpose) error {
 	if err != nil {
 		return err
 	}
-	return svc.SetExposed()
+	if len(args.SourceCIDRs) == 0 {
+		return svc.SetExposed()
+	}
+	if err := network.ValidateCIDRs(args.SourceCIDRs); err != nil {
+		return errors.Trace(err)
+	}
+	if err := c.checkIngressRulesSupported(); err != nil {
+		return errors.Trace(err)
+	}
+	return svc.SetExposedFrom(args.SourceCIDRs)
+}
+
+// checkIngressRulesSupported returns an error satisfying
+// errors.IsNotSupported if the environment's provider cannot restrict
+// the sources from which exposed ports may be reached.
+func (c *Client) checkIngressRulesSupported() error {
+	cfg, err := c.api.state.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	env, err := environs.New(cfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := environs.SupportsIngressRules(env); !ok {
+		return errors.NotSupportedf("exposing services to specific CIDRs on provider %q", cfg.Type())
+	}
+	return nil
 }
 
 // ServiceUnexpose changes the juju-managed firewall to unexpose any ports that
@@ -295,11 +331,11 @@ func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
 	}
 	// Set up service's settings.
 	if args.SettingsYAML != "" {
-		if err = serviceSetSettingsYAML(svc, args.SettingsYAML); err != nil {
+		if err = serviceSetSettingsYAML(svc, args.SettingsYAML, c.api.auth.GetAuthTag().String()); err != nil {
 			return err
 		}
 	} else if len(args.SettingsStrings) > 0 {
-		if err = service.ServiceSetSettingsStrings(svc, args.SettingsStrings); err != nil {
+		if err = service.ServiceSetSettingsStrings(svc, args.SettingsStrings, c.api.auth.GetAuthTag().String()); err != nil {
 			return err
 		}
 	}
@@ -352,8 +388,9 @@ func (c *Client) serviceSetCharm1dot16(service *state.Service, curl *charm.URL,
 }
 
 // serviceSetSettingsYAML updates the settings for the given service,
-// taking the configuration from a YAML string.
-func serviceSetSettingsYAML(service *state.Service, settings string) error {
+// taking the configuration from a YAML string. The change is recorded
+// in the service's config history as made by the given user.
+func serviceSetSettingsYAML(service *state.Service, settings, user string) error {
 	ch, _, err := service.Charm()
 	if err != nil {
 		return err
@@ -362,7 +399,7 @@ func serviceSetSettingsYAML(service *state.Service, settings string) error {
 	if err != nil {
 		return err
 	}
-	return service.UpdateConfigSettings(changes)
+	return service.UpdateConfigSettingsByUser(changes, user)
 }
 
 // newServiceSetSettingsStringsForClientAPI updates the settings for the given
@@ -370,7 +407,7 @@ func serviceSetSettingsYAML(service *state.Service, settings string) error {
 //
 // TODO(Nate): replace serviceSetSettingsStrings with this onces the GUI no
 // longer expects to be able to unset values by sending an empty string.
-func newServiceSetSettingsStringsForClientAPI(service *state.Service, settings map[string]string) error {
+func newServiceSetSettingsStringsForClientAPI(service *state.Service, settings map[string]string, user string) error {
 	ch, _, err := service.Charm()
 	if err != nil {
 		return err
@@ -382,7 +419,7 @@ func newServiceSetSettingsStringsForClientAPI(service *state.Service, settings m
 		return err
 	}
 
-	return service.UpdateConfigSettings(changes)
+	return service.UpdateConfigSettingsByUser(changes, user)
 }
 
 // ServiceSetCharm sets the charm for a given service.
@@ -401,6 +438,23 @@ func (c *Client) ServiceSetCharm(args params.ServiceSetCharm) error {
 	return c.serviceSetCharm(service, args.CharmUrl, args.Force)
 }
 
+// ServiceRollbackCharm changes a service's charm back to the one it
+// ran before its charm was last changed, and returns the URL of that
+// charm.
+func (c *Client) ServiceRollbackCharm(args params.ServiceGet) (params.StringResult, error) {
+	// Like a forced charm upgrade, a rollback is a means of recovering
+	// broken units, so it is not blocked.
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return params.StringResult{}, err
+	}
+	if err := service.RollbackCharm(); err != nil {
+		return params.StringResult{}, errors.Trace(err)
+	}
+	curl, _ := service.CharmURL()
+	return params.StringResult{Result: curl.String()}, nil
+}
+
 // addServiceUnits adds a given number of units to a service.
 func addServiceUnits(state *state.State, args params.AddServiceUnits) ([]*state.Unit, error) {
 	service, err := state.Service(args.ServiceName)
@@ -795,8 +849,7 @@ func (c *Client) ShareEnvironment(args params.ModifyEnvironUsers) (result params
 		}
 		switch arg.Action {
 		case params.AddEnvUser:
-			_, err := c.api.state.AddEnvironmentUser(user, createdBy, "")
-			if err != nil {
+			if err := c.shareEnvironment(user, createdBy, arg.Access); err != nil {
 				err = errors.Annotate(err, "could not share environment")
 				result.Results[i].Error = common.ServerError(err)
 			}
@@ -813,6 +866,25 @@ func (c *Client) ShareEnvironment(args params.ModifyEnvironUsers) (result params
 	return result, nil
 }
 
+// shareEnvironment gives the user the requested access to the
+// environment. A user who already has access to the environment only
+// has their access changed if an access level is explicitly requested.
+func (c *Client) shareEnvironment(user, createdBy names.UserTag, access params.EnvironmentAccess) error {
+	envAccess := state.EnvironmentWriteAccess
+	if access != "" {
+		envAccess = state.EnvironmentAccess(access)
+	}
+	_, err := c.api.state.AddEnvironmentUser(user, createdBy, "", envAccess)
+	if !errors.IsAlreadyExists(err) || access == "" {
+		return err
+	}
+	envUser, err := c.api.state.EnvironmentUser(user)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return envUser.SetAccess(envAccess)
+}
+
 // EnvUserInfo returns information on all users in the environment.
 func (c *Client) EnvUserInfo() (params.EnvUserInfoResults, error) {
 	var results params.EnvUserInfoResults
@@ -833,6 +905,7 @@ func (c *Client) EnvUserInfo() (params.EnvUserInfoResults, error) {
 				CreatedBy:      user.CreatedBy(),
 				DateCreated:    user.DateCreated(),
 				LastConnection: user.LastConnection(),
+				Access:         params.EnvironmentAccess(user.Access()),
 			},
 		})
 	}
@@ -907,11 +980,12 @@ func (c *Client) AgentVersion() (params.AgentVersionResult, error) {
 func (c *Client) EnvironmentGet() (params.EnvironmentConfigResults, error) {
 	result := params.EnvironmentConfigResults{}
 	// Get the existing environment config from the state.
-	config, err := c.api.state.EnvironConfig()
+	cfg, err := c.api.state.EnvironConfig()
 	if err != nil {
 		return result, err
 	}
-	result.Config = config.AllAttrs()
+	result.Config = cfg.AllAttrs()
+	common.MaskBackupSecrets(result.Config)
 	return result, nil
 }
 
@@ -143,6 +143,7 @@ func (s *serverSuite) TestEnvUsersInfo(c *gc.C) {
 					CreatedBy:      owner.UserName(),
 					DateCreated:    owner.DateCreated(),
 					LastConnection: owner.LastConnection(),
+					Access:         params.EnvironmentAdminAccess,
 				},
 			}, {
 				Result: &params.EnvUserInfo{
@@ -151,6 +152,7 @@ func (s *serverSuite) TestEnvUsersInfo(c *gc.C) {
 					CreatedBy:      owner.UserName(),
 					DateCreated:    localUser1.DateCreated(),
 					LastConnection: localUser1.LastConnection(),
+					Access:         params.EnvironmentAdminAccess,
 				},
 			}, {
 				Result: &params.EnvUserInfo{
@@ -159,6 +161,7 @@ func (s *serverSuite) TestEnvUsersInfo(c *gc.C) {
 					CreatedBy:      owner.UserName(),
 					DateCreated:    localUser2.DateCreated(),
 					LastConnection: localUser2.LastConnection(),
+					Access:         params.EnvironmentAdminAccess,
 				},
 			}, {
 				Result: &params.EnvUserInfo{
@@ -167,6 +170,7 @@ func (s *serverSuite) TestEnvUsersInfo(c *gc.C) {
 					CreatedBy:      owner.UserName(),
 					DateCreated:    remoteUser1.DateCreated(),
 					LastConnection: remoteUser1.LastConnection(),
+					Access:         params.EnvironmentAdminAccess,
 				},
 			}, {
 				Result: &params.EnvUserInfo{
@@ -175,6 +179,7 @@ func (s *serverSuite) TestEnvUsersInfo(c *gc.C) {
 					CreatedBy:      owner.UserName(),
 					DateCreated:    remoteUser2.DateCreated(),
 					LastConnection: remoteUser2.LastConnection(),
+					Access:         params.EnvironmentAdminAccess,
 				},
 			}},
 	}
@@ -274,6 +279,60 @@ func (s *serverSuite) TestShareEnvironmentAddLocalUser(c *gc.C) {
 	c.Assert(envUser.UserName(), gc.Equals, user.UserTag().Username())
 	c.Assert(envUser.CreatedBy(), gc.Equals, dummy.AdminUserTag().Username())
 	c.Assert(envUser.LastConnection(), gc.IsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentWriteAccess)
+}
+
+func (s *serverSuite) TestShareEnvironmentAddUserWithAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", NoEnvUser: true})
+	args := params.ModifyEnvironUsers{
+		Changes: []params.ModifyEnvironUser{{
+			UserTag: user.Tag().String(),
+			Action:  params.AddEnvUser,
+			Access:  params.EnvironmentReadAccess,
+		}}}
+
+	result, err := s.client.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.IsNil)
+
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+}
+
+func (s *serverSuite) TestShareEnvironmentChangeAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar"})
+	args := params.ModifyEnvironUsers{
+		Changes: []params.ModifyEnvironUser{{
+			UserTag: user.Tag().String(),
+			Action:  params.AddEnvUser,
+			Access:  params.EnvironmentReadAccess,
+		}}}
+
+	result, err := s.client.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.IsNil)
+
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+}
+
+func (s *serverSuite) TestShareEnvironmentInvalidAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar", NoEnvUser: true})
+	args := params.ModifyEnvironUsers{
+		Changes: []params.ModifyEnvironUser{{
+			UserTag: user.Tag().String(),
+			Action:  params.AddEnvUser,
+			Access:  "superuser",
+		}}}
+
+	result, err := s.client.ShareEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.ErrorMatches, `could not share environment: environment access "superuser" not valid`)
+
+	_, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
 func (s *serverSuite) TestShareEnvironmentAddRemoteUser(c *gc.C) {
@@ -1239,6 +1298,19 @@ func (s *clientSuite) TestClientServiceExpose(c *gc.C) {
 	}
 }
 
+func (s *clientSuite) TestClientServiceExposeFrom(c *gc.C) {
+	svc := s.AddTestingService(c, "dummy-service", s.AddTestingCharm(c, "dummy"))
+	err := s.APIState.Client().ServiceExposeFrom("dummy-service", []string{"10.1.2.3/8"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.IsExposed(), jc.IsTrue)
+	c.Assert(svc.ExposedCIDRs(), jc.DeepEquals, []string{"10.0.0.0/8"})
+
+	err = s.APIState.Client().ServiceExposeFrom("dummy-service", []string{"bad"})
+	c.Assert(err, gc.ErrorMatches, `invalid CIDR "bad"`)
+}
+
 func (s *clientSuite) setupServiceExpose(c *gc.C) {
 	charm := s.AddTestingCharm(c, "dummy")
 	serviceNames := []string{"dummy-service", "exposed-service"}
@@ -2233,6 +2305,31 @@ func (s *clientRepoSuite) TestBlockServiceSetCharmForce(c *gc.C) {
 	s.assertServiceSetCharm(c, true)
 }
 
+func (s *clientRepoSuite) TestClientServiceRollbackCharm(c *gc.C) {
+	s.setupServiceSetCharm(c)
+	s.assertServiceSetCharm(c, false)
+
+	// Rolling back is not blocked, like a forced upgrade.
+	s.BlockAllChanges(c, "TestClientServiceRollbackCharm")
+	curl, err := s.APIState.Client().ServiceRollbackCharm("service")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(curl.String(), gc.Equals, "cs:precise/dummy-0")
+
+	service, err := s.State.Service("service")
+	c.Assert(err, jc.ErrorIsNil)
+	charm, force, err := service.Charm()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(charm.URL().String(), gc.Equals, "cs:precise/dummy-0")
+	c.Assert(force, jc.IsTrue)
+	c.Assert(service.PreviousCharmURL().String(), gc.Equals, "cs:precise/wordpress-3")
+}
+
+func (s *clientRepoSuite) TestClientServiceRollbackCharmNoPrevious(c *gc.C) {
+	s.setupServiceSetCharm(c)
+	_, err := s.APIState.Client().ServiceRollbackCharm("service")
+	c.Assert(err, gc.ErrorMatches, `cannot roll back charm of service "service": previous charm not found`)
+}
+
 func (s *clientSuite) TestClientServiceSetCharmInvalidService(c *gc.C) {
 	err := s.APIState.Client().ServiceSetCharm(
 		"badservice", "cs:precise/wordpress-3", true,
@@ -2710,6 +2807,20 @@ func (s *serverSuite) TestClientEnvironmentGet(c *gc.C) {
 	c.Assert(result.Config, gc.DeepEquals, envConfig.AllAttrs())
 }
 
+func (s *serverSuite) TestClientEnvironmentGetMasksBackupSecrets(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"backup-target":        "s3",
+		"backup-s3-bucket":     "juju-backups",
+		"backup-s3-access-key": "access",
+		"backup-s3-secret-key": "secret",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := s.client.EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Config["backup-s3-secret-key"], gc.Equals, "not available")
+	c.Assert(result.Config["backup-s3-access-key"], gc.Equals, "access")
+}
+
 func (s *serverSuite) assertEnvValue(c *gc.C, key string, expected interface{}) {
 	envConfig, err := s.State.EnvironConfig()
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,56 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// ServiceConfigHistory returns the recorded changes to a service's
+// config settings, ordered from oldest to newest.
+func (c *Client) ServiceConfigHistory(args params.ServiceGet) (params.ServiceConfigHistoryResults, error) {
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return params.ServiceConfigHistoryResults{}, err
+	}
+	history, err := service.ConfigHistory()
+	if err != nil {
+		return params.ServiceConfigHistoryResults{}, errors.Trace(err)
+	}
+	results := params.ServiceConfigHistoryResults{
+		Revisions: make([]params.ServiceConfigRevision, len(history)),
+	}
+	for i, rev := range history {
+		changes := make([]params.ServiceConfigChange, len(rev.Changes))
+		for j, change := range rev.Changes {
+			changes[j] = params.ServiceConfigChange{
+				Key:      change.Key,
+				OldValue: change.OldValue,
+				NewValue: change.NewValue,
+			}
+		}
+		results.Revisions[i] = params.ServiceConfigRevision{
+			Revision: rev.Revision,
+			Time:     rev.Time,
+			User:     rev.User,
+			Changes:  changes,
+		}
+	}
+	return results, nil
+}
+
+// ServiceRevertConfig changes a service's config settings back to
+// those recorded in the given revision of its config history.
+func (c *Client) ServiceRevertConfig(args params.ServiceRevertConfig) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return err
+	}
+	return service.RevertConfigSettings(args.Revision, c.api.auth.GetAuthTag().String())
+}
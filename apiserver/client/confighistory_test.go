@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type configHistorySuite struct {
+	baseSuite
+	dummy *state.Service
+}
+
+var _ = gc.Suite(&configHistorySuite{})
+
+func (s *configHistorySuite) SetUpTest(c *gc.C) {
+	s.baseSuite.SetUpTest(c)
+	s.dummy = s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+}
+
+func (s *configHistorySuite) TestServiceConfigHistory(c *gc.C) {
+	client := s.APIState.Client()
+	err := client.ServiceSet("dummy", map[string]string{"title": "foobar"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.ServiceUnset("dummy", []string{"title"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := client.ServiceConfigHistory("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 3)
+	for i, rev := range history {
+		c.Assert(rev.Revision, gc.Equals, i+1)
+		c.Assert(rev.User, gc.Equals, s.AdminUserTag(c).String())
+		c.Assert(rev.Time.IsZero(), jc.IsFalse)
+	}
+	c.Assert(history[0].Changes, gc.HasLen, 0)
+	c.Assert(history[1].Changes, jc.DeepEquals, []params.ServiceConfigChange{
+		{Key: "title", NewValue: "foobar"},
+	})
+	c.Assert(history[2].Changes, jc.DeepEquals, []params.ServiceConfigChange{
+		{Key: "title", OldValue: "foobar"},
+	})
+}
+
+func (s *configHistorySuite) TestServiceConfigHistoryUnknownService(c *gc.C) {
+	_, err := s.APIState.Client().ServiceConfigHistory("unknown")
+	c.Assert(err, gc.ErrorMatches, `service "unknown" not found`)
+}
+
+func (s *configHistorySuite) TestServiceRevertConfig(c *gc.C) {
+	client := s.APIState.Client()
+	err := client.ServiceSet("dummy", map[string]string{"title": "foobar"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.ServiceSet("dummy", map[string]string{"title": "baz", "outlook": "fine"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = client.ServiceRevertConfig("dummy", 2)
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := s.dummy.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, charm.Settings{"title": "foobar"})
+
+	history, err := s.dummy.ConfigHistory()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 4)
+	c.Assert(history[3].User, gc.Equals, s.AdminUserTag(c).String())
+}
+
+func (s *configHistorySuite) TestServiceRevertConfigUnknownRevision(c *gc.C) {
+	err := s.APIState.Client().ServiceRevertConfig("dummy", 42)
+	c.Assert(err, gc.ErrorMatches, `cannot revert service "dummy" to config revision 42: config revision 42 of service "dummy" not found`)
+}
+
+func (s *configHistorySuite) TestBlockChangesServiceRevertConfig(c *gc.C) {
+	err := s.APIState.Client().ServiceSet("dummy", map[string]string{"title": "foobar"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.BlockAllChanges(c, "TestBlockChangesServiceRevertConfig")
+	err = s.APIState.Client().ServiceRevertConfig("dummy", 1)
+	s.AssertBlocked(c, err, "TestBlockChangesServiceRevertConfig")
+}
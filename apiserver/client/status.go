@@ -49,61 +49,113 @@ func (s sortableStatuses) Less(i, j int) bool {
 	return s[i].Since.Before(*s[j].Since)
 }
 
-// TODO(perrito666) this client method requires more testing, only its parts are unittested.
 // UnitStatusHistory returns a slice of past statuses for a given unit.
+// It is retained for older clients; StatusHistory supersedes it.
 func (c *Client) UnitStatusHistory(args params.StatusHistory) (api.UnitStatusHistory, error) {
-	size := args.Size - 1
-	if size < 1 {
+	switch args.Kind {
+	case params.KindCombined, params.KindAgent, params.KindWorkload:
+	default:
+		return api.UnitStatusHistory{}, errors.NotValidf("unit status history kind %q", args.Kind)
+	}
+	return c.StatusHistory(args)
+}
+
+// StatusHistory returns a slice of past statuses for the unit, machine
+// or service named in args, restricted to at most args.Size of the most
+// recent statuses within the time range described by args.
+func (c *Client) StatusHistory(args params.StatusHistory) (api.UnitStatusHistory, error) {
+	if args.Size < 1 {
 		return api.UnitStatusHistory{}, errors.Errorf("invalid history size: %d", args.Size)
 	}
-	unit, err := c.api.state.Unit(args.Name)
-	if err != nil {
-		return api.UnitStatusHistory{}, errors.Trace(err)
+	if args.Since != nil && args.Until != nil && args.Until.Before(*args.Since) {
+		return api.UnitStatusHistory{}, errors.New("end of time range is before its start")
 	}
-	statuses := api.UnitStatusHistory{}
-	if args.Kind == params.KindCombined || args.Kind == params.KindWorkload {
-		unitStatuses, err := unit.StatusHistory(size)
+	filter := state.StatusHistoryFilter{
+		Size:  args.Size,
+		Since: args.Since,
+		Until: args.Until,
+	}
+	var entities []historyEntity
+	switch args.Kind {
+	case params.KindCombined, params.KindAgent, params.KindWorkload:
+		unit, err := c.api.state.Unit(args.Name)
 		if err != nil {
 			return api.UnitStatusHistory{}, errors.Trace(err)
 		}
-
-		current, err := unit.Status()
+		if args.Kind == params.KindCombined || args.Kind == params.KindWorkload {
+			entities = append(entities, historyEntity{unit, params.KindWorkload})
+		}
+		if args.Kind == params.KindCombined || args.Kind == params.KindAgent {
+			agent, ok := unit.Agent().(*state.UnitAgent)
+			if !ok {
+				return api.UnitStatusHistory{}, errors.Errorf("cannot obtain agent for %q", args.Name)
+			}
+			entities = append(entities, historyEntity{agent, params.KindAgent})
+		}
+	case params.KindMachine:
+		machine, err := c.api.state.Machine(args.Name)
 		if err != nil {
 			return api.UnitStatusHistory{}, errors.Trace(err)
 		}
-		unitStatuses = append(unitStatuses, current)
-
-		statuses.Statuses = append(statuses.Statuses, agentStatusFromStatusInfo(unitStatuses, params.KindWorkload)...)
-	}
-	if args.Kind == params.KindCombined || args.Kind == params.KindAgent {
-		agentEntity := unit.Agent()
-		agent, ok := agentEntity.(*state.UnitAgent)
-		if !ok {
-			return api.UnitStatusHistory{}, errors.Errorf("cannot obtain agent for %q", args.Name)
-		}
-		agentStatuses, err := agent.StatusHistory(size)
+		entities = append(entities, historyEntity{machine, params.KindMachine})
+	case params.KindService:
+		service, err := c.api.state.Service(args.Name)
 		if err != nil {
 			return api.UnitStatusHistory{}, errors.Trace(err)
 		}
+		entities = append(entities, historyEntity{service, params.KindService})
+	default:
+		return api.UnitStatusHistory{}, errors.NotValidf("status history kind %q", args.Kind)
+	}
 
-		current, err := agent.Status()
+	statuses := api.UnitStatusHistory{}
+	for _, entity := range entities {
+		history, err := entity.history(filter)
 		if err != nil {
 			return api.UnitStatusHistory{}, errors.Trace(err)
 		}
-		agentStatuses = append(agentStatuses, current)
-
-		statuses.Statuses = append(statuses.Statuses, agentStatusFromStatusInfo(agentStatuses, params.KindAgent)...)
+		statuses.Statuses = append(statuses.Statuses, history...)
 	}
+	// Status times are only recorded to the second, so a stable sort
+	// is needed to keep each entity's statuses in the order they were
+	// set.
+	sort.Stable(sortableStatuses(statuses.Statuses))
+	if len(statuses.Statuses) > args.Size {
+		statuses.Statuses = statuses.Statuses[len(statuses.Statuses)-args.Size:]
+	}
+	return statuses, nil
+}
 
-	sort.Sort(sortableStatuses(statuses.Statuses))
-	if args.Kind == params.KindCombined {
-
-		if len(statuses.Statuses) > args.Size {
-			statuses.Statuses = statuses.Statuses[len(statuses.Statuses)-args.Size:]
-		}
+// historyEntity is an entity whose status history can be reported,
+// along with the kind reported for its statuses.
+type historyEntity struct {
+	entity interface {
+		state.StatusGetter
+		state.StatusHistoryGetter
+	}
+	kind params.HistoryKind
+}
 
+// history returns, oldest first, the past statuses of the entity that
+// match the filter, together with its current status if that matches
+// too.
+func (e historyEntity) history(filter state.StatusHistoryFilter) ([]api.AgentStatus, error) {
+	history, err := e.entity.StatusHistory(filter)
+	if err != nil {
+		return nil, errors.Trace(err)
 	}
-	return statuses, nil
+	statuses := make([]state.StatusInfo, len(history))
+	for i, info := range history {
+		statuses[len(history)-1-i] = info
+	}
+	current, err := e.entity.Status()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if filter.Matches(current) {
+		statuses = append(statuses, current)
+	}
+	return agentStatusFromStatusInfo(statuses, e.kind), nil
 }
 
 // FullStatus gives the information needed for juju status over the api
@@ -4,11 +4,14 @@
 package client_test
 
 import (
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
@@ -62,6 +65,94 @@ func (s *statusSuite) TestLegacyStatus(c *gc.C) {
 	c.Check(resultMachine.InstanceId, gc.Equals, instanceId)
 }
 
+func (s *statusSuite) TestMachineStatusHistory(c *gc.C) {
+	machine := s.addMachine(c)
+	err := machine.SetStatus(state.StatusStarted, "first", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetStatus(state.StatusError, "second", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.APIState.Client().StatusHistory(params.StatusHistory{
+		Kind: params.KindMachine,
+		Name: machine.Id(),
+		Size: 2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history.Statuses, gc.HasLen, 2)
+	c.Check(history.Statuses[0].Status, gc.Equals, params.StatusStarted)
+	c.Check(history.Statuses[0].Info, gc.Equals, "first")
+	c.Check(history.Statuses[0].Kind, gc.Equals, params.KindMachine)
+	c.Check(history.Statuses[1].Status, gc.Equals, params.StatusError)
+	c.Check(history.Statuses[1].Info, gc.Equals, "second")
+}
+
+func (s *statusSuite) TestStatusHistoryTimeRange(c *gc.C) {
+	machine := s.addMachine(c)
+	err := machine.SetStatus(state.StatusStarted, "first", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	current, err := machine.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	before := current.Since.Add(-time.Hour)
+	after := current.Since.Add(time.Hour)
+
+	for i, test := range []struct {
+		since    *time.Time
+		until    *time.Time
+		expected int
+	}{
+		{since: &before, until: &after, expected: 2},
+		{since: &after, expected: 0},
+		{until: &before, expected: 0},
+	} {
+		c.Logf("test %d", i)
+		history, err := s.APIState.Client().StatusHistory(params.StatusHistory{
+			Kind:  params.KindMachine,
+			Name:  machine.Id(),
+			Size:  10,
+			Since: test.since,
+			Until: test.until,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(history.Statuses, gc.HasLen, test.expected)
+	}
+}
+
+func (s *statusSuite) TestServiceStatusHistory(c *gc.C) {
+	service := s.Factory.MakeService(c, nil)
+	err := service.SetStatus(state.StatusActive, "first", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = service.SetStatus(state.StatusBlocked, "second", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.APIState.Client().StatusHistory(params.StatusHistory{
+		Kind: params.KindService,
+		Name: service.Name(),
+		Size: 5,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history.Statuses, gc.HasLen, 2)
+	c.Check(history.Statuses[0].Info, gc.Equals, "first")
+	c.Check(history.Statuses[1].Info, gc.Equals, "second")
+	c.Check(history.Statuses[1].Kind, gc.Equals, params.KindService)
+}
+
+func (s *statusSuite) TestStatusHistoryInvalidArgs(c *gc.C) {
+	client := s.APIState.Client()
+	_, err := client.StatusHistory(params.StatusHistory{
+		Kind: params.KindMachine,
+		Name: "0",
+	})
+	c.Assert(err, gc.ErrorMatches, "invalid history size: 0")
+	_, err = client.StatusHistory(params.StatusHistory{
+		Kind: params.HistoryKind("bogus"),
+		Name: "0",
+		Size: 1,
+	})
+	c.Assert(err, gc.ErrorMatches, `status history kind "bogus" not valid`)
+	_, err = client.UnitStatusHistory(params.KindMachine, "0", 1)
+	c.Assert(err, gc.ErrorMatches, `unit status history kind "machine" not valid`)
+}
+
 var _ = gc.Suite(&statusUnitTestSuite{})
 
 type statusUnitTestSuite struct {
@@ -6,6 +6,7 @@ package common
 import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/watcher"
 )
@@ -56,11 +57,11 @@ func (e *EnvironWatcher) WatchForEnvironConfigChanges() (params.NotifyWatchResul
 func (e *EnvironWatcher) EnvironConfig() (params.EnvironConfigResult, error) {
 	result := params.EnvironConfigResult{}
 
-	config, err := e.st.EnvironConfig()
+	cfg, err := e.st.EnvironConfig()
 	if err != nil {
 		return result, err
 	}
-	allAttrs := config.AllAttrs()
+	allAttrs := cfg.AllAttrs()
 
 	if !e.authorizer.AuthEnvironManager() {
 		// Mask out any secrets in the environment configuration
@@ -70,15 +71,36 @@ func (e *EnvironWatcher) EnvironConfig() (params.EnvironConfigResult, error) {
 		// Delete the code below and mark the bug as fixed,
 		// once it's live tested on MAAS and 1.16 compatibility
 		// is dropped.
-		provider, err := environs.Provider(config.Type())
+		provider, err := environs.Provider(cfg.Type())
 		if err != nil {
 			return result, err
 		}
-		secretAttrs, err := provider.SecretAttrs(config)
+		secretAttrs, err := provider.SecretAttrs(cfg)
 		for k := range secretAttrs {
 			allAttrs[k] = "not available"
 		}
+		// Only state servers take backups, so nobody else
+		// needs the backup credentials.
+		MaskBackupSecrets(allAttrs)
 	}
 	result.Config = allAttrs
 	return result, nil
 }
+
+// backupSecretAttrs holds the environment settings that carry
+// credentials for the backup target.
+var backupSecretAttrs = []string{
+	config.BackupS3SecretKeyKey,
+	config.BackupSFTPPrivateKeyKey,
+}
+
+// MaskBackupSecrets replaces the values of any backup credentials
+// in attrs with a placeholder of the same type, so the configuration
+// still passes validation.
+func MaskBackupSecrets(attrs map[string]interface{}) {
+	for _, key := range backupSecretAttrs {
+		if _, ok := attrs[key]; ok {
+			attrs[key] = "not available"
+		}
+	}
+}
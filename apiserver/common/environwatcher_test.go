@@ -122,6 +122,53 @@ func (*environWatcherSuite) TestEnvironConfigMaskedSecrets(c *gc.C) {
 	c.Check(map[string]interface{}(result.Config), jc.DeepEquals, testingEnvConfig.AllAttrs())
 }
 
+func (*environWatcherSuite) TestEnvironConfigMaskedBackupSecrets(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag:            names.NewMachineTag("0"),
+		EnvironManager: false,
+	}
+	testingEnvConfig, err := testingEnvConfig(c).Apply(map[string]interface{}{
+		"backup-target":        "s3",
+		"backup-s3-bucket":     "juju-backups",
+		"backup-s3-access-key": "access",
+		"backup-s3-secret-key": "secret",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	e := common.NewEnvironWatcher(
+		&fakeEnvironAccessor{envConfig: testingEnvConfig},
+		nil,
+		authorizer,
+	)
+	result, err := e.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Config["backup-s3-secret-key"], gc.Equals, "not available")
+	c.Check(result.Config["backup-s3-access-key"], gc.Equals, "access")
+	_, found := result.Config["backup-sftp-private-key"]
+	c.Check(found, jc.IsFalse)
+}
+
+func (*environWatcherSuite) TestEnvironConfigBackupSecretsForEnvironManager(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag:            names.NewMachineTag("0"),
+		EnvironManager: true,
+	}
+	testingEnvConfig, err := testingEnvConfig(c).Apply(map[string]interface{}{
+		"backup-target":        "s3",
+		"backup-s3-bucket":     "juju-backups",
+		"backup-s3-access-key": "access",
+		"backup-s3-secret-key": "secret",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	e := common.NewEnvironWatcher(
+		&fakeEnvironAccessor{envConfig: testingEnvConfig},
+		nil,
+		authorizer,
+	)
+	result, err := e.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Config["backup-s3-secret-key"], gc.Equals, "secret")
+}
+
 func testingEnvConfig(c *gc.C) *config.Config {
 	cfg, err := config.New(config.NoDefaults, dummy.SampleConfig())
 	c.Assert(err, jc.ErrorIsNil)
@@ -75,6 +75,8 @@ var (
 	ErrBadRequest         = stderrors.New("invalid request")
 	ErrTryAgain           = stderrors.New("try again")
 	ErrActionNotAvailable = stderrors.New("action no longer available")
+	ErrRateLimited        = stderrors.New("request rate limit exceeded")
+	ErrTooManyWatchers    = stderrors.New("too many watchers")
 
 	ErrOperationBlocked = func(msg string) *params.Error {
 		if msg == "" {
@@ -102,6 +104,8 @@ var singletonErrorCodes = map[error]string{
 	ErrStoppedWatcher:            params.CodeStopped,
 	ErrTryAgain:                  params.CodeTryAgain,
 	ErrActionNotAvailable:        params.CodeActionNotAvailable,
+	ErrRateLimited:               params.CodeRateLimited,
+	ErrTooManyWatchers:           params.CodeTooManyWatchers,
 }
 
 func singletonCode(err error) (string, bool) {
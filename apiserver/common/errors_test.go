@@ -105,6 +105,14 @@ var errorTransformTests = []struct {
 	err:        common.ErrTryAgain,
 	code:       params.CodeTryAgain,
 	helperFunc: params.IsCodeTryAgain,
+}, {
+	err:        common.ErrRateLimited,
+	code:       params.CodeRateLimited,
+	helperFunc: params.IsCodeRateLimited,
+}, {
+	err:        common.ErrTooManyWatchers,
+	code:       params.CodeTooManyWatchers,
+	helperFunc: params.IsCodeTooManyWatchers,
 }, {
 	err:        state.UpgradeInProgressError,
 	code:       params.CodeUpgradeInProgress,
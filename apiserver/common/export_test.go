@@ -12,6 +12,7 @@ var (
 	NilFacadeRecord      = facadeRecord{}
 	EnvtoolsFindTools    = &envtoolsFindTools
 	SendMetrics          = &sendMetrics
+	APIWatchers          = apiWatchers
 )
 
 type Patcher interface {
@@ -7,6 +7,14 @@ import (
 	"fmt"
 	"strconv"
 	"sync"
+
+	"github.com/juju/juju/instrumentation"
+	"github.com/juju/juju/state"
+)
+
+var apiWatchers = instrumentation.NewGauge(
+	"juju_apiserver_watchers",
+	"Number of watchers held open on behalf of API clients.",
 )
 
 // Resource represents any resource that should be cleaned up when an
@@ -26,6 +34,8 @@ type Resources struct {
 	// The stack is used to control the order of destruction.
 	// last registered, first stopped.
 	stack []string
+	// watchers holds the number of resources which are watchers.
+	watchers int
 }
 
 func NewResources() *Resources {
@@ -52,6 +62,10 @@ func (rs *Resources) Register(r Resource) string {
 	id := strconv.FormatUint(rs.maxId, 10)
 	rs.resources[id] = r
 	rs.stack = append(rs.stack, id)
+	if isWatcher(r) {
+		rs.watchers++
+		apiWatchers.Inc()
+	}
 	logger.Tracef("registered unnamed resource: %s", id)
 	return id
 }
@@ -74,6 +88,10 @@ func (rs *Resources) RegisterNamed(name string, r Resource) error {
 	}
 	rs.resources[name] = r
 	rs.stack = append(rs.stack, name)
+	if isWatcher(r) {
+		rs.watchers++
+		apiWatchers.Inc()
+	}
 	logger.Tracef("registered named resource: %s", name)
 	return nil
 }
@@ -97,6 +115,14 @@ func (rs *Resources) Stop(id string) error {
 	err := r.Stop()
 	rs.mu.Lock()
 	defer rs.mu.Unlock()
+	if _, ok := rs.resources[id]; !ok {
+		// Stopped concurrently, and already unregistered.
+		return err
+	}
+	if isWatcher(r) {
+		rs.watchers--
+		apiWatchers.Dec()
+	}
 	delete(rs.resources, id)
 	for pos := 0; pos < len(rs.stack); pos++ {
 		if rs.stack[pos] == id {
@@ -118,9 +144,13 @@ func (rs *Resources) StopAll() {
 		if err := r.Stop(); err != nil {
 			logger.Errorf("error stopping %T resource: %v", r, err)
 		}
+		if isWatcher(r) {
+			apiWatchers.Dec()
+		}
 	}
 	rs.resources = make(map[string]Resource)
 	rs.stack = nil
+	rs.watchers = 0
 }
 
 // Count returns the number of resources currently held.
@@ -130,6 +160,23 @@ func (rs *Resources) Count() int {
 	return len(rs.resources)
 }
 
+// WatcherCount returns the number of watchers currently held.
+func (rs *Resources) WatcherCount() int {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.watchers
+}
+
+// isWatcher returns whether the resource watches the state on behalf
+// of a client.
+func isWatcher(r Resource) bool {
+	switch r.(type) {
+	case state.Watcher, *state.Multiwatcher:
+		return true
+	}
+	return false
+}
+
 // StringResource is just a regular 'string' that matches the Resource
 // interface.
 type StringResource string
@@ -7,6 +7,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/state"
 )
 
 type resourceSuite struct{}
@@ -22,6 +23,15 @@ func (r *fakeResource) Stop() error {
 	return nil
 }
 
+type fakeWatcher struct {
+	state.Watcher
+	fakeResource
+}
+
+func (w *fakeWatcher) Stop() error {
+	return w.fakeResource.Stop()
+}
+
 func (resourceSuite) TestRegisterGetCount(c *gc.C) {
 	rs := common.NewResources()
 	r1 := &fakeResource{}
@@ -149,6 +159,23 @@ func (resourceSuite) TestStopAll(c *gc.C) {
 	c.Assert(rs.Count(), gc.Equals, 0)
 }
 
+func (resourceSuite) TestWatchersCounted(c *gc.C) {
+	before := common.APIWatchers.Value()
+	rs := common.NewResources()
+	rs.Register(&fakeResource{})
+	id := rs.Register(&fakeWatcher{})
+	rs.Register(&fakeWatcher{})
+	c.Assert(rs.WatcherCount(), gc.Equals, 2)
+	c.Assert(common.APIWatchers.Value(), gc.Equals, before+2)
+
+	rs.Stop(id)
+	c.Assert(rs.WatcherCount(), gc.Equals, 1)
+	c.Assert(common.APIWatchers.Value(), gc.Equals, before+1)
+	rs.StopAll()
+	c.Assert(rs.WatcherCount(), gc.Equals, 0)
+	c.Assert(common.APIWatchers.Value(), gc.Equals, before)
+}
+
 func (resourceSuite) TestStringResource(c *gc.C) {
 	rs := common.NewResources()
 	r1 := common.StringResource("foobar")
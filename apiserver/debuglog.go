@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -69,6 +70,11 @@ func newDebugLogHandler(
 //      - has no meaning if 'replay' is true
 //   level -> string one of [TRACE, DEBUG, INFO, WARNING, ERROR]
 //   replay -> string - one of [true, false], if true, start the file from the start
+//   startTime -> string - RFC 3339 time; only send lines recorded at or after it
+//      - implies replay
+//   endTime -> string - RFC 3339 time; only send lines recorded at or before it
+//      - the connection is closed once all such lines have been sent
+//   format -> string - one of [text, json]; json sends a params.LogRecord per line
 func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	server := websocket.Server{
 		Handler: func(conn *websocket.Conn) {
@@ -85,7 +91,7 @@ func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 				return
 			}
 			defer stateWrapper.cleanup()
-			if err := stateWrapper.authenticateUser(req); err != nil {
+			if err := stateWrapper.authenticateUser(req, state.EnvironmentReadAccess); err != nil {
 				socket.sendError(fmt.Errorf("auth failed: %v", err))
 				return
 			}
@@ -166,6 +172,15 @@ type debugLogParams struct {
 	excludeEntity []string
 	includeModule []string
 	excludeModule []string
+	startTime     time.Time
+	endTime       time.Time
+	format        params.DebugLogFormat // text if unset
+}
+
+// requiresDB returns whether the request can only be satisfied from
+// logs stored in the database rather than from all-machines.log.
+func (p *debugLogParams) requiresDB() bool {
+	return !p.startTime.IsZero() || !p.endTime.IsZero() || p.format == params.DebugLogFormatJSON
 }
 
 func readDebugLogParams(queryMap url.Values) (*debugLogParams, error) {
@@ -205,6 +220,36 @@ func readDebugLogParams(queryMap url.Values) (*debugLogParams, error) {
 		params.filterLevel = level
 	}
 
+	if value := queryMap.Get("startTime"); value != "" {
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, errors.Errorf("startTime value %q is not a valid time", value)
+		}
+		params.startTime = t
+		params.fromTheStart = true
+	}
+
+	if value := queryMap.Get("endTime"); value != "" {
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, errors.Errorf("endTime value %q is not a valid time", value)
+		}
+		params.endTime = t
+	}
+
+	if !params.endTime.IsZero() && params.endTime.Before(params.startTime) {
+		return nil, errors.Errorf("endTime %q is before startTime %q",
+			queryMap.Get("endTime"), queryMap.Get("startTime"))
+	}
+
+	if value := queryMap.Get("format"); value != "" {
+		format, err := parseDebugLogFormat(value)
+		if err != nil {
+			return nil, err
+		}
+		params.format = format
+	}
+
 	params.includeEntity = queryMap["includeEntity"]
 	params.excludeEntity = queryMap["excludeEntity"]
 	params.includeModule = queryMap["includeModule"]
@@ -212,3 +257,13 @@ func readDebugLogParams(queryMap url.Values) (*debugLogParams, error) {
 
 	return params, nil
 }
+
+// parseDebugLogFormat returns the log format named by value.
+func parseDebugLogFormat(value string) (params.DebugLogFormat, error) {
+	switch format := params.DebugLogFormat(value); format {
+	case params.DebugLogFormatText, params.DebugLogFormatJSON:
+		return format, nil
+	}
+	return "", errors.Errorf("format value %q is not one of %q, %q",
+		value, params.DebugLogFormatText, params.DebugLogFormatJSON)
+}
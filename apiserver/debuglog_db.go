@@ -4,12 +4,14 @@
 package apiserver
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/juju/errors"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
 )
 
@@ -42,8 +44,11 @@ func handleDebugLogDBRequest(
 				return errors.Annotate(tailer.Err(), "tailer stopped")
 			}
 
-			line := formatLogRecord(rec)
-			_, err := socket.Write([]byte(line))
+			line, err := formatLogRecordAs(rec, reqParams.format)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			_, err = socket.Write([]byte(line))
 			if err != nil {
 				return errors.Annotate(err, "sending failed")
 			}
@@ -70,9 +75,36 @@ func makeLogTailerParams(reqParams *debugLogParams) *state.LogTailerParams {
 	if reqParams.fromTheStart {
 		params.InitialLines = 0
 	}
+	if !reqParams.startTime.IsZero() {
+		params.StartTime = reqParams.startTime
+	}
+	if !reqParams.endTime.IsZero() {
+		// No more logs can match once the end time has passed, so
+		// there's no point waiting for them.
+		params.EndTime = reqParams.endTime
+		params.NoTail = true
+	}
 	return params
 }
 
+func formatLogRecordAs(r *state.LogRecord, format params.DebugLogFormat) (string, error) {
+	if format != params.DebugLogFormatJSON {
+		return formatLogRecord(r), nil
+	}
+	data, err := json.Marshal(&params.LogRecord{
+		Time:     r.Time.UTC(),
+		Entity:   r.Entity,
+		Module:   r.Module,
+		Location: r.Location,
+		Level:    r.Level.String(),
+		Message:  r.Message,
+	})
+	if err != nil {
+		return "", errors.Annotate(err, "cannot marshal log record")
+	}
+	return string(data) + "\n", nil
+}
+
 func formatLogRecord(r *state.LogRecord) string {
 	return fmt.Sprintf("%s: %s %s %s %s %s\n",
 		r.Entity,
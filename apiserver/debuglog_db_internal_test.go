@@ -11,6 +11,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 )
@@ -86,6 +87,61 @@ func (s *debugLogDBIntSuite) TestParamConversionReplay(c *gc.C) {
 	c.Assert(called, jc.IsTrue)
 }
 
+func (s *debugLogDBIntSuite) TestParamConversionTimeRange(c *gc.C) {
+	start := time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	reqParams := &debugLogParams{
+		fromTheStart: true,
+		startTime:    start,
+		endTime:      end,
+	}
+
+	called := false
+	s.PatchValue(&newLogTailer, func(_ state.LoggingState, params *state.LogTailerParams) state.LogTailer {
+		called = true
+
+		c.Assert(params.StartTime, gc.Equals, start)
+		c.Assert(params.EndTime, gc.Equals, end)
+		c.Assert(params.NoTail, jc.IsTrue)
+		c.Assert(params.InitialLines, gc.Equals, 0)
+
+		return newFakeLogTailer()
+	})
+
+	stop := make(chan struct{})
+	close(stop) // Stop the request immediately.
+	err := handleDebugLogDBRequest(nil, reqParams, s.sock, stop)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *debugLogDBIntSuite) TestJSONFormat(c *gc.C) {
+	tailer := newFakeLogTailer()
+	tailer.logsCh <- &state.LogRecord{
+		Time:     time.Date(2015, 6, 19, 15, 34, 37, 0, time.UTC),
+		Entity:   "machine-99",
+		Module:   "some.where",
+		Location: "code.go:42",
+		Level:    loggo.INFO,
+		Message:  "stuff happened",
+	}
+	close(tailer.logsCh)
+	s.PatchValue(&newLogTailer, func(_ state.LoggingState, params *state.LogTailerParams) state.LogTailer {
+		return tailer
+	})
+
+	done := s.runRequest(&debugLogParams{format: params.DebugLogFormatJSON}, nil)
+
+	s.assertOutput(c, []string{
+		"ok",
+		`{"timestamp":"2015-06-19T15:34:37Z","entity":"machine-99","module":"some.where",` +
+			`"location":"code.go:42","level":"INFO","message":"stuff happened"}` + "\n",
+	})
+
+	// The request finishes once the tailer has no more logs.
+	s.assertStops(c, done, tailer)
+}
+
 func (s *debugLogDBIntSuite) TestFullRequest(c *gc.C) {
 	// Set up a fake log tailer with a 2 log records ready to send.
 	tailer := newFakeLogTailer()
@@ -34,6 +34,11 @@ func (h *debugLogFileHandler) handle(
 	socket debugLogSocket,
 	stop <-chan struct{},
 ) error {
+	if params.requiresDB() {
+		err := fmt.Errorf("time ranges and JSON output require logs stored in the database")
+		socket.sendError(err)
+		return err
+	}
 	stream := newLogFileStream(params)
 
 	// Open log file.
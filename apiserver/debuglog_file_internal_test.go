@@ -16,6 +16,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/testing"
 )
 
@@ -352,6 +353,43 @@ func (s *debugLogFileIntSuite) TestParamErrors(c *gc.C) {
 
 	_, err = readDebugLogParams(url.Values{"level": []string{"foo"}})
 	c.Assert(err, gc.ErrorMatches, `level value "foo" is not one of "TRACE", "DEBUG", "INFO", "WARNING", "ERROR"`)
+
+	_, err = readDebugLogParams(url.Values{"startTime": []string{"foo"}})
+	c.Assert(err, gc.ErrorMatches, `startTime value "foo" is not a valid time`)
+
+	_, err = readDebugLogParams(url.Values{"endTime": []string{"foo"}})
+	c.Assert(err, gc.ErrorMatches, `endTime value "foo" is not a valid time`)
+
+	_, err = readDebugLogParams(url.Values{
+		"startTime": []string{"2015-07-01T12:00:00Z"},
+		"endTime":   []string{"2015-07-01T11:00:00Z"},
+	})
+	c.Assert(err, gc.ErrorMatches, `endTime "2015-07-01T11:00:00Z" is before startTime "2015-07-01T12:00:00Z"`)
+
+	_, err = readDebugLogParams(url.Values{"format": []string{"xml"}})
+	c.Assert(err, gc.ErrorMatches, `format value "xml" is not one of "text", "json"`)
+}
+
+func (s *debugLogFileIntSuite) TestTimeRangeAndFormatParams(c *gc.C) {
+	p, err := readDebugLogParams(url.Values{
+		"startTime": []string{"2015-07-01T12:00:00Z"},
+		"endTime":   []string{"2015-07-01T13:00:00.5Z"},
+		"format":    []string{"json"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.startTime, gc.Equals, time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC))
+	c.Assert(p.endTime, gc.Equals, time.Date(2015, 7, 1, 13, 0, 0, 500000000, time.UTC))
+	c.Assert(p.format, gc.Equals, params.DebugLogFormatJSON)
+	c.Assert(p.fromTheStart, jc.IsTrue)
+	c.Assert(p.requiresDB(), jc.IsTrue)
+}
+
+func (s *debugLogFileIntSuite) TestHandleRejectsDBOnlyParams(c *gc.C) {
+	handler := &debugLogFileHandler{logDir: c.MkDir()}
+	sock := newFakeDebugLogSocket()
+	err := handler.handle(nil, &debugLogParams{format: params.DebugLogFormatJSON}, sock, nil)
+	c.Assert(err, gc.ErrorMatches, "time ranges and JSON output require logs stored in the database")
+	c.Assert(<-sock.writes, gc.Equals, "err: time ranges and JSON output require logs stored in the database")
 }
 
 type agentMatchTest struct {
@@ -27,6 +27,8 @@ var (
 	ParseLogLine          = parseLogLine
 	AgentMatchesFilter    = agentMatchesFilter
 	NewLogTailer          = &newLogTailer
+	LoginRateLimited      = loginRateLimited
+	NewResources          = &newResources
 )
 
 func ApiHandlerWithEntity(entity state.Entity) *apiHandler {
@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The firewaller package implements the API interface used by the
+// firewaller worker. This file contains the API facade version 2.
+
+package firewaller
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Firewaller", 2, NewFirewallerAPIV2)
+}
+
+// FirewallerAPIV2 implements the API version 2, used by the
+// firewaller worker.
+type FirewallerAPIV2 struct {
+	*FirewallerAPI
+}
+
+// NewFirewallerAPIV2 creates a new server-side FirewallerAPI facade,
+// version 2.
+func NewFirewallerAPIV2(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*FirewallerAPIV2, error) {
+	baseAPI, err := NewFirewallerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &FirewallerAPIV2{baseAPI}, nil
+}
+
+// GetExposedCIDRs returns the CIDRs from which the open ports of each
+// given service may be accessed when it is exposed. No CIDRs are
+// returned for a service which may be accessed from any address.
+func (f *FirewallerAPIV2) GetExposedCIDRs(args params.Entities) (params.StringsResults, error) {
+	result := params.StringsResults{
+		Results: make([]params.StringsResult, len(args.Entities)),
+	}
+	canAccess, err := f.accessService()
+	if err != nil {
+		return params.StringsResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseServiceTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		service, err := f.getService(canAccess, tag)
+		if err == nil {
+			result.Results[i].Result = service.ExposedCIDRs()
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewaller_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/firewaller"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state"
+)
+
+type firewallerV2Suite struct {
+	firewallerBaseSuite
+
+	firewaller *firewaller.FirewallerAPIV2
+}
+
+var _ = gc.Suite(&firewallerV2Suite{})
+
+func (s *firewallerV2Suite) SetUpTest(c *gc.C) {
+	s.firewallerBaseSuite.setUpTest(c)
+
+	firewallerAPI, err := firewaller.NewFirewallerAPIV2(
+		s.State,
+		s.resources,
+		s.authorizer,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	s.firewaller = firewallerAPI
+}
+
+func (s *firewallerV2Suite) TestFirewallerFailsWithNonEnvironManagerUser(c *gc.C) {
+	constructor := func(st *state.State, res *common.Resources, auth common.Authorizer) error {
+		_, err := firewaller.NewFirewallerAPIV2(st, res, auth)
+		return err
+	}
+	s.testFirewallerFailsWithNonEnvironManagerUser(c, constructor)
+}
+
+func (s *firewallerV2Suite) TestGetExposedCIDRsNotInV1(c *gc.C) {
+	facadeType, err := common.Facades.GetType("Firewaller", 1)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = rpcreflect.ObjTypeOf(facadeType).Method("GetExposedCIDRs")
+	c.Assert(err, gc.NotNil)
+}
+
+func (s *firewallerV2Suite) TestGetExposedCIDRs(c *gc.C) {
+	err := s.service.SetExposedFrom([]string{"10.0.0.0/8"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := addFakeEntities(params.Entities{Entities: []params.Entity{
+		{Tag: s.service.Tag().String()},
+	}})
+	result, err := s.firewaller.GetExposedCIDRs(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsResults{
+		Results: []params.StringsResult{
+			{Result: []string{"10.0.0.0/8"}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.NotFoundError(`service "bar"`)},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// No CIDRs are returned for a service exposed to any address.
+	err = s.service.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	args = params.Entities{Entities: []params.Entity{
+		{Tag: s.service.Tag().String()},
+	}}
+	result, err = s.firewaller.GetExposedCIDRs(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsResults{
+		Results: []params.StringsResult{{}},
+	})
+}
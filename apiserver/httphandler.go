@@ -48,6 +48,16 @@ func (h *httpHandler) authError(w http.ResponseWriter, sender errorSender) {
 	sender.sendError(w, http.StatusUnauthorized, "unauthorized")
 }
 
+// userAuthError sends a forbidden error if a user was authenticated but
+// lacks the access needed, and an unauthorized error otherwise.
+func (h *httpHandler) userAuthError(w http.ResponseWriter, sender errorSender, err error) {
+	if errors.Cause(err) == common.ErrPerm {
+		sender.sendError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	h.authError(w, sender)
+}
+
 func (h *httpHandler) validateEnvironUUID(r *http.Request) (*httpStateWrapper, error) {
 	envUUID := h.getEnvironUUID(r)
 	envState, needsClosing, err := validateEnvironUUID(validateArgs{
@@ -100,17 +110,28 @@ func (h *httpStateWrapper) authenticate(r *http.Request) (names.Tag, error) {
 	return tag, err
 }
 
-func (h *httpStateWrapper) authenticateUser(r *http.Request) error {
+// authenticateUser authenticates the request as coming from a user,
+// and returns common.ErrPerm if the user does not have at least the
+// given access to the environment.
+func (h *httpStateWrapper) authenticateUser(r *http.Request, access state.EnvironmentAccess) error {
 	tag, err := h.authenticate(r)
 	if err != nil {
 		return err
 	}
-	switch tag.(type) {
-	case names.UserTag:
-		return nil
-	default:
+	userTag, ok := tag.(names.UserTag)
+	if !ok {
 		return common.ErrBadCreds
 	}
+	envUser, err := h.state.EnvironmentUser(userTag)
+	if errors.IsNotFound(err) {
+		return common.ErrPerm
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if !envUser.Access().Includes(access) {
+		return common.ErrPerm
+	}
+	return nil
 }
 
 func (h *httpStateWrapper) authenticateAgent(r *http.Request) (names.Tag, error) {
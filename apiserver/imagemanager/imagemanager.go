@@ -76,11 +76,13 @@ func (api *ImageManagerAPI) ListImages(arg params.ImageFilterParams) (params.Lis
 	result.Result = make([]params.ImageMetadata, len(metadata))
 	for i, m := range metadata {
 		result.Result[i] = params.ImageMetadata{
-			Kind:    m.Kind,
-			Series:  m.Series,
-			Arch:    m.Arch,
-			URL:     m.SourceURL,
-			Created: m.Created,
+			Kind:     m.Kind,
+			Series:   m.Series,
+			Arch:     m.Arch,
+			URL:      m.SourceURL,
+			Created:  m.Created,
+			LastUsed: m.LastUsed,
+			Size:     m.Size,
 		}
 	}
 	return result, nil
@@ -89,10 +89,13 @@ func (s *imageManagerSuite) TestListAllImages(c *gc.C) {
 	result, err := s.imagemanager.ListImages(args)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(result.Result, gc.HasLen, 1)
+	c.Assert(result.Result[0].LastUsed.Before(result.Result[0].Created), jc.IsFalse)
 	dummyTime := time.Now()
 	result.Result[0].Created = dummyTime
+	result.Result[0].LastUsed = dummyTime
 	c.Assert(result.Result[0], gc.Equals, params.ImageMetadata{
-		Kind: "lxc", Arch: "amd64", Series: "trusty", URL: "http://lxc-trusty-amd64", Created: dummyTime,
+		Kind: "lxc", Arch: "amd64", Series: "trusty", URL: "http://lxc-trusty-amd64",
+		Created: dummyTime, LastUsed: dummyTime, Size: 5,
 	})
 }
 
@@ -110,10 +113,13 @@ func (s *imageManagerSuite) TestListImagesWithSingleFilter(c *gc.C) {
 	result, err := s.imagemanager.ListImages(args)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(result.Result, gc.HasLen, 1)
+	c.Assert(result.Result[0].LastUsed.Before(result.Result[0].Created), jc.IsFalse)
 	dummyTime := time.Now()
 	result.Result[0].Created = dummyTime
+	result.Result[0].LastUsed = dummyTime
 	c.Assert(result.Result[0], gc.Equals, params.ImageMetadata{
-		Kind: "lxc", Arch: "amd64", Series: "trusty", URL: "http://lxc-trusty-amd64", Created: dummyTime,
+		Kind: "lxc", Arch: "amd64", Series: "trusty", URL: "http://lxc-trusty-amd64",
+		Created: dummyTime, LastUsed: dummyTime, Size: 5,
 	})
 }
 
@@ -0,0 +1,163 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/juju/names"
+
+	"github.com/juju/juju/instrumentation"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state"
+)
+
+var (
+	apiRequests = instrumentation.NewCounter(
+		"juju_apiserver_requests_total",
+		"Number of API requests served.",
+		"facade", "method", "version",
+	)
+	apiRequestDuration = instrumentation.NewHistogram(
+		"juju_apiserver_request_duration_seconds",
+		"Time taken to serve API requests.",
+		instrumentation.DefaultBuckets,
+		"facade", "method", "version",
+	)
+	apiConnections = instrumentation.NewGauge(
+		"juju_apiserver_connections",
+		"Number of open API connections, by the kind of entity logged in.",
+		"kind",
+	)
+	loginRateLimited = instrumentation.NewCounter(
+		"juju_apiserver_login_rate_limited_total",
+		"Number of agent logins rejected because too many were in progress.",
+	)
+	apiRateLimited = instrumentation.NewCounter(
+		"juju_apiserver_rate_limited_total",
+		"Number of API requests rejected because they exceeded a request rate or watcher limit.",
+		"limit",
+	)
+)
+
+// unauthenticatedKind is the kind recorded for connections on which no
+// entity has yet logged in.
+const unauthenticatedKind = "unauthenticated"
+
+// connectionKind returns the kind recorded for connections on which
+// the entity with the given tag has logged in.
+func connectionKind(tag string) string {
+	kind, err := names.TagKind(tag)
+	if err != nil {
+		return unauthenticatedKind
+	}
+	return kind
+}
+
+// metricsNotifier is an rpc.RequestNotifier which records the number
+// and latency of the requests served on a connection, passing every
+// notification on to next, if set.
+type metricsNotifier struct {
+	next rpc.RequestNotifier
+
+	// known holds the ids of requests which were bound to a
+	// method. Requests for unknown facades and methods are not
+	// recorded, so that clients cannot create arbitrary numbers of
+	// series.
+	mu    sync.Mutex
+	known map[uint64]bool
+}
+
+func newMetricsNotifier(next rpc.RequestNotifier) *metricsNotifier {
+	return &metricsNotifier{
+		next:  next,
+		known: make(map[uint64]bool),
+	}
+}
+
+// ServerRequest implements rpc.RequestNotifier.
+func (n *metricsNotifier) ServerRequest(hdr *rpc.Header, body interface{}) {
+	if body != nil {
+		n.mu.Lock()
+		n.known[hdr.RequestId] = true
+		n.mu.Unlock()
+	}
+	if n.next != nil {
+		n.next.ServerRequest(hdr, body)
+	}
+}
+
+// ServerReply implements rpc.RequestNotifier.
+func (n *metricsNotifier) ServerReply(req rpc.Request, hdr *rpc.Header, body interface{}, timeSpent time.Duration) {
+	n.mu.Lock()
+	known := n.known[hdr.RequestId]
+	delete(n.known, hdr.RequestId)
+	n.mu.Unlock()
+	if known {
+		version := strconv.Itoa(req.Version)
+		apiRequests.Inc(req.Type, req.Action, version)
+		apiRequestDuration.Observe(timeSpent.Seconds(), req.Type, req.Action, version)
+	}
+	if n.next != nil {
+		n.next.ServerReply(req, hdr, body, timeSpent)
+	}
+}
+
+// ClientRequest implements rpc.RequestNotifier.
+func (n *metricsNotifier) ClientRequest(hdr *rpc.Header, body interface{}) {
+	if n.next != nil {
+		n.next.ClientRequest(hdr, body)
+	}
+}
+
+// ClientReply implements rpc.RequestNotifier.
+func (n *metricsNotifier) ClientReply(req rpc.Request, hdr *rpc.Header, body interface{}) {
+	if n.next != nil {
+		n.next.ClientReply(req, hdr, body)
+	}
+}
+
+// metricsHandler serves the metrics of the API server, and of the
+// agent running it, in the Prometheus text exposition format.
+type metricsHandler struct {
+	httpHandler
+}
+
+func (h *metricsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	stateWrapper, err := h.validateEnvironUUID(req)
+	if err != nil {
+		h.sendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	defer stateWrapper.cleanup()
+
+	if err := stateWrapper.authenticateUser(req, state.EnvironmentReadAccess); err != nil {
+		h.userAuthError(w, h, err)
+		return
+	}
+	if req.Method != "GET" {
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("unsupported method: %q", req.Method))
+		return
+	}
+	var buf bytes.Buffer
+	if err := instrumentation.Default.WriteText(&buf); err != nil {
+		h.sendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", instrumentation.ContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// sendError sends a plain text error response.
+func (h *metricsHandler) sendError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(statusCode)
+	fmt.Fprintln(w, message)
+}
@@ -0,0 +1,85 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instrumentation"
+	"github.com/juju/juju/state"
+)
+
+type metricsSuite struct {
+	userAuthHttpSuite
+}
+
+var _ = gc.Suite(&metricsSuite{})
+
+func (s *metricsSuite) metricsURL(c *gc.C) string {
+	uri := s.baseURL(c)
+	uri.Path = "/metrics"
+	return uri.String()
+}
+
+func (s *metricsSuite) assertPlainTextError(c *gc.C, resp *http.Response, statusCode int, message string) {
+	body := assertResponse(c, resp, statusCode, "text/plain; charset=utf-8")
+	c.Check(string(body), gc.Equals, message+"\n")
+}
+
+func (s *metricsSuite) TestRequiresAuth(c *gc.C) {
+	resp, err := s.sendRequest(c, "", "", "GET", s.metricsURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPlainTextError(c, resp, http.StatusUnauthorized, "unauthorized")
+}
+
+func (s *metricsSuite) TestRequiresUser(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProvisioned("foo", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	password, err := utils.RandomPassword()
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetPassword(password)
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp, err := s.sendRequest(c, machine.Tag().String(), password, "GET", s.metricsURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPlainTextError(c, resp, http.StatusUnauthorized, "unauthorized")
+}
+
+func (s *metricsSuite) TestInvalidMethod(c *gc.C) {
+	resp, err := s.authRequest(c, "POST", s.metricsURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPlainTextError(c, resp, http.StatusMethodNotAllowed, `unsupported method: "POST"`)
+}
+
+func (s *metricsSuite) TestMetrics(c *gc.C) {
+	// Make sure at least one request has been served.
+	_, err := s.APIState.Client().EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+
+	resp, err := s.authRequest(c, "GET", s.metricsURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), gc.Equals, instrumentation.ContentType)
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+
+	for _, expected := range []string{
+		`(?m)^juju_apiserver_requests_total{facade="Client",method="EnvironmentGet",version="[0-9]+"} [0-9]+$`,
+		`(?m)^juju_apiserver_request_duration_seconds_count{facade="Client",method="EnvironmentGet",version="[0-9]+"} [0-9]+$`,
+		`(?m)^juju_apiserver_connections{kind="user"} [0-9]+$`,
+		`(?m)^juju_apiserver_login_rate_limited_total [0-9]+$`,
+		`(?m)^juju_apiserver_watchers [0-9]+$`,
+		`(?m)^juju_state_txn_retries_total [0-9]+$`,
+	} {
+		c.Check(string(body), gc.Matches, `(?s).*`+expected+`.*`)
+	}
+}
@@ -43,6 +43,9 @@ type Action struct {
 	Receiver   string                 `json:"receiver"`
 	Name       string                 `json:"name"`
 	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	// Timeout is how long the action may run before the unit kills
+	// it; zero means no limit.
+	Timeout time.Duration `json:"timeout,omitempty"`
 }
 
 // ActionResults is a slice of ActionResult for bulk requests.
@@ -117,3 +120,75 @@ type ServiceCharmActionsResult struct {
 	Actions    *charm.Actions `json:"actions,omitempty"`
 	Error      *Error         `json:"error,omitempty"`
 }
+
+// ServiceActions is a slice of ServiceAction for bulk requests.
+type ServiceActions struct {
+	Actions []ServiceAction `json:"actions,omitempty"`
+}
+
+// ServiceAction describes an Action to be queued up on every unit of a
+// service.
+type ServiceAction struct {
+	// Service is the tag of the service whose units run the action.
+	Service    string                 `json:"service"`
+	Name       string                 `json:"name"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+
+	// BatchSize is the greatest number of units that may run the
+	// action at once; zero means no limit.
+	BatchSize int `json:"batch-size,omitempty"`
+
+	// StopOnFailure, if true, stops further units running the
+	// action once it has failed on any unit.
+	StopOnFailure bool `json:"stop-on-failure,omitempty"`
+
+	// Scheduled, if set, is the time before which no unit will run
+	// the action.
+	Scheduled *time.Time `json:"scheduled,omitempty"`
+
+	// Timeout, if not zero, is the time the action may run on each
+	// unit before it is stopped.
+	Timeout time.Duration `json:"timeout,omitempty"`
+}
+
+// ActionOperationIds holds the ids, or unique id prefixes, of action
+// operations.
+type ActionOperationIds struct {
+	Ids []string `json:"ids,omitempty"`
+}
+
+// ActionOperationResults is a slice of ActionOperationResult for bulk
+// requests.
+type ActionOperationResults struct {
+	Results []ActionOperationResult `json:"results,omitempty"`
+}
+
+// ActionOperationResult describes the progress of an Action queued up
+// on every unit of a service.
+type ActionOperationResult struct {
+	Id            string                 `json:"id,omitempty"`
+	Service       string                 `json:"service,omitempty"`
+	Name          string                 `json:"name,omitempty"`
+	Parameters    map[string]interface{} `json:"parameters,omitempty"`
+	BatchSize     int                    `json:"batch-size,omitempty"`
+	StopOnFailure bool                   `json:"stop-on-failure,omitempty"`
+	Timeout       time.Duration          `json:"timeout,omitempty"`
+	Enqueued      time.Time              `json:"enqueued,omitempty"`
+	Scheduled     time.Time              `json:"scheduled,omitempty"`
+	Completed     time.Time              `json:"completed,omitempty"`
+	Status        string                 `json:"status,omitempty"`
+	Units         []ActionOperationUnit  `json:"units,omitempty"`
+	Error         *Error                 `json:"error,omitempty"`
+}
+
+// ActionOperationUnit describes the progress of an action operation
+// on a single unit.
+type ActionOperationUnit struct {
+	// Unit is the tag of the unit.
+	Unit string `json:"unit"`
+
+	// Action is the tag of the action queued for the unit, if any.
+	Action string `json:"action,omitempty"`
+
+	Status string `json:"status"`
+}
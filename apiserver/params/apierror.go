@@ -55,6 +55,8 @@ const (
 	CodeActionNotAvailable        = "action no longer available"
 	CodeOperationBlocked          = "operation is blocked"
 	CodeLeadershipClaimDenied     = "leadership claim denied"
+	CodeRateLimited               = "rate limited"
+	CodeTooManyWatchers           = "too many watchers"
 )
 
 // ErrCode returns the error code associated with
@@ -173,3 +175,11 @@ func IsCodeOperationBlocked(err error) bool {
 func IsCodeLeadershipClaimDenied(err error) bool {
 	return ErrCode(err) == CodeLeadershipClaimDenied
 }
+
+func IsCodeRateLimited(err error) bool {
+	return ErrCode(err) == CodeRateLimited
+}
+
+func IsCodeTooManyWatchers(err error) bool {
+	return ErrCode(err) == CodeTooManyWatchers
+}
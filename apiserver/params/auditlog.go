@@ -0,0 +1,58 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// AuditRecord describes a single auditable operation performed
+// against an environment.
+type AuditRecord struct {
+	// Timestamp records when the operation was performed.
+	Timestamp time.Time `json:"timestamp"`
+
+	// User holds the tag of the entity that performed the operation.
+	User string `json:"user"`
+
+	// Facade, Version and Method identify the API call made.
+	Facade  string `json:"facade"`
+	Version int    `json:"version"`
+	Method  string `json:"method"`
+
+	// Args holds a summary of the arguments passed to the call.
+	Args string `json:"args,omitempty"`
+
+	// Entities holds the tags of the entities targeted by the
+	// operation.
+	Entities []string `json:"entities,omitempty"`
+
+	// Error holds the error message returned by the operation,
+	// if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+// AuditRecordFilter holds the criteria used to select audit records.
+type AuditRecordFilter struct {
+	// User, if set, selects only operations performed by the
+	// entity with this tag.
+	User string `json:"user,omitempty"`
+
+	// Entity, if set, selects only operations that targeted the
+	// entity with this tag.
+	Entity string `json:"entity,omitempty"`
+
+	// Since and Until, if set, bound the time window of the
+	// selected records.
+	Since *time.Time `json:"since,omitempty"`
+	Until *time.Time `json:"until,omitempty"`
+
+	// Limit, if greater than zero, restricts the result to the
+	// most recent Limit records.
+	Limit int `json:"limit,omitempty"`
+}
+
+// AuditRecordResults holds the result of an API call to list
+// audit records.
+type AuditRecordResults struct {
+	Results []AuditRecord `json:"results"`
+}
@@ -12,6 +12,13 @@ import (
 // BackupsCreateArgs holds the args for the API Create method.
 type BackupsCreateArgs struct {
 	Notes string
+	// Target is the backup target in which to store the archive. The
+	// environment's default backup target is used if it is not set.
+	Target string
+	// EncryptionKey is the armored OpenPGP public key to which the
+	// archive is encrypted. The environment's backup encryption key
+	// is used if it is not set.
+	EncryptionKey string
 }
 
 // BackupsInfoArgs holds the args for the API Info method.
@@ -21,6 +28,9 @@ type BackupsInfoArgs struct {
 
 // BackupsListArgs holds the args for the API List method.
 type BackupsListArgs struct {
+	// Scheduled restricts the list to scheduled backups, and
+	// requests the status of the backup schedule.
+	Scheduled bool
 }
 
 // BackupsDownloadArgs holds the args for the API Download method.
@@ -42,6 +52,19 @@ type BackupsRemoveArgs struct {
 // BackupsListResult holds the list of all stored backups.
 type BackupsListResult struct {
 	List []BackupsMetadataResult
+	// Schedule holds the status of the backup schedule, if it was
+	// requested.
+	Schedule *BackupsScheduleStatus
+}
+
+// BackupsScheduleStatus holds the status of an environment's
+// scheduled backups.
+type BackupsScheduleStatus struct {
+	// Schedule is the configured backup schedule, if any.
+	Schedule    string
+	LastAttempt time.Time // May be zero...
+	LastSuccess time.Time // May be zero...
+	LastError   string
 }
 
 // BackupsListResult holds the list of all stored backups.
@@ -62,10 +85,16 @@ type BackupsMetadataResult struct {
 	Started     time.Time
 	Finished    time.Time // May be zero...
 	Notes       string
+	Target      string
+	Scheduled   bool
 	Environment string
 	Machine     string
 	Hostname    string
 	Version     version.Number
+
+	// KeyFingerprint is the fingerprint of the OpenPGP key to which
+	// the archive is encrypted, if it is.
+	KeyFingerprint string
 }
 
 // RestoreArgs Holds the backup file or id
@@ -41,10 +41,24 @@ const (
 	RemoveEnvUser EnvironAction = "remove"
 )
 
+// EnvironmentAccess is the level of access a user has to an environment.
+type EnvironmentAccess string
+
+// Levels of access a user can have to an environment.
+const (
+	EnvironmentReadAccess  EnvironmentAccess = "read"
+	EnvironmentWriteAccess EnvironmentAccess = "write"
+	EnvironmentAdminAccess EnvironmentAccess = "admin"
+)
+
 // ModifyEnvironUser stores the parameters used for a Client.ShareEnvironment call.
+// Access is only used when adding a user; if it is empty, the user is
+// given write access. Adding a user who already has access to the
+// environment with a non-empty Access changes their access level.
 type ModifyEnvironUser struct {
-	UserTag string        `json:"user-tag"`
-	Action  EnvironAction `json:"action"`
+	UserTag string            `json:"user-tag"`
+	Action  EnvironAction     `json:"action"`
+	Access  EnvironmentAccess `json:"access,omitempty"`
 }
 
 // SetEnvironAgentVersion contains the arguments for
@@ -55,11 +69,12 @@ type SetEnvironAgentVersion struct {
 
 // EnvUserInfo holds information on a user.
 type EnvUserInfo struct {
-	UserName       string     `json:"user"`
-	DisplayName    string     `json:"displayname"`
-	CreatedBy      string     `json:"createdby"`
-	DateCreated    time.Time  `json:"datecreated"`
-	LastConnection *time.Time `json:"lastconnection"`
+	UserName       string            `json:"user"`
+	DisplayName    string            `json:"displayname"`
+	CreatedBy      string            `json:"createdby"`
+	DateCreated    time.Time         `json:"datecreated"`
+	LastConnection *time.Time        `json:"lastconnection"`
+	Access         EnvironmentAccess `json:"access"`
 }
 
 // EnvUserInfoResult holds the result of an EnvUserInfo call.
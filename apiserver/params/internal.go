@@ -364,12 +364,22 @@ type SetInstancesStatus struct {
 	Entities []InstanceStatus
 }
 
+// HistoryKind identifies the statuses returned by a status history
+// query.
 type HistoryKind string
 
 const (
+	// KindCombined, KindAgent and KindWorkload select the statuses of
+	// a unit.
 	KindCombined HistoryKind = "combined"
 	KindAgent    HistoryKind = "agent"
 	KindWorkload HistoryKind = "workload"
+
+	// KindMachine selects the statuses of a machine.
+	KindMachine HistoryKind = "machine"
+
+	// KindService selects the statuses of a service.
+	KindService HistoryKind = "service"
 )
 
 // StatusHistory holds the parameters to filter a status history query.
@@ -377,6 +387,11 @@ type StatusHistory struct {
 	Kind HistoryKind
 	Size int
 	Name string
+
+	// Since and Until, if set, restrict the results to statuses set
+	// within the given time range.
+	Since *time.Time `json:",omitempty"`
+	Until *time.Time `json:",omitempty"`
 }
 
 // StatusResult holds an entity status, extra information, or an
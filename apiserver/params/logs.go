@@ -0,0 +1,31 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// DebugLogFormat identifies how the debug-log API end-point renders
+// each log record it sends.
+type DebugLogFormat string
+
+const (
+	// DebugLogFormatText renders each record as a single line of
+	// human readable text.
+	DebugLogFormatText DebugLogFormat = "text"
+
+	// DebugLogFormatJSON renders each record as a JSON encoded
+	// LogRecord, one per line.
+	DebugLogFormatJSON DebugLogFormat = "json"
+)
+
+// LogRecord holds a single log message as sent by the debug-log
+// API end-point when the JSON format is requested.
+type LogRecord struct {
+	Time     time.Time `json:"timestamp"`
+	Entity   string    `json:"entity"`
+	Module   string    `json:"module"`
+	Location string    `json:"location"`
+	Level    string    `json:"level"`
+	Message  string    `json:"message"`
+}
@@ -224,8 +224,11 @@ type ServiceSetCharm struct {
 }
 
 // ServiceExpose holds the parameters for making the ServiceExpose call.
+// If SourceCIDRs is not empty, the service is exposed to those CIDRs
+// only.
 type ServiceExpose struct {
 	ServiceName string
+	SourceCIDRs []string `json:",omitempty"`
 }
 
 // ServiceSet holds the parameters for a ServiceSet
@@ -265,6 +268,36 @@ type ServiceGetResults struct {
 	Constraints constraints.Value
 }
 
+// ServiceConfigHistoryResults holds the results of the
+// ServiceConfigHistory call.
+type ServiceConfigHistoryResults struct {
+	Revisions []ServiceConfigRevision
+}
+
+// ServiceConfigRevision describes a recorded change to a service's
+// config settings.
+type ServiceConfigRevision struct {
+	Revision int
+	Time     time.Time
+	User     string
+	Changes  []ServiceConfigChange
+}
+
+// ServiceConfigChange describes a change to a single service config
+// setting. A nil OldValue or NewValue indicates an unset setting.
+type ServiceConfigChange struct {
+	Key      string
+	OldValue interface{}
+	NewValue interface{}
+}
+
+// ServiceRevertConfig holds parameters for the ServiceRevertConfig
+// call.
+type ServiceRevertConfig struct {
+	ServiceName string
+	Revision    int
+}
+
 // ServiceCharmRelations holds parameters for making the ServiceCharmRelations call.
 type ServiceCharmRelations struct {
 	ServiceName string
@@ -729,11 +762,13 @@ type ListImageResult struct {
 
 // ImageMetadata represents an image in storage.
 type ImageMetadata struct {
-	Kind    string    `json:"kind"`
-	Arch    string    `json:"arch"`
-	Series  string    `json:"series"`
-	URL     string    `json:"url"`
-	Created time.Time `json:"created"`
+	Kind     string    `json:"kind"`
+	Arch     string    `json:"arch"`
+	Series   string    `json:"series"`
+	URL      string    `json:"url"`
+	Created  time.Time `json:"created"`
+	LastUsed time.Time `json:"last-used"`
+	Size     int64     `json:"size"`
 }
 
 // RebootActionResults holds a list of RebootActionResult and any error.
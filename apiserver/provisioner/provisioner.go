@@ -20,6 +20,7 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
@@ -252,6 +253,12 @@ func (p *ProvisionerAPI) ContainerManagerConfig(args params.ContainerManagerConf
 			logger.Debugf("using default MTU %v for all LXC containers NICs", lxcDefaultMTU)
 			cfg[container.ConfigLXCDefaultMTU] = fmt.Sprintf("%d", lxcDefaultMTU)
 		}
+		templateSeries, err := p.lxcTemplateSeries(config)
+		if err != nil {
+			return result, err
+		}
+		cfg[container.ConfigLXCTemplateSeries] = strings.Join(templateSeries, ",")
+		cfg[container.ConfigLXCTemplateMaxAge] = config.ImageCacheMaxAge().String()
 	}
 
 	if !environs.AddressAllocationEnabled() {
@@ -288,6 +295,23 @@ func (p *ProvisionerAPI) ContainerManagerConfig(args params.ContainerManagerConf
 	return result, nil
 }
 
+// lxcTemplateSeries returns the sorted series of the LXC containers in
+// the environment, and its default series, so that hosts can create
+// clone templates for them before they are needed.
+func (p *ProvisionerAPI) lxcTemplateSeries(cfg *config.Config) ([]string, error) {
+	series := set.NewStrings(config.PreferredSeries(cfg))
+	machines, err := p.st.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, machine := range machines {
+		if machine.ContainerType() == instance.LXC && machine.Life() != state.Dead {
+			series.Add(machine.Series())
+		}
+	}
+	return series.SortedValues(), nil
+}
+
 // ContainerConfig returns information from the environment config that is
 // needed for container cloud-init.
 func (p *ProvisionerAPI) ContainerConfig() (params.ContainerConfig, error) {
@@ -5,6 +5,8 @@ package provisioner_test
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	stdtesting "testing"
 
 	"github.com/juju/errors"
@@ -20,6 +22,7 @@ import (
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/instance"
@@ -1359,6 +1362,32 @@ func (s *withoutStateServerSuite) TestContainerManagerConfigNoIPForwarding(c *gc
 	})
 }
 
+func (s *withoutStateServerSuite) TestContainerManagerConfigLXCTemplates(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"image-cache-max-age": "168h",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	template := state.MachineTemplate{
+		Series: "precise",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}
+	_, err = s.State.AddMachineInsideMachine(template, s.machines[0].Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	template.Series = "utopic"
+	_, err = s.State.AddMachineInsideMachine(template, s.machines[0].Id(), instance.KVM)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg := s.getManagerConfig(c, instance.LXC)
+	expected := []string{"precise", config.LatestLtsSeries()}
+	sort.Strings(expected)
+	c.Assert(cfg[container.ConfigLXCTemplateSeries], gc.Equals, strings.Join(expected, ","))
+	c.Assert(cfg[container.ConfigLXCTemplateMaxAge], gc.Equals, "168h0m0s")
+
+	// Only LXC containers are given templates.
+	cfg = s.getManagerConfig(c, instance.KVM)
+	c.Assert(cfg[container.ConfigLXCTemplateSeries], gc.Equals, "")
+}
+
 func (s *withoutStateServerSuite) TestContainerConfig(c *gc.C) {
 	attrs := map[string]interface{}{
 		"http-proxy":            "http://proxy.example.com:9000",
@@ -1580,8 +1609,10 @@ func (s *lxcDefaultMTUSuite) TestContainerManagerConfigLXCDefaultMTU(c *gc.C) {
 		container.ConfigName:          "juju",
 		container.ConfigLXCDefaultMTU: "9000",
 
-		"use-aufs":                   "false",
-		container.ConfigIPForwarding: "true",
+		"use-aufs":                        "false",
+		container.ConfigIPForwarding:      "true",
+		container.ConfigLXCTemplateSeries: config.LatestLtsSeries(),
+		container.ConfigLXCTemplateMaxAge: "720h0m0s",
 	})
 
 	// KVM instances are not affected.
@@ -0,0 +1,189 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/juju/ratelimit"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+)
+
+// requestBuckets holds the token buckets which limit the rate of the
+// requests made by each entity, so that an entity is limited to the
+// same rate however many connections it has open.
+type requestBuckets struct {
+	mu      sync.Mutex
+	buckets map[string]*requestBucket
+}
+
+type requestBucket struct {
+	*ratelimit.Bucket
+	rate int
+}
+
+func newRequestBuckets() *requestBuckets {
+	return &requestBuckets{
+		buckets: make(map[string]*requestBucket),
+	}
+}
+
+// get returns the bucket limiting the entity with the given tag to
+// rate requests per second, with bursts of up to a second's worth of
+// requests. It returns nil if rate is zero.
+func (b *requestBuckets) get(tag string, rate int) *ratelimit.Bucket {
+	if rate <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	bucket, ok := b.buckets[tag]
+	if !ok || bucket.rate != rate {
+		bucket = &requestBucket{
+			Bucket: ratelimit.NewBucketWithRate(float64(rate), int64(rate)),
+			rate:   rate,
+		}
+		b.buckets[tag] = bucket
+	}
+	return bucket.Bucket
+}
+
+// watcherCounter is implemented by types that know how many watchers
+// are held. It is satisfied by *common.Resources.
+type watcherCounter interface {
+	WatcherCount() int
+}
+
+// entityWatchers tracks the watchers held by each entity's connections,
+// so that an entity is limited to the same number of watchers however
+// many connections it has open.
+type entityWatchers struct {
+	mu          sync.Mutex
+	connections map[string]map[watcherCounter]bool
+}
+
+func newEntityWatchers() *entityWatchers {
+	return &entityWatchers{
+		connections: make(map[string]map[watcherCounter]bool),
+	}
+}
+
+// add records that the watchers counted by conn are held by the entity
+// with the given tag, until the returned function is called.
+func (w *entityWatchers) add(tag string, conn watcherCounter) (remove func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.connections[tag] == nil {
+		w.connections[tag] = make(map[watcherCounter]bool)
+	}
+	w.connections[tag][conn] = true
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.connections[tag], conn)
+		if len(w.connections[tag]) == 0 {
+			delete(w.connections, tag)
+		}
+	}
+}
+
+// counter returns a watcherCounter which counts the watchers held by
+// all the connections of the entity with the given tag.
+func (w *entityWatchers) counter(tag string) watcherCounter {
+	return entityWatcherCounter{w, tag}
+}
+
+type entityWatcherCounter struct {
+	watchers *entityWatchers
+	tag      string
+}
+
+// WatcherCount is part of the watcherCounter interface.
+func (c entityWatcherCounter) WatcherCount() int {
+	c.watchers.mu.Lock()
+	defer c.watchers.mu.Unlock()
+	count := 0
+	for conn := range c.watchers.connections[c.tag] {
+		count += conn.WatcherCount()
+	}
+	return count
+}
+
+// rateLimitedRoot rejects calls made faster than the rate allowed by
+// its bucket, and calls which would start more watchers than allowed.
+// Calls rejected for their rate carry the params.CodeRateLimited error
+// code, so that clients know to try again later; calls rejected for
+// starting too many watchers carry params.CodeTooManyWatchers, as they
+// will fail until the client stops some of its watchers.
+type rateLimitedRoot struct {
+	rpc.MethodFinder
+	bucket      *ratelimit.Bucket
+	watchers    watcherCounter
+	maxWatchers int
+	release     func()
+}
+
+// newRateLimitedRoot returns a new rateLimitedRoot. The bucket may be
+// nil, and maxWatchers zero, in which case the corresponding limit is
+// not applied. The release function, if not nil, is called when the
+// connection is cleaned up.
+func newRateLimitedRoot(
+	finder rpc.MethodFinder,
+	bucket *ratelimit.Bucket,
+	watchers watcherCounter,
+	maxWatchers int,
+	release func(),
+) *rateLimitedRoot {
+	return &rateLimitedRoot{
+		MethodFinder: finder,
+		bucket:       bucket,
+		watchers:     watchers,
+		maxWatchers:  maxWatchers,
+		release:      release,
+	}
+}
+
+// Kill implements rpc.Killer, passing the call on to the wrapped root.
+func (r *rateLimitedRoot) Kill() {
+	killRoot(r.MethodFinder)
+}
+
+// Cleanup implements rpc.Cleaner, passing the call on to the wrapped
+// root, and then no longer counting the connection's watchers.
+func (r *rateLimitedRoot) Cleanup() {
+	cleanupRoot(r.MethodFinder)
+	if r.release != nil {
+		r.release()
+	}
+}
+
+// FindMethod returns common.ErrRateLimited or common.ErrTooManyWatchers
+// if the call would exceed the limits, and otherwise delegates to the
+// wrapped MethodFinder.
+func (r *rateLimitedRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	if isRateLimitExempt(rootName) {
+		return r.MethodFinder.FindMethod(rootName, version, methodName)
+	}
+	if r.bucket != nil && r.bucket.TakeAvailable(1) == 0 {
+		apiRateLimited.Inc("requests")
+		return nil, common.ErrRateLimited
+	}
+	if r.maxWatchers > 0 && strings.HasPrefix(methodName, "Watch") && r.watchers.WatcherCount() >= r.maxWatchers {
+		apiRateLimited.Inc("watchers")
+		return nil, common.ErrTooManyWatchers
+	}
+	return r.MethodFinder.FindMethod(rootName, version, methodName)
+}
+
+// isRateLimitExempt returns whether calls to the given facade are
+// never rate limited. Agents must be able to ping the server to stay
+// alive, and clients must always be able to wait on and stop the
+// watchers they already have.
+func isRateLimitExempt(rootName string) bool {
+	return rootName == "Pinger" || strings.HasSuffix(rootName, "Watcher")
+}
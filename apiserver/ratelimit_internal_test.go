@@ -0,0 +1,132 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/ratelimit"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type rateLimitSuite struct {
+	coretesting.BaseSuite
+	finder   *fakeMethodFinder
+	watchers fakeWatcherCounter
+}
+
+var _ = gc.Suite(&rateLimitSuite{})
+
+func (s *rateLimitSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.finder = &fakeMethodFinder{}
+	s.watchers = 0
+}
+
+// newBucket returns a bucket which never refills during a test.
+func newBucket(capacity int64) *ratelimit.Bucket {
+	return ratelimit.NewBucketWithRate(0.001, capacity)
+}
+
+func (s *rateLimitSuite) TestRequestRateLimited(c *gc.C) {
+	root := newRateLimitedRoot(s.finder, newBucket(2), &s.watchers, 0, nil)
+	for i := 0; i < 2; i++ {
+		_, err := root.FindMethod("Client", 0, "FullStatus")
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	before := apiRateLimited.Value("requests")
+	_, err := root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, gc.Equals, common.ErrRateLimited)
+	c.Assert(common.ServerError(err), jc.Satisfies, params.IsCodeRateLimited)
+	c.Assert(apiRateLimited.Value("requests"), gc.Equals, before+1)
+}
+
+func (s *rateLimitSuite) TestExemptFacades(c *gc.C) {
+	root := newRateLimitedRoot(s.finder, newBucket(1), &s.watchers, 1, nil)
+	s.watchers = 1
+	_, err := root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+	for _, facade := range []string{"Pinger", "NotifyWatcher", "AllWatcher"} {
+		_, err := root.FindMethod(facade, 0, "Stop")
+		c.Check(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *rateLimitSuite) TestTooManyWatchers(c *gc.C) {
+	root := newRateLimitedRoot(s.finder, nil, &s.watchers, 2, nil)
+	s.watchers = 1
+	_, err := root.FindMethod("Client", 0, "WatchAll")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.watchers = 2
+	before := apiRateLimited.Value("watchers")
+	_, err = root.FindMethod("Client", 0, "WatchAll")
+	c.Assert(err, gc.Equals, common.ErrTooManyWatchers)
+	c.Assert(common.ServerError(err), jc.Satisfies, params.IsCodeTooManyWatchers)
+	// Clients retry rate limited calls, but there is no point in
+	// retrying until the client has stopped some watchers.
+	c.Assert(common.ServerError(err), gc.Not(jc.Satisfies), params.IsCodeRateLimited)
+	c.Assert(apiRateLimited.Value("watchers"), gc.Equals, before+1)
+
+	// Other calls are still allowed.
+	_, err = root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *rateLimitSuite) TestBucketsSharedByEntity(c *gc.C) {
+	buckets := newRequestBuckets()
+	c.Assert(buckets.get("user-bob", 0), gc.IsNil)
+
+	bucket := buckets.get("user-bob", 10)
+	c.Assert(bucket, gc.NotNil)
+	c.Assert(buckets.get("user-bob", 10), gc.Equals, bucket)
+	c.Assert(buckets.get("user-mary", 10), gc.Not(gc.Equals), bucket)
+	// A new bucket is made when the rate changes.
+	c.Assert(buckets.get("user-bob", 20), gc.Not(gc.Equals), bucket)
+}
+
+func (s *rateLimitSuite) TestWatchersCountedByEntity(c *gc.C) {
+	watchers := newEntityWatchers()
+	bob1, bob2, mary := fakeWatcherCounter(1), fakeWatcherCounter(2), fakeWatcherCounter(4)
+	removeBob1 := watchers.add("user-bob", &bob1)
+	watchers.add("user-bob", &bob2)
+	watchers.add("user-mary", &mary)
+
+	bob := watchers.counter("user-bob")
+	c.Assert(bob.WatcherCount(), gc.Equals, 3)
+	c.Assert(watchers.counter("user-mary").WatcherCount(), gc.Equals, 4)
+	c.Assert(watchers.counter("user-fred").WatcherCount(), gc.Equals, 0)
+
+	// The limit applies across all of an entity's connections.
+	root := newRateLimitedRoot(s.finder, nil, bob, 3, nil)
+	_, err := root.FindMethod("Client", 0, "WatchAll")
+	c.Assert(err, gc.Equals, common.ErrTooManyWatchers)
+
+	removeBob1()
+	c.Assert(bob.WatcherCount(), gc.Equals, 2)
+	_, err = root.FindMethod("Client", 0, "WatchAll")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *rateLimitSuite) TestKillAndCleanupPassedOn(c *gc.C) {
+	finder := &fakeKillerCleaner{}
+	released := false
+	root := newRateLimitedRoot(finder, nil, &s.watchers, 1, func() {
+		finder.calls = append(finder.calls, "release")
+		released = true
+	})
+	root.Kill()
+	c.Assert(released, jc.IsFalse)
+	root.Cleanup()
+	c.Assert(finder.calls, jc.DeepEquals, []string{"Kill", "Cleanup", "release"})
+}
+
+type fakeWatcherCounter int
+
+func (n *fakeWatcherCounter) WatcherCount() int {
+	return int(*n)
+}
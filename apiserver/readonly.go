@@ -0,0 +1,118 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/utils/set"
+)
+
+// readOnlyFacades holds the names of the facades none of whose
+// methods change the state of an environment.
+var readOnlyFacades = set.NewStrings(
+	"AllWatcher",
+	"EntityWatcher",
+	"FilesystemAttachmentsWatcher",
+	"NotifyWatcher",
+	"Pinger",
+	"RelationUnitsWatcher",
+	"StringsWatcher",
+	"VolumeAttachmentsWatcher",
+)
+
+// readOnlyMethods holds, by facade, the methods which do not change
+// the state of an environment. Methods must be added here explicitly
+// when they are added to a facade; any method not listed is audited
+// and denied to users with read access.
+var readOnlyMethods = map[string]set.Strings{
+	"Action": set.NewStrings(
+		"ActionOperations",
+		"Actions",
+		"FindActionTagsByPrefix",
+		"ListAll",
+		"ListCompleted",
+		"ListPending",
+		"ListRunning",
+		"ServicesCharmActions",
+	),
+	"Annotations": set.NewStrings(
+		"Get",
+	),
+	"AuditLog": set.NewStrings(
+		"Records",
+	),
+	"Backups": set.NewStrings(
+		"Info",
+		"List",
+	),
+	"Block": set.NewStrings(
+		"List",
+	),
+	"Charms": set.NewStrings(
+		"CharmInfo",
+		"IsMetered",
+		"List",
+	),
+	"Client": set.NewStrings(
+		"APIHostPorts",
+		"AgentVersion",
+		"CharmInfo",
+		"EnvUserInfo",
+		"EnvironmentGet",
+		"EnvironmentInfo",
+		"ExportBundle",
+		"FindTools",
+		"FullStatus",
+		"GetAnnotations",
+		"GetEnvironmentConstraints",
+		"GetServiceConstraints",
+		"PrivateAddress",
+		"PublicAddress",
+		"ResolveCharms",
+		"ServiceCharmRelations",
+		"ServiceConfigHistory",
+		"ServiceGet",
+		"ServiceGetCharmURL",
+		"Status",
+		"StatusHistory",
+		"UnitStatusHistory",
+		"WatchAll",
+	),
+	"EnvironmentManager": set.NewStrings(
+		"ConfigSkeleton",
+		"ListEnvironments",
+	),
+	"ImageManager": set.NewStrings(
+		"ListImages",
+	),
+	"KeyManager": set.NewStrings(
+		"ListKeys",
+	),
+	"Storage": set.NewStrings(
+		"List",
+		"ListPools",
+		"ListVolumes",
+		"Show",
+	),
+	"SystemManager": set.NewStrings(
+		"AllEnvironments",
+		"EnvironmentConfig",
+		"ListBlockedEnvironments",
+	),
+	"UserManager": set.NewStrings(
+		"UserInfo",
+	),
+}
+
+// isReadOnlyCall returns whether a call to the given facade method is
+// known not to change the state of an environment. Any method not
+// known to be read-only is treated as mutating.
+func isReadOnlyCall(facadeName, methodName string) bool {
+	if readOnlyFacades.Contains(facadeName) {
+		return true
+	}
+	if methods, ok := readOnlyMethods[facadeName]; ok {
+		return methods.Contains(methodName)
+	}
+	return false
+}
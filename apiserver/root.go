@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/rpcreflect"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/tracing"
 )
 
 var (
@@ -56,12 +57,16 @@ type apiHandler struct {
 
 var _ = (*apiHandler)(nil)
 
+// newResources is called to create the resources held on behalf of
+// each connection. It is a variable so tests can inspect them.
+var newResources = common.NewResources
+
 // newApiHandler returns a new apiHandler.
 func newApiHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, reqNotifier *requestNotifier, envUUID string) (*apiHandler, error) {
 	r := &apiHandler{
 		state:      st,
 		closeState: st.EnvironUUID() != srv.state.EnvironUUID(),
-		resources:  common.NewResources(),
+		resources:  newResources(),
 		rpcConn:    rpcConn,
 		envUUID:    envUUID,
 	}
@@ -107,6 +112,10 @@ type srvCaller struct {
 	objMethod rpcreflect.ObjMethod
 	goType    reflect.Type
 	creator   func(id string) (reflect.Value, error)
+
+	// tracedCreator creates an object whose State records its
+	// transactions on behalf of the given span.
+	tracedCreator func(span *tracing.Span, id string) (reflect.Value, error)
 }
 
 // ParamsType defines the parameters that should be supplied to this function.
@@ -131,6 +140,20 @@ func (s *srvCaller) Call(objId string, arg reflect.Value) (reflect.Value, error)
 	return s.objMethod.Call(objVal, arg)
 }
 
+// WithSpan implements rpc.TracingMethodCaller. The objects the returned
+// caller places calls on are created for each call, rather than shared
+// with other calls, so that their State can carry the span.
+func (s *srvCaller) WithSpan(span *tracing.Span) rpcreflect.MethodCaller {
+	return &srvCaller{
+		objMethod: s.objMethod,
+		goType:    s.goType,
+		creator: func(id string) (reflect.Value, error) {
+			return s.tracedCreator(span, id)
+		},
+		tracedCreator: s.tracedCreator,
+	}
+}
+
 // apiRoot implements basic method dispatching to the facade registry.
 type apiRoot struct {
 	state       *state.State
@@ -166,6 +189,24 @@ func (r *apiRoot) Cleanup() {
 	}
 }
 
+// killRoot calls Kill on the given root if it implements rpc.Killer.
+// Roots that wrap another root must pass the call on, or the wrapped
+// root's resources are never stopped.
+func killRoot(root rpc.MethodFinder) {
+	if killer, ok := root.(rpc.Killer); ok {
+		killer.Kill()
+	}
+}
+
+// cleanupRoot calls Cleanup on the given root if it implements
+// rpc.Cleaner. Roots that wrap another root must pass the call on, or
+// the wrapped root's State is never closed.
+func cleanupRoot(root rpc.MethodFinder) {
+	if cleaner, ok := root.(rpc.Cleaner); ok {
+		cleaner.Cleanup()
+	}
+}
+
 // FindMethod looks up the given rootName and version in our facade registry
 // and returns a MethodCaller that will be used by the RPC code to place calls on
 // that facade.
@@ -193,42 +234,56 @@ func (r *apiRoot) FindMethod(rootName string, version int, methodName string) (r
 		}
 		// Now that we have the write lock, check one more time in case
 		// someone got the write lock before us.
-		factory, err := common.Facades.GetFactory(rootName, version)
-		if err != nil {
-			// We don't check for IsNotFound here, because it
-			// should have already been handled in the GetType
-			// check.
-			return reflect.Value{}, err
-		}
-		obj, err := factory(r.state, r.resources, r.authorizer, id)
+		objValue, err := r.newObject(r.state, goType, rootName, version, id)
 		if err != nil {
 			return reflect.Value{}, err
 		}
-		objValue = reflect.ValueOf(obj)
-		if !objValue.Type().AssignableTo(goType) {
-			return reflect.Value{}, errors.Errorf(
-				"internal error, %s(%d) claimed to return %s but returned %T",
-				rootName, version, goType, obj)
-		}
-		if goType.Kind() == reflect.Interface {
-			// If the original function wanted to return an
-			// interface type, the indirection in the factory via
-			// an interface{} strips the original interface
-			// information off. So here we have to create the
-			// interface again, and assign it.
-			asInterface := reflect.New(goType).Elem()
-			asInterface.Set(objValue)
-			objValue = asInterface
-		}
 		r.objectCache[objKey] = objValue
 		return objValue, nil
 	}
+	tracedCreator := func(span *tracing.Span, id string) (reflect.Value, error) {
+		return r.newObject(r.state.Traced(span), goType, rootName, version, id)
+	}
 	return &srvCaller{
-		creator:   creator,
-		objMethod: objMethod,
+		creator:       creator,
+		tracedCreator: tracedCreator,
+		objMethod:     objMethod,
 	}, nil
 }
 
+// newObject creates the facade object of the given name, version and
+// id, using the given State.
+func (r *apiRoot) newObject(st *state.State, goType reflect.Type, rootName string, version int, id string) (reflect.Value, error) {
+	factory, err := common.Facades.GetFactory(rootName, version)
+	if err != nil {
+		// We don't check for IsNotFound here, because it
+		// should have already been handled in the GetType
+		// check.
+		return reflect.Value{}, err
+	}
+	obj, err := factory(st, r.resources, r.authorizer, id)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	objValue := reflect.ValueOf(obj)
+	if !objValue.Type().AssignableTo(goType) {
+		return reflect.Value{}, errors.Errorf(
+			"internal error, %s(%d) claimed to return %s but returned %T",
+			rootName, version, goType, obj)
+	}
+	if goType.Kind() == reflect.Interface {
+		// If the original function wanted to return an
+		// interface type, the indirection in the factory via
+		// an interface{} strips the original interface
+		// information off. So here we have to create the
+		// interface again, and assign it.
+		asInterface := reflect.New(goType).Elem()
+		asInterface.Set(objValue)
+		objValue = asInterface
+	}
+	return objValue, nil
+}
+
 func (r *apiRoot) lookupMethod(rootName string, version int, methodName string) (reflect.Type, rpcreflect.ObjMethod, error) {
 	noMethod := rpcreflect.ObjMethod{}
 	goType, err := common.Facades.GetType(rootName, version)
@@ -21,6 +21,7 @@ import (
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cert"
 	jujutesting "github.com/juju/juju/juju/testing"
@@ -364,6 +365,46 @@ func (s *serverSuite) checkApiHandlerTeardown(c *gc.C, srvSt, st *state.State) {
 	}
 }
 
+func (s *serverSuite) TestUserConnectionStopsWatchersOnClose(c *gc.C) {
+	created := make(chan *common.Resources, 10)
+	s.PatchValue(apiserver.NewResources, func() *common.Resources {
+		resources := common.NewResources()
+		select {
+		case created <- resources:
+		default:
+		}
+		return resources
+	})
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "dummy-password"})
+	info := s.APIInfo(c)
+	info.Tag = user.UserTag()
+	info.Password = "dummy-password"
+	st, err := api.Open(info, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var resources *common.Resources
+	select {
+	case resources = <-created:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("connection resources never created")
+	}
+	_, err = st.Client().WatchAll()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources.WatcherCount(), gc.Equals, 1)
+
+	err = st.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if resources.WatcherCount() == 0 {
+			break
+		}
+		if !a.HasNext() {
+			c.Fatalf("watcher not stopped after connection closed")
+		}
+	}
+	c.Assert(resources.Count(), gc.Equals, 0)
+}
+
 func assertStateIsOpen(c *gc.C, st *state.State) {
 	c.Assert(st.Ping(), jc.ErrorIsNil)
 }
@@ -174,8 +174,9 @@ func DeployService(st *state.State, owner string, args params.ServiceDeploy) err
 }
 
 // ServiceSetSettingsStrings updates the settings for the given service,
-// taking the configuration from a map of strings.
-func ServiceSetSettingsStrings(service *state.Service, settings map[string]string) error {
+// taking the configuration from a map of strings. The change is recorded
+// in the service's config history as made by the given user.
+func ServiceSetSettingsStrings(service *state.Service, settings map[string]string, user string) error {
 	ch, _, err := service.Charm()
 	if err != nil {
 		return err
@@ -185,7 +186,7 @@ func ServiceSetSettingsStrings(service *state.Service, settings map[string]strin
 	if err != nil {
 		return err
 	}
-	return service.UpdateConfigSettings(changes)
+	return service.UpdateConfigSettingsByUser(changes, user)
 }
 
 func networkTagsToNames(tags []string) ([]string, error) {
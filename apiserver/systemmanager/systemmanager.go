@@ -242,6 +242,7 @@ func (s *SystemManagerAPI) EnvironmentConfig() (params.EnvironmentConfigResults,
 	}
 
 	result.Config = config.AllAttrs()
+	common.MaskBackupSecrets(result.Config)
 	return result, nil
 }
 
@@ -77,7 +77,7 @@ func (s *systemManagerSuite) TestAllEnvironments(c *gc.C) {
 	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{
 		Name: "user", Owner: remoteUserTag})
 	defer st.Close()
-	st.AddEnvironmentUser(admin.UserTag(), remoteUserTag, "Foo Bar")
+	st.AddEnvironmentUser(admin.UserTag(), remoteUserTag, "Foo Bar", state.EnvironmentAdminAccess)
 
 	s.Factory.MakeEnvironment(c, &factory.EnvParams{
 		Name: "no-access", Owner: remoteUserTag}).Close()
@@ -144,6 +144,20 @@ func (s *systemManagerSuite) TestEnvironmentConfig(c *gc.C) {
 	c.Assert(env.Config["name"], gc.Equals, "dummyenv")
 }
 
+func (s *systemManagerSuite) TestEnvironmentConfigMasksBackupSecrets(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"backup-target":        "s3",
+		"backup-s3-bucket":     "juju-backups",
+		"backup-s3-access-key": "access",
+		"backup-s3-secret-key": "secret",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	env, err := s.systemManager.EnvironmentConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.Config["backup-s3-secret-key"], gc.Equals, "not available")
+	c.Assert(env.Config["backup-s3-bucket"], gc.Equals, "juju-backups")
+}
+
 func (s *systemManagerSuite) TestEnvironmentConfigFromNonStateServer(c *gc.C) {
 	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{
 		Name: "test"})
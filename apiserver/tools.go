@@ -76,8 +76,8 @@ func (h *toolsUploadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	defer stateWrapper.cleanup()
 
-	if err := stateWrapper.authenticateUser(r); err != nil {
-		h.authError(w, h)
+	if err := stateWrapper.authenticateUser(r, state.EnvironmentWriteAccess); err != nil {
+		h.userAuthError(w, h, err)
 		return
 	}
 
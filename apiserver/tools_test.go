@@ -68,6 +68,13 @@ func (s *toolsSuite) TestRequiresPOST(c *gc.C) {
 	s.assertErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "PUT"`)
 }
 
+func (s *toolsSuite) TestRequiresWriteAccess(c *gc.C) {
+	tag, password := s.makeEnvUser(c, state.EnvironmentReadAccess)
+	resp, err := s.sendRequest(c, tag, password, "POST", s.toolsURI(c, ""), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusForbidden, "permission denied")
+}
+
 func (s *toolsSuite) TestAuthRequiresUser(c *gc.C) {
 	// Add a machine and try to login.
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
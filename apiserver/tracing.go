@@ -0,0 +1,101 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/juju/juju/tracing"
+)
+
+// tracers holds the tracer which exports the spans of traced API
+// requests to the trace endpoint currently in use, so that all
+// connections share it. Tracers for endpoints no longer in use are
+// closed.
+type tracers struct {
+	// logDir holds the directory outside which spans are never
+	// written to files.
+	logDir string
+
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	tracers map[string]*tracing.Tracer
+}
+
+func newTracers(logDir string) *tracers {
+	return &tracers{
+		logDir:  logDir,
+		tracers: make(map[string]*tracing.Tracer),
+	}
+}
+
+// get returns the tracer which exports spans to the given endpoint,
+// closing any tracers for other endpoints. It returns nil if the
+// endpoint is empty or the tracer cannot be created, in which case
+// requests are not traced.
+func (t *tracers) get(endpoint string) *tracing.Tracer {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for other, tracer := range t.tracers {
+		if other != endpoint {
+			t.close(other, tracer)
+		}
+	}
+	if endpoint == "" {
+		return nil
+	}
+	if tracer, ok := t.tracers[endpoint]; ok {
+		return tracer
+	}
+	if path, ok := tracing.EndpointFile(endpoint); ok && !withinDir(t.logDir, path) {
+		logger.Warningf("not tracing API requests: trace file %q not in %q", path, t.logDir)
+		return nil
+	}
+	tracer, err := tracing.NewTracer("jujud", endpoint)
+	if err != nil {
+		logger.Warningf("not tracing API requests: %v", err)
+		return nil
+	}
+	t.tracers[endpoint] = tracer
+	return tracer
+}
+
+// close removes the tracer for the given endpoint and closes it in
+// the background, so that exporting its remaining spans does not hold
+// up the caller. It must be called with t.mu held.
+func (t *tracers) close(endpoint string, tracer *tracing.Tracer) {
+	delete(t.tracers, endpoint)
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		if err := tracer.Close(); err != nil {
+			logger.Warningf("cannot close tracer for %q: %v", endpoint, err)
+		}
+	}()
+}
+
+// closeAll exports the spans recorded so far and closes all the
+// tracers.
+func (t *tracers) closeAll() {
+	t.mu.Lock()
+	for endpoint, tracer := range t.tracers {
+		t.close(endpoint, tracer)
+	}
+	t.mu.Unlock()
+	t.wg.Wait()
+}
+
+// withinDir reports whether path names a file inside dir.
+func withinDir(dir, path string) bool {
+	if dir == "" {
+		return false
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// This is an internal package test.
+
+package apiserver
+
+import (
+	"path/filepath"
+
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+)
+
+type tracersSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&tracersSuite{})
+
+func (s *tracersSuite) TestGetSharesTracer(c *gc.C) {
+	logDir := c.MkDir()
+	t := newTracers(logDir)
+	defer t.closeAll()
+	endpoint := filepath.Join(logDir, "trace.json")
+	tracer := t.get(endpoint)
+	c.Assert(tracer, gc.NotNil)
+	c.Assert(t.get(endpoint), gc.Equals, tracer)
+}
+
+func (s *tracersSuite) TestGetClosesUnusedTracers(c *gc.C) {
+	logDir := c.MkDir()
+	t := newTracers(logDir)
+	defer t.closeAll()
+	first := filepath.Join(logDir, "first.json")
+	second := filepath.Join(logDir, "second.json")
+	c.Assert(t.get(first), gc.NotNil)
+	c.Assert(t.get(second), gc.NotNil)
+	c.Assert(t.tracers, gc.HasLen, 1)
+	c.Assert(t.tracers[second], gc.NotNil)
+
+	c.Assert(t.get(""), gc.IsNil)
+	c.Assert(t.tracers, gc.HasLen, 0)
+}
+
+func (s *tracersSuite) TestGetRejectsFileOutsideLogDir(c *gc.C) {
+	logDir := c.MkDir()
+	t := newTracers(logDir)
+	defer t.closeAll()
+	for _, endpoint := range []string{
+		filepath.Join(c.MkDir(), "trace.json"),
+		filepath.Join(logDir, "..", "trace.json"),
+		"file://" + filepath.Join(logDir+"-other", "trace.json"),
+		logDir,
+	} {
+		c.Logf("endpoint %q", endpoint)
+		c.Check(t.get(endpoint), gc.IsNil)
+	}
+	c.Assert(t.tracers, gc.HasLen, 0)
+}
+
+func (s *tracersSuite) TestGetAllowsCollector(c *gc.C) {
+	t := newTracers(c.MkDir())
+	defer t.closeAll()
+	c.Assert(t.get("http://localhost:4318/v1/traces"), gc.NotNil)
+}
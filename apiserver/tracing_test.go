@@ -0,0 +1,117 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	jujutesting "github.com/juju/juju/juju/testing"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/tracing"
+)
+
+type tracingSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&tracingSuite{})
+
+func (s *tracingSuite) TestTracedRequest(c *gc.C) {
+	serverPath := filepath.Join(s.LogDir, "server.json")
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"trace-endpoint": serverPath,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	tracer, err := tracing.NewTracer("juju", filepath.Join(c.MkDir(), "client.json"))
+	c.Assert(err, jc.ErrorIsNil)
+	defer tracer.Close()
+	span := tracer.StartSpan("juju set-env", tracing.SpanKindInternal, tracing.SpanContext{})
+
+	// The trace endpoint is read when a connection logs in.
+	st, err := api.Open(s.APIInfo(c), api.DialOpts{TraceSpan: span})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	err = st.Client().EnvironmentSet(map[string]interface{}{"api-max-watchers": 100})
+	c.Assert(err, jc.ErrorIsNil)
+	span.End()
+
+	// The API server exports the spans of the request, and of the
+	// transactions it ran, in the client's trace.
+	traceId := `"traceId":"` + span.Context().TraceID.String() + `"`
+	expected := []*regexp.Regexp{
+		regexp.MustCompile(`\{` + traceId + `,[^{]*"name":"Client.EnvironmentSet","kind":2,`),
+		regexp.MustCompile(`\{` + traceId + `,[^{]*"name":"state.RunTransaction","kind":1,`),
+	}
+	var data []byte
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		data, err = ioutil.ReadFile(serverPath)
+		c.Assert(err, jc.ErrorIsNil)
+		if matchAll(expected, data) {
+			return
+		}
+	}
+	c.Fatalf("spans not exported; got %s", data)
+}
+
+func (s *tracingSuite) TestTraceFileOutsideLogDir(c *gc.C) {
+	serverPath := filepath.Join(c.MkDir(), "server.json")
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"trace-endpoint": serverPath,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.makeTracedRequest(c, s.APIInfo(c))
+	_, err = os.Stat(serverPath)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *tracingSuite) TestHostedEnvironmentEndpointIgnored(c *gc.C) {
+	envState := s.Factory.MakeEnvironment(c, nil)
+	defer envState.Close()
+	serverPath := filepath.Join(s.LogDir, "hosted.json")
+	err := envState.UpdateEnvironConfig(map[string]interface{}{
+		"trace-endpoint": serverPath,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	info := s.APIInfo(c)
+	info.EnvironTag = envState.EnvironTag()
+	s.makeTracedRequest(c, info)
+	_, err = os.Stat(serverPath)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+// makeTracedRequest makes a traced API request through a connection
+// opened with the given info.
+func (s *tracingSuite) makeTracedRequest(c *gc.C, info *api.Info) {
+	tracer, err := tracing.NewTracer("juju", filepath.Join(c.MkDir(), "client.json"))
+	c.Assert(err, jc.ErrorIsNil)
+	defer tracer.Close()
+	span := tracer.StartSpan("juju get-env", tracing.SpanKindInternal, tracing.SpanContext{})
+	defer span.End()
+
+	st, err := api.Open(info, api.DialOpts{TraceSpan: span})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	_, err = st.Client().EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func matchAll(exprs []*regexp.Regexp, data []byte) bool {
+	for _, expr := range exprs {
+		if !expr.Match(data) {
+			return false
+		}
+	}
+	return true
+}
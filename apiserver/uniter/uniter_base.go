@@ -824,6 +824,7 @@ func (u *uniterBaseAPI) Actions(args params.Entities) (params.ActionsQueryResult
 		results.Results[i].Action.Action = &params.Action{
 			Name:       action.Name(),
 			Parameters: action.Parameters(),
+			Timeout:    action.Timeout(),
 		}
 	}
 
@@ -848,6 +849,12 @@ func (u *uniterBaseAPI) BeginActions(args params.Entities) (params.ErrorResults,
 			continue
 		}
 
+		if action.Status() != state.ActionPending {
+			// The action was cancelled after the unit was told
+			// about it.
+			results.Results[i].Error = common.ServerError(common.ErrActionNotAvailable)
+			continue
+		}
 		_, err = action.Begin()
 		if err != nil {
 			results.Results[i].Error = common.ServerError(err)
@@ -1518,6 +1518,17 @@ func (s *uniterBaseSuite) testBeginActions(c *gc.C, facade beginActions) {
 	c.Assert(ten_seconds_ago.Before(enqueued), jc.IsTrue, gc.Commentf("enqueued time should be after 10 seconds ago"))
 	c.Assert(ten_seconds_ago.Before(started), jc.IsTrue, gc.Commentf("started time should be after 10 seconds ago"))
 	c.Assert(started.After(enqueued) || started.Equal(enqueued), jc.IsTrue, gc.Commentf("started should be after or equal to enqueued time"))
+
+	// An action cancelled before it begins is no longer available.
+	cancelled, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.wordpressUnit.CancelAction(cancelled)
+	c.Assert(err, jc.ErrorIsNil)
+	args = params.Entities{Entities: []params.Entity{{Tag: cancelled.ActionTag().String()}}}
+	res, err = facade.BeginActions(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 1)
+	c.Assert(res.Results[0].Error, jc.Satisfies, params.IsCodeActionNotAvailable)
 }
 
 func (s *uniterBaseSuite) testRelation(
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/juju/utils"
 	"github.com/juju/utils/packaging"
 	"github.com/juju/utils/packaging/config"
 	"github.com/juju/utils/proxy"
@@ -27,10 +28,19 @@ func (cfg *centOSCloudConfig) SetPackageProxy(url string) {
 	cfg.SetAttr("package_proxy", url)
 }
 
-// addPackageProxyCmd is a helper function which returns the corresponding runcmd
-// to apply the package proxy settings on a CentOS machine.
+// yumConfigFile is the path of the yum configuration file, in which
+// the package proxy is set.
+const yumConfigFile = "/etc/yum.conf"
+
+// addPackageProxyCmd is a helper function which returns the corresponding
+// command to apply the package proxy settings on a CentOS machine. Any
+// previously set proxy is replaced, so the command may be run on every
+// boot.
 func addPackageProxyCmd(cfg CloudConfig, url string) string {
-	return fmt.Sprintf("/bin/echo 'proxy=%s' >> /etc/yum.conf", url)
+	return fmt.Sprintf(
+		"sed -i '/^proxy=/d' %s && printf '%%s\\n' %s >> %s",
+		yumConfigFile, utils.ShQuote("proxy="+url), yumConfigFile,
+	)
 }
 
 // UnsetPackageProxy is defined on the PackageProxyConfig interface.
@@ -92,13 +102,15 @@ func (cfg *centOSCloudConfig) PackagePreferences() []packaging.PackagePreference
 // Render is defined on the the Renderer interface.
 func (cfg *centOSCloudConfig) RenderYAML() ([]byte, error) {
 	// Save the fields that we will modify
-	var oldruncmds []string
+	var oldbootcmds, oldruncmds []string
+	oldbootcmds = copyStringSlice(cfg.BootCmds())
 	oldruncmds = copyStringSlice(cfg.RunCmds())
 
-	// check for package proxy setting and add commands:
+	// check for package proxy setting and add commands. The proxy is
+	// set at boot, so that it is used to install packages.
 	var proxy string
 	if proxy = cfg.PackageProxy(); proxy != "" {
-		cfg.AddRunCmd(addPackageProxyCmd(cfg, proxy))
+		cfg.AddBootCmd(addPackageProxyCmd(cfg, proxy))
 		cfg.UnsetPackageProxy()
 	}
 
@@ -125,6 +137,11 @@ func (cfg *centOSCloudConfig) RenderYAML() ([]byte, error) {
 	cfg.SetPackageProxy(proxy)
 	cfg.SetPackageMirror(mirror)
 	cfg.SetAttr("package_sources", srcs)
+	if oldbootcmds != nil {
+		cfg.SetAttr("bootcmd", oldbootcmds)
+	} else {
+		cfg.UnsetAttr("bootcmd")
+	}
 	if oldruncmds != nil {
 		cfg.SetAttr("runcmd", oldruncmds)
 	} else {
@@ -148,6 +165,11 @@ func (cfg *centOSCloudConfig) AddCloudArchiveCloudTools() {
 func (cfg *centOSCloudConfig) getCommandsForAddingPackages() ([]string, error) {
 	var cmds []string
 
+	if proxy := cfg.PackageProxy(); proxy != "" {
+		cmds = append(cmds, LogProgressCmd("Setting yum proxy to %s", proxy))
+		cmds = append(cmds, addPackageProxyCmd(cfg, proxy))
+	}
+
 	if newMirror := cfg.PackageMirror(); newMirror != "" {
 		cmds = append(cmds, LogProgressCmd("Changing package mirror does not yet work on CentOS"))
 		// TODO(bogdanteleaga, aznashwan): This should work after a further PR
@@ -226,9 +248,15 @@ func (cfg *centOSCloudConfig) addRequiredPackages() {
 	}
 }
 
-//TODO(bogdanteleaga, aznashwan): On ubuntu when we render the conf as yaml we
-//have apt_proxy and when we render it as bash we use the equivalent of this.
-//However on centOS even when rendering the YAML we use a helper function
-//addPackageProxyCmds. Research if calling the same is fine.
+// updateProxySettings is defined on the AdvancedPackagingConfig
+// interface. yum has a single proxy setting, which is used for all
+// protocols, so the HTTP proxy is preferred.
 func (cfg *centOSCloudConfig) updateProxySettings(proxySettings proxy.Settings) {
+	url := proxySettings.Http
+	if url == "" {
+		url = proxySettings.Https
+	}
+	if url != "" {
+		cfg.SetPackageProxy(url)
+	}
 }
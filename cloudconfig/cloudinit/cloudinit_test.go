@@ -10,7 +10,9 @@ import (
 
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/packaging"
+	"github.com/juju/utils/proxy"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/yaml.v1"
 
 	"github.com/juju/juju/cloudconfig/cloudinit"
 	coretesting "github.com/juju/juju/testing"
@@ -405,6 +407,28 @@ func (S) TestSetOutput(c *gc.C) {
 	}
 }
 
+func (S) TestCentOSPackageProxy(c *gc.C) {
+	cfg, err := cloudinit.New("centos7")
+	c.Assert(err, jc.ErrorIsNil)
+	cfg.AddPackageCommands(proxy.Settings{Http: "http://10.0.3.1:3142"}, "", false, false)
+	c.Assert(cfg.PackageProxy(), gc.Equals, "http://10.0.3.1:3142")
+
+	// The proxy is set at boot, before any packages are installed.
+	expectedCmd := `sed -i '/^proxy=/d' /etc/yum.conf && printf '%s\n' 'proxy=http://10.0.3.1:3142' >> /etc/yum.conf`
+	data, err := cfg.RenderYAML()
+	c.Assert(err, jc.ErrorIsNil)
+	var out map[string]interface{}
+	err = yaml.Unmarshal(data, &out)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out["bootcmd"], jc.DeepEquals, []interface{}{expectedCmd})
+	c.Assert(out["package_proxy"], gc.IsNil)
+	c.Assert(cfg.BootCmds(), gc.HasLen, 0)
+
+	script, err := cfg.RenderScript()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(script, jc.Contains, expectedCmd)
+}
+
 func (S) TestWindowsRender(c *gc.C) {
 	compareOutput := "#ps1_sysnative\r\n\r\npowershell"
 	cfg, err := cloudinit.New("win8")
@@ -8,7 +8,9 @@ import (
 	"bytes"
 	"fmt"
 	"io/ioutil"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -21,8 +23,10 @@ import (
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/version"
 )
 
 var (
@@ -38,7 +42,7 @@ func WriteUserData(
 	networkConfig *container.NetworkConfig,
 	directory string,
 ) (string, error) {
-	userData, err := cloudInitUserData(instanceConfig, networkConfig)
+	userData, err := CloudInitUserData(instanceConfig, networkConfig)
 	if err != nil {
 		logger.Errorf("failed to create user data: %v", err)
 		return "", err
@@ -107,6 +111,86 @@ func GenerateNetworkConfig(networkConfig *container.NetworkConfig) (string, erro
 	return buf.String(), nil
 }
 
+// centOSInterfaceConfigTemplate defines how to render the ifcfg file
+// for a single NIC of a CentOS container.
+const centOSInterfaceConfigTemplate = `{{.InterfaceName | printf "# interface %q"}}
+DEVICE={{.InterfaceName}}
+ONBOOT={{if .NoAutoStart}}no{{else}}yes{{end}}{{if eq .ConfigType "static"}}
+BOOTPROTO=none
+IPADDR={{.Address.Value}}
+PREFIX=32{{range $i, $dns := .DNSServers}}
+DNS{{inc $i}}={{$dns.Value}}{{end}}{{if .DNSSearch}}
+DOMAIN={{.DNSSearch}}{{end}}{{else}}
+BOOTPROTO=dhcp{{end}}
+`
+
+// centOSRouteConfigTemplate defines how to render the route file for
+// a single statically configured NIC of a CentOS container.
+const centOSRouteConfigTemplate = `{{.GatewayAddress.Value}} dev {{.InterfaceName}}
+default via {{.GatewayAddress.Value}} dev {{.InterfaceName}}
+`
+
+var networkScriptsDir = "/etc/sysconfig/network-scripts"
+
+// generateCentOSNetworkConfig renders the ifcfg and route files, keyed
+// by path, for each of the network interfaces in networkConfig.
+func generateCentOSNetworkConfig(networkConfig *container.NetworkConfig) (map[string]string, error) {
+	funcs := template.FuncMap{
+		"inc": func(i int) int { return i + 1 },
+	}
+	ifcfg, err := template.New("ifcfg").Funcs(funcs).Parse(centOSInterfaceConfigTemplate)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot parse interface config template")
+	}
+	route, err := template.New("route").Parse(centOSRouteConfigTemplate)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot parse route config template")
+	}
+	files := make(map[string]string)
+	for _, nic := range networkConfig.Interfaces {
+		var buf bytes.Buffer
+		if err := ifcfg.Execute(&buf, nic); err != nil {
+			return nil, errors.Annotatef(err, "cannot render config for interface %q", nic.InterfaceName)
+		}
+		files[path.Join(networkScriptsDir, "ifcfg-"+nic.InterfaceName)] = buf.String()
+		if nic.ConfigType != network.ConfigStatic || nic.GatewayAddress.Value == "" {
+			continue
+		}
+		buf.Reset()
+		if err := route.Execute(&buf, nic); err != nil {
+			return nil, errors.Annotatef(err, "cannot render routes for interface %q", nic.InterfaceName)
+		}
+		files[path.Join(networkScriptsDir, "route-"+nic.InterfaceName)] = buf.String()
+	}
+	return files, nil
+}
+
+// NetworkConfigFiles returns the contents of the files, keyed by path,
+// which configure the network interfaces in networkConfig on a
+// container of the given series. No files are returned if
+// networkConfig is nil or has no interfaces.
+func NetworkConfigFiles(series string, networkConfig *container.NetworkConfig) (map[string]string, error) {
+	if networkConfig == nil || len(networkConfig.Interfaces) == 0 {
+		logger.Tracef("no network config to generate")
+		return nil, nil
+	}
+	os, err := version.GetOSFromSeries(series)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch os {
+	case version.Ubuntu:
+		config, err := GenerateNetworkConfig(networkConfig)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return map[string]string{networkInterfacesFile: config}, nil
+	case version.CentOS:
+		return generateCentOSNetworkConfig(networkConfig)
+	}
+	return nil, errors.NotSupportedf("network config for series %q", series)
+}
+
 // newCloudInitConfigWithNetworks creates a cloud-init config which
 // might include per-interface networking config if both networkConfig
 // is not nil and its Interfaces field is not empty.
@@ -115,17 +199,28 @@ func newCloudInitConfigWithNetworks(series string, networkConfig *container.Netw
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	config, err := GenerateNetworkConfig(networkConfig)
-	if err != nil || len(config) == 0 {
-		return cloudConfig, errors.Trace(err)
+	files, err := NetworkConfigFiles(series, networkConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
 	}
 
-	// Now add it to cloud-init as a file created early in the boot process.
-	cloudConfig.AddBootTextFile(networkInterfacesFile, config, 0644)
+	// Now add them to cloud-init as files created early in the boot
+	// process.
+	filenames := make([]string, 0, len(files))
+	for filename := range files {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+	for _, filename := range filenames {
+		cloudConfig.AddBootTextFile(filename, files[filename], 0644)
+	}
 	return cloudConfig, nil
 }
 
-func cloudInitUserData(
+// CloudInitUserData generates the cloud-init user-data using the
+// specified machine and network config for a container, and returns
+// its serialized form.
+func CloudInitUserData(
 	instanceConfig *instancecfg.InstanceConfig,
 	networkConfig *container.NetworkConfig,
 ) ([]byte, error) {
@@ -228,13 +323,36 @@ auto eth0
 iface eth0 inet dhcp
 `
 
+// defaultCentOSInterfaceConfig is the contents of the ifcfg-eth0 file
+// which is left on the template LXC container on shutdown on CentOS,
+// for the same reasons as defaultEtcNetworkInterfaces.
+const defaultCentOSInterfaceConfig = `
+DEVICE=eth0
+ONBOOT=yes
+BOOTPROTO=dhcp
+`
+
 func shutdownInitCommands(initSystem, series string) ([]string, error) {
+	os, err := version.GetOSFromSeries(series)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The DHCP client keeps its leases, and the default network
+	// config lives, in different places on each OS.
+	dhcpLeases := "/var/lib/dhcp/dhclient*"
+	netConfFile, netConf := "/etc/network/interfaces", defaultEtcNetworkInterfaces
+	if os == version.CentOS {
+		dhcpLeases = "/var/lib/dhclient/*"
+		netConfFile = path.Join(networkScriptsDir, "ifcfg-eth0")
+		netConf = defaultCentOSInterfaceConfig
+	}
+
 	// These files are removed just before the template shuts down.
 	cleanupOnShutdown := []string{
 		// We remove any dhclient lease files so there's no chance a
 		// clone to reuse a lease from the template it was cloned
 		// from.
-		"/var/lib/dhcp/dhclient*",
+		dhcpLeases,
 		// Both of these sets of files below are recreated on boot and
 		// if we leave them in the template's rootfs boot logs coming
 		// from cloned containers will be appended. It's better to
@@ -245,8 +363,8 @@ func shutdownInitCommands(initSystem, series string) ([]string, error) {
 	// Using EOC below as the template shutdown script is itself
 	// passed through cat > ... < EOF.
 	replaceNetConfCmd := fmt.Sprintf(
-		"/bin/cat > /etc/network/interfaces << EOC%sEOC\n  ",
-		defaultEtcNetworkInterfaces,
+		"/bin/cat > %s << EOC%sEOC\n  ",
+		netConfFile, netConf,
 	)
 	paths := strings.Join(cleanupOnShutdown, " ")
 	removeCmd := fmt.Sprintf("/bin/rm -fr %s\n  ", paths)
@@ -256,8 +374,8 @@ func shutdownInitCommands(initSystem, series string) ([]string, error) {
 
 	execStart := shutdownCmd
 	if environs.AddressAllocationEnabled() {
-		// Only do the cleanup and replacement of /e/n/i when address
-		// allocation feature flag is enabled.
+		// Only do the cleanup and replacement of the network config
+		// when address allocation feature flag is enabled.
 		execStart = replaceNetConfCmd + removeCmd + shutdownCmd
 	}
 
@@ -5,11 +5,13 @@
 package containerinit_test
 
 import (
+	"fmt"
 	"path/filepath"
 	"strings"
 	stdtesting "testing"
 
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/proxy"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/yaml.v1"
 
@@ -22,6 +24,8 @@ import (
 	"github.com/juju/juju/service"
 	systemdtesting "github.com/juju/juju/service/systemd/testing"
 	"github.com/juju/juju/testing"
+	"github.com/juju/juju/tools"
+	"github.com/juju/juju/version"
 )
 
 func Test(t *stdtesting.T) {
@@ -115,6 +119,65 @@ bootcmd:
 	assertUserData(c, cloudConf, expected)
 }
 
+func (s *UserDataSuite) TestNetworkConfigFilesCentOS(c *gc.C) {
+	files, err := containerinit.NetworkConfigFiles("centos7", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, gc.HasLen, 0)
+
+	netConfig := container.BridgeNetworkConfig("foo", 0, s.fakeInterfaces)
+	files, err = containerinit.NetworkConfigFiles("centos7", netConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, jc.DeepEquals, map[string]string{
+		"/etc/sysconfig/network-scripts/ifcfg-eth0": `
+# interface "eth0"
+DEVICE=eth0
+ONBOOT=yes
+BOOTPROTO=none
+IPADDR=0.1.2.3
+PREFIX=32
+DNS1=ns1.invalid
+DNS2=ns2.invalid
+DOMAIN=foo.bar
+`[1:],
+		"/etc/sysconfig/network-scripts/route-eth0": `
+0.1.2.1 dev eth0
+default via 0.1.2.1 dev eth0
+`[1:],
+		"/etc/sysconfig/network-scripts/ifcfg-eth1": `
+# interface "eth1"
+DEVICE=eth1
+ONBOOT=no
+BOOTPROTO=dhcp
+`[1:],
+	})
+}
+
+func (s *UserDataSuite) TestNetworkConfigFilesUbuntu(c *gc.C) {
+	netConfig := container.BridgeNetworkConfig("foo", 0, s.fakeInterfaces)
+	files, err := containerinit.NetworkConfigFiles("quantal", netConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, jc.DeepEquals, map[string]string{
+		s.networkInterfacesFile: s.expectedNetConfig,
+	})
+}
+
+func (s *UserDataSuite) TestNewCloudInitConfigWithNetworksCentOS(c *gc.C) {
+	netConfig := container.BridgeNetworkConfig("foo", 0, s.fakeInterfaces)
+	cloudConf, err := containerinit.NewCloudInitConfigWithNetworks("centos7", netConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	var installed []string
+	for _, cmd := range cloudConf.BootCmds() {
+		if strings.HasPrefix(cmd, "install -D") {
+			installed = append(installed, cmd)
+		}
+	}
+	c.Assert(installed, jc.DeepEquals, []string{
+		"install -D -m 644 /dev/null '/etc/sysconfig/network-scripts/ifcfg-eth0'",
+		"install -D -m 644 /dev/null '/etc/sysconfig/network-scripts/ifcfg-eth1'",
+		"install -D -m 644 /dev/null '/etc/sysconfig/network-scripts/route-eth0'",
+	})
+}
+
 func (s *UserDataSuite) TestNewCloudInitConfigWithNetworksNoConfig(c *gc.C) {
 	netConfig := container.BridgeNetworkConfig("foo", 0, nil)
 	cloudConf, err := containerinit.NewCloudInitConfigWithNetworks("quantal", netConfig)
@@ -134,6 +197,32 @@ func (s *UserDataSuite) TestCloudInitUserData(c *gc.C) {
 	c.Assert(string(data), jc.HasPrefix, "#cloud-config\n")
 }
 
+func (s *UserDataSuite) TestCloudInitUserDataCentOS(c *gc.C) {
+	instanceConfig, err := containertesting.MockMachineConfig("1/lxc/0")
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig.Series = "centos7"
+	instanceConfig.Tools = &tools.Tools{
+		Version: version.MustParseBinary("2.3.4-centos7-amd64"),
+		URL:     "http://tools.testing.invalid/2.3.4-centos7-amd64.tgz",
+	}
+	instanceConfig.AptProxySettings = proxy.Settings{Http: "http://10.0.3.1:3142"}
+	networkConfig := container.BridgeNetworkConfig("foo", 0, s.fakeInterfaces)
+	data, err := containerinit.CloudInitUserData(instanceConfig, networkConfig)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var out map[string]interface{}
+	err = yaml.Unmarshal(data, &out)
+	c.Assert(err, jc.ErrorIsNil)
+	bootcmds := fmt.Sprint(out["bootcmd"])
+	c.Check(bootcmds, jc.Contains, "/etc/sysconfig/network-scripts/ifcfg-eth0")
+	c.Check(bootcmds, jc.Contains, "'proxy=http://10.0.3.1:3142' >> /etc/yum.conf")
+	c.Check(bootcmds, gc.Not(jc.Contains), "/etc/network/interfaces")
+	// The agent is installed as a systemd service.
+	runcmds := fmt.Sprint(out["runcmd"])
+	c.Check(runcmds, jc.Contains, "jujud-machine-1-lxc-0.service")
+	c.Check(runcmds, gc.Not(jc.Contains), "/etc/init/jujud-machine-1-lxc-0.conf")
+}
+
 func assertUserData(c *gc.C, cloudConf cloudinit.CloudConfig, expected string) {
 	data, err := cloudConf.RenderYAML()
 	c.Assert(err, jc.ErrorIsNil)
@@ -187,6 +276,41 @@ end script
 	testing.CheckWriteFileCommand(c, cmds[0], filename, script, nil)
 }
 
+func (s *UserDataSuite) TestShutdownInitCommandsCentOS(c *gc.C) {
+	s.SetFeatureFlags(feature.AddressAllocation)
+	commands, err := containerinit.ShutdownInitCommands(service.InitSystemSystemd, "centos7")
+	c.Assert(err, jc.ErrorIsNil)
+
+	test := systemdtesting.WriteConfTest{
+		Service: "juju-template-restart",
+		DataDir: "/var/lib/juju",
+		Expected: `
+[Unit]
+Description=juju shutdown job
+After=syslog.target
+After=network.target
+After=systemd-user-sessions.service
+After=cloud-config.target
+
+[Service]
+ExecStart=/var/lib/juju/init/juju-template-restart/exec-start.sh
+ExecStopPost=/bin/systemctl disable juju-template-restart.service
+
+[Install]
+WantedBy=multi-user.target
+`[1:],
+		Script: `
+/bin/cat > /etc/sysconfig/network-scripts/ifcfg-eth0 << EOC
+DEVICE=eth0
+ONBOOT=yes
+BOOTPROTO=dhcp
+EOC
+  /bin/rm -fr /var/lib/dhclient/* /var/log/cloud-init*.log
+  /sbin/shutdown -h now`[1:],
+	}
+	test.CheckInstallAndStartCommands(c, commands)
+}
+
 func (s *UserDataSuite) TestShutdownInitCommandsSystemd(c *gc.C) {
 	s.SetFeatureFlags(feature.AddressAllocation)
 	commands, err := containerinit.ShutdownInitCommands(service.InitSystemSystemd, "vivid")
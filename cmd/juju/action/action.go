@@ -17,8 +17,8 @@ import (
 
 var actionDoc = `
 "juju action" executes and manages actions on units; it queues up new actions,
-monitors the status of running actions, and retrieves the results of completed
-actions.
+monitors the status of running actions, cancels queued or running actions, and
+retrieves the results of completed actions.
 `
 
 var actionPurpose = "execute, manage, monitor, and retrieve results of actions"
@@ -32,6 +32,7 @@ func NewSuperCommand() cmd.Command {
 			UsagePrefix: "juju",
 			Purpose:     actionPurpose,
 		})
+	actionCmd.Register(envcmd.Wrap(&CancelCommand{}))
 	actionCmd.Register(envcmd.Wrap(&DefinedCommand{}))
 	actionCmd.Register(envcmd.Wrap(&DoCommand{}))
 	actionCmd.Register(envcmd.Wrap(&FetchCommand{}))
@@ -49,6 +50,15 @@ type APIClient interface {
 	// Action.
 	Enqueue(params.Actions) (params.ActionResults, error)
 
+	// EnqueueServiceActions takes a list of ServiceActions and queues
+	// each up to be executed by every unit of the designated service,
+	// returning the operation that tracks the progress of each.
+	EnqueueServiceActions(params.ServiceActions) (params.ActionOperationResults, error)
+
+	// ActionOperations takes a list of action operation ids, or unique
+	// prefixes of them, and returns the progress of each operation.
+	ActionOperations(params.ActionOperationIds) (params.ActionOperationResults, error)
+
 	// ListAll takes a list of Tags representing ActionReceivers and returns
 	// all of the Actions that have been queued or run by each of those
 	// Entities.
@@ -64,8 +74,9 @@ type APIClient interface {
 	// Entities.
 	ListCompleted(params.Entities) (params.ActionsByReceivers, error)
 
-	// Cancel attempts to cancel a queued up Action from running.
-	Cancel(params.Actions) (params.ActionResults, error)
+	// Cancel attempts to cancel the Actions with the given tags,
+	// stopping any that are already running.
+	Cancel(params.Entities) (params.ActionResults, error)
 
 	// ServiceCharmActions is a single query which uses ServicesCharmActions to
 	// get the charm.Actions for a single Service by tag.
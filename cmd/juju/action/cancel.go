@@ -0,0 +1,75 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// CancelCommand cancels queued or running Actions by ID.
+type CancelCommand struct {
+	ActionCommandBase
+	out          cmd.Output
+	requestedIds []string
+}
+
+const cancelDoc = `
+Cancel the Actions matching the given IDs or partial ID prefixes. Each prefix
+must match exactly one Action.
+
+Actions that have not yet started will not be run. Actions that are running
+are stopped, and any processes they started are killed.
+`
+
+// Set up the output.
+func (c *CancelCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+}
+
+func (c *CancelCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "cancel",
+		Args:    "<action ID>|<action ID prefix> [...]",
+		Purpose: "cancel queued or running actions",
+		Doc:     cancelDoc,
+	}
+}
+
+func (c *CancelCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no action ID specified")
+	}
+	c.requestedIds = args
+	return nil
+}
+
+func (c *CancelCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	entities := []params.Entity{}
+	for _, prefix := range c.requestedIds {
+		tag, err := getActionTagByPrefix(api, prefix)
+		if err != nil {
+			return err
+		}
+		entities = append(entities, params.Entity{tag.String()})
+	}
+
+	results, err := api.Cancel(params.Entities{Entities: entities})
+	if err != nil {
+		return err
+	}
+	if len(results.Results) != len(entities) {
+		return errors.New("illegal number of results returned")
+	}
+	return c.out.Write(ctx, resultsToMap(results.Results))
+}
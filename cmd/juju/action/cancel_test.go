@@ -0,0 +1,75 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"bytes"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/testing"
+)
+
+type CancelSuite struct {
+	BaseActionSuite
+	subcommand *action.CancelCommand
+}
+
+var _ = gc.Suite(&CancelSuite{})
+
+func (s *CancelSuite) SetUpTest(c *gc.C) {
+	s.BaseActionSuite.SetUpTest(c)
+	s.subcommand = &action.CancelCommand{}
+}
+
+func (s *CancelSuite) TestHelp(c *gc.C) {
+	s.checkHelp(c, s.subcommand)
+}
+
+func (s *CancelSuite) TestInit(c *gc.C) {
+	_, err := testing.RunCommand(c, &action.CancelCommand{})
+	c.Assert(err, gc.ErrorMatches, "no action ID specified")
+}
+
+func (s *CancelSuite) TestRun(c *gc.C) {
+	prefix := "deadbeef"
+	faketag := "action-" + prefix + "-0000-4000-8000-feedfacebeef"
+	fakeClient := &fakeAPIClient{
+		actionTagMatches: tagsForIdPrefix(prefix, faketag),
+		actionResults: []params.ActionResult{{
+			Action: &params.Action{
+				Tag:      faketag,
+				Receiver: "unit-mysql-0",
+			},
+			Status: params.ActionCancelled,
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, &action.CancelCommand{}, "--format", "yaml", prefix)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fakeClient.cancelled, jc.DeepEquals, params.Entities{
+		Entities: []params.Entity{{Tag: faketag}},
+	})
+	c.Check(ctx.Stdout.(*bytes.Buffer).String(), gc.Equals, `
+actions:
+- id: deadbeef-0000-4000-8000-feedfacebeef
+  status: cancelled
+  unit: mysql/0
+`[1:])
+}
+
+func (s *CancelSuite) TestRunNotFound(c *gc.C) {
+	fakeClient := &fakeAPIClient{actionTagMatches: tagsForIdPrefix("deadbeef")}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	_, err := testing.RunCommand(c, &action.CancelCommand{}, "deadbeef")
+	c.Assert(err, gc.ErrorMatches, `actions for identifier "deadbeef" not found`)
+	c.Check(fakeClient.cancelled.Entities, gc.HasLen, 0)
+}
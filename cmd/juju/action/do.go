@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -19,22 +20,38 @@ import (
 
 var keyRule = regexp.MustCompile("^[a-z0-9](?:[a-z0-9-]*[a-z0-9])?$")
 
-// DoCommand enqueues an Action for running on the given unit with given
-// params
+// DoCommand enqueues an Action for running on the given unit, or on
+// every unit of the given service, with given params
 type DoCommand struct {
 	ActionCommandBase
-	unitTag      names.UnitTag
-	actionName   string
-	paramsYAML   cmd.FileVar
-	parseStrings bool
-	out          cmd.Output
-	args         [][]string
+	unitTag       names.UnitTag
+	serviceTag    names.ServiceTag
+	actionName    string
+	paramsYAML    cmd.FileVar
+	parseStrings  bool
+	batchSize     int
+	stopOnFailure bool
+	at            string
+	scheduled     *time.Time
+	timeout       time.Duration
+	out           cmd.Output
+	args          [][]string
 }
 
 const doDoc = `
 Queue an Action for execution on a given unit, with a given set of params.
 Displays the ID of the Action for use with 'juju kill', 'juju status', etc.
 
+If a service is given instead of a unit, the Action is queued as a single
+operation on every unit of the service.  The operation may run on a limited
+number of units at a time with --batch-size, may stop queueing on further
+units once one has failed with --stop-on-failure, and may be deferred with
+--at, which takes an RFC3339 timestamp or a duration from now.  Displays the
+ID of the operation for use with 'juju action status --operation'.
+
+With --timeout, the Action is stopped and marked as failed if it runs for
+longer than the given duration on any unit.
+
 Params are validated according to the charm for the unit's service.  The 
 valid params can be seen using "juju action defined <service> --schema".
 Params may be in a yaml file which is passed with the --params flag, or they
@@ -97,6 +114,17 @@ $ juju action do sleeper/0 pause time=1000
 $ juju action do sleeper/0 pause --string-args time=1000
 ...
 The value for the "time" param will be the string literal "1000".
+
+$ juju action do mysql backup --batch-size 2 --stop-on-failure
+operation: <ID>
+
+$ juju action do mysql/3 backup --timeout 30m
+...
+The backup will be stopped if it has not finished within 30 minutes.
+
+$ juju action do mysql backup --at 2015-07-01T02:00:00Z
+...
+The backup will be queued on the units of mysql at 2am UTC.
 `
 
 // actionNameRule describes the format an action name must match to be valid.
@@ -107,18 +135,22 @@ func (c *DoCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
 	f.Var(&c.paramsYAML, "params", "path to yaml-formatted params file")
 	f.BoolVar(&c.parseStrings, "string-args", false, "use raw string values of CLI args")
+	f.IntVar(&c.batchSize, "batch-size", 0, "number of service units to run the action on at once (0 for all)")
+	f.BoolVar(&c.stopOnFailure, "stop-on-failure", false, "stop queueing on further service units once one fails")
+	f.DurationVar(&c.timeout, "timeout", 0, "stop the action if it runs for longer than this (0 for no limit)")
+	f.StringVar(&c.at, "at", "", "time at which to start queueing on service units")
 }
 
 func (c *DoCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "do",
-		Args:    "<unit> <action name> [key.key.key...=value]",
+		Args:    "<unit>|<service> <action name> [key.key.key...=value]",
 		Purpose: "queue an action for execution",
 		Doc:     doDoc,
 	}
 }
 
-// Init gets the unit tag, and checks for other correct args.
+// Init gets the unit or service tag, and checks for other correct args.
 func (c *DoCommand) Init(args []string) error {
 	switch len(args) {
 	case 0:
@@ -126,17 +158,27 @@ func (c *DoCommand) Init(args []string) error {
 	case 1:
 		return errors.New("no action specified")
 	default:
-		// Grab and verify the unit and action names.
-		unitName := args[0]
-		if !names.IsValidUnit(unitName) {
-			return errors.Errorf("invalid unit name %q", unitName)
+		// Grab and verify the unit or service and action names.
+		receiverName := args[0]
+		switch {
+		case names.IsValidUnit(receiverName):
+			c.unitTag = names.NewUnitTag(receiverName)
+		case names.IsValidService(receiverName):
+			c.serviceTag = names.NewServiceTag(receiverName)
+		default:
+			return errors.Errorf("invalid unit or service name %q", receiverName)
 		}
 		actionName := args[1]
 		if valid := actionNameRule.MatchString(actionName); !valid {
 			return fmt.Errorf("invalid action name %q", actionName)
 		}
-		c.unitTag = names.NewUnitTag(unitName)
 		c.actionName = actionName
+		if c.timeout < 0 {
+			return errors.Errorf("invalid timeout %v", c.timeout)
+		}
+		if err := c.initOperation(); err != nil {
+			return err
+		}
 		if len(args) == 2 {
 			return nil
 		}
@@ -161,6 +203,33 @@ func (c *DoCommand) Init(args []string) error {
 	}
 }
 
+// initOperation checks the flags that only apply when queueing an
+// action on a service.
+func (c *DoCommand) initOperation() error {
+	if c.serviceTag.Id() == "" {
+		if c.batchSize != 0 || c.stopOnFailure || c.at != "" {
+			return errors.New("--batch-size, --stop-on-failure and --at only apply to services")
+		}
+		return nil
+	}
+	if c.batchSize < 0 {
+		return errors.Errorf("invalid batch size %d", c.batchSize)
+	}
+	if c.at == "" {
+		return nil
+	}
+	scheduled, err := time.Parse(time.RFC3339, c.at)
+	if err != nil {
+		d, derr := time.ParseDuration(c.at)
+		if derr != nil || d < 0 {
+			return errors.Errorf("invalid --at value %q: expected an RFC3339 timestamp or a duration", c.at)
+		}
+		scheduled = time.Now().Add(d)
+	}
+	c.scheduled = &scheduled
+	return nil
+}
+
 func (c *DoCommand) Run(ctx *cmd.Context) error {
 	api, err := c.NewActionAPIClient()
 	if err != nil {
@@ -221,11 +290,16 @@ func (c *DoCommand) Run(ctx *cmd.Context) error {
 		return errors.Errorf("params must be a map, got %T", typedConformantParams)
 	}
 
+	if c.serviceTag.Id() != "" {
+		return c.enqueueOperation(ctx, api, actionParams)
+	}
+
 	actionParam := params.Actions{
 		Actions: []params.Action{{
 			Receiver:   c.unitTag.String(),
 			Name:       c.actionName,
 			Parameters: actionParams,
+			Timeout:    c.timeout,
 		}},
 	}
 
@@ -255,3 +329,34 @@ func (c *DoCommand) Run(ctx *cmd.Context) error {
 	output := map[string]string{"Action queued with id": tag.Id()}
 	return c.out.Write(ctx, output)
 }
+
+// enqueueOperation queues the action on the units of the service as a
+// single operation, and reports the id of the operation.
+func (c *DoCommand) enqueueOperation(ctx *cmd.Context, api APIClient, actionParams map[string]interface{}) error {
+	results, err := api.EnqueueServiceActions(params.ServiceActions{
+		Actions: []params.ServiceAction{{
+			Service:       c.serviceTag.String(),
+			Name:          c.actionName,
+			Parameters:    actionParams,
+			BatchSize:     c.batchSize,
+			StopOnFailure: c.stopOnFailure,
+			Scheduled:     c.scheduled,
+			Timeout:       c.timeout,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if len(results.Results) != 1 {
+		return errors.New("illegal number of results returned")
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.Id == "" {
+		return errors.New("action failed to enqueue")
+	}
+	output := map[string]string{"Operation queued with id": result.Id}
+	return c.out.Write(ctx, output)
+}
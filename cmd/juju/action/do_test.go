@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/juju/names"
@@ -82,7 +83,15 @@ func (s *DoSuite) TestInit(c *gc.C) {
 	}, {
 		should:      "fail with invalid unit tag",
 		args:        []string{invalidUnitId, "valid-action-name"},
-		expectError: "invalid unit name \"something-strange-\"",
+		expectError: "invalid unit or service name \"something-strange-\"",
+	}, {
+		should:      "fail with service-only flags on a unit",
+		args:        []string{validUnitId, "valid-action-name", "--batch-size", "2"},
+		expectError: "--batch-size, --stop-on-failure and --at only apply to services",
+	}, {
+		should:      "fail with negative timeout",
+		args:        []string{validUnitId, "valid-action-name", "--timeout", "-1s"},
+		expectError: "invalid timeout -1s",
 	}, {
 		should:      "fail with invalid action name",
 		args:        []string{validUnitId, "BadName"},
@@ -398,3 +407,76 @@ func (s *DoSuite) TestRun(c *gc.C) {
 		}()
 	}
 }
+
+func (s *DoSuite) TestInitService(c *gc.C) {
+	s.subcommand = &action.DoCommand{}
+	err := testing.InitCommand(s.subcommand, []string{
+		validServiceId, "valid-action-name", "--batch-size", "2", "--stop-on-failure", "--at", "2015-07-01T02:00:00Z",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.subcommand.ServiceTag(), gc.Equals, names.NewServiceTag(validServiceId))
+	c.Check(s.subcommand.UnitTag(), gc.Equals, names.UnitTag{})
+	c.Check(s.subcommand.ActionName(), gc.Equals, "valid-action-name")
+	c.Check(s.subcommand.BatchSize(), gc.Equals, 2)
+	c.Check(s.subcommand.StopOnFailure(), jc.IsTrue)
+	c.Assert(s.subcommand.Scheduled(), gc.NotNil)
+	c.Check(s.subcommand.Scheduled().Equal(time.Date(2015, 7, 1, 2, 0, 0, 0, time.UTC)), jc.IsTrue)
+
+	s.subcommand = &action.DoCommand{}
+	before := time.Now()
+	err = testing.InitCommand(s.subcommand, []string{validServiceId, "valid-action-name", "--at", "1h"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.subcommand.Scheduled(), gc.NotNil)
+	c.Check(s.subcommand.Scheduled().Before(before.Add(time.Hour)), jc.IsFalse)
+
+	s.subcommand = &action.DoCommand{}
+	err = testing.InitCommand(s.subcommand, []string{validServiceId, "valid-action-name", "--at", "tomorrow"})
+	c.Assert(err, gc.ErrorMatches, `invalid --at value "tomorrow": expected an RFC3339 timestamp or a duration`)
+
+	s.subcommand = &action.DoCommand{}
+	err = testing.InitCommand(s.subcommand, []string{validServiceId, "valid-action-name", "--batch-size", "-1"})
+	c.Assert(err, gc.ErrorMatches, "invalid batch size -1")
+}
+
+func (s *DoSuite) TestInitTimeout(c *gc.C) {
+	s.subcommand = &action.DoCommand{}
+	err := testing.InitCommand(s.subcommand, []string{validUnitId, "valid-action-name", "--timeout", "90s"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.subcommand.Timeout(), gc.Equals, 90*time.Second)
+}
+
+func (s *DoSuite) TestRunService(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		operationResults: []params.ActionOperationResult{{Id: validActionId}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	s.subcommand = &action.DoCommand{}
+	ctx, err := testing.RunCommand(c, s.subcommand,
+		validServiceId, "some-action", "--batch-size", "1", "--stop-on-failure", "--timeout", "10m", "out.name=bar")
+	c.Assert(err, jc.ErrorIsNil)
+	resultMap := make(map[string]string)
+	err = yaml.Unmarshal(ctx.Stdout.(*bytes.Buffer).Bytes(), &resultMap)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(resultMap, jc.DeepEquals, map[string]string{"Operation queued with id": validActionId})
+	c.Check(fakeClient.EnqueuedActions().Actions, gc.HasLen, 0)
+	c.Check(fakeClient.enqueuedServiceActions, jc.DeepEquals, params.ServiceActions{
+		Actions: []params.ServiceAction{{
+			Service: names.NewServiceTag(validServiceId).String(),
+			Name:    "some-action",
+			Parameters: map[string]interface{}{
+				"out": map[string]interface{}{"name": "bar"},
+			},
+			BatchSize:     1,
+			StopOnFailure: true,
+			Timeout:       10 * time.Minute,
+		}},
+	})
+
+	fakeClient.operationResults = []params.ActionOperationResult{{
+		Error: common.ServerError(errors.New(`service "mysql" has no units`)),
+	}}
+	_, err = testing.RunCommand(c, &action.DoCommand{}, validServiceId, "some-action")
+	c.Assert(err, gc.ErrorMatches, `service "mysql" has no units`)
+}
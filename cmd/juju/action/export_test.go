@@ -4,6 +4,8 @@
 package action
 
 import (
+	"time"
+
 	"github.com/juju/names"
 
 	"github.com/juju/juju/apiserver/params"
@@ -25,6 +27,26 @@ func (c *DoCommand) UnitTag() names.UnitTag {
 	return c.unitTag
 }
 
+func (c *DoCommand) ServiceTag() names.ServiceTag {
+	return c.serviceTag
+}
+
+func (c *DoCommand) BatchSize() int {
+	return c.batchSize
+}
+
+func (c *DoCommand) StopOnFailure() bool {
+	return c.stopOnFailure
+}
+
+func (c *DoCommand) Scheduled() *time.Time {
+	return c.scheduled
+}
+
+func (c *DoCommand) Timeout() time.Duration {
+	return c.timeout
+}
+
 func (c *DoCommand) ActionName() string {
 	return c.actionName
 }
@@ -129,14 +129,18 @@ func setupValueFile(c *gc.C, dir, filename, value string) string {
 }
 
 type fakeAPIClient struct {
-	delay              *time.Timer
-	timeout            *time.Timer
-	actionResults      []params.ActionResult
-	enqueuedActions    params.Actions
-	actionsByReceivers []params.ActionsByReceiver
-	actionTagMatches   params.FindTagsResults
-	charmActions       *charm.Actions
-	apiErr             error
+	delay                  *time.Timer
+	timeout                *time.Timer
+	actionResults          []params.ActionResult
+	enqueuedActions        params.Actions
+	enqueuedServiceActions params.ServiceActions
+	operationResults       []params.ActionOperationResult
+	operationIds           params.ActionOperationIds
+	actionsByReceivers     []params.ActionsByReceiver
+	actionTagMatches       params.FindTagsResults
+	charmActions           *charm.Actions
+	cancelled              params.Entities
+	apiErr                 error
 }
 
 var _ action.APIClient = (*fakeAPIClient)(nil)
@@ -156,6 +160,16 @@ func (c *fakeAPIClient) Enqueue(args params.Actions) (params.ActionResults, erro
 	return params.ActionResults{Results: c.actionResults}, c.apiErr
 }
 
+func (c *fakeAPIClient) EnqueueServiceActions(args params.ServiceActions) (params.ActionOperationResults, error) {
+	c.enqueuedServiceActions = args
+	return params.ActionOperationResults{Results: c.operationResults}, c.apiErr
+}
+
+func (c *fakeAPIClient) ActionOperations(args params.ActionOperationIds) (params.ActionOperationResults, error) {
+	c.operationIds = args
+	return params.ActionOperationResults{Results: c.operationResults}, c.apiErr
+}
+
 func (c *fakeAPIClient) ListAll(args params.Entities) (params.ActionsByReceivers, error) {
 	return params.ActionsByReceivers{
 		Actions: c.actionsByReceivers,
@@ -174,7 +188,8 @@ func (c *fakeAPIClient) ListCompleted(args params.Entities) (params.ActionsByRec
 	}, c.apiErr
 }
 
-func (c *fakeAPIClient) Cancel(args params.Actions) (params.ActionResults, error) {
+func (c *fakeAPIClient) Cancel(args params.Entities) (params.ActionResults, error) {
+	c.cancelled = args
 	return params.ActionResults{
 		Results: c.actionResults,
 	}, c.apiErr
@@ -17,15 +17,20 @@ type StatusCommand struct {
 	ActionCommandBase
 	out         cmd.Output
 	requestedId string
+	operationId string
 }
 
 const statusDoc = `
 Show the status of Actions matching given ID, partial ID prefix, or all Actions if no ID is supplied.
+
+With --operation, show the status of the operation, queued by 'juju action do <service>',
+matching the given ID or partial ID prefix, along with the status of the Action on each unit.
 `
 
 // Set up the output.
 func (c *StatusCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.StringVar(&c.operationId, "operation", "", "show the status of the operation with this ID or ID prefix")
 }
 
 func (c *StatusCommand) Info() *cmd.Info {
@@ -38,6 +43,9 @@ func (c *StatusCommand) Info() *cmd.Info {
 }
 
 func (c *StatusCommand) Init(args []string) error {
+	if c.operationId != "" {
+		return cmd.CheckEmpty(args)
+	}
 	switch len(args) {
 	case 0:
 		c.requestedId = ""
@@ -57,6 +65,10 @@ func (c *StatusCommand) Run(ctx *cmd.Context) error {
 	}
 	defer api.Close()
 
+	if c.operationId != "" {
+		return c.operationStatus(ctx, api)
+	}
+
 	actionTags, err := getActionTagsByPrefix(api, c.requestedId)
 	if err != nil {
 		return err
@@ -87,6 +99,22 @@ func (c *StatusCommand) Run(ctx *cmd.Context) error {
 	return c.out.Write(ctx, resultsToMap(actions.Results))
 }
 
+// operationStatus shows the status of the requested operation.
+func (c *StatusCommand) operationStatus(ctx *cmd.Context, api APIClient) error {
+	results, err := api.ActionOperations(params.ActionOperationIds{Ids: []string{c.operationId}})
+	if err != nil {
+		return err
+	}
+	if len(results.Results) != 1 {
+		return errors.New("illegal number of results returned")
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return result.Error
+	}
+	return c.out.Write(ctx, operationToMap(result))
+}
+
 func resultsToMap(results []params.ActionResult) map[string]interface{} {
 	items := []map[string]interface{}{}
 	for _, item := range results {
@@ -119,3 +147,38 @@ func resultToMap(result params.ActionResult) map[string]interface{} {
 	item["status"] = result.Status
 	return item
 }
+
+func operationToMap(result params.ActionOperationResult) map[string]interface{} {
+	item := map[string]interface{}{
+		"id":     result.Id,
+		"action": result.Name,
+		"status": result.Status,
+	}
+	stag, err := names.ParseServiceTag(result.Service)
+	if err != nil {
+		item["service"] = result.Service
+	} else {
+		item["service"] = stag.Id()
+	}
+	units := []map[string]interface{}{}
+	for _, unit := range result.Units {
+		entry := map[string]interface{}{"status": unit.Status}
+		utag, err := names.ParseUnitTag(unit.Unit)
+		if err != nil {
+			entry["unit"] = unit.Unit
+		} else {
+			entry["unit"] = utag.Id()
+		}
+		if unit.Action != "" {
+			atag, err := names.ParseActionTag(unit.Action)
+			if err != nil {
+				entry["id"] = unit.Action
+			} else {
+				entry["id"] = atag.Id()
+			}
+		}
+		units = append(units, entry)
+	}
+	item["units"] = units
+	return map[string]interface{}{"operation": item}
+}
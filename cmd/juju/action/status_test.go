@@ -70,6 +70,46 @@ func (s *StatusSuite) TestRun(c *gc.C) {
 	}
 }
 
+func (s *StatusSuite) TestRunOperation(c *gc.C) {
+	result := params.ActionOperationResult{
+		Id:      "deadbeef-0000-4000-8000-feedfacebeef",
+		Service: "service-mysql",
+		Name:    "backup",
+		Status:  params.ActionRunning,
+		Units: []params.ActionOperationUnit{{
+			Unit:   "unit-mysql-0",
+			Action: "action-f47ac10b-58cc-4372-a567-0e02b2c3d479",
+			Status: params.ActionCompleted,
+		}, {
+			Unit:   "unit-mysql-1",
+			Status: params.ActionPending,
+		}},
+	}
+	fakeClient := &fakeAPIClient{operationResults: []params.ActionOperationResult{result}}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, &action.StatusCommand{}, "--operation", "deadbeef", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fakeClient.operationIds, jc.DeepEquals, params.ActionOperationIds{Ids: []string{"deadbeef"}})
+	c.Check(ctx.Stdout.(*bytes.Buffer).String(), gc.Equals, `
+operation:
+  action: backup
+  id: deadbeef-0000-4000-8000-feedfacebeef
+  service: mysql
+  status: running
+  units:
+  - id: f47ac10b-58cc-4372-a567-0e02b2c3d479
+    status: completed
+    unit: mysql/0
+  - status: pending
+    unit: mysql/1
+`[1:])
+
+	_, err = testing.RunCommand(c, &action.StatusCommand{}, "--operation", "deadbeef", "deadbeef")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["deadbeef"\]`)
+}
+
 func (s *StatusSuite) runTestCase(c *gc.C, tc statusTestCase) {
 	fakeClient := makeFakeClient(
 		0*time.Second, // No API delay
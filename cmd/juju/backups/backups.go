@@ -15,6 +15,7 @@ import (
 	apiserverbackups "github.com/juju/juju/apiserver/backups"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/environs/config"
 	statebackups "github.com/juju/juju/state/backups"
 )
 
@@ -56,11 +57,12 @@ func NewCommand() cmd.Command {
 type APIClient interface {
 	io.Closer
 	// Create sends an RPC request to create a new backup.
-	Create(notes string) (*params.BackupsMetadataResult, error)
+	Create(notes, target, encryptionKey string) (*params.BackupsMetadataResult, error)
 	// Info gets the backup's metadata.
 	Info(id string) (*params.BackupsMetadataResult, error)
-	// List gets all stored metadata.
-	List() (*params.BackupsListResult, error)
+	// List gets all stored metadata, or only that of scheduled
+	// backups along with the status of the backup schedule.
+	List(scheduled bool) (*params.BackupsListResult, error)
 	// Download pulls the backup archive file.
 	Download(id string) (io.ReadCloser, error)
 	// Upload pushes a backup archive to storage.
@@ -102,6 +104,9 @@ func (c *CommandBase) dumpMetadata(ctx *cmd.Context, result *params.BackupsMetad
 	fmt.Fprintf(ctx.Stdout, "started:         %v\n", result.Started)
 	fmt.Fprintf(ctx.Stdout, "finished:        %v\n", result.Finished)
 	fmt.Fprintf(ctx.Stdout, "notes:           %q\n", result.Notes)
+	fmt.Fprintf(ctx.Stdout, "stored in:       %s\n", backupTarget(result.Target))
+	fmt.Fprintf(ctx.Stdout, "scheduled:       %v\n", result.Scheduled)
+	fmt.Fprintf(ctx.Stdout, "encrypted to:    %s\n", encryptionKey(result.KeyFingerprint))
 
 	fmt.Fprintf(ctx.Stdout, "environment ID:  %q\n", result.Environment)
 	fmt.Fprintf(ctx.Stdout, "machine ID:      %q\n", result.Machine)
@@ -109,6 +114,25 @@ func (c *CommandBase) dumpMetadata(ctx *cmd.Context, result *params.BackupsMetad
 	fmt.Fprintf(ctx.Stdout, "juju version:    %v\n", result.Version)
 }
 
+// encryptionKey returns the fingerprint of the OpenPGP key to which
+// the archive is encrypted, or "(not encrypted)".
+func encryptionKey(fingerprint string) string {
+	if fingerprint == "" {
+		return "(not encrypted)"
+	}
+	return fingerprint
+}
+
+// backupTarget returns the name of the backup target in which the
+// archive is stored. Archives created before backup targets were
+// introduced have no recorded target, and are stored in state.
+func backupTarget(target string) string {
+	if target == "" {
+		return config.BackupTargetState
+	}
+	return target
+}
+
 func getArchive(filename string) (rc io.ReadCloser, metaResult *params.BackupsMetadataResult, err error) {
 	defer func() {
 		if err != nil && rc != nil {
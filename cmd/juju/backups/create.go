@@ -6,6 +6,7 @@ package backups
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"time"
 
@@ -27,12 +28,26 @@ const createDoc = `
 backup's unique ID.  You may provide a note to associate with the backup.
 
 The backup archive and associated metadata are stored remotely by juju.
+The archive is stored in the environment's default backup target (see
+the "backup-target" environment setting) unless the --target option is
+given. The available targets are:
+
+    state   the state server's database (always available)
+    local   a directory on the state server ("backup-local-dir")
+    s3      an S3-compatible object store ("backup-s3-*")
+    sftp    a directory on a remote host ("backup-sftp-*")
+
+The archive is encrypted to the OpenPGP public key in the file given
+with the --key option, if any, or else to the environment's backup
+encryption key ("backup-encryption-key"), if it is set. Encrypted
+archives are stored and downloaded encrypted, and may only be restored
+with the matching private key (see "juju backups restore --key").
 
 The --download option may be used without the --filename option.  In
 that case, the backup archive will be stored in the current working
 directory with a name matching juju-backup-<date>-<time>.tar.gz.
 
-WARNING: Remotely stored backups will be lost when the environment is
+WARNING: Backups stored in state will be lost when the environment is
 destroyed.  Furthermore, the remotely backup is not guaranteed to be
 available.
 
@@ -53,6 +68,11 @@ type CreateCommand struct {
 	Filename string
 	// Notes is the custom message to associated with the new backup.
 	Notes string
+	// Target is the backup target in which to store the new backup.
+	Target string
+	// KeyFile holds the armored OpenPGP public key to which the new
+	// backup is encrypted.
+	KeyFile string
 }
 
 // Info implements Command.Info.
@@ -70,6 +90,8 @@ func (c *CreateCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.Quiet, "quiet", false, "do not print the metadata")
 	f.BoolVar(&c.NoDownload, "no-download", false, "do not download the archive")
 	f.StringVar(&c.Filename, "filename", notset, "download to this file")
+	f.StringVar(&c.Target, "target", "", "store the backup in this backup target")
+	f.StringVar(&c.KeyFile, "key", "", "encrypt the backup to the OpenPGP public key in this file")
 }
 
 // Init implements Command.Init.
@@ -98,7 +120,16 @@ func (c *CreateCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
-	result, err := client.Create(c.Notes)
+	var encryptionKey string
+	if c.KeyFile != "" {
+		data, err := ioutil.ReadFile(c.KeyFile)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		encryptionKey = string(data)
+	}
+
+	result, err := client.Create(c.Notes, c.Target, encryptionKey)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -5,6 +5,9 @@ package backups_test
 
 import (
 	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/juju/cmd"
@@ -122,6 +125,34 @@ func (s *createSuite) TestNotes(c *gc.C) {
 	client.Check(c, s.metaresult.ID, "spam", "Create", "Download")
 }
 
+func (s *createSuite) TestTarget(c *gc.C) {
+	client := s.BaseBackupsSuite.setDownload()
+	_, err := testing.RunCommand(c, s.command, "create", "--target", "s3", "spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	client.Check(c, s.metaresult.ID, "spam", "Create", "Download")
+	c.Check(client.target, gc.Equals, "s3")
+}
+
+func (s *createSuite) TestKey(c *gc.C) {
+	client := s.BaseBackupsSuite.setDownload()
+	keyFile := filepath.Join(c.MkDir(), "backups.asc")
+	err := ioutil.WriteFile(keyFile, []byte("<public key>"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = testing.RunCommand(c, s.command, "create", "--key", keyFile, "spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	client.Check(c, s.metaresult.ID, "spam", "Create", "Download")
+	c.Check(client.encryptionKey, gc.Equals, "<public key>")
+}
+
+func (s *createSuite) TestKeyNotFound(c *gc.C) {
+	s.BaseBackupsSuite.setDownload()
+	keyFile := filepath.Join(c.MkDir(), "backups.asc")
+	_, err := testing.RunCommand(c, s.command, "create", "--key", keyFile)
+	c.Assert(errors.Cause(err), jc.Satisfies, os.IsNotExist)
+}
+
 func (s *createSuite) TestFilename(c *gc.C) {
 	client := s.setDownload()
 	s.subcommand.Filename = "backup.tgz"
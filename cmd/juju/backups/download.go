@@ -20,6 +20,10 @@ const downloadDoc = `
 
 If --filename is not used, the archive is downloaded to a temporary
 location and the filename is printed to stdout.
+
+Encrypted archives are downloaded as they are stored, so they remain
+encrypted. Use "juju backups restore --file <filename> --key <keyfile>"
+to restore from one.
 `
 
 // DownloadCommand is the sub-command for downloading a backup archive.
@@ -5,14 +5,24 @@ package backups
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
 )
 
 const listDoc = `
-"list" provides the metadata associated with all backups.
+"list" provides the metadata associated with all backups, including
+the backup target in which each backup archive is stored.
+
+With --scheduled, only the backups created on the schedule given by
+the backup-schedule environment setting are listed. They are preceded
+by the status of the schedule: when a scheduled backup was last
+attempted and last succeeded, and why the last attempt failed, if it
+did.
 `
 
 // ListCommand is the sub-command for listing all available backups.
@@ -20,6 +30,9 @@ type ListCommand struct {
 	CommandBase
 	// Brief means only IDs will be printed.
 	Brief bool
+	// Scheduled means only scheduled backups will be listed, along
+	// with the status of the backup schedule.
+	Scheduled bool
 }
 
 // Info implements Command.Info.
@@ -35,6 +48,7 @@ func (c *ListCommand) Info() *cmd.Info {
 // SetFlags implements Command.SetFlags.
 func (c *ListCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.Brief, "brief", false, "only print IDs")
+	f.BoolVar(&c.Scheduled, "scheduled", false, "only list scheduled backups, after the status of the schedule")
 }
 
 // Init implements Command.Init.
@@ -53,11 +67,16 @@ func (c *ListCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
-	result, err := client.List()
+	result, err := client.List(c.Scheduled)
 	if err != nil {
 		return errors.Trace(err)
 	}
 
+	if result.Schedule != nil && !c.Brief {
+		dumpScheduleStatus(ctx, result.Schedule)
+		fmt.Fprintln(ctx.Stdout)
+	}
+
 	if len(result.List) == 0 {
 		fmt.Fprintln(ctx.Stdout, "(no backups found)")
 		return nil
@@ -78,3 +97,25 @@ func (c *ListCommand) Run(ctx *cmd.Context) error {
 	}
 	return nil
 }
+
+// dumpScheduleStatus writes the formatted status of the backup
+// schedule to stdout.
+func dumpScheduleStatus(ctx *cmd.Context, status *params.BackupsScheduleStatus) {
+	schedule := status.Schedule
+	if schedule == "" {
+		schedule = "(not set)"
+	}
+	fmt.Fprintf(ctx.Stdout, "schedule:        %s\n", schedule)
+	fmt.Fprintf(ctx.Stdout, "last attempt:    %s\n", formatScheduleTime(status.LastAttempt))
+	fmt.Fprintf(ctx.Stdout, "last success:    %s\n", formatScheduleTime(status.LastSuccess))
+	if status.LastError != "" {
+		fmt.Fprintf(ctx.Stdout, "last error:      %q\n", status.LastError)
+	}
+}
+
+func formatScheduleTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.String()
+}
@@ -5,12 +5,14 @@ package backups_test
 
 import (
 	"strings"
+	"time"
 
 	"github.com/juju/cmd/cmdtesting"
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/backups"
 	"github.com/juju/juju/testing"
 )
@@ -51,6 +53,17 @@ func (s *listSuite) TestOkay(c *gc.C) {
 	s.checkStd(c, ctx, out, "")
 }
 
+func (s *listSuite) TestTarget(c *gc.C) {
+	s.metaresult.Target = "sftp"
+	s.setSuccess()
+	ctx := cmdtesting.Context(c)
+	err := s.subcommand.Run(ctx)
+	c.Check(err, jc.ErrorIsNil)
+
+	out := strings.Replace(MetaResultString, "stored in:       state", "stored in:       sftp", 1)
+	s.checkStd(c, ctx, out, "")
+}
+
 func (s *listSuite) TestBrief(c *gc.C) {
 	s.setSuccess()
 	s.subcommand.Brief = true
@@ -69,3 +82,43 @@ func (s *listSuite) TestError(c *gc.C) {
 
 	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
 }
+
+func (s *listSuite) TestScheduled(c *gc.C) {
+	s.metaresult.Scheduled = true
+	client := s.setSuccess()
+	client.schedule = &params.BackupsScheduleStatus{
+		Schedule:    "@daily",
+		LastAttempt: time.Date(2015, 7, 2, 0, 0, 0, 0, time.UTC),
+		LastSuccess: time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC),
+		LastError:   "creating backup: disk full",
+	}
+	ctx, err := testing.RunCommand(c, s.command, "list", "--scheduled")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(client.scheduled, jc.IsTrue)
+
+	out := `
+schedule:        @daily
+last attempt:    2015-07-02 00:00:00 +0000 UTC
+last success:    2015-07-01 00:00:00 +0000 UTC
+last error:      "creating backup: disk full"
+
+`[1:] + strings.Replace(MetaResultString, "scheduled:       false", "scheduled:       true", 1)
+	s.checkStd(c, ctx, out, "")
+}
+
+func (s *listSuite) TestScheduledNeverRun(c *gc.C) {
+	client := s.setSuccess()
+	client.schedule = &params.BackupsScheduleStatus{}
+	s.subcommand.Scheduled = true
+	ctx := cmdtesting.Context(c)
+	err := s.subcommand.Run(ctx)
+	c.Check(err, jc.ErrorIsNil)
+
+	out := `
+schedule:        (not set)
+last attempt:    never
+last success:    never
+
+`[1:] + MetaResultString
+	s.checkStd(c, ctx, out, "")
+}
@@ -32,6 +32,9 @@ stored:          0001-01-01 00:00:00 +0000 UTC
 started:         0001-01-01 00:00:00 +0000 UTC
 finished:        0001-01-01 00:00:00 +0000 UTC
 notes:           ""
+stored in:       state
+scheduled:       false
+encrypted to:    (not encrypted)
 environment ID:  ""
 machine ID:      ""
 created on host: ""
@@ -120,10 +123,14 @@ type fakeAPIClient struct {
 	archive    io.ReadCloser
 	err        error
 
-	calls []string
-	args  []string
-	idArg string
-	notes string
+	calls         []string
+	args          []string
+	idArg         string
+	notes         string
+	target        string
+	encryptionKey string
+	scheduled     bool
+	schedule      *params.BackupsScheduleStatus
 }
 
 func (f *fakeAPIClient) Check(c *gc.C, id, notes string, calls ...string) {
@@ -132,10 +139,12 @@ func (f *fakeAPIClient) Check(c *gc.C, id, notes string, calls ...string) {
 	c.Check(f.notes, gc.Equals, notes)
 }
 
-func (c *fakeAPIClient) Create(notes string) (*params.BackupsMetadataResult, error) {
+func (c *fakeAPIClient) Create(notes, target, encryptionKey string) (*params.BackupsMetadataResult, error) {
 	c.calls = append(c.calls, "Create")
-	c.args = append(c.args, "notes")
+	c.args = append(c.args, "notes", "target", "encryptionKey")
 	c.notes = notes
+	c.target = target
+	c.encryptionKey = encryptionKey
 	if c.err != nil {
 		return nil, c.err
 	}
@@ -152,13 +161,18 @@ func (c *fakeAPIClient) Info(id string) (*params.BackupsMetadataResult, error) {
 	return c.metaresult, nil
 }
 
-func (c *fakeAPIClient) List() (*params.BackupsListResult, error) {
+func (c *fakeAPIClient) List(scheduled bool) (*params.BackupsListResult, error) {
 	c.calls = append(c.calls, "List")
+	c.args = append(c.args, "scheduled")
+	c.scheduled = scheduled
 	if c.err != nil {
 		return nil, c.err
 	}
 	var result params.BackupsListResult
 	result.List = []params.BackupsMetadataResult{*c.metaresult}
+	if scheduled {
+		result.Schedule = c.schedule
+	}
 	return &result, nil
 }
 
@@ -5,6 +5,9 @@ package backups
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -19,6 +22,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/bootstrap"
 	"github.com/juju/juju/environs/configstore"
+	statebackups "github.com/juju/juju/state/backups"
 )
 
 // RestoreCommand is a subcommand of backups that implement the restore behaior
@@ -28,6 +32,7 @@ type RestoreCommand struct {
 	constraints constraints.Value
 	filename    string
 	backupId    string
+	keyFile     string
 	bootstrap   bool
 }
 
@@ -47,6 +52,12 @@ If the provided state cannot be restored, this command will fail with
 an appropriate message.  For instance, if the existing bootstrap
 instance is already running then the command will fail with a message
 to that effect.
+
+Encrypted backups are decrypted with the armored OpenPGP private key
+in the file given with --key.  The archive is decrypted locally, so
+the private key is never sent to the state server; a backup stored
+remotely is downloaded first.  Private keys protected by a passphrase
+are not supported.
 `
 
 // Info returns the content for --help.
@@ -67,6 +78,7 @@ func (c *RestoreCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.bootstrap, "b", false, "bootstrap a new state machine")
 	f.StringVar(&c.filename, "file", "", "provide a file to be used as the backup.")
 	f.StringVar(&c.backupId, "id", "", "provide the name of the backup to be restored.")
+	f.StringVar(&c.keyFile, "key", "", "decrypt the backup with the OpenPGP private key in this file.")
 }
 
 // Init is where the preconditions for this commands can be checked.
@@ -101,11 +113,21 @@ func (c *RestoreCommand) runRestore(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 	defer closer()
-	var target string
+	target := c.filename
+	if target == "" {
+		target = c.backupId
+	}
+	filename := c.filename
+	if c.keyFile != "" {
+		filename, err = c.decryptArchive(client)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer os.Remove(filename)
+	}
 	var rErr error
-	if c.filename != "" {
-		target = c.filename
-		archive, meta, err := getArchive(c.filename)
+	if filename != "" {
+		archive, meta, err := getArchive(filename)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -113,7 +135,6 @@ func (c *RestoreCommand) runRestore(ctx *cmd.Context) error {
 
 		rErr = client.RestoreReader(archive, meta, c.newClient)
 	} else {
-		target = c.backupId
 		rErr = client.Restore(c.backupId, c.newClient)
 	}
 	if params.IsCodeNotImplemented(rErr) {
@@ -127,6 +148,47 @@ func (c *RestoreCommand) runRestore(ctx *cmd.Context) error {
 	return nil
 }
 
+// decryptArchive decrypts the backup archive, read from the given file
+// or downloaded from the state server, into a temporary file and
+// returns its name.
+func (c *RestoreCommand) decryptArchive(client *backups.Client) (_ string, err error) {
+	privateKey, err := ioutil.ReadFile(c.keyFile)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	var encrypted io.ReadCloser
+	if c.filename != "" {
+		encrypted, err = os.Open(c.filename)
+	} else {
+		encrypted, err = client.Download(c.backupId)
+	}
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer encrypted.Close()
+	plaintext, err := statebackups.DecryptArchive(encrypted, string(privateKey))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	decrypted, err := ioutil.TempFile("", "juju-restore-")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer func() {
+		if closeErr := decrypted.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(decrypted.Name())
+		}
+	}()
+	if _, err := io.Copy(decrypted, plaintext); err != nil {
+		return "", errors.Annotate(err, "cannot decrypt backup archive")
+	}
+	return decrypted.Name(), nil
+}
+
 // rebootstrap will bootstrap a new server in safe-mode (not killing any other agent)
 // if there is no current server available to restore to.
 func (c *RestoreCommand) rebootstrap(ctx *cmd.Context) error {
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/juju/cmd"
 	"launchpad.net/gnuflag"
 
@@ -14,7 +15,10 @@ import (
 )
 
 const ListCommandDoc = `
-List cached os images in the Juju environment.
+List cached os images in the Juju environment, with their size and
+when they were last used to create a container. Images which have not
+been used for longer than the environment's image-cache-max-age are
+removed automatically.
 
 Images can be filtered on:
   Kind         eg "lxc"
@@ -84,6 +88,8 @@ type ImageInfo struct {
 	Arch      string `yaml:"arch" json:"arch"`
 	SourceURL string `yaml:"source-url" json:"source-url"`
 	Created   string `yaml:"created" json:"created"`
+	LastUsed  string `yaml:"last-used,omitempty" json:"last-used,omitempty"`
+	Size      string `yaml:"size" json:"size"`
 }
 
 func (c *ListCommand) imageMetadataToImageInfo(images []params.ImageMetadata) []ImageInfo {
@@ -95,6 +101,11 @@ func (c *ListCommand) imageMetadataToImageInfo(images []params.ImageMetadata) []
 			Arch:      metadata.Arch,
 			Created:   metadata.Created.Format(time.RFC1123),
 			SourceURL: metadata.URL,
+			Size:      humanize.IBytes(uint64(metadata.Size)),
+		}
+		// Older servers do not record when images were used.
+		if !metadata.LastUsed.IsZero() {
+			imageInfo.LastUsed = metadata.LastUsed.Format(time.RFC1123)
 		}
 		output = append(output, imageInfo)
 	}
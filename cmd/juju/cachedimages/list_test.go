@@ -35,11 +35,13 @@ func (f *fakeImagesListAPI) ListImages(kind, series, arch string) ([]params.Imag
 	}
 	result := []params.ImageMetadata{
 		{
-			Kind:    kind,
-			Series:  series,
-			Arch:    arch,
-			URL:     "http://image",
-			Created: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC),
+			Kind:     kind,
+			Series:   series,
+			Arch:     arch,
+			URL:      "http://image",
+			Created:  time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC),
+			LastUsed: time.Date(2015, 2, 1, 12, 0, 0, 0, time.UTC),
+			Size:     300 * 1024 * 1024,
 		},
 	}
 	return result, nil
@@ -67,7 +69,7 @@ func (*listImagesCommandSuite) TestListImagesFormatJson(c *gc.C) {
 	context, err := runListCommand(c, "--format", "json", "--kind", "lxc", "--series", "trusty", "--arch", "amd64")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(testing.Stdout(context), gc.Equals, "Cached images:\n["+
-		`{"kind":"lxc","series":"trusty","arch":"amd64","source-url":"http://image","created":"Thu, 01 Jan 2015 00:00:00 UTC"}`+
+		`{"kind":"lxc","series":"trusty","arch":"amd64","source-url":"http://image","created":"Thu, 01 Jan 2015 00:00:00 UTC","last-used":"Sun, 01 Feb 2015 12:00:00 UTC","size":"300 MiB"}`+
 		"]\n")
 }
 
@@ -79,7 +81,9 @@ func (*listImagesCommandSuite) TestListImagesFormatYaml(c *gc.C) {
 		"  series: trusty\n"+
 		"  arch: amd64\n"+
 		"  source-url: http://image\n"+
-		"  created: Thu, 01 Jan 2015 00:00:00 UTC\n")
+		"  created: Thu, 01 Jan 2015 00:00:00 UTC\n"+
+		"  last-used: Sun, 01 Feb 2015 12:00:00 UTC\n"+
+		"  size: 300 MiB\n")
 }
 
 func (*listImagesCommandSuite) TestTooManyArgs(c *gc.C) {
@@ -0,0 +1,201 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/auditlog"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const auditLogDoc = `
+Show the audit log of the environment. Every API call made by a user
+which may change the environment is recorded, along with the user who
+made it, the entities it targeted and its outcome.
+
+Records can be filtered by the user who made the call, by an entity
+the call targeted (given as a tag, or as a machine, service or unit
+name) and by time. Times may be given as RFC3339 timestamps
+("2015-07-01T12:00:00Z"), as dates ("2015-07-01"), or as durations
+relative to now ("90m", "24h").
+
+Examples:
+    juju audit-log --user bob --since 24h
+    juju audit-log --entity wordpress/0
+    juju audit-log --since 2015-07-01 --until 2015-07-02 --format json
+`
+
+// AuditLogCommand shows the environment's audit log.
+type AuditLogCommand struct {
+	envcmd.EnvCommandBase
+	out     cmd.Output
+	user    string
+	entity  string
+	since   string
+	until   string
+	limit   int
+	isoTime bool
+	filter  params.AuditRecordFilter
+}
+
+// Info implements Command.Info.
+func (c *AuditLogCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "audit-log",
+		Purpose: "show the audit log of the environment",
+		Doc:     auditLogDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *AuditLogCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.user, "user", "", "only show operations performed by this user")
+	f.StringVar(&c.entity, "entity", "", "only show operations that targeted this entity")
+	f.StringVar(&c.since, "since", "", "only show operations performed at or after this time")
+	f.StringVar(&c.until, "until", "", "only show operations performed at or before this time")
+	f.IntVar(&c.limit, "n", 0, "show at most this many of the most recent operations")
+	f.BoolVar(&c.isoTime, "utc", false, "display time as UTC in RFC3339 format")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": c.formatTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *AuditLogCommand) Init(args []string) error {
+	if c.limit < 0 {
+		return errors.Errorf("invalid number of records %d", c.limit)
+	}
+	c.filter = params.AuditRecordFilter{Limit: c.limit}
+	if c.user != "" {
+		if !names.IsValidUser(c.user) {
+			return errors.NotValidf("user name %q", c.user)
+		}
+		c.filter.User = names.NewUserTag(c.user).String()
+	}
+	if c.entity != "" {
+		tag, err := entityTag(c.entity)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.filter.Entity = tag.String()
+	}
+	now := time.Now()
+	if c.since != "" {
+		since, err := parseTimeArg(c.since, now)
+		if err != nil {
+			return errors.Annotate(err, "invalid --since value")
+		}
+		c.filter.Since = &since
+	}
+	if c.until != "" {
+		until, err := parseTimeArg(c.until, now)
+		if err != nil {
+			return errors.Annotate(err, "invalid --until value")
+		}
+		c.filter.Until = &until
+	}
+	if c.filter.Since != nil && c.filter.Until != nil && c.filter.Until.Before(*c.filter.Since) {
+		return errors.New("--until must not be before --since")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// AuditLogAPI defines the API methods used by the audit-log command.
+type AuditLogAPI interface {
+	Records(filter params.AuditRecordFilter) ([]params.AuditRecord, error)
+	Close() error
+}
+
+var getAuditLogAPI = func(c *AuditLogCommand) (AuditLogAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return auditlog.NewClient(root), nil
+}
+
+// Run implements Command.Run.
+func (c *AuditLogCommand) Run(ctx *cmd.Context) error {
+	client, err := getAuditLogAPI(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer client.Close()
+	records, err := client.Records(c.filter)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return c.out.Write(ctx, records)
+}
+
+func (c *AuditLogCommand) formatTabular(value interface{}) ([]byte, error) {
+	records, ok := value.([]params.AuditRecord)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", records, value)
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tUSER\tOPERATION\tENTITIES\tOUTCOME")
+	for _, rec := range records {
+		outcome := "ok"
+		if rec.Error != "" {
+			outcome = "error: " + rec.Error
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s.%s\t%s\t%s\n",
+			formatStatusTime(&rec.Timestamp, c.isoTime),
+			rec.User,
+			rec.Facade, rec.Method,
+			strings.Join(rec.Entities, ","),
+			outcome,
+		)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
+
+// entityTag returns the tag for the given entity, which may be a
+// tag, or the name of a machine, unit or service.
+func entityTag(entity string) (names.Tag, error) {
+	if tag, err := names.ParseTag(entity); err == nil {
+		return tag, nil
+	}
+	switch {
+	case names.IsValidMachine(entity):
+		return names.NewMachineTag(entity), nil
+	case names.IsValidUnit(entity):
+		return names.NewUnitTag(entity), nil
+	case names.IsValidService(entity):
+		return names.NewServiceTag(entity), nil
+	}
+	return nil, errors.NotValidf("entity %q", entity)
+}
+
+// parseTimeArg parses a time given on the command line. The time may
+// be an RFC3339 timestamp, a date, or a duration which is subtracted
+// from now.
+func parseTimeArg(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, errors.Errorf("%q is not a timestamp, date or duration", value)
+}
@@ -0,0 +1,158 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type AuditLogSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeAuditLogAPI
+}
+
+var _ = gc.Suite(&AuditLogSuite{})
+
+func (s *AuditLogSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeAuditLogAPI{}
+	s.PatchValue(&getAuditLogAPI, func(*AuditLogCommand) (AuditLogAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *AuditLogSuite) TestInit(c *gc.C) {
+	since := time.Date(2015, 7, 1, 0, 0, 0, 0, time.Local)
+	until := time.Date(2015, 7, 2, 12, 0, 0, 0, time.UTC)
+	for i, test := range []struct {
+		args     []string
+		filter   params.AuditRecordFilter
+		errMatch string
+	}{{
+		args: nil,
+	}, {
+		args:   []string{"--user", "bob", "-n", "10"},
+		filter: params.AuditRecordFilter{User: "user-bob", Limit: 10},
+	}, {
+		args:   []string{"--entity", "wordpress/0"},
+		filter: params.AuditRecordFilter{Entity: "unit-wordpress-0"},
+	}, {
+		args:   []string{"--entity", "1"},
+		filter: params.AuditRecordFilter{Entity: "machine-1"},
+	}, {
+		args:   []string{"--entity", "mysql"},
+		filter: params.AuditRecordFilter{Entity: "service-mysql"},
+	}, {
+		args:   []string{"--entity", "user-mary"},
+		filter: params.AuditRecordFilter{Entity: "user-mary"},
+	}, {
+		args:   []string{"--since", "2015-07-01", "--until", "2015-07-02T12:00:00Z"},
+		filter: params.AuditRecordFilter{Since: &since, Until: &until},
+	}, {
+		args:     []string{"--user", "Bob!"},
+		errMatch: `user name "Bob!" not valid`,
+	}, {
+		args:     []string{"--entity", "foo-bar-"},
+		errMatch: `entity "foo-bar-" not valid`,
+	}, {
+		args:     []string{"--since", "yesterday"},
+		errMatch: `invalid --since value: "yesterday" is not a timestamp, date or duration`,
+	}, {
+		args:     []string{"--since", "2015-07-02", "--until", "2015-07-01"},
+		errMatch: "--until must not be before --since",
+	}, {
+		args:     []string{"-n", "-1"},
+		errMatch: "invalid number of records -1",
+	}, {
+		args:     []string{"extra"},
+		errMatch: `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &AuditLogCommand{}
+		err := testing.InitCommand(envcmd.Wrap(command), test.args)
+		if test.errMatch != "" {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(command.filter, jc.DeepEquals, test.filter)
+	}
+}
+
+func (s *AuditLogSuite) TestInitRelativeTime(c *gc.C) {
+	command := &AuditLogCommand{}
+	before := time.Now()
+	err := testing.InitCommand(envcmd.Wrap(command), []string{"--since", "2h"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(command.filter.Since, gc.NotNil)
+	c.Assert(command.filter.Since.Before(before.Add(-2*time.Hour+time.Second)), jc.IsTrue)
+	c.Assert(command.filter.Since.After(time.Now().Add(-2*time.Hour-time.Second)), jc.IsTrue)
+}
+
+func (s *AuditLogSuite) TestRunTabular(c *gc.C) {
+	s.api.records = []params.AuditRecord{{
+		Timestamp: time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC),
+		User:      "user-admin",
+		Facade:    "Client",
+		Method:    "ServiceDeploy",
+		Entities:  []string{"service-wordpress"},
+	}, {
+		Timestamp: time.Date(2015, 7, 1, 12, 5, 0, 0, time.UTC),
+		User:      "user-bob",
+		Facade:    "Client",
+		Method:    "DestroyMachines",
+		Entities:  []string{"machine-1", "machine-2"},
+		Error:     "machine 1 is required by the environment",
+	}}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&AuditLogCommand{}), "--utc", "--user", "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.filter, jc.DeepEquals, params.AuditRecordFilter{User: "user-bob"})
+	c.Assert(s.api.closed, jc.IsTrue)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"TIME                 USER       OPERATION              ENTITIES            OUTCOME\n"+
+		"2015-07-01 12:00:00Z user-admin Client.ServiceDeploy   service-wordpress   ok\n"+
+		"2015-07-01 12:05:00Z user-bob   Client.DestroyMachines machine-1,machine-2 error: machine 1 is required by the environment\n",
+	)
+}
+
+func (s *AuditLogSuite) TestRunJSON(c *gc.C) {
+	s.api.records = []params.AuditRecord{{
+		Timestamp: time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC),
+		User:      "user-admin",
+		Facade:    "Client",
+		Method:    "ServiceExpose",
+		Args:      `{"ServiceName":"wordpress"}`,
+		Entities:  []string{"service-wordpress"},
+	}}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&AuditLogCommand{}), "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		`[{"timestamp":"2015-07-01T12:00:00Z","user":"user-admin","facade":"Client",`+
+		`"version":0,"method":"ServiceExpose","args":"{\"ServiceName\":\"wordpress\"}",`+
+		`"entities":["service-wordpress"]}]`+"\n",
+	)
+}
+
+type fakeAuditLogAPI struct {
+	records []params.AuditRecord
+	filter  params.AuditRecordFilter
+	closed  bool
+}
+
+func (f *fakeAuditLogAPI) Records(filter params.AuditRecordFilter) ([]params.AuditRecord, error) {
+	f.filter = filter
+	return f.records, nil
+}
+
+func (f *fakeAuditLogAPI) Close() error {
+	f.closed = true
+	return nil
+}
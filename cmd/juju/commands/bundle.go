@@ -0,0 +1,893 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v5"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/api"
+	apiservice "github.com/juju/juju/api/service"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/storage"
+)
+
+// bundleData holds the contents of a bundle file, which describes a
+// set of services, the machines their units are placed on and the
+// relations between them, along with the parts of it parsed when it
+// is read.
+type bundleData struct {
+	*charm.BundleData
+
+	// services holds the parsed parts of each service, keyed by
+	// service name.
+	services map[string]*bundleService
+
+	// machineCons holds the constraints of each bundle machine,
+	// keyed by bundle machine id.
+	machineCons map[string]constraints.Value
+}
+
+// bundleService holds the parsed parts of a service in a bundle.
+type bundleService struct {
+	// placements holds the placement directives of the service's
+	// units. The i'th directive places the i'th unit; units without
+	// a directive are deployed to new machines.
+	placements []*charm.UnitPlacement
+
+	cons    constraints.Value
+	storage map[string]storage.Constraints
+
+	// exposeFrom holds the CIDRs an exposed service is exposed to,
+	// if not to any address. It is read from the "expose_from" field
+	// of the service, which bundles only support when deployed by
+	// juju itself.
+	exposeFrom []string
+}
+
+// bundleExtensions holds the fields of a bundle file not known to the
+// charm package.
+type bundleExtensions struct {
+	Services map[string]*struct {
+		ExposeFrom []string `yaml:"expose_from"`
+	} `yaml:"services"`
+}
+
+// readBundle reads the bundle in the file at the given path, and
+// verifies that it is consistent.
+func readBundle(path string) (*bundleData, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	bd, err := charm.ReadBundleData(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot parse bundle %q", path)
+	}
+	var extensions bundleExtensions
+	if err := goyaml.Unmarshal(data, &extensions); err != nil {
+		return nil, errors.Annotatef(err, "cannot parse bundle %q", path)
+	}
+	bundle := &bundleData{BundleData: bd}
+	if err := bundle.verify(&extensions); err != nil {
+		return nil, errors.Annotatef(err, "invalid bundle %q", path)
+	}
+	return bundle, nil
+}
+
+// verify checks that the bundle is consistent, and parses the parts
+// of its services and machines that juju needs to deploy it.
+func (b *bundleData) verify(extensions *bundleExtensions) error {
+	if len(b.Services) == 0 {
+		return errors.New("no services specified")
+	}
+	for name, svc := range b.Services {
+		if svc == nil {
+			return errors.Errorf("no charm specified for service %q", name)
+		}
+	}
+	for id, m := range b.Machines {
+		if m == nil {
+			b.Machines[id] = &charm.MachineSpec{}
+		}
+	}
+	if err := b.Verify(verifyConstraints); err != nil {
+		return errors.Trace(err)
+	}
+	b.machineCons = make(map[string]constraints.Value)
+	for id, m := range b.Machines {
+		cons, err := constraints.Parse(m.Constraints)
+		if err != nil {
+			return errors.Annotatef(err, "invalid constraints for machine %q", id)
+		}
+		b.machineCons[id] = cons
+	}
+	b.services = make(map[string]*bundleService)
+	for name, svc := range b.Services {
+		parsed := &bundleService{}
+		for _, directive := range svc.To {
+			p, err := charm.ParsePlacement(directive)
+			if err != nil {
+				return errors.Annotatef(err, "service %q", name)
+			}
+			if p.ContainerType != "" {
+				if _, err := instance.ParseContainerType(p.ContainerType); err != nil {
+					return errors.Errorf("service %q: invalid placement %q: %v", name, directive, err)
+				}
+			}
+			parsed.placements = append(parsed.placements, p)
+		}
+		cons, err := constraints.Parse(svc.Constraints)
+		if err != nil {
+			return errors.Annotatef(err, "invalid constraints for service %q", name)
+		}
+		parsed.cons = cons
+		for store, value := range svc.Storage {
+			storageCons, err := storage.ParseConstraints(value)
+			if err != nil {
+				return errors.Annotatef(err, "invalid storage %q for service %q", store, name)
+			}
+			if parsed.storage == nil {
+				parsed.storage = make(map[string]storage.Constraints)
+			}
+			parsed.storage[store] = storageCons
+		}
+		if ext := extensions.Services[name]; ext != nil {
+			parsed.exposeFrom = ext.ExposeFrom
+		}
+		if len(parsed.exposeFrom) > 0 && !svc.Expose {
+			return errors.Errorf("expose_from specified for unexposed service %q", name)
+		}
+		if err := network.ValidateCIDRs(parsed.exposeFrom); err != nil {
+			return errors.Annotatef(err, "service %q", name)
+		}
+		b.services[name] = parsed
+	}
+	return nil
+}
+
+// verifyConstraints returns an error if the given constraints are not
+// valid.
+func verifyConstraints(s string) error {
+	_, err := constraints.Parse(s)
+	return err
+}
+
+// serviceNames returns the names of the services in the bundle, in
+// the order their units must be added: services whose units are
+// placed alongside the units of other services come after those
+// services.
+func (b *bundleData) serviceNames() ([]string, error) {
+	var remaining []string
+	for name := range b.Services {
+		remaining = append(remaining, name)
+	}
+	sort.Strings(remaining)
+	done := make(map[string]bool)
+	var ordered []string
+	for len(remaining) > 0 {
+		var blocked []string
+		for _, name := range remaining {
+			ready := true
+			for _, p := range b.services[name].placements {
+				if p.Service != "" && p.Service != name && !done[p.Service] {
+					ready = false
+				}
+			}
+			if ready {
+				done[name] = true
+				ordered = append(ordered, name)
+			} else {
+				blocked = append(blocked, name)
+			}
+		}
+		if len(blocked) == len(remaining) {
+			return nil, errors.Errorf("cycle in placement directives of services %s", strings.Join(blocked, ", "))
+		}
+		remaining = blocked
+	}
+	return ordered, nil
+}
+
+// bundleChange is a single step in deploying a bundle.
+type bundleChange struct {
+	description string
+	apply       func() error
+}
+
+// bundleDeployer computes and applies the changes needed to deploy a
+// bundle into an environment, skipping those parts of the bundle that
+// are already deployed.
+type bundleDeployer struct {
+	bundle *bundleData
+	client *api.Client
+	status *api.Status
+
+	// defaultSeries holds the series used for charms that do not
+	// specify one.
+	defaultSeries string
+
+	// The following fields are only needed to apply changes.
+	ctx              *cmd.Context
+	conf             *config.Config
+	repoPath         string
+	csClient         *csClient
+	newServiceClient func() (*apiservice.Client, error)
+
+	// charms maps the charms in the bundle to the URLs they were
+	// added to the environment with.
+	charms map[string]*charm.URL
+
+	// machines maps bundle machine ids to environment machine ids.
+	machines map[string]string
+
+	// units holds the names of each service's units, indexed as
+	// in the bundle.
+	units map[string][]string
+}
+
+// newBundleDeployer returns a bundleDeployer that deploys the given
+// bundle into an environment with the given status.
+func newBundleDeployer(bundle *bundleData, client *api.Client, status *api.Status, conf *config.Config) *bundleDeployer {
+	defaultSeries := bundle.Series
+	if defaultSeries == "" {
+		defaultSeries, _ = conf.DefaultSeries()
+	}
+	return &bundleDeployer{
+		bundle:        bundle,
+		client:        client,
+		status:        status,
+		defaultSeries: defaultSeries,
+		conf:          conf,
+		charms:        make(map[string]*charm.URL),
+		machines:      make(map[string]string),
+		units:         make(map[string][]string),
+	}
+}
+
+// changes returns the changes that must be applied, in order, to
+// deploy the bundle.
+func (d *bundleDeployer) changes() ([]bundleChange, error) {
+	serviceNames, err := d.bundle.serviceNames()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	charms := make(map[string]string)
+	for _, name := range serviceNames {
+		svc := d.bundle.Services[name]
+		charmRef, err := d.charmRef(svc.Charm)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		existing, ok := d.status.Services[name]
+		if !ok {
+			charms[name] = charmRef
+			continue
+		}
+		if !charmMatches(existing.Charm, charmRef) {
+			return nil, errors.Errorf("service %q already deployed with charm %q, not %q", name, existing.Charm, charmRef)
+		}
+		if err := d.mapExistingUnits(name, existing); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	var changes []bundleChange
+	added := make(map[string]bool)
+	for _, name := range serviceNames {
+		charmRef, ok := charms[name]
+		if !ok || added[charmRef] {
+			continue
+		}
+		added[charmRef] = true
+		changes = append(changes, d.addCharmChange(charmRef))
+	}
+	var machineIds []string
+	for id := range d.bundle.Machines {
+		if _, ok := d.machines[id]; !ok {
+			machineIds = append(machineIds, id)
+		}
+	}
+	sort.Sort(machineIdSlice(machineIds))
+	for _, id := range machineIds {
+		changes = append(changes, d.addMachineChange(id))
+	}
+	for _, name := range serviceNames {
+		if charmRef, ok := charms[name]; ok {
+			changes = append(changes, d.deployChange(name, charmRef))
+		}
+	}
+	for _, name := range serviceNames {
+		if _, ok := charms[name]; ok {
+			continue
+		}
+		updates, err := d.serviceUpdateChanges(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		changes = append(changes, updates...)
+	}
+	for _, name := range serviceNames {
+		placements := d.bundle.services[name].placements
+		for i := len(d.units[name]); i < d.bundle.Services[name].NumUnits; i++ {
+			var p *charm.UnitPlacement
+			if i < len(placements) {
+				p = placements[i]
+			}
+			changes = append(changes, d.addUnitChange(name, i, p))
+		}
+	}
+	for _, relation := range d.bundle.Relations {
+		if !d.relationExists(relation) {
+			changes = append(changes, d.addRelationChange(relation))
+		}
+	}
+	for _, name := range serviceNames {
+		if d.bundle.Services[name].Expose && !d.status.Services[name].Exposed {
+			changes = append(changes, d.exposeChange(name))
+		}
+	}
+	for _, name := range serviceNames {
+		annotations := d.bundle.Services[name].Annotations
+		if len(annotations) == 0 {
+			continue
+		}
+		tag := names.NewServiceTag(name).String()
+		if _, ok := d.status.Services[name]; ok {
+			set, err := d.annotationsSet(tag, annotations)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if set {
+				continue
+			}
+		}
+		changes = append(changes, d.setAnnotationsChange(
+			fmt.Sprintf("service %s", name),
+			func() string { return tag },
+			annotations,
+		))
+	}
+	for _, id := range d.sortedMachineIds() {
+		annotations := d.bundle.Machines[id].Annotations
+		if len(annotations) == 0 {
+			continue
+		}
+		if machine, ok := d.machines[id]; ok {
+			set, err := d.annotationsSet(names.NewMachineTag(machine).String(), annotations)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if set {
+				continue
+			}
+		}
+		id := id
+		changes = append(changes, d.setAnnotationsChange(
+			d.describeMachine(id),
+			func() string { return names.NewMachineTag(d.machines[id]).String() },
+			annotations,
+		))
+	}
+	return changes, nil
+}
+
+// charmRef returns the given charm reference with the bundle's
+// default series filled in if it has none.
+func (d *bundleDeployer) charmRef(charmName string) (string, error) {
+	ref, err := charm.ParseReference(charmName)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if ref.Series == "" {
+		ref.Series = d.defaultSeries
+	}
+	return ref.String(), nil
+}
+
+// charmMatches reports whether the charm URL of a deployed service
+// matches the given charm reference. The revision is only compared
+// if the reference specifies one.
+func charmMatches(curlStr, charmRef string) bool {
+	curl, err := charm.ParseURL(curlStr)
+	if err != nil {
+		return false
+	}
+	ref, err := charm.ParseReference(charmRef)
+	if err != nil {
+		return false
+	}
+	if ref.Series == "" {
+		ref.Series = curl.Series
+	}
+	if ref.Revision == -1 {
+		curl = curl.WithRevision(-1)
+	}
+	return curl.String() == ref.String()
+}
+
+// mapExistingUnits records the existing units of the named service,
+// and maps the bundle machines they were placed on to the machines
+// hosting them.
+func (d *bundleDeployer) mapExistingUnits(name string, existing api.ServiceStatus) error {
+	var unitNames []string
+	for unitName := range existing.Units {
+		unitNames = append(unitNames, unitName)
+	}
+	sort.Sort(unitNameSlice(unitNames))
+	d.units[name] = unitNames
+	placements := d.bundle.services[name].placements
+	for i, unitName := range unitNames {
+		if i >= len(placements) {
+			break
+		}
+		p := placements[i]
+		if p.Machine == "" || p.Machine == "new" {
+			continue
+		}
+		if _, ok := d.machines[p.Machine]; ok {
+			continue
+		}
+		machine := existing.Units[unitName].Machine
+		if machine == "" {
+			return errors.Errorf("unit %q has no machine", unitName)
+		}
+		if p.ContainerType != "" {
+			machine = strings.SplitN(machine, "/", 2)[0]
+		}
+		d.machines[p.Machine] = machine
+	}
+	return nil
+}
+
+// serviceUpdateChanges returns the changes needed to bring the options
+// and constraints of the named deployed service into line with the
+// bundle. Options and constraints the bundle does not specify are left
+// unchanged.
+func (d *bundleDeployer) serviceUpdateChanges(name string) ([]bundleChange, error) {
+	svc := d.bundle.Services[name]
+	if len(svc.Options) == 0 && svc.Constraints == "" {
+		return nil, nil
+	}
+	current, err := d.client.ServiceGet(name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var changes []bundleChange
+	options := make(map[string]interface{})
+	for key, value := range svc.Options {
+		info, _ := current.Config[key].(map[string]interface{})
+		if info == nil || fmt.Sprint(info["value"]) != fmt.Sprint(value) {
+			options[key] = value
+		}
+	}
+	if len(options) > 0 {
+		changes = append(changes, d.setOptionsChange(name, options))
+	}
+	cons := d.bundle.services[name].cons
+	if svc.Constraints != "" && cons.String() != current.Constraints.String() {
+		changes = append(changes, d.setConstraintsChange(name, cons))
+	}
+	return changes, nil
+}
+
+// annotationsSet reports whether the entity with the given tag
+// already has all the given annotations.
+func (d *bundleDeployer) annotationsSet(tag string, annotations map[string]string) (bool, error) {
+	existing, err := d.client.GetAnnotations(tag)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	for key, value := range annotations {
+		if existing[key] != value {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// relationExists reports whether a relation between the given
+// endpoints is already established.
+func (d *bundleDeployer) relationExists(endpoints []string) bool {
+	for _, relation := range d.status.Relations {
+		if len(relation.Endpoints) != len(endpoints) {
+			continue
+		}
+		matched := true
+		for _, endpoint := range endpoints {
+			if !relationHasEndpoint(relation, endpoint) {
+				matched = false
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func relationHasEndpoint(relation api.RelationStatus, endpoint string) bool {
+	parts := strings.SplitN(endpoint, ":", 2)
+	for _, ep := range relation.Endpoints {
+		if ep.ServiceName == parts[0] && (len(parts) == 1 || ep.Name == parts[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *bundleDeployer) addCharmChange(charmRef string) bundleChange {
+	return bundleChange{
+		description: fmt.Sprintf("add charm %s", charmRef),
+		apply: func() error {
+			curl, repo, err := resolveCharmURL(charmRef, d.csClient.params, d.repoPath, d.conf)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			curl, err = addCharmViaAPI(d.client, d.ctx, curl, repo, d.csClient)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			d.charms[charmRef] = curl
+			return nil
+		},
+	}
+}
+
+func (d *bundleDeployer) addMachineChange(id string) bundleChange {
+	m := d.bundle.Machines[id]
+	series := m.Series
+	if series == "" {
+		series = d.bundle.Series
+	}
+	return bundleChange{
+		description: fmt.Sprintf("add new machine for bundle machine %s", id),
+		apply: func() error {
+			machine, err := d.addMachine(params.AddMachineParams{
+				Series:      series,
+				Constraints: d.bundle.machineCons[id],
+			})
+			if err != nil {
+				return errors.Trace(err)
+			}
+			d.machines[id] = machine
+			return nil
+		},
+	}
+}
+
+func (d *bundleDeployer) deployChange(name, charmRef string) bundleChange {
+	svc := d.bundle.Services[name]
+	parsed := d.bundle.services[name]
+	return bundleChange{
+		description: fmt.Sprintf("deploy service %s using %s", name, charmRef),
+		apply: func() error {
+			curl := d.charms[charmRef]
+			var configYAML []byte
+			if len(svc.Options) > 0 {
+				var err error
+				configYAML, err = goyaml.Marshal(map[string]interface{}{name: svc.Options})
+				if err != nil {
+					return errors.Trace(err)
+				}
+			}
+			if len(parsed.storage) == 0 {
+				return d.client.ServiceDeploy(curl.String(), name, 0, string(configYAML), parsed.cons, "")
+			}
+			serviceClient, err := d.newServiceClient()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			defer serviceClient.Close()
+			err = serviceClient.ServiceDeploy(
+				curl.String(), name, 0, string(configYAML), parsed.cons, "", nil, nil, parsed.storage,
+			)
+			if params.IsCodeNotImplemented(err) {
+				return errors.New("cannot deploy charms with storage: not supported by the API server")
+			}
+			return err
+		},
+	}
+}
+
+func (d *bundleDeployer) setOptionsChange(name string, options map[string]interface{}) bundleChange {
+	var keys []string
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return bundleChange{
+		description: fmt.Sprintf("set options %s for service %s", strings.Join(keys, ", "), name),
+		apply: func() error {
+			configYAML, err := goyaml.Marshal(map[string]interface{}{name: options})
+			if err != nil {
+				return errors.Trace(err)
+			}
+			return errors.Trace(d.client.ServiceSetYAML(name, string(configYAML)))
+		},
+	}
+}
+
+func (d *bundleDeployer) setConstraintsChange(name string, cons constraints.Value) bundleChange {
+	return bundleChange{
+		description: fmt.Sprintf("set constraints for service %s to %s", name, cons),
+		apply: func() error {
+			return errors.Trace(d.client.SetServiceConstraints(name, cons))
+		},
+	}
+}
+
+func (d *bundleDeployer) addUnitChange(name string, index int, p *charm.UnitPlacement) bundleChange {
+	return bundleChange{
+		description: fmt.Sprintf("add unit %d of service %s to %s", index, name, d.describePlacement(index, p)),
+		apply: func() error {
+			machine, err := d.placeUnit(name, index, p)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			units, err := d.client.AddServiceUnits(name, 1, machine)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			d.units[name] = append(d.units[name], units...)
+			return nil
+		},
+	}
+}
+
+func (d *bundleDeployer) addRelationChange(endpoints []string) bundleChange {
+	return bundleChange{
+		description: fmt.Sprintf("add relation %s", strings.Join(endpoints, " - ")),
+		apply: func() error {
+			_, err := d.client.AddRelation(endpoints...)
+			return errors.Trace(err)
+		},
+	}
+}
+
+func (d *bundleDeployer) exposeChange(name string) bundleChange {
+	cidrs := d.bundle.services[name].exposeFrom
+	description := fmt.Sprintf("expose service %s", name)
+	if len(cidrs) > 0 {
+		description += fmt.Sprintf(" to %s", strings.Join(cidrs, ","))
+	}
+	return bundleChange{
+		description: description,
+		apply: func() error {
+			return errors.Trace(d.client.ServiceExposeFrom(name, cidrs))
+		},
+	}
+}
+
+func (d *bundleDeployer) setAnnotationsChange(entity string, tag func() string, annotations map[string]string) bundleChange {
+	return bundleChange{
+		description: fmt.Sprintf("set annotations for %s", entity),
+		apply: func() error {
+			return errors.Trace(d.client.SetAnnotations(tag(), annotations))
+		},
+	}
+}
+
+// describeMachine returns a description of the given bundle machine,
+// naming the environment machine it is mapped to if there is one.
+func (d *bundleDeployer) describeMachine(id string) string {
+	if machine, ok := d.machines[id]; ok {
+		return fmt.Sprintf("machine %s", machine)
+	}
+	return fmt.Sprintf("bundle machine %s", id)
+}
+
+// describePlacement returns a description of where a unit with the
+// given index and placement is deployed.
+func (d *bundleDeployer) describePlacement(index int, p *charm.UnitPlacement) string {
+	if p == nil {
+		return "new machine"
+	}
+	var target string
+	switch {
+	case p.Machine == "new":
+		target = "new machine"
+	case p.Machine != "":
+		target = d.describeMachine(p.Machine)
+	default:
+		unit := p.Unit
+		if unit < 0 {
+			unit = index
+		}
+		target = fmt.Sprintf("machine of unit %d of service %s", unit, p.Service)
+	}
+	if p.ContainerType != "" {
+		return fmt.Sprintf("new %s container on %s", p.ContainerType, target)
+	}
+	return target
+}
+
+// placeUnit returns the machine spec with which the unit of the named
+// service with the given index and placement is added. Units not
+// placed on existing machines or containers are added to new ones,
+// created with the service's constraints.
+func (d *bundleDeployer) placeUnit(name string, index int, p *charm.UnitPlacement) (string, error) {
+	newMachine := params.AddMachineParams{
+		Series:      d.serviceSeries(name),
+		Constraints: d.bundle.services[name].cons,
+	}
+	if p == nil {
+		return d.addMachine(newMachine)
+	}
+	var machine string
+	switch {
+	case p.Machine == "new":
+		newMachine.ContainerType = instance.ContainerType(p.ContainerType)
+		return d.addMachine(newMachine)
+	case p.Machine != "":
+		machine = d.machines[p.Machine]
+	default:
+		unit := p.Unit
+		if unit < 0 {
+			unit = index
+		}
+		units := d.units[p.Service]
+		if unit >= len(units) {
+			return "", errors.Errorf("unit %d of service %q is not deployed", unit, p.Service)
+		}
+		var err error
+		machine, err = d.unitMachine(p.Service, units[unit])
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+	if p.ContainerType != "" {
+		return fmt.Sprintf("%s:%s", p.ContainerType, machine), nil
+	}
+	return machine, nil
+}
+
+// serviceSeries returns the series of the named service's charm.
+func (d *bundleDeployer) serviceSeries(name string) string {
+	if existing, ok := d.status.Services[name]; ok {
+		if curl, err := charm.ParseURL(existing.Charm); err == nil {
+			return curl.Series
+		}
+	}
+	charmRef, err := d.charmRef(d.bundle.Services[name].Charm)
+	if err != nil {
+		return d.defaultSeries
+	}
+	if curl, ok := d.charms[charmRef]; ok {
+		return curl.Series
+	}
+	return d.defaultSeries
+}
+
+// addMachine adds a machine that can host units, and returns its id.
+func (d *bundleDeployer) addMachine(machineParams params.AddMachineParams) (string, error) {
+	machineParams.Jobs = []multiwatcher.MachineJob{multiwatcher.JobHostUnits}
+	results, err := d.client.AddMachines([]params.AddMachineParams{machineParams})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results) != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Error != nil {
+		return "", results[0].Error
+	}
+	return results[0].Machine, nil
+}
+
+// unitMachine returns the id of the machine hosting the given unit of
+// the named service.
+func (d *bundleDeployer) unitMachine(service, unit string) (string, error) {
+	status, err := d.client.Status([]string{unit})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	unitStatus, ok := status.Services[service].Units[unit]
+	if !ok || unitStatus.Machine == "" {
+		return "", errors.Errorf("unit %q has no machine", unit)
+	}
+	return unitStatus.Machine, nil
+}
+
+func (d *bundleDeployer) sortedMachineIds() []string {
+	var ids []string
+	for id := range d.bundle.Machines {
+		ids = append(ids, id)
+	}
+	sort.Sort(machineIdSlice(ids))
+	return ids
+}
+
+// machineIdSlice sorts bundle machine ids numerically.
+type machineIdSlice []string
+
+func (s machineIdSlice) Len() int      { return len(s) }
+func (s machineIdSlice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s machineIdSlice) Less(i, j int) bool {
+	a, _ := strconv.Atoi(s[i])
+	b, _ := strconv.Atoi(s[j])
+	return a < b
+}
+
+// unitNameSlice sorts the unit names of a service by unit number.
+type unitNameSlice []string
+
+func (s unitNameSlice) Len() int      { return len(s) }
+func (s unitNameSlice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s unitNameSlice) Less(i, j int) bool {
+	return unitNumber(s[i]) < unitNumber(s[j])
+}
+
+func unitNumber(unitName string) int {
+	n, _ := strconv.Atoi(unitName[strings.LastIndex(unitName, "/")+1:])
+	return n
+}
+
+// isBundlePath reports whether the deploy argument names a bundle file
+// rather than a charm.
+func isBundlePath(arg string) bool {
+	return strings.HasSuffix(arg, ".yaml") || strings.HasSuffix(arg, ".yml")
+}
+
+// deployBundle deploys the bundle named on the command line, or just
+// prints the changes needed to deploy it if --dry-run was given.
+func (c *DeployCommand) deployBundle(ctx *cmd.Context, client *api.Client, conf *config.Config) error {
+	bundle, err := readBundle(ctx.AbsPath(c.BundlePath))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	status, err := client.Status(nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	d := newBundleDeployer(bundle, client, status, conf)
+	changes, err := d.changes()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if c.DryRun {
+		if len(changes) == 0 {
+			fmt.Fprintln(ctx.Stdout, "No changes to apply.")
+		}
+		for _, change := range changes {
+			fmt.Fprintln(ctx.Stdout, change.description)
+		}
+		return nil
+	}
+
+	csClient, err := newCharmStoreClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer csClient.jar.Save()
+	d.ctx = ctx
+	d.repoPath = ctx.AbsPath(c.RepoPath)
+	d.csClient = csClient
+	d.newServiceClient = c.newServiceAPIClient
+	for _, change := range changes {
+		ctx.Infof("%s", change.description)
+		if err := change.apply(); err != nil {
+			err = errors.Annotatef(err, "cannot %s", change.description)
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+	}
+	ctx.Infof("Deployment of bundle %q completed.", c.BundlePath)
+	return nil
+}
@@ -0,0 +1,359 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/testcharms"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type bundleSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&bundleSuite{})
+
+func writeBundle(c *gc.C, content string) string {
+	path := filepath.Join(c.MkDir(), "bundle.yaml")
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+var readBundleErrorTests = []struct {
+	bundle string
+	err    string
+}{{
+	bundle: `services: {}`,
+	err:    `no services specified`,
+}, {
+	bundle: `services: {wordpress: }`,
+	err:    `no charm specified for service "wordpress"`,
+}, {
+	// The errors below are reported by charm.BundleData.Verify.
+	bundle: `services: {wordpress: {num_units: 1}}`,
+	err:    `.*charm.*`,
+}, {
+	bundle: `services: {wordpress: {charm: wordpress, num_units: -1}}`,
+	err:    `.*negative number of units.*`,
+}, {
+	bundle: `services: {wordpress: {charm: wordpress, num_units: 1, to: ["1", "2"]}}`,
+	err:    `.*too many units.*`,
+}, {
+	bundle: `services: {wordpress: {charm: wordpress, num_units: 1, to: ["1"]}}`,
+	err:    `.*placement "1" refers to a machine not defined.*`,
+}, {
+	bundle: `services: {wordpress: {charm: wordpress, num_units: 1, to: ["mysql/0"]}}`,
+	err:    `.*placement "mysql/0" refers to a service not defined.*`,
+}, {
+	bundle: `services: {wordpress: {charm: wordpress, num_units: 1, constraints: "gibber=plop"}}`,
+	err:    `.*unknown constraint "gibber".*`,
+}, {
+	bundle: `{services: {wordpress: {charm: wordpress}}, machines: {"1/lxc/0": {}}}`,
+	err:    `.*invalid machine id "1/lxc/0".*`,
+}, {
+	bundle: `{services: {wordpress: {charm: wordpress}}, relations: [[wordpress:db, mysql:server]]}`,
+	err:    `.*refers to service "mysql" not defined.*`,
+}, {
+	bundle: `{services: {wordpress: {charm: wordpress}}, relations: [[wordpress:db]]}`,
+	err:    `.*endpoint.*`,
+}, {
+	// The errors below are reported by juju itself.
+	bundle: `{services: {wordpress: {charm: wordpress, num_units: 1, to: ["foo:1"]}}, machines: {"1": {}}}`,
+	err:    `service "wordpress": invalid placement "foo:1": invalid container type "foo"`,
+}, {
+	bundle: `services: {wordpress: {charm: wordpress, storage: {data: ","}}}`,
+	err:    `invalid storage "data" for service "wordpress": storage constraints require at least one field to be specified`,
+}, {
+	bundle: `services: {wordpress: {charm: wordpress, expose_from: ["10.0.0.0/8"]}}`,
+	err:    `expose_from specified for unexposed service "wordpress"`,
+}, {
+	bundle: `services: {wordpress: {charm: wordpress, expose: true, expose_from: ["10.0.0.0"]}}`,
+	err:    `service "wordpress": invalid CIDR "10.0.0.0"`,
+}, {
+	bundle: `
+services:
+  wordpress: {charm: wordpress, num_units: 1, to: [mysql]}
+  mysql: {charm: mysql, num_units: 1, to: [wordpress]}
+`,
+	err: `.*cycle in placement directives of services mysql, wordpress`,
+}}
+
+func (s *bundleSuite) TestReadBundleErrors(c *gc.C) {
+	for i, test := range readBundleErrorTests {
+		c.Logf("test %d", i)
+		path := writeBundle(c, test.bundle)
+		bundle, err := readBundle(path)
+		if err == nil {
+			_, err = bundle.serviceNames()
+		}
+		c.Check(err, gc.ErrorMatches, `(invalid bundle ".*": )?`+test.err)
+	}
+}
+
+func (s *bundleSuite) TestReadBundle(c *gc.C) {
+	path := writeBundle(c, `
+services:
+  wordpress:
+    charm: wordpress
+    num_units: 2
+    to: ["lxc:1", "new"]
+    constraints: mem=2G
+    storage:
+      data: ebs,10G
+    expose: true
+    expose_from: ["10.0.0.0/8"]
+machines:
+  "1":
+    constraints: cpu-cores=2
+`)
+	bundle, err := readBundle(path)
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress := bundle.services["wordpress"]
+	c.Assert(wordpress.placements, jc.DeepEquals, []*charm.UnitPlacement{
+		{ContainerType: "lxc", Machine: "1", Unit: -1},
+		{Machine: "new", Unit: -1},
+	})
+	c.Assert(wordpress.cons, jc.DeepEquals, constraints.MustParse("mem=2G"))
+	c.Assert(wordpress.storage, gc.HasLen, 1)
+	c.Assert(wordpress.exposeFrom, jc.DeepEquals, []string{"10.0.0.0/8"})
+	c.Assert(bundle.machineCons["1"], jc.DeepEquals, constraints.MustParse("cpu-cores=2"))
+}
+
+const testBundle = `
+services:
+  wordpress:
+    charm: local:wordpress
+    num_units: 1
+    to: ["1"]
+    annotations:
+      gui-x: "10"
+  mysql:
+    charm: local:mysql
+    num_units: 1
+    to: ["lxc:wordpress/0"]
+  dummy:
+    charm: local:dummy
+    num_units: 1
+    constraints: mem=2G
+    options:
+      skill-level: 42
+machines:
+  "1":
+    constraints: cpu-cores=2
+relations:
+  - ["wordpress:db", "mysql:server"]
+`
+
+func (s *DeploySuite) setUpBundleCharms(c *gc.C) {
+	for _, name := range []string{"wordpress", "mysql", "dummy"} {
+		testcharms.Repo.CharmArchivePath(s.SeriesPath, name)
+	}
+}
+
+func (s *DeploySuite) runDeployBundle(c *gc.C, path string, args ...string) (string, error) {
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), append([]string{path}, args...)...)
+	if err != nil {
+		return "", err
+	}
+	return coretesting.Stdout(ctx), nil
+}
+
+func (s *DeploySuite) TestDeployBundleDryRun(c *gc.C) {
+	path := writeBundle(c, testBundle)
+	out, err := s.runDeployBundle(c, path, "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.Split(strings.TrimSpace(out), "\n"), jc.DeepEquals, []string{
+		"add charm local:trusty/dummy",
+		"add charm local:trusty/wordpress",
+		"add charm local:trusty/mysql",
+		"add new machine for bundle machine 1",
+		"deploy service dummy using local:trusty/dummy",
+		"deploy service wordpress using local:trusty/wordpress",
+		"deploy service mysql using local:trusty/mysql",
+		"add unit 0 of service dummy to new machine",
+		"add unit 0 of service wordpress to bundle machine 1",
+		"add unit 0 of service mysql to new lxc container on machine of unit 0 of service wordpress",
+		"add relation wordpress:db - mysql:server",
+		"set annotations for service wordpress",
+	})
+	services, err := s.State.AllServices()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(services, gc.HasLen, 0)
+}
+
+func (s *DeploySuite) assertUnitMachine(c *gc.C, unitName, machineId string) {
+	unit, err := s.State.Unit(unitName)
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, machineId)
+}
+
+func (s *DeploySuite) TestDeployBundle(c *gc.C) {
+	s.setUpBundleCharms(c)
+	path := writeBundle(c, testBundle)
+	_, err := s.runDeployBundle(c, path)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Bundle machine 1 is added first, as machine 0.
+	machine, err := s.State.Machine("0")
+	c.Assert(err, jc.ErrorIsNil)
+	cons, err := machine.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse("cpu-cores=2"))
+	s.assertUnitMachine(c, "dummy/0", "1")
+	s.assertUnitMachine(c, "wordpress/0", "0")
+	s.assertUnitMachine(c, "mysql/0", "0/lxc/0")
+
+	dummy, err := s.State.Service("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	cons, err = dummy.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=2G"))
+	settings, err := dummy.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings["skill-level"], gc.Equals, int64(42))
+
+	wordpress, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	rels, err := wordpress.Relations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rels, gc.HasLen, 1)
+	c.Assert(rels[0].String(), gc.Equals, "wordpress:db mysql:server")
+	annotations, err := s.APIState.Client().GetAnnotations(names.NewServiceTag("wordpress").String())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(annotations, jc.DeepEquals, map[string]string{"gui-x": "10"})
+}
+
+func (s *DeploySuite) TestDeployBundleTwice(c *gc.C) {
+	s.setUpBundleCharms(c)
+	path := writeBundle(c, testBundle)
+	_, err := s.runDeployBundle(c, path)
+	c.Assert(err, jc.ErrorIsNil)
+
+	out, err := s.runDeployBundle(c, path, "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "No changes to apply.\n")
+	_, err = s.runDeployBundle(c, path)
+	c.Assert(err, jc.ErrorIsNil)
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 3)
+	for _, name := range []string{"wordpress", "mysql", "dummy"} {
+		svc, err := s.State.Service(name)
+		c.Assert(err, jc.ErrorIsNil)
+		units, err := svc.AllUnits()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(units, gc.HasLen, 1)
+	}
+}
+
+func (s *DeploySuite) TestDeployBundleAddsMissingUnits(c *gc.C) {
+	s.setUpBundleCharms(c)
+	path := writeBundle(c, testBundle)
+	_, err := s.runDeployBundle(c, path)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Bundle machine 1 is mapped to the machine hosting wordpress/0.
+	path = writeBundle(c, strings.Replace(testBundle, `
+    num_units: 1
+    to: ["1"]`, `
+    num_units: 2
+    to: ["1", "lxc:1"]`, 1))
+	out, err := s.runDeployBundle(c, path, "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.Split(strings.TrimSpace(out), "\n"), jc.DeepEquals, []string{
+		"add unit 1 of service wordpress to new lxc container on machine 0",
+	})
+	_, err = s.runDeployBundle(c, path)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitMachine(c, "wordpress/1", "0/lxc/1")
+}
+
+func (s *DeploySuite) TestDeployBundleUpdatesOptionsAndConstraints(c *gc.C) {
+	s.setUpBundleCharms(c)
+	path := writeBundle(c, testBundle)
+	_, err := s.runDeployBundle(c, path)
+	c.Assert(err, jc.ErrorIsNil)
+
+	path = writeBundle(c, strings.Replace(testBundle, `
+    constraints: mem=2G
+    options:
+      skill-level: 42`, `
+    constraints: mem=4G
+    options:
+      skill-level: 43`, 1))
+	out, err := s.runDeployBundle(c, path, "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.Split(strings.TrimSpace(out), "\n"), jc.DeepEquals, []string{
+		"set options skill-level for service dummy",
+		"set constraints for service dummy to mem=4096M",
+	})
+	_, err = s.runDeployBundle(c, path)
+	c.Assert(err, jc.ErrorIsNil)
+
+	dummy, err := s.State.Service("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	cons, err := dummy.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=4G"))
+	settings, err := dummy.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings["skill-level"], gc.Equals, int64(43))
+}
+
+func (s *DeploySuite) TestDeployBundleExpose(c *gc.C) {
+	s.setUpBundleCharms(c)
+	path := writeBundle(c, strings.Replace(testBundle, `
+    annotations:
+      gui-x: "10"`, `
+    expose: true
+    expose_from: ["10.0.0.0/8"]`, 1))
+	out, err := s.runDeployBundle(c, path, "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, jc.Contains, "expose service wordpress to 10.0.0.0/8\n")
+	_, err = s.runDeployBundle(c, path)
+	c.Assert(err, jc.ErrorIsNil)
+
+	wordpress, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(wordpress.IsExposed(), jc.IsTrue)
+	c.Assert(wordpress.ExposedCIDRs(), jc.DeepEquals, []string{"10.0.0.0/8"})
+
+	out, err = s.runDeployBundle(c, path, "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "No changes to apply.\n")
+}
+
+func (s *DeploySuite) TestDeployBundleCharmMismatch(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	curl := charm.MustParseURL("local:trusty/dummy-1")
+	s.AssertService(c, "wordpress", curl, 1, 0)
+
+	path := writeBundle(c, testBundle)
+	_, err = s.runDeployBundle(c, path, "--dry-run")
+	c.Assert(err, gc.ErrorMatches, `service "wordpress" already deployed with charm "local:trusty/dummy-1", not "local:trusty/wordpress"`)
+}
+
+func (s *DeploySuite) TestDeployBundleBlocked(c *gc.C) {
+	s.setUpBundleCharms(c)
+	s.BlockAllChanges(c, "TestDeployBundleBlocked")
+	path := writeBundle(c, testBundle)
+	_, err := s.runDeployBundle(c, path)
+	s.AssertBlocked(c, err, ".*TestDeployBundleBlocked.*")
+}
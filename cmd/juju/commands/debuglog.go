@@ -6,12 +6,15 @@ package commands
 import (
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 )
 
@@ -19,6 +22,9 @@ type DebugLogCommand struct {
 	envcmd.EnvCommandBase
 
 	level  string
+	since  string
+	until  string
+	format string
 	params api.DebugLogParams
 }
 
@@ -31,6 +37,17 @@ const defaultLineCount = 10
 const debuglogDoc = `
 Stream the consolidated debug log file. This file contains the log messages
 from all nodes in the environment.
+
+When the environment stores its logs in the database, the logs recorded
+within a time range may be exported with --since and --until. Both accept
+an RFC3339 timestamp, a date, or a duration before the current time. Once
+the messages up to --until have been shown, the command exits. With
+--format json, each message is written as a single line JSON object.
+
+Examples:
+
+    juju debug-log --replay --since 2015-07-01 --until 2015-07-02 --format json > logs.json
+    juju debug-log --since 1h --level WARNING
 `
 
 func (c *DebugLogCommand) Info() *cmd.Info {
@@ -56,6 +73,9 @@ func (c *DebugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.UintVar(&c.params.Backlog, "lines", defaultLineCount, "")
 	f.UintVar(&c.params.Limit, "limit", 0, "show at most this many lines")
 	f.BoolVar(&c.params.Replay, "replay", false, "start filtering from the start")
+	f.StringVar(&c.since, "since", "", "only show log messages recorded at or after this time")
+	f.StringVar(&c.until, "until", "", "only show log messages recorded at or before this time, then exit")
+	f.StringVar(&c.format, "format", "", "output format, one of [text, json]")
 }
 
 func (c *DebugLogCommand) Init(args []string) error {
@@ -67,6 +87,33 @@ func (c *DebugLogCommand) Init(args []string) error {
 		}
 		c.params.Level = level
 	}
+	now := time.Now()
+	if c.since != "" {
+		since, err := parseTimeArg(c.since, now)
+		if err != nil {
+			return errors.Annotate(err, "invalid --since value")
+		}
+		c.params.StartTime = since
+		c.params.Replay = true
+	}
+	if c.until != "" {
+		until, err := parseTimeArg(c.until, now)
+		if err != nil {
+			return errors.Annotate(err, "invalid --until value")
+		}
+		c.params.EndTime = until
+	}
+	if !c.params.EndTime.IsZero() && c.params.EndTime.Before(c.params.StartTime) {
+		return errors.New("--until must not be before --since")
+	}
+	switch format := params.DebugLogFormat(c.format); format {
+	case "":
+	case params.DebugLogFormatText, params.DebugLogFormatJSON:
+		c.params.Format = format
+	default:
+		return fmt.Errorf("format value %q is not one of %q, %q",
+			c.format, params.DebugLogFormatText, params.DebugLogFormatJSON)
+	}
 	return cmd.CheckEmpty(args)
 }
 
@@ -7,12 +7,14 @@ import (
 	"io"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/testing"
 )
@@ -85,6 +87,29 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 				Backlog: 10,
 				Limit:   100,
 			},
+		}, {
+			args: []string{"--since", "2015-07-01T12:00:00Z", "--until", "2015-07-01T13:00:00Z"},
+			expected: api.DebugLogParams{
+				Backlog:   10,
+				Replay:    true,
+				StartTime: time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(2015, 7, 1, 13, 0, 0, 0, time.UTC),
+			},
+		}, {
+			args:     []string{"--since", "2015-07-01T12:00:00Z", "--until", "2015-07-01T11:00:00Z"},
+			errMatch: "--until must not be before --since",
+		}, {
+			args:     []string{"--since", "yesterday"},
+			errMatch: `invalid --since value: "yesterday" is not a timestamp, date or duration`,
+		}, {
+			args: []string{"--format", "json"},
+			expected: api.DebugLogParams{
+				Backlog: 10,
+				Format:  params.DebugLogFormatJSON,
+			},
+		}, {
+			args:     []string{"--format", "xml"},
+			errMatch: `format value "xml" is not one of "text", "json"`,
 		},
 	} {
 		c.Logf("test %v", i)
@@ -37,6 +37,14 @@ type DeployCommand struct {
 	RepoPath     string // defaults to JUJU_REPOSITORY
 	RegisterURL  string
 
+	// BundlePath holds the path of the bundle file to deploy, if a
+	// bundle rather than a charm is being deployed.
+	BundlePath string
+
+	// DryRun causes the changes needed to deploy a bundle to be
+	// printed rather than applied.
+	DryRun bool
+
 	// TODO(axw) move this to UnitCommandBase once we support --storage
 	// on add-unit too.
 	//
@@ -113,7 +121,49 @@ to configure on it. The --networks argument instructs juju to add all the
 networks specified with it to all new machines deployed to host units of
 the service. Not supported on all providers.
 
+A bundle, describing a set of services and the relations between them,
+can be deployed by passing the path of a bundle file, which must end
+in ".yaml" or ".yml", instead of a charm name. For example:
+
+  series: trusty
+  services:
+    wordpress:
+      charm: cs:trusty/wordpress
+      num_units: 2
+      to: ["1", "lxc:1"]
+      options:
+        debug: "yes"
+      constraints: mem=2G
+      expose: true
+      expose_from: ["10.0.0.0/8"]
+    mysql:
+      charm: cs:trusty/mysql
+      num_units: 1
+      to: ["lxc:wordpress/0"]
+      storage:
+        data: ebs,10G
+  machines:
+    "1":
+      constraints: cpu-cores=4
+  relations:
+    - ["wordpress:db", "mysql:db"]
+
+Units are placed in order by the "to" directives of their service, which
+may name a bundle machine ("1"), a unit of another service ("mysql/0"),
+the machine of the unit of another service with the same index ("mysql")
+or a new machine ("new"), each optionally inside a new container on that
+machine ("lxc:1"). Units without a directive are deployed to new
+machines. Services, units and relations that are already deployed are
+kept, so deploying a bundle again only adds what is missing, and sets
+the options and constraints of deployed services that differ from the
+bundle.
+Use --dry-run to print the changes that would be made without making
+them.
+
+   juju deploy bundle.yaml --dry-run
+
 See Also:
+   juju help export-bundle
    juju help constraints
    juju help set-constraints
    juju help get-constraints
@@ -122,7 +172,7 @@ See Also:
 func (c *DeployCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "deploy",
-		Args:    "<charm name> [<service name>]",
+		Args:    "<charm name> [<service name>] | <bundle file>",
 		Purpose: "deploy a new service",
 		Doc:     deployDoc,
 	}
@@ -138,9 +188,16 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.Networks, "networks", "", "bind the service to specific networks")
 	f.StringVar(&c.RepoPath, "repository", os.Getenv(osenv.JujuRepositoryEnvKey), "local charm repository")
 	f.Var(storageFlag{&c.Storage}, "storage", "charm storage constraints")
+	f.BoolVar(&c.DryRun, "dry-run", false, "print the changes needed to deploy a bundle without applying them")
 }
 
 func (c *DeployCommand) Init(args []string) error {
+	if len(args) > 0 && isBundlePath(args[0]) {
+		return c.initBundle(args)
+	}
+	if c.DryRun {
+		return errors.New("--dry-run is only supported when deploying a bundle")
+	}
 	switch len(args) {
 	case 2:
 		if !names.IsValidService(args[1]) {
@@ -161,6 +218,21 @@ func (c *DeployCommand) Init(args []string) error {
 	return c.UnitCommandBase.Init(args)
 }
 
+// initBundle checks the arguments used to deploy a bundle. The
+// settings of a bundle's services are all given in the bundle, so
+// none of the flags that apply to a single service may be used.
+func (c *DeployCommand) initBundle(args []string) error {
+	if err := cmd.CheckEmpty(args[1:]); err != nil {
+		return err
+	}
+	if c.NumUnits != 1 || c.PlacementSpec != "" || c.Config.Path != "" ||
+		!constraints.IsEmpty(&c.Constraints) || c.Networks != "" || len(c.Storage) > 0 {
+		return errors.New("--num-units, --to, --config, --constraints, --networks and --storage cannot be used when deploying a bundle")
+	}
+	c.BundlePath = args[0]
+	return nil
+}
+
 func (c *DeployCommand) newServiceAPIClient() (*apiservice.Client, error) {
 	root, err := c.NewAPIRoot()
 	if err != nil {
@@ -181,6 +253,10 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 
+	if c.BundlePath != "" {
+		return c.deployBundle(ctx, client, conf)
+	}
+
 	if err := c.CheckProvider(conf); err != nil {
 		return err
 	}
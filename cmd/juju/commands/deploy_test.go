@@ -83,6 +83,18 @@ var initErrorTests = []struct {
 	}, {
 		args: []string{"craziness", "burble1", "--constraints", "gibber=plop"},
 		err:  `invalid value "gibber=plop" for flag --constraints: unknown constraint "gibber"`,
+	}, {
+		args: []string{"craziness", "--dry-run"},
+		err:  `--dry-run is only supported when deploying a bundle`,
+	}, {
+		args: []string{"bundle.yaml", "burble1"},
+		err:  `unrecognized args: \["burble1"\]`,
+	}, {
+		args: []string{"bundle.yaml", "-n", "2"},
+		err:  `--num-units, --to, --config, --constraints, --networks and --storage cannot be used when deploying a bundle`,
+	}, {
+		args: []string{"bundle.yml", "--to", "1"},
+		err:  `--num-units, --to, --config, --constraints, --networks and --storage cannot be used when deploying a bundle`,
 	},
 }
 
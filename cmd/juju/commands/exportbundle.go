@@ -0,0 +1,84 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io/ioutil"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// ExportBundleCommand writes a bundle describing the services in the
+// environment.
+type ExportBundleCommand struct {
+	envcmd.EnvCommandBase
+	Filename string
+}
+
+const exportBundleDoc = `
+Writes a bundle describing the services deployed in the environment,
+their charms, configuration, constraints, storage, exposure and unit
+placement, the machines hosting their units and the relations between
+them. The bundle can be deployed into another environment with
+"juju deploy", to reproduce this one.
+
+Machines in the bundle are identified by the ids of the machines they
+describe in this environment. Units in containers are placed in new
+containers of the same type.
+
+The bundle includes the options of every service, so only environment
+administrators may export it.
+
+Examples:
+   juju export-bundle
+   juju export-bundle --filename staging.yaml
+
+See Also:
+   juju help deploy
+`
+
+func (c *ExportBundleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "export-bundle",
+		Purpose: "export the environment's services as a bundle",
+		Doc:     exportBundleDoc,
+	}
+}
+
+func (c *ExportBundleCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Filename, "filename", "", "write the bundle to this file rather than to stdout")
+}
+
+func (c *ExportBundleCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *ExportBundleCommand) Run(ctx *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	bundle, err := client.ExportBundle()
+	if params.IsCodeNotImplemented(err) {
+		return errors.New("cannot export bundle: not supported by the API server")
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if c.Filename == "" {
+		_, err := ctx.Stdout.Write([]byte(bundle))
+		return err
+	}
+	if err := ioutil.WriteFile(ctx.AbsPath(c.Filename), []byte(bundle), 0644); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("Bundle written to %q.", c.Filename)
+	return nil
+}
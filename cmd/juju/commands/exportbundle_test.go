@@ -0,0 +1,74 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testcharms"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ExportBundleSuite struct {
+	testing.RepoSuite
+}
+
+var _ = gc.Suite(&ExportBundleSuite{})
+
+func runExportBundle(c *gc.C, args ...string) (string, error) {
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&ExportBundleCommand{}), args...)
+	if err != nil {
+		return "", err
+	}
+	return coretesting.Stdout(ctx), nil
+}
+
+func (s *ExportBundleSuite) TestInitErrors(c *gc.C) {
+	err := coretesting.InitCommand(envcmd.Wrap(&ExportBundleCommand{}), []string{"foo"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["foo"\]`)
+}
+
+func (s *ExportBundleSuite) TestExportBundleRoundTrip(c *gc.C) {
+	for _, name := range []string{"wordpress", "mysql", "dummy"} {
+		testcharms.Repo.CharmArchivePath(s.SeriesPath, name)
+	}
+	_, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), writeBundle(c, testBundle))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.APIState.Client().ServiceExpose("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+
+	out, err := runExportBundle(c)
+	c.Assert(err, jc.ErrorIsNil)
+	bundle, err := readBundle(writeBundle(c, out))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bundle.Services, gc.HasLen, 3)
+	wordpress := bundle.Services["wordpress"]
+	c.Assert(wordpress.Charm, gc.Equals, "local:trusty/wordpress")
+	c.Assert(wordpress.NumUnits, gc.Equals, 1)
+	c.Assert(wordpress.Expose, jc.IsTrue)
+	c.Assert(bundle.Services["mysql"].To, jc.DeepEquals, []string{"lxc:0"})
+	c.Assert(bundle.Services["dummy"].Constraints, gc.Equals, "mem=2048M")
+	c.Assert(bundle.Services["dummy"].Options, jc.DeepEquals, map[string]interface{}{"skill-level": 42})
+	c.Assert(bundle.Machines, gc.HasLen, 2)
+	c.Assert(bundle.Machines["0"].Constraints, gc.Equals, "cpu-cores=2")
+	c.Assert(bundle.Relations, jc.DeepEquals, [][]string{{"wordpress:db", "mysql:server"}})
+
+	// Deploying the exported bundle into the environment it describes
+	// changes nothing.
+	path := filepath.Join(c.MkDir(), "exported.yaml")
+	_, err = runExportBundle(c, "--filename", path)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, out)
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), path, "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, "No changes to apply.\n")
+}
@@ -5,23 +5,36 @@ package commands
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/network"
 )
 
 // ExposeCommand is responsible exposing services.
 type ExposeCommand struct {
 	envcmd.EnvCommandBase
 	ServiceName string
+	SourceCIDRs []string
 }
 
 var jujuExposeHelp = `
 Adjusts firewall rules and similar security mechanisms of the provider, to
 allow the service to be accessed on its public address.
 
+By default the service may be accessed from any address. The --from-cidr
+option, which may be repeated or given a comma-separated list, restricts
+access to the given IPv4 CIDRs, replacing any the service was already
+exposed to. Not all providers support this.
+
+Examples:
+   juju expose wordpress
+   juju expose wordpress --from-cidr 10.0.0.0/8
+   juju expose wordpress --from-cidr 10.0.0.0/8,192.168.0.0/16
 `
 
 func (c *ExposeCommand) Info() *cmd.Info {
@@ -33,11 +46,25 @@ func (c *ExposeCommand) Info() *cmd.Info {
 	}
 }
 
+func (c *ExposeCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(cmd.NewAppendStringsValue(&c.SourceCIDRs), "from-cidr", "expose the service to these CIDRs only")
+}
+
 func (c *ExposeCommand) Init(args []string) error {
 	if len(args) == 0 {
 		return errors.New("no service name specified")
 	}
 	c.ServiceName = args[0]
+	var cidrs []string
+	for _, value := range c.SourceCIDRs {
+		for _, cidr := range strings.Split(value, ",") {
+			cidrs = append(cidrs, strings.TrimSpace(cidr))
+		}
+	}
+	if err := network.ValidateCIDRs(cidrs); err != nil {
+		return err
+	}
+	c.SourceCIDRs = cidrs
 	return cmd.CheckEmpty(args[1:])
 }
 
@@ -49,5 +76,5 @@ func (c *ExposeCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
-	return block.ProcessBlockedError(client.ServiceExpose(c.ServiceName), block.BlockChange)
+	return block.ProcessBlockedError(client.ServiceExposeFrom(c.ServiceName, c.SourceCIDRs), block.BlockChange)
 }
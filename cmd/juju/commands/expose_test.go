@@ -55,6 +55,35 @@ func (s *ExposeSuite) TestExpose(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `service "nonexistent-service" not found`)
 }
 
+func (s *ExposeSuite) TestExposeFromCIDRs(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy", "some-service-name")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = runExpose(c, "some-service-name", "--from-cidr", "192.168.0.0/16,10.0.0.0/8", "--from-cidr", "172.16.0.0/12")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertExposed(c, "some-service-name")
+	s.assertExposedCIDRs(c, "some-service-name", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16")
+
+	// Exposing the service again replaces the CIDRs.
+	err = runExpose(c, "some-service-name", "--from-cidr", "10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertExposedCIDRs(c, "some-service-name", "10.0.0.0/8")
+	err = runExpose(c, "some-service-name")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertExposed(c, "some-service-name")
+	s.assertExposedCIDRs(c, "some-service-name")
+
+	err = runExpose(c, "some-service-name", "--from-cidr", "10.0.0.0")
+	c.Assert(err, gc.ErrorMatches, `invalid CIDR "10.0.0.0"`)
+}
+
+func (s *ExposeSuite) assertExposedCIDRs(c *gc.C, service string, cidrs ...string) {
+	svc, err := s.State.Service(service)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.ExposedCIDRs(), jc.DeepEquals, cidrs)
+}
+
 func (s *ExposeSuite) TestBlockExpose(c *gc.C) {
 	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
 	err := runDeploy(c, "local:dummy", "some-service-name")
@@ -28,6 +28,7 @@ import (
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/tracing"
 	// Import the providers.
 	_ "github.com/juju/juju/provider/all"
 	"github.com/juju/juju/version"
@@ -68,7 +69,10 @@ func Main(args []string) {
 		os.Exit(0)
 	}
 	jcmd := NewJujuCommand(ctx)
-	os.Exit(cmd.Main(jcmd, ctx, args[1:]))
+	os.Exit(runTraced(ctx, args, func(span *tracing.Span) int {
+		juju.SetTraceSpan(span)
+		return cmd.Main(jcmd, ctx, args[1:])
+	}))
 }
 
 func NewJujuCommand(ctx *cmd.Context) cmd.Command {
@@ -119,6 +123,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// Creation commands.
 	r.Register(wrapEnvCommand(&BootstrapCommand{}))
 	r.Register(wrapEnvCommand(&DeployCommand{}))
+	r.Register(wrapEnvCommand(&ExportBundleCommand{}))
 	r.Register(wrapEnvCommand(&AddRelationCommand{}))
 
 	// Destruction commands.
@@ -133,6 +138,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(wrapEnvCommand(&EndpointCommand{}))
 	r.Register(wrapEnvCommand(&APIInfoCommand{}))
 	r.Register(wrapEnvCommand(&StatusHistoryCommand{}))
+	r.Register(wrapEnvCommand(&AuditLogCommand{}))
 
 	// Error resolution and debugging commands.
 	r.Register(wrapEnvCommand(&RunCommand{}))
@@ -4,12 +4,16 @@
 package commands
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"strconv"
+	"text/tabwriter"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/params"
@@ -24,25 +28,41 @@ type StatusHistoryCommand struct {
 	outputContent string
 	backlogSize   int
 	isoTime       bool
-	unitName      string
+	since         string
+	until         string
+	entityName    string
+	args          params.StatusHistory
 }
 
 var statusHistoryDoc = `
 This command will report the history of status changes for
-a given unit.
+a given unit, machine or service.
 The statuses for the unit workload and/or agent are available.
 -type supports:
     agent: will show statuses for the unit's agent
     workload: will show statuses for the unit's workload
     combined: will show agent and workload statuses combined
  and sorted by time of occurence.
+-type only applies to units; machines and services have a
+single status.
+
+The history may be restricted to a time range with --since
+and --until. Times may be given as RFC3339 timestamps
+("2015-07-01T12:00:00Z"), as dates ("2015-07-01"), or as
+durations relative to now ("90m", "24h").
+
+Examples:
+    juju status-history mysql/0
+    juju status-history --type agent --since 24h mysql/0
+    juju status-history --format json 0
+    juju status-history -n 50 mysql
 `
 
 func (c *StatusHistoryCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "status-history",
-		Args:    "[-n N] <unit>",
-		Purpose: "output past statuses for a unit",
+		Args:    "[-n N] <unit|machine|service>",
+		Purpose: "output past statuses for a unit, machine or service",
 		Doc:     statusHistoryDoc,
 	}
 }
@@ -51,16 +71,23 @@ func (c *StatusHistoryCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.outputContent, "type", "combined", "type of statuses to be displayed [agent|workload|combined].")
 	f.IntVar(&c.backlogSize, "n", 20, "size of logs backlog.")
 	f.BoolVar(&c.isoTime, "utc", false, "display time as UTC in RFC3339 format")
+	f.StringVar(&c.since, "since", "", "only show statuses set at or after this time")
+	f.StringVar(&c.until, "until", "", "only show statuses set at or before this time")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": c.formatTabular,
+	})
 }
 
 func (c *StatusHistoryCommand) Init(args []string) error {
 	switch {
 	case len(args) > 1:
-		return errors.Errorf("unexpected arguments after unit name.")
+		return errors.Errorf("unexpected arguments after entity name.")
 	case len(args) == 0:
-		return errors.Errorf("unit name is missing.")
+		return errors.Errorf("entity name is missing.")
 	default:
-		c.unitName = args[0]
+		c.entityName = args[0]
 	}
 	// If use of ISO time not specified on command line,
 	// check env var.
@@ -73,24 +100,83 @@ func (c *StatusHistoryCommand) Init(args []string) error {
 			}
 		}
 	}
+	if c.backlogSize < 1 {
+		return errors.Errorf("invalid backlog size %d", c.backlogSize)
+	}
 	kind := params.HistoryKind(c.outputContent)
 	switch kind {
 	case params.KindCombined, params.KindAgent, params.KindWorkload:
-		return nil
-
+	default:
+		return errors.Errorf("unexpected status type %q", c.outputContent)
+	}
+	switch {
+	case names.IsValidUnit(c.entityName):
+	case kind != params.KindCombined:
+		return errors.Errorf("status type %q only applies to units", c.outputContent)
+	case names.IsValidMachine(c.entityName):
+		kind = params.KindMachine
+	case names.IsValidService(c.entityName):
+		kind = params.KindService
+	default:
+		return errors.Errorf("%q is not a valid unit, machine or service name", c.entityName)
 	}
-	return errors.Errorf("unexpected status type %q", c.outputContent)
+	c.args = params.StatusHistory{
+		Kind: kind,
+		Size: c.backlogSize,
+		Name: c.entityName,
+	}
+	now := time.Now()
+	if c.since != "" {
+		since, err := parseTimeArg(c.since, now)
+		if err != nil {
+			return errors.Annotate(err, "invalid --since value")
+		}
+		c.args.Since = &since
+	}
+	if c.until != "" {
+		until, err := parseTimeArg(c.until, now)
+		if err != nil {
+			return errors.Annotate(err, "invalid --until value")
+		}
+		c.args.Until = &until
+	}
+	if c.args.Since != nil && c.args.Until != nil && c.args.Until.Before(*c.args.Since) {
+		return errors.New("--until must not be before --since")
+	}
+	return nil
+}
+
+// StatusHistoryAPI defines the API methods used by the status-history
+// command.
+type StatusHistoryAPI interface {
+	StatusHistory(args params.StatusHistory) (*api.UnitStatusHistory, error)
+	UnitStatusHistory(kind params.HistoryKind, unitName string, size int) (*api.UnitStatusHistory, error)
+	Close() error
+}
+
+var getStatusHistoryAPI = func(c *StatusHistoryCommand) (StatusHistoryAPI, error) {
+	return c.NewAPIClient()
+}
+
+// historyEntry holds a single status history entry for output.
+type historyEntry struct {
+	Time    string                 `json:"time" yaml:"time"`
+	Type    string                 `json:"type" yaml:"type"`
+	Status  string                 `json:"status" yaml:"status"`
+	Message string                 `json:"message,omitempty" yaml:"message,omitempty"`
+	Data    map[string]interface{} `json:"data,omitempty" yaml:"data,omitempty"`
 }
 
 func (c *StatusHistoryCommand) Run(ctx *cmd.Context) error {
-	apiclient, err := c.NewAPIClient()
+	apiclient, err := getStatusHistoryAPI(c)
 	if err != nil {
 		return fmt.Errorf(connectionError, c.ConnectionName(), err)
 	}
 	defer apiclient.Close()
-	var statuses *api.UnitStatusHistory
-	kind := params.HistoryKind(c.outputContent)
-	statuses, err = apiclient.UnitStatusHistory(kind, c.unitName, c.backlogSize)
+	statuses, err := apiclient.StatusHistory(c.args)
+	if errors.IsNotImplemented(err) {
+		statuses, err = c.legacyStatusHistory(apiclient)
+	}
 	if err != nil {
 		if len(statuses.Statuses) == 0 {
 			return errors.Trace(err)
@@ -100,20 +186,44 @@ func (c *StatusHistoryCommand) Run(ctx *cmd.Context) error {
 	} else if len(statuses.Statuses) == 0 {
 		return errors.Errorf("no status history available")
 	}
-	table := [][]string{{"TIME", "TYPE", "STATUS", "MESSAGE"}}
-	lengths := []int{1, 1, 1, 1}
-	for _, v := range statuses.Statuses {
-		fields := []string{formatStatusTime(v.Since, c.isoTime), string(v.Kind), string(v.Status), v.Info}
-		for k, v := range fields {
-			if len(v) > lengths[k] {
-				lengths[k] = len(v)
-			}
+	entries := make([]historyEntry, len(statuses.Statuses))
+	for i, v := range statuses.Statuses {
+		entries[i] = historyEntry{
+			Time:    formatStatusTime(v.Since, c.isoTime),
+			Type:    string(v.Kind),
+			Status:  string(v.Status),
+			Message: v.Info,
+			Data:    v.Data,
 		}
-		table = append(table, fields)
 	}
-	f := fmt.Sprintf("%%-%ds\t%%-%ds\t%%-%ds\t%%-%ds\n", lengths[0], lengths[1], lengths[2], lengths[3])
-	for _, v := range table {
-		fmt.Printf(f, v[0], v[1], v[2], v[3])
+	return c.out.Write(ctx, entries)
+}
+
+// legacyStatusHistory fetches the history of a unit from an API server
+// that predates the StatusHistory call, and so supports neither
+// machines, services nor time ranges.
+func (c *StatusHistoryCommand) legacyStatusHistory(apiclient StatusHistoryAPI) (*api.UnitStatusHistory, error) {
+	switch c.args.Kind {
+	case params.KindMachine, params.KindService:
+		return &api.UnitStatusHistory{}, errors.Errorf("status history for %s %q not supported by the API server", c.args.Kind, c.args.Name)
 	}
-	return nil
+	if c.args.Since != nil || c.args.Until != nil {
+		return &api.UnitStatusHistory{}, errors.New("--since and --until not supported by the API server")
+	}
+	return apiclient.UnitStatusHistory(c.args.Kind, c.args.Name, c.args.Size)
+}
+
+func (c *StatusHistoryCommand) formatTabular(value interface{}) ([]byte, error) {
+	entries, ok := value.([]historyEntry)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", entries, value)
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tTYPE\tSTATUS\tMESSAGE")
+	for _, entry := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", entry.Time, entry.Type, entry.Status, entry.Message)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
 }
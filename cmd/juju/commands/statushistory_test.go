@@ -0,0 +1,212 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/testing"
+)
+
+type StatusHistorySuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeStatusHistoryAPI
+}
+
+var _ = gc.Suite(&StatusHistorySuite{})
+
+func (s *StatusHistorySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.PatchEnvironment(osenv.JujuStatusIsoTimeEnvKey, "")
+	s.api = &fakeStatusHistoryAPI{}
+	s.PatchValue(&getStatusHistoryAPI, func(*StatusHistoryCommand) (StatusHistoryAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *StatusHistorySuite) TestInit(c *gc.C) {
+	since := time.Date(2015, 7, 1, 0, 0, 0, 0, time.Local)
+	until := time.Date(2015, 7, 2, 12, 0, 0, 0, time.UTC)
+	for i, test := range []struct {
+		args     []string
+		expected params.StatusHistory
+		errMatch string
+	}{{
+		args:     []string{"mysql/0"},
+		expected: params.StatusHistory{Kind: params.KindCombined, Size: 20, Name: "mysql/0"},
+	}, {
+		args:     []string{"--type", "agent", "-n", "5", "mysql/0"},
+		expected: params.StatusHistory{Kind: params.KindAgent, Size: 5, Name: "mysql/0"},
+	}, {
+		args:     []string{"1/lxc/0"},
+		expected: params.StatusHistory{Kind: params.KindMachine, Size: 20, Name: "1/lxc/0"},
+	}, {
+		args:     []string{"mysql"},
+		expected: params.StatusHistory{Kind: params.KindService, Size: 20, Name: "mysql"},
+	}, {
+		args: []string{"--since", "2015-07-01", "--until", "2015-07-02T12:00:00Z", "mysql/0"},
+		expected: params.StatusHistory{
+			Kind:  params.KindCombined,
+			Size:  20,
+			Name:  "mysql/0",
+			Since: &since,
+			Until: &until,
+		},
+	}, {
+		errMatch: "entity name is missing.",
+	}, {
+		args:     []string{"mysql/0", "mysql/1"},
+		errMatch: "unexpected arguments after entity name.",
+	}, {
+		args:     []string{"--type", "bogus", "mysql/0"},
+		errMatch: `unexpected status type "bogus"`,
+	}, {
+		args:     []string{"--type", "workload", "0"},
+		errMatch: `status type "workload" only applies to units`,
+	}, {
+		args:     []string{"-n", "0", "mysql/0"},
+		errMatch: "invalid backlog size 0",
+	}, {
+		args:     []string{"Not-Valid!"},
+		errMatch: `"Not-Valid!" is not a valid unit, machine or service name`,
+	}, {
+		args:     []string{"--since", "yesterday", "mysql/0"},
+		errMatch: `invalid --since value: "yesterday" is not a timestamp, date or duration`,
+	}, {
+		args:     []string{"--since", "2015-07-02", "--until", "2015-07-01", "mysql/0"},
+		errMatch: "--until must not be before --since",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &StatusHistoryCommand{}
+		err := testing.InitCommand(envcmd.Wrap(command), test.args)
+		if test.errMatch != "" {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(command.args, jc.DeepEquals, test.expected)
+	}
+}
+
+func (s *StatusHistorySuite) setHistory() {
+	t0 := time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+	t1 := time.Date(2015, 7, 1, 12, 5, 0, 0, time.UTC)
+	s.api.history = &api.UnitStatusHistory{
+		Statuses: []api.AgentStatus{{
+			Status: params.StatusPending,
+			Since:  &t0,
+			Kind:   params.KindMachine,
+		}, {
+			Status: params.StatusStarted,
+			Info:   "running",
+			Data:   map[string]interface{}{"foo": "bar"},
+			Since:  &t1,
+			Kind:   params.KindMachine,
+		}},
+	}
+}
+
+func (s *StatusHistorySuite) TestRunTabular(c *gc.C) {
+	s.setHistory()
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&StatusHistoryCommand{}), "--utc", "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.args, jc.DeepEquals, params.StatusHistory{
+		Kind: params.KindMachine,
+		Size: 20,
+		Name: "0",
+	})
+	c.Assert(s.api.closed, jc.IsTrue)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"TIME                 TYPE    STATUS  MESSAGE\n"+
+		"2015-07-01 12:00:00Z machine pending \n"+
+		"2015-07-01 12:05:00Z machine started running\n",
+	)
+}
+
+func (s *StatusHistorySuite) TestRunJSON(c *gc.C) {
+	s.setHistory()
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&StatusHistoryCommand{}), "--utc", "--format", "json", "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		`[{"time":"2015-07-01 12:00:00Z","type":"machine","status":"pending"},`+
+		`{"time":"2015-07-01 12:05:00Z","type":"machine","status":"started","message":"running","data":{"foo":"bar"}}]`+"\n",
+	)
+}
+
+func (s *StatusHistorySuite) TestRunYAML(c *gc.C) {
+	s.setHistory()
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&StatusHistoryCommand{}), "--utc", "--format", "yaml", "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+- time: 2015-07-01 12:00:00Z
+  type: machine
+  status: pending
+- time: 2015-07-01 12:05:00Z
+  type: machine
+  status: started
+  message: running
+  data:
+    foo: bar
+`[1:])
+}
+
+func (s *StatusHistorySuite) TestRunNoHistory(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&StatusHistoryCommand{}), "0")
+	c.Assert(err, gc.ErrorMatches, "no status history available")
+}
+
+func (s *StatusHistorySuite) TestRunLegacyServer(c *gc.C) {
+	s.setHistory()
+	s.api.notImplemented = true
+	_, err := testing.RunCommand(c, envcmd.Wrap(&StatusHistoryCommand{}), "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.legacyCalled, jc.IsTrue)
+
+	_, err = testing.RunCommand(c, envcmd.Wrap(&StatusHistoryCommand{}), "0")
+	c.Assert(err, gc.ErrorMatches, `status history for machine "0" not supported by the API server`)
+
+	_, err = testing.RunCommand(c, envcmd.Wrap(&StatusHistoryCommand{}), "--since", "1h", "mysql/0")
+	c.Assert(err, gc.ErrorMatches, "--since and --until not supported by the API server")
+}
+
+type fakeStatusHistoryAPI struct {
+	history        *api.UnitStatusHistory
+	args           params.StatusHistory
+	notImplemented bool
+	legacyCalled   bool
+	closed         bool
+}
+
+func (f *fakeStatusHistoryAPI) result() *api.UnitStatusHistory {
+	if f.history == nil {
+		return &api.UnitStatusHistory{}
+	}
+	return f.history
+}
+
+func (f *fakeStatusHistoryAPI) StatusHistory(args params.StatusHistory) (*api.UnitStatusHistory, error) {
+	f.args = args
+	if f.notImplemented {
+		return &api.UnitStatusHistory{}, errors.NotImplementedf("StatusHistory")
+	}
+	return f.result(), nil
+}
+
+func (f *fakeStatusHistoryAPI) UnitStatusHistory(kind params.HistoryKind, unitName string, size int) (*api.UnitStatusHistory, error) {
+	f.legacyCalled = true
+	return f.result(), nil
+}
+
+func (f *fakeStatusHistoryAPI) Close() error {
+	f.closed = true
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/tracing"
+)
+
+// runTraced calls run, which runs the juju command with the given
+// arguments, and returns its exit code. If $JUJU_TRACE_ENDPOINT is set,
+// the command is recorded in a span exported to that endpoint, which
+// is passed to run so that the API requests made by the command can be
+// traced on its behalf through the API server. Otherwise run is passed
+// a nil span.
+func runTraced(ctx *cmd.Context, args []string, run func(span *tracing.Span) int) int {
+	endpoint := os.Getenv(osenv.JujuTraceEndpointEnvKey)
+	if endpoint == "" {
+		return run(nil)
+	}
+	tracer, err := tracing.NewTracer("juju", endpoint)
+	if err != nil {
+		fmt.Fprintf(ctx.Stderr, "WARNING not tracing command: %v\n", err)
+		return run(nil)
+	}
+	defer func() {
+		if err := tracer.Close(); err != nil {
+			fmt.Fprintf(ctx.Stderr, "WARNING cannot export trace: %v\n", err)
+		}
+	}()
+	span := tracer.StartSpan(commandSpanName(args), tracing.SpanKindInternal, tracing.SpanContext{})
+	defer span.End()
+	code := run(span)
+	span.SetAttribute("juju.exit-code", code)
+	if code != 0 {
+		span.SetError(fmt.Errorf("exit code %d", code))
+	}
+	return code
+}
+
+// commandSpanName returns the name of the span recording the juju
+// command with the given arguments. Only the command name is used,
+// as the other arguments may hold secrets.
+func commandSpanName(args []string) string {
+	for _, arg := range args[1:] {
+		if !strings.HasPrefix(arg, "-") {
+			return "juju " + arg
+		}
+	}
+	return "juju"
+}
@@ -0,0 +1,61 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/tracing"
+)
+
+type TracingSuite struct {
+	testing.FakeJujuHomeSuite
+}
+
+var _ = gc.Suite(&TracingSuite{})
+
+func (s *TracingSuite) TestNotTraced(c *gc.C) {
+	s.PatchEnvironment(osenv.JujuTraceEndpointEnvKey, "")
+	ctx := testing.Context(c)
+	code := runTraced(ctx, []string{"juju", "status"}, func(span *tracing.Span) int {
+		c.Check(span, gc.IsNil)
+		return 0
+	})
+	c.Assert(code, gc.Equals, 0)
+}
+
+func (s *TracingSuite) TestTraced(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "traces.json")
+	s.PatchEnvironment(osenv.JujuTraceEndpointEnvKey, path)
+	ctx := testing.Context(c)
+	var traceId string
+	code := runTraced(ctx, []string{"juju", "--debug", "deploy", "mysql"}, func(span *tracing.Span) int {
+		c.Check(span, gc.NotNil)
+		traceId = span.Context().TraceID.String()
+		return 1
+	})
+	c.Assert(code, gc.Equals, 1)
+
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Matches,
+		`(?s).*\{"traceId":"`+traceId+`",[^{]*"name":"juju deploy",.*"status":\{"code":2,"message":"exit code 1"\}.*`)
+}
+
+func (s *TracingSuite) TestInvalidEndpoint(c *gc.C) {
+	s.PatchEnvironment(osenv.JujuTraceEndpointEnvKey, "traces.json")
+	ctx := testing.Context(c)
+	code := runTraced(ctx, []string{"juju", "status"}, func(span *tracing.Span) int {
+		c.Check(span, gc.IsNil)
+		return 0
+	})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(testing.Stderr(ctx), gc.Matches, `WARNING not tracing command: invalid trace endpoint "traces.json": file path must be absolute\n`)
+}
@@ -13,6 +13,7 @@ import (
 	"gopkg.in/juju/charm.v5"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/juju/service"
@@ -26,6 +27,7 @@ type UpgradeCharmCommand struct {
 	RepoPath    string // defaults to JUJU_REPOSITORY
 	SwitchURL   string
 	Revision    int // defaults to -1 (latest)
+	Rollback    bool
 }
 
 const upgradeCharmDoc = `
@@ -64,6 +66,14 @@ would specify revision number 5 of the wordpress charm.
 Use of the --force flag is not generally recommended; units upgraded while in an
 error state will not have upgrade-charm hooks executed, and may cause unexpected
 behavior.
+
+The --rollback flag returns the service to the charm it ran before its charm
+was last changed; for instance, after an upgrade whose upgrade-charm hook fails
+on some units. All units are moved back to that charm, even if they are in an
+error state, as with --force. Units keep a copy of the charm they last upgraded
+from, so no download is needed. Rolling back twice returns the service to the
+charm it was rolled back from. --rollback cannot be combined with any other
+flag.
 `
 
 func (c *UpgradeCharmCommand) Info() *cmd.Info {
@@ -80,6 +90,7 @@ func (c *UpgradeCharmCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.RepoPath, "repository", os.Getenv("JUJU_REPOSITORY"), "local charm repository path")
 	f.StringVar(&c.SwitchURL, "switch", "", "crossgrade to a different charm")
 	f.IntVar(&c.Revision, "revision", -1, "explicit revision of current charm")
+	f.BoolVar(&c.Rollback, "rollback", false, "return to the charm the service ran before its last upgrade")
 }
 
 func (c *UpgradeCharmCommand) Init(args []string) error {
@@ -97,6 +108,9 @@ func (c *UpgradeCharmCommand) Init(args []string) error {
 	if c.SwitchURL != "" && c.Revision != -1 {
 		return fmt.Errorf("--switch and --revision are mutually exclusive")
 	}
+	if c.Rollback && (c.SwitchURL != "" || c.Revision != -1 || c.Force) {
+		return fmt.Errorf("--rollback cannot be used with --switch, --revision or --force")
+	}
 	return nil
 }
 
@@ -108,6 +122,17 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
+	if c.Rollback {
+		curl, err := client.ServiceRollbackCharm(c.ServiceName)
+		if params.IsCodeNotImplemented(err) {
+			return errors.New("cannot roll back charm: not supported by the API server")
+		}
+		if err != nil {
+			return err
+		}
+		ctx.Infof("Rolled back service %q to charm %q.", c.ServiceName, curl)
+		return nil
+	}
 	oldURL, err := client.ServiceGetCharmURL(c.ServiceName)
 	if err != nil {
 		return err
@@ -102,6 +102,20 @@ func (s *UpgradeCharmErrorsSuite) TestSwitchAndRevisionFails(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "--switch and --revision are mutually exclusive")
 }
 
+func (s *UpgradeCharmErrorsSuite) TestRollbackWithOtherFlagsFails(c *gc.C) {
+	s.deployService(c)
+	for _, flag := range []string{"--switch=riak", "--revision=2", "--force"} {
+		err := runUpgradeCharm(c, "riak", "--rollback", flag)
+		c.Assert(err, gc.ErrorMatches, "--rollback cannot be used with --switch, --revision or --force")
+	}
+}
+
+func (s *UpgradeCharmErrorsSuite) TestRollbackWithoutUpgrade(c *gc.C) {
+	s.deployService(c)
+	err := runUpgradeCharm(c, "riak", "--rollback")
+	c.Assert(err, gc.ErrorMatches, `cannot roll back charm of service "riak": previous charm not found`)
+}
+
 func (s *UpgradeCharmErrorsSuite) TestInvalidRevision(c *gc.C) {
 	s.deployService(c)
 	err := runUpgradeCharm(c, "riak", "--revision=blah")
@@ -160,6 +174,21 @@ func (s *UpgradeCharmSuccessSuite) TestLocalRevisionUnchanged(c *gc.C) {
 	s.assertLocalRevision(c, 7, s.path)
 }
 
+func (s *UpgradeCharmSuccessSuite) TestRollback(c *gc.C) {
+	err := runUpgradeCharm(c, "riak")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgraded(c, 8, false)
+
+	// Rolling back is allowed even when changes are blocked, so that
+	// units broken by an upgrade can be recovered.
+	s.BlockAllChanges(c, "TestRollback")
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&UpgradeCharmCommand{}), "riak", "--rollback")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "Rolled back service \"riak\" to charm \"local:trusty/riak-7\".\n")
+	s.assertUpgraded(c, 7, true)
+	c.Assert(s.riak.PreviousCharmURL().Revision, gc.Equals, 8)
+}
+
 func (s *UpgradeCharmSuccessSuite) TestBlockUpgradeCharm(c *gc.C) {
 	// Block operation
 	s.BlockAllChanges(c, "TestBlockUpgradeCharm")
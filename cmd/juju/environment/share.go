@@ -9,7 +9,9 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 )
@@ -17,6 +19,14 @@ import (
 const shareEnvHelpDoc = `
 Share the current environment with another user.
 
+The --access option sets the level of access the users are given:
+ read    the users may inspect the environment but not change it
+ write   the users may change the environment (the default for new users)
+ admin   the users may also share and destroy the environment
+
+Sharing the environment with a user who already has access to it
+changes their access level if --access is given.
+
 Examples:
  juju environment share joe
      Give local user "joe" access to the current environment
@@ -26,6 +36,9 @@ Examples:
 
  juju environment share sam --environment myenv
      Give local user "sam" access to the environment named "myenv"
+
+ juju environment share --access=read joe
+     Give local user "joe" read-only access to the current environment
  `
 
 // ShareCommand represents the command to share an environment with a user(s).
@@ -36,6 +49,9 @@ type ShareCommand struct {
 
 	// Users to share the environment with.
 	Users []names.UserTag
+
+	// Access is the level of access to give the users.
+	Access params.EnvironmentAccess
 }
 
 // Info implements Command.Info.
@@ -48,11 +64,22 @@ func (c *ShareCommand) Info() *cmd.Info {
 	}
 }
 
+// SetFlags implements Command.SetFlags.
+func (c *ShareCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar((*string)(&c.Access), "access", "", "level of access to give the users: read, write or admin")
+}
+
 func (c *ShareCommand) Init(args []string) (err error) {
 	if len(args) == 0 {
 		return errors.New("no users specified")
 	}
 
+	switch c.Access {
+	case "", params.EnvironmentReadAccess, params.EnvironmentWriteAccess, params.EnvironmentAdminAccess:
+	default:
+		return errors.Errorf("invalid access level %q, expected read, write or admin", c.Access)
+	}
+
 	for _, arg := range args {
 		if !names.IsValidUser(arg) {
 			return errors.Errorf("invalid username: %q", arg)
@@ -73,7 +100,7 @@ func (c *ShareCommand) getAPI() (ShareEnvironmentAPI, error) {
 // ShareEnvironmentAPI defines the API functions used by the environment share command.
 type ShareEnvironmentAPI interface {
 	Close() error
-	ShareEnvironment(...names.UserTag) error
+	ShareEnvironment(params.EnvironmentAccess, ...names.UserTag) error
 }
 
 func (c *ShareCommand) Run(ctx *cmd.Context) error {
@@ -83,5 +110,5 @@ func (c *ShareCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
-	return block.ProcessBlockedError(client.ShareEnvironment(c.Users...), block.BlockChange)
+	return block.ProcessBlockedError(client.ShareEnvironment(c.Access, c.Users...), block.BlockChange)
 }
@@ -5,6 +5,7 @@ package service_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 
@@ -19,6 +20,8 @@ type fakeServiceAPI struct {
 	servName  string
 	charmName string
 	config    string
+	history   []params.ServiceConfigRevision
+	reverted  int
 	err       error
 }
 
@@ -98,3 +101,30 @@ func (f *fakeServiceAPI) ServiceUnset(service string, options []string) error {
 
 	return nil
 }
+
+func (f *fakeServiceAPI) ServiceConfigHistory(service string) ([]params.ServiceConfigRevision, error) {
+	if service != f.servName {
+		return nil, errors.NotFoundf("service %q", service)
+	}
+	return f.history, nil
+}
+
+func (f *fakeServiceAPI) ServiceRevertConfig(service string, revision int) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	if service != f.servName {
+		return errors.NotFoundf("service %q", service)
+	}
+
+	if revision > len(f.history) {
+		return errors.NotFoundf("config revision %d of service %q", revision, service)
+	}
+	f.reverted = revision
+	f.history = append(f.history, params.ServiceConfigRevision{
+		Revision: len(f.history) + 1,
+		Time:     time.Now(),
+	})
+	return nil
+}
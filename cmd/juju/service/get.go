@@ -7,6 +7,7 @@ import (
 	"errors"
 
 	"github.com/juju/cmd"
+	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
@@ -17,6 +18,7 @@ import (
 type GetCommand struct {
 	envcmd.EnvCommandBase
 	ServiceName string
+	History     bool
 	out         cmd.Output
 	api         GetServiceAPI
 }
@@ -44,18 +46,39 @@ settings:
 NOTE: In the example above the descriptions and most other settings were omitted for
 brevity. The "engine" setting was left at its default value ("nginx"), while the
 "tuning" setting was set to "optimized" (the default value is "single").
+
+With --history, the command instead lists the recorded changes to the
+service's configuration, oldest first. Each revision shows when and by
+whom the change was made and the old and new values of the settings it
+changed; settings without an old or new value were unset. The first
+revision records the service's initial configuration. Any revision can
+be restored with "juju service set --revert <revision> <service>".
+Example:
+
+$ juju service get --history wordpress
+
+- revision: 1
+  time: 2015-07-01 12:00:00Z
+  user: admin@local
+- revision: 2
+  time: 2015-07-02 09:30:12Z
+  user: admin@local
+  changes:
+    tuning:
+      new: optimized
 `
 
 func (c *GetCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "get",
-		Args:    "<service>",
+		Args:    "[--history] <service>",
 		Purpose: "get service configuration options",
 		Doc:     getDoc,
 	}
 }
 
 func (c *GetCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.History, "history", false, "show the history of changes to the service's configuration")
 	// TODO(dfc) add json formatting ?
 	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
 		"yaml": cmd.FormatYaml,
@@ -76,6 +99,7 @@ func (c *GetCommand) Init(args []string) error {
 type GetServiceAPI interface {
 	Close() error
 	ServiceGet(service string) (*params.ServiceGetResults, error)
+	ServiceConfigHistory(service string) ([]params.ServiceConfigRevision, error)
 }
 
 func (c *GetCommand) getAPI() (GetServiceAPI, error) {
@@ -94,6 +118,9 @@ func (c *GetCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
+	if c.History {
+		return c.writeHistory(ctx, client)
+	}
 	results, err := client.ServiceGet(c.ServiceName)
 	if err != nil {
 		return err
@@ -106,3 +133,56 @@ func (c *GetCommand) Run(ctx *cmd.Context) error {
 	}
 	return c.out.Write(ctx, resultsMap)
 }
+
+// configRevision and configChange define the serialization of a
+// service's config history.
+type configRevision struct {
+	Revision int                     `yaml:"revision" json:"revision"`
+	Time     string                  `yaml:"time" json:"time"`
+	User     string                  `yaml:"user,omitempty" json:"user,omitempty"`
+	Changes  map[string]configChange `yaml:"changes,omitempty" json:"changes,omitempty"`
+}
+
+type configChange struct {
+	Old interface{} `yaml:"old,omitempty" json:"old,omitempty"`
+	New interface{} `yaml:"new,omitempty" json:"new,omitempty"`
+}
+
+// writeHistory fetches the recorded changes to the configuration of the
+// service and writes them out, oldest first.
+func (c *GetCommand) writeHistory(ctx *cmd.Context, client GetServiceAPI) error {
+	history, err := client.ServiceConfigHistory(c.ServiceName)
+	if params.IsCodeNotImplemented(err) {
+		return errors.New("cannot get config history: not supported by the API server")
+	}
+	if err != nil {
+		return err
+	}
+	revisions := make([]configRevision, len(history))
+	for i, rev := range history {
+		revisions[i] = configRevision{
+			Revision: rev.Revision,
+			Time:     rev.Time.UTC().Format("2006-01-02 15:04:05Z"),
+			User:     formatUser(rev.User),
+		}
+		for _, change := range rev.Changes {
+			if revisions[i].Changes == nil {
+				revisions[i].Changes = make(map[string]configChange)
+			}
+			revisions[i].Changes[change.Key] = configChange{
+				Old: change.OldValue,
+				New: change.NewValue,
+			}
+		}
+	}
+	return c.out.Write(ctx, revisions)
+}
+
+// formatUser returns the name of the user with the given tag, or the
+// tag itself if it is not a user tag.
+func formatUser(tag string) string {
+	if userTag, err := names.ParseUserTag(tag); err == nil {
+		return userTag.Id()
+	}
+	return tag
+}
@@ -5,12 +5,14 @@ package service_test
 
 import (
 	"bytes"
+	"time"
 
 	"github.com/juju/cmd"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	goyaml "gopkg.in/yaml.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/service"
 	coretesting "github.com/juju/juju/testing"
@@ -99,3 +101,37 @@ func (s *GetSuite) TestGetConfig(c *gc.C) {
 		c.Assert(actual, gc.DeepEquals, expected)
 	}
 }
+
+func (s *GetSuite) TestGetHistory(c *gc.C) {
+	s.fake.history = []params.ServiceConfigRevision{{
+		Revision: 1,
+		Time:     time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC),
+		User:     "user-admin@local",
+	}, {
+		Revision: 2,
+		Time:     time.Date(2015, 7, 2, 9, 30, 12, 0, time.UTC),
+		User:     "user-bob@local",
+		Changes: []params.ServiceConfigChange{
+			{Key: "outlook", OldValue: "true"},
+			{Key: "title", OldValue: "Nearly There", NewValue: "There"},
+		},
+	}}
+	ctx := coretesting.Context(c)
+	code := cmd.Main(envcmd.Wrap(service.NewGetCommand(s.fake)), ctx, []string{"--history", "dummy-service"})
+	c.Check(code, gc.Equals, 0)
+	c.Assert(ctx.Stderr.(*bytes.Buffer).String(), gc.Equals, "")
+	c.Assert(ctx.Stdout.(*bytes.Buffer).String(), gc.Equals, `
+- revision: 1
+  time: 2015-07-01 12:00:00Z
+  user: admin@local
+- revision: 2
+  time: 2015-07-02 09:30:12Z
+  user: bob@local
+  changes:
+    outlook:
+      old: "true"
+    title:
+      old: Nearly There
+      new: There
+`[1:])
+}
@@ -26,6 +26,7 @@ type SetCommand struct {
 	ServiceName     string
 	SettingsStrings map[string]string
 	SettingsYAML    cmd.FileVar
+	Revert          int
 	api             SetServiceAPI
 }
 
@@ -40,6 +41,11 @@ size of this value is 5M.
 
 Option values may be any UTF-8 encoded string. UTF-8 is accepted on the command
 line and in configuration files.
+
+With --revert, the configuration of the service is instead changed back to that
+recorded in the given revision of its config history, as listed by
+"juju service get --history <service>". The units of the service see the change
+like any other; it is recorded as a new revision.
 `
 
 const maxValueSize = 5242880
@@ -47,7 +53,7 @@ const maxValueSize = 5242880
 func (c *SetCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "set",
-		Args:    "<service> name=value ...",
+		Args:    "<service> name=value ... | --revert <revision> <service>",
 		Purpose: "set service config options",
 		Doc:     setDoc,
 	}
@@ -55,6 +61,7 @@ func (c *SetCommand) Info() *cmd.Info {
 
 func (c *SetCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(&c.SettingsYAML, "config", "path to yaml-formatted service config")
+	f.IntVar(&c.Revert, "revert", 0, "revert to this revision of the service config history")
 }
 
 func (c *SetCommand) Init(args []string) error {
@@ -65,6 +72,15 @@ func (c *SetCommand) Init(args []string) error {
 		return errors.New("cannot specify --config when using key=value arguments")
 	}
 	c.ServiceName = args[0]
+	if c.Revert != 0 {
+		if c.Revert < 0 {
+			return fmt.Errorf("invalid config revision %d", c.Revert)
+		}
+		if c.SettingsYAML.Path != "" || len(args) > 1 {
+			return errors.New("cannot specify --revert with --config or key=value arguments")
+		}
+		return nil
+	}
 	settings, err := keyvalues.Parse(args[1:], true)
 	if err != nil {
 		return err
@@ -80,6 +96,7 @@ type SetServiceAPI interface {
 	ServiceSetYAML(service string, yaml string) error
 	ServiceGet(service string) (*params.ServiceGetResults, error)
 	ServiceSet(service string, options map[string]string) error
+	ServiceRevertConfig(service string, revision int) error
 }
 
 func (c *SetCommand) getAPI() (SetServiceAPI, error) {
@@ -97,6 +114,13 @@ func (c *SetCommand) Run(ctx *cmd.Context) error {
 	}
 	defer api.Close()
 
+	if c.Revert != 0 {
+		err := api.ServiceRevertConfig(c.ServiceName, c.Revert)
+		if params.IsCodeNotImplemented(err) {
+			return errors.New("cannot revert config: not supported by the API server")
+		}
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
 	if c.SettingsYAML.Path != "" {
 		b, err := c.SettingsYAML.Read(ctx)
 		if err != nil {
@@ -16,6 +16,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/service"
 	coretesting "github.com/juju/juju/testing"
@@ -64,6 +65,18 @@ func (*SetSuite) TestSetCommandInit(c *gc.C) {
 	// --config and options specified
 	err = coretesting.InitCommand(&service.SetCommand{}, []string{"service", "--config", "testconfig.yaml", "bees="})
 	c.Assert(err, gc.ErrorMatches, "cannot specify --config when using key=value arguments")
+
+	// --revert and options specified
+	err = coretesting.InitCommand(&service.SetCommand{}, []string{"service", "--revert", "2", "bees="})
+	c.Assert(err, gc.ErrorMatches, "cannot specify --revert with --config or key=value arguments")
+
+	// --revert and --config specified
+	err = coretesting.InitCommand(&service.SetCommand{}, []string{"service", "--revert", "2", "--config", "testconfig.yaml"})
+	c.Assert(err, gc.ErrorMatches, "cannot specify --revert with --config or key=value arguments")
+
+	// invalid revision
+	err = coretesting.InitCommand(&service.SetCommand{}, []string{"service", "--revert", "-1"})
+	c.Assert(err, gc.ErrorMatches, "invalid config revision -1")
 }
 
 func (s *SetSuite) TestSetOptionSuccess(c *gc.C) {
@@ -155,6 +168,35 @@ func (s *SetSuite) TestBlockSetConfig(c *gc.C) {
 	c.Check(stripped, gc.Matches, ".*TestBlockSetConfig.*")
 }
 
+func (s *SetSuite) TestSetRevert(c *gc.C) {
+	s.fake.history = []params.ServiceConfigRevision{{Revision: 1}, {Revision: 2}}
+	ctx := coretesting.ContextForDir(c, s.dir)
+	code := cmd.Main(envcmd.Wrap(service.NewSetCommand(s.fake)), ctx, []string{
+		"dummy-service",
+		"--revert",
+		"1"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(s.fake.reverted, gc.Equals, 1)
+	c.Check(s.fake.history, gc.HasLen, 3)
+
+	s.assertSetFail(c, s.dir, []string{"--revert", "42"},
+		`error: config revision 42 of service "dummy-service" not found\n`)
+}
+
+func (s *SetSuite) TestBlockSetRevert(c *gc.C) {
+	s.fake.history = []params.ServiceConfigRevision{{Revision: 1}}
+	s.fake.err = common.ErrOperationBlocked("TestBlockSetRevert")
+	ctx := coretesting.ContextForDir(c, s.dir)
+	code := cmd.Main(envcmd.Wrap(service.NewSetCommand(s.fake)), ctx, []string{
+		"dummy-service",
+		"--revert",
+		"1"})
+	c.Check(code, gc.Equals, 1)
+	// msg is logged
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Check(stripped, gc.Matches, ".*TestBlockSetRevert.*")
+}
+
 // assertSetSuccess sets configuration options and checks the expected settings.
 func (s *SetSuite) assertSetSuccess(c *gc.C, dir string, args []string, expect map[string]interface{}) {
 	ctx := coretesting.ContextForDir(c, dir)
@@ -25,6 +25,8 @@ import (
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/introspection"
 )
 
 var (
@@ -303,3 +305,20 @@ func openAPIStateUsingInfo(info *api.Info, a Agent, oldPassword string) (*api.St
 
 	return st, usedOldPassword, nil
 }
+
+// startIntrospectionWorker starts, in the given runner, a worker that
+// serves reports on the runner's workers over the agent's
+// introspection socket, for use by "jujud agent-report".
+func startIntrospectionWorker(runner worker.Runner, dataDir string, tag names.Tag) {
+	reporter, ok := runner.(dependency.Reporter)
+	if !ok {
+		logger.Debugf("not starting introspection worker: %T does not report", runner)
+		return
+	}
+	runner.StartWorker("introspection", func() (worker.Worker, error) {
+		return introspection.NewWorker(introspection.Config{
+			SocketPath: introspection.SocketPath(dataDir, tag),
+			Reporter:   reporter,
+		})
+	})
+}
@@ -5,6 +5,7 @@ package agent
 
 import (
 	"fmt"
+	"runtime"
 	"time"
 
 	"github.com/juju/cmd"
@@ -32,6 +33,7 @@ import (
 	coretools "github.com/juju/juju/tools"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/introspection"
 	"github.com/juju/juju/worker/proxyupdater"
 )
 
@@ -272,3 +274,28 @@ type fakeAPIOpenConfig struct{ agent.Config }
 func (fakeAPIOpenConfig) APIInfo() *api.Info              { return &api.Info{} }
 func (fakeAPIOpenConfig) OldPassword() string             { return "old" }
 func (fakeAPIOpenConfig) Jobs() []multiwatcher.MachineJob { return []multiwatcher.MachineJob{} }
+
+// assertIntrospectionReport waits for the agent with the given tag to
+// answer on its introspection socket, and checks that it reports the
+// given workers.
+func assertIntrospectionReport(c *gc.C, dataDir string, tag names.Tag, workers ...string) {
+	if runtime.GOOS == "windows" {
+		c.Skip("introspection tests use unix sockets")
+	}
+	socketPath := introspection.SocketPath(dataDir, tag)
+	var report map[string]interface{}
+	var err error
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		report, err = introspection.Report(socketPath)
+		if err == nil {
+			break
+		}
+	}
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report[worker.KeyState], gc.Equals, worker.StateStarted)
+	reported, _ := report[worker.KeyWorkers].(map[string]interface{})
+	for _, name := range workers {
+		_, ok := reported[name]
+		c.Check(ok, jc.IsTrue, gc.Commentf("worker %q not reported", name))
+	}
+}
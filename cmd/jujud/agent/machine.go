@@ -46,6 +46,7 @@ import (
 	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/container/lxc"
 	"github.com/juju/juju/container/lxc/lxcutils"
+	"github.com/juju/juju/container/lxd"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/feature"
@@ -59,15 +60,20 @@ import (
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/common"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
 	"github.com/juju/juju/state/multiwatcher"
 	statestorage "github.com/juju/juju/state/storage"
 	"github.com/juju/juju/storage/looputil"
+	storageprovider "github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/storage/provider/registry"
 	coretools "github.com/juju/juju/tools"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/actionscheduler"
 	"github.com/juju/juju/worker/addresser"
 	"github.com/juju/juju/worker/apiaddressupdater"
 	"github.com/juju/juju/worker/authenticationworker"
+	"github.com/juju/juju/worker/backupscheduler"
 	"github.com/juju/juju/worker/certupdater"
 	"github.com/juju/juju/worker/charmrevisionworker"
 	"github.com/juju/juju/worker/cleaner"
@@ -77,8 +83,10 @@ import (
 	"github.com/juju/juju/worker/diskmanager"
 	"github.com/juju/juju/worker/envworkermanager"
 	"github.com/juju/juju/worker/firewaller"
+	"github.com/juju/juju/worker/imagepruner"
 	"github.com/juju/juju/worker/instancepoller"
 	"github.com/juju/juju/worker/localstorage"
+	"github.com/juju/juju/worker/logforwarder"
 	workerlogger "github.com/juju/juju/worker/logger"
 	"github.com/juju/juju/worker/logsender"
 	"github.com/juju/juju/worker/machiner"
@@ -149,6 +157,22 @@ func init() {
 	}
 }
 
+var registerMachineStorageProvidersOnce sync.Once
+
+// registerMachineStorageProviders registers the storage providers that
+// only the machine agent can supply, because they depend on workers
+// that run within it.
+func registerMachineStorageProviders() {
+	registerMachineStorageProvidersOnce.Do(func() {
+		// The LVM storage provider chooses devices for its volume
+		// groups from the block devices found by the diskmanager.
+		registry.RegisterProvider(
+			storageprovider.LVMProviderType,
+			storageprovider.NewLVMProvider(diskmanager.DefaultListBlockDevices),
+		)
+	})
+}
+
 // AgentInitializer handles initializing a type for use as a Jujud
 // agent.
 type AgentInitializer interface {
@@ -456,6 +480,7 @@ func (a *MachineAgent) Run(*cmd.Context) error {
 	a.previousAgentVersion = agentConfig.UpgradedToVersion()
 
 	network.InitializeFromConfig(agentConfig)
+	registerMachineStorageProviders()
 	charmrepo.CacheDir = filepath.Join(agentConfig.DataDir(), "charmcache")
 	if err := a.createJujuRun(agentConfig.DataDir()); err != nil {
 		return fmt.Errorf("cannot create juju run symlink: %v", err)
@@ -465,6 +490,7 @@ func (a *MachineAgent) Run(*cmd.Context) error {
 	a.runner.StartWorker("termination", func() (worker.Worker, error) {
 		return terminationworker.NewWorker(), nil
 	})
+	startIntrospectionWorker(a.runner, agentConfig.DataDir(), a.Tag())
 
 	// At this point, all workers will have been configured to start
 	close(a.workersStarted)
@@ -931,6 +957,14 @@ func (a *MachineAgent) setupContainerSupport(runner worker.Runner, st *api.State
 	if err == nil && supportsKvm {
 		supportedContainers = append(supportedContainers, instance.KVM)
 	}
+
+	supportsLXD, err := lxd.IsLXDSupported()
+	if err != nil {
+		logger.Warningf("no lxd containers possible: %v", err)
+	}
+	if err == nil && supportsLXD {
+		supportedContainers = append(supportedContainers, instance.LXD)
+	}
 	return a.updateSupportedContainers(runner, st, entity.Tag(), supportedContainers, agentConfig)
 }
 
@@ -1075,11 +1109,28 @@ func (a *MachineAgent) StateWorker() (worker.Worker, error) {
 				a.startWorkerAfterUpgrade(singularRunner, "dblogpruner", func() (worker.Worker, error) {
 					return dblogpruner.New(st, dblogpruner.NewLogPruneParams()), nil
 				})
+				a.startWorkerAfterUpgrade(singularRunner, "logforwarder", func() (worker.Worker, error) {
+					return logforwarder.New(st), nil
+				})
 			}
 			a.startWorkerAfterUpgrade(singularRunner, "statushistorypruner", func() (worker.Worker, error) {
 				return statushistorypruner.New(st, statushistorypruner.NewHistoryPrunerParams()), nil
 			})
 
+			a.startWorkerAfterUpgrade(singularRunner, "backupscheduler", func() (worker.Worker, error) {
+				return backupscheduler.New(st, backupscheduler.Params{
+					MachineID: a.machineId,
+					Paths: backups.Paths{
+						DataDir: agentConfig.DataDir(),
+						LogsDir: agentConfig.LogDir(),
+					},
+				}), nil
+			})
+
+			a.startWorkerAfterUpgrade(singularRunner, "actionscheduler", func() (worker.Worker, error) {
+				return actionscheduler.New(st, actionscheduler.DefaultInterval), nil
+			})
+
 			a.startWorkerAfterUpgrade(singularRunner, "txnpruner", func() (worker.Worker, error) {
 				return txnpruner.New(st, time.Hour*2), nil
 			})
@@ -1156,6 +1207,9 @@ func (a *MachineAgent) startEnvWorkers(
 	singularRunner.StartWorker("addresserworker", func() (worker.Worker, error) {
 		return addresser.NewWorker(st)
 	})
+	singularRunner.StartWorker("imagepruner", func() (worker.Worker, error) {
+		return imagepruner.New(st, imagepruner.DefaultPruneInterval), nil
+	})
 
 	// Start workers that use an API connection.
 	singularRunner.StartWorker("environ-provisioner", func() (worker.Worker, error) {
@@ -144,6 +144,7 @@ func (a *UnitAgent) Run(ctx *cmd.Context) error {
 
 	network.InitializeFromConfig(agentConfig)
 	a.runner.StartWorker("api", a.APIWorkers)
+	startIntrospectionWorker(a.runner, agentConfig.DataDir(), a.Tag())
 	err := cmdutil.AgentDone(logger, a.runner.Wait())
 	a.tomb.Kill(err)
 	return err
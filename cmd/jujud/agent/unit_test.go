@@ -190,6 +190,14 @@ func (s *UnitSuite) TestRunStop(c *gc.C) {
 	waitForUnitActive(s.State, unit, c)
 }
 
+func (s *UnitSuite) TestIntrospectionSocket(c *gc.C) {
+	_, unit, _, _ := s.primeAgent(c)
+	a := s.newAgent(c, unit)
+	go func() { c.Check(a.Run(nil), gc.IsNil) }()
+	defer func() { c.Check(a.Stop(), gc.IsNil) }()
+	assertIntrospectionReport(c, a.CurrentConfig().DataDir(), unit.Tag(), "api", "introspection")
+}
+
 func (s *UnitSuite) TestUpgrade(c *gc.C) {
 	machine, unit, _, currentTools := s.primeAgent(c)
 	agent := s.newAgent(c, unit)
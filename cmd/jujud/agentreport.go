@@ -0,0 +1,103 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	cmdutil "github.com/juju/juju/cmd/jujud/util"
+	"github.com/juju/juju/worker/introspection"
+)
+
+// getAgentReport is patched out in tests.
+var getAgentReport = introspection.Report
+
+// AgentReportCommand prints the state of the workers run by an agent
+// on this machine.
+type AgentReportCommand struct {
+	cmd.CommandBase
+	out cmd.Output
+	tag names.Tag
+}
+
+const agentReportCommandDoc = `
+Report the state of the workers run by an agent on this machine:
+which are running, which are waiting to be started or restarted, how
+often each has been started and the last error each stopped with.
+Workers run by other workers are reported beneath them.
+
+The agent may be given as a machine id, a unit name or a tag:
+ i.e.  0, mysql/0, machine-0 or unit-mysql-0
+
+The dot format renders the dependencies between workers as a graph,
+suitable for passing to graphviz.
+`
+
+// Info returns usage information for the command.
+func (c *AgentReportCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "agent-report",
+		Args:    "<agent>",
+		Purpose: "report the state of an agent's workers",
+		Doc:     agentReportCommandDoc,
+	}
+}
+
+func (c *AgentReportCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+		"dot":  formatDOT,
+	})
+}
+
+func (c *AgentReportCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("missing agent")
+	}
+	agent, args := args[0], args[1:]
+	// Accept machine ids and unit names as well as tags, as they are
+	// what users most often have to hand.
+	switch {
+	case names.IsValidMachine(agent):
+		c.tag = names.NewMachineTag(agent)
+	case names.IsValidUnit(agent):
+		c.tag = names.NewUnitTag(agent)
+	default:
+		tag, err := names.ParseTag(agent)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		switch tag.(type) {
+		case names.MachineTag, names.UnitTag:
+		default:
+			return fmt.Errorf("%q is not a machine or unit agent", agent)
+		}
+		c.tag = tag
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *AgentReportCommand) Run(ctx *cmd.Context) error {
+	report, err := getAgentReport(introspection.SocketPath(cmdutil.DataDir, c.tag))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return c.out.Write(ctx, report)
+}
+
+// formatDOT is a cmd.Formatter that renders an agent report as a
+// graph in the DOT language.
+func formatDOT(value interface{}) ([]byte, error) {
+	report, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("expected report, got %T", value)
+	}
+	return []byte(introspection.FormatDOT(report)), nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	cmdutil "github.com/juju/juju/cmd/jujud/util"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/introspection"
+)
+
+type AgentReportSuite struct {
+	testing.BaseSuite
+	socketPath string
+}
+
+var _ = gc.Suite(&AgentReportSuite{})
+
+func (s *AgentReportSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.PatchValue(&cmdutil.DataDir, c.MkDir())
+	s.socketPath = ""
+	s.PatchValue(&getAgentReport, func(socketPath string) (map[string]interface{}, error) {
+		s.socketPath = socketPath
+		return map[string]interface{}{
+			"state": "started",
+			"manifolds": map[string]interface{}{
+				"uniter": map[string]interface{}{
+					"state":  "started",
+					"inputs": []interface{}{"api-caller"},
+				},
+			},
+		}, nil
+	})
+}
+
+func (*AgentReportSuite) TestArgParsing(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+		tag      names.Tag
+	}{{
+		errMatch: "missing agent",
+	}, {
+		args: []string{"0"},
+		tag:  names.NewMachineTag("0"),
+	}, {
+		args: []string{"mysql/0"},
+		tag:  names.NewUnitTag("mysql/0"),
+	}, {
+		args: []string{"machine-1-lxc-0"},
+		tag:  names.NewMachineTag("1/lxc/0"),
+	}, {
+		args: []string{"unit-mysql-1"},
+		tag:  names.NewUnitTag("mysql/1"),
+	}, {
+		args:     []string{"service-mysql"},
+		errMatch: `"service-mysql" is not a machine or unit agent`,
+	}, {
+		args:     []string{"foo"},
+		errMatch: `"foo" is not a valid tag`,
+	}, {
+		args:     []string{"0", "1"},
+		errMatch: `unrecognized args: \["1"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &AgentReportCommand{}
+		err := testing.InitCommand(command, test.args)
+		if test.errMatch != "" {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(command.tag, gc.Equals, test.tag)
+	}
+}
+
+func (s *AgentReportSuite) TestYAML(c *gc.C) {
+	ctx, err := testing.RunCommand(c, &AgentReportCommand{}, "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+manifolds:
+  uniter:
+    inputs:
+    - api-caller
+    state: started
+state: started
+`[1:])
+	expectPath := introspection.SocketPath(cmdutil.DataDir, names.NewUnitTag("mysql/0"))
+	c.Assert(s.socketPath, gc.Equals, expectPath)
+	c.Assert(filepath.Base(s.socketPath), gc.Equals, "introspection.socket")
+}
+
+func (s *AgentReportSuite) TestJSON(c *gc.C) {
+	ctx, err := testing.RunCommand(c, &AgentReportCommand{}, "--format", "json", "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals,
+		`{"manifolds":{"uniter":{"inputs":["api-caller"],"state":"started"}},"state":"started"}`+"\n")
+}
+
+func (s *AgentReportSuite) TestDOT(c *gc.C) {
+	ctx, err := testing.RunCommand(c, &AgentReportCommand{}, "--format", "dot", "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `digraph dependencies {
+    "uniter" [label="uniter\nstarted", color="green"];
+    "uniter" -> "api-caller";
+}
+`)
+}
+
+func (s *AgentReportSuite) TestReportError(c *gc.C) {
+	s.PatchValue(&getAgentReport, func(string) (map[string]interface{}, error) {
+		return nil, errors.New("cannot connect to agent: no such file")
+	})
+	_, err := testing.RunCommand(c, &AgentReportCommand{}, "0")
+	c.Assert(err, gc.ErrorMatches, "cannot connect to agent: no such file")
+}
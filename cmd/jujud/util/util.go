@@ -220,6 +220,18 @@ func (c *CloseWorker) Wait() error {
 	return err
 }
 
+// Report returns the report of the wrapped worker, if it produces
+// one, so that the workers run by a wrapped runner are included in
+// agent reports.
+func (c *CloseWorker) Report() map[string]interface{} {
+	if reporter, ok := c.worker.(interface {
+		Report() map[string]interface{}
+	}); ok {
+		return reporter.Report()
+	}
+	return nil
+}
+
 // HookExecutionLock returns an *fslock.Lock suitable for use as a
 // unit hook execution lock. Other workers may also use this lock if
 // they require isolation from hook execution.
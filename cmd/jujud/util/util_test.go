@@ -136,3 +136,25 @@ type testPinger func() error
 func (f testPinger) Ping() error {
 	return f()
 }
+
+type reportingWorker struct {
+	worker.Worker
+}
+
+func (reportingWorker) Report() map[string]interface{} {
+	return map[string]interface{}{"state": "started"}
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+func (*toolSuite) TestCloseWorkerReport(c *gc.C) {
+	w := NewCloseWorker(logger, reportingWorker{}, nopCloser{}).(*CloseWorker)
+	c.Assert(w.Report(), jc.DeepEquals, map[string]interface{}{"state": "started"})
+
+	w = NewCloseWorker(logger, worker.NewNoOpWorker(), nopCloser{}).(*CloseWorker)
+	c.Assert(w.Report(), gc.IsNil)
+}
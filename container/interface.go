@@ -29,6 +29,16 @@ const (
 	// setting.
 	ConfigLXCDefaultMTU = "lxc-default-mtu"
 
+	// ConfigLXCTemplateSeries holds the comma-separated series used
+	// by LXC containers in the environment, for which clone templates
+	// are created before they are needed.
+	ConfigLXCTemplateSeries = "lxc-template-series"
+
+	// ConfigLXCTemplateMaxAge holds how long (e.g. "720h0m0s") an LXC
+	// clone template may go unused before it is removed. Templates
+	// are not removed when it is "0s".
+	ConfigLXCTemplateMaxAge = "lxc-template-max-age"
+
 	DefaultNamespace = "juju"
 )
 
@@ -108,6 +108,13 @@ func (manager *containerManager) CreateContainer(
 	storageConfig *container.StorageConfig,
 ) (instance.Instance, *instance.HardwareCharacteristics, error) {
 
+	// uvtool only syncs Ubuntu cloud images.
+	if seriesOS, err := version.GetOSFromSeries(series); err != nil {
+		return nil, nil, errors.Trace(err)
+	} else if seriesOS != version.Ubuntu {
+		return nil, nil, errors.NotSupportedf("kvm containers of series %q", series)
+	}
+
 	name := names.NewMachineTag(instanceConfig.MachineId).String()
 	if manager.name != "" {
 		name = fmt.Sprintf("%s-%s", manager.name, name)
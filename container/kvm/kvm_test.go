@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -102,6 +103,15 @@ func (s *KVMSuite) TestCreateContainer(c *gc.C) {
 	containertesting.AssertCloudInit(c, cloudInitFilename)
 }
 
+func (s *KVMSuite) TestCreateContainerUnsupportedSeries(c *gc.C) {
+	instanceConfig, err := containertesting.MockMachineConfig("1/kvm/0")
+	c.Assert(err, jc.ErrorIsNil)
+	networkConfig := container.BridgeNetworkConfig("virbr0", 0, nil)
+	_, _, err = s.manager.CreateContainer(instanceConfig, "centos7", networkConfig, &container.StorageConfig{})
+	c.Assert(err, gc.ErrorMatches, `kvm containers of series "centos7" not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *KVMSuite) TestWriteTemplate(c *gc.C) {
 	params := kvm.CreateMachineParams{
 		Hostname:      "foo-bar",
@@ -56,7 +56,7 @@ func EnsureCloneTemplate(
 	imageURLGetter container.ImageURLGetter,
 	useAUFS bool,
 ) (golxc.Container, error) {
-	name := fmt.Sprintf("juju-%s-lxc-template", series)
+	name := templateName(series)
 	containerDirectory, err := container.NewDirectory(name)
 	if err != nil {
 		return nil, err
@@ -72,6 +72,7 @@ func EnsureCloneTemplate(
 	// Early exit if the container has been constructed before.
 	if lxcContainer.IsConstructed() {
 		logger.Infof("template exists, continuing")
+		recordUse(name)
 		return lxcContainer, nil
 	}
 	logger.Infof("template does not exist, creating")
@@ -94,14 +95,13 @@ func EnsureCloneTemplate(
 		return nil, err
 	}
 
-	templateParams := []string{
-		"--debug",                      // Debug errors in the cloud image
-		"--userdata", userDataFilename, // Our groovey cloud-init
-		"--hostid", name, // Use the container name as the hostid
-		"-r", series,
+	lxcTemplate, templateParams, err := containerTemplate(series, name, userDataFilename)
+	if err != nil {
+		return nil, errors.Trace(err)
 	}
 	var caCert []byte
-	if imageURLGetter != nil {
+	// Only images for the ubuntu-cloud template are cached.
+	if imageURLGetter != nil && lxcTemplate == defaultTemplate {
 		arch := arch.HostArch()
 		imageURL, err := imageURLGetter.ImageURL(instance.LXC, series, arch)
 		if err != nil {
@@ -119,6 +119,7 @@ func EnsureCloneTemplate(
 	logger.Tracef("create the template container")
 	err = createContainer(
 		lxcContainer,
+		lxcTemplate,
 		containerDirectory,
 		networkConfig,
 		extraCreateArgs,
@@ -129,6 +130,11 @@ func EnsureCloneTemplate(
 		logger.Errorf("lxc template container creation failed: %v", err)
 		return nil, err
 	}
+	if lxcTemplate != defaultTemplate {
+		if err := seedCloudInit(name, userDataFilename); err != nil {
+			return nil, errors.Annotate(err, "failed to seed cloud-init")
+		}
+	}
 	// Make sure that the mount dir has been created.
 	logger.Tracef("make the mount dir for the shared logs")
 	if err := os.MkdirAll(internalLogDir(name), 0755); err != nil {
@@ -174,9 +180,18 @@ func EnsureCloneTemplate(
 		time.Sleep(time.Second)
 	}
 
+	recordUse(name)
 	return lxcContainer, nil
 }
 
+// recordUse records that the named template was used, so that it is
+// not removed as unused. Failure is non-fatal.
+func recordUse(name string) {
+	if err := recordTemplateUse(name); err != nil {
+		logger.Warningf("cannot record use of template %q: %v", name, err)
+	}
+}
+
 type logTail struct {
 	tick  time.Time
 	mutex sync.Mutex
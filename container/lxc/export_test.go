@@ -21,6 +21,8 @@ var (
 	RuntimeGOOS             = &runtimeGOOS
 	RunningInsideLXC        = &runningInsideLXC
 	WriteWgetTmpFile        = &writeWgetTmpFile
+	ChrootCommandOutput     = &chrootCommandOutput
+	HostResolvConf          = &hostResolvConf
 )
 
 func GetCreateWithCloneValue(mgr container.Manager) bool {
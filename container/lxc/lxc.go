@@ -40,6 +40,7 @@ var logger = loggo.GetLogger("juju.container.lxc")
 
 var (
 	defaultTemplate  = "ubuntu-cloud"
+	downloadTemplate = "download"
 	LxcContainerDir  = golxc.GetDefaultLXCContainerDir()
 	LxcRestartDir    = "/etc/lxc/auto"
 	LxcObjectFactory = golxc.Factory()
@@ -54,9 +55,9 @@ const (
 	// Btrfs is special as we treat it differently for create and clone.
 	Btrfs = "btrfs"
 
-	// etcNetworkInterfaces here is the path (inside the container's
-	// rootfs) where the network config is stored.
-	etcNetworkInterfaces = "/etc/network/interfaces"
+	// cloudInitSeedDir is the path (inside the container's rootfs)
+	// from which cloud-init's NoCloud data source reads the user data.
+	cloudInitSeedDir = "/var/lib/cloud/seed/nocloud-net"
 )
 
 // DefaultNetworkConfig returns a valid NetworkConfig to use the
@@ -69,6 +70,15 @@ func DefaultNetworkConfig() *container.NetworkConfig {
 // we can test what *would* be run without actually executing another program
 var FsCommandOutput = (*exec.Cmd).CombinedOutput
 
+// chrootCommandOutput calls cmd.CombinedOutput, this is used as an
+// overloading point so we can test what *would* be run in a
+// container's rootfs without actually executing another program.
+var chrootCommandOutput = (*exec.Cmd).CombinedOutput
+
+// hostResolvConf is the host's resolver configuration, which is copied
+// into a container's rootfs while packages are installed into it.
+var hostResolvConf = "/etc/resolv.conf"
+
 func containerDirFilesystem() (string, error) {
 	cmd := exec.Command("df", "--output=fstype", LxcContainerDir)
 	out, err := FsCommandOutput(cmd)
@@ -219,6 +229,13 @@ func (manager *containerManager) CreateContainer(
 	if err != nil {
 		return nil, nil, errors.Annotate(err, "failed to write user data")
 	}
+	lxcTemplate, templateParams, err := containerTemplate(series, name, userDataFilename)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	// Only the ubuntu-cloud template can seed cloud-init in an AUFS
+	// snapshot, as the rootfs is not available until it is started.
+	useAUFS := manager.useAUFS && lxcTemplate == defaultTemplate
 
 	var lxcContainer golxc.Container
 	if manager.createWithClone {
@@ -232,21 +249,26 @@ func (manager *containerManager) CreateContainer(
 			instanceConfig.EnableOSRefreshUpdate,
 			instanceConfig.EnableOSUpgrade,
 			manager.imageURLGetter,
-			manager.useAUFS,
+			useAUFS,
 		)
 		if err != nil {
 			return nil, nil, errors.Annotate(err, "failed to retrieve the template to clone")
 		}
-		templateParams := []string{
-			"--debug",                      // Debug errors in the cloud image
-			"--userdata", userDataFilename, // Our groovey cloud-init
-			"--hostid", name, // Use the container name as the hostid
+		// The clone hook of the ubuntu-cloud template takes the same
+		// parameters as the template itself, other than the release.
+		var cloneParams []string
+		if lxcTemplate == defaultTemplate {
+			cloneParams = []string{
+				"--debug",                      // Debug errors in the cloud image
+				"--userdata", userDataFilename, // Our groovey cloud-init
+				"--hostid", name, // Use the container name as the hostid
+			}
 		}
 		var extraCloneArgs []string
-		if manager.backingFilesystem == Btrfs || manager.useAUFS {
+		if manager.backingFilesystem == Btrfs || useAUFS {
 			extraCloneArgs = append(extraCloneArgs, "--snapshot")
 		}
-		if manager.backingFilesystem != Btrfs && manager.useAUFS {
+		if manager.backingFilesystem != Btrfs && useAUFS {
 			extraCloneArgs = append(extraCloneArgs, "--backingstore", "aufs")
 		}
 
@@ -266,7 +288,7 @@ func (manager *containerManager) CreateContainer(
 			return nil, nil, errors.Annotate(err, "failed to reorder network settings")
 		}
 
-		lxcContainer, err = templateContainer.Clone(name, extraCloneArgs, templateParams)
+		lxcContainer, err = templateContainer.Clone(name, extraCloneArgs, cloneParams)
 		if err != nil {
 			return nil, nil, errors.Annotate(err, "lxc container cloning failed")
 		}
@@ -275,14 +297,9 @@ func (manager *containerManager) CreateContainer(
 		// object, and doesn't actually construct the underlying lxc container on
 		// disk.
 		lxcContainer = LxcObjectFactory.New(name)
-		templateParams := []string{
-			"--debug",                      // Debug errors in the cloud image
-			"--userdata", userDataFilename, // Our groovey cloud-init
-			"--hostid", name, // Use the container name as the hostid
-			"-r", series,
-		}
 		var caCert []byte
-		if manager.imageURLGetter != nil {
+		// Only images for the ubuntu-cloud template are cached.
+		if manager.imageURLGetter != nil && lxcTemplate == defaultTemplate {
 			arch := arch.HostArch()
 			imageURL, err := manager.imageURLGetter.ImageURL(instance.LXC, series, arch)
 			if err != nil {
@@ -293,6 +310,7 @@ func (manager *containerManager) CreateContainer(
 		}
 		err = createContainer(
 			lxcContainer,
+			lxcTemplate,
 			directory,
 			networkConfig,
 			nil,
@@ -303,6 +321,11 @@ func (manager *containerManager) CreateContainer(
 			return nil, nil, errors.Trace(err)
 		}
 	}
+	if lxcTemplate != defaultTemplate {
+		if err := seedCloudInit(name, userDataFilename); err != nil {
+			return nil, nil, errors.Annotate(err, "failed to seed cloud-init")
+		}
+	}
 
 	if err := autostartContainer(name); err != nil {
 		return nil, nil, errors.Annotate(err, "failed to configure the container for autostart")
@@ -336,22 +359,28 @@ func (manager *containerManager) CreateContainer(
 	}
 
 	// To speed-up the initial container startup we pre-render the
-	// /etc/network/interfaces directly inside the rootfs. This won't
-	// work if we use AUFS snapshots, so it's disabled if useAUFS is
-	// true (for now).
+	// network config (e.g. /etc/network/interfaces) directly inside
+	// the rootfs. This won't work if we use AUFS snapshots, so it's
+	// disabled if useAUFS is true (for now).
 	if networkConfig != nil && len(networkConfig.Interfaces) > 0 {
-		interfacesFile := filepath.Join(LxcContainerDir, name, "rootfs", etcNetworkInterfaces)
-		if manager.useAUFS {
-			logger.Tracef("not pre-rendering %q when using AUFS-backed rootfs", interfacesFile)
+		rootfs := filepath.Join(LxcContainerDir, name, "rootfs")
+		if useAUFS {
+			logger.Tracef("not pre-rendering network config in %q when using AUFS-backed rootfs", rootfs)
 		} else {
-			data, err := containerinit.GenerateNetworkConfig(networkConfig)
+			files, err := containerinit.NetworkConfigFiles(series, networkConfig)
 			if err != nil {
-				return nil, nil, errors.Annotatef(err, "failed to generate %q", interfacesFile)
+				return nil, nil, errors.Annotate(err, "failed to generate network config")
 			}
-			if err := utils.AtomicWriteFile(interfacesFile, []byte(data), 0644); err != nil {
-				return nil, nil, errors.Annotatef(err, "cannot write generated %q", interfacesFile)
+			for filename, data := range files {
+				configFile := filepath.Join(rootfs, filename)
+				if err := os.MkdirAll(filepath.Dir(configFile), 0755); err != nil {
+					return nil, nil, errors.Trace(err)
+				}
+				if err := utils.AtomicWriteFile(configFile, []byte(data), 0644); err != nil {
+					return nil, nil, errors.Annotatef(err, "cannot write generated %q", configFile)
+				}
+				logger.Tracef("pre-rendered network config in %q", configFile)
 			}
-			logger.Tracef("pre-rendered network config in %q", interfacesFile)
 		}
 	}
 
@@ -393,6 +422,7 @@ func (manager *containerManager) CreateContainer(
 
 func createContainer(
 	lxcContainer golxc.Container,
+	templateName string,
 	directory string,
 	networkConfig *container.NetworkConfig,
 	extraCreateArgs, templateParams []string,
@@ -420,12 +450,86 @@ func createContainer(
 	// Create the container.
 	logger.Debugf("creating lxc container %q", lxcContainer.Name())
 	logger.Debugf("lxc-create template params: %v", templateParams)
-	if err := lxcContainer.Create(configPath, defaultTemplate, extraCreateArgs, templateParams, execEnv); err != nil {
+	if err := lxcContainer.Create(configPath, templateName, extraCreateArgs, templateParams, execEnv); err != nil {
 		return errors.Annotatef(err, "lxc container creation failed")
 	}
+	if templateName == downloadTemplate {
+		if err := installCloudInit(lxcContainer.Name()); err != nil {
+			return errors.Annotate(err, "failed to install cloud-init")
+		}
+	}
+	return nil
+}
+
+// installCloudInit installs cloud-init into the rootfs of the named
+// container, which must not yet have been started. The images of the
+// download template do not include it, so without it nothing reads
+// the user data written by seedCloudInit. Packages are installed with
+// yum, which uses the proxy settings in the environment.
+func installCloudInit(name string) error {
+	rootfs := filepath.Join(LxcContainerDir, name, "rootfs")
+	// yum must be able to resolve the names of the package mirrors.
+	resolvConf := filepath.Join(rootfs, "etc", "resolv.conf")
+	if _, err := os.Stat(resolvConf); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(resolvConf), 0755); err != nil {
+			return errors.Trace(err)
+		}
+		if err := utils.CopyFile(resolvConf, hostResolvConf); err != nil {
+			return errors.Trace(err)
+		}
+		defer os.Remove(resolvConf)
+	}
+	cmd := exec.Command("chroot", rootfs, "yum", "install", "--assumeyes", "cloud-init")
+	if out, err := chrootCommandOutput(cmd); err != nil {
+		return errors.Annotatef(err, "yum failed: %s", bytes.TrimSpace(out))
+	}
 	return nil
 }
 
+// containerTemplate returns the lxc-create template with which a
+// container of the given series is created, and the parameters passed
+// to it. Only the ubuntu-cloud template seeds cloud-init with the user
+// data; containers created from other templates are seeded by
+// seedCloudInit.
+func containerTemplate(series, name, userDataFilename string) (string, []string, error) {
+	seriesOS, err := version.GetOSFromSeries(series)
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	switch seriesOS {
+	case version.Ubuntu:
+		return defaultTemplate, []string{
+			"--debug",                      // Debug errors in the cloud image
+			"--userdata", userDataFilename, // Our groovey cloud-init
+			"--hostid", name, // Use the container name as the hostid
+			"-r", series,
+		}, nil
+	case version.CentOS:
+		return downloadTemplate, []string{
+			"--dist", "centos",
+			"--release", strings.TrimPrefix(series, "centos"),
+			"--arch", arch.HostArch(),
+		}, nil
+	}
+	return "", nil, errors.NotSupportedf("lxc containers of series %q", series)
+}
+
+// seedCloudInit writes the user data and instance metadata for the
+// cloud-init NoCloud data source into the rootfs of the named
+// container, as the ubuntu-cloud template does for Ubuntu containers.
+// The container's image must include cloud-init; see installCloudInit.
+func seedCloudInit(name, userDataFilename string) error {
+	seedDir := filepath.Join(LxcContainerDir, name, "rootfs", cloudInitSeedDir)
+	if err := os.MkdirAll(seedDir, 0755); err != nil {
+		return errors.Trace(err)
+	}
+	if err := utils.CopyFile(filepath.Join(seedDir, "user-data"), userDataFilename); err != nil {
+		return errors.Trace(err)
+	}
+	metaData := fmt.Sprintf("instance-id: %s\nlocal-hostname: %s\n", name, name)
+	return utils.AtomicWriteFile(filepath.Join(seedDir, "meta-data"), []byte(metaData), 0644)
+}
+
 // wgetEnvironment creates a script to call wget with the
 // --no-check-certificate argument, patching the PATH to ensure
 // the script is invoked by the lxc template bash script.
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -33,9 +34,12 @@ import (
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/instance"
 	instancetest "github.com/juju/juju/instance/testing"
+	"github.com/juju/juju/juju/arch"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/dummy"
 	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/tools"
+	"github.com/juju/juju/version"
 )
 
 func Test(t *stdtesting.T) {
@@ -733,6 +737,115 @@ func (s *LxcSuite) TestCreateContainerEvents(c *gc.C) {
 	s.AssertEvent(c, <-s.events, mock.Started, id)
 }
 
+// patchChroot records the commands run in container rootfses in the
+// returned slice, checking that the host's resolver configuration is
+// available to them.
+func (s *LxcSuite) patchChroot(c *gc.C) *[][]string {
+	hostResolvConf := filepath.Join(c.MkDir(), "resolv.conf")
+	err := ioutil.WriteFile(hostResolvConf, []byte("nameserver 10.0.0.1\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(lxc.HostResolvConf, hostResolvConf)
+	var commands [][]string
+	s.PatchValue(lxc.ChrootCommandOutput, func(cmd *exec.Cmd) ([]byte, error) {
+		rootfs := cmd.Args[1]
+		resolvConf, err := ioutil.ReadFile(filepath.Join(rootfs, "etc", "resolv.conf"))
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(string(resolvConf), gc.Equals, "nameserver 10.0.0.1\n")
+		commands = append(commands, cmd.Args)
+		return nil, nil
+	})
+	return &commands
+}
+
+func (s *LxcSuite) TestCreateContainerCentOS(c *gc.C) {
+	commands := s.patchChroot(c)
+	manager := s.makeManager(c, "test")
+	instanceConfig, err := containertesting.MockMachineConfig("1/lxc/0")
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig.Series = "centos7"
+	instanceConfig.Tools = &tools.Tools{
+		Version: version.MustParseBinary("2.3.4-centos7-amd64"),
+		URL:     "http://tools.testing.invalid/2.3.4-centos7-amd64.tgz",
+	}
+	envConfig, err := config.New(config.NoDefaults, dummy.SampleConfig())
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig.Config = envConfig
+	networkConfig := container.BridgeNetworkConfig("nic42", 0, nil)
+	instance, _, err := manager.CreateContainer(instanceConfig, "centos7", networkConfig, &container.StorageConfig{})
+	c.Assert(err, jc.ErrorIsNil)
+	name := string(instance.Id())
+
+	// CentOS containers are created from the download template, whose
+	// images lack cloud-init, so it is installed into the rootfs before
+	// the container starts. It is then seeded with the user data.
+	createEvent := <-s.events
+	c.Assert(createEvent.Action, gc.Equals, mock.Created)
+	c.Assert(createEvent.InstanceId, gc.Equals, name)
+	c.Assert(createEvent.Template, gc.Equals, "download")
+	c.Assert(createEvent.TemplateArgs, jc.DeepEquals, []string{
+		"--dist", "centos", "--release", "7", "--arch", arch.HostArch(),
+	})
+	rootfs := filepath.Join(s.LxcDir, name, "rootfs")
+	c.Assert(*commands, jc.DeepEquals, [][]string{
+		{"chroot", rootfs, "yum", "install", "--assumeyes", "cloud-init"},
+	})
+	s.AssertEvent(c, <-s.events, mock.Started, name)
+
+	// The host's resolver configuration is removed again.
+	_, err = os.Stat(filepath.Join(rootfs, "etc", "resolv.conf"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+
+	seedDir := filepath.Join(rootfs, "var", "lib", "cloud", "seed", "nocloud-net")
+	userData, err := ioutil.ReadFile(filepath.Join(seedDir, "user-data"))
+	c.Assert(err, jc.ErrorIsNil)
+	cloudInit, err := ioutil.ReadFile(filepath.Join(s.ContainerDir, name, "cloud-init"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(userData), gc.Equals, string(cloudInit))
+	metaData, err := ioutil.ReadFile(filepath.Join(seedDir, "meta-data"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(metaData), gc.Equals, "instance-id: "+name+"\nlocal-hostname: "+name+"\n")
+}
+
+func (s *LxcSuite) TestEnsureCloneTemplateCentOS(c *gc.C) {
+	commands := s.patchChroot(c)
+	name := "juju-centos7-lxc-template"
+	ch := s.ensureTemplateStopped(name)
+	defer func() { <-ch }()
+	network := container.BridgeNetworkConfig("nic42", 4321, nil)
+	template, err := lxc.EnsureCloneTemplate(
+		"ext4",
+		"centos7",
+		network,
+		"authorized keys list",
+		proxy.Settings{},
+		"",
+		true,
+		true,
+		&containertesting.MockURLGetter{},
+		false,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(template.Name(), gc.Equals, name)
+
+	createEvent := <-s.events
+	c.Assert(createEvent.Action, gc.Equals, mock.Created)
+	c.Assert(createEvent.InstanceId, gc.Equals, name)
+	c.Assert(createEvent.Template, gc.Equals, "download")
+	c.Assert(createEvent.TemplateArgs, jc.DeepEquals, []string{
+		"--dist", "centos", "--release", "7", "--arch", arch.HostArch(),
+	})
+	rootfs := filepath.Join(s.LxcDir, name, "rootfs")
+	c.Assert(*commands, jc.DeepEquals, [][]string{
+		{"chroot", rootfs, "yum", "install", "--assumeyes", "cloud-init"},
+	})
+	s.AssertEvent(c, <-s.events, mock.Started, name)
+	s.AssertEvent(c, <-s.events, mock.Stopped, name)
+
+	seedDir := filepath.Join(rootfs, "var", "lib", "cloud", "seed", "nocloud-net")
+	_, err = os.Stat(filepath.Join(seedDir, "user-data"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *LxcSuite) TestCreateContainerEventsWithClone(c *gc.C) {
 	s.PatchValue(&s.useClone, true)
 	// The template containers are created with an upstart job that
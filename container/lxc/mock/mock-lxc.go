@@ -70,6 +70,9 @@ type Event struct {
 	Args         []string
 	TemplateArgs []string
 	EnvArgs      []string
+	// Template holds the name of the template a container
+	// was created from.
+	Template string
 }
 
 type ContainerFactory interface {
@@ -138,7 +141,9 @@ func (mock *mockContainer) Create(configFile, template string, extraArgs []strin
 		return errors.Trace(err)
 	}
 	mock.setState(golxc.StateStopped)
-	mock.factory.notify(eventArgs(Created, mock.name, extraArgs, templateArgs, envArgs))
+	created := eventArgs(Created, mock.name, extraArgs, templateArgs, envArgs)
+	created.Template = template
+	mock.factory.notify(created)
 	return nil
 }
 
@@ -317,11 +322,11 @@ func (mock *mockFactory) List() (result []golxc.Container, err error) {
 }
 
 func event(action Action, instanceId string) Event {
-	return Event{action, instanceId, nil, nil, nil}
+	return Event{action, instanceId, nil, nil, nil, ""}
 }
 
 func eventArgs(action Action, instanceId string, args, template, envArgs []string) Event {
-	return Event{action, instanceId, args, template, envArgs}
+	return Event{action, instanceId, args, template, envArgs, ""}
 }
 
 func (mock *mockFactory) notify(event Event) {
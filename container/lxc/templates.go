@@ -0,0 +1,196 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/proxy"
+	"launchpad.net/golxc"
+
+	"github.com/juju/juju/container"
+)
+
+const (
+	templatePrefix = "juju-"
+	templateSuffix = "-lxc-template"
+
+	// templateLastUsedFile is the name of the file, in a clone
+	// template's container directory, whose modification time
+	// records when the template was last used.
+	templateLastUsedFile = "last-used"
+)
+
+// templateName returns the name of the clone template for the given
+// series.
+func templateName(series string) string {
+	return templatePrefix + series + templateSuffix
+}
+
+func isTemplateName(name string) bool {
+	return strings.HasPrefix(name, templatePrefix) && strings.HasSuffix(name, templateSuffix)
+}
+
+// TemplateConfig holds the settings with which clone templates are
+// created.
+type TemplateConfig struct {
+	AuthorizedKeys       string
+	AptProxy             proxy.Settings
+	AptMirror            string
+	EnablePackageUpdates bool
+	EnableOSUpgrades     bool
+}
+
+// TemplateManager is implemented by the LXC container manager to
+// create clone templates before they are needed, and to remove those
+// which are no longer used.
+type TemplateManager interface {
+	// EnsureTemplates creates the clone templates for the given
+	// series which do not already exist, if containers are created
+	// by cloning.
+	EnsureTemplates(series []string, networkConfig *container.NetworkConfig, config TemplateConfig) error
+
+	// RemoveUnusedTemplates removes the clone templates which have
+	// not been used for longer than maxAge, other than those that
+	// cloned containers depend on, and returns their names.
+	RemoveUnusedTemplates(maxAge time.Duration) ([]string, error)
+}
+
+var _ TemplateManager = (*containerManager)(nil)
+
+// EnsureTemplates is specified on the TemplateManager interface.
+func (manager *containerManager) EnsureTemplates(
+	series []string,
+	networkConfig *container.NetworkConfig,
+	config TemplateConfig,
+) error {
+	if !manager.createWithClone {
+		return nil
+	}
+	var failed []string
+	for _, s := range series {
+		_, err := EnsureCloneTemplate(
+			manager.backingFilesystem,
+			s,
+			networkConfig,
+			config.AuthorizedKeys,
+			config.AptProxy,
+			config.AptMirror,
+			config.EnablePackageUpdates,
+			config.EnableOSUpgrades,
+			manager.imageURLGetter,
+			manager.useAUFS,
+		)
+		if err != nil {
+			logger.Errorf("cannot create clone template for %q: %v", s, err)
+			failed = append(failed, s)
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("cannot create clone templates for %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// RemoveUnusedTemplates is specified on the TemplateManager interface.
+func (manager *containerManager) RemoveUnusedTemplates(maxAge time.Duration) ([]string, error) {
+	if maxAge <= 0 {
+		return nil, nil
+	}
+	containers, err := LxcObjectFactory.List()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var removed []string
+	for _, lxcContainer := range containers {
+		name := lxcContainer.Name()
+		if !isTemplateName(name) || !lxcContainer.IsConstructed() {
+			continue
+		}
+		lastUsed, err := templateLastUsed(name)
+		if err != nil {
+			return removed, errors.Trace(err)
+		}
+		if time.Since(lastUsed) <= maxAge {
+			continue
+		}
+		if hasSnapshots(name) {
+			logger.Debugf("not removing template %q: cloned containers depend on it", name)
+			continue
+		}
+		ok, err := removeTemplate(lxcContainer)
+		if err != nil {
+			return removed, errors.Annotatef(err, "cannot remove template %q", name)
+		}
+		if ok {
+			logger.Infof("removed template %q, last used %v", name, lastUsed)
+			removed = append(removed, name)
+		}
+	}
+	return removed, nil
+}
+
+// removeTemplate destroys the given clone template, unless it is
+// running because it is still being created.
+func removeTemplate(template golxc.Container) (bool, error) {
+	name := template.Name()
+	lock, err := AcquireTemplateLock(name, "remove unused template")
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	defer lock.Unlock()
+	if template.IsRunning() {
+		return false, nil
+	}
+	if err := template.Destroy(); err != nil {
+		return false, errors.Trace(err)
+	}
+	return true, container.RemoveDirectory(name)
+}
+
+// hasSnapshots returns whether any containers were cloned from the
+// named container as snapshots, and so depend on it.
+func hasSnapshots(name string) bool {
+	data, err := ioutil.ReadFile(filepath.Join(LxcContainerDir, name, "lxc_snapshots"))
+	return err == nil && strings.TrimSpace(string(data)) != ""
+}
+
+func templateLastUsedPath(name string) string {
+	return filepath.Join(container.ContainerDir, name, templateLastUsedFile)
+}
+
+// recordTemplateUse records that the named template was used now.
+func recordTemplateUse(name string) error {
+	path := templateLastUsedPath(name)
+	now := time.Now()
+	err := os.Chtimes(path, now, now)
+	if !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, nil, 0644)
+}
+
+// templateLastUsed returns when the named template was last used.
+// Templates created before their use was recorded are treated as
+// having been used now.
+func templateLastUsed(name string) (time.Time, error) {
+	info, err := os.Stat(templateLastUsedPath(name))
+	if os.IsNotExist(err) {
+		if err := recordTemplateUse(name); err != nil {
+			return time.Time{}, errors.Trace(err)
+		}
+		return time.Now(), nil
+	} else if err != nil {
+		return time.Time{}, errors.Trace(err)
+	}
+	return info.ModTime(), nil
+}
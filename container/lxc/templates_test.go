@@ -0,0 +1,109 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxc_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/lxc"
+	"github.com/juju/juju/container/lxc/mock"
+	containertesting "github.com/juju/juju/container/testing"
+)
+
+const quantalTemplate = "juju-quantal-lxc-template"
+
+func (s *LxcSuite) templateLastUsed(c *gc.C, name string) time.Time {
+	info, err := os.Stat(filepath.Join(s.ContainerDir, name, "last-used"))
+	c.Assert(err, jc.ErrorIsNil)
+	return info.ModTime()
+}
+
+func (s *LxcSuite) setTemplateLastUsed(c *gc.C, name string, lastUsed time.Time) {
+	err := os.Chtimes(filepath.Join(s.ContainerDir, name, "last-used"), lastUsed, lastUsed)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *LxcSuite) TestEnsureCloneTemplateRecordsUse(c *gc.C) {
+	s.createTemplate(c)
+	lastUsed := s.templateLastUsed(c, quantalTemplate)
+	c.Assert(time.Since(lastUsed) < time.Minute, jc.IsTrue)
+
+	s.setTemplateLastUsed(c, quantalTemplate, time.Now().Add(-48*time.Hour))
+	s.PatchValue(&s.useClone, true)
+	manager := s.makeManager(c, "test")
+	containertesting.CreateContainer(c, manager, "1")
+	lastUsed = s.templateLastUsed(c, quantalTemplate)
+	c.Assert(time.Since(lastUsed) < time.Minute, jc.IsTrue)
+}
+
+func (s *LxcSuite) TestEnsureTemplates(c *gc.C) {
+	s.PatchValue(&s.useClone, true)
+	ch := s.ensureTemplateStopped(quantalTemplate)
+	defer func() { <-ch }()
+	manager := s.makeManager(c, "test").(lxc.TemplateManager)
+	network := container.BridgeNetworkConfig("nic42", 0, nil)
+	err := manager.EnsureTemplates([]string{"quantal"}, network, lxc.TemplateConfig{
+		AuthorizedKeys: "authorized keys list",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AssertEvent(c, <-s.events, mock.Created, quantalTemplate)
+	s.AssertEvent(c, <-s.events, mock.Started, quantalTemplate)
+	s.AssertEvent(c, <-s.events, mock.Stopped, quantalTemplate)
+	c.Assert(s.ContainerFactory.New(quantalTemplate).IsConstructed(), jc.IsTrue)
+}
+
+func (s *LxcSuite) TestEnsureTemplatesWithoutClone(c *gc.C) {
+	s.PatchValue(&s.useClone, false)
+	manager := s.makeManager(c, "test").(lxc.TemplateManager)
+	network := container.BridgeNetworkConfig("nic42", 0, nil)
+	err := manager.EnsureTemplates([]string{"quantal"}, network, lxc.TemplateConfig{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.ContainerFactory.New(quantalTemplate).IsConstructed(), jc.IsFalse)
+}
+
+func (s *LxcSuite) TestRemoveUnusedTemplates(c *gc.C) {
+	s.createTemplate(c)
+	manager := s.makeManager(c, "test").(lxc.TemplateManager)
+
+	// Recently used templates are kept.
+	removed, err := manager.RemoveUnusedTemplates(24 * time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, gc.HasLen, 0)
+
+	// As are all templates when there is no maximum age.
+	s.setTemplateLastUsed(c, quantalTemplate, time.Now().Add(-48*time.Hour))
+	removed, err = manager.RemoveUnusedTemplates(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, gc.HasLen, 0)
+
+	removed, err = manager.RemoveUnusedTemplates(24 * time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, jc.DeepEquals, []string{quantalTemplate})
+	s.AssertEvent(c, <-s.events, mock.Destroyed, quantalTemplate)
+	c.Assert(s.ContainerFactory.New(quantalTemplate).IsConstructed(), jc.IsFalse)
+	c.Assert(filepath.Join(s.ContainerDir, quantalTemplate), jc.DoesNotExist)
+}
+
+func (s *LxcSuite) TestRemoveUnusedTemplatesKeepsSnapshotted(c *gc.C) {
+	s.createTemplate(c)
+	s.setTemplateLastUsed(c, quantalTemplate, time.Now().Add(-48*time.Hour))
+	snapshots := filepath.Join(s.LxcDir, quantalTemplate, "lxc_snapshots")
+	err := os.MkdirAll(filepath.Dir(snapshots), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(snapshots, []byte("/var/lib/lxc\njuju-machine-1-lxc-0\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	manager := s.makeManager(c, "test").(lxc.TemplateManager)
+	removed, err := manager.RemoveUnusedTemplates(24 * time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, gc.HasLen, 0)
+	c.Assert(s.ContainerFactory.New(quantalTemplate).IsConstructed(), jc.IsTrue)
+}
@@ -0,0 +1,302 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// The status codes used by LXD for containers and operations.
+const (
+	StatusRunning = 103
+	StatusStopped = 102
+	StatusSuccess = 200
+)
+
+// Container holds the information LXD reports about a container.
+type Container struct {
+	Name       string                       `json:"name"`
+	Status     string                       `json:"status"`
+	StatusCode int                          `json:"status_code"`
+	Config     map[string]string            `json:"config"`
+	Devices    map[string]map[string]string `json:"devices"`
+}
+
+// IsRunning returns whether the container is running.
+func (c *Container) IsRunning() bool {
+	return c.StatusCode == StatusRunning
+}
+
+// ContainerState holds the runtime state of a container.
+type ContainerState struct {
+	Status     string                  `json:"status"`
+	StatusCode int                     `json:"status_code"`
+	Network    map[string]NetworkState `json:"network"`
+}
+
+// NetworkState holds the state of one of a container's network
+// interfaces.
+type NetworkState struct {
+	Addresses []NetworkAddress `json:"addresses"`
+	HostName  string           `json:"host_name"`
+	State     string           `json:"state"`
+}
+
+// NetworkAddress is an address of a container's network interface.
+type NetworkAddress struct {
+	Family  string `json:"family"`
+	Address string `json:"address"`
+	Netmask string `json:"netmask"`
+	Scope   string `json:"scope"`
+}
+
+// Network holds the information LXD reports about a network on the
+// host, such as a bridge containers may be attached to.
+type Network struct {
+	Name    string            `json:"name"`
+	Type    string            `json:"type"`
+	Managed bool              `json:"managed"`
+	Config  map[string]string `json:"config"`
+}
+
+// ContainerSpec describes a container to be created.
+type ContainerSpec struct {
+	Name     string                       `json:"name"`
+	Profiles []string                     `json:"profiles,omitempty"`
+	Config   map[string]string            `json:"config,omitempty"`
+	Devices  map[string]map[string]string `json:"devices,omitempty"`
+	Source   ImageSource                  `json:"source"`
+}
+
+// ImageSource identifies the image from which a container is
+// created. An image with the given alias or fingerprint is pulled from
+// Server if it is not already held by the LXD daemon.
+type ImageSource struct {
+	Type        string `json:"type"`
+	Mode        string `json:"mode,omitempty"`
+	Server      string `json:"server,omitempty"`
+	Protocol    string `json:"protocol,omitempty"`
+	Alias       string `json:"alias,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// response is the envelope in which LXD returns the result of every
+// request.
+type response struct {
+	Type       string          `json:"type"`
+	Status     string          `json:"status"`
+	StatusCode int             `json:"status_code"`
+	Operation  string          `json:"operation"`
+	ErrorCode  int             `json:"error_code"`
+	Error      string          `json:"error"`
+	Metadata   json.RawMessage `json:"metadata"`
+}
+
+// operation holds the state of a background operation, as returned
+// for asynchronous requests.
+type operation struct {
+	ID         string `json:"id"`
+	Status     string `json:"status"`
+	StatusCode int    `json:"status_code"`
+	Err        string `json:"err"`
+}
+
+// stateChange is the body of a request to change a container's state.
+type stateChange struct {
+	Action  string `json:"action"`
+	Timeout int    `json:"timeout"`
+	Force   bool   `json:"force"`
+}
+
+// Client talks to the LXD daemon's REST API over its local unix
+// socket.
+type Client struct {
+	http *http.Client
+}
+
+// NewClient returns a client for the LXD daemon listening on the
+// given unix socket. No connection is made until a request is sent.
+func NewClient(socketPath string) *Client {
+	transport := &http.Transport{
+		Dial: func(string, string) (net.Conn, error) {
+			return net.Dial("unix", socketPath)
+		},
+	}
+	return &Client{http: &http.Client{Transport: transport}}
+}
+
+// Containers returns all the containers known to the LXD daemon.
+func (c *Client) Containers() ([]Container, error) {
+	var containers []Container
+	if err := c.get("/1.0/containers?recursion=1", &containers); err != nil {
+		return nil, errors.Annotate(err, "cannot list containers")
+	}
+	return containers, nil
+}
+
+// Container returns the named container. If it does not exist, an
+// error satisfying errors.IsNotFound is returned.
+func (c *Client) Container(name string) (*Container, error) {
+	var container Container
+	if err := c.get(containerPath(name), &container); err != nil {
+		return nil, errors.Annotatef(err, "cannot get container %q", name)
+	}
+	return &container, nil
+}
+
+// ContainerState returns the runtime state of the named container.
+func (c *Client) ContainerState(name string) (*ContainerState, error) {
+	var state ContainerState
+	if err := c.get(containerPath(name)+"/state", &state); err != nil {
+		return nil, errors.Annotatef(err, "cannot get state of container %q", name)
+	}
+	return &state, nil
+}
+
+// CreateContainer creates a container as described by spec, waiting
+// for its image to be fetched if necessary. The container is not
+// started.
+func (c *Client) CreateContainer(spec ContainerSpec) error {
+	if err := c.do("POST", "/1.0/containers", spec, nil); err != nil {
+		return errors.Annotatef(err, "cannot create container %q", spec.Name)
+	}
+	return nil
+}
+
+// StartContainer starts the named container.
+func (c *Client) StartContainer(name string) error {
+	change := stateChange{Action: "start", Timeout: -1}
+	if err := c.do("PUT", containerPath(name)+"/state", change, nil); err != nil {
+		return errors.Annotatef(err, "cannot start container %q", name)
+	}
+	return nil
+}
+
+// StopContainer stops the named container, killing it if it does not
+// shut down cleanly within the given number of seconds.
+func (c *Client) StopContainer(name string, timeout int) error {
+	change := stateChange{Action: "stop", Timeout: timeout, Force: true}
+	if err := c.do("PUT", containerPath(name)+"/state", change, nil); err != nil {
+		return errors.Annotatef(err, "cannot stop container %q", name)
+	}
+	return nil
+}
+
+// DeleteContainer removes the named container, which must be stopped.
+func (c *Client) DeleteContainer(name string) error {
+	if err := c.do("DELETE", containerPath(name), nil, nil); err != nil {
+		return errors.Annotatef(err, "cannot delete container %q", name)
+	}
+	return nil
+}
+
+// Networks returns the networks on the LXD host.
+func (c *Client) Networks() ([]Network, error) {
+	var networks []Network
+	if err := c.get("/1.0/networks?recursion=1", &networks); err != nil {
+		return nil, errors.Annotate(err, "cannot list networks")
+	}
+	return networks, nil
+}
+
+func containerPath(name string) string {
+	return "/1.0/containers/" + url.QueryEscape(name)
+}
+
+func (c *Client) get(path string, result interface{}) error {
+	return c.do("GET", path, nil, result)
+}
+
+// do sends a request to the LXD daemon and unmarshals the metadata of
+// the response into result, if it is not nil. If the daemon starts a
+// background operation, do waits for it to complete.
+func (c *Client) do(method, path string, body, result interface{}) error {
+	resp, err := c.send(method, path, body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch resp.Type {
+	case "sync":
+	case "async":
+		return c.wait(resp.Operation)
+	case "error":
+		return responseError(resp)
+	default:
+		return errors.Errorf("unexpected response type %q", resp.Type)
+	}
+	if result == nil || len(resp.Metadata) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Metadata, result); err != nil {
+		return errors.Annotate(err, "cannot decode response")
+	}
+	return nil
+}
+
+// wait waits for the background operation at the given path to
+// complete, and returns an error if it failed.
+func (c *Client) wait(path string) error {
+	resp, err := c.send("GET", path+"/wait", nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if resp.Type == "error" {
+		return responseError(resp)
+	}
+	var op operation
+	if err := json.Unmarshal(resp.Metadata, &op); err != nil {
+		return errors.Annotate(err, "cannot decode operation")
+	}
+	if op.StatusCode != StatusSuccess {
+		if op.Err == "" {
+			op.Err = strings.ToLower(op.Status)
+		}
+		return errors.Errorf("operation failed: %s", op.Err)
+	}
+	return nil
+}
+
+func (c *Client) send(method, path string, body interface{}) (*response, error) {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	// The host is ignored, since we always dial the socket.
+	req, err := http.NewRequest(method, "http://lxd"+path, reqBody)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	httpResp, err := c.http.Do(req)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot connect to LXD")
+	}
+	defer httpResp.Body.Close()
+	var resp response
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, errors.Annotatef(err, "cannot decode LXD response (%s)", httpResp.Status)
+	}
+	return &resp, nil
+}
+
+func responseError(resp *response) error {
+	if resp.ErrorCode == http.StatusNotFound {
+		return errors.NewNotFound(nil, resp.Error)
+	}
+	return errors.New(resp.Error)
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container/lxd"
+	lxdtesting "github.com/juju/juju/container/lxd/testing"
+)
+
+type ClientSuite struct {
+	lxdtesting.TestSuite
+	client *lxd.Client
+}
+
+var _ = gc.Suite(&ClientSuite{})
+
+func (s *ClientSuite) SetUpTest(c *gc.C) {
+	s.TestSuite.SetUpTest(c)
+	s.client = lxd.NewClient(s.Server.SocketPath)
+}
+
+func (s *ClientSuite) TestContainerNotFound(c *gc.C) {
+	_, err := s.client.Container("missing")
+	c.Assert(err, gc.ErrorMatches, `cannot get container "missing": not found`)
+	c.Assert(errors.IsNotFound(err), jc.IsTrue)
+}
+
+func (s *ClientSuite) TestCreateStartStopDelete(c *gc.C) {
+	err := s.client.CreateContainer(lxd.ContainerSpec{
+		Name:   "spam",
+		Source: lxd.ImageSource{Type: "image", Alias: "trusty"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	container, err := s.client.Container("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(container.IsRunning(), jc.IsFalse)
+
+	err = s.client.StartContainer("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	containers, err := s.client.Containers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 1)
+	c.Assert(containers[0].Name, gc.Equals, "spam")
+	c.Assert(containers[0].IsRunning(), jc.IsTrue)
+
+	err = s.client.StopContainer("spam", 1)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.client.DeleteContainer("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	containers, err = s.client.Containers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 0)
+}
+
+func (s *ClientSuite) TestOperationFailure(c *gc.C) {
+	s.Server.AddContainer("spam", true)
+	err := s.client.StartContainer("spam")
+	c.Assert(err, gc.ErrorMatches, `cannot start container "spam": operation failed: The container is already running`)
+}
+
+func (s *ClientSuite) TestRequestError(c *gc.C) {
+	s.Server.AddContainer("spam", true)
+	err := s.client.DeleteContainer("spam")
+	c.Assert(err, gc.ErrorMatches, `cannot delete container "spam": container is running`)
+}
+
+func (s *ClientSuite) TestConnectionError(c *gc.C) {
+	client := lxd.NewClient(c.MkDir() + "/missing.socket")
+	_, err := client.Containers()
+	c.Assert(err, gc.ErrorMatches, "cannot list containers: cannot connect to LXD: .*")
+}
+
+func (s *ClientSuite) TestNetworks(c *gc.C) {
+	networks, err := s.client.Networks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(networks, jc.DeepEquals, []lxd.Network{{
+		Name:    "lxdbr0",
+		Type:    "bridge",
+		Managed: true,
+	}})
+}
@@ -0,0 +1,53 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"github.com/juju/utils/packaging/manager"
+
+	"github.com/juju/juju/container"
+)
+
+var requiredPackages = []string{
+	"lxd",
+}
+
+type containerInitialiser struct {
+	series string
+}
+
+// containerInitialiser implements container.Initialiser.
+var _ container.Initialiser = (*containerInitialiser)(nil)
+
+// NewContainerInitialiser returns an instance used to perform the steps
+// required to allow a host machine to run a LXD container.
+func NewContainerInitialiser(series string) container.Initialiser {
+	return &containerInitialiser{series}
+}
+
+// Initialise is specified on the container.Initialiser interface.
+func (ci *containerInitialiser) Initialise() error {
+	return ensureDependencies(ci.series)
+}
+
+// getPackageManager is a helper function which returns the
+// package manager implementation for the current system.
+func getPackageManager(series string) (manager.PackageManager, error) {
+	return manager.NewPackageManager(series)
+}
+
+// ensureDependencies installs the packages needed to run the LXD
+// daemon, which starts listening on its socket once installed.
+func ensureDependencies(series string) error {
+	pacman, err := getPackageManager(series)
+	if err != nil {
+		return err
+	}
+	for _, pack := range requiredPackages {
+		if err := pacman.Install(pack); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+)
+
+type lxdInstance struct {
+	id     string
+	client *Client
+}
+
+var _ instance.Instance = (*lxdInstance)(nil)
+
+// Id implements instance.Instance.Id.
+func (lxd *lxdInstance) Id() instance.Id {
+	return instance.Id(lxd.id)
+}
+
+// Status implements instance.Instance.Status.
+func (lxd *lxdInstance) Status() string {
+	container, err := lxd.client.Container(lxd.id)
+	if err != nil {
+		logger.Warningf("cannot get status of lxd container %q: %v", lxd.id, err)
+		return "unknown"
+	}
+	return container.Status
+}
+
+// Refresh implements instance.Instance.Refresh.
+func (*lxdInstance) Refresh() error {
+	return nil
+}
+
+// Addresses implements instance.Instance.Addresses, returning the
+// addresses LXD reports for the container's network interfaces.
+func (lxd *lxdInstance) Addresses() ([]network.Address, error) {
+	state, err := lxd.client.ContainerState(lxd.id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var addresses []network.Address
+	for name, netState := range state.Network {
+		if name == "lo" {
+			continue
+		}
+		for _, addr := range netState.Addresses {
+			if addr.Scope == "link" || addr.Scope == "local" {
+				continue
+			}
+			addresses = append(addresses, network.NewScopedAddress(addr.Address, network.ScopeCloudLocal))
+		}
+	}
+	return addresses, nil
+}
+
+// OpenPorts implements instance.Instance.OpenPorts.
+func (lxd *lxdInstance) OpenPorts(machineId string, ports []network.PortRange) error {
+	return fmt.Errorf("not implemented")
+}
+
+// ClosePorts implements instance.Instance.ClosePorts.
+func (lxd *lxdInstance) ClosePorts(machineId string, ports []network.PortRange) error {
+	return fmt.Errorf("not implemented")
+}
+
+// Ports implements instance.Instance.Ports.
+func (lxd *lxdInstance) Ports(machineId string) ([]network.PortRange, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+// Add a string representation of the id.
+func (lxd *lxdInstance) String() string {
+	return fmt.Sprintf("lxd:%s", lxd.id)
+}
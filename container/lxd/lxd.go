@@ -0,0 +1,233 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/cloudconfig/containerinit"
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/lxc/lxcutils"
+	"github.com/juju/juju/environs/imagemetadata"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/version"
+)
+
+var (
+	logger = loggo.GetLogger("juju.container.lxd")
+
+	// SocketPath holds the path of the unix socket on which the LXD
+	// daemon listens.
+	SocketPath = "/var/lib/lxd/unix.socket"
+
+	// DefaultLxdBridge is the bridge containers are attached to if
+	// none is configured.
+	DefaultLxdBridge = "lxdbr0"
+
+	// ImageServer is the simplestreams server from which container
+	// images are fetched.
+	ImageServer = "https://cloud-images.ubuntu.com"
+
+	// StopTimeout is the number of seconds a container is given to
+	// shut down cleanly before it is killed.
+	StopTimeout = 30
+
+	runtimeGOOS = runtime.GOOS
+)
+
+// IsLXDSupported returns a boolean value indicating whether or not
+// we can run LXD containers. It is a variable to allow us to override
+// behaviour in the tests.
+var IsLXDSupported = func() (bool, error) {
+	if runtimeGOOS != "linux" {
+		return false, nil
+	}
+	// We do not support running LXD inside LXC containers.
+	insideLXC, err := lxcutils.RunningInsideLXC()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return !insideLXC, nil
+}
+
+// NewContainerManager returns a manager object that can start and stop
+// LXD containers. The containers that are created are namespaced by
+// the name parameter.
+func NewContainerManager(conf container.ManagerConfig) (container.Manager, error) {
+	name := conf.PopValue(container.ConfigName)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	conf.WarnAboutUnused()
+	return &containerManager{
+		name:   name,
+		client: NewClient(SocketPath),
+	}, nil
+}
+
+// containerManager creates, lists and destroys containers through the
+// LXD daemon's REST API.
+type containerManager struct {
+	name   string
+	client *Client
+}
+
+var _ container.Manager = (*containerManager)(nil)
+
+// CreateContainer is specified on the container.Manager interface.
+func (manager *containerManager) CreateContainer(
+	instanceConfig *instancecfg.InstanceConfig,
+	series string,
+	networkConfig *container.NetworkConfig,
+	storageConfig *container.StorageConfig,
+) (instance.Instance, *instance.HardwareCharacteristics, error) {
+	name := names.NewMachineTag(instanceConfig.MachineId).String()
+	if manager.name != "" {
+		name = fmt.Sprintf("%s-%s", manager.name, name)
+	}
+	instanceConfig.MachineContainerHostname = name
+
+	bridge := DefaultLxdBridge
+	if networkConfig != nil && networkConfig.Device != "" {
+		bridge = networkConfig.Device
+	}
+	if err := manager.checkBridge(bridge); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	logger.Tracef("generate cloud-init")
+	userData, err := containerinit.CloudInitUserData(instanceConfig, networkConfig)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "failed to generate user data")
+	}
+
+	spec := ContainerSpec{
+		Name: name,
+		Config: map[string]string{
+			"user.user-data": string(userData),
+		},
+		Devices: map[string]map[string]string{
+			"eth0": {
+				"type":    "nic",
+				"nictype": "bridged",
+				"parent":  bridge,
+			},
+		},
+		Source: imageSource(series, instanceConfig.ImageStream),
+	}
+	arch := version.Current.Arch
+	hardware := instance.HardwareCharacteristics{
+		Arch: &arch,
+	}
+	cons := instanceConfig.Constraints
+	if cons.Mem != nil {
+		spec.Config["limits.memory"] = fmt.Sprintf("%dMB", *cons.Mem)
+		hardware.Mem = cons.Mem
+	}
+	if cons.CpuCores != nil {
+		spec.Config["limits.cpu"] = fmt.Sprint(*cons.CpuCores)
+		hardware.CpuCores = cons.CpuCores
+	}
+
+	logger.Tracef("create the container, constraints: %v", cons)
+	if err := manager.client.CreateContainer(spec); err != nil {
+		err = errors.Annotate(err, "lxd container creation failed")
+		logger.Infof(err.Error())
+		return nil, nil, err
+	}
+	if err := manager.client.StartContainer(name); err != nil {
+		if err := manager.client.DeleteContainer(name); err != nil {
+			logger.Errorf("cannot remove container %q after failed start: %v", name, err)
+		}
+		return nil, nil, errors.Trace(err)
+	}
+	logger.Tracef("lxd container created")
+	return &lxdInstance{id: name, client: manager.client}, &hardware, nil
+}
+
+// imageSource returns the source of the image for containers of the
+// given series, fetched from the given simplestreams image stream.
+func imageSource(series, stream string) ImageSource {
+	if stream == "" {
+		stream = imagemetadata.ReleasedStream
+	}
+	return ImageSource{
+		Type:     "image",
+		Mode:     "pull",
+		Server:   ImageServer + "/" + stream,
+		Protocol: "simplestreams",
+		Alias:    series,
+	}
+}
+
+// checkBridge returns an error if the LXD host has no bridge with the
+// given name for containers to be attached to.
+func (manager *containerManager) checkBridge(bridge string) error {
+	networks, err := manager.client.Networks()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, network := range networks {
+		if network.Name == bridge {
+			if network.Type != "bridge" {
+				return errors.Errorf("network %q is not a bridge", bridge)
+			}
+			return nil
+		}
+	}
+	return errors.NotFoundf("bridge %q", bridge)
+}
+
+// DestroyContainer is specified on the container.Manager interface.
+func (manager *containerManager) DestroyContainer(id instance.Id) error {
+	name := string(id)
+	lxdContainer, err := manager.client.Container(name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if lxdContainer.IsRunning() {
+		if err := manager.client.StopContainer(name, StopTimeout); err != nil {
+			logger.Errorf("failed to stop lxd container: %v", err)
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(manager.client.DeleteContainer(name))
+}
+
+// ListContainers is specified on the container.Manager interface.
+func (manager *containerManager) ListContainers() (result []instance.Instance, err error) {
+	containers, err := manager.client.Containers()
+	if err != nil {
+		logger.Errorf("failed getting all instances: %v", err)
+		return nil, errors.Trace(err)
+	}
+	managerPrefix := fmt.Sprintf("%s-", manager.name)
+	for _, container := range containers {
+		// Filter out those not starting with our name.
+		if !strings.HasPrefix(container.Name, managerPrefix) {
+			continue
+		}
+		if container.IsRunning() {
+			result = append(result, &lxdInstance{id: container.Name, client: manager.client})
+		}
+	}
+	return result, nil
+}
+
+// IsInitialized is specified on the container.Manager interface.
+func (manager *containerManager) IsInitialized() bool {
+	_, err := os.Stat(SocketPath)
+	return err == nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/lxd"
+	lxdtesting "github.com/juju/juju/container/lxd/testing"
+	containertesting "github.com/juju/juju/container/testing"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/dummy"
+)
+
+type LxdSuite struct {
+	lxdtesting.TestSuite
+	manager container.Manager
+}
+
+var _ = gc.Suite(&LxdSuite{})
+
+func (s *LxdSuite) SetUpTest(c *gc.C) {
+	s.TestSuite.SetUpTest(c)
+	var err error
+	s.manager, err = lxd.NewContainerManager(container.ManagerConfig{container.ConfigName: "test"})
+	c.Assert(err, jc.ErrorIsNil)
+	// The container testing helpers attach containers to nic42.
+	s.Server.SetNetworks(lxd.Network{Name: "nic42", Type: "bridge"})
+}
+
+func (*LxdSuite) TestManagerNameNeeded(c *gc.C) {
+	manager, err := lxd.NewContainerManager(container.ManagerConfig{container.ConfigName: ""})
+	c.Assert(err, gc.ErrorMatches, "name is required")
+	c.Assert(manager, gc.IsNil)
+}
+
+func (s *LxdSuite) TestIsInitialized(c *gc.C) {
+	c.Assert(s.manager.IsInitialized(), jc.IsTrue)
+	s.PatchValue(&lxd.SocketPath, c.MkDir()+"/missing.socket")
+	c.Assert(s.manager.IsInitialized(), jc.IsFalse)
+}
+
+func (s *LxdSuite) TestIsLXDSupportedNotLinux(c *gc.C) {
+	s.PatchValue(lxd.RuntimeGOOS, "windows")
+	supported, err := lxd.IsLXDSupported()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(supported, jc.IsFalse)
+}
+
+func (s *LxdSuite) TestListInitiallyEmpty(c *gc.C) {
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, gc.HasLen, 0)
+}
+
+func (s *LxdSuite) TestListMatchesManagerNameAndRunning(c *gc.C) {
+	s.Server.AddContainer("test-match1", true)
+	s.Server.AddContainer("test-match2", true)
+	s.Server.AddContainer("test-stopped", false)
+	s.Server.AddContainer("testNoMatch", true)
+	s.Server.AddContainer("other", true)
+	containers, err := s.manager.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	var ids []instance.Id
+	for _, inst := range containers {
+		ids = append(ids, inst.Id())
+	}
+	c.Assert(ids, jc.SameContents, []instance.Id{"test-match1", "test-match2"})
+}
+
+func (s *LxdSuite) TestCreateContainer(c *gc.C) {
+	inst := containertesting.CreateContainer(c, s.manager, "1/lxd/0")
+	name := string(inst.Id())
+	c.Assert(name, gc.Equals, "test-machine-1-lxd-0")
+	c.Assert(inst.Status(), gc.Equals, "Running")
+
+	specs := s.Server.CreatedSpecs()
+	c.Assert(specs, gc.HasLen, 1)
+	spec := specs[0]
+	c.Assert(spec.Name, gc.Equals, name)
+	c.Assert(spec.Source, jc.DeepEquals, lxd.ImageSource{
+		Type:     "image",
+		Mode:     "pull",
+		Server:   "https://cloud-images.ubuntu.com/released",
+		Protocol: "simplestreams",
+		Alias:    "quantal",
+	})
+	c.Assert(spec.Devices, jc.DeepEquals, map[string]map[string]string{
+		"eth0": {"type": "nic", "nictype": "bridged", "parent": "nic42"},
+	})
+	c.Assert(spec.Config["user.user-data"], jc.HasPrefix, "#cloud-config\n")
+
+	addrs, err := inst.Addresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addrs, jc.DeepEquals, []network.Address{
+		network.NewScopedAddress("10.0.8.2", network.ScopeCloudLocal),
+	})
+}
+
+func (s *LxdSuite) TestCreateContainerWithConstraints(c *gc.C) {
+	instanceConfig, err := containertesting.MockMachineConfig("1/lxd/0")
+	c.Assert(err, jc.ErrorIsNil)
+	envConfig, err := config.New(config.NoDefaults, dummy.SampleConfig())
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig.Config = envConfig
+	instanceConfig.Constraints = constraints.MustParse("mem=1G cpu-cores=2")
+
+	networkConfig := container.BridgeNetworkConfig("nic42", 0, nil)
+	_, hardware, err := s.manager.CreateContainer(instanceConfig, "trusty", networkConfig, &container.StorageConfig{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*hardware.Mem, gc.Equals, uint64(1024))
+	c.Assert(*hardware.CpuCores, gc.Equals, uint64(2))
+
+	spec := s.Server.CreatedSpecs()[0]
+	c.Assert(spec.Config["limits.memory"], gc.Equals, "1024MB")
+	c.Assert(spec.Config["limits.cpu"], gc.Equals, "2")
+	c.Assert(spec.Source.Alias, gc.Equals, "trusty")
+}
+
+func (s *LxdSuite) TestCreateContainerMissingBridge(c *gc.C) {
+	s.Server.SetNetworks(lxd.Network{Name: "eth0", Type: "physical"})
+	_, err := containertesting.CreateContainerTest(c, s.manager, "1/lxd/0")
+	c.Assert(err, gc.ErrorMatches, `.*bridge "nic42" not found`)
+	c.Assert(s.Server.CreatedSpecs(), gc.HasLen, 0)
+}
+
+func (s *LxdSuite) TestDestroyContainer(c *gc.C) {
+	inst := containertesting.CreateContainer(c, s.manager, "1/lxd/0")
+
+	err := s.manager.DestroyContainer(inst.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := s.Server.Container(string(inst.Id()))
+	c.Assert(ok, jc.IsFalse)
+
+	// Destroying a container that no longer exists is not an error.
+	err = s.manager.DestroyContainer(inst.Id())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *LxdSuite) TestDestroyStoppedContainer(c *gc.C) {
+	s.Server.AddContainer("test-machine-2", false)
+
+	err := s.manager.DestroyContainer("test-machine-2")
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := s.Server.Container("test-machine-2")
+	c.Assert(ok, jc.IsFalse)
+}
@@ -0,0 +1,294 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/container/lxd"
+)
+
+// FakeServer implements enough of the LXD REST API, on a unix socket,
+// to create, start, stop, list and delete containers. Background
+// operations complete before the request that starts them returns.
+type FakeServer struct {
+	// SocketPath holds the path of the socket the server listens on.
+	SocketPath string
+
+	server *httptest.Server
+
+	mu         sync.Mutex
+	containers map[string]*lxd.Container
+	networks   []lxd.Network
+	specs      []lxd.ContainerSpec
+	operations map[string]fakeOperation
+	nextOp     int
+}
+
+type fakeOperation struct {
+	ID         string `json:"id"`
+	Status     string `json:"status"`
+	StatusCode int    `json:"status_code"`
+	Err        string `json:"err"`
+}
+
+// NewFakeServer starts a fake LXD server listening on the given
+// socket path. The host has a single bridge, lxd.DefaultLxdBridge.
+func NewFakeServer(socketPath string) (*FakeServer, error) {
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	s := &FakeServer{
+		SocketPath: socketPath,
+		containers: make(map[string]*lxd.Container),
+		networks: []lxd.Network{{
+			Name:    lxd.DefaultLxdBridge,
+			Type:    "bridge",
+			Managed: true,
+		}},
+		operations: make(map[string]fakeOperation),
+	}
+	s.server = httptest.NewUnstartedServer(http.HandlerFunc(s.serveHTTP))
+	s.server.Listener.Close()
+	s.server.Listener = listener
+	s.server.Start()
+	return s, nil
+}
+
+// Close stops the server.
+func (s *FakeServer) Close() {
+	s.server.Close()
+}
+
+// AddContainer adds a container to the server, as if created outside
+// juju.
+func (s *FakeServer) AddContainer(name string, running bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.containers[name] = newContainer(name, nil, running)
+}
+
+// Container returns the named container, if it exists.
+func (s *FakeServer) Container(name string) (lxd.Container, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	container, ok := s.containers[name]
+	if !ok {
+		return lxd.Container{}, false
+	}
+	return *container, true
+}
+
+// CreatedSpecs returns the specs of all the containers created through
+// the API, in order.
+func (s *FakeServer) CreatedSpecs() []lxd.ContainerSpec {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]lxd.ContainerSpec(nil), s.specs...)
+}
+
+// SetNetworks replaces the networks reported by the server.
+func (s *FakeServer) SetNetworks(networks ...lxd.Network) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.networks = networks
+}
+
+func newContainer(name string, config map[string]string, running bool) *lxd.Container {
+	container := &lxd.Container{
+		Name:   name,
+		Config: config,
+	}
+	setRunning(container, running)
+	return container
+}
+
+func setRunning(container *lxd.Container, running bool) {
+	if running {
+		container.Status, container.StatusCode = "Running", lxd.StatusRunning
+	} else {
+		container.Status, container.StatusCode = "Stopped", lxd.StatusStopped
+	}
+}
+
+func (s *FakeServer) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "1.0" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	switch {
+	case len(parts) == 2 && parts[1] == "containers":
+		s.serveContainers(w, req)
+	case len(parts) == 3 && parts[1] == "containers":
+		s.serveContainer(w, req, parts[2])
+	case len(parts) == 4 && parts[1] == "containers" && parts[3] == "state":
+		s.serveContainerState(w, req, parts[2])
+	case len(parts) == 2 && parts[1] == "networks" && req.Method == "GET":
+		writeSync(w, s.networks)
+	case len(parts) == 4 && parts[1] == "operations" && parts[3] == "wait":
+		op, ok := s.operations[parts[2]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		writeSync(w, op)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *FakeServer) serveContainers(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case "GET":
+		containers := []lxd.Container{}
+		for _, container := range s.containers {
+			containers = append(containers, *container)
+		}
+		writeSync(w, containers)
+	case "POST":
+		var spec lxd.ContainerSpec
+		if err := json.NewDecoder(req.Body).Decode(&spec); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if _, ok := s.containers[spec.Name]; ok {
+			writeError(w, http.StatusConflict, "The container already exists")
+			return
+		}
+		s.specs = append(s.specs, spec)
+		s.containers[spec.Name] = newContainer(spec.Name, spec.Config, false)
+		s.writeOperation(w, "")
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *FakeServer) serveContainer(w http.ResponseWriter, req *http.Request, name string) {
+	container, ok := s.containers[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	switch req.Method {
+	case "GET":
+		writeSync(w, container)
+	case "DELETE":
+		if container.StatusCode == lxd.StatusRunning {
+			writeError(w, http.StatusBadRequest, "container is running")
+			return
+		}
+		delete(s.containers, name)
+		s.writeOperation(w, "")
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *FakeServer) serveContainerState(w http.ResponseWriter, req *http.Request, name string) {
+	container, ok := s.containers[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	switch req.Method {
+	case "GET":
+		state := lxd.ContainerState{
+			Status:     container.Status,
+			StatusCode: container.StatusCode,
+		}
+		if container.StatusCode == lxd.StatusRunning {
+			state.Network = map[string]lxd.NetworkState{
+				"lo": {Addresses: []lxd.NetworkAddress{{
+					Family: "inet", Address: "127.0.0.1", Netmask: "8", Scope: "local",
+				}}},
+				"eth0": {Addresses: []lxd.NetworkAddress{{
+					Family: "inet", Address: "10.0.8.2", Netmask: "24", Scope: "global",
+				}, {
+					Family: "inet6", Address: "fe80::1", Netmask: "64", Scope: "link",
+				}}},
+			}
+		}
+		writeSync(w, state)
+	case "PUT":
+		var change struct {
+			Action string `json:"action"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&change); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		running := container.StatusCode == lxd.StatusRunning
+		switch {
+		case change.Action == "start" && running:
+			s.writeOperation(w, "The container is already running")
+		case change.Action == "stop" && !running:
+			s.writeOperation(w, "The container is already stopped")
+		case change.Action == "start", change.Action == "stop":
+			setRunning(container, change.Action == "start")
+			s.writeOperation(w, "")
+		default:
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown action %q", change.Action))
+		}
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// writeOperation records a completed background operation, which
+// failed with the given error if it is not empty, and writes the
+// response that starts it.
+func (s *FakeServer) writeOperation(w http.ResponseWriter, failure string) {
+	s.nextOp++
+	op := fakeOperation{
+		ID:         fmt.Sprint(s.nextOp),
+		Status:     "Success",
+		StatusCode: lxd.StatusSuccess,
+	}
+	if failure != "" {
+		op.Status, op.StatusCode, op.Err = "Failure", 400, failure
+	}
+	s.operations[op.ID] = op
+	writeResponse(w, http.StatusAccepted, map[string]interface{}{
+		"type":        "async",
+		"status":      "Operation created",
+		"status_code": 100,
+		"operation":   "/1.0/operations/" + op.ID,
+		"metadata":    op,
+	})
+}
+
+func writeSync(w http.ResponseWriter, metadata interface{}) {
+	writeResponse(w, http.StatusOK, map[string]interface{}{
+		"type":        "sync",
+		"status":      "Success",
+		"status_code": lxd.StatusSuccess,
+		"metadata":    metadata,
+	})
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeResponse(w, code, map[string]interface{}{
+		"type":       "error",
+		"error":      message,
+		"error_code": code,
+	})
+}
+
+func writeResponse(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
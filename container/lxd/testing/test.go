@@ -0,0 +1,43 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Functions defined in this file should *ONLY* be used for testing.  These
+// functions are exported for testing purposes only, and shouldn't be called
+// from code that isn't in a test file.
+
+package testing
+
+import (
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/testing"
+)
+
+// TestSuite starts a fake LXD server for each test, and points the
+// container manager at its socket.
+type TestSuite struct {
+	testing.BaseSuite
+	Server *FakeServer
+}
+
+func (s *TestSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	socketPath := filepath.Join(c.MkDir(), "unix.socket")
+	server, err := NewFakeServer(socketPath)
+	c.Assert(err, jc.ErrorIsNil)
+	s.Server = server
+	s.PatchValue(&lxd.SocketPath, socketPath)
+	c.Logf("lxd.SocketPath = %q", socketPath)
+}
+
+func (s *TestSuite) TearDownTest(c *gc.C) {
+	if s.Server != nil {
+		s.Server.Close()
+		s.Server = nil
+	}
+	s.BaseSuite.TearDownTest(c)
+}
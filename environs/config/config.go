@@ -6,8 +6,11 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"net"
+	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -17,6 +20,8 @@ import (
 	"github.com/juju/schema"
 	"github.com/juju/utils"
 	"github.com/juju/utils/proxy"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/ssh"
 	"gopkg.in/juju/charm.v5"
 	"gopkg.in/juju/charm.v5/charmrepo"
 	"gopkg.in/juju/environschema.v1"
@@ -24,6 +29,8 @@ import (
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/tracing"
+	"github.com/juju/juju/utils/cron"
 	"github.com/juju/juju/version"
 )
 
@@ -95,6 +102,39 @@ const (
 	// config setting. Only non-zero, positive integer values will
 	// have effect.
 	DefaultLXCDefaultMTU = 0
+
+	// DefaultImageCacheMaxAge is the default value for the
+	// "image-cache-max-age" config setting.
+	DefaultImageCacheMaxAge = 30 * 24 * time.Hour
+
+	// DefaultHookRetryMaxAttempts is the default value for the
+	// "hook-retry-max-attempts" config setting.
+	DefaultHookRetryMaxAttempts = 5
+
+	// DefaultHookRetryMaxDelay is the default value for the
+	// "hook-retry-max-delay" config setting.
+	DefaultHookRetryMaxDelay = 5 * time.Minute
+
+	// BackupTargetState stores backup archives in the state server's
+	// own database. It is the default backup target.
+	BackupTargetState = "state"
+
+	// BackupTargetLocal stores backup archives in a directory on the
+	// state server, which is expected to be on separate storage (e.g.
+	// a network filesystem).
+	BackupTargetLocal = "local"
+
+	// BackupTargetS3 stores backup archives in an S3-compatible
+	// object store.
+	BackupTargetS3 = "s3"
+
+	// BackupTargetSFTP stores backup archives on a remote host,
+	// transferring them with SFTP.
+	BackupTargetSFTP = "sftp"
+
+	// DefaultBackupS3Region is the default value for the
+	// "backup-s3-region" config setting.
+	DefaultBackupS3Region = "us-east-1"
 )
 
 // TODO(katco-): Please grow this over time.
@@ -171,6 +211,140 @@ const (
 	// interfaces created for LXC containers. See also bug #1442257.
 	LXCDefaultMTU = "lxc-default-mtu"
 
+	// LogForwardSyslogAddressKey holds the host:port of a remote syslog
+	// server to which the environment's logs are forwarded. Logs are
+	// only forwarded when it is set.
+	LogForwardSyslogAddressKey = "logforward-syslog-address"
+
+	// LogForwardSyslogTLSKey specifies whether forwarded logs are sent
+	// to the remote syslog server over TLS.
+	LogForwardSyslogTLSKey = "logforward-syslog-tls"
+
+	// LogForwardSyslogCACertKey holds the PEM encoded certificate of
+	// the CA used to verify the remote syslog server when TLS is used.
+	LogForwardSyslogCACertKey = "logforward-syslog-ca-cert"
+
+	// LogForwardBackfillKey specifies whether logs recorded before
+	// forwarding was first enabled are forwarded too.
+	LogForwardBackfillKey = "logforward-backfill"
+
+	// BackupTargetKey specifies where backup archives are stored when
+	// no target is given on creation: one of "state", "local", "s3"
+	// or "sftp".
+	BackupTargetKey = "backup-target"
+
+	// BackupLocalDirKey holds the absolute path of the directory on
+	// the state server in which the "local" backup target stores
+	// archives.
+	BackupLocalDirKey = "backup-local-dir"
+
+	// BackupS3EndpointKey holds the URL of the S3-compatible object
+	// store used by the "s3" backup target. Amazon S3 is used when
+	// it is not set.
+	BackupS3EndpointKey = "backup-s3-endpoint"
+
+	// BackupS3RegionKey holds the region of the object store used by
+	// the "s3" backup target.
+	BackupS3RegionKey = "backup-s3-region"
+
+	// BackupS3BucketKey holds the name of the bucket in which the
+	// "s3" backup target stores archives.
+	BackupS3BucketKey = "backup-s3-bucket"
+
+	// BackupS3AccessKeyKey holds the access key used to authenticate
+	// with the object store used by the "s3" backup target.
+	BackupS3AccessKeyKey = "backup-s3-access-key"
+
+	// BackupS3SecretKeyKey holds the secret key used to authenticate
+	// with the object store used by the "s3" backup target.
+	BackupS3SecretKeyKey = "backup-s3-secret-key"
+
+	// BackupSFTPURLKey holds the location, in the form
+	// sftp://user@host[:port]/path, of the remote directory in which
+	// the "sftp" backup target stores archives.
+	BackupSFTPURLKey = "backup-sftp-url"
+
+	// BackupSFTPPrivateKeyKey holds the PEM encoded SSH private key
+	// used to authenticate with the "sftp" backup target's host.
+	BackupSFTPPrivateKeyKey = "backup-sftp-private-key"
+
+	// BackupSFTPHostKeyKey holds the "sftp" backup target host's
+	// public key, as a line in OpenSSH known_hosts format, against
+	// which the host is verified.
+	BackupSFTPHostKeyKey = "backup-sftp-host-key"
+
+	// BackupScheduleKey holds the cron-like schedule (e.g. "0 3 * * *")
+	// on which backups are created automatically, in UTC. No backups
+	// are scheduled when it is not set.
+	BackupScheduleKey = "backup-schedule"
+
+	// BackupRetainCountKey specifies the number of most recent
+	// scheduled backups to keep.
+	BackupRetainCountKey = "backup-retain-count"
+
+	// BackupRetainDailyKey specifies the number of days for which the
+	// most recent scheduled backup of each day is kept.
+	BackupRetainDailyKey = "backup-retain-daily"
+
+	// BackupRetainWeeklyKey specifies the number of weeks for which
+	// the most recent scheduled backup of each week is kept.
+	BackupRetainWeeklyKey = "backup-retain-weekly"
+
+	// BackupEncryptionKeyKey holds the armored OpenPGP public key to
+	// which backup archives are encrypted. Archives are not encrypted
+	// when it is not set.
+	BackupEncryptionKeyKey = "backup-encryption-key"
+
+	// APIRequestRateUserKey specifies the number of API requests per
+	// second that each user may make, across all their connections.
+	// Users are not rate limited when it is zero or not set.
+	APIRequestRateUserKey = "api-request-rate-user"
+
+	// APIRequestRateAgentKey specifies the number of API requests per
+	// second that each agent may make, across all its connections.
+	// Agents are not rate limited when it is zero or not set.
+	APIRequestRateAgentKey = "api-request-rate-agent"
+
+	// APIMaxWatchersKey specifies the number of watchers that each
+	// entity may hold open at once, across all of its API connections.
+	// The number is not limited when it is zero or not set.
+	APIMaxWatchersKey = "api-max-watchers"
+
+	// TraceEndpointKey specifies where the API server exports the
+	// spans of the API requests made by traced clients: the absolute
+	// path of a file in the API server's log directory, or the URL of
+	// an OpenTelemetry (OTLP/HTTP) collector. Requests are not traced
+	// when it is not set. Only the state server environment's setting
+	// is used.
+	TraceEndpointKey = "trace-endpoint"
+
+	// ImageCacheMaxAgeKey specifies how long (e.g. "720h") a container
+	// image cached by the state servers, or an LXC clone template, may
+	// go unused before it is removed. Nothing is removed for its age
+	// when it is "0".
+	ImageCacheMaxAgeKey = "image-cache-max-age"
+
+	// ImageCacheMaxSizeKey specifies the total size, in MiB, of the
+	// container images cached by the state servers. The least
+	// recently used images are removed to keep within it. The size
+	// is not limited when it is zero or not set.
+	ImageCacheMaxSizeKey = "image-cache-max-size"
+
+	// AutomaticallyRetryHooksKey specifies whether the unit agents
+	// retry failed hooks by themselves, waiting longer after each
+	// failure, before leaving the unit in an error state to be
+	// resolved by the user.
+	AutomaticallyRetryHooksKey = "automatically-retry-hooks"
+
+	// HookRetryMaxAttemptsKey specifies how many times a failed hook
+	// is retried automatically before the unit is left in an error
+	// state.
+	HookRetryMaxAttemptsKey = "hook-retry-max-attempts"
+
+	// HookRetryMaxDelayKey specifies the longest time (e.g. "5m") a
+	// unit agent waits between automatic retries of a failed hook.
+	HookRetryMaxDelayKey = "hook-retry-max-delay"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -639,6 +813,87 @@ func Validate(cfg, old *Config) error {
 		return errors.Errorf("%s: expected positive integer, got %v", LXCDefaultMTU, lxcDefaultMTU)
 	}
 
+	// Check the log forwarding settings are usable, when set.
+	if addr, ok := cfg.LogForwardSyslogAddress(); ok {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return errors.Errorf("%s: expected host:port, got %q", LogForwardSyslogAddressKey, addr)
+		}
+	}
+	if caCert, ok := cfg.LogForwardSyslogCACert(); ok {
+		if _, err := cert.ParseCert(caCert); err != nil {
+			return errors.Annotatef(err, "bad %s", LogForwardSyslogCACertKey)
+		}
+	}
+
+	// Check the backup target settings are usable, when set, and that
+	// the default backup target is fully configured.
+	if dir, ok := cfg.BackupLocalDir(); ok && !filepath.IsAbs(dir) {
+		return errors.Errorf("%s: expected absolute path, got %q", BackupLocalDirKey, dir)
+	}
+	if endpoint, ok := cfg.BackupS3Endpoint(); ok {
+		if u, err := url.Parse(endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.Errorf("%s: expected URL, got %q", BackupS3EndpointKey, endpoint)
+		}
+	}
+	if sftpURL, ok := cfg.BackupSFTPURL(); ok {
+		if _, err := ParseBackupSFTPURL(sftpURL); err != nil {
+			return errors.Annotatef(err, "bad %s", BackupSFTPURLKey)
+		}
+	}
+	if hostKey, ok := cfg.BackupSFTPHostKey(); ok {
+		if err := checkKnownHostsLine(hostKey); err != nil {
+			return errors.Annotatef(err, "bad %s", BackupSFTPHostKeyKey)
+		}
+	}
+	if err := cfg.CheckBackupTarget(cfg.BackupTarget()); err != nil {
+		return errors.Annotatef(err, "bad %s", BackupTargetKey)
+	}
+	if schedule, ok := cfg.BackupSchedule(); ok {
+		if _, err := cron.Parse(schedule); err != nil {
+			return errors.Annotatef(err, "bad %s", BackupScheduleKey)
+		}
+	}
+	for _, key := range []string{BackupRetainCountKey, BackupRetainDailyKey, BackupRetainWeeklyKey} {
+		if v, _ := cfg.defined[key].(int); v < 0 {
+			return errors.Errorf("%s: expected non-negative integer, got %v", key, v)
+		}
+	}
+	if key, ok := cfg.BackupEncryptionKey(); ok {
+		if _, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key)); err != nil {
+			return errors.Annotatef(err, "bad %s", BackupEncryptionKeyKey)
+		}
+	}
+	for _, key := range []string{APIRequestRateUserKey, APIRequestRateAgentKey, APIMaxWatchersKey} {
+		if v, _ := cfg.defined[key].(int); v < 0 {
+			return errors.Errorf("%s: expected non-negative integer, got %v", key, v)
+		}
+	}
+	if endpoint := cfg.TraceEndpoint(); endpoint != "" {
+		if err := tracing.ValidateEndpoint(endpoint); err != nil {
+			return errors.Annotatef(err, "bad %s", TraceEndpointKey)
+		}
+	}
+	if v := cfg.asString(ImageCacheMaxAgeKey); v != "" {
+		if maxAge, err := time.ParseDuration(v); err != nil {
+			return errors.Annotatef(err, "bad %s", ImageCacheMaxAgeKey)
+		} else if maxAge < 0 {
+			return errors.Errorf("%s: expected non-negative duration, got %v", ImageCacheMaxAgeKey, v)
+		}
+	}
+	if v, _ := cfg.defined[ImageCacheMaxSizeKey].(int); v < 0 {
+		return errors.Errorf("%s: expected non-negative integer, got %v", ImageCacheMaxSizeKey, v)
+	}
+	if v, _ := cfg.defined[HookRetryMaxAttemptsKey].(int); v < 0 {
+		return errors.Errorf("%s: expected non-negative integer, got %v", HookRetryMaxAttemptsKey, v)
+	}
+	if v := cfg.asString(HookRetryMaxDelayKey); v != "" {
+		if maxDelay, err := time.ParseDuration(v); err != nil {
+			return errors.Annotatef(err, "bad %s", HookRetryMaxDelayKey)
+		} else if maxDelay <= 0 {
+			return errors.Errorf("%s: expected positive duration, got %v", HookRetryMaxDelayKey, v)
+		}
+	}
+
 	cfg.defined = ProcessDeprecatedAttributes(cfg.defined)
 	return nil
 }
@@ -1163,6 +1418,253 @@ func (c *Config) AllowLXCLoopMounts() (bool, bool) {
 	return v, ok
 }
 
+// LogForwardSyslogAddress returns the host:port of the remote syslog
+// server to which logs should be forwarded, and whether it is set.
+func (c *Config) LogForwardSyslogAddress() (string, bool) {
+	addr := c.asString(LogForwardSyslogAddressKey)
+	return addr, addr != ""
+}
+
+// LogForwardSyslogTLS returns whether forwarded logs should be sent
+// to the remote syslog server over TLS.
+func (c *Config) LogForwardSyslogTLS() bool {
+	v, _ := c.defined[LogForwardSyslogTLSKey].(bool)
+	return v
+}
+
+// LogForwardSyslogCACert returns the PEM encoded certificate of the
+// CA used to verify the remote syslog server, and whether it is set.
+func (c *Config) LogForwardSyslogCACert() (string, bool) {
+	caCert := c.asString(LogForwardSyslogCACertKey)
+	return caCert, caCert != ""
+}
+
+// LogForwardBackfill returns whether logs recorded before forwarding
+// was first enabled should be forwarded too.
+func (c *Config) LogForwardBackfill() bool {
+	v, _ := c.defined[LogForwardBackfillKey].(bool)
+	return v
+}
+
+// BackupTarget returns the target in which backup archives are stored
+// when no target is given on creation.
+func (c *Config) BackupTarget() string {
+	if target := c.asString(BackupTargetKey); target != "" {
+		return target
+	}
+	return BackupTargetState
+}
+
+// CheckBackupTarget returns an error if the named backup target is
+// not known, or is not fully configured.
+func (c *Config) CheckBackupTarget(target string) error {
+	var required []string
+	switch target {
+	case BackupTargetState:
+	case BackupTargetLocal:
+		required = []string{BackupLocalDirKey}
+	case BackupTargetS3:
+		required = []string{BackupS3BucketKey, BackupS3AccessKeyKey, BackupS3SecretKeyKey}
+	case BackupTargetSFTP:
+		required = []string{BackupSFTPURLKey, BackupSFTPPrivateKeyKey, BackupSFTPHostKeyKey}
+	default:
+		return errors.NotValidf("backup target %q", target)
+	}
+	for _, key := range required {
+		if c.asString(key) == "" {
+			return errors.Errorf("backup target %q requires %s to be set", target, key)
+		}
+	}
+	return nil
+}
+
+// BackupLocalDir returns the directory in which the "local" backup
+// target stores archives, and whether it is set.
+func (c *Config) BackupLocalDir() (string, bool) {
+	dir := c.asString(BackupLocalDirKey)
+	return dir, dir != ""
+}
+
+// BackupS3Endpoint returns the URL of the object store used by the
+// "s3" backup target, and whether it is set.
+func (c *Config) BackupS3Endpoint() (string, bool) {
+	endpoint := c.asString(BackupS3EndpointKey)
+	return endpoint, endpoint != ""
+}
+
+// BackupS3Region returns the region of the object store used by the
+// "s3" backup target.
+func (c *Config) BackupS3Region() string {
+	if region := c.asString(BackupS3RegionKey); region != "" {
+		return region
+	}
+	return DefaultBackupS3Region
+}
+
+// BackupS3Bucket returns the name of the bucket in which the "s3"
+// backup target stores archives, and whether it is set.
+func (c *Config) BackupS3Bucket() (string, bool) {
+	bucket := c.asString(BackupS3BucketKey)
+	return bucket, bucket != ""
+}
+
+// BackupS3Credentials returns the access and secret keys used to
+// authenticate with the object store used by the "s3" backup target.
+func (c *Config) BackupS3Credentials() (accessKey, secretKey string) {
+	return c.asString(BackupS3AccessKeyKey), c.asString(BackupS3SecretKeyKey)
+}
+
+// BackupSFTPURL returns the location of the remote directory in which
+// the "sftp" backup target stores archives, and whether it is set.
+func (c *Config) BackupSFTPURL() (string, bool) {
+	sftpURL := c.asString(BackupSFTPURLKey)
+	return sftpURL, sftpURL != ""
+}
+
+// BackupSFTPPrivateKey returns the SSH private key used to authenticate
+// with the "sftp" backup target's host, and whether it is set.
+func (c *Config) BackupSFTPPrivateKey() (string, bool) {
+	key := c.asString(BackupSFTPPrivateKeyKey)
+	return key, key != ""
+}
+
+// BackupSFTPHostKey returns the known_hosts line against which the
+// "sftp" backup target's host is verified, and whether it is set.
+func (c *Config) BackupSFTPHostKey() (string, bool) {
+	key := c.asString(BackupSFTPHostKeyKey)
+	return key, key != ""
+}
+
+// BackupSchedule returns the cron-like schedule on which backups are
+// created automatically, and whether it is set.
+func (c *Config) BackupSchedule() (string, bool) {
+	schedule := c.asString(BackupScheduleKey)
+	return schedule, schedule != ""
+}
+
+// BackupRetention returns the retention policy for scheduled backups:
+// the number of most recent backups to keep, and the numbers of days
+// and weeks for which the most recent backup of each is kept. Zero
+// values do not keep any backups; if all are zero, every scheduled
+// backup is kept.
+func (c *Config) BackupRetention() (count, daily, weekly int) {
+	count, _ = c.defined[BackupRetainCountKey].(int)
+	daily, _ = c.defined[BackupRetainDailyKey].(int)
+	weekly, _ = c.defined[BackupRetainWeeklyKey].(int)
+	return count, daily, weekly
+}
+
+// BackupEncryptionKey returns the armored OpenPGP public key to which
+// backup archives are encrypted, and whether it is set.
+func (c *Config) BackupEncryptionKey() (string, bool) {
+	key := c.asString(BackupEncryptionKeyKey)
+	return key, key != ""
+}
+
+// APIRequestRates returns the number of API requests per second that
+// each user and each agent may make. Zero values are not limited.
+func (c *Config) APIRequestRates() (user, agent int) {
+	user, _ = c.defined[APIRequestRateUserKey].(int)
+	agent, _ = c.defined[APIRequestRateAgentKey].(int)
+	return user, agent
+}
+
+// APIMaxWatchers returns the number of watchers that each entity may
+// hold open at once across its API connections, or zero if it is not
+// limited.
+func (c *Config) APIMaxWatchers() int {
+	max, _ := c.defined[APIMaxWatchersKey].(int)
+	return max
+}
+
+// TraceEndpoint returns where the API server exports the spans of
+// traced API requests, or "" if they are not exported.
+func (c *Config) TraceEndpoint() string {
+	return c.asString(TraceEndpointKey)
+}
+
+// ImageCacheMaxAge returns how long a cached container image or LXC
+// clone template may go unused before it is removed, or zero if they
+// are never removed for their age.
+func (c *Config) ImageCacheMaxAge() time.Duration {
+	v := c.asString(ImageCacheMaxAgeKey)
+	if v == "" {
+		return DefaultImageCacheMaxAge
+	}
+	// The value has already been validated.
+	maxAge, _ := time.ParseDuration(v)
+	return maxAge
+}
+
+// ImageCacheMaxSize returns the total size, in MiB, of the container
+// images cached by the state servers, or zero if it is not limited.
+func (c *Config) ImageCacheMaxSize() int {
+	max, _ := c.defined[ImageCacheMaxSizeKey].(int)
+	return max
+}
+
+// AutomaticallyRetryHooks returns whether unit agents should retry
+// failed hooks by themselves before waiting to be resolved.
+func (c *Config) AutomaticallyRetryHooks() bool {
+	v, _ := c.defined[AutomaticallyRetryHooksKey].(bool)
+	return v
+}
+
+// HookRetryMaxAttempts returns how many times a failed hook is
+// retried automatically, when AutomaticallyRetryHooks is true.
+func (c *Config) HookRetryMaxAttempts() int {
+	if v, ok := c.defined[HookRetryMaxAttemptsKey].(int); ok {
+		return v
+	}
+	return DefaultHookRetryMaxAttempts
+}
+
+// HookRetryMaxDelay returns the longest time to wait between
+// automatic retries of a failed hook.
+func (c *Config) HookRetryMaxDelay() time.Duration {
+	v := c.asString(HookRetryMaxDelayKey)
+	if v == "" {
+		return DefaultHookRetryMaxDelay
+	}
+	// The value has already been validated.
+	maxDelay, _ := time.ParseDuration(v)
+	return maxDelay
+}
+
+// checkKnownHostsLine returns an error if the line is not a single
+// OpenSSH known_hosts entry of the form "hosts keytype key [comment]".
+func checkKnownHostsLine(line string) error {
+	line = strings.TrimSpace(line)
+	if strings.Contains(line, "\n") {
+		return errors.New("expected a single known_hosts line")
+	}
+	fields := strings.SplitN(line, " ", 2)
+	if len(fields) != 2 || strings.HasPrefix(fields[0], "@") {
+		return errors.Errorf("expected hosts, key type and key, got %q", line)
+	}
+	if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(fields[1])); err != nil {
+		return errors.Annotate(err, "cannot parse host key")
+	}
+	return nil
+}
+
+// ParseBackupSFTPURL parses a location of the form
+// sftp://user@host[:port]/path, as used for the "backup-sftp-url"
+// setting. The port defaults to 22.
+func ParseBackupSFTPURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if u.Scheme != "sftp" || u.User == nil || u.User.Username() == "" || u.Host == "" || !path.IsAbs(u.Path) {
+		return nil, errors.Errorf("expected sftp://user@host[:port]/path, got %q", s)
+	}
+	if _, _, err := net.SplitHostPort(u.Host); err != nil {
+		u.Host = net.JoinHostPort(u.Host, "22")
+	}
+	return u, nil
+}
+
 // ResourceTags returns a set of tags to set on environment resources
 // that Juju creates and manages, if the provider supports them. These
 // tags have no special meaning to Juju, but may be used for existing
@@ -1274,6 +1776,34 @@ var alwaysOptional = schema.Defaults{
 	SetNumaControlPolicyKey:      DefaultNumaControlPolicy,
 	AllowLXCLoopMounts:           false,
 	ResourceTagsKey:              schema.Omit,
+	LogForwardSyslogAddressKey:   schema.Omit,
+	LogForwardSyslogTLSKey:       schema.Omit,
+	LogForwardSyslogCACertKey:    schema.Omit,
+	LogForwardBackfillKey:        schema.Omit,
+	BackupTargetKey:              schema.Omit,
+	BackupLocalDirKey:            schema.Omit,
+	BackupS3EndpointKey:          schema.Omit,
+	BackupS3RegionKey:            schema.Omit,
+	BackupS3BucketKey:            schema.Omit,
+	BackupS3AccessKeyKey:         schema.Omit,
+	BackupS3SecretKeyKey:         schema.Omit,
+	BackupSFTPURLKey:             schema.Omit,
+	BackupSFTPPrivateKeyKey:      schema.Omit,
+	BackupSFTPHostKeyKey:         schema.Omit,
+	BackupScheduleKey:            schema.Omit,
+	BackupRetainCountKey:         schema.Omit,
+	BackupRetainDailyKey:         schema.Omit,
+	BackupRetainWeeklyKey:        schema.Omit,
+	BackupEncryptionKeyKey:       schema.Omit,
+	APIRequestRateUserKey:        schema.Omit,
+	APIRequestRateAgentKey:       schema.Omit,
+	APIMaxWatchersKey:            schema.Omit,
+	TraceEndpointKey:             schema.Omit,
+	ImageCacheMaxAgeKey:          schema.Omit,
+	ImageCacheMaxSizeKey:         schema.Omit,
+	AutomaticallyRetryHooksKey:   schema.Omit,
+	HookRetryMaxAttemptsKey:      schema.Omit,
+	HookRetryMaxDelayKey:         schema.Omit,
 
 	// Storage related config.
 	// Environ providers will specify their own defaults.
@@ -1552,6 +2082,21 @@ var configSchema = environschema.Fields{
 		Group:       environschema.EnvironGroup,
 		Immutable:   true,
 	},
+	APIMaxWatchersKey: {
+		Description: "The number of watchers each entity may hold open at once across its API connections (0 for no limit)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	APIRequestRateAgentKey: {
+		Description: "The number of API requests per second each agent may make (0 for no limit)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	APIRequestRateUserKey: {
+		Description: "The number of API requests per second each user may make (0 for no limit)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
 	AptFtpProxyKey: {
 		// TODO document acceptable format
 		Description: "The APT FTP proxy for the environment",
@@ -1601,6 +2146,84 @@ var configSchema = environschema.Fields{
 		Type:        environschema.Tbool,
 		Group:       environschema.EnvironGroup,
 	},
+	BackupEncryptionKeyKey: {
+		Description: "The armored OpenPGP public key to which backup archives are encrypted",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupLocalDirKey: {
+		Description: "The absolute path of the directory on the state server in which the local backup target stores archives",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupRetainCountKey: {
+		Description: "The number of most recent scheduled backups to keep",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupRetainDailyKey: {
+		Description: "The number of days for which the most recent scheduled backup of each day is kept",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupRetainWeeklyKey: {
+		Description: "The number of weeks for which the most recent scheduled backup of each week is kept",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupS3AccessKeyKey: {
+		Description: "The access key used to authenticate with the s3 backup target",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupS3BucketKey: {
+		Description: "The name of the bucket in which the s3 backup target stores archives",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupS3EndpointKey: {
+		Description: "The URL of the S3-compatible object store used by the s3 backup target (defaults to Amazon S3)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupS3RegionKey: {
+		Description: "The region of the object store used by the s3 backup target",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupS3SecretKeyKey: {
+		Description: "The secret key used to authenticate with the s3 backup target",
+		Type:        environschema.Tstring,
+		Secret:      true,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupSFTPHostKeyKey: {
+		Description: "The sftp backup target host's public key, as a line in OpenSSH known_hosts format",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupSFTPPrivateKeyKey: {
+		Description: "The SSH private key used to authenticate with the sftp backup target, in PEM format",
+		Type:        environschema.Tstring,
+		Secret:      true,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupSFTPURLKey: {
+		Description: "The location, as sftp://user@host[:port]/path, of the directory in which the sftp backup target stores archives",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupScheduleKey: {
+		Description: `The cron-like schedule, in UTC, on which backups are created automatically (e.g. "0 3 * * *")`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	BackupTargetKey: {
+		Description: `Where backup archives are stored by default: "state", "local", "s3" or "sftp"`,
+		Type:        environschema.Tstring,
+		Values:      []interface{}{BackupTargetState, BackupTargetLocal, BackupTargetS3, BackupTargetSFTP},
+		Group:       environschema.EnvironGroup,
+	},
 	"bootstrap-addresses-delay": {
 		Description: "The amount of time between refreshing the addresses in seconds. Not too frequent as we refresh addresses from the provider each time.",
 		Type:        environschema.Tint,
@@ -1701,6 +2324,31 @@ global or per instance security groups.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	ImageCacheMaxAgeKey: {
+		Description: `How long (e.g. "720h") a cached container image or LXC clone template may go unused before it is removed ("0" to keep them)`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	ImageCacheMaxSizeKey: {
+		Description: "The total size, in MiB, of the container images cached by the state servers (0 for no limit)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	AutomaticallyRetryHooksKey: {
+		Description: "Whether unit agents retry failed hooks, with exponential backoff, before leaving the unit in an error state",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
+	HookRetryMaxAttemptsKey: {
+		Description: "How many times a failed hook is retried automatically (default 5)",
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	HookRetryMaxDelayKey: {
+		Description: `The longest time (e.g. "5m") a unit agent waits between automatic retries of a failed hook`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	"image-metadata-url": {
 		Description: "The URL at which the metadata used to locate OS image ids is located",
 		Type:        environschema.Tstring,
@@ -1711,6 +2359,26 @@ global or per instance security groups.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	LogForwardBackfillKey: {
+		Description: "Whether logs recorded before forwarding was first enabled are forwarded too",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
+	LogForwardSyslogAddressKey: {
+		Description: "The host:port of a remote syslog server to forward the environment's logs to",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogForwardSyslogCACertKey: {
+		Description: "The certificate of the CA used to verify the remote syslog server, in PEM format",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogForwardSyslogTLSKey: {
+		Description: "Whether logs are forwarded to the remote syslog server over TLS",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
 	"logging-config": {
 		Description: `The configuration string to use when configuring Juju agent logging (see http://godoc.org/github.com/juju/loggo#ParseConfigurationString for details)`,
 		Type:        environschema.Tstring,
@@ -1834,6 +2502,11 @@ data of the store. (default false)`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	TraceEndpointKey: {
+		Description: "The file path, in the API server's log directory, or OTLP/HTTP collector URL to which the spans of traced API requests are exported; only used in the state server environment",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	"type": {
 		Description: "Type of environment, e.g. local, ec2",
 		Type:        environschema.Tstring,
@@ -4,6 +4,7 @@
 package config_test
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"regexp"
@@ -16,6 +17,8 @@ import (
 	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/proxy"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v5/charmrepo"
 	"gopkg.in/juju/environschema.v1"
@@ -24,6 +27,7 @@ import (
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/testing"
+	sshtesting "github.com/juju/juju/utils/ssh/testing"
 	"github.com/juju/juju/version"
 )
 
@@ -257,6 +261,237 @@ var configTests = []configTest{
 			"lxc-default-mtu": -42,
 		},
 		err: `lxc-default-mtu: expected positive integer, got -42`,
+	}, {
+		about:       "Log forwarding to syslog over TLS",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                      "my-type",
+			"name":                      "my-name",
+			"logforward-syslog-address": "syslog.example.com:6514",
+			"logforward-syslog-tls":     true,
+			"logforward-syslog-ca-cert": caCert,
+		},
+	}, {
+		about:       "Log forwarding address invalid",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                      "my-type",
+			"name":                      "my-name",
+			"logforward-syslog-address": "syslog.example.com",
+		},
+		err: `logforward-syslog-address: expected host:port, got "syslog.example.com"`,
+	}, {
+		about:       "Log forwarding CA cert invalid",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                      "my-type",
+			"name":                      "my-name",
+			"logforward-syslog-ca-cert": "rubbish",
+		},
+		err: `bad logforward-syslog-ca-cert: .*`,
+	}, {
+		about:       "Backups stored in S3 by default",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                 "my-type",
+			"name":                 "my-name",
+			"backup-target":        "s3",
+			"backup-s3-endpoint":   "http://10.0.0.1:8080",
+			"backup-s3-bucket":     "juju-backups",
+			"backup-s3-access-key": "access",
+			"backup-s3-secret-key": "secret",
+		},
+	}, {
+		about:       "Default backup target not configured",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":          "my-type",
+			"name":          "my-name",
+			"backup-target": "local",
+		},
+		err: `bad backup-target: backup target "local" requires backup-local-dir to be set`,
+	}, {
+		about:       "Backup local directory relative",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":             "my-type",
+			"name":             "my-name",
+			"backup-local-dir": "backups",
+		},
+		err: `backup-local-dir: expected absolute path, got "backups"`,
+	}, {
+		about:       "Backup SFTP URL invalid",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":            "my-type",
+			"name":            "my-name",
+			"backup-sftp-url": "sftp://backups.example.com/srv",
+		},
+		err: `bad backup-sftp-url: expected sftp://user@host\[:port\]/path, got "sftp://backups.example.com/srv"`,
+	}, {
+		about:       "Backup SFTP host key",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                 "my-type",
+			"name":                 "my-name",
+			"backup-sftp-host-key": "backups.example.com " + sshtesting.ValidKeyOne.Key,
+		},
+	}, {
+		about:       "Backup SFTP host key without hosts",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                 "my-type",
+			"name":                 "my-name",
+			"backup-sftp-host-key": sshtesting.ValidKeyOne.Key,
+		},
+		err: `bad backup-sftp-host-key: cannot parse host key: .*`,
+	}, {
+		about:       "Backup SFTP host key with marker",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                 "my-type",
+			"name":                 "my-name",
+			"backup-sftp-host-key": "@revoked " + sshtesting.ValidKeyOne.Key,
+		},
+		err: `bad backup-sftp-host-key: expected hosts, key type and key, got .*`,
+	}, {
+		about:       "Scheduled backups with retention",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                 "my-type",
+			"name":                 "my-name",
+			"backup-schedule":      "0 3 * * *",
+			"backup-retain-count":  3,
+			"backup-retain-daily":  7,
+			"backup-retain-weekly": 4,
+		},
+	}, {
+		about:       "Backup schedule invalid",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":            "my-type",
+			"name":            "my-name",
+			"backup-schedule": "0 25 * * *",
+		},
+		err: `bad backup-schedule: schedule "0 25 \* \* \*": hour "25" out of range 0-23`,
+	}, {
+		about:       "Backup retention negative",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"backup-retain-daily": -1,
+		},
+		err: `backup-retain-daily: expected non-negative integer, got -1`,
+	}, {
+		about:       "Backup encryption key invalid",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"backup-encryption-key": "not a key",
+		},
+		err: `bad backup-encryption-key: .*`,
+	}, {
+		about:       "API limits",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                   "my-type",
+			"name":                   "my-name",
+			"api-request-rate-user":  10,
+			"api-request-rate-agent": 50,
+			"api-max-watchers":       100,
+		},
+	}, {
+		about:       "API request rate negative",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                  "my-type",
+			"name":                  "my-name",
+			"api-request-rate-user": -1,
+		},
+		err: `api-request-rate-user: expected non-negative integer, got -1`,
+	}, {
+		about:       "Image cache limits",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                 "my-type",
+			"name":                 "my-name",
+			"image-cache-max-age":  "168h",
+			"image-cache-max-size": 4096,
+		},
+	}, {
+		about:       "Image cache max age invalid",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                "my-type",
+			"name":                "my-name",
+			"image-cache-max-age": "a week",
+		},
+		err: `bad image-cache-max-age: .*`,
+	}, {
+		about:       "Image cache max size negative",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                 "my-type",
+			"name":                 "my-name",
+			"image-cache-max-size": -1,
+		},
+		err: `image-cache-max-size: expected non-negative integer, got -1`,
+	}, {
+		about:       "Automatic hook retries",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                      "my-type",
+			"name":                      "my-name",
+			"automatically-retry-hooks": true,
+			"hook-retry-max-attempts":   10,
+			"hook-retry-max-delay":      "10m",
+		},
+	}, {
+		about:       "Hook retry max attempts negative",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                    "my-type",
+			"name":                    "my-name",
+			"hook-retry-max-attempts": -1,
+		},
+		err: `hook-retry-max-attempts: expected non-negative integer, got -1`,
+	}, {
+		about:       "Hook retry max delay invalid",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                 "my-type",
+			"name":                 "my-name",
+			"hook-retry-max-delay": "a while",
+		},
+		err: `bad hook-retry-max-delay: .*`,
+	}, {
+		about:       "Hook retry max delay zero",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":                 "my-type",
+			"name":                 "my-name",
+			"hook-retry-max-delay": "0",
+		},
+		err: `hook-retry-max-delay: expected positive duration, got 0`,
+	}, {
+		about:       "Trace endpoint",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"trace-endpoint": "http://localhost:4318/v1/traces",
+		},
+	}, {
+		about:       "Invalid trace endpoint",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"trace-endpoint": "traces.json",
+		},
+		err: `bad trace-endpoint: invalid trace endpoint "traces.json": file path must be absolute`,
 	}, {
 		about:       "CA cert & key from path",
 		useDefaults: config.UseDefaults,
@@ -1767,6 +2002,162 @@ func (s *ConfigSuite) TestProxyValuesNotSet(c *gc.C) {
 	c.Assert(config.NoProxy(), gc.Equals, "")
 }
 
+func (s *ConfigSuite) TestLogForwardSyslogValues(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{
+		"logforward-syslog-address": "10.0.0.1:6514",
+		"logforward-syslog-tls":     true,
+		"logforward-syslog-ca-cert": caCert,
+		"logforward-backfill":       true,
+	})
+	addr, ok := cfg.LogForwardSyslogAddress()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(addr, gc.Equals, "10.0.0.1:6514")
+	c.Assert(cfg.LogForwardSyslogTLS(), jc.IsTrue)
+	caCertPEM, ok := cfg.LogForwardSyslogCACert()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(caCertPEM, gc.Equals, caCert)
+	c.Assert(cfg.LogForwardBackfill(), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestLogForwardSyslogValuesNotSet(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{})
+	_, ok := cfg.LogForwardSyslogAddress()
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(cfg.LogForwardSyslogTLS(), jc.IsFalse)
+	_, ok = cfg.LogForwardSyslogCACert()
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(cfg.LogForwardBackfill(), jc.IsFalse)
+}
+
+func (s *ConfigSuite) TestBackupValues(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{
+		"backup-target":           "sftp",
+		"backup-local-dir":        "/srv/backups",
+		"backup-s3-bucket":        "juju-backups",
+		"backup-s3-access-key":    "access",
+		"backup-s3-secret-key":    "secret",
+		"backup-sftp-url":         "sftp://juju@backups.example.com/srv/backups",
+		"backup-sftp-private-key": "private-key",
+		"backup-sftp-host-key":    "backups.example.com " + sshtesting.ValidKeyOne.Key,
+	})
+	c.Assert(cfg.BackupTarget(), gc.Equals, "sftp")
+	dir, ok := cfg.BackupLocalDir()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(dir, gc.Equals, "/srv/backups")
+	_, ok = cfg.BackupS3Endpoint()
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(cfg.BackupS3Region(), gc.Equals, "us-east-1")
+	bucket, ok := cfg.BackupS3Bucket()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(bucket, gc.Equals, "juju-backups")
+	accessKey, secretKey := cfg.BackupS3Credentials()
+	c.Assert(accessKey, gc.Equals, "access")
+	c.Assert(secretKey, gc.Equals, "secret")
+	sftpURL, ok := cfg.BackupSFTPURL()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(sftpURL, gc.Equals, "sftp://juju@backups.example.com/srv/backups")
+	key, ok := cfg.BackupSFTPPrivateKey()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(key, gc.Equals, "private-key")
+	hostKey, ok := cfg.BackupSFTPHostKey()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(hostKey, gc.Equals, "backups.example.com "+sshtesting.ValidKeyOne.Key)
+
+	for _, target := range []string{"state", "local", "s3", "sftp"} {
+		c.Check(cfg.CheckBackupTarget(target), jc.ErrorIsNil)
+	}
+	c.Check(cfg.CheckBackupTarget("tape"), gc.ErrorMatches, `backup target "tape" not valid`)
+}
+
+func (s *ConfigSuite) TestBackupValuesNotSet(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.BackupTarget(), gc.Equals, "state")
+	_, ok := cfg.BackupLocalDir()
+	c.Assert(ok, jc.IsFalse)
+	_, ok = cfg.BackupS3Bucket()
+	c.Assert(ok, jc.IsFalse)
+	_, ok = cfg.BackupSFTPURL()
+	c.Assert(ok, jc.IsFalse)
+	_, ok = cfg.BackupSFTPHostKey()
+	c.Assert(ok, jc.IsFalse)
+	c.Check(cfg.CheckBackupTarget("state"), jc.ErrorIsNil)
+	c.Check(cfg.CheckBackupTarget("s3"), gc.ErrorMatches, `backup target "s3" requires backup-s3-bucket to be set`)
+}
+
+func (s *ConfigSuite) TestBackupCredentialsSecret(c *gc.C) {
+	for _, key := range []string{"backup-s3-secret-key", "backup-sftp-private-key"} {
+		c.Check(config.ConfigSchema[key].Secret, jc.IsTrue, gc.Commentf("%s", key))
+	}
+}
+
+func (s *ConfigSuite) TestBackupScheduleValues(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{
+		"backup-schedule":      "@daily",
+		"backup-retain-count":  3,
+		"backup-retain-weekly": 4,
+	})
+	schedule, ok := cfg.BackupSchedule()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(schedule, gc.Equals, "@daily")
+	count, daily, weekly := cfg.BackupRetention()
+	c.Assert(count, gc.Equals, 3)
+	c.Assert(daily, gc.Equals, 0)
+	c.Assert(weekly, gc.Equals, 4)
+}
+
+func (s *ConfigSuite) TestBackupScheduleValuesNotSet(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{})
+	_, ok := cfg.BackupSchedule()
+	c.Assert(ok, jc.IsFalse)
+	count, daily, weekly := cfg.BackupRetention()
+	c.Assert(count, gc.Equals, 0)
+	c.Assert(daily, gc.Equals, 0)
+	c.Assert(weekly, gc.Equals, 0)
+}
+
+func (s *ConfigSuite) TestBackupEncryptionKey(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{})
+	_, ok := cfg.BackupEncryptionKey()
+	c.Assert(ok, jc.IsFalse)
+
+	entity, err := openpgp.NewEntity("Juju Backups", "", "backups@example.com", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entity.Serialize(w), jc.ErrorIsNil)
+	c.Assert(w.Close(), jc.ErrorIsNil)
+
+	cfg = newTestConfig(c, testing.Attrs{"backup-encryption-key": buf.String()})
+	key, ok := cfg.BackupEncryptionKey()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(key, gc.Equals, buf.String())
+}
+
+func (s *ConfigSuite) TestParseBackupSFTPURL(c *gc.C) {
+	u, err := config.ParseBackupSFTPURL("sftp://juju@backups.example.com/srv/backups")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u.User.Username(), gc.Equals, "juju")
+	c.Assert(u.Host, gc.Equals, "backups.example.com:22")
+	c.Assert(u.Path, gc.Equals, "/srv/backups")
+
+	u, err = config.ParseBackupSFTPURL("sftp://juju@10.0.0.1:2222/backups")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(u.Host, gc.Equals, "10.0.0.1:2222")
+
+	for _, sftpURL := range []string{"ssh://juju@host/backups", "sftp://juju@host", "sftp:///backups"} {
+		_, err := config.ParseBackupSFTPURL(sftpURL)
+		c.Check(err, gc.ErrorMatches, "expected sftp://user@host\\[:port\\]/path, got .*")
+	}
+}
+
 func (s *ConfigSuite) TestProxyConfigMap(c *gc.C) {
 	s.addJujuFiles(c)
 	cfg := newTestConfig(c, testing.Attrs{})
@@ -2057,3 +2448,67 @@ var invalidCACert = `
 MIIBOgIBAAJAZabKgKInuOxj5vDWLwHHQtK3/45KB+32D15w94Nt83BmuGxo90lw
 -----END CERTIFICATE-----
 `[1:]
+
+func (s *ConfigSuite) TestAPILimits(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{})
+	user, agent := cfg.APIRequestRates()
+	c.Assert(user, gc.Equals, 0)
+	c.Assert(agent, gc.Equals, 0)
+	c.Assert(cfg.APIMaxWatchers(), gc.Equals, 0)
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"api-request-rate-user":  10,
+		"api-request-rate-agent": 50,
+		"api-max-watchers":       100,
+	})
+	user, agent = cfg.APIRequestRates()
+	c.Assert(user, gc.Equals, 10)
+	c.Assert(agent, gc.Equals, 50)
+	c.Assert(cfg.APIMaxWatchers(), gc.Equals, 100)
+}
+
+func (s *ConfigSuite) TestTraceEndpoint(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.TraceEndpoint(), gc.Equals, "")
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"trace-endpoint": "/var/log/juju/traces.json",
+	})
+	c.Assert(cfg.TraceEndpoint(), gc.Equals, "/var/log/juju/traces.json")
+}
+
+func (s *ConfigSuite) TestImageCacheLimits(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.ImageCacheMaxAge(), gc.Equals, config.DefaultImageCacheMaxAge)
+	c.Assert(cfg.ImageCacheMaxSize(), gc.Equals, 0)
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"image-cache-max-age":  "168h",
+		"image-cache-max-size": 4096,
+	})
+	c.Assert(cfg.ImageCacheMaxAge(), gc.Equals, 168*time.Hour)
+	c.Assert(cfg.ImageCacheMaxSize(), gc.Equals, 4096)
+
+	cfg = newTestConfig(c, testing.Attrs{"image-cache-max-age": "0"})
+	c.Assert(cfg.ImageCacheMaxAge(), gc.Equals, time.Duration(0))
+}
+
+func (s *ConfigSuite) TestHookRetries(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.AutomaticallyRetryHooks(), jc.IsFalse)
+	c.Assert(cfg.HookRetryMaxAttempts(), gc.Equals, config.DefaultHookRetryMaxAttempts)
+	c.Assert(cfg.HookRetryMaxDelay(), gc.Equals, config.DefaultHookRetryMaxDelay)
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"automatically-retry-hooks": true,
+		"hook-retry-max-attempts":   10,
+		"hook-retry-max-delay":      "10m",
+	})
+	c.Assert(cfg.AutomaticallyRetryHooks(), jc.IsTrue)
+	c.Assert(cfg.HookRetryMaxAttempts(), gc.Equals, 10)
+	c.Assert(cfg.HookRetryMaxDelay(), gc.Equals, 10*time.Minute)
+}
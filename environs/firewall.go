@@ -0,0 +1,49 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"github.com/juju/juju/network"
+)
+
+// IngressRules defines the methods of environments which can restrict
+// the source addresses from which opened ports may be reached. The
+// instances of such environments implement instance.IngressRules.
+type IngressRules interface {
+	// OpenIngressRules opens the given rules for the whole
+	// environment. Must only be used if the environment was setup
+	// with the FwGlobal firewall mode.
+	OpenIngressRules(rules []network.IngressRule) error
+
+	// CloseIngressRules closes the given rules for the whole
+	// environment. Must only be used if the environment was setup
+	// with the FwGlobal firewall mode.
+	CloseIngressRules(rules []network.IngressRule) error
+
+	// IngressRules returns the rules opened for the whole
+	// environment, as sorted by network.SortIngressRules(). Must only
+	// be used if the environment was setup with the FwGlobal firewall
+	// mode.
+	IngressRules() ([]network.IngressRule, error)
+}
+
+// IngressRulesEnviron combines the standard Environ interface with the
+// functionality for restricting the sources of opened ports.
+type IngressRulesEnviron interface {
+	// Environ represents a juju environment.
+	Environ
+
+	// IngressRules defines the methods of environments which can
+	// restrict the sources of opened ports.
+	IngressRules
+}
+
+// SupportsIngressRules is a convenience helper to check if an
+// environment can restrict the sources from which opened ports may be
+// reached. It returns an interface containing Environ and IngressRules
+// in this case.
+func SupportsIngressRules(environ Environ) (IngressRulesEnviron, bool) {
+	ie, ok := environ.(IngressRulesEnviron)
+	return ie, ok
+}
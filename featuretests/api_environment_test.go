@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/juju"
 	jujunames "github.com/juju/juju/juju/names"
 	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
 	"github.com/juju/juju/version"
@@ -42,7 +43,7 @@ func (s *apiEnvironmentSuite) TearDownTest(c *gc.C) {
 func (s *apiEnvironmentSuite) TestEnvironmentShare(c *gc.C) {
 	user := names.NewUserTag("foo@ubuntuone")
 
-	err := s.client.ShareEnvironment(user)
+	err := s.client.ShareEnvironment("", user)
 	c.Assert(err, jc.ErrorIsNil)
 
 	envUser, err := s.State.EnvironmentUser(user)
@@ -52,10 +53,22 @@ func (s *apiEnvironmentSuite) TestEnvironmentShare(c *gc.C) {
 	c.Assert(envUser.LastConnection(), gc.IsNil)
 }
 
+func (s *apiEnvironmentSuite) TestEnvironmentShareReadOnly(c *gc.C) {
+	user := names.NewUserTag("foo@ubuntuone")
+
+	err := s.client.ShareEnvironment(params.EnvironmentReadAccess, user)
+	c.Assert(err, jc.ErrorIsNil)
+
+	envUser, err := s.State.EnvironmentUser(user)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.UserName(), gc.Equals, user.Username())
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+}
+
 func (s *apiEnvironmentSuite) TestEnvironmentUnshare(c *gc.C) {
 	// Firt share an environment with a user.
 	user := names.NewUserTag("foo@ubuntuone")
-	err := s.client.ShareEnvironment(user)
+	err := s.client.ShareEnvironment("", user)
 	c.Assert(err, jc.ErrorIsNil)
 
 	envUser, err := s.State.EnvironmentUser(user)
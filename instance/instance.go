@@ -49,6 +49,23 @@ type Instance interface {
 	Ports(machineId string) ([]network.PortRange, error)
 }
 
+// IngressRules defines the methods of instances which can restrict the
+// source addresses from which opened ports may be reached.
+type IngressRules interface {
+	// OpenIngressRules opens the given rules on the instance, which
+	// should have been started with the given machine id.
+	OpenIngressRules(machineId string, rules []network.IngressRule) error
+
+	// CloseIngressRules closes the given rules on the instance, which
+	// should have been started with the given machine id.
+	CloseIngressRules(machineId string, rules []network.IngressRule) error
+
+	// IngressRules returns the rules opened on the instance, which
+	// should have been started with the given machine id. The rules
+	// are returned as sorted by network.SortIngressRules().
+	IngressRules(machineId string) ([]network.IngressRule, error)
+}
+
 // HardwareCharacteristics represents the characteristics of the instance (if known).
 // Attributes that are nil are unknown or not supported.
 type HardwareCharacteristics struct {
@@ -0,0 +1,356 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package instrumentation holds counters, gauges and histograms
+// describing a running juju agent, and writes them out in the
+// Prometheus text exposition format.
+package instrumentation
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the HTTP content type of the text written by
+// Registry.WriteText.
+const ContentType = "text/plain; version=0.0.4"
+
+// DefaultBuckets holds the upper bounds, in seconds, of the buckets
+// used for histograms of request latencies.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Default is the registry holding the metrics of the agent.
+var Default = NewRegistry()
+
+// Registry holds a set of metrics, each with a unique name.
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+// NewRegistry returns a new, empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		families: make(map[string]*family),
+	}
+}
+
+// NewCounter adds a counter with the given name, help text and label
+// names to the registry, and returns it. It panics if the registry
+// already holds a metric with the same name.
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	return &Counter{r.add(name, help, "counter", labels, nil)}
+}
+
+// NewGauge adds a gauge with the given name, help text and label names
+// to the registry, and returns it. It panics if the registry already
+// holds a metric with the same name.
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{r.add(name, help, "gauge", labels, nil)}
+}
+
+// NewHistogram adds a histogram with the given name, help text, bucket
+// upper bounds and label names to the registry, and returns it. The
+// bounds must be in increasing order. It panics if the registry
+// already holds a metric with the same name.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if !sort.Float64sAreSorted(buckets) {
+		panic(fmt.Sprintf("histogram %q buckets not in increasing order", name))
+	}
+	return &Histogram{r.add(name, help, "histogram", labels, buckets)}
+}
+
+func (r *Registry) add(name, help, kind string, labels []string, buckets []float64) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.families[name]; ok {
+		panic(fmt.Sprintf("metric %q already registered", name))
+	}
+	f := &family{
+		name:    name,
+		help:    help,
+		kind:    kind,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*series),
+	}
+	if len(labels) == 0 && kind != "histogram" {
+		// Unlabelled counters and gauges are always reported, even
+		// when they have never been touched.
+		f.get(nil)
+	}
+	r.families[name] = f
+	return f
+}
+
+// WriteText writes all the metrics in the registry to w, in the
+// Prometheus text exposition format. Metrics are written in order of
+// name.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	families := make([]*family, len(names))
+	for i, name := range names {
+		families[i] = r.families[name]
+	}
+	r.mu.Unlock()
+
+	for _, f := range families {
+		if err := f.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewCounter adds a counter to the Default registry.
+func NewCounter(name, help string, labels ...string) *Counter {
+	return Default.NewCounter(name, help, labels...)
+}
+
+// NewGauge adds a gauge to the Default registry.
+func NewGauge(name, help string, labels ...string) *Gauge {
+	return Default.NewGauge(name, help, labels...)
+}
+
+// NewHistogram adds a histogram to the Default registry.
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return Default.NewHistogram(name, help, buckets, labels...)
+}
+
+// Counter is a metric whose values only ever increase. All its methods
+// take the values of its labels, in the order in which the label names
+// were given when it was created.
+type Counter struct {
+	f *family
+}
+
+// Inc adds one to the counter.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds v, which must not be negative, to the counter.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		panic(fmt.Sprintf("counter %q cannot decrease", c.f.name))
+	}
+	c.f.mu.Lock()
+	defer c.f.mu.Unlock()
+	c.f.get(labelValues).value += v
+}
+
+// Value returns the value of the counter.
+func (c *Counter) Value(labelValues ...string) float64 {
+	return c.f.value(labelValues)
+}
+
+// Gauge is a metric whose values may go up and down. All its methods
+// take the values of its labels, in the order in which the label names
+// were given when it was created.
+type Gauge struct {
+	f *family
+}
+
+// Set sets the gauge to v.
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.f.mu.Lock()
+	defer g.f.mu.Unlock()
+	g.f.get(labelValues).value = v
+}
+
+// Add adds v to the gauge.
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	g.f.mu.Lock()
+	defer g.f.mu.Unlock()
+	g.f.get(labelValues).value += v
+}
+
+// Inc adds one to the gauge.
+func (g *Gauge) Inc(labelValues ...string) {
+	g.Add(1, labelValues...)
+}
+
+// Dec subtracts one from the gauge.
+func (g *Gauge) Dec(labelValues ...string) {
+	g.Add(-1, labelValues...)
+}
+
+// Value returns the value of the gauge.
+func (g *Gauge) Value(labelValues ...string) float64 {
+	return g.f.value(labelValues)
+}
+
+// Histogram is a metric which counts observations, such as request
+// latencies, in buckets. All its methods take the values of its
+// labels, in the order in which the label names were given when it was
+// created.
+type Histogram struct {
+	f *family
+}
+
+// Observe records a single observation of v.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	h.f.mu.Lock()
+	defer h.f.mu.Unlock()
+	s := h.f.get(labelValues)
+	for i, bound := range h.f.buckets {
+		if v <= bound {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += v
+}
+
+// Count returns the number of observations made.
+func (h *Histogram) Count(labelValues ...string) uint64 {
+	h.f.mu.Lock()
+	defer h.f.mu.Unlock()
+	if s, ok := h.f.series[seriesKey(labelValues)]; ok {
+		return s.count
+	}
+	return 0
+}
+
+// family holds all the series of a metric, one for each distinct set
+// of label values.
+type family struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// series holds the value of a metric for one set of label values. The
+// counts, sum and count fields are only used by histograms; counts
+// holds the cumulative count of observations in each bucket.
+type series struct {
+	labelValues []string
+	value       float64
+	counts      []uint64
+	sum         float64
+	count       uint64
+}
+
+func seriesKey(labelValues []string) string {
+	return strings.Join(labelValues, "\xff")
+}
+
+// get returns the series with the given label values, creating it if
+// necessary. The family's mutex must be held.
+func (f *family) get(labelValues []string) *series {
+	if len(labelValues) != len(f.labels) {
+		panic(fmt.Sprintf("metric %q has %d labels, got %d values", f.name, len(f.labels), len(labelValues)))
+	}
+	key := seriesKey(labelValues)
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{
+			labelValues: append([]string(nil), labelValues...),
+			counts:      make([]uint64, len(f.buckets)),
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+func (f *family) value(labelValues []string) float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if s, ok := f.series[seriesKey(labelValues)]; ok {
+		return s.value
+	}
+	return 0
+}
+
+func (f *family) write(w io.Writer) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf []byte
+	buf = append(buf, "# HELP "+f.name+" "+escapeHelp(f.help)+"\n"...)
+	buf = append(buf, "# TYPE "+f.name+" "+f.kind+"\n"...)
+	for _, key := range keys {
+		s := f.series[key]
+		if f.kind != "histogram" {
+			buf = appendSample(buf, f.name, f.labels, s.labelValues, s.value)
+			continue
+		}
+		// Each bucket is labelled with its upper bound.
+		n := len(f.labels)
+		labels := append(f.labels[:n:n], "le")
+		for i, bound := range f.buckets {
+			values := append(s.labelValues[:n:n], formatFloat(bound))
+			buf = appendSample(buf, f.name+"_bucket", labels, values, float64(s.counts[i]))
+		}
+		values := append(s.labelValues[:n:n], "+Inf")
+		buf = appendSample(buf, f.name+"_bucket", labels, values, float64(s.count))
+		buf = appendSample(buf, f.name+"_sum", f.labels, s.labelValues, s.sum)
+		buf = appendSample(buf, f.name+"_count", f.labels, s.labelValues, float64(s.count))
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// appendSample appends a single sample line to buf.
+func appendSample(buf []byte, name string, labels, labelValues []string, v float64) []byte {
+	buf = append(buf, name...)
+	if len(labels) > 0 {
+		buf = append(buf, '{')
+		for i, label := range labels {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = append(buf, label+`="`+escapeLabelValue(labelValues[i])+`"`...)
+		}
+		buf = append(buf, '}')
+	}
+	buf = append(buf, ' ')
+	buf = append(buf, formatFloat(v)...)
+	return append(buf, '\n')
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
@@ -0,0 +1,124 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instrumentation_test
+
+import (
+	"bytes"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instrumentation"
+)
+
+type instrumentationSuite struct {
+	registry *instrumentation.Registry
+}
+
+var _ = gc.Suite(&instrumentationSuite{})
+
+func (s *instrumentationSuite) SetUpTest(c *gc.C) {
+	s.registry = instrumentation.NewRegistry()
+}
+
+func (s *instrumentationSuite) assertText(c *gc.C, expected string) {
+	var buf bytes.Buffer
+	err := s.registry.WriteText(&buf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, expected)
+}
+
+func (s *instrumentationSuite) TestCounter(c *gc.C) {
+	counter := s.registry.NewCounter("requests_total", "Requests served.", "facade", "method")
+	counter.Inc("Client", "FullStatus")
+	counter.Add(2, "Client", "FullStatus")
+	counter.Inc("Uniter", "Watch")
+	c.Assert(counter.Value("Client", "FullStatus"), gc.Equals, float64(3))
+	c.Assert(counter.Value("Client", "Unknown"), gc.Equals, float64(0))
+
+	s.assertText(c, `# HELP requests_total Requests served.
+# TYPE requests_total counter
+requests_total{facade="Client",method="FullStatus"} 3
+requests_total{facade="Uniter",method="Watch"} 1
+`)
+}
+
+func (s *instrumentationSuite) TestCounterCannotDecrease(c *gc.C) {
+	counter := s.registry.NewCounter("retries_total", "Retries.")
+	c.Assert(func() { counter.Add(-1) }, gc.PanicMatches, `counter "retries_total" cannot decrease`)
+}
+
+func (s *instrumentationSuite) TestUnlabelledAlwaysWritten(c *gc.C) {
+	s.registry.NewCounter("retries_total", "Retries.")
+	s.registry.NewGauge("watchers", "Watchers.")
+	s.assertText(c, `# HELP retries_total Retries.
+# TYPE retries_total counter
+retries_total 0
+# HELP watchers Watchers.
+# TYPE watchers gauge
+watchers 0
+`)
+}
+
+func (s *instrumentationSuite) TestGauge(c *gc.C) {
+	gauge := s.registry.NewGauge("connections", "Open connections.", "kind")
+	gauge.Inc("machine")
+	gauge.Inc("machine")
+	gauge.Dec("machine")
+	gauge.Set(5, "user")
+	gauge.Add(-2, "user")
+	c.Assert(gauge.Value("machine"), gc.Equals, float64(1))
+	c.Assert(gauge.Value("user"), gc.Equals, float64(3))
+
+	s.assertText(c, `# HELP connections Open connections.
+# TYPE connections gauge
+connections{kind="machine"} 1
+connections{kind="user"} 3
+`)
+}
+
+func (s *instrumentationSuite) TestHistogram(c *gc.C) {
+	histogram := s.registry.NewHistogram("duration_seconds", "Time taken.", []float64{0.1, 1}, "method")
+	histogram.Observe(0.05, "Watch")
+	histogram.Observe(0.5, "Watch")
+	histogram.Observe(2, "Watch")
+	c.Assert(histogram.Count("Watch"), gc.Equals, uint64(3))
+	c.Assert(histogram.Count("Next"), gc.Equals, uint64(0))
+
+	s.assertText(c, `# HELP duration_seconds Time taken.
+# TYPE duration_seconds histogram
+duration_seconds_bucket{method="Watch",le="0.1"} 1
+duration_seconds_bucket{method="Watch",le="1"} 2
+duration_seconds_bucket{method="Watch",le="+Inf"} 3
+duration_seconds_sum{method="Watch"} 2.55
+duration_seconds_count{method="Watch"} 3
+`)
+}
+
+func (s *instrumentationSuite) TestHistogramBucketsSorted(c *gc.C) {
+	c.Assert(func() {
+		s.registry.NewHistogram("duration_seconds", "Time taken.", []float64{1, 0.1})
+	}, gc.PanicMatches, `histogram "duration_seconds" buckets not in increasing order`)
+}
+
+func (s *instrumentationSuite) TestAlreadyRegistered(c *gc.C) {
+	s.registry.NewCounter("requests_total", "Requests served.")
+	c.Assert(func() {
+		s.registry.NewGauge("requests_total", "Requests served.")
+	}, gc.PanicMatches, `metric "requests_total" already registered`)
+}
+
+func (s *instrumentationSuite) TestWrongLabelCount(c *gc.C) {
+	counter := s.registry.NewCounter("requests_total", "Requests served.", "facade")
+	c.Assert(func() { counter.Inc() }, gc.PanicMatches, `metric "requests_total" has 1 labels, got 0 values`)
+}
+
+func (s *instrumentationSuite) TestEscaping(c *gc.C) {
+	counter := s.registry.NewCounter("errors_total", "Errors,\nby \\ message.", "message")
+	counter.Inc("a \"quoted\"\nvalue")
+	s.assertText(c, `# HELP errors_total Errors,\nby \\ message.
+# TYPE errors_total counter
+errors_total{message="a \"quoted\"\nvalue"} 1
+`)
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/configstore"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/tracing"
 )
 
 var logger = loggo.GetLogger("juju.api")
@@ -28,6 +29,24 @@ var (
 	providerConnectDelay = 2 * time.Second
 )
 
+// traceSpan, if non-nil, is the span on whose behalf the API
+// connections opened by this package trace their calls.
+var traceSpan *tracing.Span
+
+// SetTraceSpan sets the span on whose behalf the API connections
+// subsequently opened by NewAPIFromName and NewAPIClientFromName
+// trace their calls. If span is nil, calls are not traced.
+func SetTraceSpan(span *tracing.Span) {
+	traceSpan = span
+}
+
+// dialOpts returns the options used to open API connections.
+func dialOpts() api.DialOpts {
+	opts := api.DefaultDialOpts()
+	opts.TraceSpan = traceSpan
+	return opts
+}
+
 // apiState provides a subset of api.State's public
 // interface, defined here so it can be mocked.
 type apiState interface {
@@ -290,7 +309,7 @@ func apiInfoConnect(info configstore.EnvironInfo, apiOpen apiOpenFunc, stop <-ch
 		Password:   info.APICredentials().Password,
 		EnvironTag: environTag,
 	}
-	st, err := apiOpen(apiInfo, api.DefaultDialOpts())
+	st, err := apiOpen(apiInfo, dialOpts())
 	if err != nil {
 		return nil, &infoConnectError{err}
 	}
@@ -317,7 +336,7 @@ func apiConfigConnect(cfg *config.Config, apiOpen apiOpenFunc, stop <-chan struc
 		return nil, err
 	}
 
-	st, err := apiOpen(apiInfo, api.DefaultDialOpts())
+	st, err := apiOpen(apiInfo, dialOpts())
 	// TODO(rog): handle errUnauthorized when the API handles passwords.
 	if err != nil {
 		return nil, err
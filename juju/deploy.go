@@ -90,7 +90,7 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 		return nil, err
 	}
 	if len(settings) > 0 {
-		if err := service.UpdateConfigSettings(settings); err != nil {
+		if err := service.UpdateConfigSettingsByUser(settings, args.ServiceOwner); err != nil {
 			return nil, err
 		}
 	}
@@ -38,6 +38,11 @@ const (
 	// This includes args and output.
 	// Default is 1.
 	JujuCLIVersion = "JUJU_CLI_VERSION"
+
+	// JujuTraceEndpointEnvKey is the env var which, if set, causes
+	// the juju command to trace the API requests it makes, exporting
+	// the spans to the file path or OTLP/HTTP collector URL it holds.
+	JujuTraceEndpointEnvKey = "JUJU_TRACE_ENDPOINT"
 )
 
 // FeatureFlags returns a map that can be merged with os.Environ.
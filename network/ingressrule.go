@@ -0,0 +1,172 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// AnyCIDR is the CIDR matching every IPv4 address. Ports opened
+// without a source restriction are opened to AnyCIDR.
+const AnyCIDR = "0.0.0.0/0"
+
+// IngressRule represents a port range opened to traffic from the given
+// source CIDRs.
+type IngressRule struct {
+	PortRange
+
+	// SourceCIDRs holds the sorted CIDRs from which the port range may
+	// be reached.
+	SourceCIDRs []string
+}
+
+// NewIngressRule returns a rule opening the port range to the given
+// source CIDRs, or to AnyCIDR if none are given. The CIDRs are
+// normalised as by NormalizeCIDRs.
+func NewIngressRule(portRange PortRange, sourceCIDRs ...string) (IngressRule, error) {
+	cidrs, err := NormalizeCIDRs(sourceCIDRs)
+	if err != nil {
+		return IngressRule{}, errors.Trace(err)
+	}
+	if len(cidrs) == 0 {
+		cidrs = []string{AnyCIDR}
+	}
+	return IngressRule{PortRange: portRange, SourceCIDRs: cidrs}, nil
+}
+
+// MustNewIngressRule returns a rule as NewIngressRule does, but panics
+// if the CIDRs are invalid.
+func MustNewIngressRule(portRange PortRange, sourceCIDRs ...string) IngressRule {
+	rule, err := NewIngressRule(portRange, sourceCIDRs...)
+	if err != nil {
+		panic(err)
+	}
+	return rule
+}
+
+// IngressRulesForPorts returns rules opening each of the given port
+// ranges to AnyCIDR.
+func IngressRulesForPorts(portRanges []PortRange) []IngressRule {
+	rules := make([]IngressRule, len(portRanges))
+	for i, portRange := range portRanges {
+		rules[i] = IngressRule{
+			PortRange:   portRange,
+			SourceCIDRs: []string{AnyCIDR},
+		}
+	}
+	return rules
+}
+
+// Validate returns an error if the rule's port range or any of its
+// source CIDRs are invalid.
+func (r IngressRule) Validate() error {
+	if err := r.PortRange.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if len(r.SourceCIDRs) == 0 {
+		return errors.Errorf("no source CIDRs for port range %v", r.PortRange)
+	}
+	return ValidateCIDRs(r.SourceCIDRs)
+}
+
+// IsAnySource returns whether the rule opens its port range to every
+// address.
+func (r IngressRule) IsAnySource() bool {
+	for _, cidr := range r.SourceCIDRs {
+		if cidr == AnyCIDR {
+			return true
+		}
+	}
+	return false
+}
+
+func (r IngressRule) String() string {
+	if len(r.SourceCIDRs) == 0 || r.IsAnySource() {
+		return r.PortRange.String()
+	}
+	return fmt.Sprintf("%v from %s", r.PortRange, strings.Join(r.SourceCIDRs, ","))
+}
+
+func (r IngressRule) GoString() string {
+	return r.String()
+}
+
+type ingressRuleSlice []IngressRule
+
+func (s ingressRuleSlice) Len() int      { return len(s) }
+func (s ingressRuleSlice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s ingressRuleSlice) Less(i, j int) bool {
+	if s[i].PortRange != s[j].PortRange {
+		return portRangeLess(s[i].PortRange, s[j].PortRange)
+	}
+	return strings.Join(s[i].SourceCIDRs, ",") < strings.Join(s[j].SourceCIDRs, ",")
+}
+
+// SortIngressRules sorts the given rules by port range, as
+// SortPortRanges does, then by source CIDRs.
+func SortIngressRules(rules []IngressRule) {
+	sort.Sort(ingressRuleSlice(rules))
+}
+
+// MergeIngressRules returns the given rules with the source CIDRs of
+// those with the same port range merged into a single rule, sorted as
+// by SortIngressRules.
+func MergeIngressRules(rules []IngressRule) []IngressRule {
+	cidrs := make(map[PortRange]map[string]bool)
+	for _, rule := range rules {
+		if cidrs[rule.PortRange] == nil {
+			cidrs[rule.PortRange] = make(map[string]bool)
+		}
+		for _, cidr := range rule.SourceCIDRs {
+			cidrs[rule.PortRange][cidr] = true
+		}
+	}
+	var merged []IngressRule
+	for portRange, set := range cidrs {
+		rule := IngressRule{PortRange: portRange}
+		for cidr := range set {
+			rule.SourceCIDRs = append(rule.SourceCIDRs, cidr)
+		}
+		sort.Strings(rule.SourceCIDRs)
+		merged = append(merged, rule)
+	}
+	SortIngressRules(merged)
+	return merged
+}
+
+// ValidateCIDRs returns an error if any of the given strings is not an
+// IPv4 CIDR, such as "10.0.0.0/8".
+func ValidateCIDRs(cidrs []string) error {
+	_, err := NormalizeCIDRs(cidrs)
+	return err
+}
+
+// NormalizeCIDRs parses the given IPv4 CIDRs and returns them in their
+// canonical form, sorted and without duplicates, so that "10.1.2.3/8"
+// becomes "10.0.0.0/8".
+func NormalizeCIDRs(cidrs []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var result []string
+	for _, cidr := range cidrs {
+		ip, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, errors.Errorf("invalid CIDR %q", cidr)
+		}
+		if ip.To4() == nil {
+			return nil, errors.Errorf("invalid CIDR %q: only IPv4 is supported", cidr)
+		}
+		normalized := ipNet.String()
+		if !seen[normalized] {
+			seen[normalized] = true
+			result = append(result, normalized)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type IngressRuleSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&IngressRuleSuite{})
+
+func (*IngressRuleSuite) TestNewIngressRule(c *gc.C) {
+	portRange := network.MustParsePortRange("80/tcp")
+	rule, err := network.NewIngressRule(portRange)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rule.SourceCIDRs, jc.DeepEquals, []string{network.AnyCIDR})
+	c.Assert(rule.IsAnySource(), jc.IsTrue)
+	c.Assert(rule.String(), gc.Equals, "80/tcp")
+
+	rule, err = network.NewIngressRule(portRange, "192.168.1.0/24", "10.1.2.3/8", "10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rule.SourceCIDRs, jc.DeepEquals, []string{"10.0.0.0/8", "192.168.1.0/24"})
+	c.Assert(rule.IsAnySource(), jc.IsFalse)
+	c.Assert(rule.String(), gc.Equals, "80/tcp from 10.0.0.0/8,192.168.1.0/24")
+	c.Assert(rule.Validate(), jc.ErrorIsNil)
+}
+
+func (*IngressRuleSuite) TestNewIngressRuleInvalidCIDR(c *gc.C) {
+	portRange := network.MustParsePortRange("80/tcp")
+	_, err := network.NewIngressRule(portRange, "10.0.0.0")
+	c.Assert(err, gc.ErrorMatches, `invalid CIDR "10.0.0.0"`)
+	_, err = network.NewIngressRule(portRange, "2001:db8::/32")
+	c.Assert(err, gc.ErrorMatches, `invalid CIDR "2001:db8::/32": only IPv4 is supported`)
+}
+
+func (*IngressRuleSuite) TestValidate(c *gc.C) {
+	rule := network.IngressRule{PortRange: network.MustParsePortRange("80/tcp")}
+	c.Assert(rule.Validate(), gc.ErrorMatches, "no source CIDRs for port range 80/tcp")
+	rule.SourceCIDRs = []string{"bad"}
+	c.Assert(rule.Validate(), gc.ErrorMatches, `invalid CIDR "bad"`)
+	rule.PortRange.ToPort = 70
+	c.Assert(rule.Validate(), gc.ErrorMatches, "invalid port range 80-70/tcp")
+}
+
+func (*IngressRuleSuite) TestIngressRulesForPorts(c *gc.C) {
+	portRanges := []network.PortRange{
+		network.MustParsePortRange("80/tcp"),
+		network.MustParsePortRange("53/udp"),
+	}
+	c.Assert(network.IngressRulesForPorts(portRanges), jc.DeepEquals, []network.IngressRule{
+		{portRanges[0], []string{network.AnyCIDR}},
+		{portRanges[1], []string{network.AnyCIDR}},
+	})
+}
+
+func (*IngressRuleSuite) TestSortIngressRules(c *gc.C) {
+	rules := []network.IngressRule{
+		network.MustNewIngressRule(network.MustParsePortRange("80/tcp"), "10.0.0.0/8"),
+		network.MustNewIngressRule(network.MustParsePortRange("53/udp")),
+		network.MustNewIngressRule(network.MustParsePortRange("80/tcp")),
+		network.MustNewIngressRule(network.MustParsePortRange("22/tcp")),
+	}
+	network.SortIngressRules(rules)
+	var got []string
+	for _, rule := range rules {
+		got = append(got, rule.String())
+	}
+	c.Assert(got, jc.DeepEquals, []string{
+		"22/tcp", "80/tcp", "80/tcp from 10.0.0.0/8", "53/udp",
+	})
+}
+
+func (*IngressRuleSuite) TestMergeIngressRules(c *gc.C) {
+	http := network.MustParsePortRange("80/tcp")
+	dns := network.MustParsePortRange("53/udp")
+	merged := network.MergeIngressRules([]network.IngressRule{
+		{dns, []string{network.AnyCIDR}},
+		{http, []string{"192.168.0.0/16"}},
+		{http, []string{"10.0.0.0/8", "192.168.0.0/16"}},
+	})
+	c.Assert(merged, jc.DeepEquals, []network.IngressRule{
+		{http, []string{"10.0.0.0/8", "192.168.0.0/16"}},
+		{dns, []string{network.AnyCIDR}},
+	})
+	c.Assert(network.MergeIngressRules(nil), gc.HasLen, 0)
+}
+
+func (*IngressRuleSuite) TestNormalizeCIDRs(c *gc.C) {
+	cidrs, err := network.NormalizeCIDRs([]string{" 10.1.0.0/16", "10.1.2.3/16", "0.0.0.0/0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, jc.DeepEquals, []string{"0.0.0.0/0", "10.1.0.0/16"})
+
+	cidrs, err = network.NormalizeCIDRs(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.HasLen, 0)
+}
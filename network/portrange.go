@@ -66,8 +66,10 @@ type portRangeSlice []PortRange
 func (p portRangeSlice) Len() int      { return len(p) }
 func (p portRangeSlice) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
 func (p portRangeSlice) Less(i, j int) bool {
-	p1 := p[i]
-	p2 := p[j]
+	return portRangeLess(p[i], p[j])
+}
+
+func portRangeLess(p1, p2 PortRange) bool {
 	if p1.Protocol != p2.Protocol {
 		return p1.Protocol < p2.Protocol
 	}
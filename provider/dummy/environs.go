@@ -217,6 +217,7 @@ type OpOpenPorts struct {
 	MachineId  string
 	InstanceId instance.Id
 	Ports      []network.PortRange
+	Rules      []network.IngressRule
 }
 
 type OpClosePorts struct {
@@ -224,6 +225,7 @@ type OpClosePorts struct {
 	MachineId  string
 	InstanceId instance.Id
 	Ports      []network.PortRange
+	Rules      []network.IngressRule
 }
 
 type OpPutFile struct {
@@ -258,7 +260,7 @@ type environState struct {
 	maxId        int // maximum instance id allocated so far.
 	maxAddr      int // maximum allocated address last byte
 	insts        map[instance.Id]*dummyInstance
-	globalPorts  map[network.PortRange]bool
+	globalPorts  portSources
 	bootstrapped bool
 	storageDelay time.Duration
 	storage      *storageServer
@@ -280,6 +282,7 @@ type environ struct {
 }
 
 var _ environs.Environ = (*environ)(nil)
+var _ environs.IngressRulesEnviron = (*environ)(nil)
 
 // discardOperations discards all Operations written to it.
 var discardOperations chan<- Operation
@@ -373,7 +376,7 @@ func newState(name string, ops chan<- Operation, policy state.Policy) *environSt
 		ops:         ops,
 		statePolicy: policy,
 		insts:       make(map[instance.Id]*dummyInstance),
-		globalPorts: make(map[network.PortRange]bool),
+		globalPorts: make(portSources),
 	}
 	s.storage = newStorageServer(s, "/"+name+"/private")
 	s.listenStorage()
@@ -725,7 +728,7 @@ func (e *environ) Bootstrap(ctx environs.BootstrapContext, args environs.Bootstr
 	i := &dummyInstance{
 		id:           BootstrapInstanceId,
 		addresses:    network.NewAddresses("localhost"),
-		ports:        make(map[network.PortRange]bool),
+		ports:        make(portSources),
 		machineId:    agent.BootstrapMachineId,
 		series:       series,
 		firewallMode: e.Config().FirewallMode(),
@@ -917,7 +920,7 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	i := &dummyInstance{
 		id:           instance.Id(idString),
 		addresses:    addrs,
-		ports:        make(map[network.PortRange]bool),
+		ports:        make(portSources),
 		machineId:    machineId,
 		series:       series,
 		firewallMode: e.Config().FirewallMode(),
@@ -1333,7 +1336,85 @@ func (e *environ) AllInstances() ([]instance.Instance, error) {
 	return insts, nil
 }
 
+// portSources holds the port ranges opened to each source CIDR.
+type portSources map[portSource]bool
+
+// portSource is a port range opened to a single source CIDR.
+type portSource struct {
+	portRange network.PortRange
+	cidr      string
+}
+
+func (sources portSources) open(rules []network.IngressRule) {
+	for _, rule := range rules {
+		for _, cidr := range rule.SourceCIDRs {
+			sources[portSource{rule.PortRange, cidr}] = true
+		}
+	}
+}
+
+func (sources portSources) close(rules []network.IngressRule) {
+	for _, rule := range rules {
+		for _, cidr := range rule.SourceCIDRs {
+			delete(sources, portSource{rule.PortRange, cidr})
+		}
+	}
+}
+
+// rules returns the opened ingress rules.
+func (sources portSources) rules() []network.IngressRule {
+	var rules []network.IngressRule
+	for source := range sources {
+		rules = append(rules, network.IngressRule{
+			PortRange:   source.portRange,
+			SourceCIDRs: []string{source.cidr},
+		})
+	}
+	return network.MergeIngressRules(rules)
+}
+
+// ports returns the port ranges opened to any address.
+func (sources portSources) ports() []network.PortRange {
+	var ports []network.PortRange
+	for source := range sources {
+		if source.cidr == network.AnyCIDR {
+			ports = append(ports, source.portRange)
+		}
+	}
+	network.SortPortRanges(ports)
+	return ports
+}
+
+func portRanges(rules []network.IngressRule) []network.PortRange {
+	ports := make([]network.PortRange, len(rules))
+	for i, rule := range rules {
+		ports[i] = rule.PortRange
+	}
+	return ports
+}
+
 func (e *environ) OpenPorts(ports []network.PortRange) error {
+	return e.OpenIngressRules(network.IngressRulesForPorts(ports))
+}
+
+func (e *environ) ClosePorts(ports []network.PortRange) error {
+	return e.CloseIngressRules(network.IngressRulesForPorts(ports))
+}
+
+func (e *environ) Ports() (ports []network.PortRange, err error) {
+	if mode := e.ecfg().FirewallMode(); mode != config.FwGlobal {
+		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from environment", mode)
+	}
+	estate, err := e.state()
+	if err != nil {
+		return nil, err
+	}
+	estate.mu.Lock()
+	defer estate.mu.Unlock()
+	return estate.globalPorts.ports(), nil
+}
+
+func (e *environ) OpenIngressRules(rules []network.IngressRule) error {
 	if mode := e.ecfg().FirewallMode(); mode != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on environment", mode)
 	}
@@ -1343,13 +1424,11 @@ func (e *environ) OpenPorts(ports []network.PortRange) error {
 	}
 	estate.mu.Lock()
 	defer estate.mu.Unlock()
-	for _, p := range ports {
-		estate.globalPorts[p] = true
-	}
+	estate.globalPorts.open(rules)
 	return nil
 }
 
-func (e *environ) ClosePorts(ports []network.PortRange) error {
+func (e *environ) CloseIngressRules(rules []network.IngressRule) error {
 	if mode := e.ecfg().FirewallMode(); mode != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on environment", mode)
 	}
@@ -1359,13 +1438,11 @@ func (e *environ) ClosePorts(ports []network.PortRange) error {
 	}
 	estate.mu.Lock()
 	defer estate.mu.Unlock()
-	for _, p := range ports {
-		delete(estate.globalPorts, p)
-	}
+	estate.globalPorts.close(rules)
 	return nil
 }
 
-func (e *environ) Ports() (ports []network.PortRange, err error) {
+func (e *environ) IngressRules() ([]network.IngressRule, error) {
 	if mode := e.ecfg().FirewallMode(); mode != config.FwGlobal {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from environment", mode)
 	}
@@ -1375,11 +1452,7 @@ func (e *environ) Ports() (ports []network.PortRange, err error) {
 	}
 	estate.mu.Lock()
 	defer estate.mu.Unlock()
-	for p := range estate.globalPorts {
-		ports = append(ports, p)
-	}
-	network.SortPortRanges(ports)
-	return
+	return estate.globalPorts.rules(), nil
 }
 
 func (*environ) Provider() environs.EnvironProvider {
@@ -1388,7 +1461,7 @@ func (*environ) Provider() environs.EnvironProvider {
 
 type dummyInstance struct {
 	state        *environState
-	ports        map[network.PortRange]bool
+	ports        portSources
 	id           instance.Id
 	status       string
 	machineId    string
@@ -1438,8 +1511,30 @@ func (inst *dummyInstance) Addresses() ([]network.Address, error) {
 }
 
 func (inst *dummyInstance) OpenPorts(machineId string, ports []network.PortRange) error {
+	return inst.OpenIngressRules(machineId, network.IngressRulesForPorts(ports))
+}
+
+func (inst *dummyInstance) ClosePorts(machineId string, ports []network.PortRange) error {
+	return inst.CloseIngressRules(machineId, network.IngressRulesForPorts(ports))
+}
+
+func (inst *dummyInstance) Ports(machineId string) (ports []network.PortRange, err error) {
+	defer delay()
+	if inst.firewallMode != config.FwInstance {
+		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from instance",
+			inst.firewallMode)
+	}
+	if inst.machineId != machineId {
+		panic(fmt.Errorf("Ports with mismatched machine id, expected %q got %q", inst.machineId, machineId))
+	}
+	inst.state.mu.Lock()
+	defer inst.state.mu.Unlock()
+	return inst.ports.ports(), nil
+}
+
+func (inst *dummyInstance) OpenIngressRules(machineId string, rules []network.IngressRule) error {
 	defer delay()
-	logger.Infof("openPorts %s, %#v", machineId, ports)
+	logger.Infof("openPorts %s, %#v", machineId, rules)
 	if inst.firewallMode != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on instance",
 			inst.firewallMode)
@@ -1453,15 +1548,14 @@ func (inst *dummyInstance) OpenPorts(machineId string, ports []network.PortRange
 		Env:        inst.state.name,
 		MachineId:  machineId,
 		InstanceId: inst.Id(),
-		Ports:      ports,
-	}
-	for _, p := range ports {
-		inst.ports[p] = true
+		Ports:      portRanges(rules),
+		Rules:      rules,
 	}
+	inst.ports.open(rules)
 	return nil
 }
 
-func (inst *dummyInstance) ClosePorts(machineId string, ports []network.PortRange) error {
+func (inst *dummyInstance) CloseIngressRules(machineId string, rules []network.IngressRule) error {
 	defer delay()
 	if inst.firewallMode != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on instance",
@@ -1476,30 +1570,25 @@ func (inst *dummyInstance) ClosePorts(machineId string, ports []network.PortRang
 		Env:        inst.state.name,
 		MachineId:  machineId,
 		InstanceId: inst.Id(),
-		Ports:      ports,
-	}
-	for _, p := range ports {
-		delete(inst.ports, p)
+		Ports:      portRanges(rules),
+		Rules:      rules,
 	}
+	inst.ports.close(rules)
 	return nil
 }
 
-func (inst *dummyInstance) Ports(machineId string) (ports []network.PortRange, err error) {
+func (inst *dummyInstance) IngressRules(machineId string) ([]network.IngressRule, error) {
 	defer delay()
 	if inst.firewallMode != config.FwInstance {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from instance",
 			inst.firewallMode)
 	}
 	if inst.machineId != machineId {
-		panic(fmt.Errorf("Ports with mismatched machine id, expected %q got %q", inst.machineId, machineId))
+		panic(fmt.Errorf("IngressRules with mismatched machine id, expected %q got %q", inst.machineId, machineId))
 	}
 	inst.state.mu.Lock()
 	defer inst.state.mu.Unlock()
-	for p := range inst.ports {
-		ports = append(ports, p)
-	}
-	network.SortPortRanges(ports)
-	return
+	return inst.ports.rules(), nil
 }
 
 // providerDelay controls the delay before dummy responds.
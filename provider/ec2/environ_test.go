@@ -79,7 +79,7 @@ func pInt(i uint64) *uint64 {
 	return &i
 }
 
-func (*Suite) TestPortsToIPPerms(c *gc.C) {
+func (*Suite) TestRulesToIPPerms(c *gc.C) {
 	testCases := []struct {
 		about    string
 		ports    []network.PortRange
@@ -136,7 +136,17 @@ func (*Suite) TestPortsToIPPerms(c *gc.C) {
 
 	for i, t := range testCases {
 		c.Logf("test %d: %s", i, t.about)
-		ipperms := portsToIPPerms(t.ports)
+		ipperms := rulesToIPPerms(network.IngressRulesForPorts(t.ports))
 		c.Assert(ipperms, gc.DeepEquals, t.expected)
 	}
+
+	rules := []network.IngressRule{
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "10.0.0.0/8", "192.168.0.0/16"),
+	}
+	c.Assert(rulesToIPPerms(rules), gc.DeepEquals, []amzec2.IPPerm{{
+		Protocol:  "tcp",
+		FromPort:  80,
+		ToPort:    80,
+		SourceIPs: []string{"10.0.0.0/8", "192.168.0.0/16"},
+	}})
 }
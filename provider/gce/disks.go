@@ -0,0 +1,393 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/schema"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/provider/gce/google"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/poolmanager"
+)
+
+const (
+	// storageProviderType is the name of the storage provider that
+	// creates GCE persistent disks.
+	storageProviderType = storage.ProviderType("gce")
+
+	// diskTypeAttribute is the name of the pool attribute used to
+	// choose between standard and SSD persistent disks.
+	diskTypeAttribute = "disk-type"
+
+	// maxDiskSizeGB is the largest persistent disk GCE will create.
+	maxDiskSizeGB = 10240
+
+	// volumeIdSeparator separates the zone from the disk name in the
+	// volume IDs reported to Juju; GCE disk names are only unique
+	// within a zone.
+	volumeIdSeparator = "--"
+
+	// diskHardwareIdPrefix is the prefix of the hardware ID of an
+	// attached persistent disk; the rest is the device name, which is
+	// always the disk's name.
+	diskHardwareIdPrefix = "scsi-0Google_PersistentDisk_"
+)
+
+func init() {
+	ssdPool, _ := storage.NewConfig("gce-ssd", storageProviderType, map[string]interface{}{
+		diskTypeAttribute: google.DiskPersistentSSD,
+	})
+	poolmanager.RegisterDefaultStoragePools([]*storage.Config{ssdPool})
+}
+
+// storageProvider creates volume sources which use GCE persistent
+// disks.
+type storageProvider struct{}
+
+var _ storage.Provider = (*storageProvider)(nil)
+
+var storageConfigFields = schema.Fields{
+	diskTypeAttribute: schema.OneOf(
+		schema.Const(google.DiskPersistentStandard),
+		schema.Const(google.DiskPersistentSSD),
+	),
+}
+
+var storageConfigChecker = schema.FieldMap(
+	storageConfigFields,
+	schema.Defaults{
+		diskTypeAttribute: google.DiskPersistentStandard,
+	},
+)
+
+type storageConfig struct {
+	diskType string
+}
+
+func newStorageConfig(attrs map[string]interface{}) (*storageConfig, error) {
+	out, err := storageConfigChecker.Coerce(attrs, nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "validating GCE storage config")
+	}
+	coerced := out.(map[string]interface{})
+	return &storageConfig{
+		diskType: coerced[diskTypeAttribute].(string),
+	}, nil
+}
+
+// ValidateConfig is defined on the storage.Provider interface.
+func (*storageProvider) ValidateConfig(cfg *storage.Config) error {
+	_, err := newStorageConfig(cfg.Attrs())
+	return errors.Trace(err)
+}
+
+// Supports is defined on the storage.Provider interface.
+func (*storageProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is defined on the storage.Provider interface.
+func (*storageProvider) Scope() storage.Scope {
+	return storage.ScopeEnviron
+}
+
+// Dynamic is defined on the storage.Provider interface.
+func (*storageProvider) Dynamic() bool {
+	return true
+}
+
+// VolumeSource is defined on the storage.Provider interface.
+func (*storageProvider) VolumeSource(environConfig *config.Config, cfg *storage.Config) (storage.VolumeSource, error) {
+	ecfg, err := newValidConfig(environConfig, configDefaults)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	uuid, ok := ecfg.UUID()
+	if !ok {
+		return nil, errors.NotFoundf("environment UUID")
+	}
+	conn, err := newConnection(ecfg)
+	if err != nil {
+		return nil, errors.Annotate(err, "connecting to GCE")
+	}
+	source := &volumeSource{
+		gce:    conn,
+		region: ecfg.region(),
+		prefix: fmt.Sprintf("juju-%s-volume-", uuid),
+	}
+	return source, nil
+}
+
+// FilesystemSource is defined on the storage.Provider interface.
+func (*storageProvider) FilesystemSource(environConfig *config.Config, cfg *storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+type volumeSource struct {
+	gce    gceConnection
+	region string
+	// prefix starts the name of every disk created by the source.
+	prefix string
+}
+
+var _ storage.VolumeSource = (*volumeSource)(nil)
+
+// diskName returns the name of the persistent disk for the volume
+// with the given tag.
+func (v *volumeSource) diskName(tag names.VolumeTag) string {
+	return v.prefix + strings.Replace(tag.Id(), "/", "-", -1)
+}
+
+// volumeId returns the ID reported to Juju for the named disk in the
+// given zone.
+func volumeId(zone, name string) string {
+	return zone + volumeIdSeparator + name
+}
+
+// parseVolumeId returns the zone and name of the disk with the given
+// volume ID.
+func parseVolumeId(volId string) (zone, name string, _ error) {
+	parts := strings.SplitN(volId, volumeIdSeparator, 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.NotValidf("volume ID %q", volId)
+	}
+	return parts[0], parts[1], nil
+}
+
+// diskVolumeInfo returns the storage.VolumeInfo describing a disk.
+func diskVolumeInfo(disk *google.Disk) storage.VolumeInfo {
+	return storage.VolumeInfo{
+		VolumeId:   volumeId(disk.Zone, disk.Name),
+		HardwareId: diskHardwareIdPrefix + disk.Name,
+		Size:       gbToMib(disk.SizeGB),
+		// Persistent disks outlive the instances they are
+		// attached to.
+		Persistent: true,
+	}
+}
+
+// CreateVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) CreateVolumes(params []storage.VolumeParams) (_ []storage.Volume, _ []storage.VolumeAttachment, err error) {
+	volumes := make([]storage.Volume, 0, len(params))
+	attachments := make([]storage.VolumeAttachment, 0, len(params))
+
+	// If there's an error, we destroy any disks that were created.
+	defer func() {
+		if err == nil || len(volumes) == 0 {
+			return
+		}
+		volIds := make([]string, len(volumes))
+		for i, vol := range volumes {
+			volIds[i] = vol.VolumeId
+		}
+		for i, volErr := range v.DestroyVolumes(volIds) {
+			if volErr != nil {
+				logger.Warningf("error cleaning up volume %v: %v", volumes[i].Tag, volErr)
+			}
+		}
+	}()
+
+	for _, p := range params {
+		if err := v.ValidateVolumeParams(p); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	for _, p := range params {
+		zone, err := v.volumeZone(p)
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "choosing zone for %v", p.Tag)
+		}
+		cfg, _ := newStorageConfig(p.Attributes)
+		disk, err := v.gce.CreateDisk(zone, google.PersistentDiskSpec{
+			Name:        v.diskName(p.Tag),
+			SizeHintGB:  mibToGb(p.Size),
+			Type:        cfg.diskType,
+			Description: names.ReadableString(p.Tag),
+		})
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		volumes = append(volumes, storage.Volume{p.Tag, diskVolumeInfo(disk)})
+
+		if p.Attachment == nil || p.Attachment.InstanceId == "" {
+			continue
+		}
+		attachParams := *p.Attachment
+		attachParams.VolumeId = volumeId(disk.Zone, disk.Name)
+		attachment, err := v.attachVolume(attachParams)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		attachments = append(attachments, attachment)
+	}
+	return volumes, attachments, nil
+}
+
+// volumeZone returns the zone in which to create a volume: the zone of
+// the instance to which it will be attached if there is one, or else
+// the first available zone in the environment's region.
+func (v *volumeSource) volumeZone(p storage.VolumeParams) (string, error) {
+	if p.Attachment != nil && p.Attachment.InstanceId != "" {
+		instId := string(p.Attachment.InstanceId)
+		insts, err := v.gce.Instances(instId, instStatuses...)
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		for _, inst := range insts {
+			if inst.ID == instId {
+				return path.Base(inst.ZoneName), nil
+			}
+		}
+		return "", errors.NotFoundf("instance %q", instId)
+	}
+	zones, err := v.gce.AvailabilityZones(v.region)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	for _, zone := range zones {
+		if zone.Available() && !zone.Deprecated() {
+			return zone.Name(), nil
+		}
+	}
+	return "", errors.Errorf("no available zones in region %q", v.region)
+}
+
+// ListVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) ListVolumes() ([]string, error) {
+	disks, err := v.gce.Disks(v.prefix)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volIds := make([]string, len(disks))
+	for i, disk := range disks {
+		volIds[i] = volumeId(disk.Zone, disk.Name)
+	}
+	return volIds, nil
+}
+
+// DescribeVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) DescribeVolumes(volIds []string) ([]storage.VolumeInfo, error) {
+	results := make([]storage.VolumeInfo, len(volIds))
+	for i, volId := range volIds {
+		zone, name, err := parseVolumeId(volId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		disk, err := v.gce.Disk(zone, name)
+		if err != nil {
+			return nil, errors.Annotatef(err, "describing volume %q", volId)
+		}
+		results[i] = diskVolumeInfo(disk)
+	}
+	return results, nil
+}
+
+// DestroyVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) DestroyVolumes(volIds []string) []error {
+	results := make([]error, len(volIds))
+	for i, volId := range volIds {
+		results[i] = v.destroyVolume(volId)
+	}
+	return results
+}
+
+func (v *volumeSource) destroyVolume(volId string) error {
+	logger.Debugf("destroying %q", volId)
+	zone, name, err := parseVolumeId(volId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	disk, err := v.gce.Disk(zone, name)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Annotatef(err, "destroying %q", volId)
+	}
+	// Disks cannot be removed while they are attached.
+	for _, instId := range disk.AttachedTo {
+		if err := v.gce.DetachDisk(zone, name, instId); err != nil {
+			return errors.Annotatef(err, "destroying %q", volId)
+		}
+	}
+	if err := v.gce.RemoveDisk(zone, name); err != nil {
+		return errors.Annotatef(err, "destroying %q", volId)
+	}
+	return nil
+}
+
+// ValidateVolumeParams is specified on the storage.VolumeSource interface.
+func (v *volumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	if _, err := newStorageConfig(params.Attributes); err != nil {
+		return errors.Trace(err)
+	}
+	if size := mibToGb(params.Size); size > maxDiskSizeGB {
+		return errors.Errorf("%d GB exceeds the maximum of %d GB", size, maxDiskSizeGB)
+	}
+	return nil
+}
+
+// AttachVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) AttachVolumes(attachParams []storage.VolumeAttachmentParams) ([]storage.VolumeAttachment, error) {
+	attachments := make([]storage.VolumeAttachment, len(attachParams))
+	for i, p := range attachParams {
+		attachment, err := v.attachVolume(p)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		attachments[i] = attachment
+	}
+	return attachments, nil
+}
+
+func (v *volumeSource) attachVolume(p storage.VolumeAttachmentParams) (storage.VolumeAttachment, error) {
+	zone, name, err := parseVolumeId(p.VolumeId)
+	if err != nil {
+		return storage.VolumeAttachment{}, errors.Trace(err)
+	}
+	instId := string(p.InstanceId)
+	if err := v.gce.AttachDisk(zone, name, instId, p.ReadOnly); err != nil {
+		return storage.VolumeAttachment{}, errors.Annotatef(err, "attaching %v to %v", p.VolumeId, instId)
+	}
+	// The device name is left blank: the disk is identified on the
+	// instance by its hardware ID instead.
+	return storage.VolumeAttachment{
+		p.Volume,
+		p.Machine,
+		storage.VolumeAttachmentInfo{
+			ReadOnly: p.ReadOnly,
+		},
+	}, nil
+}
+
+// DetachVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) DetachVolumes(attachParams []storage.VolumeAttachmentParams) error {
+	for _, p := range attachParams {
+		zone, name, err := parseVolumeId(p.VolumeId)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := v.gce.DetachDisk(zone, name, string(p.InstanceId)); err != nil {
+			return errors.Annotatef(err, "detaching %v from %v", p.Volume, p.Machine)
+		}
+	}
+	return nil
+}
+
+// mibToGb converts a size in MiB, as used by Juju, to the number of GB
+// (strictly GiB), as used by GCE, rounding up.
+func mibToGb(m uint64) uint64 {
+	return (m + 1023) / 1024
+}
+
+// gbToMib converts a size in GB, as used by GCE, to MiB.
+func gbToMib(g uint64) uint64 {
+	return g * 1024
+}
@@ -0,0 +1,260 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/gce"
+	"github.com/juju/juju/provider/gce/google"
+	"github.com/juju/juju/storage"
+)
+
+type storageProviderSuite struct {
+	gce.BaseSuite
+	provider storage.Provider
+}
+
+var _ = gc.Suite(&storageProviderSuite{})
+
+func (s *storageProviderSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.provider = gce.StorageProvider()
+}
+
+func (s *storageProviderSuite) TestValidateConfig(c *gc.C) {
+	cfg, err := storage.NewConfig("foo", "gce", map[string]interface{}{
+		"disk-type": "pd-ssd",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.provider.ValidateConfig(cfg), jc.ErrorIsNil)
+
+	cfg, err = storage.NewConfig("foo", "gce", map[string]interface{}{
+		"disk-type": "floppy",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.provider.ValidateConfig(cfg)
+	c.Check(err, gc.ErrorMatches, `validating GCE storage config: disk-type: expected "pd-standard", got "floppy"`)
+}
+
+func (s *storageProviderSuite) TestSupports(c *gc.C) {
+	c.Check(s.provider.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Check(s.provider.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+	c.Check(s.provider.Scope(), gc.Equals, storage.ScopeEnviron)
+	c.Check(s.provider.Dynamic(), jc.IsTrue)
+}
+
+type volumeSourceSuite struct {
+	gce.BaseSuite
+	source   storage.VolumeSource
+	diskName string
+	volumeId string
+}
+
+var _ = gc.Suite(&volumeSourceSuite{})
+
+func (s *volumeSourceSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	cfg, err := storage.NewConfig("gce", "gce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.source, err = gce.StorageProvider().VolumeSource(s.Config, cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.diskName = s.Prefix + "volume-0"
+	s.volumeId = "home-zone--" + s.diskName
+	s.FakeConn.DiskValue = &google.Disk{
+		Name:   s.diskName,
+		Zone:   "home-zone",
+		SizeGB: 10,
+		Type:   google.DiskPersistentStandard,
+		Status: google.DiskStatusReady,
+	}
+}
+
+func (s *volumeSourceSuite) TestCreateVolumes(c *gc.C) {
+	s.FakeConn.Insts = []google.Instance{*s.BaseInstance}
+
+	volumes, attachments, err := s.source.CreateVolumes([]storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("0"),
+		Size:       10 * 1024,
+		Provider:   "gce",
+		Attributes: map[string]interface{}{"disk-type": "pd-ssd"},
+		Attachment: &storage.VolumeAttachmentParams{
+			AttachmentParams: storage.AttachmentParams{
+				Machine:    names.NewMachineTag("0"),
+				InstanceId: instance.Id("spam"),
+			},
+			Volume: names.NewVolumeTag("0"),
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(volumes, jc.DeepEquals, []storage.Volume{{
+		names.NewVolumeTag("0"),
+		storage.VolumeInfo{
+			VolumeId:   s.volumeId,
+			HardwareId: "scsi-0Google_PersistentDisk_" + s.diskName,
+			Size:       10 * 1024,
+			Persistent: true,
+		},
+	}})
+	c.Check(attachments, jc.DeepEquals, []storage.VolumeAttachment{{
+		names.NewVolumeTag("0"),
+		names.NewMachineTag("0"),
+		storage.VolumeAttachmentInfo{},
+	}})
+
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 3)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "Instances")
+	c.Check(s.FakeConn.Calls[0].Prefix, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "CreateDisk")
+	c.Check(s.FakeConn.Calls[1].ZoneName, gc.Equals, "home-zone")
+	c.Check(s.FakeConn.Calls[1].DiskSpec, jc.DeepEquals, google.PersistentDiskSpec{
+		Name:        s.diskName,
+		SizeHintGB:  10,
+		Type:        google.DiskPersistentSSD,
+		Description: "volume 0",
+	})
+	c.Check(s.FakeConn.Calls[2].FuncName, gc.Equals, "AttachDisk")
+	c.Check(s.FakeConn.Calls[2].ZoneName, gc.Equals, "home-zone")
+	c.Check(s.FakeConn.Calls[2].ID, gc.Equals, s.diskName)
+	c.Check(s.FakeConn.Calls[2].IDs, jc.DeepEquals, []string{"spam"})
+}
+
+func (s *volumeSourceSuite) TestCreateVolumesUnattached(c *gc.C) {
+	s.FakeConn.Zones = []google.AvailabilityZone{
+		google.NewZone("a-zone", google.StatusDown, "", ""),
+		google.NewZone("b-zone", google.StatusUp, "", ""),
+	}
+
+	_, attachments, err := s.source.CreateVolumes([]storage.VolumeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		Size:     1024,
+		Provider: "gce",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(attachments, gc.HasLen, 0)
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "AvailabilityZones")
+	c.Check(s.FakeConn.Calls[0].Region, gc.Equals, "home")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "CreateDisk")
+	c.Check(s.FakeConn.Calls[1].ZoneName, gc.Equals, "b-zone")
+	c.Check(s.FakeConn.Calls[1].DiskSpec.Type, gc.Equals, google.DiskPersistentStandard)
+}
+
+func (s *volumeSourceSuite) TestCreateVolumesUnknownInstance(c *gc.C) {
+	_, _, err := s.source.CreateVolumes([]storage.VolumeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		Size:     1024,
+		Provider: "gce",
+		Attachment: &storage.VolumeAttachmentParams{
+			AttachmentParams: storage.AttachmentParams{
+				InstanceId: instance.Id("spam"),
+			},
+		},
+	}})
+	c.Assert(err, gc.ErrorMatches, `choosing zone for volume-0: instance "spam" not found`)
+}
+
+func (s *volumeSourceSuite) TestValidateVolumeParams(c *gc.C) {
+	err := s.source.ValidateVolumeParams(storage.VolumeParams{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 20000 * 1024,
+	})
+	c.Assert(err, gc.ErrorMatches, "20000 GB exceeds the maximum of 10240 GB")
+}
+
+func (s *volumeSourceSuite) TestListVolumes(c *gc.C) {
+	s.FakeConn.DiskValues = []*google.Disk{s.FakeConn.DiskValue}
+
+	volIds, err := s.source.ListVolumes()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(volIds, jc.DeepEquals, []string{s.volumeId})
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "Disks")
+	c.Check(s.FakeConn.Calls[0].Prefix, gc.Equals, s.Prefix+"volume-")
+}
+
+func (s *volumeSourceSuite) TestDescribeVolumes(c *gc.C) {
+	info, err := s.source.DescribeVolumes([]string{s.volumeId})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(info, jc.DeepEquals, []storage.VolumeInfo{{
+		VolumeId:   s.volumeId,
+		HardwareId: "scsi-0Google_PersistentDisk_" + s.diskName,
+		Size:       10 * 1024,
+		Persistent: true,
+	}})
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "home-zone")
+	c.Check(s.FakeConn.Calls[0].ID, gc.Equals, s.diskName)
+
+	_, err = s.source.DescribeVolumes([]string{"bogus"})
+	c.Check(err, gc.ErrorMatches, `volume ID "bogus" not valid`)
+}
+
+func (s *volumeSourceSuite) TestDestroyVolumes(c *gc.C) {
+	s.FakeConn.DiskValue.AttachedTo = []string{"spam"}
+
+	errs := s.source.DestroyVolumes([]string{s.volumeId})
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 3)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "Disk")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "DetachDisk")
+	c.Check(s.FakeConn.Calls[1].IDs, jc.DeepEquals, []string{"spam"})
+	c.Check(s.FakeConn.Calls[2].FuncName, gc.Equals, "RemoveDisk")
+	c.Check(s.FakeConn.Calls[2].ZoneName, gc.Equals, "home-zone")
+	c.Check(s.FakeConn.Calls[2].ID, gc.Equals, s.diskName)
+}
+
+func (s *volumeSourceSuite) TestDestroyVolumesNotFound(c *gc.C) {
+	s.FakeConn.Err = errors.NotFoundf("disk")
+
+	errs := s.source.DestroyVolumes([]string{s.volumeId})
+	c.Assert(errs, jc.DeepEquals, []error{nil})
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+}
+
+func (s *volumeSourceSuite) TestAttachVolumes(c *gc.C) {
+	attachments, err := s.source.AttachVolumes([]storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: instance.Id("spam"),
+			ReadOnly:   true,
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: s.volumeId,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(attachments, jc.DeepEquals, []storage.VolumeAttachment{{
+		names.NewVolumeTag("0"),
+		names.NewMachineTag("0"),
+		storage.VolumeAttachmentInfo{ReadOnly: true},
+	}})
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "AttachDisk")
+	c.Check(s.FakeConn.Calls[0].ReadOnly, jc.IsTrue)
+}
+
+func (s *volumeSourceSuite) TestDetachVolumes(c *gc.C) {
+	err := s.source.DetachVolumes([]storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: instance.Id("spam"),
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: s.volumeId,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "DetachDisk")
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "home-zone")
+	c.Check(s.FakeConn.Calls[0].ID, gc.Equals, s.diskName)
+	c.Check(s.FakeConn.Calls[0].IDs, jc.DeepEquals, []string{"spam"})
+}
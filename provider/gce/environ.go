@@ -16,8 +16,6 @@ import (
 	"github.com/juju/juju/provider/gce/google"
 )
 
-// Note: This provider/environment does *not* implement storage.
-
 type gceConnection interface {
 	VerifyCredentials() error
 
@@ -31,8 +29,18 @@ type gceConnection interface {
 	Ports(fwname string) ([]network.PortRange, error)
 	OpenPorts(fwname string, ports ...network.PortRange) error
 	ClosePorts(fwname string, ports ...network.PortRange) error
+	IngressRules(fwname string) ([]network.IngressRule, error)
+	OpenIngressRules(fwname string, rules ...network.IngressRule) error
+	CloseIngressRules(fwname string, rules ...network.IngressRule) error
 
 	AvailabilityZones(region string) ([]google.AvailabilityZone, error)
+
+	CreateDisk(zone string, spec google.PersistentDiskSpec) (*google.Disk, error)
+	Disk(zone, name string) (*google.Disk, error)
+	Disks(prefix string) ([]*google.Disk, error)
+	RemoveDisk(zone, name string) error
+	AttachDisk(zone, name, instanceID string, readOnly bool) error
+	DetachDisk(zone, name, instanceID string) error
 }
 
 type environ struct {
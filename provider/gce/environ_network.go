@@ -6,6 +6,7 @@ package gce
 import (
 	"github.com/juju/errors"
 
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/common"
@@ -64,3 +65,30 @@ func (env *environ) Ports() ([]network.PortRange, error) {
 	ports, err := env.gce.Ports(env.globalFirewallName())
 	return ports, errors.Trace(err)
 }
+
+var _ environs.IngressRulesEnviron = (*environ)(nil)
+
+// OpenIngressRules opens the given rules' port ranges to their source
+// CIDRs for the whole environment. Must only be used if the
+// environment was setup with the FwGlobal firewall mode.
+func (env *environ) OpenIngressRules(rules []network.IngressRule) error {
+	err := env.gce.OpenIngressRules(env.globalFirewallName(), rules...)
+	return errors.Trace(err)
+}
+
+// CloseIngressRules closes the given rules' port ranges to their
+// source CIDRs for the whole environment. Must only be used if the
+// environment was setup with the FwGlobal firewall mode.
+func (env *environ) CloseIngressRules(rules []network.IngressRule) error {
+	err := env.gce.CloseIngressRules(env.globalFirewallName(), rules...)
+	return errors.Trace(err)
+}
+
+// IngressRules returns the port ranges opened for the whole
+// environment, with the source CIDRs from which they may be reached.
+// Must only be used if the environment was setup with the FwGlobal
+// firewall mode.
+func (env *environ) IngressRules() ([]network.IngressRule, error) {
+	rules, err := env.gce.IngressRules(env.globalFirewallName())
+	return rules, errors.Trace(err)
+}
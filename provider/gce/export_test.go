@@ -8,6 +8,7 @@ import (
 	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/provider/gce/google"
+	"github.com/juju/juju/storage"
 )
 
 var (
@@ -19,6 +20,10 @@ var (
 	ConfigImmutable                            = configImmutableFields
 )
 
+func StorageProvider() storage.Provider {
+	return &storageProvider{}
+}
+
 func ExposeInstBase(inst *environInstance) *google.Instance {
 	return inst.base
 }
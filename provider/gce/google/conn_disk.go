@@ -0,0 +1,101 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package google
+
+import (
+	"github.com/juju/errors"
+)
+
+// CreateDisk creates a new persistent disk in the given zone, based
+// on the spec's data, and returns it. The call blocks until the disk
+// is created or the request fails.
+func (gce *Connection) CreateDisk(zone string, spec PersistentDiskSpec) (*Disk, error) {
+	if err := gce.raw.AddDisk(gce.projectID, zone, spec.raw(zone)); err != nil {
+		return nil, errors.Annotatef(err, "creating disk %q", spec.Name)
+	}
+	return gce.Disk(zone, spec.Name)
+}
+
+// Disk gets the up-to-date info about the named persistent disk in
+// the given zone and returns it. If the disk does not exist then
+// errors.NotFound is returned.
+func (gce *Connection) Disk(zone, name string) (*Disk, error) {
+	raw, err := gce.raw.GetDisk(gce.projectID, zone, name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newDisk(raw), nil
+}
+
+// Disks returns all of the persistent disks in the Connection's
+// project, in any zone, for which the name starts with the provided
+// prefix.
+func (gce *Connection) Disks(prefix string) ([]*Disk, error) {
+	rawDisks, err := gce.raw.ListDisks(gce.projectID, prefix)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var disks []*Disk
+	for _, rawDisk := range rawDisks {
+		disks = append(disks, newDisk(rawDisk))
+	}
+	return disks, nil
+}
+
+// RemoveDisk removes the named persistent disk from the given zone.
+// Removing a disk that does not exist is not an error. The call blocks
+// until the disk is removed or the request fails.
+func (gce *Connection) RemoveDisk(zone, name string) error {
+	err := gce.raw.RemoveDisk(gce.projectID, zone, name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+// AttachDisk attaches the named persistent disk to the identified
+// instance, which must be in the same zone. The disk's name is used
+// as its device name on the instance. Attaching a disk that is already
+// attached to the instance is not an error.
+func (gce *Connection) AttachDisk(zone, name, instanceID string, readOnly bool) error {
+	disk, err := gce.Disk(zone, name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, attached := range disk.AttachedTo {
+		if attached == instanceID {
+			return nil
+		}
+	}
+	attachment := newAttachedPersistent(gce.projectID, zone, name, readOnly)
+	err = gce.raw.AttachDisk(gce.projectID, zone, instanceID, attachment)
+	if err != nil {
+		return errors.Annotatef(err, "attaching disk %q to %q", name, instanceID)
+	}
+	return nil
+}
+
+// DetachDisk detaches the named persistent disk from the identified
+// instance. Detaching a disk that does not exist, or is not attached to
+// the instance, is not an error.
+func (gce *Connection) DetachDisk(zone, name, instanceID string) error {
+	disk, err := gce.Disk(zone, name)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	for _, attached := range disk.AttachedTo {
+		if attached != instanceID {
+			continue
+		}
+		err := gce.raw.DetachDisk(gce.projectID, zone, instanceID, name)
+		if err != nil {
+			return errors.Annotatef(err, "detaching disk %q from %q", name, instanceID)
+		}
+		break
+	}
+	return nil
+}
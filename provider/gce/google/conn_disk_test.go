@@ -0,0 +1,139 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package google_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"google.golang.org/api/compute/v1"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/provider/gce/google"
+)
+
+func (s *connSuite) rawDisk(users ...string) *compute.Disk {
+	return &compute.Disk{
+		Name:   "juju-volume-0",
+		Zone:   "https://www.googleapis.com/compute/v1/projects/spam/zones/a-zone",
+		SizeGb: 20,
+		Type:   "https://www.googleapis.com/compute/v1/projects/spam/zones/a-zone/diskTypes/pd-ssd",
+		Status: google.DiskStatusReady,
+		Users:  users,
+	}
+}
+
+func (s *connSuite) TestConnectionCreateDisk(c *gc.C) {
+	s.FakeConn.Disk = s.rawDisk()
+
+	disk, err := s.Conn.CreateDisk("a-zone", google.PersistentDiskSpec{
+		Name:       "juju-volume-0",
+		SizeHintGB: 20,
+		Type:       google.DiskPersistentSSD,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(disk, jc.DeepEquals, &google.Disk{
+		Name:   "juju-volume-0",
+		Zone:   "a-zone",
+		SizeGB: 20,
+		Type:   google.DiskPersistentSSD,
+		Status: google.DiskStatusReady,
+	})
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "AddDisk")
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "a-zone")
+	c.Check(s.FakeConn.Calls[0].Disk, jc.DeepEquals, &compute.Disk{
+		Name:   "juju-volume-0",
+		SizeGb: 20,
+		Type:   "zones/a-zone/diskTypes/pd-ssd",
+	})
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "GetDisk")
+	c.Check(s.FakeConn.Calls[1].Name, gc.Equals, "juju-volume-0")
+}
+
+func (s *connSuite) TestConnectionCreateDiskDefaults(c *gc.C) {
+	s.FakeConn.Disk = s.rawDisk()
+
+	_, err := s.Conn.CreateDisk("a-zone", google.PersistentDiskSpec{Name: "juju-volume-0"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls[0].Disk.SizeGb, gc.Equals, int64(google.MinDiskSizeGB))
+	c.Check(s.FakeConn.Calls[0].Disk.Type, gc.Equals, "zones/a-zone/diskTypes/pd-standard")
+}
+
+func (s *connSuite) TestConnectionCreateDiskError(c *gc.C) {
+	s.FakeConn.Err = errors.New("<unknown>")
+
+	_, err := s.Conn.CreateDisk("a-zone", google.PersistentDiskSpec{Name: "juju-volume-0"})
+
+	c.Check(err, gc.ErrorMatches, `creating disk "juju-volume-0": <unknown>`)
+}
+
+func (s *connSuite) TestConnectionDisks(c *gc.C) {
+	s.FakeConn.Disks = []*compute.Disk{s.rawDisk("zones/a-zone/instances/spam")}
+
+	disks, err := s.Conn.Disks("juju-")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(disks, gc.HasLen, 1)
+	c.Check(disks[0].AttachedTo, jc.DeepEquals, []string{"spam"})
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListDisks")
+	c.Check(s.FakeConn.Calls[0].Prefix, gc.Equals, "juju-")
+}
+
+func (s *connSuite) TestConnectionRemoveDiskNotFound(c *gc.C) {
+	s.FakeConn.Err = errors.NotFoundf("disk")
+
+	err := s.Conn.RemoveDisk("a-zone", "juju-volume-0")
+
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "RemoveDisk")
+}
+
+func (s *connSuite) TestConnectionAttachDisk(c *gc.C) {
+	s.FakeConn.Disk = s.rawDisk()
+
+	err := s.Conn.AttachDisk("a-zone", "juju-volume-0", "spam", true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "AttachDisk")
+	c.Check(s.FakeConn.Calls[1].ID, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[1].Attached, jc.DeepEquals, &compute.AttachedDisk{
+		Type:       "PERSISTENT",
+		Mode:       "READ_ONLY",
+		Source:     "projects/spam/zones/a-zone/disks/juju-volume-0",
+		DeviceName: "juju-volume-0",
+	})
+}
+
+func (s *connSuite) TestConnectionAttachDiskAlreadyAttached(c *gc.C) {
+	s.FakeConn.Disk = s.rawDisk("zones/a-zone/instances/spam")
+
+	err := s.Conn.AttachDisk("a-zone", "juju-volume-0", "spam", false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+}
+
+func (s *connSuite) TestConnectionDetachDisk(c *gc.C) {
+	s.FakeConn.Disk = s.rawDisk("zones/a-zone/instances/spam")
+
+	err := s.Conn.DetachDisk("a-zone", "juju-volume-0", "spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "DetachDisk")
+	c.Check(s.FakeConn.Calls[1].ID, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[1].Name, gc.Equals, "juju-volume-0")
+}
+
+func (s *connSuite) TestConnectionDetachDiskNotAttached(c *gc.C) {
+	s.FakeConn.Disk = s.rawDisk("zones/a-zone/instances/eggs")
+
+	err := s.Conn.DetachDisk("a-zone", "juju-volume-0", "spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+}
@@ -103,12 +103,22 @@ func (gce *Connection) removeInstance(id, zone string) error {
 
 	fwname := id
 	err = gce.raw.RemoveFirewall(gce.projectID, fwname)
-	if errors.IsNotFound(err) {
-		return nil
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
 	}
+
+	// Also remove the firewalls holding ports opened to specific
+	// source CIDRs.
+	firewalls, err := gce.sourceFirewalls(fwname)
 	if err != nil {
 		return errors.Trace(err)
 	}
+	for _, firewall := range firewalls {
+		err := gce.raw.RemoveFirewall(gce.projectID, firewall.Name)
+		if err != nil && !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+	}
 	return nil
 }
 
@@ -209,7 +209,7 @@ func (s *connSuite) TestConnectionRemoveInstanceAPI(c *gc.C) {
 	err := google.ConnRemoveInstance(s.Conn, "spam", "a-zone")
 	c.Assert(err, jc.ErrorIsNil)
 
-	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls, gc.HasLen, 3)
 	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "RemoveInstance")
 	c.Check(s.FakeConn.Calls[0].ProjectID, gc.Equals, "spam")
 	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "a-zone")
@@ -217,6 +217,22 @@ func (s *connSuite) TestConnectionRemoveInstanceAPI(c *gc.C) {
 	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "RemoveFirewall")
 	c.Check(s.FakeConn.Calls[1].ProjectID, gc.Equals, "spam")
 	c.Check(s.FakeConn.Calls[1].Name, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[2].FuncName, gc.Equals, "ListFirewalls")
+	c.Check(s.FakeConn.Calls[2].Prefix, gc.Equals, "spam-from-")
+}
+
+func (s *connSuite) TestConnectionRemoveInstanceSourceFirewalls(c *gc.C) {
+	s.FakeConn.Firewalls = []*compute.Firewall{{
+		Name:       "spam-from-93997fe8a8121085",
+		TargetTags: []string{"spam"},
+	}}
+
+	err := google.ConnRemoveInstance(s.Conn, "spam", "a-zone")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 4)
+	c.Check(s.FakeConn.Calls[3].FuncName, gc.Equals, "RemoveFirewall")
+	c.Check(s.FakeConn.Calls[3].Name, gc.Equals, "spam-from-93997fe8a8121085")
 }
 
 func (s *connSuite) TestConnectionRemoveInstanceFailed(c *gc.C) {
@@ -253,12 +269,13 @@ func (s *connSuite) TestConnectionRemoveInstancesAPI(c *gc.C) {
 	err := s.Conn.RemoveInstances("sp", "spam")
 	c.Assert(err, jc.ErrorIsNil)
 
-	c.Check(s.FakeConn.Calls, gc.HasLen, 3)
+	c.Check(s.FakeConn.Calls, gc.HasLen, 4)
 	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListInstances")
 	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "RemoveInstance")
 	c.Check(s.FakeConn.Calls[1].ID, gc.Equals, "spam")
 	c.Check(s.FakeConn.Calls[2].FuncName, gc.Equals, "RemoveFirewall")
 	c.Check(s.FakeConn.Calls[2].Name, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[3].FuncName, gc.Equals, "ListFirewalls")
 }
 
 func (s *connSuite) TestConnectionRemoveInstancesMultiple(c *gc.C) {
@@ -273,16 +290,18 @@ func (s *connSuite) TestConnectionRemoveInstancesMultiple(c *gc.C) {
 	err := s.Conn.RemoveInstances("", "spam", "special")
 	c.Assert(err, jc.ErrorIsNil)
 
-	c.Check(s.FakeConn.Calls, gc.HasLen, 5)
+	c.Check(s.FakeConn.Calls, gc.HasLen, 7)
 	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListInstances")
 	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "RemoveInstance")
 	c.Check(s.FakeConn.Calls[1].ID, gc.Equals, "spam")
 	c.Check(s.FakeConn.Calls[2].FuncName, gc.Equals, "RemoveFirewall")
 	c.Check(s.FakeConn.Calls[2].Name, gc.Equals, "spam")
-	c.Check(s.FakeConn.Calls[3].FuncName, gc.Equals, "RemoveInstance")
-	c.Check(s.FakeConn.Calls[3].ID, gc.Equals, "special")
-	c.Check(s.FakeConn.Calls[4].FuncName, gc.Equals, "RemoveFirewall")
-	c.Check(s.FakeConn.Calls[4].Name, gc.Equals, "special")
+	c.Check(s.FakeConn.Calls[3].FuncName, gc.Equals, "ListFirewalls")
+	c.Check(s.FakeConn.Calls[4].FuncName, gc.Equals, "RemoveInstance")
+	c.Check(s.FakeConn.Calls[4].ID, gc.Equals, "special")
+	c.Check(s.FakeConn.Calls[5].FuncName, gc.Equals, "RemoveFirewall")
+	c.Check(s.FakeConn.Calls[5].Name, gc.Equals, "special")
+	c.Check(s.FakeConn.Calls[6].FuncName, gc.Equals, "ListFirewalls")
 }
 
 func (s *connSuite) TestConnectionRemoveInstancesPartialMatch(c *gc.C) {
@@ -297,12 +316,13 @@ func (s *connSuite) TestConnectionRemoveInstancesPartialMatch(c *gc.C) {
 	err := s.Conn.RemoveInstances("", "spam")
 	c.Assert(err, jc.ErrorIsNil)
 
-	c.Check(s.FakeConn.Calls, gc.HasLen, 3)
+	c.Check(s.FakeConn.Calls, gc.HasLen, 4)
 	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListInstances")
 	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "RemoveInstance")
 	c.Check(s.FakeConn.Calls[1].ID, gc.Equals, "spam")
 	c.Check(s.FakeConn.Calls[2].FuncName, gc.Equals, "RemoveFirewall")
 	c.Check(s.FakeConn.Calls[2].Name, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[3].FuncName, gc.Equals, "ListFirewalls")
 }
 
 func (s *connSuite) TestConnectionRemoveInstancesListFailed(c *gc.C) {
@@ -4,7 +4,12 @@
 package google
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
 	"github.com/juju/errors"
+	"google.golang.org/api/compute/v1"
 
 	"github.com/juju/juju/network"
 )
@@ -20,7 +25,11 @@ func (gce Connection) Ports(fwname string) ([]network.PortRange, error) {
 	if err != nil {
 		return nil, errors.Annotate(err, "while getting ports from GCE")
 	}
+	return firewallPorts(firewall)
+}
 
+// firewallPorts returns the port ranges allowed by the firewall.
+func firewallPorts(firewall *compute.Firewall) ([]network.PortRange, error) {
 	var ports []network.PortRange
 	for _, allowed := range firewall.Allowed {
 		for _, portRangeStr := range allowed.Ports {
@@ -43,6 +52,12 @@ func (gce Connection) Ports(fwname string) ([]network.PortRange, error) {
 // ports it already has open. The call blocks until the ports are
 // opened or the request fails.
 func (gce Connection) OpenPorts(fwname string, ports ...network.PortRange) error {
+	return gce.openPorts(fwname, fwname, network.AnyCIDR, ports)
+}
+
+// openPorts opens the port ranges to the source CIDR on the named
+// firewall, which applies to instances tagged with target.
+func (gce Connection) openPorts(fwname, target, sourceCIDR string, ports []network.PortRange) error {
 	// TODO(ericsnow) Short-circuit if ports is empty.
 
 	// Compose the full set of open ports.
@@ -59,7 +74,7 @@ func (gce Connection) OpenPorts(fwname string, ports ...network.PortRange) error
 	// Send the request, depending on the current ports.
 	if currentPortsSet.IsEmpty() {
 		// Create a new firewall.
-		firewall := firewallSpec(fwname, inputPortsSet)
+		firewall := firewallSpec(fwname, target, sourceCIDR, inputPortsSet)
 		if err := gce.raw.AddFirewall(gce.projectID, firewall); err != nil {
 			return errors.Annotatef(err, "opening port(s) %+v", ports)
 		}
@@ -68,7 +83,7 @@ func (gce Connection) OpenPorts(fwname string, ports ...network.PortRange) error
 
 	// Update an existing firewall.
 	newPortsSet := currentPortsSet.Union(inputPortsSet)
-	firewall := firewallSpec(fwname, newPortsSet)
+	firewall := firewallSpec(fwname, target, sourceCIDR, newPortsSet)
 	if err := gce.raw.UpdateFirewall(gce.projectID, fwname, firewall); err != nil {
 		return errors.Annotatef(err, "opening port(s) %+v", ports)
 	}
@@ -82,6 +97,12 @@ func (gce Connection) OpenPorts(fwname string, ports ...network.PortRange) error
 // match the provided port ranges. The call blocks until the ports are
 // closed or the request fails.
 func (gce Connection) ClosePorts(fwname string, ports ...network.PortRange) error {
+	return gce.closePorts(fwname, fwname, network.AnyCIDR, ports)
+}
+
+// closePorts closes the port ranges opened to the source CIDR on the
+// named firewall, which applies to instances tagged with target.
+func (gce Connection) closePorts(fwname, target, sourceCIDR string, ports []network.PortRange) error {
 	// Compose the full set of open ports.
 	currentPorts, err := gce.Ports(fwname)
 	if err != nil {
@@ -105,9 +126,154 @@ func (gce Connection) ClosePorts(fwname string, ports ...network.PortRange) erro
 	}
 
 	// Update an existing firewall.
-	firewall := firewallSpec(fwname, newPortsSet)
+	firewall := firewallSpec(fwname, target, sourceCIDR, newPortsSet)
 	if err := gce.raw.UpdateFirewall(gce.projectID, fwname, firewall); err != nil {
 		return errors.Annotatef(err, "closing port(s) %+v", ports)
 	}
 	return nil
 }
+
+const (
+	// maxNameLength is the length of the longest name GCE allows
+	// for a resource.
+	maxNameLength = 63
+
+	// sourceHashLength is the number of characters of the hash of
+	// a source CIDR used in the names of the firewalls holding the
+	// port ranges opened to it, room permitting.
+	sourceHashLength = 16
+
+	// minSourceHashLength is the fewest characters of the hash of
+	// a source CIDR used in those names. The firewall name they
+	// are based on is truncated if necessary to make room for them.
+	minSourceHashLength = 8
+)
+
+// sourceFirewallName returns the name of the firewall holding the port
+// ranges opened to the source CIDR on instances tagged with fwname.
+// Port ranges opened to any source are held by the firewall named
+// fwname itself, so that Ports, OpenPorts and ClosePorts see them.
+//
+// The name is made from a hash of the CIDR, rather than the CIDR
+// itself, so that it fits within the names GCE allows however long
+// the CIDR is.
+func sourceFirewallName(fwname, sourceCIDR string) string {
+	if sourceCIDR == network.AnyCIDR {
+		return fwname
+	}
+	prefix := sourceFirewallPrefix(fwname)
+	sum := sha256.Sum256([]byte(sourceCIDR))
+	hash := hex.EncodeToString(sum[:])[:sourceHashLength]
+	if room := maxNameLength - len(prefix); room < len(hash) {
+		hash = hash[:room]
+	}
+	return prefix + hash
+}
+
+// sourceFirewallPrefix returns the prefix of the names of the firewalls
+// holding the port ranges opened to specific CIDRs on instances tagged
+// with fwname. Long firewall names are truncated, so the prefix may be
+// shared with other firewall names; see sourceFirewalls.
+func sourceFirewallPrefix(fwname string) string {
+	const separator = "-from-"
+	if max := maxNameLength - minSourceHashLength - len(separator); len(fwname) > max {
+		fwname = fwname[:max]
+	}
+	return fwname + separator
+}
+
+// sourceFirewalls returns the firewalls holding the port ranges opened
+// to specific CIDRs on instances tagged with fwname.
+func (gce Connection) sourceFirewalls(fwname string) ([]*compute.Firewall, error) {
+	firewalls, err := gce.raw.ListFirewalls(gce.projectID, sourceFirewallPrefix(fwname))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []*compute.Firewall
+	for _, firewall := range firewalls {
+		// The prefix alone does not identify the firewalls of
+		// fwname if it was truncated.
+		if len(firewall.TargetTags) == 1 && firewall.TargetTags[0] == fwname {
+			result = append(result, firewall)
+		}
+	}
+	return result, nil
+}
+
+// IngressRules returns the port ranges opened on the instances tagged
+// with fwname, along with the source CIDRs from which they may be
+// reached. Rules with the same port range are merged.
+func (gce Connection) IngressRules(fwname string) ([]network.IngressRule, error) {
+	ports, err := gce.Ports(fwname)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rules := network.IngressRulesForPorts(ports)
+
+	firewalls, err := gce.sourceFirewalls(fwname)
+	if err != nil {
+		return nil, errors.Annotate(err, "while getting ingress rules from GCE")
+	}
+	for _, firewall := range firewalls {
+		ports, err := firewallPorts(firewall)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, portRange := range ports {
+			rule, err := network.NewIngressRule(portRange, firewall.SourceRanges...)
+			if err != nil {
+				return nil, errors.Annotatef(err, "bad source ranges from GCE")
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return network.MergeIngressRules(rules), nil
+}
+
+// OpenIngressRules opens the rules' port ranges to their source CIDRs
+// on the instances tagged with fwname. The port ranges opened to each
+// CIDR are held in a separate firewall, created as needed.
+func (gce Connection) OpenIngressRules(fwname string, rules ...network.IngressRule) error {
+	byCIDR := portsByCIDR(rules)
+	for _, cidr := range sortedCIDRs(byCIDR) {
+		name := sourceFirewallName(fwname, cidr)
+		if err := gce.openPorts(name, fwname, cidr, byCIDR[cidr]); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// CloseIngressRules closes the rules' port ranges to their source CIDRs
+// on the instances tagged with fwname. Firewalls left with no port
+// ranges open are removed.
+func (gce Connection) CloseIngressRules(fwname string, rules ...network.IngressRule) error {
+	byCIDR := portsByCIDR(rules)
+	for _, cidr := range sortedCIDRs(byCIDR) {
+		name := sourceFirewallName(fwname, cidr)
+		if err := gce.closePorts(name, fwname, cidr, byCIDR[cidr]); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// portsByCIDR groups the rules' port ranges by source CIDR.
+func portsByCIDR(rules []network.IngressRule) map[string][]network.PortRange {
+	byCIDR := make(map[string][]network.PortRange)
+	for _, rule := range rules {
+		for _, cidr := range rule.SourceCIDRs {
+			byCIDR[cidr] = append(byCIDR[cidr], rule.PortRange)
+		}
+	}
+	return byCIDR
+}
+
+func sortedCIDRs(byCIDR map[string][]network.PortRange) []string {
+	var cidrs []string
+	for cidr := range byCIDR {
+		cidrs = append(cidrs, cidr)
+	}
+	sort.Strings(cidrs)
+	return cidrs
+}
@@ -5,6 +5,7 @@ package google_test
 
 import (
 	"sort"
+	"strings"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -12,6 +13,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/gce/google"
 )
 
 func (s *connSuite) TestConnectionPorts(c *gc.C) {
@@ -173,3 +175,131 @@ func (s *connSuite) TestConnectionClosePortsUpdate(c *gc.C) {
 		}},
 	})
 }
+
+func (s *connSuite) TestConnectionIngressRules(c *gc.C) {
+	s.FakeConn.Firewall = &compute.Firewall{
+		Name:         "spam",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"0.0.0.0/0"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"80-81"},
+		}},
+	}
+	s.FakeConn.Firewalls = []*compute.Firewall{{
+		Name:         "spam-from-93997fe8a8121085",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"10.0.0.0/8"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"22", "80-81"},
+		}},
+	}}
+
+	rules, err := s.Conn.IngressRules("spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(rules, jc.DeepEquals, []network.IngressRule{
+		network.MustNewIngressRule(network.MustParsePortRange("22/tcp"), "10.0.0.0/8"),
+		network.MustNewIngressRule(network.MustParsePortRange("80-81/tcp"), "0.0.0.0/0", "10.0.0.0/8"),
+	})
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetFirewall")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "ListFirewalls")
+	c.Check(s.FakeConn.Calls[1].Prefix, gc.Equals, "spam-from-")
+}
+
+func (s *connSuite) TestConnectionOpenIngressRules(c *gc.C) {
+	s.FakeConn.Err = errors.NotFoundf("spam")
+
+	rule := network.MustNewIngressRule(network.MustParsePortRange("443/tcp"), "10.0.0.0/8")
+	err := s.Conn.OpenIngressRules("spam", rule)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetFirewall")
+	c.Check(s.FakeConn.Calls[0].Name, gc.Equals, "spam-from-93997fe8a8121085")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "AddFirewall")
+	c.Check(s.FakeConn.Calls[1].Firewall, jc.DeepEquals, &compute.Firewall{
+		Name:         "spam-from-93997fe8a8121085",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"10.0.0.0/8"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"443"},
+		}},
+	})
+}
+
+func (s *connSuite) TestConnectionCloseIngressRules(c *gc.C) {
+	s.FakeConn.Firewall = &compute.Firewall{
+		Name:         "spam-from-93997fe8a8121085",
+		TargetTags:   []string{"spam"},
+		SourceRanges: []string{"10.0.0.0/8"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"443"},
+		}},
+	}
+
+	rule := network.MustNewIngressRule(network.MustParsePortRange("443/tcp"), "10.0.0.0/8")
+	err := s.Conn.CloseIngressRules("spam", rule)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetFirewall")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "RemoveFirewall")
+	c.Check(s.FakeConn.Calls[1].Name, gc.Equals, "spam-from-93997fe8a8121085")
+}
+
+func (s *connSuite) TestSourceFirewallName(c *gc.C) {
+	envName := "juju-2d02eeac-9dbb-11e4-89d3-123b93f75cba"
+	name := google.SourceFirewallName(envName, "10.0.0.0/8")
+	c.Check(name, gc.Equals, envName+"-from-93997fe8a8121085")
+	c.Check(name, gc.HasLen, 63)
+
+	c.Check(google.SourceFirewallName(envName, "0.0.0.0/0"), gc.Equals, envName)
+}
+
+func (s *connSuite) TestSourceFirewallNameFits(c *gc.C) {
+	envName := "juju-2d02eeac-9dbb-11e4-89d3-123b93f75cba"
+	cidrs := []string{
+		"10.0.0.0/8",
+		"10.0.0.1/32",
+		"2001:db8:85a3:8d3:1319:8a2e:370:7348/128",
+	}
+	for _, fwname := range []string{envName, envName + "-machine-0", envName + "-machine-1234567"} {
+		names := make(map[string]bool)
+		for _, cidr := range cidrs {
+			name := google.SourceFirewallName(fwname, cidr)
+			c.Logf("%s from %s: %s", fwname, cidr, name)
+			c.Check(len(name) <= 63, jc.IsTrue)
+			c.Check(name, gc.Matches, "[a-z]([-a-z0-9]*[a-z0-9])?")
+			c.Check(strings.HasPrefix(name, google.SourceFirewallPrefix(fwname)), jc.IsTrue)
+			names[name] = true
+		}
+		c.Check(names, gc.HasLen, len(cidrs))
+	}
+}
+
+func (s *connSuite) TestConnectionIngressRulesIgnoresOtherTargets(c *gc.C) {
+	// Long firewall names are truncated in the names of their
+	// source firewalls, which may then share a prefix.
+	fwname := "juju-2d02eeac-9dbb-11e4-89d3-123b93f75cba-machine-10"
+	s.FakeConn.Err = errors.NotFoundf(fwname)
+	s.FakeConn.Firewalls = []*compute.Firewall{{
+		Name:         google.SourceFirewallName(fwname+"0", "10.0.0.0/8"),
+		TargetTags:   []string{fwname + "0"},
+		SourceRanges: []string{"10.0.0.0/8"},
+		Allowed: []*compute.FirewallAllowed{{
+			IPProtocol: "tcp",
+			Ports:      []string{"22"},
+		}},
+	}}
+
+	rules, err := s.Conn.IngressRules(fwname)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(rules, gc.HasLen, 0)
+	c.Check(s.FakeConn.Calls[1].Prefix, gc.Equals, google.SourceFirewallPrefix(fwname+"0"))
+}
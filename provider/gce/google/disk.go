@@ -4,6 +4,9 @@
 package google
 
 import (
+	"fmt"
+	"path"
+
 	"google.golang.org/api/compute/v1"
 )
 
@@ -19,6 +22,25 @@ const (
 	diskModeRO = "READ_ONLY"
 )
 
+// The kinds of persistent disk supported by GCE.
+const (
+	DiskPersistentStandard = "pd-standard"
+	DiskPersistentSSD      = "pd-ssd"
+)
+
+// The statuses a persistent disk may have.
+const (
+	DiskStatusCreating  = "CREATING"
+	DiskStatusFailed    = "FAILED"
+	DiskStatusReady     = "READY"
+	DiskStatusRestoring = "RESTORING"
+)
+
+const (
+	partialDiskType = "zones/%s/diskTypes/%s"
+	partialDisk     = "projects/%s/zones/%s/disks/%s"
+)
+
 // MinDiskSizeGB is the minimum/default size (in megabytes) for
 // GCE disks.
 //
@@ -100,3 +122,91 @@ func (ds *DiskSpec) newAttached() *compute.AttachedDisk {
 	}
 	return &disk
 }
+
+// PersistentDiskSpec holds all the data needed to request a new
+// persistent disk that is not created along with an instance.
+type PersistentDiskSpec struct {
+	// Name is the name of the disk. It must be unique within the
+	// disk's zone.
+	Name string
+	// SizeHintGB is the requested disk size in Gigabytes. Disks
+	// smaller than MinDiskSizeGB are created with the minimum size.
+	SizeHintGB uint64
+	// Type is the kind of persistent disk, e.g. DiskPersistentSSD.
+	// If empty, DiskPersistentStandard is used.
+	Type string
+	// Description is a free-form description to record on the disk.
+	Description string
+}
+
+// SizeGB returns the disk size to use for a new disk.
+func (ds *PersistentDiskSpec) SizeGB() uint64 {
+	if ds.SizeHintGB < MinDiskSizeGB {
+		return MinDiskSizeGB
+	}
+	return ds.SizeHintGB
+}
+
+func (ds *PersistentDiskSpec) raw(zone string) *compute.Disk {
+	diskType := ds.Type
+	if diskType == "" {
+		diskType = DiskPersistentStandard
+	}
+	return &compute.Disk{
+		Name:        ds.Name,
+		Description: ds.Description,
+		SizeGb:      int64(ds.SizeGB()),
+		Type:        fmt.Sprintf(partialDiskType, zone, diskType),
+	}
+}
+
+// Disk represents a GCE persistent disk.
+type Disk struct {
+	// Name is the name of the disk.
+	Name string
+	// Zone is the name of the zone in which the disk lives.
+	Zone string
+	// SizeGB is the size of the disk in Gigabytes.
+	SizeGB uint64
+	// Type is the kind of persistent disk, e.g. DiskPersistentSSD.
+	Type string
+	// Status is the disk's status, one of the DiskStatus* values.
+	Status string
+	// Description is the description recorded on the disk.
+	Description string
+	// AttachedTo holds the names of the instances to which the disk
+	// is attached.
+	AttachedTo []string
+}
+
+func newDisk(raw *compute.Disk) *Disk {
+	disk := &Disk{
+		Name:        raw.Name,
+		Zone:        path.Base(raw.Zone),
+		SizeGB:      uint64(raw.SizeGb),
+		Type:        path.Base(raw.Type),
+		Status:      raw.Status,
+		Description: raw.Description,
+	}
+	for _, user := range raw.Users {
+		disk.AttachedTo = append(disk.AttachedTo, path.Base(user))
+	}
+	return disk
+}
+
+// newAttachedPersistent builds the compute.AttachedDisk used to
+// attach the named persistent disk to an instance. The disk's name
+// is used as the device name, so the disk will appear on the instance
+// as /dev/disk/by-id/google-<name>.
+func newAttachedPersistent(projectID, zone, name string, readOnly bool) *compute.AttachedDisk {
+	mode := diskModeRW
+	if readOnly {
+		mode = diskModeRO
+	}
+	return &compute.AttachedDisk{
+		Type:       diskTypePersistent,
+		Mode:       mode,
+		Source:     fmt.Sprintf(partialDisk, projectID, zone, name),
+		DeviceName: name,
+	}
+}
@@ -16,6 +16,9 @@ var (
 	FormatMachineType = formatMachineType
 	FirewallSpec      = firewallSpec
 	ExtractAddresses  = extractAddresses
+
+	SourceFirewallName   = sourceFirewallName
+	SourceFirewallPrefix = sourceFirewallPrefix
 )
 
 func SetRawConn(conn *Connection, raw rawConnectionWrapper) {
@@ -62,7 +62,7 @@ func (s *networkSuite) TestFirewallSpec(c *gc.C) {
 		network.MustParsePortRange("8888/tcp"),
 		network.MustParsePortRange("1234/udp"),
 	)
-	fw := google.FirewallSpec("spam", ports)
+	fw := google.FirewallSpec("spam", "spam", "0.0.0.0/0", ports)
 
 	allowed := []*compute.FirewallAllowed{{
 		IPProtocol: "tcp",
@@ -130,6 +130,16 @@ func (rc *rawConn) GetFirewall(projectID, name string) (*compute.Firewall, error
 	return firewallList.Items[0], nil
 }
 
+func (rc *rawConn) ListFirewalls(projectID, prefix string) ([]*compute.Firewall, error) {
+	call := rc.Firewalls.List(projectID)
+	call = call.Filter("name eq " + prefix + ".*")
+	firewallList, err := call.Do()
+	if err != nil {
+		return nil, errors.Annotate(err, "while listing firewalls from GCE")
+	}
+	return firewallList.Items, nil
+}
+
 func (rc *rawConn) AddFirewall(projectID string, firewall *compute.Firewall) error {
 	call := rc.Firewalls.Insert(projectID, firewall)
 	operation, err := call.Do()
@@ -187,6 +197,78 @@ func (rc *rawConn) ListAvailabilityZones(projectID, region string) ([]*compute.Z
 	return results, nil
 }
 
+func (rc *rawConn) GetDisk(projectID, zone, name string) (*compute.Disk, error) {
+	call := rc.Disks.Get(projectID, zone, name)
+	disk, err := call.Do()
+	return disk, errors.Trace(convertRawAPIError(err))
+}
+
+func (rc *rawConn) ListDisks(projectID, prefix string) ([]*compute.Disk, error) {
+	call := rc.Disks.AggregatedList(projectID)
+	call = call.Filter("name eq " + prefix + ".*")
+
+	var results []*compute.Disk
+	for {
+		rawResult, err := call.Do()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+
+		for _, diskList := range rawResult.Items {
+			results = append(results, diskList.Disks...)
+		}
+		if rawResult.NextPageToken == "" {
+			break
+		}
+		call = call.PageToken(rawResult.NextPageToken)
+	}
+	return results, nil
+}
+
+func (rc *rawConn) AddDisk(projectID, zone string, spec *compute.Disk) error {
+	call := rc.Disks.Insert(projectID, zone, spec)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Annotate(err, "sending new disk request")
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(err)
+}
+
+func (rc *rawConn) RemoveDisk(projectID, zone, name string) error {
+	call := rc.Disks.Delete(projectID, zone, name)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Trace(convertRawAPIError(err))
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(convertRawAPIError(err))
+}
+
+func (rc *rawConn) AttachDisk(projectID, zone, instanceID string, disk *compute.AttachedDisk) error {
+	call := rc.Instances.AttachDisk(projectID, zone, instanceID, disk)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsShort)
+	return errors.Trace(err)
+}
+
+func (rc *rawConn) DetachDisk(projectID, zone, instanceID, deviceName string) error {
+	call := rc.Instances.DetachDisk(projectID, zone, instanceID, deviceName)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Trace(convertRawAPIError(err))
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsShort)
+	return errors.Trace(err)
+}
+
 type waitError struct {
 	op    *compute.Operation
 	cause error
@@ -15,5 +15,6 @@ const (
 func init() {
 	environs.RegisterProvider(providerType, providerInstance)
 
-	registry.RegisterEnvironStorageProviders(providerType)
+	registry.RegisterProvider(storageProviderType, &storageProvider{})
+	registry.RegisterEnvironStorageProviders(providerType, storageProviderType)
 }
@@ -18,6 +18,7 @@ type environInstance struct {
 }
 
 var _ instance.Instance = (*environInstance)(nil)
+var _ instance.IngressRules = (*environInstance)(nil)
 
 func newInstance(base *google.Instance, env *environ) *environInstance {
 	return &environInstance{
@@ -87,3 +88,33 @@ func (inst *environInstance) Ports(machineID string) ([]network.PortRange, error
 	ports, err := env.gce.Ports(name)
 	return ports, errors.Trace(err)
 }
+
+// OpenIngressRules opens the given rules' port ranges to their source
+// CIDRs on the instance, which should have been started with the
+// given machine id.
+func (inst *environInstance) OpenIngressRules(machineID string, rules []network.IngressRule) error {
+	name := common.MachineFullName(inst.env, machineID)
+	env := inst.env.getSnapshot()
+	err := env.gce.OpenIngressRules(name, rules...)
+	return errors.Trace(err)
+}
+
+// CloseIngressRules closes the given rules' port ranges to their
+// source CIDRs on the instance, which should have been started with
+// the given machine id.
+func (inst *environInstance) CloseIngressRules(machineID string, rules []network.IngressRule) error {
+	name := common.MachineFullName(inst.env, machineID)
+	env := inst.env.getSnapshot()
+	err := env.gce.CloseIngressRules(name, rules...)
+	return errors.Trace(err)
+}
+
+// IngressRules returns the port ranges open on the instance, which
+// should have been started with the given machine id, along with the
+// source CIDRs from which they may be reached.
+func (inst *environInstance) IngressRules(machineID string) ([]network.IngressRule, error) {
+	name := common.MachineFullName(inst.env, machineID)
+	env := inst.env.getSnapshot()
+	rules, err := env.gce.IngressRules(name)
+	return rules, errors.Trace(err)
+}
@@ -421,7 +421,10 @@ type fakeConnCall struct {
 	InstanceSpec google.InstanceSpec
 	FirewallName string
 	PortRanges   []network.PortRange
+	Rules        []network.IngressRule
 	Region       string
+	DiskSpec     google.PersistentDiskSpec
+	ReadOnly     bool
 }
 
 type fakeConn struct {
@@ -430,7 +433,10 @@ type fakeConn struct {
 	Inst       *google.Instance
 	Insts      []google.Instance
 	PortRanges []network.PortRange
+	Rules      []network.IngressRule
 	Zones      []google.AvailabilityZone
+	DiskValue  *google.Disk
+	DiskValues []*google.Disk
 	Err        error
 	FailOnCall int
 }
@@ -511,6 +517,32 @@ func (fc *fakeConn) ClosePorts(fwname string, ports ...network.PortRange) error
 	return fc.err()
 }
 
+func (fc *fakeConn) IngressRules(fwname string) ([]network.IngressRule, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName:     "IngressRules",
+		FirewallName: fwname,
+	})
+	return fc.Rules, fc.err()
+}
+
+func (fc *fakeConn) OpenIngressRules(fwname string, rules ...network.IngressRule) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName:     "OpenIngressRules",
+		FirewallName: fwname,
+		Rules:        rules,
+	})
+	return fc.err()
+}
+
+func (fc *fakeConn) CloseIngressRules(fwname string, rules ...network.IngressRule) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName:     "CloseIngressRules",
+		FirewallName: fwname,
+		Rules:        rules,
+	})
+	return fc.err()
+}
+
 func (fc *fakeConn) AvailabilityZones(region string) ([]google.AvailabilityZone, error) {
 	fc.Calls = append(fc.Calls, fakeConnCall{
 		FuncName: "AvailabilityZones",
@@ -519,6 +551,62 @@ func (fc *fakeConn) AvailabilityZones(region string) ([]google.AvailabilityZone,
 	return fc.Zones, fc.err()
 }
 
+func (fc *fakeConn) CreateDisk(zone string, spec google.PersistentDiskSpec) (*google.Disk, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "CreateDisk",
+		ZoneName: zone,
+		DiskSpec: spec,
+	})
+	return fc.DiskValue, fc.err()
+}
+
+func (fc *fakeConn) Disk(zone, name string) (*google.Disk, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "Disk",
+		ZoneName: zone,
+		ID:       name,
+	})
+	return fc.DiskValue, fc.err()
+}
+
+func (fc *fakeConn) Disks(prefix string) ([]*google.Disk, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "Disks",
+		Prefix:   prefix,
+	})
+	return fc.DiskValues, fc.err()
+}
+
+func (fc *fakeConn) RemoveDisk(zone, name string) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "RemoveDisk",
+		ZoneName: zone,
+		ID:       name,
+	})
+	return fc.err()
+}
+
+func (fc *fakeConn) AttachDisk(zone, name, instanceID string, readOnly bool) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "AttachDisk",
+		ZoneName: zone,
+		ID:       name,
+		IDs:      []string{instanceID},
+		ReadOnly: readOnly,
+	})
+	return fc.err()
+}
+
+func (fc *fakeConn) DetachDisk(zone, name, instanceID string) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "DetachDisk",
+		ZoneName: zone,
+		ID:       name,
+		IDs:      []string{instanceID},
+	})
+	return fc.err()
+}
+
 func (fc *fakeConn) WasCalled(funcName string) (bool, []fakeConnCall) {
 	var calls []fakeConnCall
 	called := false
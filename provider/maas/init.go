@@ -5,6 +5,7 @@ package maas
 
 import (
 	"github.com/juju/juju/environs"
+	storageprovider "github.com/juju/juju/storage/provider"
 	"github.com/juju/juju/storage/provider/registry"
 )
 
@@ -18,5 +19,9 @@ func init() {
 	//Register the MAAS specific storage providers.
 	registry.RegisterProvider(maasStorageProviderType, &maasStorageProvider{})
 
-	registry.RegisterEnvironStorageProviders(providerType, maasStorageProviderType)
+	registry.RegisterEnvironStorageProviders(
+		providerType,
+		maasStorageProviderType,
+		storageprovider.LVMProviderType,
+	)
 }
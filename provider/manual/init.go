@@ -5,6 +5,7 @@ package manual
 
 import (
 	"github.com/juju/juju/environs"
+	storageprovider "github.com/juju/juju/storage/provider"
 	"github.com/juju/juju/storage/provider/registry"
 )
 
@@ -16,5 +17,5 @@ func init() {
 	p := manualProvider{}
 	environs.RegisterProvider(providerType, p, "null")
 
-	registry.RegisterEnvironStorageProviders(providerType)
+	registry.RegisterEnvironStorageProviders(providerType, storageprovider.LVMProviderType)
 }
@@ -367,6 +367,7 @@ var _ simplestreams.HasRegion = (*environ)(nil)
 var _ state.Prechecker = (*environ)(nil)
 var _ state.InstanceDistributor = (*environ)(nil)
 var _ environs.InstanceTagger = (*environ)(nil)
+var _ environs.IngressRulesEnviron = (*environ)(nil)
 
 type openstackInstance struct {
 	e        *environ
@@ -384,6 +385,7 @@ func (inst *openstackInstance) String() string {
 }
 
 var _ instance.Instance = (*openstackInstance)(nil)
+var _ instance.IngressRules = (*openstackInstance)(nil)
 
 func (inst *openstackInstance) Refresh() error {
 	inst.mu.Lock()
@@ -501,42 +503,61 @@ func convertNovaAddresses(publicIP string, addresses map[string][]nova.IPAddress
 // TODO: following 30 lines nearly verbatim from environs/ec2
 
 func (inst *openstackInstance) OpenPorts(machineId string, ports []network.PortRange) error {
+	return inst.OpenIngressRules(machineId, network.IngressRulesForPorts(ports))
+}
+
+func (inst *openstackInstance) ClosePorts(machineId string, ports []network.PortRange) error {
+	return inst.CloseIngressRules(machineId, network.IngressRulesForPorts(ports))
+}
+
+func (inst *openstackInstance) Ports(machineId string) ([]network.PortRange, error) {
+	if inst.e.Config().FirewallMode() != config.FwInstance {
+		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from instance",
+			inst.e.Config().FirewallMode())
+	}
+	name := inst.e.machineGroupName(machineId)
+	portRanges, err := inst.e.portsInGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	return portRanges, nil
+}
+
+// OpenIngressRules is specified on instance.IngressRules.
+func (inst *openstackInstance) OpenIngressRules(machineId string, rules []network.IngressRule) error {
 	if inst.e.Config().FirewallMode() != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on instance",
 			inst.e.Config().FirewallMode())
 	}
 	name := inst.e.machineGroupName(machineId)
-	if err := inst.e.openPortsInGroup(name, ports); err != nil {
+	if err := inst.e.openRulesInGroup(name, rules); err != nil {
 		return err
 	}
-	logger.Infof("opened ports in security group %s: %v", name, ports)
+	logger.Infof("opened ports in security group %s: %v", name, rules)
 	return nil
 }
 
-func (inst *openstackInstance) ClosePorts(machineId string, ports []network.PortRange) error {
+// CloseIngressRules is specified on instance.IngressRules.
+func (inst *openstackInstance) CloseIngressRules(machineId string, rules []network.IngressRule) error {
 	if inst.e.Config().FirewallMode() != config.FwInstance {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on instance",
 			inst.e.Config().FirewallMode())
 	}
 	name := inst.e.machineGroupName(machineId)
-	if err := inst.e.closePortsInGroup(name, ports); err != nil {
+	if err := inst.e.closeRulesInGroup(name, rules); err != nil {
 		return err
 	}
-	logger.Infof("closed ports in security group %s: %v", name, ports)
+	logger.Infof("closed ports in security group %s: %v", name, rules)
 	return nil
 }
 
-func (inst *openstackInstance) Ports(machineId string) ([]network.PortRange, error) {
+// IngressRules is specified on instance.IngressRules.
+func (inst *openstackInstance) IngressRules(machineId string) ([]network.IngressRule, error) {
 	if inst.e.Config().FirewallMode() != config.FwInstance {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from instance",
 			inst.e.Config().FirewallMode())
 	}
-	name := inst.e.machineGroupName(machineId)
-	portRanges, err := inst.e.portsInGroup(name)
-	if err != nil {
-		return nil, err
-	}
-	return portRanges, nil
+	return inst.e.rulesInGroup(inst.e.machineGroupName(machineId))
 }
 
 func (e *environ) ecfg() *environConfig {
@@ -1355,29 +1376,31 @@ func (e *environ) machinesFilter() *nova.Filter {
 	return filter
 }
 
-// portsToRuleInfo maps port ranges to nova rules
-func portsToRuleInfo(groupId string, ports []network.PortRange) []nova.RuleInfo {
-	rules := make([]nova.RuleInfo, len(ports))
-	for i, portRange := range ports {
-		rules[i] = nova.RuleInfo{
-			ParentGroupId: groupId,
-			FromPort:      portRange.FromPort,
-			ToPort:        portRange.ToPort,
-			IPProtocol:    portRange.Protocol,
-			Cidr:          "0.0.0.0/0",
+// rulesToRuleInfo maps ingress rules to nova rules, one for each
+// source CIDR of each rule.
+func rulesToRuleInfo(groupId string, rules []network.IngressRule) []nova.RuleInfo {
+	var ruleInfos []nova.RuleInfo
+	for _, rule := range rules {
+		for _, cidr := range rule.SourceCIDRs {
+			ruleInfos = append(ruleInfos, nova.RuleInfo{
+				ParentGroupId: groupId,
+				FromPort:      rule.FromPort,
+				ToPort:        rule.ToPort,
+				IPProtocol:    rule.Protocol,
+				Cidr:          cidr,
+			})
 		}
 	}
-	return rules
+	return ruleInfos
 }
 
-func (e *environ) openPortsInGroup(name string, portRanges []network.PortRange) error {
+func (e *environ) openRulesInGroup(name string, rules []network.IngressRule) error {
 	novaclient := e.nova()
 	group, err := novaclient.SecurityGroupByName(name)
 	if err != nil {
 		return err
 	}
-	rules := portsToRuleInfo(group.Id, portRanges)
-	for _, rule := range rules {
+	for _, rule := range rulesToRuleInfo(group.Id, rules) {
 		_, err := novaclient.CreateSecurityGroupRule(rule)
 		if err != nil {
 			// TODO: if err is not rule already exists, raise?
@@ -1397,8 +1420,17 @@ func ruleMatchesPortRange(rule nova.SecurityGroupRule, portRange network.PortRan
 		*rule.ToPort == portRange.ToPort
 }
 
-func (e *environ) closePortsInGroup(name string, portRanges []network.PortRange) error {
-	if len(portRanges) == 0 {
+// ruleCIDR returns the source CIDR of the supplied nova security group
+// rule. Rules without a CIDR allow access from any address.
+func ruleCIDR(rule nova.SecurityGroupRule) string {
+	if cidr := rule.IPRange["cidr"]; cidr != "" {
+		return cidr
+	}
+	return network.AnyCIDR
+}
+
+func (e *environ) closeRulesInGroup(name string, rules []network.IngressRule) error {
+	if len(rules) == 0 {
 		return nil
 	}
 	novaclient := e.nova()
@@ -1407,33 +1439,54 @@ func (e *environ) closePortsInGroup(name string, portRanges []network.PortRange)
 		return err
 	}
 	// TODO: Hey look ma, it's quadratic
-	for _, portRange := range portRanges {
-		for _, p := range (*group).Rules {
-			if !ruleMatchesPortRange(p, portRange) {
-				continue
-			}
-			err := novaclient.DeleteSecurityGroupRule(p.Id)
-			if err != nil {
-				return err
+	for _, rule := range rules {
+		for _, cidr := range rule.SourceCIDRs {
+			for _, p := range (*group).Rules {
+				if !ruleMatchesPortRange(p, rule.PortRange) || ruleCIDR(p) != cidr {
+					continue
+				}
+				err := novaclient.DeleteSecurityGroupRule(p.Id)
+				if err != nil {
+					return err
+				}
+				break
 			}
-			break
 		}
 	}
 	return nil
 }
 
-func (e *environ) portsInGroup(name string) (portRanges []network.PortRange, err error) {
+func (e *environ) rulesInGroup(name string) ([]network.IngressRule, error) {
 	group, err := e.nova().SecurityGroupByName(name)
 	if err != nil {
 		return nil, err
 	}
+	var rules []network.IngressRule
 	for _, p := range (*group).Rules {
-		portRanges = append(portRanges, network.PortRange{
-			Protocol: *p.IPProtocol,
-			FromPort: *p.FromPort,
-			ToPort:   *p.ToPort,
+		rules = append(rules, network.IngressRule{
+			PortRange: network.PortRange{
+				Protocol: *p.IPProtocol,
+				FromPort: *p.FromPort,
+				ToPort:   *p.ToPort,
+			},
+			SourceCIDRs: []string{ruleCIDR(p)},
 		})
 	}
+	return network.MergeIngressRules(rules), nil
+}
+
+// portsInGroup returns the port ranges opened to any address in the
+// named group.
+func (e *environ) portsInGroup(name string) (portRanges []network.PortRange, err error) {
+	rules, err := e.rulesInGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule.IsAnySource() {
+			portRanges = append(portRanges, rule.PortRange)
+		}
+	}
 	network.SortPortRanges(portRanges)
 	return portRanges, nil
 }
@@ -1441,35 +1494,54 @@ func (e *environ) portsInGroup(name string) (portRanges []network.PortRange, err
 // TODO: following 30 lines nearly verbatim from environs/ec2
 
 func (e *environ) OpenPorts(ports []network.PortRange) error {
+	return e.OpenIngressRules(network.IngressRulesForPorts(ports))
+}
+
+func (e *environ) ClosePorts(ports []network.PortRange) error {
+	return e.CloseIngressRules(network.IngressRulesForPorts(ports))
+}
+
+func (e *environ) Ports() ([]network.PortRange, error) {
+	if e.Config().FirewallMode() != config.FwGlobal {
+		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from environment",
+			e.Config().FirewallMode())
+	}
+	return e.portsInGroup(e.globalGroupName())
+}
+
+// OpenIngressRules is specified on environs.IngressRules.
+func (e *environ) OpenIngressRules(rules []network.IngressRule) error {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for opening ports on environment",
 			e.Config().FirewallMode())
 	}
-	if err := e.openPortsInGroup(e.globalGroupName(), ports); err != nil {
+	if err := e.openRulesInGroup(e.globalGroupName(), rules); err != nil {
 		return err
 	}
-	logger.Infof("opened ports in global group: %v", ports)
+	logger.Infof("opened ports in global group: %v", rules)
 	return nil
 }
 
-func (e *environ) ClosePorts(ports []network.PortRange) error {
+// CloseIngressRules is specified on environs.IngressRules.
+func (e *environ) CloseIngressRules(rules []network.IngressRule) error {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return fmt.Errorf("invalid firewall mode %q for closing ports on environment",
 			e.Config().FirewallMode())
 	}
-	if err := e.closePortsInGroup(e.globalGroupName(), ports); err != nil {
+	if err := e.closeRulesInGroup(e.globalGroupName(), rules); err != nil {
 		return err
 	}
-	logger.Infof("closed ports in global group: %v", ports)
+	logger.Infof("closed ports in global group: %v", rules)
 	return nil
 }
 
-func (e *environ) Ports() ([]network.PortRange, error) {
+// IngressRules is specified on environs.IngressRules.
+func (e *environ) IngressRules() ([]network.IngressRule, error) {
 	if e.Config().FirewallMode() != config.FwGlobal {
 		return nil, fmt.Errorf("invalid firewall mode %q for retrieving ports from environment",
 			e.Config().FirewallMode())
 	}
-	return e.portsInGroup(e.globalGroupName())
+	return e.rulesInGroup(e.globalGroupName())
 }
 
 func (e *environ) Provider() environs.EnvironProvider {
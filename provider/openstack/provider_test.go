@@ -211,7 +211,7 @@ func (t *localTests) TestGetServerAddresses(c *gc.C) {
 	}
 }
 
-func (*localTests) TestPortsToRuleInfo(c *gc.C) {
+func (*localTests) TestRulesToRuleInfo(c *gc.C) {
 	groupId := "groupid"
 	testCases := []struct {
 		about    string
@@ -273,10 +273,28 @@ func (*localTests) TestPortsToRuleInfo(c *gc.C) {
 
 	for i, t := range testCases {
 		c.Logf("test %d: %s", i, t.about)
-		rules := openstack.PortsToRuleInfo(groupId, t.ports)
+		rules := openstack.RulesToRuleInfo(groupId, network.IngressRulesForPorts(t.ports))
 		c.Check(len(rules), gc.Equals, len(t.expected))
 		c.Check(rules, gc.DeepEquals, t.expected)
 	}
+
+	// A rule is created for each source CIDR.
+	rules := openstack.RulesToRuleInfo(groupId, []network.IngressRule{
+		network.MustNewIngressRule(network.PortRange{80, 80, "tcp"}, "10.0.0.0/8", "192.168.0.0/16"),
+	})
+	c.Check(rules, gc.DeepEquals, []nova.RuleInfo{{
+		IPProtocol:    "tcp",
+		FromPort:      80,
+		ToPort:        80,
+		Cidr:          "10.0.0.0/8",
+		ParentGroupId: groupId,
+	}, {
+		IPProtocol:    "tcp",
+		FromPort:      80,
+		ToPort:        80,
+		Cidr:          "192.168.0.0/16",
+		ParentGroupId: groupId,
+	}})
 }
 
 func (*localTests) TestRuleMatchesPortRange(c *gc.C) {
@@ -6,6 +6,8 @@ package rpc
 import (
 	"errors"
 	"strings"
+
+	"github.com/juju/juju/tracing"
 )
 
 var ErrShutdown = errors.New("connection is shut down")
@@ -17,6 +19,10 @@ type Call struct {
 	Response interface{}
 	Error    error
 	Done     chan *Call
+
+	// span records the call if it is made on behalf of the span
+	// bound to the calling goroutine.
+	span *tracing.Span
 }
 
 // RequestError represents an error returned from an RPC request.
@@ -62,6 +68,9 @@ func (conn *Conn) send(call *Call) {
 		RequestId: reqId,
 		Request:   call.Request,
 	}
+	if call.span != nil {
+		hdr.TraceContext = call.span.Context().TraceParent()
+	}
 	params := call.Params
 	if params == nil {
 		params = struct{}{}
@@ -128,6 +137,8 @@ func (conn *Conn) handleResponse(hdr *Header) error {
 }
 
 func (call *Call) done() {
+	call.span.SetError(call.Error)
+	call.span.End()
 	select {
 	case call.Done <- call:
 		// ok
@@ -145,7 +156,15 @@ func (call *Call) done() {
 // no parameters are provided; the response value may be nil to indicate
 // that any result should be discarded.
 func (conn *Conn) Call(req Request, params, response interface{}) error {
-	call := <-conn.Go(req, params, response, make(chan *Call, 1)).Done
+	return conn.CallTraced(nil, req, params, response)
+}
+
+// CallTraced is like Call, but records the call as a child of the
+// given span, and sends the span's context to the server so that the
+// work done on its behalf is recorded in the same trace. The call is
+// not traced if span is nil.
+func (conn *Conn) CallTraced(span *tracing.Span, req Request, params, response interface{}) error {
+	call := <-conn.GoTraced(span, req, params, response, make(chan *Call, 1)).Done
 	return call.Error
 }
 
@@ -154,6 +173,12 @@ func (conn *Conn) Call(req Request, params, response interface{}) error {
 // the same Call object.  If done is nil, Go will allocate a new channel.
 // If non-nil, done must be buffered or Go will deliberately panic.
 func (conn *Conn) Go(req Request, args, response interface{}, done chan *Call) *Call {
+	return conn.GoTraced(nil, req, args, response, done)
+}
+
+// GoTraced is like Go, but traces the call on behalf of the given
+// span as described for CallTraced.
+func (conn *Conn) GoTraced(span *tracing.Span, req Request, args, response interface{}, done chan *Call) *Call {
 	if done == nil {
 		done = make(chan *Call, 1)
 	} else {
@@ -170,7 +195,9 @@ func (conn *Conn) Go(req Request, args, response interface{}, done chan *Call) *
 		Params:   args,
 		Response: response,
 		Done:     done,
+		span:     span.Child(spanName(req), tracing.SpanKindClient),
 	}
+	setSpanAttributes(call.span, req)
 	conn.send(call)
 	return call
 }
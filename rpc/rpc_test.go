@@ -6,7 +6,9 @@ package rpc_test
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"sync"
@@ -21,6 +23,7 @@ import (
 	"github.com/juju/juju/rpc/jsoncodec"
 	"github.com/juju/juju/rpc/rpcreflect"
 	"github.com/juju/juju/testing"
+	"github.com/juju/juju/tracing"
 )
 
 var logger = loggo.GetLogger("juju.rpc")
@@ -59,6 +62,12 @@ type Root struct {
 	simple    map[string]*SimpleMethods
 	delayed   map[string]*DelayedMethods
 	errorInst *ErrorMethods
+
+	// tracer is set on the server connection serving the root.
+	tracer *tracing.Tracer
+	// traced holds the context of the span passed with the
+	// last call made through a TracingMethodFinder.
+	traced tracing.SpanContext
 }
 
 func (r *Root) callError(rcvr interface{}, name string, arg interface{}) error {
@@ -99,6 +108,10 @@ func (r *Root) Discard2(id string) error { return nil }
 
 func (r *Root) Discard3(id string) int { return 0 }
 
+func (r *Root) TracedMethods(string) (*TracedMethods, error) {
+	return &TracedMethods{}, nil
+}
+
 func (r *Root) CallbackMethods(string) (*CallbackMethods, error) {
 	return &CallbackMethods{r}, nil
 }
@@ -219,6 +232,10 @@ func (e *ErrorMethods) Call() error {
 	return e.err
 }
 
+type TracedMethods struct{}
+
+func (t *TracedMethods) Call() {}
+
 type CallbackMethods struct {
 	root *Root
 }
@@ -367,6 +384,37 @@ func (cc *CustomMethodFinder) FindMethod(
 	}, nil
 }
 
+// TracingMethodFinder finds the methods of root, returning callers
+// that record in root the span passed with each call.
+type TracingMethodFinder struct {
+	root *Root
+}
+
+func (f *TracingMethodFinder) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := rpcreflect.ValueOf(reflect.ValueOf(f.root)).FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	return &tracingMethodCaller{MethodCaller: caller, root: f.root}, nil
+}
+
+type tracingMethodCaller struct {
+	rpcreflect.MethodCaller
+	root *Root
+	span *tracing.Span
+}
+
+func (c *tracingMethodCaller) WithSpan(span *tracing.Span) rpcreflect.MethodCaller {
+	return &tracingMethodCaller{MethodCaller: c.MethodCaller, root: c.root, span: span}
+}
+
+func (c *tracingMethodCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	c.root.mu.Lock()
+	c.root.traced = c.span.Context()
+	c.root.mu.Unlock()
+	return c.MethodCaller.Call(objId, arg)
+}
+
 func SimpleRoot() *Root {
 	root := &Root{
 		simple: make(map[string]*SimpleMethods),
@@ -773,6 +821,64 @@ func (*rpcSuite) TestErrorCode(c *gc.C) {
 	c.Assert(err.(rpc.ErrorCoder).ErrorCode(), gc.Equals, "code")
 }
 
+func (*rpcSuite) TestTraceContextPropagated(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "traces.json")
+	tracer, err := tracing.NewTracer("juju", path)
+	c.Assert(err, jc.ErrorIsNil)
+	root := &Root{tracer: tracer}
+	client, srvDone, clientNotifier, _ := newRPCClientServer(c, &TracingMethodFinder{root}, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	// Calls made without a span are not traced.
+	err = client.Call(rpc.Request{"TracedMethods", 0, "", "Call"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(root.traced, gc.Equals, tracing.SpanContext{})
+	c.Assert(clientNotifier.clientRequests[0].hdr.TraceContext, gc.Equals, "")
+
+	span := tracer.StartSpan("juju test", tracing.SpanKindInternal, tracing.SpanContext{})
+	err = client.CallTraced(span, rpc.Request{"TracedMethods", 0, "", "Call"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	span.End()
+
+	// The server span was passed to the method caller, and the
+	// client sent the context of its own span.
+	root.mu.Lock()
+	serverContext := root.traced
+	root.mu.Unlock()
+	c.Assert(serverContext.TraceID, gc.Equals, span.Context().TraceID)
+	clientContext, err := tracing.ParseTraceParent(clientNotifier.clientRequests[1].hdr.TraceContext)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(clientContext.TraceID, gc.Equals, span.Context().TraceID)
+
+	err = tracer.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	var exported struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					SpanID       string `json:"spanId"`
+					ParentSpanID string `json:"parentSpanId"`
+					Name         string `json:"name"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	err = json.Unmarshal(data, &exported)
+	c.Assert(err, jc.ErrorIsNil)
+	parents := make(map[string]string)
+	for _, s := range exported.ResourceSpans[0].ScopeSpans[0].Spans {
+		c.Check(s.Name, gc.Matches, "juju test|TracedMethods.Call")
+		parents[s.SpanID] = s.ParentSpanID
+	}
+	c.Assert(parents, jc.DeepEquals, map[string]string{
+		span.Context().SpanID.String(): "",
+		clientContext.SpanID.String():  span.Context().SpanID.String(),
+		serverContext.SpanID.String():  clientContext.SpanID.String(),
+	})
+}
+
 func (*rpcSuite) TestTransformErrors(c *gc.C) {
 	root := &Root{
 		errorInst: &ErrorMethods{&codedError{"message", "code"}},
@@ -1203,11 +1309,16 @@ func newRPCClientServer(c *gc.C, root interface{}, tfErr func(error) error, bidi
 		if custroot, ok := root.(*CustomMethodFinder); ok {
 			rpcConn.ServeFinder(custroot, tfErr)
 			custroot.root.conn = rpcConn
+		} else if finder, ok := root.(*TracingMethodFinder); ok {
+			rpcConn.ServeFinder(finder, tfErr)
+			finder.root.conn = rpcConn
+			rpcConn.SetTracer(finder.root.tracer)
 		} else {
 			rpcConn.Serve(root, tfErr)
 		}
 		if root, ok := root.(*Root); ok {
 			root.conn = rpcConn
+			rpcConn.SetTracer(root.tracer)
 		}
 		rpcConn.Start()
 		<-rpcConn.Dead()
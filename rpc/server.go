@@ -13,6 +13,7 @@ import (
 	"github.com/juju/loggo"
 
 	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/tracing"
 )
 
 const CodeNotImplemented = "not implemented"
@@ -62,6 +63,11 @@ type Header struct {
 
 	// ErrorCode holds the code of the error, if any.
 	ErrorCode string
+
+	// TraceContext holds the context, in the W3C traceparent
+	// format, of the span on whose behalf a request is made. It
+	// is empty if the request is not being traced.
+	TraceContext string
 }
 
 // Request represents an RPC to be performed, absent its parameters.
@@ -124,6 +130,10 @@ type Conn struct {
 	// transformErrors is used to transform returned errors.
 	transformErrors func(error) error
 
+	// tracer records spans for the server requests made on behalf
+	// of traced client requests. It may be nil.
+	tracer *tracing.Tracer
+
 	// reqId holds the latest client request id.
 	reqId uint64
 
@@ -363,6 +373,29 @@ type MethodFinder interface {
 	FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error)
 }
 
+// TracingMethodCaller represents a MethodCaller that can record the
+// work done by its calls on behalf of the span of a traced request.
+type TracingMethodCaller interface {
+	rpcreflect.MethodCaller
+
+	// WithSpan returns a MethodCaller that makes the same calls,
+	// recording their work as children of the given span.
+	WithSpan(span *tracing.Span) rpcreflect.MethodCaller
+}
+
+// CallerWithSpan returns a MethodCaller that makes the same calls as
+// caller on behalf of the given span. It returns caller itself if
+// span is nil or caller does not implement TracingMethodCaller.
+func CallerWithSpan(caller rpcreflect.MethodCaller, span *tracing.Span) rpcreflect.MethodCaller {
+	if span == nil {
+		return caller
+	}
+	if tracingCaller, ok := caller.(TracingMethodCaller); ok {
+		return tracingCaller.WithSpan(span)
+	}
+	return caller
+}
+
 // Killer represents a type that can be asked to abort any outstanding
 // requests.  The Kill method should return immediately.
 type Killer interface {
@@ -549,7 +582,10 @@ func (conn *Conn) bindRequest(hdr *Header) (boundRequest, error) {
 // runRequest runs the given request and sends the reply.
 func (conn *Conn) runRequest(req boundRequest, arg reflect.Value, startTime time.Time) {
 	defer conn.srvPending.Done()
-	rv, err := req.Call(req.hdr.Request.Id, arg)
+	span := conn.startServerSpan(&req.hdr)
+	rv, err := CallerWithSpan(req.MethodCaller, span).Call(req.hdr.Request.Id, arg)
+	span.SetError(err)
+	span.End()
 	if err != nil {
 		err = conn.writeErrorResponse(&req.hdr, req.transformErrors(err), startTime)
 	} else {
@@ -574,6 +610,55 @@ func (conn *Conn) runRequest(req boundRequest, arg reflect.Value, startTime time
 	}
 }
 
+// SetTracer sets the tracer used to record the server requests made
+// on behalf of traced client requests. The span of each such request
+// is passed to its method caller if the caller implements
+// TracingMethodCaller. If tracer is nil, requests are not traced.
+func (conn *Conn) SetTracer(tracer *tracing.Tracer) {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	conn.tracer = tracer
+}
+
+// startServerSpan starts the span recording the server request with
+// the given header. It returns nil if the request is not being traced.
+func (conn *Conn) startServerSpan(hdr *Header) *tracing.Span {
+	if hdr.TraceContext == "" {
+		return nil
+	}
+	conn.mutex.Lock()
+	tracer := conn.tracer
+	conn.mutex.Unlock()
+	if tracer == nil {
+		return nil
+	}
+	parent, err := tracing.ParseTraceParent(hdr.TraceContext)
+	if err != nil {
+		logger.Debugf("not tracing request: %v", err)
+		return nil
+	}
+	span := tracer.StartSpan(spanName(hdr.Request), tracing.SpanKindServer, parent)
+	setSpanAttributes(span, hdr.Request)
+	return span
+}
+
+// spanName returns the name of the spans recording the given request.
+func spanName(req Request) string {
+	return req.Type + "." + req.Action
+}
+
+// setSpanAttributes records the details of the given request in span,
+// using the OpenTelemetry conventions for RPC spans.
+func setSpanAttributes(span *tracing.Span, req Request) {
+	span.SetAttribute("rpc.system", "juju")
+	span.SetAttribute("rpc.service", req.Type)
+	span.SetAttribute("rpc.method", req.Action)
+	span.SetAttribute("rpc.juju.version", req.Version)
+	if req.Id != "" {
+		span.SetAttribute("rpc.juju.id", req.Id)
+	}
+}
+
 type serverError RequestError
 
 func (e *serverError) Error() string {
@@ -99,6 +99,14 @@ type actionDoc struct {
 
 	// Results are the structured results from the action.
 	Results map[string]interface{} `bson:"results"`
+
+	// Operation is the id of the ActionOperation that queued this
+	// action, if any.
+	Operation string `bson:"operation,omitempty"`
+
+	// Timeout is how long the action may run before the unit kills
+	// it; zero means no limit.
+	Timeout time.Duration `bson:"timeout,omitempty"`
 }
 
 // Action represents an instruction to do some "action" and is expected
@@ -131,6 +139,18 @@ func (a *Action) Parameters() map[string]interface{} {
 	return a.doc.Parameters
 }
 
+// Operation returns the id of the ActionOperation that queued this
+// action, or an empty string if it was queued by itself.
+func (a *Action) Operation() string {
+	return a.doc.Operation
+}
+
+// Timeout returns how long the action may run before it is killed, or
+// zero if it may run indefinitely.
+func (a *Action) Timeout() time.Duration {
+	return a.doc.Timeout
+}
+
 // Enqueued returns the time the action was added to state as a pending
 // Action.
 func (a *Action) Enqueued() time.Time {
@@ -205,7 +225,18 @@ func (a *Action) Begin() (*Action, error) {
 // Finish removes action from the pending queue and captures the output
 // and end state of the action.
 func (a *Action) Finish(results ActionResults) (*Action, error) {
-	return a.removeAndLog(results.Status, results.Results, results.Message)
+	finished, err := a.removeAndLog(results.Status, results.Results, results.Message)
+	if err != nil {
+		return nil, err
+	}
+	if a.doc.Operation != "" {
+		// Let the operation queue its next actions now, rather than
+		// waiting for the scheduler to notice.
+		if err := a.st.advanceActionOperation(a.doc.Operation); err != nil {
+			actionLogger.Errorf("cannot advance action operation %q: %v", a.doc.Operation, err)
+		}
+	}
+	return finished, nil
 }
 
 // removeAndLog takes the action off of the pending queue, and creates
@@ -329,11 +360,22 @@ func (st *State) FindActionTagsByPrefix(prefix string) []names.ActionTag {
 	return results
 }
 
-// EnqueueAction
+// EnqueueAction queues an action with the given name and payload for
+// the given receiver.
 func (st *State) EnqueueAction(receiver names.Tag, actionName string, payload map[string]interface{}) (*Action, error) {
+	return st.EnqueueActionWithTimeout(receiver, actionName, payload, 0)
+}
+
+// EnqueueActionWithTimeout queues an action with the given name and
+// payload for the given receiver, which will kill the action if it
+// runs for longer than timeout. A zero timeout means no limit.
+func (st *State) EnqueueActionWithTimeout(receiver names.Tag, actionName string, payload map[string]interface{}, timeout time.Duration) (*Action, error) {
 	if len(actionName) == 0 {
 		return nil, errors.New("action name required")
 	}
+	if timeout < 0 {
+		return nil, errors.NotValidf("timeout %v", timeout)
+	}
 
 	receiverCollectionName, receiverId, err := st.tagToCollectionAndId(receiver)
 	if err != nil {
@@ -344,6 +386,7 @@ func (st *State) EnqueueAction(receiver names.Tag, actionName string, payload ma
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	doc.Timeout = timeout
 
 	ops := []txn.Op{{
 		C:      receiverCollectionName,
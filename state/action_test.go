@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -301,6 +302,23 @@ func (s *ActionSuite) TestEnqueueActionRequiresName(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "action name required")
 }
 
+func (s *ActionSuite) TestAddActionWithTimeout(c *gc.C) {
+	action, err := s.unit.AddActionWithTimeout("snapshot", nil, 5*time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Timeout(), gc.Equals, 5*time.Minute)
+
+	action, err = s.State.Action(action.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Timeout(), gc.Equals, 5*time.Minute)
+
+	action, err = s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Timeout(), gc.Equals, time.Duration(0))
+
+	_, err = s.State.EnqueueActionWithTimeout(s.unit.Tag(), "snapshot", nil, -time.Second)
+	c.Assert(err, gc.ErrorMatches, "timeout -1s not valid")
+}
+
 func (s *ActionSuite) TestAddActionAcceptsDuplicateNames(c *gc.C) {
 	name := "snapshot"
 	params1 := map[string]interface{}{"outfile": "outfile.tar.bz2"}
@@ -742,6 +760,41 @@ func (s *ActionSuite) TestWatchActionNotifications(c *gc.C) {
 	wc.AssertNoChange()
 }
 
+func (s *ActionSuite) TestWatchActionCancellations(c *gc.C) {
+	svc := s.AddTestingService(c, "dummy2", s.charm)
+	u, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := u.WatchActionCancellations()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange()
+	wc.AssertNoChange()
+
+	fa1, err := u.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	fa2, err := u.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Actions on other units, and actions finishing in other ways,
+	// are not reported.
+	other, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.unit.CancelAction(other)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = fa1.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	_, err = fa2.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = u.CancelAction(fa2)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(fa2.Id())
+	wc.AssertNoChange()
+}
+
 func (s *ActionSuite) TestActionStatusWatcher(c *gc.C) {
 	testCase := []struct {
 		receiver state.ActionReceiver
@@ -859,6 +912,9 @@ var _ state.ActionReceiver = (*mockAR)(nil)
 func (r mockAR) AddAction(name string, payload map[string]interface{}) (*state.Action, error) {
 	return nil, nil
 }
+func (r mockAR) AddActionWithTimeout(name string, payload map[string]interface{}, timeout time.Duration) (*state.Action, error) {
+	return nil, nil
+}
 func (r mockAR) CancelAction(*state.Action) (*state.Action, error) { return nil, nil }
 func (r mockAR) WatchActionNotifications() state.StringsWatcher    { return nil }
 func (r mockAR) Actions() ([]*state.Action, error)                 { return nil, nil }
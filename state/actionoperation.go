@@ -0,0 +1,553 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// actionOperationDoc records a request to run an action on every unit
+// of a service, and the actions queued so far to satisfy it.
+type actionOperationDoc struct {
+	// DocId is the key for this document; it is a UUID.
+	DocId string `bson:"_id"`
+
+	// EnvUUID is the environment identifier.
+	EnvUUID string `bson:"env-uuid"`
+
+	// Service is the name of the service whose units run the action.
+	Service string `bson:"service"`
+
+	// Name identifies the action to run on each unit.
+	Name string `bson:"name"`
+
+	// Parameters holds the action's parameters, with defaults from
+	// the charm already inserted.
+	Parameters map[string]interface{} `bson:"parameters"`
+
+	// BatchSize is the greatest number of units that may run the
+	// action at once; zero means no limit.
+	BatchSize int `bson:"batchsize"`
+
+	// StopOnFailure is true if no further actions should be queued
+	// once any has failed.
+	StopOnFailure bool `bson:"stoponfailure"`
+
+	// Enqueued is the time the operation was added.
+	Enqueued time.Time `bson:"enqueued"`
+
+	// Scheduled is the time before which no actions will be queued.
+	Scheduled time.Time `bson:"scheduled"`
+
+	// Timeout is the time each queued action may run before it is
+	// stopped; zero means no limit.
+	Timeout time.Duration `bson:"timeout,omitempty"`
+
+	// Completed is the time the last of the operation's actions
+	// finished.
+	Completed time.Time `bson:"completed"`
+
+	// Status is ActionPending until the first action is queued,
+	// ActionRunning until all queued actions have finished, and then
+	// ActionCompleted or ActionFailed.
+	Status ActionStatus `bson:"status"`
+
+	// Units holds the names of the units that will run the action,
+	// in the order they will be queued.
+	Units []string `bson:"units"`
+
+	// Actions holds the ids of the actions queued so far; the action
+	// at each index is queued for the unit at the same index of
+	// Units. An empty id marks a unit that was not alive when its
+	// turn came, and was skipped.
+	Actions []string `bson:"actions"`
+}
+
+// ActionOperationArgs holds the optional arguments to
+// Service.EnqueueActionOperation.
+type ActionOperationArgs struct {
+	// BatchSize is the greatest number of units that may run the
+	// action at once; zero means no limit.
+	BatchSize int
+
+	// StopOnFailure is true if no further actions should be queued
+	// once any has failed.
+	StopOnFailure bool
+
+	// Scheduled, if not zero, is the time before which no actions
+	// will be queued.
+	Scheduled time.Time
+
+	// Timeout is the time each queued action may run before it is
+	// stopped; zero means no limit.
+	Timeout time.Duration
+}
+
+// ActionOperation represents a request to run an action on every unit
+// of a service, optionally in batches or at a scheduled time.
+type ActionOperation struct {
+	st  *State
+	doc actionOperationDoc
+}
+
+// ActionOperationUnit describes the progress of an ActionOperation on
+// one of its units.
+type ActionOperationUnit struct {
+	// Unit is the name of the unit.
+	Unit string
+
+	// Action is the action queued for the unit, or nil if none has
+	// been queued.
+	Action *Action
+
+	// Status is the status of the unit's action. Units whose action
+	// has not yet been queued are ActionPending; units that were
+	// skipped, or were never reached because the operation stopped
+	// early, are ActionCancelled.
+	Status ActionStatus
+}
+
+// Id returns the id of the operation.
+func (op *ActionOperation) Id() string {
+	return op.st.localID(op.doc.DocId)
+}
+
+// Service returns the name of the service whose units run the action.
+func (op *ActionOperation) Service() string {
+	return op.doc.Service
+}
+
+// Name returns the name of the action.
+func (op *ActionOperation) Name() string {
+	return op.doc.Name
+}
+
+// Parameters returns the parameters passed to each action.
+func (op *ActionOperation) Parameters() map[string]interface{} {
+	return op.doc.Parameters
+}
+
+// BatchSize returns the greatest number of units that may run the
+// action at once; zero means no limit.
+func (op *ActionOperation) BatchSize() int {
+	return op.doc.BatchSize
+}
+
+// StopOnFailure returns whether the operation stops queueing actions
+// once any has failed.
+func (op *ActionOperation) StopOnFailure() bool {
+	return op.doc.StopOnFailure
+}
+
+// Enqueued returns the time the operation was added.
+func (op *ActionOperation) Enqueued() time.Time {
+	return op.doc.Enqueued
+}
+
+// Scheduled returns the time before which no actions will be queued.
+func (op *ActionOperation) Scheduled() time.Time {
+	return op.doc.Scheduled
+}
+
+// Timeout returns the time each queued action may run before it is
+// stopped; zero means no limit.
+func (op *ActionOperation) Timeout() time.Duration {
+	return op.doc.Timeout
+}
+
+// Completed returns the time the operation finished.
+func (op *ActionOperation) Completed() time.Time {
+	return op.doc.Completed
+}
+
+// Status returns the aggregate status of the operation.
+func (op *ActionOperation) Status() ActionStatus {
+	return op.doc.Status
+}
+
+// Refresh refreshes the contents of the operation from the underlying
+// state.
+func (op *ActionOperation) Refresh() error {
+	other, err := op.st.ActionOperation(op.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	op.doc = other.doc
+	return nil
+}
+
+// Units returns the progress of the operation on each of its units, in
+// the order their actions are queued.
+func (op *ActionOperation) Units() ([]ActionOperationUnit, error) {
+	result := make([]ActionOperationUnit, len(op.doc.Units))
+	for i, unitName := range op.doc.Units {
+		unit := &result[i]
+		unit.Unit = unitName
+		switch {
+		case i >= len(op.doc.Actions):
+			if op.finished() {
+				unit.Status = ActionCancelled
+			} else {
+				unit.Status = ActionPending
+			}
+		case op.doc.Actions[i] == "":
+			unit.Status = ActionCancelled
+		default:
+			action, err := op.st.Action(op.doc.Actions[i])
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			unit.Action = action
+			unit.Status = action.Status()
+		}
+	}
+	return result, nil
+}
+
+// finished returns whether the operation will queue no more actions.
+func (op *ActionOperation) finished() bool {
+	switch op.doc.Status {
+	case ActionCompleted, ActionFailed, ActionCancelled:
+		return true
+	}
+	return false
+}
+
+// abandonOrphanedActions finishes those of the operation's unfinished
+// actions whose units are dead or have been removed, and so will never
+// run them. Pending actions are cancelled; running actions are marked
+// failed, since they may have been partly run.
+func (op *ActionOperation) abandonOrphanedActions() error {
+	for _, id := range op.doc.Actions {
+		if id == "" {
+			continue
+		}
+		action, err := op.st.Action(id)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		status := action.Status()
+		if status != ActionPending && status != ActionRunning {
+			continue
+		}
+		var message string
+		unit, err := op.st.Unit(action.Receiver())
+		if errors.IsNotFound(err) {
+			message = "unit removed"
+		} else if err != nil {
+			return errors.Trace(err)
+		} else if unit.Life() == Dead {
+			message = "unit dead"
+		} else {
+			continue
+		}
+		finalStatus := ActionCancelled
+		if status == ActionRunning {
+			finalStatus = ActionFailed
+		}
+		_, err = action.removeAndLog(finalStatus, nil, message)
+		if err == txn.ErrAborted {
+			// The action finished in the meantime.
+			continue
+		} else if err != nil {
+			return errors.Annotatef(err, "cannot abandon action %q", id)
+		}
+	}
+	return nil
+}
+
+// advance queues actions for as many of the operation's remaining units
+// as its batch size allows, and records the outcome of the operation
+// once it has no more actions to queue or wait for. Actions queued for
+// units that have since died or been removed are abandoned first, so
+// that the operation does not wait for them forever.
+func (op *ActionOperation) advance() error {
+	if !op.finished() {
+		if err := op.abandonOrphanedActions(); err != nil {
+			return errors.Annotatef(err, "cannot advance action operation %q", op.Id())
+		}
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := op.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if op.finished() {
+			return nil, jujutxn.ErrNoOperations
+		}
+		now := nowToTheSecond()
+		if now.Before(op.doc.Scheduled) {
+			return nil, jujutxn.ErrNoOperations
+		}
+
+		active, failed := 0, false
+		for _, id := range op.doc.Actions {
+			if id == "" {
+				continue
+			}
+			action, err := op.st.Action(id)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			switch action.Status() {
+			case ActionPending, ActionRunning:
+				active++
+			case ActionFailed:
+				failed = true
+			}
+		}
+		assertUnchanged := bson.D{
+			{"status", op.doc.Status},
+			{"actions", bson.D{{"$size", len(op.doc.Actions)}}},
+		}
+
+		remaining := op.doc.Units[len(op.doc.Actions):]
+		if len(remaining) == 0 || (failed && op.doc.StopOnFailure) {
+			if active > 0 {
+				return nil, jujutxn.ErrNoOperations
+			}
+			status := ActionCompleted
+			if failed {
+				status = ActionFailed
+			}
+			return []txn.Op{{
+				C:      actionOperationsC,
+				Id:     op.doc.DocId,
+				Assert: assertUnchanged,
+				Update: bson.D{{"$set", bson.D{
+					{"status", status},
+					{"completed", now},
+				}}},
+			}}, nil
+		}
+
+		slots := len(remaining)
+		if op.doc.BatchSize > 0 {
+			slots = op.doc.BatchSize - active
+		}
+		if slots <= 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		var ops []txn.Op
+		actions := append([]string(nil), op.doc.Actions...)
+		for _, unitName := range remaining {
+			if slots == 0 {
+				break
+			}
+			unit, err := op.st.Unit(unitName)
+			if errors.IsNotFound(err) {
+				actions = append(actions, "")
+				continue
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if unit.Life() != Alive {
+				actions = append(actions, "")
+				continue
+			}
+			doc, ndoc, err := newActionDoc(op.st, unit.Tag(), op.doc.Name, op.doc.Parameters)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			doc.Operation = op.Id()
+			doc.Timeout = op.doc.Timeout
+			ops = append(ops, txn.Op{
+				C:      unitsC,
+				Id:     unit.doc.DocID,
+				Assert: isAliveDoc,
+			}, txn.Op{
+				C:      actionsC,
+				Id:     doc.DocId,
+				Assert: txn.DocMissing,
+				Insert: doc,
+			}, txn.Op{
+				C:      actionNotificationsC,
+				Id:     ndoc.DocId,
+				Assert: txn.DocMissing,
+				Insert: ndoc,
+			})
+			actions = append(actions, op.st.localID(doc.DocId))
+			slots--
+		}
+		return append([]txn.Op{{
+			C:      actionOperationsC,
+			Id:     op.doc.DocId,
+			Assert: assertUnchanged,
+			Update: bson.D{{"$set", bson.D{
+				{"status", ActionRunning},
+				{"actions", actions},
+			}}},
+		}}, ops...), nil
+	}
+	if err := op.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot advance action operation %q", op.Id())
+	}
+	return op.Refresh()
+}
+
+// EnqueueActionOperation validates the named action and its payload
+// against the service's charm, and adds an operation that queues the
+// action for each of the service's alive units as args allows.
+// Unless the operation is scheduled for later, actions for the first
+// batch of units are queued before it returns.
+func (s *Service) EnqueueActionOperation(name string, payload map[string]interface{}, args ActionOperationArgs) (*ActionOperation, error) {
+	if len(name) == 0 {
+		return nil, errors.New("no action name given")
+	}
+	if args.BatchSize < 0 {
+		return nil, errors.NotValidf("batch size %d", args.BatchSize)
+	}
+	if args.Timeout < 0 {
+		return nil, errors.NotValidf("timeout %v", args.Timeout)
+	}
+	ch, _, err := s.Charm()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	chActions := ch.Actions()
+	if chActions == nil || len(chActions.ActionSpecs) == 0 {
+		return nil, errors.Errorf("no actions defined on charm %q", ch.String())
+	}
+	spec, ok := chActions.ActionSpecs[name]
+	if !ok {
+		return nil, errors.Errorf("action %q not defined on service %q", name, s.Name())
+	}
+	// Reject bad payloads before attempting to insert defaults.
+	if err := spec.ValidateParams(payload); err != nil {
+		return nil, err
+	}
+	payloadWithDefaults, err := spec.InsertDefaults(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	units, err := s.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var unitNames []string
+	for _, unit := range units {
+		if unit.Life() == Alive {
+			unitNames = append(unitNames, unit.Name())
+		}
+	}
+	if len(unitNames) == 0 {
+		return nil, errors.Errorf("service %q has no units", s.Name())
+	}
+	sort.Strings(unitNames)
+
+	id, err := NewUUID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	doc := actionOperationDoc{
+		DocId:         s.st.docID(id.String()),
+		EnvUUID:       s.st.EnvironUUID(),
+		Service:       s.Name(),
+		Name:          name,
+		Parameters:    payloadWithDefaults,
+		BatchSize:     args.BatchSize,
+		StopOnFailure: args.StopOnFailure,
+		Enqueued:      nowToTheSecond(),
+		Scheduled:     args.Scheduled,
+		Timeout:       args.Timeout,
+		Status:        ActionPending,
+		Units:         unitNames,
+		Actions:       []string{},
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+	}, {
+		C:      actionOperationsC,
+		Id:     doc.DocId,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}}
+	if err := s.st.runTransaction(ops); err != nil {
+		return nil, errors.Annotatef(onAbort(err, errNotAlive), "cannot add action operation to service %q", s.Name())
+	}
+	op := &ActionOperation{st: s.st, doc: doc}
+	if err := op.advance(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return op, nil
+}
+
+// ActionOperation returns the action operation with the given id.
+func (st *State) ActionOperation(id string) (*ActionOperation, error) {
+	operations, closer := st.getCollection(actionOperationsC)
+	defer closer()
+
+	var doc actionOperationDoc
+	err := operations.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("action operation %q", id)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get action operation %q", id)
+	}
+	return &ActionOperation{st: st, doc: doc}, nil
+}
+
+// FindActionOperationIdsByPrefix returns the ids of the action
+// operations whose ids start with the given prefix.
+func (st *State) FindActionOperationIdsByPrefix(prefix string) ([]string, error) {
+	operations, closer := st.getCollection(actionOperationsC)
+	defer closer()
+
+	var doc struct {
+		Id string `bson:"_id"`
+	}
+	var results []string
+	iter := operations.Find(bson.D{{"_id", bson.D{{"$regex", "^" + regexp.QuoteMeta(st.docID(prefix))}}}}).Iter()
+	for iter.Next(&doc) {
+		results = append(results, st.localID(doc.Id))
+	}
+	return results, errors.Trace(iter.Close())
+}
+
+// AdvanceActionOperations queues the next actions, and records the
+// outcome, of every action operation that has yet to finish. It is
+// called periodically to start scheduled operations, and to recover
+// from failures to advance operations as their actions finish. An
+// operation that cannot be advanced is logged and skipped, so that it
+// does not hold up the others.
+func (st *State) AdvanceActionOperations() error {
+	operations, closer := st.getCollection(actionOperationsC)
+	defer closer()
+
+	var docs []actionOperationDoc
+	sel := bson.D{{"status", bson.D{{"$in", []ActionStatus{ActionPending, ActionRunning}}}}}
+	if err := operations.Find(sel).All(&docs); err != nil {
+		return errors.Annotate(err, "cannot get unfinished action operations")
+	}
+	for _, doc := range docs {
+		op := &ActionOperation{st: st, doc: doc}
+		if err := op.advance(); err != nil {
+			logger.Errorf("cannot advance action operation %q: %v", op.Id(), err)
+		}
+	}
+	return nil
+}
+
+// advanceActionOperation advances the action operation with the given
+// id.
+func (st *State) advanceActionOperation(id string) error {
+	op, err := st.ActionOperation(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return op.advance()
+}
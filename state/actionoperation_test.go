@@ -0,0 +1,307 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type ActionOperationSuite struct {
+	ConnSuite
+	service *state.Service
+	units   []*state.Unit
+}
+
+var _ = gc.Suite(&ActionOperationSuite{})
+
+func (s *ActionOperationSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	ch := s.AddTestingCharm(c, "dummy")
+	s.service = s.AddTestingService(c, "dummy", ch)
+	s.units = nil
+	for i := 0; i < 3; i++ {
+		unit, err := s.service.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		s.units = append(s.units, unit)
+	}
+}
+
+func (s *ActionOperationSuite) unitStatuses(c *gc.C, op *state.ActionOperation) []state.ActionStatus {
+	units, err := op.Units()
+	c.Assert(err, jc.ErrorIsNil)
+	var statuses []state.ActionStatus
+	for _, unit := range units {
+		statuses = append(statuses, unit.Status)
+	}
+	return statuses
+}
+
+func (s *ActionOperationSuite) finishUnit(c *gc.C, op *state.ActionOperation, index int, status state.ActionStatus) {
+	units, err := op.Units()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units[index].Action, gc.NotNil)
+	_, err = units[index].Action.Finish(state.ActionResults{Status: status})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.Refresh(), jc.ErrorIsNil)
+}
+
+func (s *ActionOperationSuite) TestEnqueueAllUnits(c *gc.C) {
+	op, err := s.service.EnqueueActionOperation("snapshot", nil, state.ActionOperationArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.Service(), gc.Equals, "dummy")
+	c.Assert(op.Name(), gc.Equals, "snapshot")
+	c.Assert(op.Parameters(), jc.DeepEquals, map[string]interface{}{"outfile": "foo.bz2"})
+	c.Assert(op.Status(), gc.Equals, state.ActionRunning)
+
+	units, err := op.Units()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 3)
+	for i, unit := range units {
+		c.Check(unit.Unit, gc.Equals, s.units[i].Name())
+		c.Assert(unit.Action, gc.NotNil)
+		c.Check(unit.Action.Receiver(), gc.Equals, s.units[i].Name())
+		c.Check(unit.Action.Operation(), gc.Equals, op.Id())
+		c.Check(unit.Status, gc.Equals, state.ActionPending)
+	}
+
+	pending, err := s.units[0].PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 1)
+
+	for i := range units {
+		s.finishUnit(c, op, i, state.ActionCompleted)
+	}
+	c.Assert(op.Status(), gc.Equals, state.ActionCompleted)
+	c.Assert(op.Completed().IsZero(), jc.IsFalse)
+}
+
+func (s *ActionOperationSuite) TestEnqueueInBatches(c *gc.C) {
+	op, err := s.service.EnqueueActionOperation("snapshot", nil, state.ActionOperationArgs{
+		BatchSize: 2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unitStatuses(c, op), jc.DeepEquals, []state.ActionStatus{
+		state.ActionPending, state.ActionPending, state.ActionPending,
+	})
+	units, err := op.Units()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units[2].Action, gc.IsNil)
+
+	s.finishUnit(c, op, 0, state.ActionFailed)
+	units, err = op.Units()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units[2].Action, gc.NotNil)
+
+	s.finishUnit(c, op, 1, state.ActionCompleted)
+	c.Assert(op.Status(), gc.Equals, state.ActionRunning)
+	s.finishUnit(c, op, 2, state.ActionCompleted)
+	c.Assert(op.Status(), gc.Equals, state.ActionFailed)
+	c.Assert(s.unitStatuses(c, op), jc.DeepEquals, []state.ActionStatus{
+		state.ActionFailed, state.ActionCompleted, state.ActionCompleted,
+	})
+}
+
+func (s *ActionOperationSuite) TestStopOnFailure(c *gc.C) {
+	op, err := s.service.EnqueueActionOperation("snapshot", nil, state.ActionOperationArgs{
+		BatchSize:     1,
+		StopOnFailure: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.finishUnit(c, op, 0, state.ActionCompleted)
+	s.finishUnit(c, op, 1, state.ActionFailed)
+	c.Assert(op.Status(), gc.Equals, state.ActionFailed)
+	c.Assert(s.unitStatuses(c, op), jc.DeepEquals, []state.ActionStatus{
+		state.ActionCompleted, state.ActionFailed, state.ActionCancelled,
+	})
+	pending, err := s.units[2].PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 0)
+}
+
+func (s *ActionOperationSuite) TestScheduled(c *gc.C) {
+	scheduled := time.Now().Add(time.Hour)
+	op, err := s.service.EnqueueActionOperation("snapshot", nil, state.ActionOperationArgs{
+		Scheduled: scheduled,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.Status(), gc.Equals, state.ActionPending)
+	units, err := op.Units()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, unit := range units {
+		c.Check(unit.Action, gc.IsNil)
+	}
+
+	err = s.State.AdvanceActionOperations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.Refresh(), jc.ErrorIsNil)
+	c.Assert(op.Status(), gc.Equals, state.ActionPending)
+
+	s.PatchValue(state.PatchableNow, func() time.Time {
+		return scheduled.Add(time.Second)
+	})
+	err = s.State.AdvanceActionOperations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.Refresh(), jc.ErrorIsNil)
+	c.Assert(op.Status(), gc.Equals, state.ActionRunning)
+	units, err = op.Units()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, unit := range units {
+		c.Check(unit.Action, gc.NotNil)
+	}
+}
+
+func (s *ActionOperationSuite) TestAdvanceSkipsBrokenOperations(c *gc.C) {
+	broken, err := s.service.EnqueueActionOperation("snapshot", nil, state.ActionOperationArgs{
+		BatchSize: 1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	units, err := broken.Units()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units[0].Action, gc.NotNil)
+	actions, closer := state.GetRawCollection(s.State, "actions")
+	defer closer()
+	err = actions.RemoveId(state.DocID(s.State, units[0].Action.Id()))
+	c.Assert(err, jc.ErrorIsNil)
+
+	scheduled := time.Now().Add(time.Hour)
+	op, err := s.service.EnqueueActionOperation("snapshot", nil, state.ActionOperationArgs{
+		Scheduled: scheduled,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchValue(state.PatchableNow, func() time.Time {
+		return scheduled.Add(time.Second)
+	})
+
+	err = s.State.AdvanceActionOperations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.Refresh(), jc.ErrorIsNil)
+	c.Assert(op.Status(), gc.Equals, state.ActionRunning)
+	c.Assert(broken.Refresh(), jc.ErrorIsNil)
+	c.Assert(broken.Status(), gc.Equals, state.ActionRunning)
+}
+
+func (s *ActionOperationSuite) TestTimeout(c *gc.C) {
+	op, err := s.service.EnqueueActionOperation("snapshot", nil, state.ActionOperationArgs{
+		Timeout: 5 * time.Minute,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.Timeout(), gc.Equals, 5*time.Minute)
+	units, err := op.Units()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, unit := range units {
+		c.Assert(unit.Action, gc.NotNil)
+		c.Check(unit.Action.Timeout(), gc.Equals, 5*time.Minute)
+	}
+}
+
+func (s *ActionOperationSuite) TestSkipsDeadUnits(c *gc.C) {
+	op, err := s.service.EnqueueActionOperation("snapshot", nil, state.ActionOperationArgs{
+		BatchSize: 1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[1].EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.finishUnit(c, op, 0, state.ActionCompleted)
+	c.Assert(s.unitStatuses(c, op), jc.DeepEquals, []state.ActionStatus{
+		state.ActionCompleted, state.ActionCancelled, state.ActionPending,
+	})
+	units, err := op.Units()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units[2].Action, gc.NotNil)
+}
+
+func (s *ActionOperationSuite) TestCancelsPendingActionsOfDeadUnits(c *gc.C) {
+	op, err := s.service.EnqueueActionOperation("snapshot", nil, state.ActionOperationArgs{
+		BatchSize: 1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.AdvanceActionOperations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.Refresh(), jc.ErrorIsNil)
+	c.Assert(s.unitStatuses(c, op), jc.DeepEquals, []state.ActionStatus{
+		state.ActionCancelled, state.ActionPending, state.ActionPending,
+	})
+	units, err := op.Units()
+	c.Assert(err, jc.ErrorIsNil)
+	_, message := units[0].Action.Results()
+	c.Assert(message, gc.Equals, "unit dead")
+	c.Assert(units[1].Action, gc.NotNil)
+}
+
+func (s *ActionOperationSuite) TestFailsRunningActionsOfRemovedUnits(c *gc.C) {
+	op, err := s.service.EnqueueActionOperation("snapshot", nil, state.ActionOperationArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	units, err := op.Units()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = units[0].Action.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.AdvanceActionOperations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.Refresh(), jc.ErrorIsNil)
+	c.Assert(s.unitStatuses(c, op), jc.DeepEquals, []state.ActionStatus{
+		state.ActionFailed, state.ActionPending, state.ActionPending,
+	})
+
+	s.finishUnit(c, op, 1, state.ActionCompleted)
+	s.finishUnit(c, op, 2, state.ActionCompleted)
+	c.Assert(op.Status(), gc.Equals, state.ActionFailed)
+}
+
+func (s *ActionOperationSuite) TestEnqueueValidation(c *gc.C) {
+	_, err := s.service.EnqueueActionOperation("", nil, state.ActionOperationArgs{})
+	c.Assert(err, gc.ErrorMatches, "no action name given")
+	_, err = s.service.EnqueueActionOperation("snapshot", nil, state.ActionOperationArgs{BatchSize: -1})
+	c.Assert(err, gc.ErrorMatches, "batch size -1 not valid")
+	_, err = s.service.EnqueueActionOperation("snapshot", nil, state.ActionOperationArgs{Timeout: -time.Second})
+	c.Assert(err, gc.ErrorMatches, "timeout -1s not valid")
+	_, err = s.service.EnqueueActionOperation("bogus", nil, state.ActionOperationArgs{})
+	c.Assert(err, gc.ErrorMatches, `action "bogus" not defined on service "dummy"`)
+	_, err = s.service.EnqueueActionOperation("snapshot", map[string]interface{}{"outfile": 5}, state.ActionOperationArgs{})
+	c.Assert(err, gc.ErrorMatches, "validation failed: .*")
+
+	ch := s.AddTestingCharm(c, "actionless")
+	actionless := s.AddTestingService(c, "actionless", ch)
+	_, err = actionless.EnqueueActionOperation("snapshot", nil, state.ActionOperationArgs{})
+	c.Assert(err, gc.ErrorMatches, `no actions defined on charm .*`)
+
+	dummy, _, err := s.service.Charm()
+	c.Assert(err, jc.ErrorIsNil)
+	noUnits := s.AddTestingService(c, "dummy2", dummy)
+	_, err = noUnits.EnqueueActionOperation("snapshot", nil, state.ActionOperationArgs{})
+	c.Assert(err, gc.ErrorMatches, `service "dummy2" has no units`)
+}
+
+func (s *ActionOperationSuite) TestFindByPrefix(c *gc.C) {
+	op, err := s.service.EnqueueActionOperation("snapshot", nil, state.ActionOperationArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	ids, err := s.State.FindActionOperationIdsByPrefix(op.Id()[:6])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids, jc.DeepEquals, []string{op.Id()})
+
+	// The prefix is matched literally.
+	ids, err = s.State.FindActionOperationIdsByPrefix(".")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids, gc.HasLen, 0)
+
+	found, err := s.State.ActionOperation(op.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Name(), gc.Equals, "snapshot")
+
+	_, err = s.State.ActionOperation("missing")
+	c.Assert(err, gc.ErrorMatches, `action operation "missing" not found`)
+}
@@ -138,6 +138,22 @@ func allCollections() collectionSchema {
 		// changes from being accepted.
 		blocksC: {},
 
+		// This collection holds a record of the auditable operations
+		// performed against the environment through the API.
+		auditLogC: {
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "user", "timestamp"},
+			}, {
+				Key: []string{"env-uuid", "entities", "timestamp"},
+			}, {
+				Key: []string{"env-uuid", "timestamp"},
+			}},
+		},
+
+		// This collection records the progress of the workers that
+		// forward the environment's logs to external destinations.
+		logForwardingC: {},
+
 		// This collection is used for internal bookkeeping; certain complex
 		// or tedious state changes are deferred by recording a cleanup doc
 		// for later handling.
@@ -277,6 +293,10 @@ func allCollections() collectionSchema {
 		actionsC:             {},
 		actionNotificationsC: {},
 
+		// This collection holds the operations that queue an action on
+		// every unit of a service, and track their progress.
+		actionOperationsC: {},
+
 		// -----
 
 		// The remaining non-global collections share the property of being
@@ -303,6 +323,14 @@ func allCollections() collectionSchema {
 			}},
 		},
 
+		// This collection records the changes made to each service's
+		// charm config settings.
+		serviceConfigHistoryC: {
+			indexes: []mgo.Index{{
+				Key: []string{"env-uuid", "service", "revision"},
+			}},
+		},
+
 		// ----------------------
 
 		// Raw-access collections
@@ -318,9 +346,11 @@ func allCollections() collectionSchema {
 // inspection.
 const (
 	actionNotificationsC   = "actionnotifications"
+	actionOperationsC      = "actionoperations"
 	actionresultsC         = "actionresults"
 	actionsC               = "actions"
 	annotationsC           = "annotations"
+	auditLogC              = "auditlog"
 	blockDevicesC          = "blockdevices"
 	blocksC                = "blocks"
 	charmsC                = "charms"
@@ -335,6 +365,7 @@ const (
 	ipaddressesC           = "ipaddresses"
 	leaseC                 = "lease"
 	leasesC                = "leases"
+	logForwardingC         = "logforwarding"
 	machinesC              = "machines"
 	meterStatusC           = "meterStatus"
 	metricsC               = "metrics"
@@ -349,6 +380,7 @@ const (
 	requestedNetworksC     = "requestednetworks"
 	restoreInfoC           = "restoreInfo"
 	sequenceC              = "sequence"
+	serviceConfigHistoryC  = "serviceconfighistory"
 	servicesC              = "services"
 	settingsC              = "settings"
 	settingsrefsC          = "settingsrefs"
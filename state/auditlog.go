@@ -0,0 +1,182 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// AuditRecord describes a single auditable operation performed
+// against an environment.
+type AuditRecord struct {
+	// Id uniquely identifies the record within its environment.
+	// It is assigned by AddAuditRecord and ignored on input.
+	Id string
+
+	// EnvUUID holds the UUID of the environment the operation
+	// was performed against. It is set by AddAuditRecord.
+	EnvUUID string
+
+	// Timestamp records when the operation was performed.
+	Timestamp time.Time
+
+	// User holds the tag of the entity that performed the
+	// operation.
+	User string
+
+	// Facade, Version and Method identify the API call made.
+	Facade  string
+	Version int
+	Method  string
+
+	// Args holds a summary of the arguments passed to the call.
+	Args string
+
+	// Entities holds the tags of the entities targeted by the
+	// operation, where those can be determined.
+	Entities []string
+
+	// Error holds the error returned by the operation, if any.
+	// An empty Error indicates that the operation succeeded.
+	Error string
+}
+
+// Succeeded returns whether the recorded operation completed
+// without error.
+func (r AuditRecord) Succeeded() bool {
+	return r.Error == ""
+}
+
+// AuditRecordFilter holds the criteria used to select audit
+// records. Zero-valued fields are not used for filtering.
+type AuditRecordFilter struct {
+	// User, if set, selects only records for operations
+	// performed by the entity with that tag.
+	User string
+
+	// Entity, if set, selects only records for operations
+	// that targeted the entity with that tag.
+	Entity string
+
+	// Since and Until, if set, bound the time window of the
+	// selected records; both bounds are inclusive.
+	Since time.Time
+	Until time.Time
+
+	// Limit, if greater than zero, restricts the result to the
+	// most recent Limit matching records.
+	Limit int
+}
+
+// auditRecordDoc is the persistent representation of an AuditRecord.
+type auditRecordDoc struct {
+	DocID     string    `bson:"_id"`
+	EnvUUID   string    `bson:"env-uuid"`
+	Seq       int       `bson:"seq"`
+	Timestamp time.Time `bson:"timestamp"`
+	User      string    `bson:"user"`
+	Facade    string    `bson:"facade"`
+	Version   int       `bson:"version"`
+	Method    string    `bson:"method"`
+	Args      string    `bson:"args,omitempty"`
+	Entities  []string  `bson:"entities,omitempty"`
+	Error     string    `bson:"error,omitempty"`
+}
+
+func (doc *auditRecordDoc) record(st *State) AuditRecord {
+	return AuditRecord{
+		Id:        st.localID(doc.DocID),
+		EnvUUID:   doc.EnvUUID,
+		Timestamp: doc.Timestamp.UTC(),
+		User:      doc.User,
+		Facade:    doc.Facade,
+		Version:   doc.Version,
+		Method:    doc.Method,
+		Args:      doc.Args,
+		Entities:  doc.Entities,
+		Error:     doc.Error,
+	}
+}
+
+// AddAuditRecord persists the given record in the environment's
+// audit log. If the record has no timestamp, the current time is
+// used.
+func (st *State) AddAuditRecord(rec AuditRecord) error {
+	if rec.User == "" {
+		return errors.NotValidf("audit record with no user")
+	}
+	if rec.Facade == "" || rec.Method == "" {
+		return errors.NotValidf("audit record with no facade method")
+	}
+	seq, err := st.sequence("auditlog")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	timestamp := rec.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	doc := &auditRecordDoc{
+		DocID:     st.docID(fmt.Sprint(seq)),
+		EnvUUID:   st.EnvironUUID(),
+		Seq:       seq,
+		Timestamp: timestamp.UTC(),
+		User:      rec.User,
+		Facade:    rec.Facade,
+		Version:   rec.Version,
+		Method:    rec.Method,
+		Args:      rec.Args,
+		Entities:  rec.Entities,
+		Error:     rec.Error,
+	}
+	auditLog, closer := st.getCollection(auditLogC)
+	defer closer()
+	err = auditLog.Writeable().Insert(doc)
+	return errors.Annotatef(err, "cannot add audit record for %s.%s", rec.Facade, rec.Method)
+}
+
+// AuditRecords returns the audit records matching the given filter,
+// ordered from oldest to newest.
+func (st *State) AuditRecords(filter AuditRecordFilter) ([]AuditRecord, error) {
+	auditLog, closer := st.getCollection(auditLogC)
+	defer closer()
+
+	sel := bson.D{}
+	if filter.User != "" {
+		sel = append(sel, bson.DocElem{"user", filter.User})
+	}
+	if filter.Entity != "" {
+		sel = append(sel, bson.DocElem{"entities", filter.Entity})
+	}
+	timeSel := bson.D{}
+	if !filter.Since.IsZero() {
+		timeSel = append(timeSel, bson.DocElem{"$gte", filter.Since.UTC()})
+	}
+	if !filter.Until.IsZero() {
+		timeSel = append(timeSel, bson.DocElem{"$lte", filter.Until.UTC()})
+	}
+	if len(timeSel) > 0 {
+		sel = append(sel, bson.DocElem{"timestamp", timeSel})
+	}
+
+	// Query newest first so that the limit applies to the most
+	// recent records, and reverse the result afterwards.
+	query := auditLog.Find(sel).Sort("-timestamp", "-seq")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	var docs []auditRecordDoc
+	if err := query.All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get audit records")
+	}
+	records := make([]AuditRecord, len(docs))
+	for i, doc := range docs {
+		records[len(docs)-1-i] = doc.record(st)
+	}
+	return records, nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type auditLogSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&auditLogSuite{})
+
+var auditBaseTime = time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+
+func (s *auditLogSuite) addRecord(c *gc.C, st *state.State, user, method string, offset time.Duration, entities ...string) {
+	err := st.AddAuditRecord(state.AuditRecord{
+		Timestamp: auditBaseTime.Add(offset),
+		User:      user,
+		Facade:    "Client",
+		Version:   0,
+		Method:    method,
+		Entities:  entities,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func auditMethods(records []state.AuditRecord) []string {
+	result := make([]string, len(records))
+	for i, rec := range records {
+		result[i] = rec.Method
+	}
+	return result
+}
+
+func (s *auditLogSuite) TestAddAuditRecordValidates(c *gc.C) {
+	err := s.State.AddAuditRecord(state.AuditRecord{Facade: "Client", Method: "ServiceDeploy"})
+	c.Assert(err, gc.ErrorMatches, "audit record with no user not valid")
+	err = s.State.AddAuditRecord(state.AuditRecord{User: "user-admin"})
+	c.Assert(err, gc.ErrorMatches, "audit record with no facade method not valid")
+}
+
+func (s *auditLogSuite) TestAddAuditRecord(c *gc.C) {
+	err := s.State.AddAuditRecord(state.AuditRecord{
+		Timestamp: auditBaseTime,
+		User:      "user-admin",
+		Facade:    "Client",
+		Version:   0,
+		Method:    "ServiceExpose",
+		Args:      `{"ServiceName":"wordpress"}`,
+		Entities:  []string{"service-wordpress"},
+		Error:     "boom",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	records, err := s.State.AuditRecords(state.AuditRecordFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, 1)
+	c.Assert(records[0].Id, gc.Not(gc.Equals), "")
+	records[0].Id = ""
+	c.Assert(records[0], jc.DeepEquals, state.AuditRecord{
+		EnvUUID:   s.State.EnvironUUID(),
+		Timestamp: auditBaseTime,
+		User:      "user-admin",
+		Facade:    "Client",
+		Version:   0,
+		Method:    "ServiceExpose",
+		Args:      `{"ServiceName":"wordpress"}`,
+		Entities:  []string{"service-wordpress"},
+		Error:     "boom",
+	})
+	c.Assert(records[0].Succeeded(), jc.IsFalse)
+}
+
+func (s *auditLogSuite) TestAuditRecordsFiltering(c *gc.C) {
+	s.addRecord(c, s.State, "user-admin", "ServiceDeploy", 0, "service-wordpress")
+	s.addRecord(c, s.State, "user-bob", "AddServiceUnits", time.Minute, "service-wordpress")
+	s.addRecord(c, s.State, "user-admin", "DestroyMachines", 2*time.Minute, "machine-1")
+	s.addRecord(c, s.State, "user-bob", "ServiceExpose", 3*time.Minute, "service-mysql")
+
+	for i, test := range []struct {
+		about  string
+		filter state.AuditRecordFilter
+		expect []string
+	}{{
+		about:  "no filter",
+		expect: []string{"ServiceDeploy", "AddServiceUnits", "DestroyMachines", "ServiceExpose"},
+	}, {
+		about:  "by user",
+		filter: state.AuditRecordFilter{User: "user-bob"},
+		expect: []string{"AddServiceUnits", "ServiceExpose"},
+	}, {
+		about:  "by entity",
+		filter: state.AuditRecordFilter{Entity: "service-wordpress"},
+		expect: []string{"ServiceDeploy", "AddServiceUnits"},
+	}, {
+		about: "by time window",
+		filter: state.AuditRecordFilter{
+			Since: auditBaseTime.Add(time.Minute),
+			Until: auditBaseTime.Add(2 * time.Minute),
+		},
+		expect: []string{"AddServiceUnits", "DestroyMachines"},
+	}, {
+		about:  "limit keeps most recent",
+		filter: state.AuditRecordFilter{Limit: 2},
+		expect: []string{"DestroyMachines", "ServiceExpose"},
+	}, {
+		about:  "combined",
+		filter: state.AuditRecordFilter{User: "user-admin", Since: auditBaseTime.Add(time.Second)},
+		expect: []string{"DestroyMachines"},
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		records, err := s.State.AuditRecords(test.filter)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(auditMethods(records), jc.DeepEquals, test.expect)
+	}
+}
+
+func (s *auditLogSuite) TestAuditRecordsIsolatedPerEnvironment(c *gc.C) {
+	otherState := s.Factory.MakeEnvironment(c, nil)
+	defer otherState.Close()
+
+	s.addRecord(c, s.State, "user-admin", "ServiceDeploy", 0)
+	s.addRecord(c, otherState, "user-admin", "DestroyMachines", 0)
+
+	records, err := s.State.AuditRecords(state.AuditRecordFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(auditMethods(records), jc.DeepEquals, []string{"ServiceDeploy"})
+
+	records, err = otherState.AuditRecords(state.AuditRecordFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(auditMethods(records), jc.DeepEquals, []string{"DestroyMachines"})
+	c.Assert(records[0].EnvUUID, gc.Equals, otherState.EnvironUUID())
+}
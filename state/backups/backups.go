@@ -145,6 +145,15 @@ func (b *backups) Create(meta *Metadata, paths *Paths, dbInfo *DBInfo) error {
 	}
 	defer result.archiveFile.Close()
 
+	// Encrypt the archive, if requested.
+	if meta.encryptionKey != nil {
+		result, err = encryptArchive(result, meta.encryptionKey)
+		if err != nil {
+			return errors.Annotate(err, "while encrypting backup archive")
+		}
+		defer result.archiveFile.Close()
+	}
+
 	// Finalize the metadata.
 	err = finishMeta(meta, result)
 	if err != nil {
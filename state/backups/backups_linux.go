@@ -32,6 +32,12 @@ func (b *backups) Restore(backupId string, args RestoreArgs) error {
 
 	defer backupReader.Close()
 
+	if meta.KeyFingerprint != "" {
+		// The private key never leaves the client, which
+		// decrypts the archive and uploads the result.
+		return errors.Errorf("backup %q is encrypted with OpenPGP key %s; restore it with --key", backupId, meta.KeyFingerprint)
+	}
+
 	workspace, err := NewArchiveWorkspaceReader(backupReader)
 	if err != nil {
 		return errors.Annotate(err, "cannot unpack backup file")
@@ -0,0 +1,117 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/hash"
+	"golang.org/x/crypto/openpgp"
+)
+
+// ParseEncryptionKey returns the single OpenPGP key in the armored key
+// block. Backup archives are encrypted to public keys, and decrypted
+// with the matching private keys.
+func ParseEncryptionKey(armored string) (*openpgp.Entity, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armored))
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read OpenPGP key")
+	}
+	if len(keyring) != 1 {
+		return nil, errors.Errorf("expected 1 OpenPGP key, got %d", len(keyring))
+	}
+	return keyring[0], nil
+}
+
+// KeyFingerprint returns the fingerprint of the key's primary key, in
+// the hexadecimal form reported by "gpg --fingerprint".
+func KeyFingerprint(key *openpgp.Entity) string {
+	return fmt.Sprintf("%X", key.PrimaryKey.Fingerprint[:])
+}
+
+// encryptArchive encrypts the newly created archive to the key. The
+// result holds the size and checksum of the encrypted archive, as
+// that is what is stored. The caller remains responsible for closing
+// the unencrypted archive.
+func encryptArchive(result *createResult, key *openpgp.Entity) (_ *createResult, err error) {
+	tempDir, err := ioutil.TempDir("", tempPrefix)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	file, err := os.Create(filepath.Join(tempDir, tempFilename+".gpg"))
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return nil, errors.Trace(err)
+	}
+	encrypted := &tempFile{file, tempDir}
+	defer func() {
+		if err != nil {
+			encrypted.Close()
+		}
+	}()
+
+	// As for the unencrypted archive, the checksum is of the file
+	// as stored.
+	hasher := hash.NewHashingWriter(file, sha1.New())
+	plaintext, err := openpgp.Encrypt(hasher, []*openpgp.Entity{key}, nil, &openpgp.FileHints{IsBinary: true}, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := io.Copy(plaintext, result.archiveFile); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := plaintext.Close(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	size, err := file.Seek(0, os.SEEK_CUR)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := file.Seek(0, os.SEEK_SET); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &createResult{
+		archiveFile: encrypted,
+		size:        size,
+		checksum:    hasher.Base64Sum(),
+	}, nil
+}
+
+// DecryptArchive returns the contents of a backup archive that was
+// encrypted to the public half of the armored OpenPGP private key.
+// Private keys protected by a passphrase are not supported. The
+// integrity of the archive is checked as the last of it is read.
+func DecryptArchive(archive io.Reader, armoredPrivateKey string) (io.Reader, error) {
+	key, err := ParseEncryptionKey(armoredPrivateKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if key.PrivateKey == nil {
+		return nil, errors.Errorf("OpenPGP key %s is not a private key", KeyFingerprint(key))
+	}
+	protected := key.PrivateKey.Encrypted
+	for _, subkey := range key.Subkeys {
+		if subkey.PrivateKey != nil && subkey.PrivateKey.Encrypted {
+			protected = true
+		}
+	}
+	if protected {
+		return nil, errors.Errorf("OpenPGP key %s is protected by a passphrase", KeyFingerprint(key))
+	}
+	md, err := openpgp.ReadMessage(archive, openpgp.EntityList{key}, nil, nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot decrypt backup archive")
+	}
+	if !md.IsEncrypted {
+		return nil, errors.New("backup archive is not encrypted")
+	}
+	return md.UnverifiedBody, nil
+}
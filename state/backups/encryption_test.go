@@ -0,0 +1,118 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/filestorage"
+	"github.com/juju/utils/set"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups"
+	backupstesting "github.com/juju/juju/state/backups/testing"
+)
+
+type encryptionSuite struct {
+	backupstesting.BaseSuite
+	publicKey  string
+	privateKey string
+}
+
+var _ = gc.Suite(&encryptionSuite{})
+
+func (s *encryptionSuite) SetUpSuite(c *gc.C) {
+	s.BaseSuite.SetUpSuite(c)
+	s.publicKey, s.privateKey = backupstesting.NewEncryptionKey(c)
+}
+
+// createArchive runs Create with the given metadata, returning what
+// would have been stored.
+func (s *encryptionSuite) createArchive(c *gc.C, meta *backups.Metadata) []byte {
+	archiveFile := ioutil.NopCloser(bytes.NewBufferString("<compressed tarball>"))
+	result := backups.NewTestCreateResult(archiveFile, 20, "<checksum>")
+	_, testCreate := backups.NewTestCreate(result)
+	s.PatchValue(backups.RunCreate, testCreate)
+	s.PatchValue(backups.TestGetFilesToBackUp, func(string, *backups.Paths, string) ([]string, error) {
+		return []string{"<some file>"}, nil
+	})
+	s.PatchValue(backups.GetDBDumper, func(*backups.DBInfo) (backups.DBDumper, error) {
+		return nil, nil
+	})
+	var stored []byte
+	s.PatchValue(backups.StoreArchiveRef, func(_ filestorage.FileStorage, _ *backups.Metadata, file io.Reader) error {
+		var err error
+		stored, err = ioutil.ReadAll(file)
+		return err
+	})
+
+	paths := backups.Paths{DataDir: "/var/lib/juju"}
+	dbInfo := backups.DBInfo{"a", "b", "c", set.NewStrings("juju")}
+	err := backups.NewBackups(s.Storage).Create(meta, &paths, &dbInfo)
+	c.Assert(err, jc.ErrorIsNil)
+	return stored
+}
+
+func (s *encryptionSuite) TestParseEncryptionKey(c *gc.C) {
+	key, err := backups.ParseEncryptionKey(s.publicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(backups.KeyFingerprint(key), gc.Matches, "[0-9A-F]{40}")
+
+	_, err = backups.ParseEncryptionKey("not a key")
+	c.Check(err, gc.ErrorMatches, "cannot read OpenPGP key: .*")
+	_, err = backups.ParseEncryptionKey(s.publicKey + s.publicKey)
+	c.Check(err, gc.ErrorMatches, "expected 1 OpenPGP key, got 2")
+}
+
+func (s *encryptionSuite) TestCreateEncrypted(c *gc.C) {
+	meta := backupstesting.NewMetadataStarted()
+	err := meta.SetEncryptionKey(s.publicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	key, err := backups.ParseEncryptionKey(s.publicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(meta.KeyFingerprint, gc.Equals, backups.KeyFingerprint(key))
+
+	stored := s.createArchive(c, meta)
+	c.Check(bytes.Contains(stored, []byte("<compressed tarball>")), jc.IsFalse)
+	// The metadata describes the archive as stored.
+	c.Check(meta.Size(), gc.Equals, int64(len(stored)))
+	c.Check(meta.Checksum(), gc.Not(gc.Equals), "<checksum>")
+
+	decrypted, err := backups.DecryptArchive(bytes.NewReader(stored), s.privateKey)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadAll(decrypted)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, "<compressed tarball>")
+}
+
+func (s *encryptionSuite) TestCreateNotEncrypted(c *gc.C) {
+	meta := backupstesting.NewMetadataStarted()
+	stored := s.createArchive(c, meta)
+	c.Check(string(stored), gc.Equals, "<compressed tarball>")
+	c.Check(meta.KeyFingerprint, gc.Equals, "")
+}
+
+func (s *encryptionSuite) TestDecryptArchiveNotPrivateKey(c *gc.C) {
+	_, err := backups.DecryptArchive(bytes.NewBufferString("<archive>"), s.publicKey)
+	c.Check(err, gc.ErrorMatches, "OpenPGP key [0-9A-F]{40} is not a private key")
+}
+
+func (s *encryptionSuite) TestDecryptArchiveWrongKey(c *gc.C) {
+	meta := backupstesting.NewMetadataStarted()
+	err := meta.SetEncryptionKey(s.publicKey)
+	c.Assert(err, jc.ErrorIsNil)
+	stored := s.createArchive(c, meta)
+
+	_, otherKey := backupstesting.NewEncryptionKey(c)
+	_, err = backups.DecryptArchive(bytes.NewReader(stored), otherKey)
+	c.Check(err, gc.ErrorMatches, "cannot decrypt backup archive: .*")
+}
+
+func (s *encryptionSuite) TestDecryptArchiveNotEncrypted(c *gc.C) {
+	_, err := backups.DecryptArchive(bytes.NewBufferString("<compressed tarball>"), s.privateKey)
+	c.Check(err, gc.ErrorMatches, "cannot decrypt backup archive: .*")
+}
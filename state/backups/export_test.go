@@ -28,10 +28,16 @@ var (
 	GetMongodumpPath     = &getMongodumpPath
 	RunCommand           = &runCommand
 	ReplaceableFolders   = &replaceableFolders
+	RunSFTP              = &runSFTP
+
+	NewLocalFileStorage = newLocalFileStorage
+	NewS3FileStorage    = newS3FileStorage
+	NewSFTPFileStorage  = newSFTPFileStorage
 )
 
 var _ filestorage.DocStorage = (*backupsDocStorage)(nil)
 var _ filestorage.RawFileStorage = (*backupBlobStorage)(nil)
+var _ filestorage.RawFileStorage = (*targetFileStorage)(nil)
 
 func getBackupDBWrapper(st *state.State) *storageDBWrapper {
 	envUUID := st.EnvironTag().Id()
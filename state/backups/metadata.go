@@ -14,6 +14,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/filestorage"
+	"golang.org/x/crypto/openpgp"
 
 	"github.com/juju/juju/version"
 )
@@ -62,6 +63,20 @@ type Metadata struct {
 	Origin Origin
 	// Notes is an optional user-supplied annotation.
 	Notes string
+	// Target is the backup target in which the archive is stored.
+	// The archive is stored in state if it is not set.
+	Target string
+	// Scheduled records whether the backup was created on the
+	// environment's backup schedule, rather than on request.
+	Scheduled bool
+	// KeyFingerprint is the fingerprint of the OpenPGP key to which
+	// the archive is encrypted. The archive is not encrypted if it is
+	// not set.
+	KeyFingerprint string
+
+	// encryptionKey is the key to which the archive is encrypted
+	// when it is created.
+	encryptionKey *openpgp.Entity
 }
 
 // NewMetadata returns a new Metadata for a state backup archive.  Only
@@ -95,6 +110,19 @@ func NewMetadataState(db DB, machine string) (*Metadata, error) {
 	return meta, nil
 }
 
+// SetEncryptionKey arranges for the archive to be encrypted to the
+// armored OpenPGP public key when it is created, and records the
+// key's fingerprint.
+func (m *Metadata) SetEncryptionKey(armoredPublicKey string) error {
+	key, err := ParseEncryptionKey(armoredPublicKey)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	m.encryptionKey = key
+	m.KeyFingerprint = KeyFingerprint(key)
+	return nil
+}
+
 // MarkComplete populates the remaining metadata values.  The default
 // checksum format is used.
 func (m *Metadata) MarkComplete(size int64, checksum string) error {
@@ -0,0 +1,81 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+)
+
+// storageScheduleName is the name of the collection holding the status
+// of each environment's scheduled backups.
+const storageScheduleName = "schedule"
+
+// ScheduleStatus records the outcome of an environment's most recent
+// scheduled backups.
+type ScheduleStatus struct {
+	// LastAttempt is when a scheduled backup was last attempted.
+	LastAttempt time.Time
+	// LastSuccess is when a scheduled backup last succeeded. It is
+	// zero if none has.
+	LastSuccess time.Time
+	// LastError holds the reason the last attempt failed, if it did.
+	LastError string
+}
+
+// scheduleStatusDoc is a mirror of ScheduleStatus, used just for DB
+// storage.
+type scheduleStatusDoc struct {
+	EnvUUID     string `bson:"_id"`
+	LastAttempt int64  `bson:"lastattempt"`
+	LastSuccess int64  `bson:"lastsuccess,omitempty"`
+	LastError   string `bson:"lasterror,omitempty"`
+}
+
+// GetScheduleStatus returns the status of the environment's scheduled
+// backups. If no scheduled backup has been attempted, an error
+// satisfying juju/errors.IsNotFound() is returned.
+func GetScheduleStatus(st DB) (*ScheduleStatus, error) {
+	session := st.MongoSession().Copy()
+	defer session.Close()
+	coll := session.DB(storageDBName).C(storageScheduleName)
+
+	var doc scheduleStatusDoc
+	err := coll.FindId(st.EnvironTag().Id()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("scheduled backup status")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "while getting scheduled backup status")
+	}
+
+	status := ScheduleStatus{
+		LastAttempt: metadocUnixToTime(doc.LastAttempt),
+		LastError:   doc.LastError,
+	}
+	if doc.LastSuccess != 0 {
+		status.LastSuccess = metadocUnixToTime(doc.LastSuccess)
+	}
+	return &status, nil
+}
+
+// SetScheduleStatus records the status of the environment's scheduled
+// backups.
+func SetScheduleStatus(st DB, status ScheduleStatus) error {
+	session := st.MongoSession().Copy()
+	defer session.Close()
+	coll := session.DB(storageDBName).C(storageScheduleName)
+
+	doc := scheduleStatusDoc{
+		EnvUUID:     st.EnvironTag().Id(),
+		LastAttempt: metadocTimeToUnix(status.LastAttempt),
+		LastError:   status.LastError,
+	}
+	if !status.LastSuccess.IsZero() {
+		doc.LastSuccess = metadocTimeToUnix(status.LastSuccess)
+	}
+	_, err := coll.UpsertId(doc.EnvUUID, &doc)
+	return errors.Annotate(err, "while setting scheduled backup status")
+}
@@ -17,6 +17,7 @@ import (
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/version"
 )
 
@@ -38,6 +39,9 @@ type storageMetaDoc struct {
 	ChecksumFormat string `bson:"checksumformat"`
 	Size           int64  `bson:"size,minsize"`
 	Stored         int64  `bson:"stored,minsize"`
+	Target         string `bson:"target,omitempty"`
+	Scheduled      bool   `bson:"scheduled,omitempty"`
+	KeyFingerprint string `bson:"keyfingerprint,omitempty"`
 
 	// backup
 
@@ -120,6 +124,9 @@ func docAsMetadata(doc *storageMetaDoc) *Metadata {
 	meta := NewMetadata()
 	meta.Started = metadocUnixToTime(doc.Started)
 	meta.Notes = doc.Notes
+	meta.Target = doc.Target
+	meta.Scheduled = doc.Scheduled
+	meta.KeyFingerprint = doc.KeyFingerprint
 
 	meta.Origin.Environment = doc.Environment
 	meta.Origin.Machine = doc.Machine
@@ -170,6 +177,9 @@ func newStorageMetaDoc(meta *Metadata) storageMetaDoc {
 		doc.Finished = metadocTimeToUnix(*meta.Finished)
 	}
 	doc.Notes = meta.Notes
+	doc.Target = meta.Target
+	doc.Scheduled = meta.Scheduled
+	doc.KeyFingerprint = meta.KeyFingerprint
 
 	doc.Environment = meta.Origin.Environment
 	doc.Machine = meta.Origin.Machine
@@ -531,17 +541,22 @@ type DB interface {
 
 	// EnvironTag is the concrete environ tag for this database.
 	EnvironTag() names.EnvironTag
+
+	// EnvironConfig returns the environment's current config, which
+	// holds the configuration of the backup targets.
+	EnvironConfig() (*config.Config, error)
 }
 
 // NewStorage returns a new FileStorage to use for storing backup
-// archives (and metadata).
+// archives (and metadata). Each archive is stored in the backup target
+// recorded in its metadata.
 func NewStorage(st DB) filestorage.FileStorage {
 	envUUID := st.EnvironTag().Id()
 	db := st.MongoSession().DB(storageDBName)
 	dbWrap := newStorageDBWrapper(db, storageMetaName, envUUID)
 	defer dbWrap.Close()
 
-	files := newFileStorage(dbWrap, backupStorageRoot)
+	files := newTargetFileStorage(dbWrap, newFileStorage(dbWrap, backupStorageRoot), st.EnvironConfig)
 	docs := newMetadataStorage(dbWrap)
 	return filestorage.NewFileStorage(docs, files)
 }
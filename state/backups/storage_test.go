@@ -4,6 +4,10 @@
 package backups_test
 
 import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/juju/errors"
@@ -166,3 +170,68 @@ func (s *storageSuite) TestSetBackupStoredTimeNotFound(c *gc.C) {
 
 	c.Check(err, jc.Satisfies, errors.IsNotFound)
 }
+
+func (s *storageSuite) TestNewStorageTarget(c *gc.C) {
+	dir := c.MkDir()
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"backup-local-dir": dir,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	stor := backups.NewStorage(s.State)
+	defer stor.Close()
+
+	original := s.metadata(c)
+	original.Target = "local"
+	archive := bytes.Repeat([]byte("x"), 42)
+	id, err := stor.Add(original, bytes.NewReader(archive))
+	c.Assert(err, jc.ErrorIsNil)
+
+	meta, err := backups.GetBackupMetadata(s.State, id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(meta.Target, gc.Equals, "local")
+	data, err := ioutil.ReadFile(filepath.Join(dir, "juju-backup-"+id+".tar.gz"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(data, jc.DeepEquals, archive)
+
+	err = stor.Remove(id)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = os.Stat(filepath.Join(dir, "juju-backup-"+id+".tar.gz"))
+	c.Check(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *storageSuite) TestNewStorageTargetNotConfigured(c *gc.C) {
+	stor := backups.NewStorage(s.State)
+	defer stor.Close()
+
+	original := s.metadata(c)
+	original.Target = "s3"
+	_, err := stor.Add(original, bytes.NewReader(bytes.Repeat([]byte("x"), 42)))
+	c.Assert(err, gc.ErrorMatches, `.*backup target "s3" requires backup-s3-bucket to be set`)
+}
+
+func (s *storageSuite) TestScheduleStatus(c *gc.C) {
+	_, err := backups.GetScheduleStatus(s.State)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	attempt := time.Date(2015, time.July, 15, 3, 0, 0, 0, time.UTC)
+	status := backups.ScheduleStatus{
+		LastAttempt: attempt,
+		LastError:   "failed!",
+	}
+	err = backups.SetScheduleStatus(s.State, status)
+	c.Assert(err, jc.ErrorIsNil)
+	stored, err := backups.GetScheduleStatus(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*stored, jc.DeepEquals, status)
+
+	status = backups.ScheduleStatus{
+		LastAttempt: attempt.Add(24 * time.Hour),
+		LastSuccess: attempt.Add(24 * time.Hour),
+	}
+	err = backups.SetScheduleStatus(s.State, status)
+	c.Assert(err, jc.ErrorIsNil)
+	stored, err = backups.GetScheduleStatus(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*stored, jc.DeepEquals, status)
+}
@@ -0,0 +1,475 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/filestorage"
+	"gopkg.in/amz.v3/aws"
+	"gopkg.in/amz.v3/s3"
+
+	"github.com/juju/juju/environs/config"
+)
+
+// targetFilename returns the name under which the identified backup
+// archive is stored in backup targets other than state.
+func targetFilename(id string) string {
+	return FilenamePrefix + id + ".tar.gz"
+}
+
+//---------------------------
+// target dispatch
+
+// targetFileStorage is a RawFileStorage that stores each backup
+// archive in the backup target recorded in its metadata. Archives
+// with no recorded target are stored in state.
+type targetFileStorage struct {
+	dbWrap        *storageDBWrapper
+	state         filestorage.RawFileStorage
+	environConfig func() (*config.Config, error)
+}
+
+func newTargetFileStorage(
+	dbWrap *storageDBWrapper,
+	state filestorage.RawFileStorage,
+	environConfig func() (*config.Config, error),
+) filestorage.RawFileStorage {
+	return &targetFileStorage{
+		dbWrap:        dbWrap.Copy(),
+		state:         state,
+		environConfig: environConfig,
+	}
+}
+
+// storage returns the raw file storage for the backup target of the
+// identified backup archive.
+func (s *targetFileStorage) storage(id string) (filestorage.RawFileStorage, error) {
+	dbWrap := s.dbWrap.Copy()
+	defer dbWrap.Close()
+
+	doc, err := getStorageMetadata(dbWrap, id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if doc.Target == "" || doc.Target == config.BackupTargetState {
+		return s.state, nil
+	}
+	cfg, err := s.environConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "while getting environment config")
+	}
+	stor, err := newTargetStorage(cfg, doc.Target)
+	return stor, errors.Trace(err)
+}
+
+// File returns the identified file from storage.
+func (s *targetFileStorage) File(id string) (io.ReadCloser, error) {
+	stor, err := s.storage(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	file, err := stor.File(id)
+	return file, errors.Trace(err)
+}
+
+// AddFile adds the file to storage.
+func (s *targetFileStorage) AddFile(id string, file io.Reader, size int64) error {
+	stor, err := s.storage(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(stor.AddFile(id, file, size))
+}
+
+// RemoveFile removes the identified file from storage.
+func (s *targetFileStorage) RemoveFile(id string) error {
+	stor, err := s.storage(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(stor.RemoveFile(id))
+}
+
+// Close closes the storage.
+func (s *targetFileStorage) Close() error {
+	err := s.state.Close()
+	s.dbWrap.Close()
+	return errors.Trace(err)
+}
+
+// newTargetStorage returns the raw file storage for the named backup
+// target, other than state, as configured in the environment config.
+var newTargetStorage = func(cfg *config.Config, target string) (filestorage.RawFileStorage, error) {
+	if err := cfg.CheckBackupTarget(target); err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch target {
+	case config.BackupTargetLocal:
+		dir, _ := cfg.BackupLocalDir()
+		return newLocalFileStorage(dir), nil
+	case config.BackupTargetS3:
+		return newS3FileStorage(cfg)
+	case config.BackupTargetSFTP:
+		return newSFTPFileStorage(cfg)
+	}
+	return nil, errors.NotSupportedf("backup target %q", target)
+}
+
+//---------------------------
+// local directory
+
+// localFileStorage stores backup archives in a directory on the
+// local machine.
+type localFileStorage struct {
+	dir string
+}
+
+func newLocalFileStorage(dir string) filestorage.RawFileStorage {
+	return &localFileStorage{dir}
+}
+
+func (s *localFileStorage) path(id string) string {
+	return filepath.Join(s.dir, targetFilename(id))
+}
+
+// File returns the identified file from storage.
+func (s *localFileStorage) File(id string) (io.ReadCloser, error) {
+	file, err := os.Open(s.path(id))
+	if os.IsNotExist(err) {
+		return nil, errors.NotFoundf("backup archive %q", id)
+	}
+	return file, errors.Trace(err)
+}
+
+// AddFile adds the file to storage.
+func (s *localFileStorage) AddFile(id string, file io.Reader, size int64) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return errors.Trace(err)
+	}
+	// Write to a temporary file first, so that a partially written
+	// archive is never mistaken for a complete one.
+	tempFile, err := ioutil.TempFile(s.dir, "tmp-")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.Remove(tempFile.Name())
+	written, err := io.Copy(tempFile, file)
+	if err := tempFile.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if written != size {
+		return errors.Errorf("expected %d bytes, wrote %d", size, written)
+	}
+	return errors.Trace(os.Rename(tempFile.Name(), s.path(id)))
+}
+
+// RemoveFile removes the identified file from storage.
+func (s *localFileStorage) RemoveFile(id string) error {
+	err := os.Remove(s.path(id))
+	if os.IsNotExist(err) {
+		return errors.NotFoundf("backup archive %q", id)
+	}
+	return errors.Trace(err)
+}
+
+// Close closes the storage.
+func (s *localFileStorage) Close() error {
+	return nil
+}
+
+//---------------------------
+// S3-compatible object store
+
+// s3FileStorage stores backup archives in a bucket in an S3-compatible
+// object store.
+type s3FileStorage struct {
+	bucket *s3.Bucket
+}
+
+func newS3FileStorage(cfg *config.Config) (filestorage.RawFileStorage, error) {
+	region, ok := aws.Regions[cfg.BackupS3Region()]
+	if endpoint, set := cfg.BackupS3Endpoint(); set {
+		// Object stores other than Amazon S3 are generally
+		// unaware of regions, so location constraints are not
+		// sent when creating buckets.
+		region = aws.Region{
+			Name:       cfg.BackupS3Region(),
+			S3Endpoint: endpoint,
+		}
+	} else if !ok {
+		return nil, errors.NotValidf("S3 region %q", cfg.BackupS3Region())
+	}
+	accessKey, secretKey := cfg.BackupS3Credentials()
+	bucketName, _ := cfg.BackupS3Bucket()
+	bucket, err := s3.New(aws.Auth{accessKey, secretKey}, region).Bucket(bucketName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &s3FileStorage{bucket}, nil
+}
+
+// File returns the identified file from storage.
+func (s *s3FileStorage) File(id string) (io.ReadCloser, error) {
+	file, err := s.bucket.GetReader(targetFilename(id))
+	if s3ErrorStatusCode(err) == 404 {
+		return nil, errors.NotFoundf("backup archive %q", id)
+	}
+	return file, errors.Trace(err)
+}
+
+// AddFile adds the file to storage.
+func (s *s3FileStorage) AddFile(id string, file io.Reader, size int64) error {
+	// PutBucket fails with a known code if the bucket already
+	// exists, for all endpoints other than Amazon's.
+	if err := s.bucket.PutBucket(s3.Private); err != nil && s3ErrorCode(err) != "BucketAlreadyOwnedByYou" {
+		return errors.Annotate(err, "cannot make S3 backups bucket")
+	}
+	err := s.bucket.PutReader(targetFilename(id), file, size, "application/x-gzip", s3.Private)
+	return errors.Trace(err)
+}
+
+// RemoveFile removes the identified file from storage.
+func (s *s3FileStorage) RemoveFile(id string) error {
+	err := s.bucket.Del(targetFilename(id))
+	if s3ErrorStatusCode(err) == 404 {
+		return errors.NotFoundf("backup archive %q", id)
+	}
+	return errors.Trace(err)
+}
+
+// Close closes the storage.
+func (s *s3FileStorage) Close() error {
+	return nil
+}
+
+// s3ErrorStatusCode returns the HTTP status of the S3 request error,
+// if it is an error from an S3 operation, or 0 if it was not.
+func s3ErrorStatusCode(err error) int {
+	if err, _ := err.(*s3.Error); err != nil {
+		return err.StatusCode
+	}
+	return 0
+}
+
+// s3ErrorCode returns the text status code of the S3 error code.
+func s3ErrorCode(err error) string {
+	if err, _ := err.(*s3.Error); err != nil {
+		return err.Code
+	}
+	return ""
+}
+
+//---------------------------
+// SFTP
+
+// sftpFileStorage stores backup archives in a directory on a remote
+// host, transferring them with the OpenSSH sftp client.
+type sftpFileStorage struct {
+	url        *url.URL
+	privateKey string
+	hostKey    string
+}
+
+func newSFTPFileStorage(cfg *config.Config) (filestorage.RawFileStorage, error) {
+	sftpURL, _ := cfg.BackupSFTPURL()
+	u, err := config.ParseBackupSFTPURL(sftpURL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	privateKey, _ := cfg.BackupSFTPPrivateKey()
+	hostKey, _ := cfg.BackupSFTPHostKey()
+	return &sftpFileStorage{u, privateKey, hostKey}, nil
+}
+
+// runSFTP runs the sftp client with the given arguments, feeding it
+// the batch of commands on stdin, and returns its standard output.
+var runSFTP = func(args []string, batch string) (string, error) {
+	cmd := exec.Command("sftp", args...)
+	cmd.Stdin = strings.NewReader(batch)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", errors.Errorf("sftp failed (%v): %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+func (s *sftpFileStorage) remotePath(id string) string {
+	return path.Join(s.url.Path, targetFilename(id))
+}
+
+// run runs the batch of sftp commands, formatted with the arguments
+// quoted, against the remote host, and returns the output. The host
+// must present the configured host key.
+func (s *sftpFileStorage) run(tempDir string, commands ...[]string) (string, error) {
+	keyFile := filepath.Join(tempDir, "id")
+	if err := ioutil.WriteFile(keyFile, []byte(s.privateKey), 0600); err != nil {
+		return "", errors.Trace(err)
+	}
+	defer os.Remove(keyFile)
+	knownHostsFile := filepath.Join(tempDir, "known_hosts")
+	if err := ioutil.WriteFile(knownHostsFile, []byte(strings.TrimSpace(s.hostKey)+"\n"), 0600); err != nil {
+		return "", errors.Trace(err)
+	}
+	defer os.Remove(knownHostsFile)
+
+	host, port := s.url.Host, "22"
+	if i := strings.LastIndex(host, ":"); i >= 0 {
+		host, port = host[:i], host[i+1:]
+	}
+	args := []string{
+		"-b", "-",
+		"-i", keyFile,
+		"-P", port,
+		"-o", "StrictHostKeyChecking=yes",
+		"-o", "UserKnownHostsFile=" + knownHostsFile,
+		"-o", "BatchMode=yes",
+		s.url.User.Username() + "@" + host,
+	}
+	var batch []string
+	for _, command := range commands {
+		line := command[0]
+		for _, arg := range command[1:] {
+			line += fmt.Sprintf(" %q", arg)
+		}
+		batch = append(batch, line)
+	}
+	return runSFTP(args, strings.Join(batch, "\n")+"\n")
+}
+
+// exists reports whether the identified archive is in the remote
+// directory. The directory is listed, rather than the archive looked
+// up, so that a missing archive can be told apart from other failures
+// without interpreting sftp's error messages.
+func (s *sftpFileStorage) exists(tempDir, id string) (bool, error) {
+	// A leading "-" tells sftp to ignore the failure of a command;
+	// ls fails if the directory does not exist yet.
+	out, err := s.run(tempDir, []string{"-ls -1", s.url.Path})
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	filename := targetFilename(id)
+	for _, line := range strings.Split(out, "\n") {
+		if path.Base(strings.TrimSpace(line)) == filename {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// File returns the identified file from storage. The archive is
+// downloaded to a temporary file, which is removed when it is closed.
+func (s *sftpFileStorage) File(id string) (io.ReadCloser, error) {
+	tempDir, err := ioutil.TempDir("", "juju-backups-sftp")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	exists, err := s.exists(tempDir, id)
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return nil, errors.Trace(err)
+	}
+	if !exists {
+		os.RemoveAll(tempDir)
+		return nil, errors.NotFoundf("backup archive %q", id)
+	}
+	localPath := filepath.Join(tempDir, targetFilename(id))
+	if _, err := s.run(tempDir, []string{"get", s.remotePath(id), localPath}); err != nil {
+		os.RemoveAll(tempDir)
+		return nil, errors.Trace(err)
+	}
+	file, err := os.Open(localPath)
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return nil, errors.Trace(err)
+	}
+	return &tempFile{file, tempDir}, nil
+}
+
+// AddFile adds the file to storage.
+func (s *sftpFileStorage) AddFile(id string, file io.Reader, size int64) error {
+	tempDir, err := ioutil.TempDir("", "juju-backups-sftp")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	localPath := filepath.Join(tempDir, targetFilename(id))
+	localFile, err := os.Create(localPath)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	written, err := io.Copy(localFile, file)
+	if err := localFile.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if written != size {
+		return errors.Errorf("expected %d bytes, wrote %d", size, written)
+	}
+	// A leading "-" tells sftp to ignore the failure of a command;
+	// mkdir fails if the directory already exists.
+	_, err = s.run(tempDir,
+		[]string{"-mkdir", s.url.Path},
+		[]string{"put", localPath, s.remotePath(id)},
+	)
+	return errors.Trace(err)
+}
+
+// RemoveFile removes the identified file from storage.
+func (s *sftpFileStorage) RemoveFile(id string) error {
+	tempDir, err := ioutil.TempDir("", "juju-backups-sftp")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	exists, err := s.exists(tempDir, id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !exists {
+		return errors.NotFoundf("backup archive %q", id)
+	}
+	_, err = s.run(tempDir, []string{"rm", s.remotePath(id)})
+	return errors.Trace(err)
+}
+
+// Close closes the storage.
+func (s *sftpFileStorage) Close() error {
+	return nil
+}
+
+// tempFile is a file within a temporary directory, which is removed
+// when the file is closed.
+type tempFile struct {
+	*os.File
+	dir string
+}
+
+// Close closes the file and removes its directory.
+func (f *tempFile) Close() error {
+	err := f.File.Close()
+	if err := os.RemoveAll(f.dir); err != nil {
+		logger.Warningf("cannot remove %q: %v", f.dir, err)
+	}
+	return errors.Trace(err)
+}
@@ -0,0 +1,201 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/filestorage"
+	"gopkg.in/amz.v3/s3/s3test"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/testing"
+	sshtesting "github.com/juju/juju/utils/ssh/testing"
+)
+
+type targetsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&targetsSuite{})
+
+const testBackupID = "20150101-120000.env-uuid"
+
+// checkRawFileStorage checks that an archive may be added to, fetched
+// from and removed from the storage.
+func checkRawFileStorage(c *gc.C, stor filestorage.RawFileStorage) {
+	_, err := stor.File(testBackupID)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = stor.AddFile(testBackupID, bytes.NewBufferString("<archive>"), 9)
+	c.Assert(err, jc.ErrorIsNil)
+
+	file, err := stor.File(testBackupID)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadAll(file)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(file.Close(), jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "<archive>")
+
+	err = stor.RemoveFile(testBackupID)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = stor.File(testBackupID)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = stor.RemoveFile(testBackupID)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *targetsSuite) TestLocalFileStorage(c *gc.C) {
+	dir := filepath.Join(c.MkDir(), "backups")
+	stor := backups.NewLocalFileStorage(dir)
+	checkRawFileStorage(c, stor)
+
+	err := stor.AddFile(testBackupID, bytes.NewBufferString("<archive>"), 9)
+	c.Assert(err, jc.ErrorIsNil)
+	infos, err := ioutil.ReadDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, gc.HasLen, 1)
+	c.Assert(infos[0].Name(), gc.Equals, "juju-backup-"+testBackupID+".tar.gz")
+}
+
+func (s *targetsSuite) TestLocalFileStorageShortWrite(c *gc.C) {
+	dir := c.MkDir()
+	stor := backups.NewLocalFileStorage(dir)
+	err := stor.AddFile(testBackupID, bytes.NewBufferString("<archive>"), 42)
+	c.Assert(err, gc.ErrorMatches, "expected 42 bytes, wrote 9")
+
+	// The partially written archive is not left behind.
+	infos, err := ioutil.ReadDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, gc.HasLen, 0)
+}
+
+func (s *targetsSuite) TestS3FileStorage(c *gc.C) {
+	srv, err := s3test.NewServer(&s3test.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+	defer srv.Quit()
+
+	cfg := testing.CustomEnvironConfig(c, testing.Attrs{
+		"backup-s3-endpoint":   srv.URL(),
+		"backup-s3-bucket":     "juju-backups",
+		"backup-s3-access-key": "access",
+		"backup-s3-secret-key": "secret",
+	})
+	stor, err := backups.NewS3FileStorage(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	checkRawFileStorage(c, stor)
+}
+
+func (s *targetsSuite) TestSFTPFileStorage(c *gc.C) {
+	remoteDir := c.MkDir()
+	hostKey := "[backups.example.com]:2222 " + sshtesting.ValidKeyOne.Key
+	var sftpArgs []string
+	s.PatchValue(backups.RunSFTP, func(args []string, batch string) (string, error) {
+		sftpArgs = args
+		c.Assert(args[6:10], jc.DeepEquals, []string{
+			"-o", "StrictHostKeyChecking=yes",
+			"-o", "UserKnownHostsFile=" + filepath.Join(filepath.Dir(args[3]), "known_hosts"),
+		})
+		knownHosts, err := ioutil.ReadFile(strings.TrimPrefix(args[9], "UserKnownHostsFile="))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(string(knownHosts), gc.Equals, hostKey+"\n")
+		return fakeSFTP(remoteDir, batch)
+	})
+
+	cfg := testing.CustomEnvironConfig(c, testing.Attrs{
+		"backup-sftp-url":         "sftp://juju@backups.example.com:2222/srv/backups",
+		"backup-sftp-private-key": "private-key",
+		"backup-sftp-host-key":    hostKey,
+	})
+	stor, err := backups.NewSFTPFileStorage(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	checkRawFileStorage(c, stor)
+
+	c.Assert(sftpArgs, gc.HasLen, 13)
+	c.Assert(sftpArgs[:2], jc.DeepEquals, []string{"-b", "-"})
+	c.Assert(sftpArgs[4:6], jc.DeepEquals, []string{"-P", "2222"})
+	c.Assert(sftpArgs[12], gc.Equals, "juju@backups.example.com")
+}
+
+func (s *targetsSuite) TestSFTPFileStorageError(c *gc.C) {
+	s.PatchValue(backups.RunSFTP, func(args []string, batch string) (string, error) {
+		return "", errors.New("sftp failed (exit status 255): Host key verification failed.")
+	})
+	cfg := testing.CustomEnvironConfig(c, testing.Attrs{
+		"backup-sftp-url":         "sftp://juju@backups.example.com/srv/backups",
+		"backup-sftp-private-key": "private-key",
+		"backup-sftp-host-key":    "backups.example.com " + sshtesting.ValidKeyOne.Key,
+	})
+	stor, err := backups.NewSFTPFileStorage(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = stor.File(testBackupID)
+	c.Assert(err, gc.ErrorMatches, "sftp failed .*: Host key verification failed.")
+	c.Assert(err, gc.Not(jc.Satisfies), errors.IsNotFound)
+	err = stor.RemoveFile(testBackupID)
+	c.Assert(err, gc.ErrorMatches, "sftp failed .*: Host key verification failed.")
+	c.Assert(err, gc.Not(jc.Satisfies), errors.IsNotFound)
+}
+
+// fakeSFTP runs the batch of sftp commands against the local
+// directory standing in for the root of the remote host, and returns
+// the output as sftp would.
+func fakeSFTP(remoteDir, batch string) (string, error) {
+	var out []string
+	for _, line := range strings.Split(strings.TrimSpace(batch), "\n") {
+		out = append(out, "sftp> "+line)
+		fields := strings.Fields(line)
+		var args []string
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, `"`) {
+				// An unquoted flag.
+				continue
+			}
+			arg, err := strconv.Unquote(field)
+			if err != nil {
+				return "", err
+			}
+			args = append(args, arg)
+		}
+		var err error
+		switch fields[0] {
+		case "-ls":
+			infos, _ := ioutil.ReadDir(filepath.Join(remoteDir, args[0]))
+			for _, info := range infos {
+				out = append(out, path.Join(args[0], info.Name()))
+			}
+		case "-mkdir":
+			os.MkdirAll(filepath.Join(remoteDir, args[0]), 0755)
+		case "put":
+			err = copyFile(filepath.Join(remoteDir, args[1]), args[0])
+		case "get":
+			err = copyFile(args[1], filepath.Join(remoteDir, args[0]))
+		case "rm":
+			err = os.Remove(filepath.Join(remoteDir, args[0]))
+		default:
+			return "", errors.Errorf("unexpected command %q", line)
+		}
+		if err != nil {
+			return "", errors.Errorf("sftp failed (exit status 1): %v", err)
+		}
+	}
+	return strings.Join(out, "\n") + "\n", nil
+}
+
+func copyFile(dest, source string) error {
+	data, err := ioutil.ReadFile(source)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dest, data, 0644)
+}
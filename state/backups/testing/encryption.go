@@ -0,0 +1,35 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"bytes"
+
+	jc "github.com/juju/testing/checkers"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	gc "gopkg.in/check.v1"
+)
+
+// NewEncryptionKey generates an OpenPGP key to which backup archives
+// may be encrypted, and returns its armored public and private keys.
+func NewEncryptionKey(c *gc.C) (publicKey, privateKey string) {
+	entity, err := openpgp.NewEntity("Juju Backups", "testing", "backups@example.com", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entity.Serialize(w), jc.ErrorIsNil)
+	c.Assert(w.Close(), jc.ErrorIsNil)
+	publicKey = buf.String()
+
+	buf.Reset()
+	w, err = armor.Encode(&buf, openpgp.PrivateKeyType, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entity.SerializePrivate(w, nil), jc.ErrorIsNil)
+	c.Assert(w.Close(), jc.ErrorIsNil)
+	privateKey = buf.String()
+	return publicKey, privateKey
+}
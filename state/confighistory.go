@@ -0,0 +1,230 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v5"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// ConfigRevision records a change made to a service's charm config
+// settings.
+type ConfigRevision struct {
+	// Revision identifies the change among those made to the
+	// service's settings. Later changes have higher revisions.
+	Revision int
+
+	// Time records when the change was made.
+	Time time.Time
+
+	// User holds the tag of the entity that made the change, if
+	// known.
+	User string
+
+	// Changes holds the settings that were changed, sorted by key.
+	Changes []ConfigChange
+
+	// Settings holds all the service's settings, as they were
+	// immediately after the change.
+	Settings charm.Settings
+}
+
+// ConfigChange describes a change to a single config setting. A nil
+// OldValue indicates that the setting was previously unset; a nil
+// NewValue, that it was unset by the change.
+type ConfigChange struct {
+	Key      string      `bson:"key"`
+	OldValue interface{} `bson:"old,omitempty"`
+	NewValue interface{} `bson:"new,omitempty"`
+}
+
+// configRevisionDoc is the persistent representation of a
+// ConfigRevision.
+type configRevisionDoc struct {
+	DocID    string                 `bson:"_id"`
+	EnvUUID  string                 `bson:"env-uuid"`
+	Service  string                 `bson:"service"`
+	Revision int                    `bson:"revision"`
+	Time     time.Time              `bson:"time"`
+	User     string                 `bson:"user,omitempty"`
+	Changes  []ConfigChange         `bson:"changes"`
+	Settings map[string]interface{} `bson:"settings"`
+}
+
+func (doc *configRevisionDoc) revision() ConfigRevision {
+	return ConfigRevision{
+		Revision: doc.Revision,
+		Time:     doc.Time.UTC(),
+		User:     doc.User,
+		Changes:  doc.Changes,
+		Settings: copyMap(doc.Settings, unescapeReplacer.Replace),
+	}
+}
+
+// configRevisionOp returns the operation that adds a revision holding
+// the given settings and the changes that produced them to the config
+// history of the service. It must be run in the same transaction as
+// the change to the settings.
+func (s *Service) configRevisionOp(changes []ItemChange, settings map[string]interface{}, user string) (txn.Op, error) {
+	seq, err := s.st.sequence(s.configHistorySequence())
+	if err != nil {
+		return txn.Op{}, errors.Annotatef(err, "cannot record config history of service %q", s.doc.Name)
+	}
+	doc := &configRevisionDoc{
+		DocID:    s.st.docID(fmt.Sprintf("%s#%d", s.doc.Name, seq+1)),
+		EnvUUID:  s.st.EnvironUUID(),
+		Service:  s.doc.Name,
+		Revision: seq + 1,
+		Time:     time.Now().UTC(),
+		User:     user,
+		Changes:  make([]ConfigChange, len(changes)),
+		Settings: copyMap(settings, escapeReplacer.Replace),
+	}
+	sort.Sort(itemChangeSlice(changes))
+	for i, change := range changes {
+		doc.Changes[i] = ConfigChange{
+			Key:      change.Key,
+			OldValue: change.OldValue,
+			NewValue: change.NewValue,
+		}
+	}
+	return txn.Op{
+		C:      serviceConfigHistoryC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}, nil
+}
+
+// configHistorySequence returns the name of the sequence used to
+// number the service's config revisions.
+func (s *Service) configHistorySequence() string {
+	return configHistorySequence(s.doc.Name)
+}
+
+func configHistorySequence(serviceName string) string {
+	return "confighistory-" + serviceName
+}
+
+// removeConfigHistoryOps returns the operations that arrange for the
+// config history of the service to be removed along with the service.
+// The history is removed by a cleanup, as it may be long; the cleanup
+// only removes the revisions recorded so far, so that it does not
+// remove those of a later service of the same name.
+func (s *Service) removeConfigHistoryOps() ([]txn.Op, error) {
+	last, err := s.st.currentSequence(s.configHistorySequence())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	prefix := fmt.Sprintf("%s#%d", s.doc.Name, last)
+	return []txn.Op{s.st.newCleanupOp(cleanupServiceConfigHistory, prefix)}, nil
+}
+
+// cleanupServiceConfigHistory removes the config history revisions
+// recorded for a service that has been removed. The prefix holds the
+// name of the service and the last revision recorded for it, separated
+// by "#".
+func (st *State) cleanupServiceConfigHistory(prefix string) error {
+	i := strings.LastIndex(prefix, "#")
+	if i < 0 {
+		return errors.Errorf("invalid config history cleanup %q", prefix)
+	}
+	last, err := strconv.Atoi(prefix[i+1:])
+	if err != nil {
+		return errors.Errorf("invalid config history cleanup %q", prefix)
+	}
+	return eraseConfigHistory(st, prefix[:i], last+1)
+}
+
+// eraseConfigHistory removes the config history revisions, lower than
+// the given revision, recorded for a service with the given name.
+func eraseConfigHistory(st *State, serviceName string, before int) error {
+	history, closer := st.getCollection(serviceConfigHistoryC)
+	defer closer()
+	_, err := history.Writeable().RemoveAll(bson.D{
+		{"service", serviceName},
+		{"revision", bson.D{{"$lt", before}}},
+	})
+	return errors.Annotatef(err, "cannot erase config history of service %q", serviceName)
+}
+
+// ConfigHistory returns the recorded changes to the service's charm
+// config settings, ordered from oldest to newest.
+func (s *Service) ConfigHistory() ([]ConfigRevision, error) {
+	history, closer := s.st.getCollection(serviceConfigHistoryC)
+	defer closer()
+
+	var docs []configRevisionDoc
+	err := history.Find(bson.D{{"service", s.doc.Name}}).Sort("revision").All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get config history of service %q", s.doc.Name)
+	}
+	revisions := make([]ConfigRevision, len(docs))
+	for i, doc := range docs {
+		revisions[i] = doc.revision()
+	}
+	return revisions, nil
+}
+
+// ConfigRevision returns the given revision of the service's charm
+// config settings.
+func (s *Service) ConfigRevision(revision int) (ConfigRevision, error) {
+	history, closer := s.st.getCollection(serviceConfigHistoryC)
+	defer closer()
+
+	var doc configRevisionDoc
+	err := history.FindId(fmt.Sprintf("%s#%d", s.doc.Name, revision)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return ConfigRevision{}, errors.NotFoundf("config revision %d of service %q", revision, s.doc.Name)
+	} else if err != nil {
+		return ConfigRevision{}, errors.Annotatef(err, "cannot get config revision %d of service %q", revision, s.doc.Name)
+	}
+	return doc.revision(), nil
+}
+
+// RevertConfigSettings changes the service's charm config settings
+// back to those recorded in the given revision of its config history.
+// Settings that are not defined by the service's current charm are
+// ignored. The change is recorded as a new revision, made by the
+// given user.
+func (s *Service) RevertConfigSettings(revision int, user string) error {
+	rev, err := s.ConfigRevision(revision)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ch, _, err := s.Charm()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	current, err := s.ConfigSettings()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	options := ch.Config().Options
+	changes := make(charm.Settings)
+	for name := range current {
+		if _, ok := rev.Settings[name]; !ok {
+			changes[name] = nil
+		}
+	}
+	for name, value := range rev.Settings {
+		changes[name] = value
+	}
+	for name := range changes {
+		if _, ok := options[name]; !ok {
+			delete(changes, name)
+		}
+	}
+	err = s.updateConfigSettings(changes, user)
+	return errors.Annotatef(err, "cannot revert service %q to config revision %d", s.doc.Name, revision)
+}
@@ -0,0 +1,224 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type configHistorySuite struct {
+	ConnSuite
+	charm   *state.Charm
+	service *state.Service
+}
+
+var _ = gc.Suite(&configHistorySuite{})
+
+func (s *configHistorySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.charm = s.AddTestingCharm(c, "dummy")
+	s.service = s.AddTestingService(c, "dummy", s.charm)
+}
+
+func (s *configHistorySuite) assertSettings(c *gc.C, expect charm.Settings) {
+	settings, err := s.service.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, expect)
+}
+
+func (s *configHistorySuite) TestInitialRevision(c *gc.C) {
+	history, err := s.service.ConfigHistory()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Revision, gc.Equals, 1)
+	c.Assert(history[0].User, gc.Equals, s.Owner.String())
+	c.Assert(history[0].Changes, gc.HasLen, 0)
+	c.Assert(history[0].Settings, gc.HasLen, 0)
+	c.Assert(history[0].Time.IsZero(), jc.IsFalse)
+}
+
+func (s *configHistorySuite) TestUpdateConfigSettingsRecordsRevisions(c *gc.C) {
+	err := s.service.UpdateConfigSettingsByUser(charm.Settings{
+		"title":       "Foo",
+		"skill-level": 9000,
+	}, "user-bob")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.UpdateConfigSettings(charm.Settings{
+		"title":   nil,
+		"outlook": "cloudy",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.service.ConfigHistory()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 3)
+
+	c.Assert(history[1].Revision, gc.Equals, 2)
+	c.Assert(history[1].User, gc.Equals, "user-bob")
+	c.Assert(history[1].Changes, jc.DeepEquals, []state.ConfigChange{
+		{Key: "skill-level", NewValue: int64(9000)},
+		{Key: "title", NewValue: "Foo"},
+	})
+	c.Assert(history[1].Settings, jc.DeepEquals, charm.Settings{
+		"title":       "Foo",
+		"skill-level": int64(9000),
+	})
+
+	c.Assert(history[2].Revision, gc.Equals, 3)
+	c.Assert(history[2].User, gc.Equals, "")
+	c.Assert(history[2].Changes, jc.DeepEquals, []state.ConfigChange{
+		{Key: "outlook", NewValue: "cloudy"},
+		{Key: "title", OldValue: "Foo"},
+	})
+	c.Assert(history[2].Settings, jc.DeepEquals, charm.Settings{
+		"outlook":     "cloudy",
+		"skill-level": int64(9000),
+	})
+
+	rev, err := s.service.ConfigRevision(2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rev, jc.DeepEquals, history[1])
+}
+
+func (s *configHistorySuite) TestUnchangedSettingsNotRecorded(c *gc.C) {
+	err := s.service.UpdateConfigSettings(charm.Settings{"title": "Foo"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.UpdateConfigSettings(charm.Settings{"title": "Foo", "outlook": nil})
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.service.ConfigHistory()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+}
+
+func (s *configHistorySuite) TestUpdateConfigSettingsConcurrentChange(c *gc.C) {
+	defer state.SetBeforeHooks(c, s.State, func() {
+		err := s.service.UpdateConfigSettings(charm.Settings{"outlook": "cloudy"})
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	err := s.service.UpdateConfigSettings(charm.Settings{"title": "Foo"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertSettings(c, charm.Settings{"title": "Foo", "outlook": "cloudy"})
+
+	history, err := s.service.ConfigHistory()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 3)
+	c.Assert(history[1].Settings, jc.DeepEquals, charm.Settings{"outlook": "cloudy"})
+	c.Assert(history[2].Changes, jc.DeepEquals, []state.ConfigChange{
+		{Key: "title", NewValue: "Foo"},
+	})
+	c.Assert(history[2].Settings, jc.DeepEquals, charm.Settings{
+		"title":   "Foo",
+		"outlook": "cloudy",
+	})
+}
+
+func (s *configHistorySuite) TestConfigRevisionNotFound(c *gc.C) {
+	_, err := s.service.ConfigRevision(42)
+	c.Assert(err, gc.ErrorMatches, `config revision 42 of service "dummy" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *configHistorySuite) TestRevertConfigSettings(c *gc.C) {
+	err := s.service.UpdateConfigSettings(charm.Settings{"title": "Foo", "skill-level": 1})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.UpdateConfigSettings(charm.Settings{"title": nil, "outlook": "cloudy"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.service.RevertConfigSettings(2, "user-bob")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertSettings(c, charm.Settings{"title": "Foo", "skill-level": int64(1)})
+
+	history, err := s.service.ConfigHistory()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 4)
+	c.Assert(history[3].Revision, gc.Equals, 4)
+	c.Assert(history[3].User, gc.Equals, "user-bob")
+	c.Assert(history[3].Changes, jc.DeepEquals, []state.ConfigChange{
+		{Key: "outlook", OldValue: "cloudy"},
+		{Key: "title", NewValue: "Foo"},
+	})
+
+	// Reverting to the initial revision restores the defaults.
+	err = s.service.RevertConfigSettings(1, "user-bob")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertSettings(c, charm.Settings{})
+}
+
+func (s *configHistorySuite) TestRevertConfigSettingsUnknownRevision(c *gc.C) {
+	err := s.service.RevertConfigSettings(42, "user-bob")
+	c.Assert(err, gc.ErrorMatches, `config revision 42 of service "dummy" not found`)
+}
+
+func (s *configHistorySuite) TestRevertConfigSettingsNotifiesUnits(c *gc.C) {
+	err := s.service.UpdateConfigSettings(charm.Settings{"title": "Foo"})
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetCharmURL(s.charm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	w, err := unit.WatchConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	defer testing.AssertStop(c, w)
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err = s.service.RevertConfigSettings(1, "user-bob")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
+func (s *configHistorySuite) TestReplacingServiceErasesHistory(c *gc.C) {
+	err := s.service.UpdateConfigSettings(charm.Settings{"title": "Foo"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.service = s.AddTestingService(c, "dummy", s.charm)
+	history, err := s.service.ConfigHistory()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 1)
+	c.Assert(history[0].Settings, gc.HasLen, 0)
+}
+
+func (s *configHistorySuite) TestRemovingServiceErasesHistory(c *gc.C) {
+	err := s.service.UpdateConfigSettings(charm.Settings{"title": "Foo"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, closer := state.GetCollection(s.State, "serviceconfighistory")
+	defer closer()
+	count, err := history.Find(nil).Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 0)
+}
+
+func (s *configHistorySuite) TestCleanupKeepsHistoryOfReplacementService(c *gc.C) {
+	err := s.service.UpdateConfigSettings(charm.Settings{"title": "Foo"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.service = s.AddTestingService(c, "dummy", s.charm)
+	err = s.service.UpdateConfigSettings(charm.Settings{"title": "Bar"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.service.ConfigHistory()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Revision, gc.Equals, 3)
+	c.Assert(history[1].Settings, jc.DeepEquals, charm.Settings{"title": "Bar"})
+}
@@ -12,6 +12,7 @@ import (
 	"gopkg.in/mgo.v2"
 
 	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/tracing"
 )
 
 type SessionCloser func()
@@ -53,6 +54,11 @@ type Database interface {
 	// Schema returns the schema used to load the database. The returned schema
 	// is not a copy and must not be modified.
 	Schema() collectionSchema
+
+	// Traced returns a matching Database, sharing this one's session, whose
+	// transaction runners record the transactions they run as children of
+	// the given span.
+	Traced(span *tracing.Span) Database
 }
 
 // collectionInfo describes important features of a collection.
@@ -153,6 +159,9 @@ type database struct {
 	// ownSession is used to avoid copying additional sessions in a database
 	// resulting from CopySession.
 	ownSession bool
+
+	// span, if non-nil, is the span on whose behalf transactions are run.
+	span *tracing.Span
 }
 
 // CopySession is part of the Database interface.
@@ -164,6 +173,7 @@ func (db *database) CopySession() (Database, SessionCloser) {
 		environUUID: db.environUUID,
 		runner:      db.runner,
 		ownSession:  true,
+		span:        db.span,
 	}, session.Close
 }
 
@@ -217,6 +227,7 @@ func (db *database) TransactionRunner() (runner jujutxn.Runner, closer SessionCl
 		rawRunner: runner,
 		envUUID:   db.environUUID,
 		schema:    db.schema,
+		span:      db.span,
 	}, closer
 }
 
@@ -224,3 +235,10 @@ func (db *database) TransactionRunner() (runner jujutxn.Runner, closer SessionCl
 func (db *database) Schema() collectionSchema {
 	return db.schema
 }
+
+// Traced is part of the Database interface.
+func (db *database) Traced(span *tracing.Span) Database {
+	traced := *db
+	traced.span = span
+	return &traced
+}
@@ -38,6 +38,57 @@ type envUserDoc struct {
 	// It is really informational only as far as everyone except the
 	// api server is concerned.
 	LastConnection *time.Time `bson:"lastconnection"`
+	// Access is empty for environment users added before access
+	// levels were introduced; they are treated as administrators.
+	Access EnvironmentAccess `bson:"access,omitempty"`
+}
+
+// EnvironmentAccess defines the level of access an environment user
+// has to the environment.
+type EnvironmentAccess string
+
+const (
+	// EnvironmentReadAccess allows a user to inspect the environment
+	// but not change it.
+	EnvironmentReadAccess EnvironmentAccess = "read"
+
+	// EnvironmentWriteAccess allows a user to change the environment,
+	// for example by deploying services and adding machines.
+	EnvironmentWriteAccess EnvironmentAccess = "write"
+
+	// EnvironmentAdminAccess allows a user to do anything to the
+	// environment, including sharing it with other users and
+	// destroying it.
+	EnvironmentAdminAccess EnvironmentAccess = "admin"
+)
+
+// Validate returns an error if the access level is not one of those
+// known.
+func (a EnvironmentAccess) Validate() error {
+	switch a {
+	case EnvironmentReadAccess, EnvironmentWriteAccess, EnvironmentAdminAccess:
+		return nil
+	}
+	return errors.NotValidf("environment access %q", string(a))
+}
+
+// accessLevels orders the known access levels, each including those
+// below it.
+var accessLevels = map[EnvironmentAccess]int{
+	EnvironmentReadAccess:  1,
+	EnvironmentWriteAccess: 2,
+	EnvironmentAdminAccess: 3,
+}
+
+// Includes returns whether the access level allows everything the
+// other access level does.
+func (a EnvironmentAccess) Includes(other EnvironmentAccess) bool {
+	level, ok := accessLevels[a]
+	if !ok {
+		return false
+	}
+	otherLevel, ok := accessLevels[other]
+	return ok && level >= otherLevel
 }
 
 // ID returns the ID of the environment user.
@@ -75,6 +126,38 @@ func (e *EnvironmentUser) DateCreated() time.Time {
 	return e.doc.DateCreated.UTC()
 }
 
+// Access returns the level of access the environment user has to the
+// environment.
+func (e *EnvironmentUser) Access() EnvironmentAccess {
+	if e.doc.Access == "" {
+		return EnvironmentAdminAccess
+	}
+	return e.doc.Access
+}
+
+// SetAccess changes the level of access the environment user has to
+// the environment.
+func (e *EnvironmentUser) SetAccess(access EnvironmentAccess) error {
+	if err := access.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      envUsersC,
+		Id:     envUserID(e.UserTag()),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"access", access}}}},
+	}}
+	err := e.st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("environment user %q", e.UserName())
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot set access for environment user %q", e.UserName())
+	}
+	e.doc.Access = access
+	return nil
+}
+
 // LastLogin returns when this EnvironmentUser last connected through the API
 // in UTC. The resulting time will be nil if the user has never logged in.
 func (e *EnvironmentUser) LastConnection() *time.Time {
@@ -129,8 +212,13 @@ func (st *State) EnvironmentUser(user names.UserTag) (*EnvironmentUser, error) {
 	return envUser, nil
 }
 
-// AddEnvironmentUser adds a new user to the database.
-func (st *State) AddEnvironmentUser(user, createdBy names.UserTag, displayName string) (*EnvironmentUser, error) {
+// AddEnvironmentUser adds a new user to the database, with the given
+// level of access to the environment.
+func (st *State) AddEnvironmentUser(user, createdBy names.UserTag, displayName string, access EnvironmentAccess) (*EnvironmentUser, error) {
+	if err := access.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	// Ensure local user exists in state before adding them as an environment user.
 	if user.IsLocal() {
 		localUser, err := st.User(user)
@@ -150,7 +238,7 @@ func (st *State) AddEnvironmentUser(user, createdBy names.UserTag, displayName s
 	}
 
 	envuuid := st.EnvironUUID()
-	op, doc := createEnvUserOpAndDoc(envuuid, user, createdBy, displayName)
+	op, doc := createEnvUserOpAndDoc(envuuid, user, createdBy, displayName, access)
 	err := st.runTransaction([]txn.Op{op})
 	if err == txn.ErrAborted {
 		err = errors.AlreadyExistsf("environment user %q", user.Username())
@@ -167,7 +255,7 @@ func envUserID(user names.UserTag) string {
 	return strings.ToLower(username)
 }
 
-func createEnvUserOpAndDoc(envuuid string, user, createdBy names.UserTag, displayName string) (txn.Op, *envUserDoc) {
+func createEnvUserOpAndDoc(envuuid string, user, createdBy names.UserTag, displayName string, access EnvironmentAccess) (txn.Op, *envUserDoc) {
 	creatorname := createdBy.Username()
 	doc := &envUserDoc{
 		ID:          envUserID(user),
@@ -176,6 +264,7 @@ func createEnvUserOpAndDoc(envuuid string, user, createdBy names.UserTag, displa
 		DisplayName: displayName,
 		CreatedBy:   creatorname,
 		DateCreated: nowToTheSecond(),
+		Access:      access,
 	}
 	op := txn.Op{
 		C:      envUsersC,
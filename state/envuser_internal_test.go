@@ -17,18 +17,26 @@ var _ = gc.Suite(&internalEnvUserSuite{})
 
 func (s *internalEnvUserSuite) TestCreateEnvUserOpAndDoc(c *gc.C) {
 	tag := names.NewUserTag("UserName")
-	op, doc := createEnvUserOpAndDoc("ignored", tag, names.NewUserTag("ignored"), "ignored")
+	op, doc := createEnvUserOpAndDoc("ignored", tag, names.NewUserTag("ignored"), "ignored", EnvironmentReadAccess)
 
 	c.Assert(op.Id, gc.Equals, "username@local")
 	c.Assert(doc.ID, gc.Equals, "username@local")
 	c.Assert(doc.UserName, gc.Equals, "UserName@local")
+	c.Assert(doc.Access, gc.Equals, EnvironmentReadAccess)
+}
+
+func (s *internalEnvUserSuite) TestAccessDefaultsToAdmin(c *gc.C) {
+	// Environment users added before access levels were introduced
+	// have no access recorded.
+	user := &EnvironmentUser{st: s.state, doc: envUserDoc{}}
+	c.Assert(user.Access(), gc.Equals, EnvironmentAdminAccess)
 }
 
 func (s *internalEnvUserSuite) TestCaseUserNameVsId(c *gc.C) {
 	env, err := s.state.Environment()
 	c.Assert(err, jc.ErrorIsNil)
 
-	user, err := s.state.AddEnvironmentUser(names.NewUserTag("Bob@RandomProvider"), env.Owner(), "")
+	user, err := s.state.AddEnvironmentUser(names.NewUserTag("Bob@RandomProvider"), env.Owner(), "", EnvironmentAdminAccess)
 	c.Assert(err, gc.IsNil)
 	c.Assert(user.UserName(), gc.Equals, "Bob@RandomProvider")
 	c.Assert(user.doc.ID, gc.Equals, s.state.docID("bob@randomprovider"))
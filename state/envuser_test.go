@@ -28,7 +28,7 @@ func (s *EnvUserSuite) TestAddEnvironmentUser(c *gc.C) {
 	now := state.NowToTheSecond()
 	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "validusername", NoEnvUser: true})
 	createdBy := s.Factory.MakeUser(c, &factory.UserParams{Name: "createdby"})
-	envUser, err := s.State.AddEnvironmentUser(user.UserTag(), createdBy.UserTag(), "")
+	envUser, err := s.State.AddEnvironmentUser(user.UserTag(), createdBy.UserTag(), "", state.EnvironmentAdminAccess)
 	c.Assert(err, jc.ErrorIsNil)
 
 	c.Assert(envUser.ID(), gc.Equals, fmt.Sprintf("%s:validusername@local", s.envTag.Id()))
@@ -48,6 +48,7 @@ func (s *EnvUserSuite) TestAddEnvironmentUser(c *gc.C) {
 	c.Assert(envUser.CreatedBy(), gc.Equals, "createdby@local")
 	c.Assert(envUser.DateCreated().Equal(now) || envUser.DateCreated().After(now), jc.IsTrue)
 	c.Assert(envUser.LastConnection(), gc.IsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentAdminAccess)
 }
 
 func (s *EnvUserSuite) TestCaseSensitiveEnvUserErrors(c *gc.C) {
@@ -55,7 +56,7 @@ func (s *EnvUserSuite) TestCaseSensitiveEnvUserErrors(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	s.Factory.MakeEnvUser(c, &factory.EnvUserParams{User: "Bob@ubuntuone"})
 
-	_, err = s.State.AddEnvironmentUser(names.NewUserTag("boB@ubuntuone"), env.Owner(), "")
+	_, err = s.State.AddEnvironmentUser(names.NewUserTag("boB@ubuntuone"), env.Owner(), "", state.EnvironmentAdminAccess)
 	c.Assert(err, gc.ErrorMatches, `environment user "boB@ubuntuone" already exists`)
 	c.Assert(errors.IsAlreadyExists(err), jc.IsTrue)
 }
@@ -101,16 +102,69 @@ func (s *EnvUserSuite) TestAddEnvironmentDisplayName(c *gc.C) {
 
 func (s *EnvUserSuite) TestAddEnvironmentNoUserFails(c *gc.C) {
 	createdBy := s.Factory.MakeUser(c, &factory.UserParams{Name: "createdby"})
-	_, err := s.State.AddEnvironmentUser(names.NewLocalUserTag("validusername"), createdBy.UserTag(), "")
+	_, err := s.State.AddEnvironmentUser(names.NewLocalUserTag("validusername"), createdBy.UserTag(), "", state.EnvironmentAdminAccess)
 	c.Assert(err, gc.ErrorMatches, `user "validusername" does not exist locally: user "validusername" not found`)
 }
 
 func (s *EnvUserSuite) TestAddEnvironmentNoCreatedByUserFails(c *gc.C) {
 	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "validusername"})
-	_, err := s.State.AddEnvironmentUser(user.UserTag(), names.NewLocalUserTag("createdby"), "")
+	_, err := s.State.AddEnvironmentUser(user.UserTag(), names.NewLocalUserTag("createdby"), "", state.EnvironmentAdminAccess)
 	c.Assert(err, gc.ErrorMatches, `createdBy user "createdby" does not exist locally: user "createdby" not found`)
 }
 
+func (s *EnvUserSuite) TestAddEnvironmentUserInvalidAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "validusername", NoEnvUser: true})
+	_, err := s.State.AddEnvironmentUser(user.UserTag(), s.Owner, "", "superuser")
+	c.Assert(err, gc.ErrorMatches, `environment access "superuser" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *EnvUserSuite) TestSetAccess(c *gc.C) {
+	envUser := s.Factory.MakeEnvUser(c, &factory.EnvUserParams{Access: state.EnvironmentReadAccess})
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentReadAccess)
+
+	err := envUser.SetAccess(state.EnvironmentWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentWriteAccess)
+
+	envUser, err = s.State.EnvironmentUser(envUser.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentWriteAccess)
+
+	err = envUser.SetAccess("superuser")
+	c.Assert(err, gc.ErrorMatches, `environment access "superuser" not valid`)
+	c.Assert(envUser.Access(), gc.Equals, state.EnvironmentWriteAccess)
+}
+
+func (s *EnvUserSuite) TestAccessIncludes(c *gc.C) {
+	for i, test := range []struct {
+		access   state.EnvironmentAccess
+		other    state.EnvironmentAccess
+		includes bool
+	}{
+		{state.EnvironmentReadAccess, state.EnvironmentReadAccess, true},
+		{state.EnvironmentReadAccess, state.EnvironmentWriteAccess, false},
+		{state.EnvironmentWriteAccess, state.EnvironmentReadAccess, true},
+		{state.EnvironmentWriteAccess, state.EnvironmentAdminAccess, false},
+		{state.EnvironmentAdminAccess, state.EnvironmentWriteAccess, true},
+		{state.EnvironmentAdminAccess, "superuser", false},
+		{"superuser", state.EnvironmentReadAccess, false},
+	} {
+		c.Logf("test %d: %q includes %q", i, test.access, test.other)
+		c.Check(test.access.Includes(test.other), gc.Equals, test.includes)
+	}
+}
+
+func (s *EnvUserSuite) TestSetAccessRemovedUser(c *gc.C) {
+	envUser := s.Factory.MakeEnvUser(c, nil)
+	err := s.State.RemoveEnvironmentUser(envUser.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = envUser.SetAccess(state.EnvironmentReadAccess)
+	c.Assert(err, gc.ErrorMatches, `cannot set access for environment user ".*": environment user ".*" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *EnvUserSuite) TestRemoveEnvironmentUser(c *gc.C) {
 	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "validUsername"})
 	_, err := s.State.EnvironmentUser(user.UserTag())
@@ -203,7 +257,7 @@ func (s *EnvUserSuite) newEnvWithUser(c *gc.C, name string, user names.UserTag)
 	newEnv, err := envState.Environment()
 	c.Assert(err, jc.ErrorIsNil)
 
-	_, err = envState.AddEnvironmentUser(user, newEnv.Owner(), "")
+	_, err = envState.AddEnvironmentUser(user, newEnv.Owner(), "", state.EnvironmentAdminAccess)
 	c.Assert(err, jc.ErrorIsNil)
 	return newEnv
 }
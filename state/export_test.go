@@ -411,8 +411,18 @@ func NewHistoricalStatusDoc(id int, s StatusDoc, key string) *historicalStatusDo
 	return newHistoricalStatusDoc(id, sdoc, key)
 }
 
-var StatusHistory = statusHistory
-var UpdateStatusHistory = updateStatusHistory
+func StatusHistory(size int, globalKey string, st *State) ([]StatusInfo, error) {
+	return statusHistory(StatusHistoryFilter{Size: size}, globalKey, st)
+}
+
+// PatchableNow allows tests to patch the clock used to timestamp
+// documents.
+var PatchableNow = &nowToTheSecond
+
+var (
+	FilteredStatusHistory = statusHistory
+	UpdateStatusHistory   = updateStatusHistory
+)
 
 func EraseUnitHistory(u *Unit) error {
 	return u.eraseHistory()
@@ -95,6 +95,7 @@ func (s *imageStorage) AddImage(r io.Reader, metadata *Metadata) (resultErr erro
 		}
 	}()
 
+	now := time.Now()
 	newDoc := imageMetadataDoc{
 		Id:        docId(metadata),
 		EnvUUID:   s.envUUID,
@@ -105,7 +106,8 @@ func (s *imageStorage) AddImage(r io.Reader, metadata *Metadata) (resultErr erro
 		SHA256:    metadata.SHA256,
 		SourceURL: metadata.SourceURL,
 		Path:      path,
-		Created:   time.Now(),
+		Created:   now,
+		LastUsed:  now,
 	}
 
 	// Add or replace metadata. If replacing, record the
@@ -169,16 +171,7 @@ func (s *imageStorage) ListImages(filter ImageFilter) ([]*Metadata, error) {
 	}
 	result := make([]*Metadata, len(metadataDocs))
 	for i, metadataDoc := range metadataDocs {
-		result[i] = &Metadata{
-			EnvUUID:   s.envUUID,
-			Kind:      metadataDoc.Kind,
-			Series:    metadataDoc.Series,
-			Arch:      metadataDoc.Arch,
-			Size:      metadataDoc.Size,
-			SHA256:    metadataDoc.SHA256,
-			Created:   metadataDoc.Created,
-			SourceURL: metadataDoc.SourceURL,
-		}
+		result[i] = s.metadata(metadataDoc)
 	}
 	return result, nil
 }
@@ -234,16 +227,15 @@ func (s *imageStorage) Image(kind, series, arch string) (*Metadata, io.ReadClose
 	if err != nil {
 		return nil, nil, err
 	}
-	metadata := &Metadata{
-		EnvUUID:   s.envUUID,
-		Kind:      metadataDoc.Kind,
-		Series:    metadataDoc.Series,
-		Arch:      metadataDoc.Arch,
-		Size:      metadataDoc.Size,
-		SHA256:    metadataDoc.SHA256,
-		SourceURL: metadataDoc.SourceURL,
-		Created:   metadataDoc.Created,
+	// Record the use of the image, so that those no longer used
+	// can be found. Failure is non-fatal.
+	lastUsed := time.Now()
+	if err := s.setLastUsed(session, metadataDoc.Id, lastUsed); err != nil {
+		logger.Errorf("failed to record use of image %v: %v", metadataDoc.Id, err)
+	} else {
+		metadataDoc.LastUsed = lastUsed
 	}
+	metadata := s.metadata(metadataDoc)
 	imageResult := &imageCloser{
 		image,
 		session,
@@ -262,6 +254,41 @@ type imageMetadataDoc struct {
 	Path      string    `bson:"path"`
 	Created   time.Time `bson:"created"`
 	SourceURL string    `bson:"sourceurl"`
+	LastUsed  time.Time `bson:"lastused,omitempty"`
+}
+
+// metadata returns the Metadata described by the given document.
+func (s *imageStorage) metadata(doc imageMetadataDoc) *Metadata {
+	lastUsed := doc.LastUsed
+	if lastUsed.IsZero() {
+		// The image was cached before its use was recorded.
+		lastUsed = doc.Created
+	}
+	return &Metadata{
+		EnvUUID:   s.envUUID,
+		Kind:      doc.Kind,
+		Series:    doc.Series,
+		Arch:      doc.Arch,
+		Size:      doc.Size,
+		SHA256:    doc.SHA256,
+		SourceURL: doc.SourceURL,
+		Created:   doc.Created,
+		LastUsed:  lastUsed,
+	}
+}
+
+// setLastUsed records the time at which the image with the given
+// metadata document id was last used.
+func (s *imageStorage) setLastUsed(session *mgo.Session, id string, lastUsed time.Time) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		return []txn.Op{{
+			C:      imagemetadataC,
+			Id:     id,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"lastused", lastUsed}}}},
+		}}, nil
+	}
+	return s.txnRunner(session).Run(buildTxn)
 }
 
 func (s *imageStorage) imageMetadataDoc(envUUID, kind, series, arch string) (imageMetadataDoc, error) {
@@ -20,6 +20,7 @@ import (
 	txntesting "github.com/juju/txn/testing"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/state/imagestorage"
 	"github.com/juju/juju/testing"
@@ -77,7 +78,9 @@ func (s *ImageSuite) TestAddImageReplaces(c *gc.C) {
 func checkMetadata(c *gc.C, fromDb, metadata *imagestorage.Metadata) {
 	c.Assert(fromDb.Created.IsZero(), jc.IsFalse)
 	c.Assert(fromDb.Created.Before(time.Now()), jc.IsTrue)
+	c.Assert(fromDb.LastUsed.Before(fromDb.Created), jc.IsFalse)
 	fromDb.Created = time.Time{}
+	fromDb.LastUsed = time.Time{}
 	c.Assert(metadata, gc.DeepEquals, fromDb)
 }
 
@@ -145,6 +148,34 @@ func (s *ImageSuite) TestImage(c *gc.C) {
 	c.Assert(string(data), gc.Equals, "blah")
 }
 
+func (s *ImageSuite) TestImageRecordsLastUsed(c *gc.C) {
+	s.addMetadataDoc(c, "lxc", "trusty", "amd64", 3, "hash(abc)", "path", "http://path")
+	err := s.metadataCollection.UpdateId("my-uuid-lxc-trusty-amd64", bson.D{{
+		"$set", bson.D{{"created", time.Now().Add(-time.Hour)}},
+	}})
+	c.Assert(err, gc.IsNil)
+	managedStorage := imagestorage.ManagedStorage(s.storage, s.session)
+	err = managedStorage.PutForEnvironment("my-uuid", "path", strings.NewReader("abc"), 3)
+	c.Assert(err, gc.IsNil)
+
+	// Images which have never been fetched were last used when cached.
+	metadata, err := s.storage.ListImages(imagestorage.ImageFilter{})
+	c.Assert(err, gc.IsNil)
+	c.Assert(metadata, gc.HasLen, 1)
+	c.Assert(metadata[0].LastUsed, gc.Equals, metadata[0].Created)
+
+	before := time.Now()
+	fetched, r, err := s.storage.Image("lxc", "trusty", "amd64")
+	c.Assert(err, gc.IsNil)
+	r.Close()
+	c.Assert(fetched.LastUsed.Before(before), jc.IsFalse)
+
+	metadata, err = s.storage.ListImages(imagestorage.ImageFilter{})
+	c.Assert(err, gc.IsNil)
+	c.Assert(metadata, gc.HasLen, 1)
+	c.Assert(metadata[0].LastUsed.Before(before.Add(-time.Second)), jc.IsFalse)
+}
+
 func (s *ImageSuite) TestAddImageRemovesExisting(c *gc.C) {
 	// Add a metadata doc and a blob at a known path, then
 	// call AddImage and ensure the original blob is removed.
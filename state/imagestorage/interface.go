@@ -18,6 +18,10 @@ type Metadata struct {
 	SHA256    string
 	Created   time.Time
 	SourceURL string
+
+	// LastUsed is when the image was last fetched from storage
+	// by Image, or when it was added if it has not been fetched.
+	LastUsed time.Time
 }
 
 // ImageFilter is used to query image metadata.
@@ -38,7 +42,8 @@ type Storage interface {
 
 	// Image returns the Metadata and image blob contents
 	// for the specified kind, series, arch if it exists, else an error
-	// satisfying errors.IsNotFound.
+	// satisfying errors.IsNotFound. The image's last used time is
+	// updated.
 	Image(kind, series, arch string) (*Metadata, io.ReadCloser, error)
 
 	// ListImages returns the image metadata matching the specified filter.
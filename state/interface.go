@@ -4,6 +4,8 @@
 package state
 
 import (
+	"time"
+
 	"github.com/juju/names"
 
 	"github.com/juju/juju/environs/config"
@@ -138,6 +140,11 @@ type ActionReceiver interface {
 	// ActionReceiver.
 	AddAction(name string, payload map[string]interface{}) (*Action, error)
 
+	// AddActionWithTimeout queues an action with the given name and
+	// payload for this ActionReceiver, to be killed if it runs for
+	// longer than timeout.
+	AddActionWithTimeout(name string, payload map[string]interface{}, timeout time.Duration) (*Action, error)
+
 	// CancelAction removes a pending Action from the queue for this
 	// ActionReceiver and marks it as cancelled.
 	CancelAction(action *Action) (*Action, error)
@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// logForwardingDoc records how far a named log forwarder has got
+// through an environment's logs.
+type logForwardingDoc struct {
+	DocID    string        `bson:"_id"`
+	EnvUUID  string        `bson:"env-uuid"`
+	Name     string        `bson:"name"`
+	LastSent bson.ObjectId `bson:"last-sent-id"`
+}
+
+// LogForwardingCursor returns the id of the most recent log record
+// that the named log forwarder has reported as delivered. If the
+// forwarder has not recorded any progress, an empty id is returned.
+func (st *State) LogForwardingCursor(name string) (bson.ObjectId, error) {
+	coll, closer := st.getCollection(logForwardingC)
+	defer closer()
+
+	var doc logForwardingDoc
+	err := coll.FindId(name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Annotatef(err, "cannot get cursor for log forwarder %q", name)
+	}
+	return doc.LastSent, nil
+}
+
+// SetLogForwardingCursor records that the named log forwarder has
+// delivered all log records up to and including the one with the
+// given id.
+func (st *State) SetLogForwardingCursor(name string, lastSent bson.ObjectId) error {
+	if name == "" {
+		return errors.NotValidf("empty log forwarder name")
+	}
+	if !lastSent.Valid() {
+		return errors.NotValidf("log record id %q", lastSent)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		coll, closer := st.getCollection(logForwardingC)
+		defer closer()
+
+		count, err := coll.FindId(name).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if count == 0 {
+			return []txn.Op{{
+				C:      logForwardingC,
+				Id:     st.docID(name),
+				Assert: txn.DocMissing,
+				Insert: &logForwardingDoc{
+					Name:     name,
+					LastSent: lastSent,
+				},
+			}}, nil
+		}
+		return []txn.Op{{
+			C:      logForwardingC,
+			Id:     st.docID(name),
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"last-sent-id", lastSent}}}},
+		}}, nil
+	}
+	err := st.run(buildTxn)
+	return errors.Annotatef(err, "cannot set cursor for log forwarder %q", name)
+}
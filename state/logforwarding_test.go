@@ -0,0 +1,64 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type logForwardingSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&logForwardingSuite{})
+
+func (s *logForwardingSuite) TestCursorInitiallyEmpty(c *gc.C) {
+	id, err := s.State.LogForwardingCursor("syslog")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, bson.ObjectId(""))
+}
+
+func (s *logForwardingSuite) TestSetCursor(c *gc.C) {
+	first := bson.NewObjectId()
+	err := s.State.SetLogForwardingCursor("syslog", first)
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := s.State.LogForwardingCursor("syslog")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, first)
+
+	second := bson.NewObjectId()
+	err = s.State.SetLogForwardingCursor("syslog", second)
+	c.Assert(err, jc.ErrorIsNil)
+	id, err = s.State.LogForwardingCursor("syslog")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, second)
+
+	// Other forwarders are unaffected.
+	id, err = s.State.LogForwardingCursor("other")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, bson.ObjectId(""))
+}
+
+func (s *logForwardingSuite) TestSetCursorEmptyName(c *gc.C) {
+	err := s.State.SetLogForwardingCursor("", bson.NewObjectId())
+	c.Assert(err, gc.ErrorMatches, "empty log forwarder name not valid")
+}
+
+func (s *logForwardingSuite) TestSetCursorInvalidId(c *gc.C) {
+	err := s.State.SetLogForwardingCursor("syslog", "")
+	c.Assert(err, gc.ErrorMatches, `log record id "" not valid`)
+}
+
+func (s *logForwardingSuite) TestCursorPerEnvironment(c *gc.C) {
+	err := s.State.SetLogForwardingCursor("syslog", bson.NewObjectId())
+	c.Assert(err, jc.ErrorIsNil)
+
+	otherState := s.Factory.MakeEnvironment(c, nil)
+	defer otherState.Close()
+	id, err := otherState.LogForwardingCursor("syslog")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, bson.ObjectId(""))
+}
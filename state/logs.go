@@ -126,6 +126,9 @@ type LogTailer interface {
 // LogRecord defines a single Juju log message as returned by
 // LogTailer.
 type LogRecord struct {
+	// Id identifies the record. Ids increase in the order in which
+	// records are written, so they can be used to resume tailing.
+	Id       bson.ObjectId
 	Time     time.Time
 	Entity   string
 	Module   string
@@ -137,7 +140,16 @@ type LogRecord struct {
 // LogTailerParams specifies the filtering a LogTailer should apply to
 // logs in order to decide which to return.
 type LogTailerParams struct {
-	StartTime     time.Time
+	StartTime time.Time
+	// AfterId, if set, excludes logs with ids up to and including
+	// it, and returns logs in id order rather than time order.
+	AfterId bson.ObjectId
+	// EndTime, if set, excludes logs recorded after it.
+	EndTime time.Time
+	// NoTail, if true, stops the LogTailer once all matching logs
+	// already recorded have been returned, rather than waiting for
+	// more to appear.
+	NoTail        bool
 	MinLevel      loggo.Level
 	InitialLines  int
 	IncludeEntity []string
@@ -223,6 +235,9 @@ func (t *logTailer) loop() error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if t.params.NoTail {
+		return nil
+	}
 
 	err = t.tailOplog()
 	return errors.Trace(err)
@@ -244,7 +259,11 @@ func (t *logTailer) processCollection() error {
 		}
 	}
 
-	iter := query.Sort("t", "_id").Iter()
+	order := []string{"t", "_id"}
+	if t.params.AfterId != "" {
+		order = []string{"_id"}
+	}
+	iter := query.Sort(order...).Iter()
 	doc := new(logDoc)
 	for iter.Next(doc) {
 		select {
@@ -262,7 +281,12 @@ func (t *logTailer) tailOplog() error {
 	recentIds := t.recentIds.AsSet()
 
 	newParams := t.params
-	newParams.StartTime = t.lastTime
+	if newParams.AfterId == "" {
+		// Records are returned in time order, so skip any older
+		// than the last one returned. When tailing after an id the
+		// id selector does that instead.
+		newParams.StartTime = t.lastTime
+	}
 	oplogSel := append(t.paramsToSelector(newParams, "o."),
 		bson.DocElem{"ns", logsDB + "." + logsC},
 	)
@@ -314,9 +338,16 @@ func (t *logTailer) tailOplog() error {
 }
 
 func (t *logTailer) paramsToSelector(params *LogTailerParams, prefix string) bson.D {
+	timeSel := bson.M{"$gte": params.StartTime}
+	if !params.EndTime.IsZero() {
+		timeSel["$lte"] = params.EndTime
+	}
 	sel := bson.D{
 		{"e", t.envUUID},
-		{"t", bson.M{"$gte": params.StartTime}},
+		{"t", timeSel},
+	}
+	if params.AfterId != "" {
+		sel = append(sel, bson.DocElem{"_id", bson.M{"$gt": params.AfterId}})
 	}
 	if params.MinLevel > loggo.UNSPECIFIED {
 		sel = append(sel, bson.DocElem{"v", bson.M{"$gte": params.MinLevel}})
@@ -414,6 +445,7 @@ func (s *objectIdSet) Length() int {
 
 func logDocToRecord(doc *logDoc) *LogRecord {
 	return &LogRecord{
+		Id:       doc.Id,
 		Time:     doc.Time,
 		Entity:   doc.Entity,
 		Module:   doc.Module,
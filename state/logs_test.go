@@ -219,6 +219,86 @@ func (s *LogTailerSuite) TestTimeFiltering(c *gc.C) {
 
 }
 
+func (s *LogTailerSuite) TestEndTimeFiltering(c *gc.C) {
+	threshT := time.Now()
+	want := logTemplate{Message: "want"}
+	s.writeLogsT(c, threshT.Add(-5*time.Second), threshT, 5, want)
+	s.writeLogsT(c,
+		threshT.Add(time.Millisecond), threshT.Add(5*time.Second), 5,
+		logTemplate{Message: "dont want"},
+	)
+
+	tailer := state.NewLogTailer(s.State, &state.LogTailerParams{
+		EndTime: threshT,
+		Oplog:   s.oplogColl,
+	})
+	defer tailer.Stop()
+	s.assertTailer(c, tailer, 5, want)
+
+	// Logs written later are also too late.
+	s.writeLogsT(c, threshT.Add(6*time.Second), threshT.Add(10*time.Second), 5,
+		logTemplate{Message: "dont want"},
+	)
+	select {
+	case log := <-tailer.Logs():
+		c.Fatalf("unexpected log: %#v", log)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *LogTailerSuite) TestAfterIdFiltering(c *gc.C) {
+	threshT := time.Now()
+	s.writeLogsT(c, threshT, threshT, 3, logTemplate{Message: "dont want"})
+	tailer := state.NewLogTailer(s.State, &state.LogTailerParams{
+		NoTail: true,
+		Oplog:  s.oplogColl,
+	})
+	var lastId bson.ObjectId
+	for log := range tailer.Logs() {
+		lastId = log.Id
+	}
+	c.Assert(tailer.Err(), jc.ErrorIsNil)
+	c.Assert(lastId.Valid(), jc.IsTrue)
+
+	// Records written after the cursor are returned in the order
+	// they were written, even when their timestamps are earlier
+	// than or equal to those already seen.
+	s.writeLogsT(c, threshT.Add(-time.Hour), threshT.Add(-time.Hour), 1, logTemplate{Message: "late"})
+	s.writeLogsT(c, threshT, threshT, 1, logTemplate{Message: "same time"})
+	tailer = state.NewLogTailer(s.State, &state.LogTailerParams{
+		AfterId: lastId,
+		Oplog:   s.oplogColl,
+	})
+	defer tailer.Stop()
+	s.assertTailer(c, tailer, 1, logTemplate{Message: "late"})
+	s.assertTailer(c, tailer, 1, logTemplate{Message: "same time"})
+
+	// Write more logs. These will be read from the the oplog.
+	want := logTemplate{Message: "want"}
+	s.writeLogs(c, 2, want)
+	s.assertTailer(c, tailer, 2, want)
+}
+
+func (s *LogTailerSuite) TestNoTail(c *gc.C) {
+	want := logTemplate{Message: "want"}
+	s.writeLogs(c, 5, want)
+
+	tailer := state.NewLogTailer(s.State, &state.LogTailerParams{
+		NoTail: true,
+		Oplog:  s.oplogColl,
+	})
+	defer tailer.Stop()
+	s.assertTailer(c, tailer, 5, want)
+
+	select {
+	case _, ok := <-tailer.Logs():
+		c.Assert(ok, jc.IsFalse)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("tailer did not stop")
+	}
+	c.Assert(tailer.Stop(), jc.ErrorIsNil)
+}
+
 func (s *LogTailerSuite) TestOplogTransition(c *gc.C) {
 	// Ensure that logs aren't repeated as the log tailer moves from
 	// reading from the logs collection to tailing the oplog.
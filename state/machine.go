@@ -1469,9 +1469,16 @@ func (m *Machine) Status() (StatusInfo, error) {
 
 // SetStatus sets the status of the machine.
 func (m *Machine) SetStatus(status Status, info string, data map[string]interface{}) error {
+	oldDoc, err := getStatus(m.st, m.globalKey())
+	if IsStatusNotFound(err) {
+		logger.Debugf("there is no state for %q yet", m.globalKey())
+	} else if err != nil {
+		logger.Debugf("cannot get state for %q yet", m.globalKey())
+	}
+
 	// If a machine is not yet provisioned, we allow its status
 	// to be set back to pending (when a retry is to occur).
-	_, err := m.InstanceId()
+	_, err = m.InstanceId()
 	allowPending := errors.IsNotProvisioned(err)
 	doc, err := newMachineStatusDoc(status, info, data, allowPending)
 	if err != nil {
@@ -1487,9 +1494,21 @@ func (m *Machine) SetStatus(status Status, info string, data map[string]interfac
 	if err = m.st.runTransaction(ops); err != nil {
 		return fmt.Errorf("cannot set status of machine %q: %v", m, onAbort(err, errNotAlive))
 	}
+
+	if oldDoc.Status != "" {
+		if err := updateStatusHistory(oldDoc, m.globalKey(), m.st); err != nil {
+			logger.Errorf("could not record status history before change to %q: %v", status, err)
+		}
+	}
 	return nil
 }
 
+// StatusHistory returns a slice of StatusInfo items selected by filter,
+// representing past statuses for this machine.
+func (m *Machine) StatusHistory(filter StatusHistoryFilter) ([]StatusInfo, error) {
+	return statusHistory(filter, m.globalKey(), m.st)
+}
+
 // Clean returns true if the machine does not have any deployed units or containers.
 func (m *Machine) Clean() bool {
 	return m.doc.Clean
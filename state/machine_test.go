@@ -1739,6 +1739,23 @@ func (s *MachineSuite) TestSetStatusPending(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `cannot set status "pending"`)
 }
 
+func (s *MachineSuite) TestStatusHistory(c *gc.C) {
+	err := s.machine.SetStatus(state.StatusStarted, "first", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetStatus(state.StatusError, "second", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetStatus(state.StatusStarted, "third", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.machine.StatusHistory(state.StatusHistoryFilter{Size: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Status, gc.Equals, state.StatusError)
+	c.Assert(history[0].Message, gc.Equals, "second")
+	c.Assert(history[1].Status, gc.Equals, state.StatusStarted)
+	c.Assert(history[1].Message, gc.Equals, "first")
+}
+
 func (s *MachineSuite) TestGetSetStatusWhileNotAlive(c *gc.C) {
 	// When Dying set/get should work.
 	err := s.machine.Destroy()
@@ -203,7 +203,11 @@ func (r *Relation) removeOps(ignoreService string, departingUnit *Unit) ([]txn.O
 			hasLastRef := bson.D{{"life", Dying}, {"unitcount", 0}, {"relationcount", 1}}
 			removable := append(bson.D{{"_id", ep.ServiceName}}, hasLastRef...)
 			if err := services.Find(removable).One(&svc.doc); err == nil {
-				ops = append(ops, svc.removeOps(hasLastRef)...)
+				svcOps, err := svc.removeOps(hasLastRef)
+				if err != nil {
+					return nil, err
+				}
+				ops = append(ops, svcOps...)
 				continue
 			} else if err != mgo.ErrNotFound {
 				return nil, err
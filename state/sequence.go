@@ -38,3 +38,18 @@ func (s *State) sequence(name string) (int, error) {
 	}
 	return result.Counter, nil
 }
+
+// currentSequence returns the last number issued by the named
+// sequence, which is zero if none has been issued.
+func (s *State) currentSequence(name string) (int, error) {
+	sequences, closer := s.getCollection(sequenceC)
+	defer closer()
+	var doc sequenceDoc
+	err := sequences.FindId(name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return 0, nil
+	} else if err != nil {
+		return -1, fmt.Errorf("cannot read %q sequence number: %v", name, err)
+	}
+	return doc.Counter, nil
+}
@@ -20,6 +20,7 @@ import (
 	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/network"
 )
 
 // Service represents the state of a service.
@@ -37,11 +38,13 @@ type serviceDoc struct {
 	Series            string     `bson:"series"`
 	Subordinate       bool       `bson:"subordinate"`
 	CharmURL          *charm.URL `bson:"charmurl"`
+	PreviousCharmURL  *charm.URL `bson:"previouscharmurl,omitempty"`
 	ForceCharm        bool       `bson:"forcecharm"`
 	Life              Life       `bson:"life"`
 	UnitCount         int        `bson:"unitcount"`
 	RelationCount     int        `bson:"relationcount"`
 	Exposed           bool       `bson:"exposed"`
+	ExposedCIDRs      []string   `bson:"exposedcidrs,omitempty"`
 	MinUnits          int        `bson:"minunits"`
 	OwnerTag          string     `bson:"ownertag"`
 	TxnRevno          int64      `bson:"txn-revno"`
@@ -169,7 +172,11 @@ func (s *Service) destroyOps() ([]txn.Op, error) {
 	// removed, the service can also be removed.
 	if s.doc.UnitCount == 0 && s.doc.RelationCount == removeCount {
 		hasLastRefs := bson.D{{"life", Alive}, {"unitcount", 0}, {"relationcount", removeCount}}
-		return append(ops, s.removeOps(hasLastRefs)...), nil
+		removeOps, err := s.removeOps(hasLastRefs)
+		if err != nil {
+			return nil, err
+		}
+		return append(ops, removeOps...), nil
 	}
 	// In all other cases, service removal will be handled as a consequence
 	// of the removal of the last unit or relation referencing it. If any
@@ -207,7 +214,7 @@ func (s *Service) destroyOps() ([]txn.Op, error) {
 
 // removeOps returns the operations required to remove the service. Supplied
 // asserts will be included in the operation on the service document.
-func (s *Service) removeOps(asserts bson.D) []txn.Op {
+func (s *Service) removeOps(asserts bson.D) ([]txn.Op, error) {
 	settingsDocID := s.st.docID(s.settingsKey())
 	ops := []txn.Op{
 		{
@@ -230,7 +237,11 @@ func (s *Service) removeOps(asserts bson.D) []txn.Op {
 		annotationRemoveOp(s.st, s.globalKey()),
 		removeLeadershipSettingsOp(s.Tag().Id()),
 	}
-	return ops
+	historyOps, err := s.removeConfigHistoryOps()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return append(ops, historyOps...), nil
 }
 
 // IsExposed returns whether this service is exposed. The explicitly open
@@ -240,29 +251,59 @@ func (s *Service) IsExposed() bool {
 	return s.doc.Exposed
 }
 
-// SetExposed marks the service as exposed.
+// ExposedCIDRs returns the CIDRs from which the open ports of an exposed
+// service may be accessed, or nil if they may be accessed from any
+// address. See SetExposedFrom.
+func (s *Service) ExposedCIDRs() []string {
+	return s.doc.ExposedCIDRs
+}
+
+// SetExposed marks the service as exposed to any address.
 // See ClearExposed and IsExposed.
 func (s *Service) SetExposed() error {
-	return s.setExposed(true)
+	return s.setExposed(true, nil)
+}
+
+// SetExposedFrom marks the service as exposed to the given CIDRs only,
+// replacing any CIDRs it was previously exposed to. Exposing a service
+// to no CIDRs exposes it to any address.
+// See ClearExposed and ExposedCIDRs.
+func (s *Service) SetExposedFrom(cidrs []string) error {
+	cidrs, err := network.NormalizeCIDRs(cidrs)
+	if err != nil {
+		return errors.Annotatef(err, "cannot expose service %q", s)
+	}
+	return s.setExposed(true, cidrs)
 }
 
-// ClearExposed removes the exposed flag from the service.
+// ClearExposed removes the exposed flag, and any CIDRs the service was
+// exposed to, from the service.
 // See SetExposed and IsExposed.
 func (s *Service) ClearExposed() error {
-	return s.setExposed(false)
+	return s.setExposed(false, nil)
 }
 
-func (s *Service) setExposed(exposed bool) (err error) {
+func (s *Service) setExposed(exposed bool, cidrs []string) (err error) {
+	var update bson.D
+	if len(cidrs) > 0 {
+		update = bson.D{{"$set", bson.D{{"exposed", exposed}, {"exposedcidrs", cidrs}}}}
+	} else {
+		update = bson.D{
+			{"$set", bson.D{{"exposed", exposed}}},
+			{"$unset", bson.D{{"exposedcidrs", nil}}},
+		}
+	}
 	ops := []txn.Op{{
 		C:      servicesC,
 		Id:     s.doc.DocID,
 		Assert: isAliveDoc,
-		Update: bson.D{{"$set", bson.D{{"exposed", exposed}}}},
+		Update: update,
 	}}
 	if err := s.st.runTransaction(ops); err != nil {
 		return fmt.Errorf("cannot set exposed flag for service %q to %v: %v", s, exposed, onAbort(err, errNotAlive))
 	}
 	s.doc.Exposed = exposed
+	s.doc.ExposedCIDRs = cidrs
 	return nil
 }
 
@@ -517,7 +558,11 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 			C:      servicesC,
 			Id:     s.doc.DocID,
 			Assert: append(notDeadDoc, differentCharm...),
-			Update: bson.D{{"$set", bson.D{{"charmurl", ch.URL()}, {"forcecharm", force}}}},
+			Update: bson.D{{"$set", bson.D{
+				{"charmurl", ch.URL()},
+				{"previouscharmurl", s.doc.CharmURL},
+				{"forcecharm", force},
+			}}},
 		},
 	}...)
 	// Add any extra peer relations that need creation.
@@ -612,12 +657,38 @@ func (s *Service) SetCharm(ch *Charm, force bool) error {
 	}
 	err := s.st.run(buildTxn)
 	if err == nil {
+		if *s.doc.CharmURL != *ch.URL() {
+			s.doc.PreviousCharmURL = s.doc.CharmURL
+		}
 		s.doc.CharmURL = ch.URL()
 		s.doc.ForceCharm = force
 	}
 	return err
 }
 
+// PreviousCharmURL returns the URL of the charm the service ran before
+// its charm was last changed, or nil if it has never been changed.
+func (s *Service) PreviousCharmURL() *charm.URL {
+	return s.doc.PreviousCharmURL
+}
+
+// RollbackCharm changes the charm for the service back to the one it
+// ran before its charm was last changed, as reported by
+// PreviousCharmURL. Units are moved back to that charm even if they
+// are in an error state, as with SetCharm when forced; rolling back
+// again returns the service to the charm it was rolled back from.
+func (s *Service) RollbackCharm() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot roll back charm of service %q", s)
+	if s.doc.PreviousCharmURL == nil {
+		return errors.NotFoundf("previous charm")
+	}
+	ch, err := s.st.Charm(s.doc.PreviousCharmURL)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return s.SetCharm(ch, true)
+}
+
 // String returns the service name.
 func (s *Service) String() string {
 	return s.doc.Name
@@ -857,7 +928,11 @@ func (s *Service) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 	}
 	if s.doc.Life == Dying && s.doc.RelationCount == 0 && s.doc.UnitCount == 1 {
 		hasLastRef := bson.D{{"life", Dying}, {"relationcount", 0}, {"unitcount", 1}}
-		return append(ops, s.removeOps(hasLastRef)...), nil
+		removeOps, err := s.removeOps(hasLastRef)
+		if err != nil {
+			return nil, err
+		}
+		return append(ops, removeOps...), nil
 	}
 	svcOp := txn.Op{
 		C:      servicesC,
@@ -934,6 +1009,17 @@ func (s *Service) ConfigSettings() (charm.Settings, error) {
 // UpdateConfigSettings changes a service's charm config settings. Values set
 // to nil will be deleted; unknown and invalid values will return an error.
 func (s *Service) UpdateConfigSettings(changes charm.Settings) error {
+	return s.updateConfigSettings(changes, "")
+}
+
+// UpdateConfigSettingsByUser is like UpdateConfigSettings, but records
+// the given user as the author of the change in the service's config
+// history.
+func (s *Service) UpdateConfigSettingsByUser(changes charm.Settings, user string) error {
+	return s.updateConfigSettings(changes, user)
+}
+
+func (s *Service) updateConfigSettings(changes charm.Settings, user string) error {
 	charm, _, err := s.Charm()
 	if err != nil {
 		return err
@@ -942,23 +1028,32 @@ func (s *Service) UpdateConfigSettings(changes charm.Settings) error {
 	if err != nil {
 		return err
 	}
-	// TODO(fwereade) state.Settings is itself really problematic in just
-	// about every use case. This needs to be resolved some time; but at
-	// least the settings docs are keyed by charm url as well as service
-	// name, so the actual impact of a race is non-threatening.
-	node, err := readSettings(s.st, s.settingsKey())
-	if err != nil {
-		return err
-	}
-	for name, value := range changes {
-		if value == nil {
-			node.Delete(name)
-		} else {
-			node.Set(name, value)
+	// The settings recorded in the config history are those read
+	// here, so the write asserts that they have not changed since,
+	// and they are read again if they have.
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		node, err := readSettings(s.st, s.settingsKey())
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range changes {
+			if value == nil {
+				node.Delete(name)
+			} else {
+				node.Set(name, value)
+			}
+		}
+		itemChanges, ops := node.writeOpsIfUnchanged()
+		if len(itemChanges) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		historyOp, err := s.configRevisionOp(itemChanges, node.Map(), user)
+		if err != nil {
+			return nil, errors.Trace(err)
 		}
+		return append(ops, historyOp), nil
 	}
-	_, err = node.Write()
-	return err
+	return s.st.run(buildTxn)
 }
 
 var ErrSubordinateConstraints = stderrors.New("constraints do not apply to subordinate services")
@@ -1204,6 +1299,12 @@ func (s *Service) SetStatus(status Status, info string, data map[string]interfac
 	return nil
 }
 
+// StatusHistory returns a slice of StatusInfo items selected by filter,
+// representing past statuses for this service.
+func (s *Service) StatusHistory(filter StatusHistoryFilter) ([]StatusInfo, error) {
+	return statusHistory(filter, s.globalKey(), s.st)
+}
+
 // ServiceAndUnitsStatus returns the status for this service and all its units.
 func (s *Service) ServiceAndUnitsStatus() (StatusInfo, map[string]StatusInfo, error) {
 	serviceStatus, err := s.Status()
@@ -67,6 +67,41 @@ func (s *ServiceSuite) TestSetCharm(c *gc.C) {
 	c.Assert(force, jc.IsTrue)
 }
 
+func (s *ServiceSuite) TestRollbackCharm(c *gc.C) {
+	c.Assert(s.mysql.PreviousCharmURL(), gc.IsNil)
+	err := s.mysql.RollbackCharm()
+	c.Assert(err, gc.ErrorMatches, `cannot roll back charm of service "mysql": previous charm not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	sch := s.AddMetaCharm(c, "mysql", metaBase, 2)
+	err = s.mysql.SetCharm(sch, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.PreviousCharmURL(), gc.DeepEquals, s.charm.URL())
+
+	// Setting the same charm again does not change the previous one.
+	err = s.mysql.SetCharm(sch, false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.PreviousCharmURL(), gc.DeepEquals, s.charm.URL())
+
+	err = s.mysql.RollbackCharm()
+	c.Assert(err, jc.ErrorIsNil)
+	url, force := s.mysql.CharmURL()
+	c.Assert(url, gc.DeepEquals, s.charm.URL())
+	c.Assert(force, jc.IsTrue)
+	c.Assert(s.mysql.PreviousCharmURL(), gc.DeepEquals, sch.URL())
+
+	// Rolling back again undoes the rollback.
+	svc, err := s.State.Service("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.RollbackCharm()
+	c.Assert(err, jc.ErrorIsNil)
+	url, _ = svc.CharmURL()
+	c.Assert(url, gc.DeepEquals, sch.URL())
+	c.Assert(svc.PreviousCharmURL(), gc.DeepEquals, s.charm.URL())
+}
+
 func (s *ServiceSuite) TestSetCharmPreconditions(c *gc.C) {
 	logging := s.AddTestingCharm(c, "logging")
 	err := s.mysql.SetCharm(logging, false)
@@ -1042,6 +1077,44 @@ func (s *ServiceSuite) TestServiceExposed(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, notAliveErr)
 }
 
+func (s *ServiceSuite) TestServiceExposedFrom(c *gc.C) {
+	c.Assert(s.mysql.ExposedCIDRs(), gc.HasLen, 0)
+
+	err := s.mysql.SetExposedFrom([]string{"192.168.0.0/16", "10.1.2.3/8"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsTrue)
+	c.Assert(s.mysql.ExposedCIDRs(), jc.DeepEquals, []string{"10.0.0.0/8", "192.168.0.0/16"})
+
+	// The CIDRs are stored.
+	svc, err := s.State.Service(s.mysql.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.IsExposed(), jc.IsTrue)
+	c.Assert(svc.ExposedCIDRs(), jc.DeepEquals, []string{"10.0.0.0/8", "192.168.0.0/16"})
+
+	// Exposing the service again replaces the CIDRs.
+	err = svc.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.ExposedCIDRs(), gc.HasLen, 0)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsTrue)
+	c.Assert(s.mysql.ExposedCIDRs(), gc.HasLen, 0)
+
+	// Unexposing the service clears the CIDRs.
+	err = s.mysql.SetExposedFrom([]string{"10.0.0.0/8"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsFalse)
+	c.Assert(s.mysql.ExposedCIDRs(), gc.HasLen, 0)
+
+	err = s.mysql.SetExposedFrom([]string{"10.0.0.0"})
+	c.Assert(err, gc.ErrorMatches, `cannot expose service "mysql": invalid CIDR "10.0.0.0"`)
+	c.Assert(s.mysql.IsExposed(), jc.IsFalse)
+}
+
 func (s *ServiceSuite) TestAddUnit(c *gc.C) {
 	// Check that principal units can be added on their own.
 	unitZero, err := s.mysql.AddUnit()
@@ -1820,6 +1893,23 @@ func (s *ServiceSuite) TestStatus(c *gc.C) {
 	}
 }
 
+func (s *ServiceSuite) TestStatusHistory(c *gc.C) {
+	err := s.mysql.SetStatus(state.StatusActive, "first", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetStatus(state.StatusBlocked, "second", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetStatus(state.StatusActive, "third", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	history, err := s.mysql.StatusHistory(state.StatusHistoryFilter{Size: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].Status, gc.Equals, state.StatusBlocked)
+	c.Assert(history[0].Message, gc.Equals, "second")
+	c.Assert(history[1].Status, gc.Equals, state.StatusActive)
+	c.Assert(history[1].Message, gc.Equals, "first")
+}
+
 const oneRequiredStorageMeta = `
 storage:
   data0:
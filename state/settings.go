@@ -133,6 +133,19 @@ func cacheKeys(caches ...map[string]interface{}) map[string]bool {
 // as a delta applied on top of the latest version of the node, to prevent
 // overwriting unrelated changes made to the node since it was last read.
 func (c *Settings) Write() ([]ItemChange, error) {
+	changes, ops := c.writeOps()
+	if len(changes) == 0 {
+		return []ItemChange{}, nil
+	}
+	if err := c.write(ops); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// writeOps returns the changes made to c and the operations that write
+// them onto its node. It returns no operations if nothing has changed.
+func (c *Settings) writeOps() ([]ItemChange, []txn.Op) {
 	changes := []ItemChange{}
 	updates := bson.M{}
 	deletions := bson.M{}
@@ -160,7 +173,7 @@ func (c *Settings) Write() ([]ItemChange, error) {
 		changes = append(changes, change)
 	}
 	if len(changes) == 0 {
-		return []ItemChange{}, nil
+		return changes, nil
 	}
 	sort.Sort(itemChangeSlice(changes))
 	ops := []txn.Op{{
@@ -169,15 +182,32 @@ func (c *Settings) Write() ([]ItemChange, error) {
 		Assert: txn.DocExists,
 		Update: setUnsetUpdate(updates, deletions),
 	}}
+	return changes, ops
+}
+
+// writeOpsIfUnchanged is like writeOps, but the operations it returns
+// also assert that the node has not changed since c was last read, so
+// that c's values can be recorded in the same transaction.
+func (c *Settings) writeOpsIfUnchanged() ([]ItemChange, []txn.Op) {
+	changes, ops := c.writeOps()
+	for i := range ops {
+		ops[i].Assert = bson.D{{"txn-revno", c.txnRevno}}
+	}
+	return changes, ops
+}
+
+// write runs the given operations, which must include those returned
+// by writeOps, and records the changes they write as being on disk.
+func (c *Settings) write(ops []txn.Op) error {
 	err := c.st.runTransaction(ops)
 	if err == txn.ErrAborted {
-		return nil, errors.NotFoundf("settings")
+		return errors.NotFoundf("settings")
 	}
 	if err != nil {
-		return nil, fmt.Errorf("cannot write settings: %v", err)
+		return fmt.Errorf("cannot write settings: %v", err)
 	}
 	c.disk = copyMap(c.core, nil)
-	return changes, nil
+	return nil
 }
 
 func newSettings(st *State, key string) *Settings {
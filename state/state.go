@@ -33,6 +33,7 @@ import (
 	"github.com/juju/juju/state/lease"
 	"github.com/juju/juju/state/presence"
 	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/tracing"
 	"github.com/juju/juju/version"
 )
 
@@ -76,6 +77,10 @@ type State struct {
 	// mu guards allManager.
 	mu         sync.Mutex
 	allManager *storeManager
+
+	// untraced holds the State from which a State returned by Traced
+	// was made, and which owns the resources they share.
+	untraced *State
 }
 
 // StateServingInfo holds information needed by a state server.
@@ -291,7 +296,38 @@ func (st *State) MongoSession() *mgo.Session {
 
 type closeFunc func()
 
+// Traced returns a State, sharing st's connection and watchers, whose
+// transactions are recorded as children of the given span. The
+// returned State must not be closed, nor used after st is closed. It
+// returns st itself if span is nil.
+func (st *State) Traced(span *tracing.Span) *State {
+	if span == nil {
+		return st
+	}
+	untraced := st
+	if st.untraced != nil {
+		untraced = st.untraced
+	}
+	traced := &State{
+		environTag:        st.environTag,
+		serverTag:         st.serverTag,
+		mongoInfo:         st.mongoInfo,
+		session:           st.session,
+		database:          st.database.Traced(span),
+		policy:            st.policy,
+		watcher:           st.watcher,
+		pwatcher:          st.pwatcher,
+		leadershipManager: st.leadershipManager,
+		untraced:          untraced,
+	}
+	traced.LeasePersistor = NewLeasePersistor(leaseC, traced.run, traced.getCollection)
+	return traced
+}
+
 func (st *State) Watch() *Multiwatcher {
+	if st.untraced != nil {
+		return st.untraced.Watch()
+	}
 	st.mu.Lock()
 	if st.allManager == nil {
 		st.allManager = newStoreManager(newAllWatcherStateBacking(st))
@@ -1123,8 +1159,15 @@ func (st *State) AddService(
 		OwnerTag:      owner,
 	}
 	svc := newService(st, svcDoc)
+	// Start the service's config history with a revision holding its
+	// initial (default) settings.
+	historyOp, err := svc.configRevisionOp(nil, nil, owner)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	ops := []txn.Op{
 		env.assertAliveOp(),
+		historyOp,
 		createConstraintsOp(st, svc.globalKey(), constraints.Value{}),
 		// TODO(dimitern) 2014-04-04 bug #1302498
 		// Once we can add networks independently of machine
@@ -1168,6 +1211,11 @@ func (st *State) AddService(
 	if err = svc.Refresh(); err != nil {
 		return nil, errors.Trace(err)
 	}
+	// Any history left by an earlier service of the same name that
+	// has not yet been cleaned up no longer applies.
+	if err := eraseConfigHistory(st, name, historyOp.Insert.(*configRevisionDoc).Revision); err != nil {
+		return nil, errors.Trace(err)
+	}
 	return svc, nil
 }
 
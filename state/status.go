@@ -19,6 +19,11 @@ var (
 	_ StatusSetter = (*Unit)(nil)
 	_ StatusGetter = (*Machine)(nil)
 	_ StatusGetter = (*Unit)(nil)
+
+	_ StatusHistoryGetter = (*Machine)(nil)
+	_ StatusHistoryGetter = (*Service)(nil)
+	_ StatusHistoryGetter = (*Unit)(nil)
+	_ StatusHistoryGetter = (*UnitAgent)(nil)
 )
 
 // Status represents the status of an entity.
@@ -242,6 +247,11 @@ type StatusGetter interface {
 	Status() (StatusInfo, error)
 }
 
+// StatusHistoryGetter represents a type whose past statuses can be read.
+type StatusHistoryGetter interface {
+	StatusHistory(filter StatusHistoryFilter) ([]StatusInfo, error)
+}
+
 // StatusInfo holds the status information for a machine, unit, service etc.
 type StatusInfo struct {
 	Status  Status
@@ -297,13 +307,58 @@ func updateStatusHistory(oldDoc statusDoc, globalKey string, st *State) error {
 	return errors.Annotatef(err, "cannot update status history of unit agent %q", globalKey)
 }
 
-func statusHistory(size int, globalKey string, st *State) ([]StatusInfo, error) {
+// StatusHistoryFilter holds the arguments used to select entries
+// from an entity's status history.
+type StatusHistoryFilter struct {
+	// Size is the maximum number of entries to return; if zero, all
+	// matching entries are returned.
+	Size int
+
+	// Since, if set, excludes entries recorded before this time.
+	Since *time.Time
+
+	// Until, if set, excludes entries recorded after this time.
+	Until *time.Time
+}
+
+// Matches returns whether the given status falls within the time
+// range covered by the filter.
+func (f StatusHistoryFilter) Matches(info StatusInfo) bool {
+	if info.Since == nil {
+		return f.Since == nil && f.Until == nil
+	}
+	if f.Since != nil && info.Since.Before(*f.Since) {
+		return false
+	}
+	if f.Until != nil && info.Since.After(*f.Until) {
+		return false
+	}
+	return true
+}
+
+func statusHistory(filter StatusHistoryFilter, globalKey string, st *State) ([]StatusInfo, error) {
 	statusHistory, closer := st.getCollection(statusesHistoryC)
 	defer closer()
 
+	sel := bson.D{{"entityid", globalKey}}
+	updated := bson.D{}
+	if filter.Since != nil {
+		updated = append(updated, bson.DocElem{"$gte", *filter.Since})
+	}
+	if filter.Until != nil {
+		updated = append(updated, bson.DocElem{"$lte", *filter.Until})
+	}
+	if len(updated) > 0 {
+		sel = append(sel, bson.DocElem{"updated", updated})
+	}
+	query := statusHistory.Find(sel).Sort("-_id")
+	if filter.Size > 0 {
+		query = query.Limit(filter.Size)
+	}
+
 	sInfo := []StatusInfo{}
 	results := []historicalStatusDoc{}
-	err := statusHistory.Find(bson.D{{"entityid", globalKey}}).Sort("-_id").Limit(size).All(&results)
+	err := query.All(&results)
 	if err == mgo.ErrNotFound {
 		return []StatusInfo{}, errors.NotFoundf("statusHistory")
 	}
@@ -5,6 +5,7 @@ package state_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -59,6 +60,62 @@ func (s *statusSuite) TestPruneStatusHistory(c *gc.C) {
 	c.Assert(history[99].Message, gc.Equals, "Status change 101")
 }
 
+func (s *statusSuite) TestStatusHistoryFilter(c *gc.C) {
+	st := s.State
+	globalKey := "BogusKey"
+	base := time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		updated := base.Add(time.Duration(i) * time.Hour)
+		sdoc := state.NewStatusDoc(state.StatusDoc{
+			EnvUUID:    st.EnvironUUID(),
+			Status:     state.StatusActive,
+			StatusInfo: fmt.Sprintf("change %d", i),
+			Updated:    &updated,
+		})
+		err := state.UpdateStatusHistory(sdoc, globalKey, st)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	since := base.Add(time.Hour)
+	until := base.Add(3 * time.Hour)
+
+	messages := func(filter state.StatusHistoryFilter) []string {
+		history, err := state.FilteredStatusHistory(filter, globalKey, st)
+		c.Assert(err, jc.ErrorIsNil)
+		var result []string
+		for _, info := range history {
+			result = append(result, info.Message)
+		}
+		return result
+	}
+	c.Check(messages(state.StatusHistoryFilter{}), jc.DeepEquals, []string{
+		"change 4", "change 3", "change 2", "change 1", "change 0",
+	})
+	c.Check(messages(state.StatusHistoryFilter{Since: &since}), jc.DeepEquals, []string{
+		"change 4", "change 3", "change 2", "change 1",
+	})
+	c.Check(messages(state.StatusHistoryFilter{Until: &until}), jc.DeepEquals, []string{
+		"change 3", "change 2", "change 1", "change 0",
+	})
+	c.Check(messages(state.StatusHistoryFilter{Since: &since, Until: &until}), jc.DeepEquals, []string{
+		"change 3", "change 2", "change 1",
+	})
+	c.Check(messages(state.StatusHistoryFilter{Size: 2, Until: &until}), jc.DeepEquals, []string{
+		"change 3", "change 2",
+	})
+}
+
+func (s *statusSuite) TestStatusHistoryFilterMatches(c *gc.C) {
+	base := time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+	before := base.Add(-time.Minute)
+	after := base.Add(time.Minute)
+	filter := state.StatusHistoryFilter{Since: &base, Until: &after}
+	c.Check(filter.Matches(state.StatusInfo{Since: &base}), jc.IsTrue)
+	c.Check(filter.Matches(state.StatusInfo{Since: &after}), jc.IsTrue)
+	c.Check(filter.Matches(state.StatusInfo{Since: &before}), jc.IsFalse)
+	c.Check(filter.Matches(state.StatusInfo{}), jc.IsFalse)
+	c.Check(state.StatusHistoryFilter{}.Matches(state.StatusInfo{}), jc.IsTrue)
+}
+
 func (s *statusSuite) TestTranslateLegacyAgentState(c *gc.C) {
 	for i, test := range []struct {
 		agentStatus     state.Status
@@ -5,11 +5,21 @@ package state
 
 import (
 	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/juju/errors"
 	jujutxn "github.com/juju/txn"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/instrumentation"
+	"github.com/juju/juju/tracing"
+)
+
+var txnRetries = instrumentation.NewCounter(
+	"juju_state_txn_retries_total",
+	"Number of times a transaction was rebuilt and run again after its assertions failed.",
 )
 
 // runTransaction is a convenience method delegating to the state's Database.
@@ -57,17 +67,27 @@ type multiEnvRunner struct {
 	rawRunner jujutxn.Runner
 	schema    collectionSchema
 	envUUID   string
+
+	// span, if non-nil, is the span on whose behalf transactions
+	// are run.
+	span *tracing.Span
 }
 
 // RunTransaction is part of the jujutxn.Runner interface. Operations
 // that affect multi-environment collections will be modified in-place
 // to ensure correct interaction with these collections.
 func (r *multiEnvRunner) RunTransaction(ops []txn.Op) error {
+	span := r.span.Child("state.RunTransaction", tracing.SpanKindInternal)
+	defer span.End()
 	ops, err := r.updateOps(ops)
 	if err != nil {
+		span.SetError(err)
 		return errors.Trace(err)
 	}
-	return r.rawRunner.RunTransaction(ops)
+	traceOps(span, ops)
+	err = r.rawRunner.RunTransaction(ops)
+	span.SetError(err)
+	return err
 }
 
 // Run is part of the jujutxn.Runner interface. Operations returned by
@@ -75,7 +95,13 @@ func (r *multiEnvRunner) RunTransaction(ops []txn.Op) error {
 // collections will be modified in-place to ensure correct interaction
 // with these collections.
 func (r *multiEnvRunner) Run(transactions jujutxn.TransactionSource) error {
-	return r.rawRunner.Run(func(attempt int) ([]txn.Op, error) {
+	span := r.span.Child("state.Run", tracing.SpanKindInternal)
+	defer span.End()
+	err := r.rawRunner.Run(func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			txnRetries.Inc()
+		}
+		span.SetAttribute("juju.txn.attempt", attempt)
 		ops, err := transactions(attempt)
 		if err != nil {
 			// Don't use Trace here as jujutxn doens't use juju/errors
@@ -86,8 +112,34 @@ func (r *multiEnvRunner) Run(transactions jujutxn.TransactionSource) error {
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+		traceOps(span, ops)
 		return ops, nil
 	})
+	span.SetError(err)
+	return err
+}
+
+// traceOps records the given transaction operations in span, and logs
+// them against its trace so that the log can be correlated with the
+// spans exported. It does nothing if span is nil.
+func traceOps(span *tracing.Span, ops []txn.Op) {
+	if span == nil {
+		return
+	}
+	seen := make(map[string]bool)
+	var collections []string
+	for _, op := range ops {
+		if !seen[op.C] {
+			seen[op.C] = true
+			collections = append(collections, op.C)
+		}
+	}
+	sort.Strings(collections)
+	span.SetAttribute("juju.txn.ops", len(ops))
+	span.SetAttribute("juju.txn.collections", strings.Join(collections, ","))
+	ctx := span.Context()
+	logger.Debugf("trace %s span %s: running transaction with %d operations on %s",
+		ctx.TraceID, ctx.SpanID, len(ops), strings.Join(collections, ", "))
 }
 
 // ResumeTransactions is part of the jujutxn.Runner interface.
@@ -459,6 +459,16 @@ func (s *MultiEnvRunnerSuite) TestRun(c *gc.C) {
 	}
 }
 
+func (s *MultiEnvRunnerSuite) TestRunCountsRetries(c *gc.C) {
+	before := txnRetries.Value()
+	err := s.multiEnvRunner.Run(func(attempt int) ([]txn.Op, error) {
+		return nil, nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	// The recording runner always reports a later attempt.
+	c.Check(txnRetries.Value(), gc.Equals, before+1)
+}
+
 func (s *MultiEnvRunnerSuite) TestRunWithError(c *gc.C) {
 	err := s.multiEnvRunner.Run(func(attempt int) ([]txn.Op, error) {
 		return nil, errors.New("boom")
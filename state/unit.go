@@ -810,10 +810,10 @@ func (u *Unit) AgentStatus() (StatusInfo, error) {
 	return agent.Status()
 }
 
-// StatusHistory returns a slice of at most <size> StatusInfo items
+// StatusHistory returns a slice of StatusInfo items selected by filter,
 // representing past statuses for this unit.
-func (u *Unit) StatusHistory(size int) ([]StatusInfo, error) {
-	return statusHistory(size, u.globalKey(), u.st)
+func (u *Unit) StatusHistory(filter StatusHistoryFilter) ([]StatusInfo, error) {
+	return statusHistory(filter, u.globalKey(), u.st)
 }
 
 // Status returns the status of the unit.
@@ -1997,6 +1997,13 @@ type ActionSpecsByName map[string]charm.ActionSpec
 // this Unit, and returns its ID.  Note that the use of spec.InsertDefaults
 // mutates payload.
 func (u *Unit) AddAction(name string, payload map[string]interface{}) (*Action, error) {
+	return u.AddActionWithTimeout(name, payload, 0)
+}
+
+// AddActionWithTimeout adds a new Action as AddAction does, which the
+// unit will kill if it runs for longer than timeout. A zero timeout
+// means no limit.
+func (u *Unit) AddActionWithTimeout(name string, payload map[string]interface{}, timeout time.Duration) (*Action, error) {
 	if len(name) == 0 {
 		return nil, errors.New("no action name given")
 	}
@@ -2017,7 +2024,7 @@ func (u *Unit) AddAction(name string, payload map[string]interface{}) (*Action,
 	if err != nil {
 		return nil, err
 	}
-	return u.st.EnqueueAction(u.Tag(), name, payloadWithDefaults)
+	return u.st.EnqueueActionWithTimeout(u.Tag(), name, payloadWithDefaults, timeout)
 }
 
 // ActionSpecs gets the ActionSpec map for the Unit's charm.
@@ -2058,6 +2065,12 @@ func (u *Unit) WatchActionNotifications() StringsWatcher {
 	return u.st.watchEnqueuedActionsFilteredBy(u)
 }
 
+// WatchActionCancellations starts and returns a StringsWatcher that
+// notifies with the ids of this Unit's actions that are cancelled.
+func (u *Unit) WatchActionCancellations() StringsWatcher {
+	return newActionStatusWatcher(u.st, []ActionReceiver{u}, ActionCancelled)
+}
+
 // Actions returns a list of actions pending or completed for this unit.
 func (u *Unit) Actions() ([]*Action, error) {
 	return u.st.matchingActions(u)
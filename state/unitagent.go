@@ -87,10 +87,10 @@ func (u *UnitAgent) SetStatus(status Status, info string, data map[string]interf
 	return nil
 }
 
-// StatusHistory returns a slice of at most <size> StatusInfo items
+// StatusHistory returns a slice of StatusInfo items selected by filter,
 // representing past statuses for this agent.
-func (u *UnitAgent) StatusHistory(size int) ([]StatusInfo, error) {
-	return statusHistory(size, u.globalKey(), u.st)
+func (u *UnitAgent) StatusHistory(filter StatusHistoryFilter) ([]StatusInfo, error) {
+	return statusHistory(filter, u.globalKey(), u.st)
 }
 
 // unitAgentGlobalKey returns the global database key for the named unit.
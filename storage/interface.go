@@ -62,6 +62,19 @@ type Provider interface {
 	ValidateConfig(*Config) error
 }
 
+// AttachmentScoper is an optional interface that may be implemented by
+// a Provider whose storage attachments are managed in a different scope
+// to the storage itself.
+//
+// For example, a network filesystem may be created and destroyed by the
+// environment storage provisioner, but must be mounted from within each
+// machine that it is attached to.
+type AttachmentScoper interface {
+	// AttachmentScope returns the scope of storage attachments
+	// managed by this provider.
+	AttachmentScope() Scope
+}
+
 // VolumeSource provides an interface for creating, destroying, describing,
 // attaching and detaching volumes in the environment. A VolumeSource is
 // configured in a particular way, and corresponds to a storage "pool".
@@ -14,7 +14,11 @@ import (
 	"github.com/juju/juju/storage"
 )
 
-var Getpagesize = &getpagesize
+var (
+	Getpagesize  = &getpagesize
+	NFSTempDir   = &nfsTempDir
+	NFSRemoveAll = &nfsRemoveAll
+)
 
 func LoopVolumeSource(
 	storageDir string,
@@ -33,6 +37,32 @@ func LoopProvider(
 	return &loopProvider{run}
 }
 
+func LVMProvider(
+	run func(string, ...string) (string, error),
+	listBlockDevices func() ([]storage.BlockDevice, error),
+) storage.Provider {
+	return &lvmProvider{run, listBlockDevices}
+}
+
+func LVMVolumeSource(
+	run func(string, ...string) (string, error),
+	listBlockDevices func() ([]storage.BlockDevice, error),
+) storage.VolumeSource {
+	return &lvmVolumeSource{run, listBlockDevices}
+}
+
+func NFSProvider(run func(string, ...string) (string, error)) storage.Provider {
+	return &nfsProvider{run}
+}
+
+func NFSFilesystemSource(run func(string, ...string) (string, error)) (storage.FilesystemSource, *MockDirFuncs) {
+	d := &MockDirFuncs{
+		osDirFuncs{run},
+		set.NewStrings(),
+	}
+	return &nfsFilesystemSource{d, run}, d
+}
+
 func NewMockManagedFilesystemSource(
 	run func(string, ...string) (string, error),
 	volumeBlockDevices map[names.VolumeTag]storage.BlockDevice,
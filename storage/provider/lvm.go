@@ -0,0 +1,459 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/schema"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	// LVMProviderType is the provider type for the LVM storage
+	// provider.
+	LVMProviderType = storage.ProviderType("lvm")
+
+	// LVMVolumeGroup is the name of the pool attribute that
+	// specifies the LVM volume group in which logical volumes
+	// are created. The volume group will be created if it does
+	// not already exist.
+	LVMVolumeGroup = "volume-group"
+
+	// LVMDevices is the name of the pool attribute that specifies
+	// a comma-separated list of block device names (e.g. "sdb,sdc")
+	// used to create the volume group. It is required, so that no
+	// disk is ever taken over without being named.
+	LVMDevices = "devices"
+
+	defaultVolumeGroup = "juju"
+)
+
+var volumeGroupNameRE = regexp.MustCompile(`^[a-zA-Z0-9+_.][a-zA-Z0-9+_.-]*$`)
+
+var lvmConfigFields = schema.Fields{
+	LVMVolumeGroup: schema.String(),
+	LVMDevices:     schema.String(),
+}
+
+var lvmConfigChecker = schema.FieldMap(
+	lvmConfigFields,
+	schema.Defaults{
+		LVMVolumeGroup: defaultVolumeGroup,
+		LVMDevices:     "",
+	},
+)
+
+// validVolumeGroupName reports whether the name is acceptable to LVM
+// as a volume group name.
+func validVolumeGroupName(name string) bool {
+	return name != "." && name != ".." && volumeGroupNameRE.MatchString(name)
+}
+
+type lvmConfig struct {
+	volumeGroup string
+	devices     []string
+}
+
+func newLVMConfig(attrs map[string]interface{}) (*lvmConfig, error) {
+	out, err := lvmConfigChecker.Coerce(attrs, nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "validating LVM storage config")
+	}
+	coerced := out.(map[string]interface{})
+	cfg := &lvmConfig{volumeGroup: coerced[LVMVolumeGroup].(string)}
+	if !validVolumeGroupName(cfg.volumeGroup) {
+		return nil, errors.NotValidf("volume group name %q", cfg.volumeGroup)
+	}
+	for _, device := range strings.Split(coerced[LVMDevices].(string), ",") {
+		device = strings.TrimPrefix(strings.TrimSpace(device), "/dev/")
+		if device != "" {
+			cfg.devices = append(cfg.devices, device)
+		}
+	}
+	if len(cfg.devices) == 0 {
+		return nil, errors.NotValidf("empty %s", LVMDevices)
+	}
+	return cfg, nil
+}
+
+// lvmProvider creates volume sources which carve logical volumes
+// out of an LVM volume group on the local machine.
+type lvmProvider struct {
+	// run is a function used for running commands on the local machine.
+	run runCommandFunc
+
+	// listBlockDevices is a function used for listing the block
+	// devices attached to the local machine.
+	listBlockDevices func() ([]storage.BlockDevice, error)
+}
+
+var _ storage.Provider = (*lvmProvider)(nil)
+
+// NewLVMProvider returns a new LVM storage provider, which uses the
+// supplied function to list the block devices that are candidates for
+// the volume group.
+func NewLVMProvider(listBlockDevices func() ([]storage.BlockDevice, error)) storage.Provider {
+	return &lvmProvider{logAndExec, listBlockDevices}
+}
+
+// ValidateConfig is defined on the Provider interface.
+func (*lvmProvider) ValidateConfig(cfg *storage.Config) error {
+	_, err := newLVMConfig(cfg.Attrs())
+	return errors.Trace(err)
+}
+
+// VolumeSource is defined on the Provider interface.
+func (p *lvmProvider) VolumeSource(
+	environConfig *config.Config,
+	sourceConfig *storage.Config,
+) (storage.VolumeSource, error) {
+	if err := p.ValidateConfig(sourceConfig); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &lvmVolumeSource{p.run, p.listBlockDevices}, nil
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (p *lvmProvider) FilesystemSource(
+	environConfig *config.Config,
+	providerConfig *storage.Config,
+) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// Supports is defined on the Provider interface.
+func (*lvmProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is defined on the Provider interface.
+func (*lvmProvider) Scope() storage.Scope {
+	return storage.ScopeMachine
+}
+
+// Dynamic is defined on the Provider interface.
+func (*lvmProvider) Dynamic() bool {
+	return true
+}
+
+// lvmVolumeSource creates logical volumes in the volume groups named
+// by the volumes' pool configuration, creating each volume group from
+// the block devices named in the pool configuration when it does not
+// yet exist.
+//
+// Volume IDs take the form "<volume-group>/<logical-volume>", which is
+// also the logical volume's path relative to /dev.
+type lvmVolumeSource struct {
+	run              runCommandFunc
+	listBlockDevices func() ([]storage.BlockDevice, error)
+}
+
+var _ storage.VolumeSource = (*lvmVolumeSource)(nil)
+
+// CreateVolumes is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) CreateVolumes(args []storage.VolumeParams) ([]storage.Volume, []storage.VolumeAttachment, error) {
+	volumes := make([]storage.Volume, len(args))
+	for i, arg := range args {
+		volume, err := lvs.createVolume(arg)
+		if err != nil {
+			return nil, nil, errors.Annotate(err, "creating volume")
+		}
+		volumes[i] = volume
+	}
+	// Logical volumes are activated on creation; they are
+	// recorded as attached by AttachVolumes.
+	return volumes, nil, nil
+}
+
+func (lvs *lvmVolumeSource) createVolume(params storage.VolumeParams) (storage.Volume, error) {
+	cfg, err := newLVMConfig(params.Attributes)
+	if err != nil {
+		return storage.Volume{}, errors.Trace(err)
+	}
+	if err := lvs.ensureVolumeGroup(cfg); err != nil {
+		return storage.Volume{}, errors.Trace(err)
+	}
+	lvName := params.Tag.String()
+	_, err = lvs.run(
+		"lvcreate",
+		"--name", lvName,
+		"--size", strconv.FormatUint(params.Size, 10)+"m",
+		cfg.volumeGroup,
+	)
+	if err != nil {
+		return storage.Volume{}, errors.Annotatef(err, "creating logical volume %q", lvName)
+	}
+	return storage.Volume{
+		params.Tag,
+		storage.VolumeInfo{
+			VolumeId: path.Join(cfg.volumeGroup, lvName),
+			Size:     params.Size,
+		},
+	}, nil
+}
+
+// ensureVolumeGroup creates the volume group if it does not already
+// exist, initialising each of the chosen devices as a physical volume.
+func (lvs *lvmVolumeSource) ensureVolumeGroup(cfg *lvmConfig) error {
+	exists, err := lvs.volumeGroupExists(cfg.volumeGroup)
+	if err != nil || exists {
+		return errors.Trace(err)
+	}
+	devices, err := lvs.volumeGroupDevices(cfg.devices)
+	if err != nil {
+		return errors.Annotatef(err, "choosing devices for volume group %q", cfg.volumeGroup)
+	}
+	devicePaths := make([]string, len(devices))
+	for i, device := range devices {
+		devicePaths[i] = path.Join("/dev", device)
+		if _, err := lvs.run("pvcreate", devicePaths[i]); err != nil {
+			return errors.Annotatef(err, "creating physical volume on %q", device)
+		}
+	}
+	args := append([]string{cfg.volumeGroup}, devicePaths...)
+	if _, err := lvs.run("vgcreate", args...); err != nil {
+		return errors.Annotatef(err, "creating volume group %q", cfg.volumeGroup)
+	}
+	return nil
+}
+
+func (lvs *lvmVolumeSource) volumeGroupExists(volumeGroup string) (bool, error) {
+	stdout, err := lvs.run("vgs", "--noheadings", "-o", "vg_name")
+	if err != nil {
+		return false, errors.Annotate(err, "listing volume groups")
+	}
+	for _, name := range strings.Fields(stdout) {
+		if name == volumeGroup {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// volumeGroupDevices checks that the block devices configured for a
+// volume group exist on the machine and are unused, and returns their
+// names.
+func (lvs *lvmVolumeSource) volumeGroupDevices(configured []string) ([]string, error) {
+	blockDevices, err := lvs.listBlockDevices()
+	if err != nil {
+		return nil, errors.Annotate(err, "listing block devices")
+	}
+	byName := make(map[string]storage.BlockDevice)
+	for _, dev := range blockDevices {
+		byName[dev.DeviceName] = dev
+	}
+	for _, name := range configured {
+		dev, ok := byName[name]
+		if !ok {
+			return nil, errors.NotFoundf("block device %q", name)
+		}
+		if !blockDeviceAvailable(dev) {
+			return nil, errors.Errorf("block device %q is in use", name)
+		}
+		hasChildren, err := lvs.blockDeviceHasChildren(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if hasChildren {
+			return nil, errors.Errorf("block device %q is partitioned or in use", name)
+		}
+	}
+	return configured, nil
+}
+
+// blockDeviceAvailable reports whether the block device may be used
+// as a physical volume: it must not be a loop device or logical volume,
+// be in use, or have a filesystem.
+func blockDeviceAvailable(dev storage.BlockDevice) bool {
+	if strings.HasPrefix(dev.DeviceName, "loop") {
+		return false
+	}
+	if strings.Contains(dev.DeviceName, "/") {
+		// Logical volumes are named by their path under /dev.
+		return false
+	}
+	return !dev.InUse && dev.FilesystemType == "" && dev.MountPoint == ""
+}
+
+// blockDeviceHasChildren reports whether lsblk shows any devices, such
+// as partitions or logical volumes, built on the named block device.
+func (lvs *lvmVolumeSource) blockDeviceHasChildren(name string) (bool, error) {
+	stdout, err := lvs.run("lsblk", "--noheadings", "--raw", "-o", "KNAME", path.Join("/dev", name))
+	if err != nil {
+		return false, errors.Annotatef(err, "listing devices on %q", name)
+	}
+	// The first line describes the device itself; any others
+	// describe its children.
+	return len(strings.Fields(stdout)) > 1, nil
+}
+
+// logicalVolumes returns the sizes, in MiB, of the logical volumes
+// created by Juju in any volume group, keyed by volume ID.
+func (lvs *lvmVolumeSource) logicalVolumes() (map[string]uint64, error) {
+	stdout, err := lvs.run(
+		"lvs", "--noheadings", "--nosuffix",
+		"--units", "m",
+		"-o", "vg_name,lv_name,lv_size",
+	)
+	if err != nil {
+		return nil, errors.Annotate(err, "listing logical volumes")
+	}
+	volumes := make(map[string]uint64)
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, errors.Errorf("unexpected output %q", line)
+		}
+		if _, err := names.ParseVolumeTag(fields[1]); err != nil {
+			// Not created by Juju.
+			continue
+		}
+		size, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return nil, errors.Annotatef(err, "parsing size of logical volume %q", fields[1])
+		}
+		volumes[path.Join(fields[0], fields[1])] = uint64(size)
+	}
+	return volumes, nil
+}
+
+// ListVolumes is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) ListVolumes() ([]string, error) {
+	volumes, err := lvs.logicalVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volumeIds := set.NewStrings()
+	for volumeId := range volumes {
+		volumeIds.Add(volumeId)
+	}
+	return volumeIds.SortedValues(), nil
+}
+
+// DescribeVolumes is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) DescribeVolumes(volumeIds []string) ([]storage.VolumeInfo, error) {
+	volumes, err := lvs.logicalVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	results := make([]storage.VolumeInfo, len(volumeIds))
+	for i, volumeId := range volumeIds {
+		size, ok := volumes[volumeId]
+		if !ok {
+			return nil, errors.NotFoundf("volume %q", volumeId)
+		}
+		results[i] = storage.VolumeInfo{
+			VolumeId: volumeId,
+			Size:     size,
+		}
+	}
+	return results, nil
+}
+
+// DestroyVolumes is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) DestroyVolumes(volumeIds []string) []error {
+	results := make([]error, len(volumeIds))
+	volumes, err := lvs.logicalVolumes()
+	if err != nil {
+		for i := range results {
+			results[i] = errors.Trace(err)
+		}
+		return results
+	}
+	for i, volumeId := range volumeIds {
+		if !validLVMVolumeId(volumeId) {
+			results[i] = errors.Errorf("invalid LVM volume ID %q", volumeId)
+			continue
+		}
+		if _, ok := volumes[volumeId]; !ok {
+			// Already destroyed.
+			continue
+		}
+		if _, err := lvs.run("lvremove", "--force", volumeId); err != nil {
+			results[i] = errors.Annotatef(err, "destroying %q", volumeId)
+		}
+	}
+	return results
+}
+
+// validLVMVolumeId reports whether the volume ID is of the form
+// "<volume-group>/<volume-tag>".
+func validLVMVolumeId(volumeId string) bool {
+	volumeGroup, lvName := path.Split(volumeId)
+	volumeGroup = strings.TrimSuffix(volumeGroup, "/")
+	if !validVolumeGroupName(volumeGroup) {
+		return false
+	}
+	_, err := names.ParseVolumeTag(lvName)
+	return err == nil
+}
+
+// ValidateVolumeParams is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	// ValidateVolumeParams may be called on a machine other than the
+	// machine where the logical volume will be created, so we cannot
+	// check the volume group until we get to CreateVolumes.
+	_, err := newLVMConfig(params.Attributes)
+	return errors.Trace(err)
+}
+
+// AttachVolumes is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) AttachVolumes(args []storage.VolumeAttachmentParams) ([]storage.VolumeAttachment, error) {
+	attachments := make([]storage.VolumeAttachment, len(args))
+	for i, arg := range args {
+		attachment, err := lvs.attachVolume(arg)
+		if err != nil {
+			return nil, errors.Annotatef(err, "attaching volume %v", arg.Volume.Id())
+		}
+		attachments[i] = attachment
+	}
+	return attachments, nil
+}
+
+func (lvs *lvmVolumeSource) attachVolume(arg storage.VolumeAttachmentParams) (storage.VolumeAttachment, error) {
+	if !validLVMVolumeId(arg.VolumeId) {
+		return storage.VolumeAttachment{}, errors.Errorf("invalid LVM volume ID %q", arg.VolumeId)
+	}
+	permission := "rw"
+	if arg.ReadOnly {
+		permission = "r"
+	}
+	if _, err := lvs.run("lvchange", "--activate", "y", "--permission", permission, arg.VolumeId); err != nil {
+		return storage.VolumeAttachment{}, errors.Annotate(err, "activating logical volume")
+	}
+	return storage.VolumeAttachment{
+		arg.Volume,
+		arg.Machine,
+		storage.VolumeAttachmentInfo{
+			// The volume ID is the logical volume's path
+			// under /dev, which is stable across restarts,
+			// and is the name the disk manager reports for
+			// the logical volume's block device.
+			DeviceName: arg.VolumeId,
+			ReadOnly:   arg.ReadOnly,
+		},
+	}, nil
+}
+
+// DetachVolumes is defined on the VolumeSource interface.
+func (lvs *lvmVolumeSource) DetachVolumes(args []storage.VolumeAttachmentParams) error {
+	for _, arg := range args {
+		if _, err := lvs.run("lvchange", "--activate", "n", arg.VolumeId); err != nil {
+			return errors.Annotatef(err, "detaching volume %s", arg.Volume.Id())
+		}
+	}
+	return nil
+}
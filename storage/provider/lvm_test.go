@@ -0,0 +1,317 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&lvmSuite{})
+
+type lvmSuite struct {
+	testing.BaseSuite
+	commands     *mockRunCommand
+	blockDevices []storage.BlockDevice
+}
+
+func (s *lvmSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.commands = &mockRunCommand{c: c}
+	s.blockDevices = []storage.BlockDevice{
+		{DeviceName: "sda", InUse: true},
+		{DeviceName: "sda1", InUse: true, MountPoint: "/"},
+		{DeviceName: "sdb"},
+		{DeviceName: "sdc", FilesystemType: "ext4"},
+		{DeviceName: "sdd"},
+		{DeviceName: "loop0"},
+		{DeviceName: "other/volume-0"},
+	}
+}
+
+func (s *lvmSuite) TearDownTest(c *gc.C) {
+	s.commands.assertDrained()
+	s.BaseSuite.TearDownTest(c)
+}
+
+func (s *lvmSuite) listBlockDevices() ([]storage.BlockDevice, error) {
+	return s.blockDevices, nil
+}
+
+func (s *lvmSuite) lvmProvider() storage.Provider {
+	return provider.LVMProvider(s.commands.run, s.listBlockDevices)
+}
+
+func (s *lvmSuite) lvmVolumeSource() storage.VolumeSource {
+	return provider.LVMVolumeSource(s.commands.run, s.listBlockDevices)
+}
+
+func (s *lvmSuite) TestValidateConfig(c *gc.C) {
+	p := s.lvmProvider()
+	cfg, err := storage.NewConfig("name", provider.LVMProviderType, map[string]interface{}{
+		"volume-group": "data",
+		"devices":      "sdb, /dev/sdc",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), jc.ErrorIsNil)
+
+	cfg, err = storage.NewConfig("name", provider.LVMProviderType, map[string]interface{}{
+		"volume-group": "-bad",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), gc.ErrorMatches, `volume group name "-bad" not valid`)
+
+	cfg, err = storage.NewConfig("name", provider.LVMProviderType, map[string]interface{}{
+		"volume-group": "data",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), gc.ErrorMatches, `empty devices not valid`)
+}
+
+func (s *lvmSuite) TestSupports(c *gc.C) {
+	p := s.lvmProvider()
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeMachine)
+	c.Assert(p.Dynamic(), jc.IsTrue)
+}
+
+func (s *lvmSuite) TestCreateVolumes(c *gc.C) {
+	source := s.lvmVolumeSource()
+	cmd := s.commands.expect("vgs", "--noheadings", "-o", "vg_name")
+	cmd.respond("  other\n", nil)
+	s.expectNoChildren("sdb")
+	s.expectNoChildren("sdd")
+	s.commands.expect("pvcreate", "/dev/sdb")
+	s.commands.expect("pvcreate", "/dev/sdd")
+	s.commands.expect("vgcreate", "juju", "/dev/sdb", "/dev/sdd")
+	s.commands.expect("lvcreate", "--name", "volume-0", "--size", "1024m", "juju")
+	cmd = s.commands.expect("vgs", "--noheadings", "-o", "vg_name")
+	cmd.respond("  juju\n", nil)
+	s.commands.expect("lvcreate", "--name", "volume-1-2", "--size", "2048m", "juju")
+
+	attrs := map[string]interface{}{"devices": "sdb,sdd"}
+	volumes, volumeAttachments, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("0"),
+		Size:       1024,
+		Attributes: attrs,
+	}, {
+		Tag:        names.NewVolumeTag("1/2"),
+		Size:       2048,
+		Attributes: attrs,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeAttachments, gc.HasLen, 0)
+	c.Assert(volumes, jc.DeepEquals, []storage.Volume{{
+		names.NewVolumeTag("0"),
+		storage.VolumeInfo{VolumeId: "juju/volume-0", Size: 1024},
+	}, {
+		names.NewVolumeTag("1/2"),
+		storage.VolumeInfo{VolumeId: "juju/volume-1-2", Size: 2048},
+	}})
+}
+
+func (s *lvmSuite) TestCreateVolumesExistingVolumeGroup(c *gc.C) {
+	source := s.lvmVolumeSource()
+	cmd := s.commands.expect("vgs", "--noheadings", "-o", "vg_name")
+	cmd.respond("  juju\n", nil)
+	s.commands.expect("lvcreate", "--name", "volume-0", "--size", "1024m", "juju")
+
+	_, _, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("0"),
+		Size:       1024,
+		Attributes: map[string]interface{}{"devices": "sdb"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *lvmSuite) TestCreateVolumesConfiguredDevices(c *gc.C) {
+	source := s.lvmVolumeSource()
+	cmd := s.commands.expect("vgs", "--noheadings", "-o", "vg_name")
+	cmd.respond("", nil)
+	s.expectNoChildren("sdd")
+	s.commands.expect("pvcreate", "/dev/sdd")
+	s.commands.expect("vgcreate", "data", "/dev/sdd")
+	s.commands.expect("lvcreate", "--name", "volume-0", "--size", "1024m", "data")
+
+	volumes, _, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 1024,
+		Attributes: map[string]interface{}{
+			"volume-group": "data",
+			"devices":      "/dev/sdd",
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumes[0].VolumeId, gc.Equals, "data/volume-0")
+}
+
+func (s *lvmSuite) TestCreateVolumesDeviceInUse(c *gc.C) {
+	source := s.lvmVolumeSource()
+	cmd := s.commands.expect("vgs", "--noheadings", "-o", "vg_name")
+	cmd.respond("", nil)
+
+	_, _, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("0"),
+		Size:       1024,
+		Attributes: map[string]interface{}{"devices": "sdc"},
+	}})
+	c.Assert(err, gc.ErrorMatches, `choosing devices for volume group "juju": block device "sdc" is in use`)
+}
+
+func (s *lvmSuite) TestCreateVolumesDevicePartitioned(c *gc.C) {
+	// Partitions are not listed as block devices; lsblk shows
+	// them as children of the disk.
+	source := s.lvmVolumeSource()
+	cmd := s.commands.expect("vgs", "--noheadings", "-o", "vg_name")
+	cmd.respond("", nil)
+	cmd = s.commands.expect("lsblk", "--noheadings", "--raw", "-o", "KNAME", "/dev/sdb")
+	cmd.respond("sdb\nsdb1\n", nil)
+
+	_, _, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("0"),
+		Size:       1024,
+		Attributes: map[string]interface{}{"devices": "sdb"},
+	}})
+	c.Assert(err, gc.ErrorMatches, `choosing devices for volume group "juju": block device "sdb" is partitioned or in use`)
+}
+
+func (s *lvmSuite) TestCreateVolumesSimilarlyNamedDevice(c *gc.C) {
+	// A device whose name extends another's is not a partition of it.
+	s.blockDevices = append(s.blockDevices, storage.BlockDevice{DeviceName: "sdbb"})
+	source := s.lvmVolumeSource()
+	cmd := s.commands.expect("vgs", "--noheadings", "-o", "vg_name")
+	cmd.respond("", nil)
+	s.expectNoChildren("sdb")
+	s.commands.expect("pvcreate", "/dev/sdb")
+	s.commands.expect("vgcreate", "juju", "/dev/sdb")
+	s.commands.expect("lvcreate", "--name", "volume-0", "--size", "1024m", "juju")
+
+	_, _, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("0"),
+		Size:       1024,
+		Attributes: map[string]interface{}{"devices": "sdb"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *lvmSuite) TestCreateVolumesNoDevices(c *gc.C) {
+	source := s.lvmVolumeSource()
+	_, _, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 1024,
+	}})
+	c.Assert(err, gc.ErrorMatches, `creating volume: empty devices not valid`)
+}
+
+func (s *lvmSuite) expectNoChildren(name string) {
+	cmd := s.commands.expect("lsblk", "--noheadings", "--raw", "-o", "KNAME", "/dev/"+name)
+	cmd.respond(name+"\n", nil)
+}
+
+func (s *lvmSuite) expectLogicalVolumes(output string) {
+	cmd := s.commands.expect(
+		"lvs", "--noheadings", "--nosuffix", "--units", "m",
+		"-o", "vg_name,lv_name,lv_size",
+	)
+	cmd.respond(output, nil)
+}
+
+func (s *lvmSuite) TestListVolumes(c *gc.C) {
+	source := s.lvmVolumeSource()
+	s.expectLogicalVolumes("  juju volume-1 2048.00\n  data volume-0 1024.00\n  ubuntu-vg swap 512.00\n")
+
+	volumeIds, err := source.ListVolumes()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeIds, jc.DeepEquals, []string{"data/volume-0", "juju/volume-1"})
+}
+
+func (s *lvmSuite) TestDescribeVolumes(c *gc.C) {
+	source := s.lvmVolumeSource()
+	s.expectLogicalVolumes("  juju volume-0 1024.00\n  juju volume-1 2052.00\n")
+
+	info, err := source.DescribeVolumes([]string{"juju/volume-1", "juju/volume-0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, []storage.VolumeInfo{
+		{VolumeId: "juju/volume-1", Size: 2052},
+		{VolumeId: "juju/volume-0", Size: 1024},
+	})
+}
+
+func (s *lvmSuite) TestDescribeVolumesNotFound(c *gc.C) {
+	source := s.lvmVolumeSource()
+	s.expectLogicalVolumes("  juju volume-0 1024.00\n")
+
+	_, err := source.DescribeVolumes([]string{"juju/volume-1"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *lvmSuite) TestDestroyVolumes(c *gc.C) {
+	source := s.lvmVolumeSource()
+	s.expectLogicalVolumes("  juju volume-0 1024.00\n")
+	s.commands.expect("lvremove", "--force", "juju/volume-0")
+
+	errs := source.DestroyVolumes([]string{"juju/volume-0", "juju/volume-1", "../etc"})
+	c.Assert(errs, gc.HasLen, 3)
+	c.Assert(errs[0], jc.ErrorIsNil)
+	c.Assert(errs[1], jc.ErrorIsNil)
+	c.Assert(errs[2], gc.ErrorMatches, `invalid LVM volume ID "../etc"`)
+}
+
+func (s *lvmSuite) TestAttachVolumes(c *gc.C) {
+	source := s.lvmVolumeSource()
+	s.commands.expect("lvchange", "--activate", "y", "--permission", "rw", "juju/volume-0")
+	s.commands.expect("lvchange", "--activate", "y", "--permission", "r", "juju/volume-1")
+
+	volumeAttachments, err := source.AttachVolumes([]storage.VolumeAttachmentParams{{
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "juju/volume-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine: names.NewMachineTag("0"),
+		},
+	}, {
+		Volume:   names.NewVolumeTag("1"),
+		VolumeId: "juju/volume-1",
+		AttachmentParams: storage.AttachmentParams{
+			Machine:  names.NewMachineTag("0"),
+			ReadOnly: true,
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeAttachments, jc.DeepEquals, []storage.VolumeAttachment{{
+		names.NewVolumeTag("0"),
+		names.NewMachineTag("0"),
+		storage.VolumeAttachmentInfo{
+			DeviceName: "juju/volume-0",
+		},
+	}, {
+		names.NewVolumeTag("1"),
+		names.NewMachineTag("0"),
+		storage.VolumeAttachmentInfo{
+			DeviceName: "juju/volume-1",
+			ReadOnly:   true,
+		},
+	}})
+}
+
+func (s *lvmSuite) TestDetachVolumes(c *gc.C) {
+	source := s.lvmVolumeSource()
+	cmd := s.commands.expect("lvchange", "--activate", "n", "juju/volume-0")
+	cmd.respond("", errors.New("in use"))
+
+	err := source.DetachVolumes([]storage.VolumeAttachmentParams{{
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "juju/volume-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine: names.NewMachineTag("0"),
+		},
+	}})
+	c.Assert(err, gc.ErrorMatches, "detaching volume 0: in use")
+}
@@ -0,0 +1,371 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/schema"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	// NFSProviderType is the provider type for the NFS storage
+	// provider.
+	NFSProviderType = storage.ProviderType("nfs")
+
+	// NFSServer is the name of the pool attribute that specifies
+	// the host name or address of the NFS server.
+	NFSServer = "server"
+
+	// NFSPath is the name of the pool attribute that specifies the
+	// absolute path of the export on the NFS server. A directory is
+	// created within the export for each filesystem.
+	NFSPath = "path"
+
+	// NFSOptions is the name of the pool attribute that specifies
+	// a comma-separated list of options (e.g. "vers=4,soft") to
+	// pass to mount when mounting the export.
+	NFSOptions = "options"
+
+	// nfsOptionsSeparator separates the mount source from the mount
+	// options in the filesystem IDs of NFS filesystems. Filesystem
+	// attachment parameters do not carry pool attributes, so the
+	// options must be recorded in the filesystem ID for machines to
+	// mount the filesystem with them.
+	nfsOptionsSeparator = ";"
+)
+
+var nfsConfigFields = schema.Fields{
+	NFSServer:  schema.String(),
+	NFSPath:    schema.String(),
+	NFSOptions: schema.String(),
+}
+
+var nfsConfigChecker = schema.FieldMap(
+	nfsConfigFields,
+	schema.Defaults{
+		NFSOptions: "",
+	},
+)
+
+type nfsConfig struct {
+	server  string
+	path    string
+	options string
+}
+
+func newNFSConfig(attrs map[string]interface{}) (*nfsConfig, error) {
+	out, err := nfsConfigChecker.Coerce(attrs, nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "validating NFS storage config")
+	}
+	coerced := out.(map[string]interface{})
+	cfg := &nfsConfig{
+		server:  coerced[NFSServer].(string),
+		path:    coerced[NFSPath].(string),
+		options: coerced[NFSOptions].(string),
+	}
+	if cfg.server == "" || strings.ContainsAny(cfg.server, ":/ ") {
+		return nil, errors.NotValidf("NFS server %q", cfg.server)
+	}
+	if !path.IsAbs(cfg.path) {
+		return nil, errors.NotValidf("NFS export path %q", cfg.path)
+	}
+	if strings.ContainsAny(cfg.options, nfsOptionsSeparator+" ") {
+		return nil, errors.NotValidf("NFS mount options %q", cfg.options)
+	}
+	return cfg, nil
+}
+
+// source returns the mount source of the export.
+func (cfg *nfsConfig) source() string {
+	return cfg.server + ":" + path.Clean(cfg.path)
+}
+
+// nfsFilesystemId returns the filesystem ID for the filesystem with
+// the given mount source and options.
+func nfsFilesystemId(source, options string) string {
+	if options == "" {
+		return source
+	}
+	return source + nfsOptionsSeparator + options
+}
+
+// parseNFSFilesystemId returns the mount source and options of the
+// filesystem with the given filesystem ID.
+func parseNFSFilesystemId(filesystemId string) (source, options string, _ error) {
+	parts := strings.SplitN(filesystemId, nfsOptionsSeparator, 2)
+	source = parts[0]
+	if len(parts) == 2 {
+		options = parts[1]
+	}
+	i := strings.Index(source, ":")
+	if i <= 0 || !path.IsAbs(source[i+1:]) {
+		return "", "", errors.NotValidf("NFS filesystem ID %q", filesystemId)
+	}
+	return source, options, nil
+}
+
+// nfsProvider creates filesystem sources which create directories
+// within an NFS export, and mount them on the machines to which they
+// are attached.
+//
+// NFS filesystems are environment-scoped, as they are created on a
+// remote server; their attachments, on the other hand, are made from
+// within each machine. Each filesystem is still attached to a single
+// unit's machine: sharing one between the units of a service needs
+// shared storage, which state does not yet support.
+type nfsProvider struct {
+	// run is a function used for running commands on the local machine.
+	run runCommandFunc
+}
+
+var (
+	_ storage.Provider         = (*nfsProvider)(nil)
+	_ storage.AttachmentScoper = (*nfsProvider)(nil)
+)
+
+// ValidateConfig is defined on the Provider interface.
+func (p *nfsProvider) ValidateConfig(cfg *storage.Config) error {
+	_, err := newNFSConfig(cfg.Attrs())
+	return errors.Trace(err)
+}
+
+// VolumeSource is defined on the Provider interface.
+func (p *nfsProvider) VolumeSource(environConfig *config.Config, sourceConfig *storage.Config) (storage.VolumeSource, error) {
+	return nil, errors.NotSupportedf("volumes")
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (p *nfsProvider) FilesystemSource(environConfig *config.Config, sourceConfig *storage.Config) (storage.FilesystemSource, error) {
+	return &nfsFilesystemSource{&osDirFuncs{p.run}, p.run}, nil
+}
+
+// Supports is defined on the Provider interface.
+func (*nfsProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindFilesystem
+}
+
+// Scope is defined on the Provider interface.
+func (*nfsProvider) Scope() storage.Scope {
+	return storage.ScopeEnviron
+}
+
+// AttachmentScope is defined on the AttachmentScoper interface.
+func (*nfsProvider) AttachmentScope() storage.Scope {
+	return storage.ScopeMachine
+}
+
+// Dynamic is defined on the Provider interface.
+func (*nfsProvider) Dynamic() bool {
+	return true
+}
+
+type nfsFilesystemSource struct {
+	dirFuncs dirFuncs
+	run      runCommandFunc
+}
+
+var _ storage.FilesystemSource = (*nfsFilesystemSource)(nil)
+
+// nfsTempDir is used to create the directories on which NFS exports
+// are temporarily mounted while creating and destroying filesystems.
+var nfsTempDir = ioutil.TempDir
+
+// nfsRemoveAll is used to remove the directories of destroyed
+// filesystems.
+var nfsRemoveAll = os.RemoveAll
+
+// ValidateFilesystemParams is defined on the FilesystemSource interface.
+func (s *nfsFilesystemSource) ValidateFilesystemParams(params storage.FilesystemParams) error {
+	_, err := newNFSConfig(params.Attributes)
+	return errors.Trace(err)
+}
+
+// CreateFilesystems is defined on the FilesystemSource interface.
+func (s *nfsFilesystemSource) CreateFilesystems(args []storage.FilesystemParams) ([]storage.Filesystem, error) {
+	filesystems := make([]storage.Filesystem, len(args))
+	for i, arg := range args {
+		filesystem, err := s.createFilesystem(arg)
+		if err != nil {
+			return nil, errors.Annotate(err, "creating filesystem")
+		}
+		filesystems[i] = filesystem
+	}
+	return filesystems, nil
+}
+
+func (s *nfsFilesystemSource) createFilesystem(params storage.FilesystemParams) (storage.Filesystem, error) {
+	var filesystem storage.Filesystem
+	cfg, err := newNFSConfig(params.Attributes)
+	if err != nil {
+		return filesystem, errors.Trace(err)
+	}
+	// NFS exports have no quotas, so the size of each filesystem is
+	// that of the export it is created in.
+	var sizeInMiB uint64
+	dirName := params.Tag.String()
+	err = s.withExport(cfg.source(), cfg.options, func(mountPoint string) error {
+		size, err := s.dirFuncs.calculateSize(mountPoint)
+		if err != nil {
+			return errors.Annotate(err, "getting size")
+		}
+		if size < params.Size {
+			return errors.Errorf("filesystem is not big enough (%dM < %dM)", size, params.Size)
+		}
+		sizeInMiB = size
+		return ensureDir(s.dirFuncs, filepath.Join(mountPoint, dirName))
+	})
+	if err != nil {
+		return filesystem, errors.Trace(err)
+	}
+	filesystem = storage.Filesystem{
+		params.Tag,
+		names.VolumeTag{},
+		storage.FilesystemInfo{
+			FilesystemId: nfsFilesystemId(path.Join(cfg.source(), dirName), cfg.options),
+			Size:         sizeInMiB,
+		},
+	}
+	return filesystem, nil
+}
+
+// DestroyFilesystems is defined on the FilesystemSource interface.
+func (s *nfsFilesystemSource) DestroyFilesystems(filesystemIds []string) []error {
+	results := make([]error, len(filesystemIds))
+	for i, filesystemId := range filesystemIds {
+		results[i] = s.destroyFilesystem(filesystemId)
+	}
+	return results
+}
+
+func (s *nfsFilesystemSource) destroyFilesystem(filesystemId string) error {
+	source, options, err := parseNFSFilesystemId(filesystemId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	i := strings.Index(source, ":")
+	exportPath, dirName := path.Split(source[i+1:])
+	exportSource := source[:i+1] + path.Clean(exportPath)
+	err = s.withExport(exportSource, options, func(mountPoint string) error {
+		dir := filepath.Join(mountPoint, dirName)
+		err := nfsRemoveAll(dir)
+		if os.IsPermission(err) {
+			// Servers that squash root, as most do, do not let us
+			// remove files written by the units. The directory is
+			// never reused, so leave it for the server's
+			// administrator rather than failing.
+			logger.Warningf("cannot remove %q from NFS export %q; it must be removed on the server: %v", dirName, exportSource, err)
+			return nil
+		}
+		return errors.Trace(err)
+	})
+	return errors.Annotatef(err, "destroying filesystem %q", filesystemId)
+}
+
+// withExport mounts the export with the given source on a temporary
+// directory for the duration of a call to f.
+func (s *nfsFilesystemSource) withExport(source, options string, f func(mountPoint string) error) error {
+	mountPoint, err := nfsTempDir("", "juju-nfs")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.Remove(mountPoint)
+	if err := s.mount(source, mountPoint, options); err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if _, err := s.run("umount", mountPoint); err != nil {
+			logger.Warningf("cannot unmount NFS export %q from %q: %v", source, mountPoint, err)
+		}
+	}()
+	return f(mountPoint)
+}
+
+func (s *nfsFilesystemSource) mount(source, mountPoint, options string) error {
+	args := []string{"-t", "nfs"}
+	if options != "" {
+		args = append(args, "-o", options)
+	}
+	args = append(args, source, mountPoint)
+	if _, err := s.run("mount", args...); err != nil {
+		return errors.Annotatef(err, "cannot mount NFS export %q", source)
+	}
+	return nil
+}
+
+// AttachFilesystems is defined on the FilesystemSource interface.
+func (s *nfsFilesystemSource) AttachFilesystems(args []storage.FilesystemAttachmentParams) ([]storage.FilesystemAttachment, error) {
+	attachments := make([]storage.FilesystemAttachment, len(args))
+	for i, arg := range args {
+		attachment, err := s.attachFilesystem(arg)
+		if err != nil {
+			return nil, errors.Annotatef(err, "attaching %s", names.ReadableString(arg.Filesystem))
+		}
+		attachments[i] = attachment
+	}
+	return attachments, nil
+}
+
+func (s *nfsFilesystemSource) attachFilesystem(arg storage.FilesystemAttachmentParams) (storage.FilesystemAttachment, error) {
+	mountPoint := arg.Path
+	if mountPoint == "" {
+		return storage.FilesystemAttachment{}, errNoMountPoint
+	}
+	source, options, err := parseNFSFilesystemId(arg.FilesystemId)
+	if err != nil {
+		return storage.FilesystemAttachment{}, errors.Trace(err)
+	}
+	if err := ensureDir(s.dirFuncs, mountPoint); err != nil {
+		return storage.FilesystemAttachment{}, errors.Trace(err)
+	}
+
+	// Check if the mount already exists.
+	mountPointSource, err := s.dirFuncs.mountPointSource(mountPoint)
+	if err != nil {
+		return storage.FilesystemAttachment{}, errors.Trace(err)
+	}
+	if mountPointSource != source {
+		if err := ensureEmptyDir(s.dirFuncs, mountPoint); err != nil {
+			return storage.FilesystemAttachment{}, err
+		}
+		if arg.ReadOnly {
+			if options != "" {
+				options += ","
+			}
+			options += "ro"
+		}
+		if err := s.mount(source, mountPoint, options); err != nil {
+			return storage.FilesystemAttachment{}, errors.Trace(err)
+		}
+	}
+
+	return storage.FilesystemAttachment{
+		arg.Filesystem,
+		arg.Machine,
+		storage.FilesystemAttachmentInfo{
+			Path:     mountPoint,
+			ReadOnly: arg.ReadOnly,
+		},
+	}, nil
+}
+
+// DetachFilesystems is defined on the FilesystemSource interface.
+func (s *nfsFilesystemSource) DetachFilesystems(args []storage.FilesystemAttachmentParams) error {
+	for _, arg := range args {
+		if err := maybeUnmount(s.run, s.dirFuncs, arg.Path); err != nil {
+			return errors.Annotatef(err, "detaching filesystem %s", arg.Filesystem.Id())
+		}
+	}
+	return nil
+}
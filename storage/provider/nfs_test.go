@@ -0,0 +1,254 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&nfsSuite{})
+
+type nfsSuite struct {
+	testing.BaseSuite
+	commands     *mockRunCommand
+	mockDirFuncs *provider.MockDirFuncs
+	mountPoint   string
+}
+
+func (s *nfsSuite) SetUpTest(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("Tests relevant only on *nix systems")
+	}
+	s.BaseSuite.SetUpTest(c)
+	s.commands = &mockRunCommand{c: c}
+	s.mountPoint = filepath.Join(c.MkDir(), "juju-nfs")
+	s.PatchValue(provider.NFSTempDir, func(dir, prefix string) (string, error) {
+		return s.mountPoint, os.Mkdir(s.mountPoint, 0755)
+	})
+}
+
+func (s *nfsSuite) TearDownTest(c *gc.C) {
+	s.commands.assertDrained()
+	s.BaseSuite.TearDownTest(c)
+}
+
+func (s *nfsSuite) nfsFilesystemSource() storage.FilesystemSource {
+	source, d := provider.NFSFilesystemSource(s.commands.run)
+	s.mockDirFuncs = d
+	return source
+}
+
+func (s *nfsSuite) TestValidateConfig(c *gc.C) {
+	p := provider.NFSProvider(s.commands.run)
+	cfg, err := storage.NewConfig("name", provider.NFSProviderType, map[string]interface{}{
+		"server":  "nfs.example.com",
+		"path":    "/exports/juju",
+		"options": "vers=4,soft",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), jc.ErrorIsNil)
+
+	for _, attrs := range []map[string]interface{}{
+		{"path": "/exports/juju"},
+		{"server": "nfs.example.com", "path": "exports/juju"},
+		{"server": "nfs.example.com:/exports", "path": "/juju"},
+		{"server": "nfs.example.com", "path": "/exports/juju", "options": "ro;rw"},
+	} {
+		cfg, err := storage.NewConfig("name", provider.NFSProviderType, attrs)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(p.ValidateConfig(cfg), gc.NotNil)
+	}
+}
+
+func (s *nfsSuite) TestSupports(c *gc.C) {
+	p := provider.NFSProvider(s.commands.run)
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsFalse)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsTrue)
+	c.Assert(p.Scope(), gc.Equals, storage.ScopeEnviron)
+	c.Assert(p.(storage.AttachmentScoper).AttachmentScope(), gc.Equals, storage.ScopeMachine)
+	c.Assert(p.Dynamic(), jc.IsTrue)
+}
+
+func (s *nfsSuite) TestCreateFilesystems(c *gc.C) {
+	source := s.nfsFilesystemSource()
+	s.commands.expect("mount", "-t", "nfs", "-o", "vers=4", "nfs.example.com:/exports/juju", s.mountPoint)
+	cmd := s.commands.expect("df", "--output=size", s.mountPoint)
+	cmd.respond("1K-blocks\n4096", nil)
+	s.commands.expect("umount", s.mountPoint)
+
+	filesystems, err := source.CreateFilesystems([]storage.FilesystemParams{{
+		Tag:  names.NewFilesystemTag("0"),
+		Size: 2,
+		Attributes: map[string]interface{}{
+			"server":  "nfs.example.com",
+			"path":    "/exports/juju/",
+			"options": "vers=4",
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(filesystems, jc.DeepEquals, []storage.Filesystem{{
+		Tag: names.NewFilesystemTag("0"),
+		FilesystemInfo: storage.FilesystemInfo{
+			FilesystemId: "nfs.example.com:/exports/juju/filesystem-0;vers=4",
+			Size:         4,
+		},
+	}})
+	c.Assert(s.mockDirFuncs.Dirs.Contains(filepath.Join(s.mountPoint, "filesystem-0")), jc.IsTrue)
+}
+
+func (s *nfsSuite) TestCreateFilesystemsNotBigEnough(c *gc.C) {
+	source := s.nfsFilesystemSource()
+	s.commands.expect("mount", "-t", "nfs", "nfs.example.com:/exports", s.mountPoint)
+	cmd := s.commands.expect("df", "--output=size", s.mountPoint)
+	cmd.respond("1K-blocks\n2048", nil)
+	s.commands.expect("umount", s.mountPoint)
+
+	_, err := source.CreateFilesystems([]storage.FilesystemParams{{
+		Tag:  names.NewFilesystemTag("0"),
+		Size: 4,
+		Attributes: map[string]interface{}{
+			"server": "nfs.example.com",
+			"path":   "/exports",
+		},
+	}})
+	c.Assert(err, gc.ErrorMatches, `creating filesystem: filesystem is not big enough \(2M < 4M\)`)
+	c.Assert(s.mockDirFuncs.Dirs, gc.HasLen, 0)
+}
+
+func (s *nfsSuite) TestCreateFilesystemsMountFails(c *gc.C) {
+	source := s.nfsFilesystemSource()
+	cmd := s.commands.expect("mount", "-t", "nfs", "nfs.example.com:/exports", s.mountPoint)
+	cmd.respond("", errors.New("access denied"))
+
+	_, err := source.CreateFilesystems([]storage.FilesystemParams{{
+		Tag: names.NewFilesystemTag("0"),
+		Attributes: map[string]interface{}{
+			"server": "nfs.example.com",
+			"path":   "/exports",
+		},
+	}})
+	c.Assert(err, gc.ErrorMatches, `creating filesystem: cannot mount NFS export "nfs.example.com:/exports": access denied`)
+}
+
+func (s *nfsSuite) TestDestroyFilesystems(c *gc.C) {
+	source := s.nfsFilesystemSource()
+	s.commands.expect("mount", "-t", "nfs", "-o", "soft", "nfs.example.com:/exports", s.mountPoint)
+	s.commands.expect("umount", s.mountPoint)
+	s.PatchValue(provider.NFSTempDir, func(dir, prefix string) (string, error) {
+		// Populate the "export" with the filesystem's directory.
+		return s.mountPoint, os.MkdirAll(filepath.Join(s.mountPoint, "filesystem-0", "data"), 0755)
+	})
+
+	errs := source.DestroyFilesystems([]string{
+		"nfs.example.com:/exports/filesystem-0;soft",
+		"filesystem-1",
+	})
+	c.Assert(errs, gc.HasLen, 2)
+	c.Assert(errs[0], jc.ErrorIsNil)
+	c.Assert(errs[1], gc.ErrorMatches, `NFS filesystem ID "filesystem-1" not valid`)
+
+	// The mount point may only be removed once it is empty.
+	_, err := os.Stat(s.mountPoint)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *nfsSuite) TestDestroyFilesystemsRootSquashed(c *gc.C) {
+	source := s.nfsFilesystemSource()
+	s.commands.expect("mount", "-t", "nfs", "nfs.example.com:/exports", s.mountPoint)
+	s.commands.expect("umount", s.mountPoint)
+	s.PatchValue(provider.NFSRemoveAll, func(path string) error {
+		c.Assert(path, gc.Equals, filepath.Join(s.mountPoint, "filesystem-0"))
+		return &os.PathError{Op: "unlinkat", Path: path, Err: os.ErrPermission}
+	})
+
+	errs := source.DestroyFilesystems([]string{"nfs.example.com:/exports/filesystem-0"})
+	c.Assert(errs, gc.HasLen, 1)
+	c.Assert(errs[0], jc.ErrorIsNil)
+}
+
+func (s *nfsSuite) TestDestroyFilesystemsRemoveError(c *gc.C) {
+	source := s.nfsFilesystemSource()
+	s.commands.expect("mount", "-t", "nfs", "nfs.example.com:/exports", s.mountPoint)
+	s.commands.expect("umount", s.mountPoint)
+	s.PatchValue(provider.NFSRemoveAll, func(path string) error {
+		return errors.New("stale file handle")
+	})
+
+	errs := source.DestroyFilesystems([]string{"nfs.example.com:/exports/filesystem-0"})
+	c.Assert(errs, gc.HasLen, 1)
+	c.Assert(errs[0], gc.ErrorMatches, `destroying filesystem "nfs.example.com:/exports/filesystem-0": stale file handle`)
+}
+
+func (s *nfsSuite) TestAttachFilesystems(c *gc.C) {
+	source := s.nfsFilesystemSource()
+	cmd := s.commands.expect("df", "--output=source", "/srv/0")
+	cmd.respond("headers\n/dev/sda1", nil)
+	s.commands.expect("mount", "-t", "nfs", "-o", "vers=4,ro", "nfs.example.com:/exports/filesystem-0", "/srv/0")
+	cmd = s.commands.expect("df", "--output=source", "/srv/1")
+	cmd.respond("headers\nnfs.example.com:/exports/filesystem-0", nil)
+
+	attachments, err := source.AttachFilesystems([]storage.FilesystemAttachmentParams{{
+		Filesystem:   names.NewFilesystemTag("0"),
+		FilesystemId: "nfs.example.com:/exports/filesystem-0;vers=4",
+		AttachmentParams: storage.AttachmentParams{
+			Machine:  names.NewMachineTag("0"),
+			ReadOnly: true,
+		},
+		Path: "/srv/0",
+	}, {
+		// Already mounted, so there is nothing to do.
+		Filesystem:   names.NewFilesystemTag("0"),
+		FilesystemId: "nfs.example.com:/exports/filesystem-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine: names.NewMachineTag("1"),
+		},
+		Path: "/srv/1",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attachments, jc.DeepEquals, []storage.FilesystemAttachment{{
+		names.NewFilesystemTag("0"),
+		names.NewMachineTag("0"),
+		storage.FilesystemAttachmentInfo{
+			Path:     "/srv/0",
+			ReadOnly: true,
+		},
+	}, {
+		names.NewFilesystemTag("0"),
+		names.NewMachineTag("1"),
+		storage.FilesystemAttachmentInfo{
+			Path: "/srv/1",
+		},
+	}})
+	c.Assert(s.mockDirFuncs.Dirs.Contains("/srv/0"), jc.IsTrue)
+}
+
+func (s *nfsSuite) TestAttachFilesystemsNoPathSpecified(c *gc.C) {
+	source := s.nfsFilesystemSource()
+	_, err := source.AttachFilesystems([]storage.FilesystemAttachmentParams{{
+		Filesystem:   names.NewFilesystemTag("0"),
+		FilesystemId: "nfs.example.com:/exports/filesystem-0",
+	}})
+	c.Assert(err, gc.ErrorMatches, "attaching filesystem 0: filesystem mount point not specified")
+}
+
+func (s *nfsSuite) TestDetachFilesystems(c *gc.C) {
+	source := s.nfsFilesystemSource()
+	testDetachFilesystems(c, s.commands, source, true)
+}
+
+func (s *nfsSuite) TestDetachFilesystemsUnattached(c *gc.C) {
+	source := s.nfsFilesystemSource()
+	testDetachFilesystems(c, s.commands, source, false)
+}
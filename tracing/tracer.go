@@ -0,0 +1,407 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+var (
+	// flushInterval holds the longest time a finished span waits
+	// to be exported.
+	flushInterval = time.Second
+
+	// maxBatch holds the greatest number of spans exported at once.
+	maxBatch = 256
+
+	// queueSize holds the number of finished spans that may wait
+	// to be exported. Spans finished when the queue is full are
+	// dropped, so that tracing never holds up the traced work.
+	queueSize = 4096
+
+	// exportTimeout holds the time allowed for a collector to
+	// accept a batch of spans.
+	exportTimeout = 10 * time.Second
+)
+
+// scopeName is the name of the instrumentation scope of the exported
+// spans.
+const scopeName = "github.com/juju/juju/tracing"
+
+// ValidateEndpoint returns an error if the given trace endpoint is not
+// valid. An endpoint is either the absolute path of a file, or a
+// file:// URL, to which spans are appended, or the http:// or https://
+// URL to which an OTLP/HTTP collector accepts spans, usually
+// http://<host>:4318/v1/traces.
+func ValidateEndpoint(endpoint string) error {
+	_, err := parseEndpoint(endpoint)
+	return err
+}
+
+// EndpointFile returns the path of the file to which spans are
+// appended, and true, if the given endpoint is a file; otherwise it
+// returns false.
+func EndpointFile(endpoint string) (string, bool) {
+	u, err := parseEndpoint(endpoint)
+	if err != nil || u.Scheme == "http" || u.Scheme == "https" {
+		return "", false
+	}
+	return filepath.Clean(u.Path), true
+}
+
+func parseEndpoint(endpoint string) (*url.URL, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Annotatef(err, "invalid trace endpoint %q", endpoint)
+	}
+	switch u.Scheme {
+	case "", "file":
+		if !filepath.IsAbs(u.Path) {
+			return nil, errors.Errorf("invalid trace endpoint %q: file path must be absolute", endpoint)
+		}
+	case "http", "https":
+		if u.Host == "" {
+			return nil, errors.Errorf("invalid trace endpoint %q: no host", endpoint)
+		}
+	default:
+		return nil, errors.Errorf("invalid trace endpoint %q: unsupported scheme %q", endpoint, u.Scheme)
+	}
+	return u, nil
+}
+
+// exporter sends encoded batches of spans to their destination.
+type exporter interface {
+	export(data []byte) error
+	close() error
+}
+
+func newExporter(endpoint string) (exporter, error) {
+	u, err := parseEndpoint(endpoint)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if u.Scheme == "http" || u.Scheme == "https" {
+		return &httpExporter{
+			url:    u.String(),
+			client: &http.Client{Timeout: exportTimeout},
+		}, nil
+	}
+	f, err := os.OpenFile(u.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot open trace file")
+	}
+	return &fileExporter{f}, nil
+}
+
+// fileExporter appends each batch of spans to a file, one line per
+// batch, as read by the OpenTelemetry collector's file receiver.
+type fileExporter struct {
+	file *os.File
+}
+
+func (e *fileExporter) export(data []byte) error {
+	_, err := e.file.Write(append(data, '\n'))
+	return err
+}
+
+func (e *fileExporter) close() error {
+	return e.file.Close()
+}
+
+// httpExporter posts each batch of spans to an OTLP/HTTP collector.
+type httpExporter struct {
+	url    string
+	client *http.Client
+}
+
+func (e *httpExporter) export(data []byte) error {
+	resp, err := e.client.Post(e.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (e *httpExporter) close() error {
+	return nil
+}
+
+// Tracer starts spans and exports them, once finished, in batches.
+// All methods on Tracer may be called on a nil Tracer, which starts no
+// spans.
+type Tracer struct {
+	// dropped is accessed atomically, so it comes first to be
+	// 64-bit aligned on 32-bit platforms.
+	dropped int64
+
+	service  string
+	exporter exporter
+	spans    chan *Span
+
+	closeOnce sync.Once
+	closing   chan struct{}
+	closed    chan struct{}
+	closeErr  error
+}
+
+// NewTracer returns a tracer which exports the spans it starts to the
+// given endpoint (see ValidateEndpoint), recording them as the work
+// of the named service.
+func NewTracer(service, endpoint string) (*Tracer, error) {
+	exporter, err := newExporter(endpoint)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	t := &Tracer{
+		service:  service,
+		exporter: exporter,
+		spans:    make(chan *Span, queueSize),
+		closing:  make(chan struct{}),
+		closed:   make(chan struct{}),
+	}
+	go t.loop()
+	return t, nil
+}
+
+// StartSpan starts a new span of the given name and kind. If parent
+// is valid, the span is started on its behalf, and is not started at
+// all if the parent is not being sampled; otherwise the span starts a
+// new trace.
+func (t *Tracer) StartSpan(name string, kind SpanKind, parent SpanContext) *Span {
+	if t == nil {
+		return nil
+	}
+	if parent.IsValid() && !parent.Sampled {
+		return nil
+	}
+	s := &Span{
+		tracer: t,
+		name:   name,
+		kind:   kind,
+		parent: parent.SpanID,
+		start:  time.Now(),
+	}
+	if parent.IsValid() {
+		s.context.TraceID = parent.TraceID
+	} else {
+		randomID(s.context.TraceID[:])
+	}
+	randomID(s.context.SpanID[:])
+	s.context.Sampled = true
+	return s
+}
+
+// Close exports all the spans finished so far and stops the tracer.
+// Spans finished after Close is called are not exported.
+func (t *Tracer) Close() error {
+	if t == nil {
+		return nil
+	}
+	t.closeOnce.Do(func() {
+		close(t.closing)
+	})
+	<-t.closed
+	return t.closeErr
+}
+
+func (t *Tracer) enqueue(s *Span) {
+	select {
+	case t.spans <- s:
+	default:
+		atomic.AddInt64(&t.dropped, 1)
+	}
+}
+
+func (t *Tracer) loop() {
+	defer close(t.closed)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	var batch []*Span
+	for {
+		select {
+		case s := <-t.spans:
+			batch = append(batch, s)
+			if len(batch) < maxBatch {
+				continue
+			}
+		case <-ticker.C:
+		case <-t.closing:
+			t.export(t.drain(batch))
+			t.closeErr = t.exporter.close()
+			return
+		}
+		t.export(batch)
+		batch = nil
+	}
+}
+
+// drain returns batch with all the queued spans appended.
+func (t *Tracer) drain(batch []*Span) []*Span {
+	for {
+		select {
+		case s := <-t.spans:
+			batch = append(batch, s)
+		default:
+			return batch
+		}
+	}
+}
+
+func (t *Tracer) export(batch []*Span) {
+	if dropped := atomic.SwapInt64(&t.dropped, 0); dropped > 0 {
+		logger.Warningf("dropped %d spans: too many waiting to be exported", dropped)
+	}
+	for len(batch) > 0 {
+		n := len(batch)
+		if n > maxBatch {
+			n = maxBatch
+		}
+		data, err := json.Marshal(t.exportRequest(batch[:n]))
+		if err != nil {
+			logger.Errorf("cannot encode spans: %v", err)
+		} else if err := t.exporter.export(data); err != nil {
+			logger.Warningf("cannot export %d spans: %v", n, err)
+		}
+		batch = batch[n:]
+	}
+}
+
+// exportRequest returns the given spans in the form of an OTLP
+// ExportTraceServiceRequest.
+func (t *Tracer) exportRequest(batch []*Span) *exportRequest {
+	spans := make([]spanData, len(batch))
+	for i, s := range batch {
+		spans[i] = s.data()
+	}
+	return &exportRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource: resource{
+				Attributes: []keyValue{{
+					Key:   "service.name",
+					Value: newAnyValue(t.service),
+				}},
+			},
+			ScopeSpans: []scopeSpans{{
+				Scope: scope{Name: scopeName},
+				Spans: spans,
+			}},
+		}},
+	}
+}
+
+func (s *Span) data() spanData {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := spanData{
+		TraceID:           s.context.TraceID.String(),
+		SpanID:            s.context.SpanID.String(),
+		Name:              s.name,
+		Kind:              s.kind,
+		StartTimeUnixNano: strconv.FormatInt(s.start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(s.end.UnixNano(), 10),
+		Attributes:        append([]keyValue(nil), s.attributes...),
+	}
+	if s.parent != (SpanID{}) {
+		d.ParentSpanID = s.parent.String()
+	}
+	if s.failed {
+		d.Status = status{Code: statusCodeError, Message: s.message}
+	}
+	return d
+}
+
+// The types below define the OTLP JSON encoding of spans.
+
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []spanData `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type spanData struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              SpanKind   `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            status     `json:"status"`
+}
+
+const statusCodeError = 2
+
+type status struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func newAnyValue(value interface{}) anyValue {
+	var s string
+	switch value := value.(type) {
+	case string:
+		s = value
+	case int:
+		s = strconv.FormatInt(int64(value), 10)
+		return anyValue{IntValue: &s}
+	case int64:
+		s = strconv.FormatInt(value, 10)
+		return anyValue{IntValue: &s}
+	case bool:
+		return anyValue{BoolValue: &value}
+	case float64:
+		return anyValue{DoubleValue: &value}
+	default:
+		s = fmt.Sprint(value)
+	}
+	return anyValue{StringValue: &s}
+}
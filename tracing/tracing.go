@@ -0,0 +1,247 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The tracing package records spans describing the work done on behalf
+// of a request as it passes from the juju client, through the API
+// server and into state, and exports them in the OpenTelemetry (OTLP)
+// JSON format to a file or to a collector.
+//
+// The context of a span is passed between processes in the W3C
+// traceparent format (see http://www.w3.org/TR/trace-context/).
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	mathrand "math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+)
+
+var logger = loggo.GetLogger("juju.tracing")
+
+// TraceID identifies a trace.
+type TraceID [16]byte
+
+// String returns the id in hex.
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+// String returns the id in hex.
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SpanContext holds the part of a span that is propagated to the
+// spans started on its behalf, possibly in other processes.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+
+	// Sampled holds whether the span is being recorded. Spans are
+	// not recorded on behalf of a span which is not.
+	Sampled bool
+}
+
+// IsValid returns whether the context identifies a span.
+func (c SpanContext) IsValid() bool {
+	return c.TraceID != TraceID{} && c.SpanID != SpanID{}
+}
+
+// TraceParent returns the context in the W3C traceparent format.
+func (c SpanContext) TraceParent() string {
+	flags := "00"
+	if c.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", c.TraceID, c.SpanID, flags)
+}
+
+// ParseTraceParent parses a span context in the W3C traceparent
+// format, as returned by SpanContext.TraceParent.
+func ParseTraceParent(s string) (SpanContext, error) {
+	fail := func(reason string) (SpanContext, error) {
+		return SpanContext{}, errors.Errorf("invalid trace context %q: %s", s, reason)
+	}
+	parts := strings.Split(s, "-")
+	if len(parts) < 4 {
+		return fail("expected version, trace id, span id and flags")
+	}
+	// Later versions may add fields, which we ignore.
+	if parts[0] == "00" && len(parts) != 4 {
+		return fail("unexpected fields")
+	}
+	if version, err := hex.DecodeString(parts[0]); err != nil || len(version) != 1 || version[0] == 0xff {
+		return fail("bad version")
+	}
+	var c SpanContext
+	if err := decodeHex(c.TraceID[:], parts[1]); err != nil {
+		return fail("bad trace id")
+	}
+	if err := decodeHex(c.SpanID[:], parts[2]); err != nil {
+		return fail("bad span id")
+	}
+	var flags [1]byte
+	if err := decodeHex(flags[:], parts[3]); err != nil {
+		return fail("bad flags")
+	}
+	if !c.IsValid() {
+		return fail("zero trace or span id")
+	}
+	c.Sampled = flags[0]&1 != 0
+	return c, nil
+}
+
+// decodeHex decodes s into exactly len(dst) bytes.
+func decodeHex(dst []byte, s string) error {
+	if len(s) != 2*len(dst) {
+		return errors.New("wrong length")
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err
+}
+
+// SpanKind describes the relationship between a span and its parent,
+// using the values of the OTLP SpanKind enumeration.
+type SpanKind int
+
+const (
+	// SpanKindInternal is the kind of a span recording an operation
+	// within a process.
+	SpanKindInternal SpanKind = 1
+
+	// SpanKindServer is the kind of a span recording the handling
+	// of a request from a remote client.
+	SpanKindServer SpanKind = 2
+
+	// SpanKindClient is the kind of a span recording a request
+	// made to a remote server.
+	SpanKindClient SpanKind = 3
+)
+
+// Span records an operation done as part of a trace. All methods on
+// Span may be called on a nil Span, in which case they do nothing, so
+// callers need not check whether tracing is enabled.
+type Span struct {
+	tracer  *Tracer
+	name    string
+	kind    SpanKind
+	context SpanContext
+	parent  SpanID
+	start   time.Time
+
+	mu         sync.Mutex
+	end        time.Time
+	attributes []keyValue
+	failed     bool
+	message    string
+	ended      bool
+}
+
+// Context returns the context of the span, which may be passed to
+// Tracer.StartSpan to start spans on its behalf. It returns the zero
+// SpanContext for a nil span.
+func (s *Span) Context() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.context
+}
+
+// Child starts a new span, of the given name and kind, on behalf of s.
+func (s *Span) Child(name string, kind SpanKind) *Span {
+	if s == nil {
+		return nil
+	}
+	return s.tracer.StartSpan(name, kind, s.context)
+}
+
+// SetAttribute records an attribute of the span, replacing any value
+// already recorded for the key. String, integer, boolean and floating
+// point values are recorded as such; any other value is recorded as
+// its string representation.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	kv := keyValue{Key: key, Value: newAnyValue(value)}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.attributes {
+		if s.attributes[i].Key == key {
+			s.attributes[i] = kv
+			return
+		}
+	}
+	s.attributes = append(s.attributes, kv)
+}
+
+// SetError records that the operation failed with the given error. It
+// does nothing if err is nil.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failed = true
+	s.message = err.Error()
+}
+
+// End records the end of the span and queues it for export. Calls
+// after the first do nothing.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mu.Unlock()
+	s.tracer.enqueue(s)
+}
+
+var (
+	idMutex sync.Mutex
+	idRand  = mathrand.New(mathrand.NewSource(randomSeed()))
+)
+
+// randomSeed returns a seed for the generator of trace and span ids,
+// which must differ between processes for the ids to be unique.
+func randomSeed() int64 {
+	var seed [8]byte
+	if _, err := rand.Read(seed[:]); err != nil {
+		return time.Now().UnixNano()
+	}
+	return int64(binary.LittleEndian.Uint64(seed[:]))
+}
+
+// randomID fills id with random bytes, never leaving it all zero.
+func randomID(id []byte) {
+	idMutex.Lock()
+	defer idMutex.Unlock()
+	for {
+		nonZero := false
+		for i := range id {
+			id[i] = byte(idRand.Intn(256))
+			nonZero = nonZero || id[i] != 0
+		}
+		if nonZero {
+			return
+		}
+	}
+}
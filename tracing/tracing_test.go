@@ -0,0 +1,247 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package tracing_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/tracing"
+)
+
+type tracingSuite struct{}
+
+var _ = gc.Suite(&tracingSuite{})
+
+func (s *tracingSuite) TestParseTraceParent(c *gc.C) {
+	ctx, err := tracing.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.TraceID.String(), gc.Equals, "4bf92f3577b34da6a3ce929d0e0e4736")
+	c.Assert(ctx.SpanID.String(), gc.Equals, "00f067aa0ba902b7")
+	c.Assert(ctx.Sampled, jc.IsTrue)
+	c.Assert(ctx.TraceParent(), gc.Equals, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	ctx, err = tracing.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.Sampled, jc.IsFalse)
+
+	// Fields added by later versions are ignored.
+	ctx, err = tracing.ParseTraceParent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.IsValid(), jc.IsTrue)
+}
+
+func (s *tracingSuite) TestParseTraceParentInvalid(c *gc.C) {
+	for i, test := range []struct {
+		traceParent string
+		err         string
+	}{{
+		traceParent: "",
+		err:         `invalid trace context "": expected version, trace id, span id and flags`,
+	}, {
+		traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		err:         `invalid trace context ".*": unexpected fields`,
+	}, {
+		traceParent: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		err:         `invalid trace context ".*": bad version`,
+	}, {
+		traceParent: "00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01",
+		err:         `invalid trace context ".*": bad trace id`,
+	}, {
+		traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902bx-01",
+		err:         `invalid trace context ".*": bad span id`,
+	}, {
+		traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1",
+		err:         `invalid trace context ".*": bad flags`,
+	}, {
+		traceParent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		err:         `invalid trace context ".*": zero trace or span id`,
+	}} {
+		c.Logf("test %d: %q", i, test.traceParent)
+		_, err := tracing.ParseTraceParent(test.traceParent)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *tracingSuite) TestValidateEndpoint(c *gc.C) {
+	for _, endpoint := range []string{
+		"/var/log/juju/traces.json",
+		"file:///var/log/juju/traces.json",
+		"http://localhost:4318/v1/traces",
+		"https://collector.example.com/v1/traces",
+	} {
+		c.Check(tracing.ValidateEndpoint(endpoint), jc.ErrorIsNil)
+	}
+	c.Check(tracing.ValidateEndpoint("traces.json"), gc.ErrorMatches,
+		`invalid trace endpoint "traces.json": file path must be absolute`)
+	c.Check(tracing.ValidateEndpoint("http:///v1/traces"), gc.ErrorMatches,
+		`invalid trace endpoint "http:///v1/traces": no host`)
+	c.Check(tracing.ValidateEndpoint("grpc://localhost:4317"), gc.ErrorMatches,
+		`invalid trace endpoint "grpc://localhost:4317": unsupported scheme "grpc"`)
+}
+
+func (s *tracingSuite) TestEndpointFile(c *gc.C) {
+	for endpoint, expected := range map[string]string{
+		"/var/log/juju/traces.json":             "/var/log/juju/traces.json",
+		"file:///var/log/juju/../traces.json":   "/var/log/traces.json",
+		"http://localhost:4318/v1/traces":       "",
+		"https://collector.example.com/v1/path": "",
+		"traces.json":                           "",
+	} {
+		path, ok := tracing.EndpointFile(endpoint)
+		c.Check(path, gc.Equals, expected)
+		c.Check(ok, gc.Equals, expected != "")
+	}
+}
+
+func (s *tracingSuite) TestNilSpansDoNothing(c *gc.C) {
+	var tracer *tracing.Tracer
+	span := tracer.StartSpan("Client.FullStatus", tracing.SpanKindClient, tracing.SpanContext{})
+	c.Assert(span, gc.IsNil)
+	c.Assert(span.Child("state.RunTransaction", tracing.SpanKindInternal), gc.IsNil)
+	c.Assert(span.Context().IsValid(), jc.IsFalse)
+	span.SetAttribute("key", "value")
+	span.SetError(errors.New("boom"))
+	span.End()
+	c.Assert(tracer.Close(), jc.ErrorIsNil)
+}
+
+func (s *tracingSuite) newTracer(c *gc.C, endpoint string) *tracing.Tracer {
+	tracer, err := tracing.NewTracer("juju", endpoint)
+	c.Assert(err, jc.ErrorIsNil)
+	return tracer
+}
+
+// exportedSpan holds the parts of an exported span checked by the tests.
+type exportedSpan struct {
+	TraceID      string `json:"traceId"`
+	SpanID       string `json:"spanId"`
+	ParentSpanID string `json:"parentSpanId"`
+	Name         string `json:"name"`
+	Kind         int    `json:"kind"`
+	Attributes   []struct {
+		Key   string                 `json:"key"`
+		Value map[string]interface{} `json:"value"`
+	} `json:"attributes"`
+	Status struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"status"`
+}
+
+type exportedRequest struct {
+	ResourceSpans []struct {
+		Resource struct {
+			Attributes []struct {
+				Key   string                 `json:"key"`
+				Value map[string]interface{} `json:"value"`
+			} `json:"attributes"`
+		} `json:"resource"`
+		ScopeSpans []struct {
+			Scope struct {
+				Name string `json:"name"`
+			} `json:"scope"`
+			Spans []exportedSpan `json:"spans"`
+		} `json:"scopeSpans"`
+	} `json:"resourceSpans"`
+}
+
+// recordSpans starts and ends a span with a failing child span.
+func recordSpans(tracer *tracing.Tracer) (parent, child *tracing.Span) {
+	parent = tracer.StartSpan("juju deploy", tracing.SpanKindInternal, tracing.SpanContext{})
+	child = parent.Child("Client.ServiceDeploy", tracing.SpanKindClient)
+	child.SetAttribute("rpc.service", "Client")
+	child.SetAttribute("rpc.version", 1)
+	child.SetAttribute("rpc.version", 0)
+	child.SetAttribute("retried", true)
+	child.SetError(errors.New("boom"))
+	child.End()
+	parent.End()
+	return parent, child
+}
+
+func (s *tracingSuite) checkExported(c *gc.C, data []byte, parent, child *tracing.Span) {
+	var req exportedRequest
+	err := json.Unmarshal(data, &req)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(req.ResourceSpans, gc.HasLen, 1)
+	resource := req.ResourceSpans[0]
+	c.Assert(resource.Resource.Attributes, gc.HasLen, 1)
+	c.Assert(resource.Resource.Attributes[0].Key, gc.Equals, "service.name")
+	c.Assert(resource.Resource.Attributes[0].Value, jc.DeepEquals, map[string]interface{}{"stringValue": "juju"})
+	c.Assert(resource.ScopeSpans, gc.HasLen, 1)
+	c.Assert(resource.ScopeSpans[0].Scope.Name, gc.Equals, "github.com/juju/juju/tracing")
+
+	spans := resource.ScopeSpans[0].Spans
+	c.Assert(spans, gc.HasLen, 2)
+	exportedChild, exportedParent := spans[0], spans[1]
+
+	c.Check(exportedParent.Name, gc.Equals, "juju deploy")
+	c.Check(exportedParent.Kind, gc.Equals, int(tracing.SpanKindInternal))
+	c.Check(exportedParent.TraceID, gc.Equals, parent.Context().TraceID.String())
+	c.Check(exportedParent.SpanID, gc.Equals, parent.Context().SpanID.String())
+	c.Check(exportedParent.ParentSpanID, gc.Equals, "")
+	c.Check(exportedParent.Status.Code, gc.Equals, 0)
+
+	c.Check(exportedChild.Name, gc.Equals, "Client.ServiceDeploy")
+	c.Check(exportedChild.Kind, gc.Equals, int(tracing.SpanKindClient))
+	c.Check(exportedChild.TraceID, gc.Equals, parent.Context().TraceID.String())
+	c.Check(exportedChild.SpanID, gc.Equals, child.Context().SpanID.String())
+	c.Check(exportedChild.ParentSpanID, gc.Equals, parent.Context().SpanID.String())
+	c.Check(exportedChild.Status.Code, gc.Equals, 2)
+	c.Check(exportedChild.Status.Message, gc.Equals, "boom")
+	c.Assert(exportedChild.Attributes, gc.HasLen, 3)
+	c.Check(exportedChild.Attributes[0].Value, jc.DeepEquals, map[string]interface{}{"stringValue": "Client"})
+	c.Check(exportedChild.Attributes[1].Value, jc.DeepEquals, map[string]interface{}{"intValue": "0"})
+	c.Check(exportedChild.Attributes[2].Value, jc.DeepEquals, map[string]interface{}{"boolValue": true})
+}
+
+func (s *tracingSuite) TestExportToFile(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "traces.json")
+	tracer := s.newTracer(c, "file://"+path)
+	parent, child := recordSpans(tracer)
+	err := tracer.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	c.Assert(lines, gc.HasLen, 1)
+	s.checkExported(c, []byte(lines[0]), parent, child)
+}
+
+func (s *tracingSuite) TestExportToCollector(c *gc.C) {
+	received := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		c.Check(req.Method, gc.Equals, "POST")
+		c.Check(req.URL.Path, gc.Equals, "/v1/traces")
+		c.Check(req.Header.Get("Content-Type"), gc.Equals, "application/json")
+		data, err := ioutil.ReadAll(req.Body)
+		c.Check(err, jc.ErrorIsNil)
+		received <- data
+	}))
+	defer server.Close()
+
+	tracer := s.newTracer(c, server.URL+"/v1/traces")
+	parent, child := recordSpans(tracer)
+	err := tracer.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkExported(c, <-received, parent, child)
+}
+
+func (s *tracingSuite) TestUnsampledParent(c *gc.C) {
+	tracer := s.newTracer(c, filepath.Join(c.MkDir(), "traces.json"))
+	defer tracer.Close()
+	parent, err := tracing.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tracer.StartSpan("Client.FullStatus", tracing.SpanKindServer, parent), gc.IsNil)
+}
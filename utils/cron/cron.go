@@ -0,0 +1,167 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package cron parses cron-like schedules, as used to configure
+// recurring tasks such as scheduled backups.
+package cron
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// Schedule is a parsed cron-like schedule.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// domStar and dowStar record whether the day of month and day
+	// of week fields were unrestricted. As in cron, when both are
+	// restricted a time matches if either of them matches.
+	domStar, dowStar bool
+}
+
+var shorthands = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+type fieldRange struct {
+	name     string
+	min, max uint
+}
+
+var (
+	minuteRange = fieldRange{"minute", 0, 59}
+	hourRange   = fieldRange{"hour", 0, 23}
+	domRange    = fieldRange{"day of month", 1, 31}
+	monthRange  = fieldRange{"month", 1, 12}
+	// Both 0 and 7 denote Sunday.
+	dowRange = fieldRange{"day of week", 0, 7}
+)
+
+// Parse parses a schedule made up of five space-separated fields:
+// minute, hour, day of month, month and day of week. Each field is
+// "*" or a comma-separated list of numbers and ranges (e.g. "1-5"),
+// optionally followed by a step (e.g. "*/15" or "0-30/10"). The
+// shorthands "@hourly", "@daily", "@weekly" and "@monthly" are also
+// accepted. Times are interpreted in UTC.
+func Parse(spec string) (*Schedule, error) {
+	expanded := spec
+	if shorthand, ok := shorthands[spec]; ok {
+		expanded = shorthand
+	}
+	fields := strings.Fields(expanded)
+	if len(fields) != 5 {
+		return nil, errors.NotValidf("schedule %q: expected 5 fields", spec)
+	}
+	var s Schedule
+	var err error
+	if s.minute, err = parseField(fields[0], minuteRange); err != nil {
+		return nil, errors.Annotatef(err, "schedule %q", spec)
+	}
+	if s.hour, err = parseField(fields[1], hourRange); err != nil {
+		return nil, errors.Annotatef(err, "schedule %q", spec)
+	}
+	if s.dom, err = parseField(fields[2], domRange); err != nil {
+		return nil, errors.Annotatef(err, "schedule %q", spec)
+	}
+	if s.month, err = parseField(fields[3], monthRange); err != nil {
+		return nil, errors.Annotatef(err, "schedule %q", spec)
+	}
+	if s.dow, err = parseField(fields[4], dowRange); err != nil {
+		return nil, errors.Annotatef(err, "schedule %q", spec)
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domStar = fields[2] == "*"
+	s.dowStar = fields[4] == "*"
+	return &s, nil
+}
+
+// parseField returns the set of values matched by the field, as a
+// bit set.
+func parseField(field string, r fieldRange) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := uint(1)
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.ParseUint(part[i+1:], 10, 8)
+			if err != nil || n == 0 {
+				return 0, errors.Errorf("invalid step in %s %q", r.name, field)
+			}
+			step, part = uint(n), part[:i]
+		}
+		first, last := r.min, r.max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			n, err := strconv.ParseUint(bounds[0], 10, 8)
+			if err != nil {
+				return 0, errors.Errorf("invalid %s %q", r.name, field)
+			}
+			first, last = uint(n), uint(n)
+			if len(bounds) == 2 {
+				n, err := strconv.ParseUint(bounds[1], 10, 8)
+				if err != nil {
+					return 0, errors.Errorf("invalid %s %q", r.name, field)
+				}
+				last = uint(n)
+			} else if step > 1 {
+				// "n/step" means from n to the end of the range.
+				last = r.max
+			}
+		}
+		if first < r.min || last > r.max || first > last {
+			return 0, errors.Errorf("%s %q out of range %d-%d", r.name, field, r.min, r.max)
+		}
+		for v := first; v <= last; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// maxSearch bounds the search for the next matching time, so that
+// schedules which can never match (e.g. "0 0 30 2 *") terminate.
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// Next returns the earliest time after t that matches the schedule,
+// or the zero time if there is none.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxSearch)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) matchDay(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
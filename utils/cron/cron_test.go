@@ -0,0 +1,116 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cron_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/utils/cron"
+)
+
+type cronSuite struct{}
+
+var _ = gc.Suite(&cronSuite{})
+
+// start is a Wednesday.
+var start = time.Date(2015, 7, 15, 10, 30, 45, 0, time.UTC)
+
+var nextTests = []struct {
+	spec     string
+	expected []time.Time
+}{{
+	spec: "* * * * *",
+	expected: []time.Time{
+		time.Date(2015, 7, 15, 10, 31, 0, 0, time.UTC),
+		time.Date(2015, 7, 15, 10, 32, 0, 0, time.UTC),
+	},
+}, {
+	spec: "*/20 * * * *",
+	expected: []time.Time{
+		time.Date(2015, 7, 15, 10, 40, 0, 0, time.UTC),
+		time.Date(2015, 7, 15, 11, 0, 0, 0, time.UTC),
+	},
+}, {
+	spec: "15 3 * * *",
+	expected: []time.Time{
+		time.Date(2015, 7, 16, 3, 15, 0, 0, time.UTC),
+		time.Date(2015, 7, 17, 3, 15, 0, 0, time.UTC),
+	},
+}, {
+	spec: "0 0,12 * * 1-5",
+	expected: []time.Time{
+		time.Date(2015, 7, 15, 12, 0, 0, 0, time.UTC),
+		time.Date(2015, 7, 16, 0, 0, 0, 0, time.UTC),
+		time.Date(2015, 7, 16, 12, 0, 0, 0, time.UTC),
+		time.Date(2015, 7, 17, 0, 0, 0, 0, time.UTC),
+		time.Date(2015, 7, 17, 12, 0, 0, 0, time.UTC),
+		time.Date(2015, 7, 20, 0, 0, 0, 0, time.UTC),
+	},
+}, {
+	spec: "@weekly",
+	expected: []time.Time{
+		time.Date(2015, 7, 19, 0, 0, 0, 0, time.UTC),
+		time.Date(2015, 7, 26, 0, 0, 0, 0, time.UTC),
+	},
+}, {
+	// Sunday may be given as 7.
+	spec: "0 0 * * 7",
+	expected: []time.Time{
+		time.Date(2015, 7, 19, 0, 0, 0, 0, time.UTC),
+	},
+}, {
+	// When both days are restricted, either may match.
+	spec: "0 0 1 * 5",
+	expected: []time.Time{
+		time.Date(2015, 7, 17, 0, 0, 0, 0, time.UTC),
+		time.Date(2015, 7, 24, 0, 0, 0, 0, time.UTC),
+		time.Date(2015, 7, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2015, 8, 1, 0, 0, 0, 0, time.UTC),
+	},
+}, {
+	spec: "0 0 29 2 *",
+	expected: []time.Time{
+		time.Date(2016, 2, 29, 0, 0, 0, 0, time.UTC),
+		time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC),
+	},
+}, {
+	spec:     "0 0 30 2 *",
+	expected: []time.Time{{}},
+}}
+
+func (*cronSuite) TestNext(c *gc.C) {
+	for i, test := range nextTests {
+		c.Logf("test %d: %s", i, test.spec)
+		schedule, err := cron.Parse(test.spec)
+		c.Assert(err, jc.ErrorIsNil)
+		t := start
+		for _, expected := range test.expected {
+			t = schedule.Next(t)
+			c.Check(t, gc.Equals, expected)
+		}
+	}
+}
+
+func (*cronSuite) TestParseErrors(c *gc.C) {
+	for i, test := range []struct {
+		spec string
+		err  string
+	}{
+		{"", `schedule "": expected 5 fields not valid`},
+		{"* * * *", `schedule "\* \* \* \*": expected 5 fields not valid`},
+		{"60 * * * *", `schedule "60 \* \* \* \*": minute "60" out of range 0-59`},
+		{"* * 0 * *", `schedule "\* \* 0 \* \*": day of month "0" out of range 1-31`},
+		{"* 5-2 * * *", `schedule "\* 5-2 \* \* \*": hour "5-2" out of range 0-23`},
+		{"*/0 * * * *", `schedule "\*/0 \* \* \* \*": invalid step in minute "\*/0"`},
+		{"* * * jan *", `schedule "\* \* \* jan \*": invalid month "jan"`},
+		{"@yearly", `schedule "@yearly": expected 5 fields not valid`},
+	} {
+		c.Logf("test %d: %q", i, test.spec)
+		_, err := cron.Parse(test.spec)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
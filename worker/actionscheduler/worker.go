@@ -0,0 +1,55 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionscheduler
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/worker"
+)
+
+// DefaultInterval is how often action operations are checked for
+// units that are due to have their actions queued.
+const DefaultInterval = 10 * time.Second
+
+// ActionOperationAdvancer defines the interface for types capable of
+// advancing action operations.
+type ActionOperationAdvancer interface {
+	// AdvanceActionOperations queues the next actions of every
+	// action operation that has yet to finish.
+	AdvanceActionOperations() error
+}
+
+type schedulerWorker struct {
+	st       ActionOperationAdvancer
+	interval time.Duration
+}
+
+// New returns a worker.Worker that periodically advances pending and
+// running action operations, starting those whose scheduled time has
+// come and queueing further batches on operations whose progress was
+// not recorded when their last actions finished.
+func New(st ActionOperationAdvancer, interval time.Duration) worker.Worker {
+	w := &schedulerWorker{
+		st:       st,
+		interval: interval,
+	}
+	return worker.NewSimpleWorker(w.loop)
+}
+
+func (w *schedulerWorker) loop(stopCh <-chan struct{}) error {
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case <-time.After(w.interval):
+			if err := w.st.AdvanceActionOperations(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionscheduler_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/actionscheduler"
+)
+
+type WorkerSuite struct {
+	coretesting.BaseSuite
+
+	advancer *advancerMock
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+// Ensure *state.State implements ActionOperationAdvancer.
+var _ actionscheduler.ActionOperationAdvancer = (*state.State)(nil)
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.advancer = &advancerMock{
+		Stub:    &testing.Stub{},
+		advance: make(chan struct{}, 10),
+	}
+}
+
+func (s *WorkerSuite) TestStop(c *gc.C) {
+	w := actionscheduler.New(s.advancer, time.Hour)
+	c.Assert(w.Stop(), jc.ErrorIsNil)
+	c.Assert(s.advancer.Calls(), gc.HasLen, 0)
+}
+
+func (s *WorkerSuite) TestAdvancesEachInterval(c *gc.C) {
+	w := actionscheduler.New(s.advancer, coretesting.ShortWait)
+	defer func() { c.Assert(w.Stop(), jc.ErrorIsNil) }()
+
+	for i := 0; i < 3; i++ {
+		s.advancer.waitAdvance(c)
+	}
+}
+
+func (s *WorkerSuite) TestAdvanceError(c *gc.C) {
+	s.advancer.SetErrors(errors.New("boom"))
+	w := actionscheduler.New(s.advancer, coretesting.ShortWait)
+	defer w.Kill()
+
+	s.advancer.waitAdvance(c)
+	c.Assert(w.Wait(), gc.ErrorMatches, "boom")
+	s.advancer.CheckCallNames(c, "AdvanceActionOperations")
+}
+
+// advancerMock records calls to AdvanceActionOperations.
+type advancerMock struct {
+	*testing.Stub
+
+	mu      sync.Mutex
+	advance chan struct{}
+}
+
+func (a *advancerMock) AdvanceActionOperations() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.MethodCall(a, "AdvanceActionOperations")
+	select {
+	case a.advance <- struct{}{}:
+	default:
+	}
+	return a.NextErr()
+}
+
+func (a *advancerMock) waitAdvance(c *gc.C) {
+	select {
+	case <-a.advance:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for action operations to be advanced")
+	}
+}
+
+var _ actionscheduler.ActionOperationAdvancer = (*advancerMock)(nil)
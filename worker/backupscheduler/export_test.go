@@ -0,0 +1,14 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+var (
+	NewBackups        = &newBackups
+	NewDBInfo         = &newDBInfo
+	GetScheduleStatus = &getScheduleStatus
+	SetScheduleStatus = &setScheduleStatus
+	TimeNow           = &timeNow
+	After             = &after
+	ExpiredBackups    = expiredBackups
+)
@@ -0,0 +1,93 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/juju/juju/state/backups"
+)
+
+// RetentionPolicy determines which scheduled backups are kept. A
+// backup is kept if any part of the policy keeps it.
+type RetentionPolicy struct {
+	// Count is the number of most recent backups to keep.
+	Count int
+
+	// Daily is the number of days for which the most recent backup
+	// of the day is kept. Only days on which backups were created
+	// are counted.
+	Daily int
+
+	// Weekly is the number of weeks for which the most recent backup
+	// of the week is kept. Only weeks in which backups were created
+	// are counted.
+	Weekly int
+}
+
+// expiredBackups returns the IDs of the scheduled backups that the
+// policy does not keep, newest first. Backups that were not scheduled
+// are never expired, and nothing is expired under an empty policy.
+func expiredBackups(metas []*backups.Metadata, policy RetentionPolicy) []string {
+	if policy == (RetentionPolicy{}) {
+		return nil
+	}
+	var scheduled []*backups.Metadata
+	for _, meta := range metas {
+		if meta.Scheduled {
+			scheduled = append(scheduled, meta)
+		}
+	}
+	sort.Sort(newestFirst(scheduled))
+
+	keep := make(map[string]bool)
+	for i := 0; i < policy.Count && i < len(scheduled); i++ {
+		keep[scheduled[i].ID()] = true
+	}
+	keepLatestInPeriod(scheduled, policy.Daily, day, keep)
+	keepLatestInPeriod(scheduled, policy.Weekly, week, keep)
+
+	var expired []string
+	for _, meta := range scheduled {
+		if !keep[meta.ID()] {
+			expired = append(expired, meta.ID())
+		}
+	}
+	return expired
+}
+
+// keepLatestInPeriod marks the most recent backup of each of the n
+// most recent periods as kept. The backups must be sorted newest
+// first.
+func keepLatestInPeriod(metas []*backups.Metadata, n int, period func(time.Time) string, keep map[string]bool) {
+	seen := make(map[string]bool)
+	for _, meta := range metas {
+		if len(seen) >= n {
+			return
+		}
+		p := period(meta.Started)
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		keep[meta.ID()] = true
+	}
+}
+
+func day(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+func week(t time.Time) string {
+	year, week := t.UTC().ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+type newestFirst []*backups.Metadata
+
+func (m newestFirst) Len() int           { return len(m) }
+func (m newestFirst) Less(i, j int) bool { return m[i].Started.After(m[j].Started) }
+func (m newestFirst) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
@@ -0,0 +1,111 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/backupscheduler"
+)
+
+type retentionSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&retentionSuite{})
+
+// newMeta returns the metadata of a backup started the given number
+// of hours after baseTime.
+func newMeta(id string, hours int, scheduled bool) *backups.Metadata {
+	meta := backups.NewMetadata()
+	meta.SetID(id)
+	meta.Started = baseTime.Add(time.Duration(hours) * time.Hour)
+	meta.Scheduled = scheduled
+	return meta
+}
+
+// retentionMetas returns scheduled backups created every 12 hours over
+// three weeks, out of order, along with a manual backup.
+func retentionMetas() []*backups.Metadata {
+	var metas []*backups.Metadata
+	for i := 0; i < 42; i++ {
+		hours := ((i * 17) % 42) * 12
+		metas = append(metas, newMeta(backupID(hours), hours, true))
+	}
+	return append(metas, newMeta("manual", 0, false))
+}
+
+func backupID(hours int) string {
+	return baseTime.Add(time.Duration(hours) * time.Hour).Format("2006-01-02T15")
+}
+
+var retentionTests = []struct {
+	about  string
+	policy backupscheduler.RetentionPolicy
+	kept   []string
+}{{
+	about: "keep last 3",
+	policy: backupscheduler.RetentionPolicy{
+		Count: 3,
+	},
+	kept: []string{"2015-07-21T12", "2015-07-21T00", "2015-07-20T12"},
+}, {
+	about: "keep daily for 2 days",
+	policy: backupscheduler.RetentionPolicy{
+		Daily: 2,
+	},
+	kept: []string{"2015-07-21T12", "2015-07-20T12"},
+}, {
+	about: "keep weekly for 3 weeks",
+	policy: backupscheduler.RetentionPolicy{
+		Weekly: 3,
+	},
+	// 2015-07-01 is a Wednesday, so the backups span four ISO weeks.
+	kept: []string{"2015-07-21T12", "2015-07-19T12", "2015-07-12T12"},
+}, {
+	about: "policies combine",
+	policy: backupscheduler.RetentionPolicy{
+		Count:  1,
+		Daily:  2,
+		Weekly: 2,
+	},
+	kept: []string{"2015-07-21T12", "2015-07-20T12", "2015-07-19T12"},
+}}
+
+func (s *retentionSuite) TestExpiredBackups(c *gc.C) {
+	metas := retentionMetas()
+	for i, test := range retentionTests {
+		c.Logf("test %d: %s", i, test.about)
+		expired := backupscheduler.ExpiredBackups(metas, test.policy)
+		c.Check(expired, gc.HasLen, 42-len(test.kept))
+
+		isExpired := make(map[string]bool)
+		for _, id := range expired {
+			isExpired[id] = true
+		}
+		c.Check(isExpired["manual"], jc.IsFalse)
+		var kept []string
+		for _, meta := range metas {
+			if meta.Scheduled && !isExpired[meta.ID()] {
+				kept = append(kept, meta.ID())
+			}
+		}
+		c.Check(kept, jc.SameContents, test.kept)
+	}
+}
+
+func (s *retentionSuite) TestExpiredBackupsNewestFirst(c *gc.C) {
+	expired := backupscheduler.ExpiredBackups(retentionMetas(), backupscheduler.RetentionPolicy{Count: 40})
+	c.Assert(expired, jc.DeepEquals, []string{"2015-07-01T12", "2015-07-01T00"})
+}
+
+func (s *retentionSuite) TestExpiredBackupsEmptyPolicy(c *gc.C) {
+	expired := backupscheduler.ExpiredBackups(retentionMetas(), backupscheduler.RetentionPolicy{})
+	c.Assert(expired, gc.HasLen, 0)
+}
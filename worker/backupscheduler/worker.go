@@ -0,0 +1,206 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+import (
+	"io"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/replicaset"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/utils/cron"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.backupscheduler")
+
+// ScheduledNotes are the notes recorded with each scheduled backup.
+const ScheduledNotes = "scheduled backup"
+
+// State defines the state methods used by the backup scheduler.
+type State interface {
+	backups.DB
+	WatchForEnvironConfigChanges() state.NotifyWatcher
+	MongoConnectionInfo() *mongo.MongoInfo
+}
+
+// Params holds the details the scheduler needs to create backups.
+type Params struct {
+	// MachineID is the ID of the state server machine on which the
+	// worker runs, and so on which backups are created.
+	MachineID string
+
+	// Paths holds the locations of the files to back up.
+	Paths backups.Paths
+}
+
+var (
+	newBackups = func(st State) (backups.Backups, io.Closer) {
+		stor := backups.NewStorage(st)
+		return backups.NewBackups(stor), stor
+	}
+	newDBInfo = func(st State) (*backups.DBInfo, error) {
+		session := st.MongoSession().Copy()
+		defer session.Close()
+		if err := replicaset.WaitUntilReady(session, 60); err != nil {
+			return nil, errors.Annotate(err, "HA not ready")
+		}
+		return backups.NewDBInfo(st.MongoConnectionInfo(), session)
+	}
+	getScheduleStatus = backups.GetScheduleStatus
+	setScheduleStatus = backups.SetScheduleStatus
+	timeNow           = time.Now
+	after             = time.After
+)
+
+// New returns a worker which creates backups of the environment
+// according to the backup-schedule in its configuration. Each backup
+// is marked as scheduled, and is followed by the removal of any
+// scheduled backups no longer kept by the configured retention
+// policy. The outcome of each attempt is recorded with
+// backups.SetScheduleStatus. Nothing is done while no schedule is
+// configured.
+func New(st State, params Params) worker.Worker {
+	w := &scheduler{st: st, params: params}
+	return worker.NewSimpleWorker(w.loop)
+}
+
+type scheduler struct {
+	st     State
+	params Params
+}
+
+func (w *scheduler) loop(stopCh <-chan struct{}) error {
+	configWatcher := w.st.WatchForEnvironConfigChanges()
+	defer configWatcher.Stop()
+
+	var current string
+	var schedule *cron.Schedule
+	var next <-chan time.Time
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case _, ok := <-configWatcher.Changes():
+			if !ok {
+				return watcher.EnsureErr(configWatcher)
+			}
+			cfg, err := w.st.EnvironConfig()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			spec, _ := cfg.BackupSchedule()
+			if spec == current {
+				continue
+			}
+			current, schedule = spec, nil
+			if spec != "" {
+				// The schedule has already been validated with
+				// the rest of the configuration.
+				if schedule, err = cron.Parse(spec); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			next = nextBackup(schedule)
+		case <-next:
+			if err := w.backUp(); err != nil {
+				return errors.Trace(err)
+			}
+			next = nextBackup(schedule)
+		}
+	}
+}
+
+// nextBackup returns a channel which receives when the next backup
+// is due, or nil if none will ever be.
+func nextBackup(schedule *cron.Schedule) <-chan time.Time {
+	if schedule == nil {
+		return nil
+	}
+	now := timeNow()
+	due := schedule.Next(now)
+	if due.IsZero() {
+		logger.Warningf("backup schedule never matches; no backups will be created")
+		return nil
+	}
+	logger.Debugf("next scheduled backup at %v", due)
+	return after(due.Sub(now))
+}
+
+// backUp creates a scheduled backup, removes expired ones and records
+// the outcome. Failing to back up does not stop the worker, as the
+// failure is reported in the schedule status.
+func (w *scheduler) backUp() error {
+	status, err := getScheduleStatus(w.st)
+	if errors.IsNotFound(err) {
+		status = &backups.ScheduleStatus{}
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	status.LastAttempt = timeNow().UTC()
+	status.LastError = ""
+	if err := w.createAndPrune(); err != nil {
+		logger.Errorf("scheduled backup failed: %v", err)
+		status.LastError = err.Error()
+	} else {
+		status.LastSuccess = status.LastAttempt
+	}
+	return errors.Trace(setScheduleStatus(w.st, *status))
+}
+
+func (w *scheduler) createAndPrune() error {
+	cfg, err := w.st.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	target := cfg.BackupTarget()
+	if err := cfg.CheckBackupTarget(target); err != nil {
+		return errors.Trace(err)
+	}
+	dbInfo, err := newDBInfo(w.st)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	meta, err := backups.NewMetadataState(w.st, w.params.MachineID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	meta.Notes = ScheduledNotes
+	meta.Target = target
+	meta.Scheduled = true
+	if key, ok := cfg.BackupEncryptionKey(); ok {
+		if err := meta.SetEncryptionKey(key); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	b, closer := newBackups(w.st)
+	defer closer.Close()
+	paths := w.params.Paths
+	if err := b.Create(meta, &paths, dbInfo); err != nil {
+		return errors.Annotate(err, "creating backup")
+	}
+	logger.Infof("created scheduled backup %q", meta.ID())
+
+	metas, err := b.List()
+	if err != nil {
+		return errors.Annotate(err, "listing backups")
+	}
+	var policy RetentionPolicy
+	policy.Count, policy.Daily, policy.Weekly = cfg.BackupRetention()
+	for _, id := range expiredBackups(metas, policy) {
+		if err := b.Remove(id); err != nil {
+			return errors.Annotatef(err, "removing expired backup %q", id)
+		}
+		logger.Infof("removed expired backup %q", id)
+	}
+	return nil
+}
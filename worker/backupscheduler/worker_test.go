@@ -0,0 +1,330 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler_test
+
+import (
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	backupstesting "github.com/juju/juju/state/backups/testing"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/backupscheduler"
+)
+
+type workerSuite struct {
+	coretesting.BaseSuite
+	st       *fakeState
+	backups  *fakeBackups
+	waits    chan time.Duration
+	fire     chan time.Time
+	statuses chan backups.ScheduleStatus
+	status   *backups.ScheduleStatus
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+var baseTime = time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC)
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.st = &fakeState{
+		cfg:     coretesting.EnvironConfig(c),
+		changes: make(chan struct{}, 1),
+	}
+	s.backups = &fakeBackups{created: make(chan *backups.Metadata, 5)}
+	s.waits = make(chan time.Duration, 5)
+	s.fire = make(chan time.Time)
+	s.statuses = make(chan backups.ScheduleStatus, 5)
+	s.status = nil
+	s.PatchValue(backupscheduler.NewBackups, func(backupscheduler.State) (backups.Backups, io.Closer) {
+		return s.backups, ioutil.NopCloser(nil)
+	})
+	s.PatchValue(backupscheduler.NewDBInfo, func(backupscheduler.State) (*backups.DBInfo, error) {
+		return &backups.DBInfo{Address: "localhost:37017"}, nil
+	})
+	s.PatchValue(backupscheduler.GetScheduleStatus, func(backups.DB) (*backups.ScheduleStatus, error) {
+		if s.status == nil {
+			return nil, errors.NotFoundf("scheduled backup status")
+		}
+		status := *s.status
+		return &status, nil
+	})
+	s.PatchValue(backupscheduler.SetScheduleStatus, func(_ backups.DB, status backups.ScheduleStatus) error {
+		s.statuses <- status
+		return nil
+	})
+	s.PatchValue(backupscheduler.TimeNow, func() time.Time { return baseTime })
+	s.PatchValue(backupscheduler.After, func(d time.Duration) <-chan time.Time {
+		s.waits <- d
+		return s.fire
+	})
+}
+
+func (s *workerSuite) setConfig(c *gc.C, attrs coretesting.Attrs) {
+	cfg, err := s.st.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err = cfg.Apply(attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	s.st.setConfig(cfg)
+	s.st.changes <- struct{}{}
+}
+
+func (s *workerSuite) startWorker(c *gc.C) worker.Worker {
+	w := backupscheduler.New(s.st, backupscheduler.Params{
+		MachineID: "0",
+		Paths: backups.Paths{
+			DataDir: "/var/lib/juju",
+			LogsDir: "/var/log/juju",
+		},
+	})
+	s.AddCleanup(func(*gc.C) { worker.Stop(w) })
+	return w
+}
+
+func (s *workerSuite) assertWait(c *gc.C, expected time.Duration) {
+	select {
+	case d := <-s.waits:
+		c.Assert(d, gc.Equals, expected)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("next backup not scheduled")
+	}
+}
+
+func (s *workerSuite) assertNoWait(c *gc.C) {
+	select {
+	case d := <-s.waits:
+		c.Fatalf("unexpected backup scheduled in %v", d)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *workerSuite) fireTimer(c *gc.C) {
+	select {
+	case s.fire <- baseTime:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("worker not waiting for next backup")
+	}
+}
+
+func (s *workerSuite) nextStatus(c *gc.C) backups.ScheduleStatus {
+	select {
+	case status := <-s.statuses:
+		return status
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("schedule status not set")
+	}
+	panic("unreachable")
+}
+
+func (s *workerSuite) TestNotConfigured(c *gc.C) {
+	s.st.changes <- struct{}{}
+	w := s.startWorker(c)
+	s.assertNoWait(c)
+	c.Assert(worker.Stop(w), jc.ErrorIsNil)
+}
+
+func (s *workerSuite) TestCreatesScheduledBackup(c *gc.C) {
+	s.setConfig(c, coretesting.Attrs{"backup-schedule": "30 3 * * *"})
+	w := s.startWorker(c)
+	s.assertWait(c, 3*time.Hour+30*time.Minute)
+	s.fireTimer(c)
+
+	meta := <-s.backups.created
+	c.Assert(meta.Scheduled, jc.IsTrue)
+	c.Assert(meta.Target, gc.Equals, config.BackupTargetState)
+	c.Assert(meta.Notes, gc.Equals, backupscheduler.ScheduledNotes)
+	c.Assert(meta.Origin.Machine, gc.Equals, "0")
+	c.Assert(meta.Origin.Environment, gc.Equals, coretesting.EnvironmentTag.Id())
+	c.Assert(s.backups.paths, jc.DeepEquals, backups.Paths{
+		DataDir: "/var/lib/juju",
+		LogsDir: "/var/log/juju",
+	})
+
+	c.Assert(s.nextStatus(c), jc.DeepEquals, backups.ScheduleStatus{
+		LastAttempt: baseTime,
+		LastSuccess: baseTime,
+	})
+	s.assertWait(c, 3*time.Hour+30*time.Minute)
+	c.Assert(s.backups.removedIDs(), gc.HasLen, 0)
+	c.Assert(worker.Stop(w), jc.ErrorIsNil)
+}
+
+func (s *workerSuite) TestEncryptsBackups(c *gc.C) {
+	publicKey, _ := backupstesting.NewEncryptionKey(c)
+	s.setConfig(c, coretesting.Attrs{
+		"backup-schedule":       "@hourly",
+		"backup-encryption-key": publicKey,
+	})
+	w := s.startWorker(c)
+	s.assertWait(c, time.Hour)
+	s.fireTimer(c)
+
+	meta := <-s.backups.created
+	c.Assert(meta.KeyFingerprint, gc.Matches, "[0-9A-F]{40}")
+	c.Assert(worker.Stop(w), jc.ErrorIsNil)
+}
+
+func (s *workerSuite) TestRemovesExpiredBackups(c *gc.C) {
+	s.backups.metas = []*backups.Metadata{
+		newMeta("manual", 0, false),
+		newMeta("old", 0, true),
+		newMeta("older", -24, true),
+		newMeta("recent", 24, true),
+	}
+	s.setConfig(c, coretesting.Attrs{
+		"backup-schedule":     "@daily",
+		"backup-retain-count": 2,
+	})
+	w := s.startWorker(c)
+	s.assertWait(c, 24*time.Hour)
+	s.fireTimer(c)
+
+	<-s.backups.created
+	c.Assert(s.nextStatus(c).LastError, gc.Equals, "")
+	c.Assert(s.backups.removedIDs(), jc.DeepEquals, []string{"old", "older"})
+	c.Assert(worker.Stop(w), jc.ErrorIsNil)
+}
+
+func (s *workerSuite) TestFailureRecorded(c *gc.C) {
+	lastSuccess := baseTime.Add(-24 * time.Hour)
+	s.status = &backups.ScheduleStatus{
+		LastAttempt: lastSuccess,
+		LastSuccess: lastSuccess,
+	}
+	s.backups.createErr = errors.New("disk full")
+	s.setConfig(c, coretesting.Attrs{"backup-schedule": "@hourly"})
+	w := s.startWorker(c)
+	s.assertWait(c, time.Hour)
+	s.fireTimer(c)
+
+	c.Assert(s.nextStatus(c), jc.DeepEquals, backups.ScheduleStatus{
+		LastAttempt: baseTime,
+		LastSuccess: lastSuccess,
+		LastError:   "creating backup: disk full",
+	})
+	// The worker carries on regardless.
+	s.assertWait(c, time.Hour)
+	c.Assert(worker.Stop(w), jc.ErrorIsNil)
+}
+
+func (s *workerSuite) TestScheduleChanged(c *gc.C) {
+	s.setConfig(c, coretesting.Attrs{"backup-schedule": "@hourly"})
+	w := s.startWorker(c)
+	s.assertWait(c, time.Hour)
+
+	// Unrelated changes leave the schedule alone.
+	s.setConfig(c, coretesting.Attrs{"backup-retain-count": 5})
+	s.assertNoWait(c)
+
+	s.setConfig(c, coretesting.Attrs{"backup-schedule": "0 12 * * *"})
+	s.assertWait(c, 12*time.Hour)
+
+	s.setConfig(c, coretesting.Attrs{"backup-schedule": ""})
+	s.assertNoWait(c)
+	select {
+	case s.fire <- baseTime:
+		c.Fatalf("worker still waiting for next backup")
+	case <-time.After(coretesting.ShortWait):
+	}
+	c.Assert(worker.Stop(w), jc.ErrorIsNil)
+}
+
+type fakeState struct {
+	backupscheduler.State
+
+	mu      sync.Mutex
+	cfg     *config.Config
+	changes chan struct{}
+}
+
+func (st *fakeState) EnvironTag() names.EnvironTag {
+	return coretesting.EnvironmentTag
+}
+
+func (st *fakeState) EnvironConfig() (*config.Config, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.cfg, nil
+}
+
+func (st *fakeState) setConfig(cfg *config.Config) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.cfg = cfg
+}
+
+func (st *fakeState) WatchForEnvironConfigChanges() state.NotifyWatcher {
+	return &fakeNotifyWatcher{changes: st.changes}
+}
+
+type fakeNotifyWatcher struct {
+	state.NotifyWatcher
+	changes chan struct{}
+}
+
+func (w *fakeNotifyWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+func (w *fakeNotifyWatcher) Stop() error {
+	return nil
+}
+
+func (w *fakeNotifyWatcher) Err() error {
+	return nil
+}
+
+type fakeBackups struct {
+	backups.Backups
+
+	mu        sync.Mutex
+	metas     []*backups.Metadata
+	paths     backups.Paths
+	removed   []string
+	createErr error
+	created   chan *backups.Metadata
+}
+
+func (b *fakeBackups) Create(meta *backups.Metadata, paths *backups.Paths, dbInfo *backups.DBInfo) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.createErr != nil {
+		return b.createErr
+	}
+	meta.SetID("new")
+	b.paths = *paths
+	b.metas = append(b.metas, meta)
+	b.created <- meta
+	return nil
+}
+
+func (b *fakeBackups) List() ([]*backups.Metadata, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.metas, nil
+}
+
+func (b *fakeBackups) Remove(id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.removed = append(b.removed, id)
+	return nil
+}
+
+func (b *fakeBackups) removedIDs() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.removed
+}
@@ -171,6 +171,17 @@ consider adding helpers to worker/util, which includes mechanisms for simple
 definition of manifolds that depend on an API caller; on an agent; or on both.
 
 
+Reporting
+---------
+
+An engine's Report method describes the engine and each of its manifolds: the
+state of each worker, the inputs it's waiting for, how many times it has been
+started, and the error it last stopped with. Workers that themselves implement
+Reporter have their own reports included. The worker/introspection package can
+serve these reports over a local socket, for inspection with `jujud
+agent-report`.
+
+
 Concerns and mitigations thereof
 --------------------------------
 
@@ -33,6 +33,7 @@ func NewEngine(isFatal IsFatalFunc, errorDelay, bounceDelay time.Duration) Engin
 		install: make(chan installTicket),
 		started: make(chan startedTicket),
 		stopped: make(chan stoppedTicket),
+		report:  make(chan reportTicket),
 	}
 	go func() {
 		defer engine.tomb.Done()
@@ -66,11 +67,12 @@ type engine struct {
 	// current holds the active worker information for each installed manifold.
 	current map[string]workerInfo
 
-	// install, started, and stopped each communicate requests and changes into
-	// the loop goroutine.
+	// install, started, stopped, and report each communicate requests and
+	// changes into the loop goroutine.
 	install chan installTicket
 	started chan startedTicket
 	stopped chan stoppedTicket
+	report  chan reportTicket
 }
 
 // loop serializes manifold install operations and worker start/stop notifications.
@@ -97,6 +99,9 @@ func (engine *engine) loop() error {
 			engine.gotStarted(ticket.name, ticket.worker)
 		case ticket := <-engine.stopped:
 			engine.gotStopped(ticket.name, ticket.error)
+		case ticket := <-engine.report:
+			// This is safe so long as the Report method reads the result.
+			ticket.result <- engine.liveReport()
 		}
 		if engine.isDying() {
 			if engine.allStopped() {
@@ -128,6 +133,93 @@ func (engine *engine) Install(name string, manifold Manifold) error {
 	}
 }
 
+// Report is part of the Reporter interface.
+func (engine *engine) Report() map[string]interface{} {
+	report := make(chan engineReport)
+	select {
+	case engine.report <- reportTicket{report}:
+		// This is safe so long as the loop sends a result.
+		snapshot := <-report
+		return snapshot.withWorkerReports()
+	case <-engine.tomb.Dead():
+		// Note that we don't report on any workers here; if the engine has
+		// stopped, so have all its workers.
+		return map[string]interface{}{
+			KeyState: StateStopped,
+			KeyError: errorMessage(engine.tomb.Err()),
+		}
+	}
+}
+
+// liveReport collects and returns a snapshot of the engine, its
+// manifolds, and their workers. It must only be called from the loop
+// goroutine.
+func (engine *engine) liveReport() engineReport {
+	state := StateStarted
+	var err error
+	if engine.isDying() {
+		state = StateStopping
+		err = engine.tomb.Err()
+	}
+	manifolds, reporters := engine.manifoldsReport()
+	return engineReport{
+		report: map[string]interface{}{
+			KeyState:     state,
+			KeyError:     errorMessage(err),
+			KeyManifolds: manifolds,
+		},
+		reporters: reporters,
+	}
+}
+
+// manifoldsReport collects and returns information about the engine's
+// manifolds and their workers, along with those workers that are
+// themselves Reporters. It must only be called from the loop goroutine.
+func (engine *engine) manifoldsReport() (map[string]interface{}, map[string]Reporter) {
+	result := map[string]interface{}{}
+	reporters := map[string]Reporter{}
+	for name, manifold := range engine.manifolds {
+		info := engine.current[name]
+		var missing []string
+		for _, input := range manifold.Inputs {
+			if engine.current[input].worker == nil {
+				missing = append(missing, input)
+			}
+		}
+		report := map[string]interface{}{
+			KeyState:         info.state(),
+			KeyError:         errorMessage(info.err),
+			KeyInputs:        manifold.Inputs,
+			KeyMissingInputs: missing,
+			KeyStartCount:    info.startCount,
+		}
+		if reporter, ok := info.worker.(Reporter); ok {
+			reporters[name] = reporter
+		}
+		result[name] = report
+	}
+	return result, reporters
+}
+
+// engineReport holds a snapshot of the state of an engine.
+type engineReport struct {
+	report    map[string]interface{}
+	reporters map[string]Reporter
+}
+
+// withWorkerReports returns the snapshot's report, including the
+// reports of those workers that are Reporters. It calls the workers'
+// own Report methods, so it must not be called from the loop goroutine:
+// a worker that is slow to report must not stop the engine responding
+// to its other workers.
+func (r engineReport) withWorkerReports() map[string]interface{} {
+	manifolds := r.report[KeyManifolds].(map[string]interface{})
+	for name, reporter := range r.reporters {
+		manifolds[name].(map[string]interface{})[KeyReport] = reporter.Report()
+	}
+	return r.report
+}
+
 // gotInstall handles the params originally supplied to Install. It must only be
 // called from the loop goroutine.
 func (engine *engine) gotInstall(name string, manifold Manifold) error {
@@ -301,6 +393,7 @@ func (engine *engine) gotStarted(name string, worker worker.Worker) {
 		logger.Infof("%q manifold worker started", name)
 		info.starting = false
 		info.worker = worker
+		info.startCount++
 		engine.current[name] = info
 
 		// Any manifold that declares this one as an input needs to be restarted.
@@ -321,8 +414,12 @@ func (engine *engine) gotStopped(name string, err error) {
 		engine.tomb.Kill(err)
 	}
 
-	// Reset engine info; and bail out if we can be sure there's no need to bounce.
-	engine.current[name] = workerInfo{}
+	// Reset engine info, keeping the history we report; and bail out if we
+	// can be sure there's no need to bounce.
+	engine.current[name] = workerInfo{
+		startCount: info.startCount,
+		err:        err,
+	}
 	if engine.isDying() {
 		logger.Debugf("permanently stopped %q manifold worker (shutting down)", name)
 		return
@@ -416,6 +513,11 @@ type workerInfo struct {
 	starting bool
 	stopping bool
 	worker   worker.Worker
+
+	// startCount and err record, for reporting purposes, how many times
+	// a worker has been started, and the error with which it last stopped.
+	startCount int
+	err        error
 }
 
 // stopped returns true unless the worker is either assigned or starting.
@@ -429,6 +531,28 @@ func (info workerInfo) stopped() bool {
 	return true
 }
 
+// state returns a description of the worker's state suitable for reporting.
+func (info workerInfo) state() string {
+	switch {
+	case info.stopping:
+		return StateStopping
+	case info.starting:
+		return StateStarting
+	case info.worker != nil:
+		return StateStarted
+	}
+	return StateStopped
+}
+
+// errorMessage returns the message of err, or the empty string if err
+// is nil or merely indicates that the engine was asked to stop.
+func errorMessage(err error) string {
+	if err == nil || err == tomb.ErrDying {
+		return ""
+	}
+	return err.Error()
+}
+
 // installTicket is used by engine to induce installation of a named manifold
 // and pass on any errors encountered in the process.
 type installTicket struct {
@@ -450,3 +574,9 @@ type stoppedTicket struct {
 	name  string
 	error error
 }
+
+// reportTicket is used by the engine to notify the loop that a status report
+// should be generated.
+type reportTicket struct {
+	result chan<- engineReport
+}
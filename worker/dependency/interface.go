@@ -18,6 +18,9 @@ type Engine interface {
 	// fails and when its inputs' workers change, until the Engine shuts down.
 	Install(name string, manifold Manifold) error
 
+	// Engine can describe its own state and that of its workers.
+	Reporter
+
 	// Engine is just another Worker.
 	worker.Worker
 }
@@ -0,0 +1,54 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package dependency
+
+// Reporter defines an interface for extracting human-relevant information
+// from a component; it's implemented by Engine, and may be implemented by
+// the workers it runs, in which case their reports will be included in the
+// engine's.
+type Reporter interface {
+
+	// Report returns a map describing the state of the receiver. It is
+	// expected to be goroutine-safe, and to produce values that can be
+	// serialized as YAML or JSON.
+	Report() map[string]interface{}
+}
+
+// The keys used in the reports produced by an Engine.
+const (
+	// KeyState holds the state of the engine or worker; one of the
+	// State* values below.
+	KeyState = "state"
+
+	// KeyError holds the message of the error that stopped the engine,
+	// or that most recently stopped a worker.
+	KeyError = "error"
+
+	// KeyManifolds holds a map from manifold name to the report for
+	// that manifold's worker.
+	KeyManifolds = "manifolds"
+
+	// KeyInputs holds the names of the manifolds a manifold depends on.
+	KeyInputs = "inputs"
+
+	// KeyMissingInputs holds the names of the inputs for which no
+	// worker is currently running.
+	KeyMissingInputs = "missing-inputs"
+
+	// KeyStartCount holds the number of times a manifold's worker has
+	// been successfully started.
+	KeyStartCount = "start-count"
+
+	// KeyReport holds the report produced by a worker that is itself
+	// a Reporter.
+	KeyReport = "report"
+)
+
+// The states reported for an Engine and its workers.
+const (
+	StateStarting = "starting"
+	StateStarted  = "started"
+	StateStopping = "stopping"
+	StateStopped  = "stopped"
+)
@@ -0,0 +1,276 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package dependency_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
+)
+
+type ReportSuite struct {
+	testing.IsolationSuite
+	engine dependency.Engine
+}
+
+var _ = gc.Suite(&ReportSuite{})
+
+func (s *ReportSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.engine = dependency.NewEngine(nothingFatal, coretesting.ShortWait/2, coretesting.ShortWait/10)
+}
+
+func (s *ReportSuite) TearDownTest(c *gc.C) {
+	if s.engine != nil {
+		err := worker.Stop(s.engine)
+		s.engine = nil
+		c.Check(err, jc.ErrorIsNil)
+	}
+	s.IsolationSuite.TearDownTest(c)
+}
+
+// waitForReport polls the engine until the named manifold's report
+// satisfies the supplied check, and returns the engine's report.
+func (s *ReportSuite) waitForReport(c *gc.C, name string, check func(map[string]interface{}) bool) map[string]interface{} {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		report := s.engine.Report()
+		manifolds := report[dependency.KeyManifolds].(map[string]interface{})
+		if manifold, ok := manifolds[name].(map[string]interface{}); ok && check(manifold) {
+			return report
+		}
+	}
+	c.Fatalf("%q manifold never reached expected state", name)
+	panic("unreachable")
+}
+
+func isStarted(report map[string]interface{}) bool {
+	return report[dependency.KeyState] == dependency.StateStarted
+}
+
+func (s *ReportSuite) TestReportEmpty(c *gc.C) {
+	c.Assert(s.engine.Report(), jc.DeepEquals, map[string]interface{}{
+		dependency.KeyState:     dependency.StateStarted,
+		dependency.KeyError:     "",
+		dependency.KeyManifolds: map[string]interface{}{},
+	})
+}
+
+func (s *ReportSuite) TestReportStarted(c *gc.C) {
+	mh1 := newManifoldHarness()
+	err := s.engine.Install("some-task", mh1.Manifold())
+	c.Assert(err, jc.ErrorIsNil)
+	mh1.AssertOneStart(c)
+
+	report := s.waitForReport(c, "some-task", isStarted)
+	c.Assert(report, jc.DeepEquals, map[string]interface{}{
+		dependency.KeyState: dependency.StateStarted,
+		dependency.KeyError: "",
+		dependency.KeyManifolds: map[string]interface{}{
+			"some-task": map[string]interface{}{
+				dependency.KeyState:         dependency.StateStarted,
+				dependency.KeyError:         "",
+				dependency.KeyInputs:        []string(nil),
+				dependency.KeyMissingInputs: []string(nil),
+				dependency.KeyStartCount:    1,
+			},
+		},
+	})
+}
+
+func (s *ReportSuite) TestReportMissingInputs(c *gc.C) {
+	mh1 := newManifoldHarness("later-task")
+	err := s.engine.Install("some-task", mh1.Manifold())
+	c.Assert(err, jc.ErrorIsNil)
+	mh1.AssertNoStart(c)
+
+	report := s.waitForReport(c, "some-task", func(report map[string]interface{}) bool {
+		return report[dependency.KeyError] == dependency.ErrMissing.Error()
+	})
+	manifolds := report[dependency.KeyManifolds].(map[string]interface{})
+	c.Assert(manifolds["some-task"], jc.DeepEquals, map[string]interface{}{
+		dependency.KeyState:         dependency.StateStopped,
+		dependency.KeyError:         "dependency not available",
+		dependency.KeyInputs:        []string{"later-task"},
+		dependency.KeyMissingInputs: []string{"later-task"},
+		dependency.KeyStartCount:    0,
+	})
+}
+
+func (s *ReportSuite) TestReportRestarts(c *gc.C) {
+	mh1 := newManifoldHarness()
+	err := s.engine.Install("error-task", mh1.Manifold())
+	c.Assert(err, jc.ErrorIsNil)
+	mh1.AssertOneStart(c)
+	s.waitForReport(c, "error-task", isStarted)
+
+	mh1.InjectError(c, errors.New("ZAP"))
+	mh1.AssertOneStart(c)
+
+	report := s.waitForReport(c, "error-task", func(report map[string]interface{}) bool {
+		return isStarted(report) && report[dependency.KeyStartCount] == 2
+	})
+	manifolds := report[dependency.KeyManifolds].(map[string]interface{})
+	manifold := manifolds["error-task"].(map[string]interface{})
+	c.Assert(manifold[dependency.KeyError], gc.Equals, "ZAP")
+}
+
+func (s *ReportSuite) TestReportIncludesWorkerReport(c *gc.C) {
+	err := s.engine.Install("reporting-task", dependency.Manifold{
+		Start: func(_ dependency.GetResourceFunc) (worker.Worker, error) {
+			w, err := startMinimalWorker(nil)
+			if err != nil {
+				return nil, err
+			}
+			return &reportingWorker{w}, nil
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	report := s.waitForReport(c, "reporting-task", isStarted)
+	manifolds := report[dependency.KeyManifolds].(map[string]interface{})
+	manifold := manifolds["reporting-task"].(map[string]interface{})
+	c.Assert(manifold[dependency.KeyReport], jc.DeepEquals, map[string]interface{}{
+		"greeting": "hello",
+	})
+}
+
+func (s *ReportSuite) TestSlowWorkerReportDoesNotBlockEngine(c *gc.C) {
+	slow := &blockingReportWorker{
+		entered: make(chan struct{}),
+		unblock: make(chan struct{}),
+	}
+	err := s.engine.Install("slow-task", dependency.Manifold{
+		Start: func(_ dependency.GetResourceFunc) (worker.Worker, error) {
+			w, err := startMinimalWorker(nil)
+			if err != nil {
+				return nil, err
+			}
+			slow.Worker = w
+			return slow, nil
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Keep asking for reports until one reaches the slow worker.
+	engine := s.engine
+	reports := make(chan map[string]interface{}, 1)
+	go func() {
+		for a := coretesting.LongAttempt.Start(); a.Next(); {
+			report := engine.Report()
+			select {
+			case <-slow.entered:
+				reports <- report
+				return
+			default:
+			}
+		}
+	}()
+	select {
+	case <-slow.entered:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("worker never asked to report")
+	}
+
+	// The engine keeps working while the worker is reporting.
+	installed := make(chan error, 1)
+	mh := newManifoldHarness()
+	go func() {
+		installed <- s.engine.Install("other-task", mh.Manifold())
+	}()
+	select {
+	case err := <-installed:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("engine blocked by worker report")
+	}
+	mh.AssertOneStart(c)
+
+	close(slow.unblock)
+	select {
+	case report := <-reports:
+		manifolds := report[dependency.KeyManifolds].(map[string]interface{})
+		manifold := manifolds["slow-task"].(map[string]interface{})
+		c.Assert(manifold[dependency.KeyReport], jc.DeepEquals, map[string]interface{}{
+			"greeting": "hello",
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("report never completed")
+	}
+}
+
+func (s *ReportSuite) TestReportStopped(c *gc.C) {
+	err := worker.Stop(s.engine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.engine.Report(), jc.DeepEquals, map[string]interface{}{
+		dependency.KeyState: dependency.StateStopped,
+		dependency.KeyError: "",
+	})
+}
+
+func (s *ReportSuite) TestReportStoppedWithError(c *gc.C) {
+	fatalError := errors.New("KABOOM")
+	err := worker.Stop(s.engine)
+	c.Assert(err, jc.ErrorIsNil)
+	s.engine = dependency.NewEngine(func(err error) bool {
+		return err == fatalError
+	}, coretesting.ShortWait/2, coretesting.ShortWait/10)
+
+	mh1 := newManifoldHarness()
+	err = s.engine.Install("some-task", mh1.Manifold())
+	c.Assert(err, jc.ErrorIsNil)
+	mh1.AssertOneStart(c)
+	mh1.InjectError(c, fatalError)
+
+	select {
+	case <-waitChan(s.engine):
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("engine never stopped")
+	}
+	c.Assert(s.engine.Report(), jc.DeepEquals, map[string]interface{}{
+		dependency.KeyState: dependency.StateStopped,
+		dependency.KeyError: "KABOOM",
+	})
+	s.engine = nil
+}
+
+func waitChan(w worker.Worker) <-chan error {
+	result := make(chan error, 1)
+	go func() {
+		result <- w.Wait()
+	}()
+	return result
+}
+
+type reportingWorker struct {
+	worker.Worker
+}
+
+func (w *reportingWorker) Report() map[string]interface{} {
+	return map[string]interface{}{"greeting": "hello"}
+}
+
+// blockingReportWorker blocks the first time it is asked to report,
+// until unblock is closed.
+type blockingReportWorker struct {
+	worker.Worker
+	once    sync.Once
+	entered chan struct{}
+	unblock chan struct{}
+}
+
+func (w *blockingReportWorker) Report() map[string]interface{} {
+	w.once.Do(func() {
+		close(w.entered)
+		<-w.unblock
+	})
+	return map[string]interface{}{"greeting": "hello"}
+}
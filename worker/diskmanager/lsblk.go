@@ -27,6 +27,7 @@ const (
 
 	typeDisk = "disk"
 	typeLoop = "loop"
+	typeLVM  = "lvm"
 )
 
 func init() {
@@ -36,6 +37,7 @@ func init() {
 func listBlockDevices() ([]storage.BlockDevice, error) {
 	columns := []string{
 		"KNAME",      // kernel name
+		"NAME",       // device name
 		"SIZE",       // size
 		"LABEL",      // filesystem label
 		"UUID",       // filesystem UUID
@@ -62,11 +64,13 @@ func listBlockDevices() ([]storage.BlockDevice, error) {
 	for s.Scan() {
 		pairs := pairsRE.FindAllStringSubmatch(s.Text(), -1)
 		var dev storage.BlockDevice
-		var deviceType string
+		var deviceType, name string
 		for _, pair := range pairs {
 			switch pair[1] {
 			case "KNAME":
 				dev.DeviceName = pair[2]
+			case "NAME":
+				name = pair[2]
 			case "SIZE":
 				size, err := strconv.ParseUint(pair[2], 10, 64)
 				if err != nil {
@@ -91,11 +95,21 @@ func listBlockDevices() ([]storage.BlockDevice, error) {
 			}
 		}
 
-		// We may later want to expand this, e.g. to handle lvm,
-		// dmraid, crypt, etc., but this is enough to cover bases
-		// for now.
+		// We may later want to expand this, e.g. to handle dmraid,
+		// crypt, etc., but this is enough to cover bases for now.
 		switch deviceType {
 		case typeDisk, typeLoop:
+		case typeLVM:
+			// The kernel name of a logical volume (e.g. "dm-0") is
+			// not stable, so we identify it by its path under /dev
+			// instead, which is also the LVM storage provider's
+			// volume ID.
+			lvPath, ok := logicalVolumePath(name)
+			if !ok {
+				logger.Debugf("ignoring logical volume with name %q", name)
+				continue
+			}
+			dev.DeviceName = lvPath
 		default:
 			logger.Tracef("ignoring %q type device: %+v", deviceType, dev)
 			continue
@@ -130,6 +144,37 @@ func listBlockDevices() ([]storage.BlockDevice, error) {
 	return blockDevices, nil
 }
 
+// logicalVolumePath returns the path of a logical volume relative to
+// /dev (i.e. "<volume-group>/<logical-volume>"), given its device-mapper
+// name. Device-mapper joins the volume group and logical volume names
+// with a hyphen, doubling any hyphens within them.
+func logicalVolumePath(dmName string) (string, bool) {
+	var volumeGroup, logicalVolume []byte
+	var separated bool
+	for i := 0; i < len(dmName); i++ {
+		c := dmName[i]
+		if c == '-' {
+			if i+1 < len(dmName) && dmName[i+1] == '-' {
+				i++
+			} else if separated {
+				return "", false
+			} else {
+				separated = true
+				continue
+			}
+		}
+		if separated {
+			logicalVolume = append(logicalVolume, c)
+		} else {
+			volumeGroup = append(volumeGroup, c)
+		}
+	}
+	if len(volumeGroup) == 0 || len(logicalVolume) == 0 {
+		return "", false
+	}
+	return string(volumeGroup) + "/" + string(logicalVolume), true
+}
+
 // blockDeviceInUse checks if the specified block device
 // is in use by attempting to open the device exclusively.
 //
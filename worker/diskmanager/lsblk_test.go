@@ -71,6 +71,29 @@ EOF`)
 	}})
 }
 
+func (s *ListBlockDevicesSuite) TestListBlockDevicesLogicalVolumes(c *gc.C) {
+	testing.PatchExecutable(c, s, "lsblk", `#!/bin/bash --norc
+cat <<EOF
+KNAME="sdb" NAME="sdb" SIZE="32017047552" LABEL="" UUID="" TYPE="disk"
+KNAME="dm-0" NAME="juju-volume--0--1" SIZE="1073741824" LABEL="" UUID="" TYPE="lvm"
+KNAME="dm-1" NAME="my--vg-data" SIZE="2147483648" LABEL="" UUID="" TYPE="lvm"
+KNAME="dm-2" NAME="bad-name-here" SIZE="2147483648" LABEL="" UUID="" TYPE="lvm"
+EOF`)
+
+	devices, err := diskmanager.ListBlockDevices()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(devices, jc.SameContents, []storage.BlockDevice{{
+		DeviceName: "sdb",
+		Size:       30533,
+	}, {
+		DeviceName: "juju/volume-0-1",
+		Size:       1024,
+	}, {
+		DeviceName: "my-vg/data",
+		Size:       2048,
+	}})
+}
+
 func (s *ListBlockDevicesSuite) TestListBlockDevicesLsblkError(c *gc.C) {
 	testing.PatchExecutableThrowError(c, s, "lsblk", 123)
 	devices, err := diskmanager.ListBlockDevices()
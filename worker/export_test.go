@@ -7,7 +7,10 @@ import (
 	"github.com/juju/juju/state/watcher"
 )
 
-var LoadedInvalid = make(chan struct{})
+var (
+	LoadedInvalid  = make(chan struct{})
+	WorkerRestarts = workerRestarts
+)
 
 func init() {
 	loadedInvalid = func() {
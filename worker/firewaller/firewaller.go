@@ -23,6 +23,15 @@ import (
 
 type machineRanges map[network.PortRange]bool
 
+// portSource is a port range opened to a single source CIDR. The
+// firewaller tracks the ports it opens as port sources, so that the
+// same port range may be opened to different CIDRs for different
+// services.
+type portSource struct {
+	portRange network.PortRange
+	cidr      string
+}
+
 // Firewaller watches the state for port ranges opened or closed on
 // machines and reflects those changes onto the backing environment.
 // Uses Firewaller API V1.
@@ -39,7 +48,7 @@ type Firewaller struct {
 	serviceds       map[names.ServiceTag]*serviceData
 	exposedChange   chan *exposedChange
 	globalMode      bool
-	globalPortRef   map[network.PortRange]int
+	globalPortRef   map[portSource]int
 	machinePorts    map[names.MachineTag]machineRanges
 }
 
@@ -88,7 +97,7 @@ func NewFirewaller(st *apifirewaller.State) (_ worker.Worker, err error) {
 	switch fw.environ.Config().FirewallMode() {
 	case config.FwGlobal:
 		fw.globalMode = true
-		fw.globalPortRef = make(map[network.PortRange]int)
+		fw.globalPortRef = make(map[portSource]int)
 	case config.FwNone:
 		logger.Warningf("stopping firewaller - firewall-mode is %q", config.FwNone)
 		return nil, errors.Errorf("firewaller is disabled when firewall-mode is %q", config.FwNone)
@@ -163,6 +172,7 @@ func (fw *Firewaller) loop() error {
 			}
 		case change := <-fw.exposedChange:
 			change.serviced.exposed = change.exposed
+			change.serviced.cidrs = change.cidrs
 			unitds := []*unitData{}
 			for _, unitd := range change.serviced.unitds {
 				unitds = append(unitds, unitd)
@@ -181,7 +191,7 @@ func (fw *Firewaller) startMachine(tag names.MachineTag) error {
 		fw:           fw,
 		tag:          tag,
 		unitds:       make(map[names.UnitTag]*unitData),
-		openedPorts:  make([]network.PortRange, 0),
+		openedPorts:  make([]portSource, 0),
 		definedPorts: make(map[network.PortRange]names.UnitTag),
 	}
 	m, err := machined.machine()
@@ -272,14 +282,19 @@ func (fw *Firewaller) startService(service *apifirewaller.Service) error {
 	if err != nil {
 		return err
 	}
+	cidrs, err := service.ExposedCIDRs()
+	if err != nil {
+		return err
+	}
 	serviced := &serviceData{
 		fw:      fw,
 		service: service,
 		exposed: exposed,
+		cidrs:   cidrs,
 		unitds:  make(map[names.UnitTag]*unitData),
 	}
 	fw.serviceds[service.Tag()] = serviced
-	go serviced.watchLoop(serviced.exposed)
+	go serviced.watchLoop(serviced.exposed, serviced.cidrs)
 	return nil
 }
 
@@ -287,11 +302,11 @@ func (fw *Firewaller) startService(service *apifirewaller.Service) error {
 // units and services with the opened and closed ports globally and
 // opens and closes the appropriate ports for the whole environment.
 func (fw *Firewaller) reconcileGlobal() error {
-	initialPortRanges, err := fw.environ.Ports()
+	initialPorts, err := fw.globalPorts()
 	if err != nil {
 		return err
 	}
-	collector := make(map[network.PortRange]bool)
+	collector := make(map[portSource]bool)
 	for _, machined := range fw.machineds {
 		for portRange, unitTag := range machined.definedPorts {
 			unitd, known := machined.unitds[unitTag]
@@ -299,31 +314,29 @@ func (fw *Firewaller) reconcileGlobal() error {
 				delete(machined.unitds, unitTag)
 				continue
 			}
-			if unitd.serviced.exposed {
-				collector[portRange] = true
+			for _, source := range unitd.serviced.exposedPorts(portRange) {
+				collector[source] = true
 			}
 		}
 	}
-	wantedPorts := []network.PortRange{}
-	for port := range collector {
-		wantedPorts = append(wantedPorts, port)
+	wantedPorts := []portSource{}
+	for source := range collector {
+		wantedPorts = append(wantedPorts, source)
 	}
 	// Check which ports to open or to close.
-	toOpen := diffRanges(wantedPorts, initialPortRanges)
-	toClose := diffRanges(initialPortRanges, wantedPorts)
+	toOpen := diffRanges(wantedPorts, initialPorts)
+	toClose := diffRanges(initialPorts, wantedPorts)
 	if len(toOpen) > 0 {
-		logger.Infof("opening global ports %v", toOpen)
-		if err := fw.environ.OpenPorts(toOpen); err != nil {
+		logger.Infof("opening global ports %v", ingressRules(toOpen))
+		if err := fw.openGlobalPorts(toOpen); err != nil {
 			return err
 		}
-		network.SortPortRanges(toOpen)
 	}
 	if len(toClose) > 0 {
-		logger.Infof("closing global ports %v", toClose)
-		if err := fw.environ.ClosePorts(toClose); err != nil {
+		logger.Infof("closing global ports %v", ingressRules(toClose))
+		if err := fw.closeGlobalPorts(toClose); err != nil {
 			return err
 		}
-		network.SortPortRanges(toClose)
 	}
 	return nil
 }
@@ -357,31 +370,29 @@ func (fw *Firewaller) reconcileInstances() error {
 			return err
 		}
 		machineId := machined.tag.Id()
-		initialPortRanges, err := instances[0].Ports(machineId)
+		initialPorts, err := instancePorts(instances[0], machineId)
 		if err != nil {
 			return err
 		}
 
 		// Check which ports to open or to close.
-		toOpen := diffRanges(machined.openedPorts, initialPortRanges)
-		toClose := diffRanges(initialPortRanges, machined.openedPorts)
+		toOpen := diffRanges(machined.openedPorts, initialPorts)
+		toClose := diffRanges(initialPorts, machined.openedPorts)
 		if len(toOpen) > 0 {
 			logger.Infof("opening instance port ranges %v for %q",
-				toOpen, machined.tag)
-			if err := instances[0].OpenPorts(machineId, toOpen); err != nil {
+				ingressRules(toOpen), machined.tag)
+			if err := openInstancePorts(instances[0], machineId, toOpen); err != nil {
 				// TODO(mue) Add local retry logic.
 				return err
 			}
-			network.SortPortRanges(toOpen)
 		}
 		if len(toClose) > 0 {
 			logger.Infof("closing instance port ranges %v for %q",
-				toClose, machined.tag)
-			if err := instances[0].ClosePorts(machineId, toClose); err != nil {
+				ingressRules(toClose), machined.tag)
+			if err := closeInstancePorts(instances[0], machineId, toClose); err != nil {
 				// TODO(mue) Add local retry logic.
 				return err
 			}
-			network.SortPortRanges(toClose)
 		}
 	}
 	return nil
@@ -503,16 +514,14 @@ func (fw *Firewaller) flushUnits(unitds []*unitData) error {
 // flushMachine opens and closes ports for the passed machine.
 func (fw *Firewaller) flushMachine(machined *machineData) error {
 	// Gather ports to open and close.
-	want := []network.PortRange{}
+	want := []portSource{}
 	for portRange, unitTag := range machined.definedPorts {
 		unitd, known := machined.unitds[unitTag]
 		if !known {
 			delete(machined.unitds, unitTag)
 			continue
 		}
-		if unitd.serviced.exposed {
-			want = append(want, portRange)
-		}
+		want = append(want, unitd.serviced.exposedPorts(portRange)...)
 	}
 	toOpen := diffRanges(want, machined.openedPorts)
 	toClose := diffRanges(machined.openedPorts, want)
@@ -526,44 +535,42 @@ func (fw *Firewaller) flushMachine(machined *machineData) error {
 // flushGlobalPorts opens and closes global ports in the environment.
 // It keeps a reference count for ports so that only 0-to-1 and 1-to-0 events
 // modify the environment.
-func (fw *Firewaller) flushGlobalPorts(rawOpen, rawClose []network.PortRange) error {
+func (fw *Firewaller) flushGlobalPorts(rawOpen, rawClose []portSource) error {
 	// Filter which ports are really to open or close.
-	var toOpen, toClose []network.PortRange
-	for _, portRange := range rawOpen {
-		if fw.globalPortRef[portRange] == 0 {
-			toOpen = append(toOpen, portRange)
+	var toOpen, toClose []portSource
+	for _, source := range rawOpen {
+		if fw.globalPortRef[source] == 0 {
+			toOpen = append(toOpen, source)
 		}
-		fw.globalPortRef[portRange]++
+		fw.globalPortRef[source]++
 	}
-	for _, portRange := range rawClose {
-		fw.globalPortRef[portRange]--
-		if fw.globalPortRef[portRange] == 0 {
-			toClose = append(toClose, portRange)
-			delete(fw.globalPortRef, portRange)
+	for _, source := range rawClose {
+		fw.globalPortRef[source]--
+		if fw.globalPortRef[source] == 0 {
+			toClose = append(toClose, source)
+			delete(fw.globalPortRef, source)
 		}
 	}
 	// Open and close the ports.
 	if len(toOpen) > 0 {
-		if err := fw.environ.OpenPorts(toOpen); err != nil {
+		if err := fw.openGlobalPorts(toOpen); err != nil {
 			// TODO(mue) Add local retry logic.
 			return err
 		}
-		network.SortPortRanges(toOpen)
-		logger.Infof("opened port ranges %v in environment", toOpen)
+		logger.Infof("opened port ranges %v in environment", ingressRules(toOpen))
 	}
 	if len(toClose) > 0 {
-		if err := fw.environ.ClosePorts(toClose); err != nil {
+		if err := fw.closeGlobalPorts(toClose); err != nil {
 			// TODO(mue) Add local retry logic.
 			return err
 		}
-		network.SortPortRanges(toClose)
-		logger.Infof("closed port ranges %v in environment", toClose)
+		logger.Infof("closed port ranges %v in environment", ingressRules(toClose))
 	}
 	return nil
 }
 
 // flushInstancePorts opens and closes ports global on the machine.
-func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose []network.PortRange) error {
+func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose []portSource) error {
 	// If there's nothing to do, do nothing.
 	// This is important because when a machine is first created,
 	// it will have no instance id but also no open ports -
@@ -589,24 +596,139 @@ func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose
 	}
 	// Open and close the ports.
 	if len(toOpen) > 0 {
-		if err := instances[0].OpenPorts(machineId, toOpen); err != nil {
+		if err := openInstancePorts(instances[0], machineId, toOpen); err != nil {
 			// TODO(mue) Add local retry logic.
 			return err
 		}
-		network.SortPortRanges(toOpen)
-		logger.Infof("opened port ranges %v on %q", toOpen, machined.tag)
+		logger.Infof("opened port ranges %v on %q", ingressRules(toOpen), machined.tag)
 	}
 	if len(toClose) > 0 {
-		if err := instances[0].ClosePorts(machineId, toClose); err != nil {
+		if err := closeInstancePorts(instances[0], machineId, toClose); err != nil {
 			// TODO(mue) Add local retry logic.
 			return err
 		}
-		network.SortPortRanges(toClose)
-		logger.Infof("closed port ranges %v on %q", toClose, machined.tag)
+		logger.Infof("closed port ranges %v on %q", ingressRules(toClose), machined.tag)
+	}
+	return nil
+}
+
+// globalPorts returns the ports opened for the whole environment.
+func (fw *Firewaller) globalPorts() ([]portSource, error) {
+	if env, ok := environs.SupportsIngressRules(fw.environ); ok {
+		rules, err := env.IngressRules()
+		if err != nil {
+			return nil, err
+		}
+		return portSources(rules), nil
+	}
+	portRanges, err := fw.environ.Ports()
+	if err != nil {
+		return nil, err
+	}
+	return portSources(network.IngressRulesForPorts(portRanges)), nil
+}
+
+// openGlobalPorts opens the given ports for the whole environment.
+func (fw *Firewaller) openGlobalPorts(sources []portSource) error {
+	if env, ok := environs.SupportsIngressRules(fw.environ); ok {
+		return env.OpenIngressRules(ingressRules(sources))
+	}
+	if portRanges := anySourcePortRanges(sources); len(portRanges) > 0 {
+		return fw.environ.OpenPorts(portRanges)
+	}
+	return nil
+}
+
+// closeGlobalPorts closes the given ports for the whole environment.
+func (fw *Firewaller) closeGlobalPorts(sources []portSource) error {
+	if env, ok := environs.SupportsIngressRules(fw.environ); ok {
+		return env.CloseIngressRules(ingressRules(sources))
+	}
+	if portRanges := anySourcePortRanges(sources); len(portRanges) > 0 {
+		return fw.environ.ClosePorts(portRanges)
+	}
+	return nil
+}
+
+// instancePorts returns the ports opened on the instance.
+func instancePorts(inst instance.Instance, machineId string) ([]portSource, error) {
+	if inst, ok := inst.(instance.IngressRules); ok {
+		rules, err := inst.IngressRules(machineId)
+		if err != nil {
+			return nil, err
+		}
+		return portSources(rules), nil
+	}
+	portRanges, err := inst.Ports(machineId)
+	if err != nil {
+		return nil, err
+	}
+	return portSources(network.IngressRulesForPorts(portRanges)), nil
+}
+
+// openInstancePorts opens the given ports on the instance.
+func openInstancePorts(inst instance.Instance, machineId string, sources []portSource) error {
+	if inst, ok := inst.(instance.IngressRules); ok {
+		return inst.OpenIngressRules(machineId, ingressRules(sources))
+	}
+	if portRanges := anySourcePortRanges(sources); len(portRanges) > 0 {
+		return inst.OpenPorts(machineId, portRanges)
 	}
 	return nil
 }
 
+// closeInstancePorts closes the given ports on the instance.
+func closeInstancePorts(inst instance.Instance, machineId string, sources []portSource) error {
+	if inst, ok := inst.(instance.IngressRules); ok {
+		return inst.CloseIngressRules(machineId, ingressRules(sources))
+	}
+	if portRanges := anySourcePortRanges(sources); len(portRanges) > 0 {
+		return inst.ClosePorts(machineId, portRanges)
+	}
+	return nil
+}
+
+// ingressRules returns the given port sources as ingress rules, sorted
+// as by network.SortIngressRules.
+func ingressRules(sources []portSource) []network.IngressRule {
+	rules := make([]network.IngressRule, len(sources))
+	for i, source := range sources {
+		rules[i] = network.IngressRule{
+			PortRange:   source.portRange,
+			SourceCIDRs: []string{source.cidr},
+		}
+	}
+	return network.MergeIngressRules(rules)
+}
+
+// portSources returns the port sources of the given ingress rules.
+func portSources(rules []network.IngressRule) []portSource {
+	var sources []portSource
+	for _, rule := range rules {
+		for _, cidr := range rule.SourceCIDRs {
+			sources = append(sources, portSource{rule.PortRange, cidr})
+		}
+	}
+	return sources
+}
+
+// anySourcePortRanges returns the port ranges of the given sources which
+// are opened to any address, for providers which cannot restrict the
+// sources of opened ports. The others are skipped with a warning, and
+// so are never opened.
+func anySourcePortRanges(sources []portSource) []network.PortRange {
+	var portRanges []network.PortRange
+	for _, source := range sources {
+		if source.cidr != network.AnyCIDR {
+			logger.Warningf("provider cannot restrict port sources: skipping port range %v from %s", source.portRange, source.cidr)
+			continue
+		}
+		portRanges = append(portRanges, source.portRange)
+	}
+	network.SortPortRanges(portRanges)
+	return portRanges
+}
+
 // machineLifeChanged starts watching new machines when the firewaller
 // is starting, or when new machines come to life, and stops watching
 // machines that are dying.
@@ -716,7 +838,7 @@ type machineData struct {
 	fw          *Firewaller
 	tag         names.MachineTag
 	unitds      map[names.UnitTag]*unitData
-	openedPorts []network.PortRange
+	openedPorts []portSource
 	// ports defined by units on this machine
 	definedPorts map[network.PortRange]names.UnitTag
 }
@@ -766,10 +888,12 @@ type unitData struct {
 	machined *machineData
 }
 
-// exposedChange contains the changed exposed flag for one specific service.
+// exposedChange contains the changed exposed flag and CIDRs for one
+// specific service.
 type exposedChange struct {
 	serviced *serviceData
 	exposed  bool
+	cidrs    []string
 }
 
 // serviceData holds service details and watches exposure changes.
@@ -778,11 +902,29 @@ type serviceData struct {
 	fw      *Firewaller
 	service *apifirewaller.Service
 	exposed bool
+	cidrs   []string
 	unitds  map[names.UnitTag]*unitData
 }
 
-// watchLoop watches the service's exposed flag for changes.
-func (sd *serviceData) watchLoop(exposed bool) {
+// exposedPorts returns the sources to which the given port range of
+// the service should be opened: none if the service is not exposed,
+// the CIDRs it is exposed to, or any address.
+func (sd *serviceData) exposedPorts(portRange network.PortRange) []portSource {
+	if !sd.exposed {
+		return nil
+	}
+	if len(sd.cidrs) == 0 {
+		return []portSource{{portRange, network.AnyCIDR}}
+	}
+	sources := make([]portSource, len(sd.cidrs))
+	for i, cidr := range sd.cidrs {
+		sources[i] = portSource{portRange, cidr}
+	}
+	return sources
+}
+
+// watchLoop watches the service's exposed flag and CIDRs for changes.
+func (sd *serviceData) watchLoop(exposed bool, cidrs []string) {
 	defer sd.tomb.Done()
 	w, err := sd.service.Watch()
 	if err != nil {
@@ -810,12 +952,18 @@ func (sd *serviceData) watchLoop(exposed bool) {
 				sd.fw.tomb.Kill(err)
 				return
 			}
-			if change == exposed {
+			changedCIDRs, err := sd.service.ExposedCIDRs()
+			if err != nil {
+				sd.fw.tomb.Kill(err)
+				return
+			}
+			if change == exposed && stringsEqual(changedCIDRs, cidrs) {
 				continue
 			}
 			exposed = change
+			cidrs = changedCIDRs
 			select {
-			case sd.fw.exposedChange <- &exposedChange{sd, change}:
+			case sd.fw.exposedChange <- &exposedChange{sd, change, changedCIDRs}:
 			case <-sd.tomb.Dying():
 				return
 			}
@@ -829,8 +977,22 @@ func (sd *serviceData) Stop() error {
 	return sd.tomb.Wait()
 }
 
-// diffRanges returns all the port rangess that exist in A but not B.
-func diffRanges(A, B []network.PortRange) (missing []network.PortRange) {
+// stringsEqual returns whether a and b hold the same strings in the
+// same order.
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// diffRanges returns all the port sources that exist in A but not B.
+func diffRanges(A, B []portSource) (missing []portSource) {
 next:
 	for _, a := range A {
 		for _, b := range B {
@@ -0,0 +1,96 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package imagepruner
+
+import (
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state/imagestorage"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.imagepruner")
+
+// DefaultPruneInterval is how often the cached images are pruned.
+const DefaultPruneInterval = time.Hour
+
+// State defines the state methods used by the image pruner.
+type State interface {
+	EnvironConfig() (*config.Config, error)
+	ImageStorage() imagestorage.Storage
+}
+
+var timeNow = time.Now
+
+// New returns a worker which periodically removes the container
+// images cached in state that have not been used for longer than the
+// environment's image-cache-max-age, and then the least recently
+// used images until those remaining fit within its
+// image-cache-max-size.
+func New(st State, interval time.Duration) worker.Worker {
+	return worker.NewPeriodicWorker(func(stop <-chan struct{}) error {
+		return pruneImages(st)
+	}, interval)
+}
+
+func pruneImages(st State) error {
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	storage := st.ImageStorage()
+	metas, err := storage.ListImages(imagestorage.ImageFilter{})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	maxSize := int64(cfg.ImageCacheMaxSize()) * 1024 * 1024
+	for _, meta := range expiredImages(metas, cfg.ImageCacheMaxAge(), maxSize, timeNow()) {
+		if err := storage.DeleteImage(meta); err != nil {
+			return errors.Annotatef(err, "removing %s %s %s image", meta.Kind, meta.Series, meta.Arch)
+		}
+		logger.Infof(
+			"removed %s %s %s image (%d bytes), last used %v",
+			meta.Kind, meta.Series, meta.Arch, meta.Size, meta.LastUsed,
+		)
+	}
+	return nil
+}
+
+type byLastUsed []*imagestorage.Metadata
+
+func (s byLastUsed) Len() int           { return len(s) }
+func (s byLastUsed) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byLastUsed) Less(i, j int) bool { return s[i].LastUsed.Before(s[j].LastUsed) }
+
+// expiredImages returns the images, least recently used first, that
+// have not been used within maxAge of now, followed by those that
+// must also be removed for the total size of the remaining images to
+// be at most maxSize bytes. Zero values of maxAge and maxSize do not
+// expire any images.
+func expiredImages(metas []*imagestorage.Metadata, maxAge time.Duration, maxSize int64, now time.Time) []*imagestorage.Metadata {
+	sorted := make([]*imagestorage.Metadata, len(metas))
+	copy(sorted, metas)
+	sort.Stable(byLastUsed(sorted))
+
+	var total int64
+	for _, meta := range sorted {
+		total += meta.Size
+	}
+	var expired []*imagestorage.Metadata
+	for _, meta := range sorted {
+		tooOld := maxAge > 0 && now.Sub(meta.LastUsed) > maxAge
+		tooBig := maxSize > 0 && total > maxSize
+		if !tooOld && !tooBig {
+			break
+		}
+		expired = append(expired, meta)
+		total -= meta.Size
+	}
+	return expired
+}
@@ -0,0 +1,171 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package imagepruner_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state/imagestorage"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/imagepruner"
+)
+
+var baseTime = time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC)
+
+type prunerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&prunerSuite{})
+
+// newMeta returns the metadata of an image of the given size in MiB,
+// last used the given number of days before baseTime.
+func newMeta(series string, sizeMiB int64, days int) *imagestorage.Metadata {
+	return &imagestorage.Metadata{
+		Kind:     "lxc",
+		Series:   series,
+		Arch:     "amd64",
+		Size:     sizeMiB * 1024 * 1024,
+		LastUsed: baseTime.AddDate(0, 0, -days),
+	}
+}
+
+func testMetas() []*imagestorage.Metadata {
+	return []*imagestorage.Metadata{
+		newMeta("trusty", 300, 1),
+		newMeta("precise", 200, 40),
+		newMeta("utopic", 250, 10),
+		newMeta("vivid", 250, 60),
+	}
+}
+
+func seriesOf(metas []*imagestorage.Metadata) []string {
+	var series []string
+	for _, meta := range metas {
+		series = append(series, meta.Series)
+	}
+	return series
+}
+
+var expiryTests = []struct {
+	about   string
+	maxAge  time.Duration
+	maxSize int64
+	expired []string
+}{{
+	about: "no limits",
+}, {
+	about:   "max age",
+	maxAge:  30 * 24 * time.Hour,
+	expired: []string{"vivid", "precise"},
+}, {
+	about:   "max size",
+	maxSize: 600 * 1024 * 1024,
+	expired: []string{"vivid", "precise"},
+}, {
+	about:   "max size removes least recently used first",
+	maxSize: 500 * 1024 * 1024,
+	expired: []string{"vivid", "precise", "utopic"},
+}, {
+	about:   "max age and size",
+	maxAge:  50 * 24 * time.Hour,
+	maxSize: 800 * 1024 * 1024,
+	expired: []string{"vivid"},
+}}
+
+func (*prunerSuite) TestExpiredImages(c *gc.C) {
+	for i, test := range expiryTests {
+		c.Logf("test %d: %s", i, test.about)
+		expired := imagepruner.ExpiredImages(testMetas(), test.maxAge, test.maxSize, baseTime)
+		c.Check(seriesOf(expired), jc.DeepEquals, test.expired)
+	}
+}
+
+func (s *prunerSuite) TestWorkerRemovesExpiredImages(c *gc.C) {
+	s.PatchValue(imagepruner.TimeNow, func() time.Time { return baseTime })
+	storage := &fakeStorage{images: testMetas()}
+	st := &fakeState{
+		config: coretesting.CustomEnvironConfig(c, coretesting.Attrs{
+			"image-cache-max-age":  "720h",
+			"image-cache-max-size": 500,
+		}),
+		storage: storage,
+	}
+	w := imagepruner.New(st, coretesting.LongWait)
+	defer func() {
+		c.Assert(worker.Stop(w), jc.ErrorIsNil)
+	}()
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if len(storage.removed()) == 3 {
+			break
+		}
+	}
+	c.Assert(storage.removed(), jc.DeepEquals, []string{"vivid", "precise", "utopic"})
+}
+
+func (s *prunerSuite) TestWorkerDeleteError(c *gc.C) {
+	s.PatchValue(imagepruner.TimeNow, func() time.Time { return baseTime })
+	storage := &fakeStorage{
+		images:    testMetas(),
+		deleteErr: errors.New("boom"),
+	}
+	st := &fakeState{
+		config:  coretesting.CustomEnvironConfig(c, coretesting.Attrs{}),
+		storage: storage,
+	}
+	w := imagepruner.New(st, coretesting.LongWait)
+	err := w.Wait()
+	c.Assert(err, gc.ErrorMatches, "removing lxc vivid amd64 image: boom")
+}
+
+type fakeState struct {
+	config  *config.Config
+	storage *fakeStorage
+}
+
+func (st *fakeState) EnvironConfig() (*config.Config, error) {
+	return st.config, nil
+}
+
+func (st *fakeState) ImageStorage() imagestorage.Storage {
+	return st.storage
+}
+
+type fakeStorage struct {
+	imagestorage.Storage
+
+	deleteErr error
+	images    []*imagestorage.Metadata
+
+	mu      sync.Mutex
+	deleted []string
+}
+
+func (s *fakeStorage) removed() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.deleted...)
+}
+
+func (s *fakeStorage) ListImages(filter imagestorage.ImageFilter) ([]*imagestorage.Metadata, error) {
+	return s.images, nil
+}
+
+func (s *fakeStorage) DeleteImage(meta *imagestorage.Metadata) error {
+	if s.deleteErr != nil {
+		return s.deleteErr
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleted = append(s.deleted, meta.Series)
+	return nil
+}
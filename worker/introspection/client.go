@@ -0,0 +1,31 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package introspection
+
+import (
+	"encoding/json"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/juju/sockets"
+)
+
+// Report connects to the introspection socket at the given path and
+// returns the report served there.
+func Report(socketPath string) (map[string]interface{}, error) {
+	client, err := sockets.Dial(socketPath)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot connect to agent")
+	}
+	defer client.Close()
+	var data string
+	if err := client.Call(ReportEndpoint, ReportArgs{}, &data); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var report map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &report); err != nil {
+		return nil, errors.Annotate(err, "cannot unmarshal report")
+	}
+	return report, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package introspection
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
+)
+
+// FormatDOT renders the manifolds in a dependency engine report, and
+// the dependencies between them, as a graph in the DOT language. Each
+// manifold is coloured according to the state of its worker.
+//
+// The workers in a runner's report are rendered in the same way. The
+// reports of workers that run others, such as the runners started by
+// the agents, are included with their names prefixed by the name of
+// the worker that runs them, on which they are drawn as depending.
+func FormatDOT(report map[string]interface{}) string {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "digraph dependencies {")
+	writeDOTNodes(&buf, "", report)
+	fmt.Fprintln(&buf, "}")
+	return buf.String()
+}
+
+// writeDOTNodes writes the manifolds or workers in the given report,
+// naming each with the given prefix.
+func writeDOTNodes(buf *bytes.Buffer, parent string, report map[string]interface{}) {
+	nodes, ok := report[dependency.KeyManifolds].(map[string]interface{})
+	if !ok {
+		nodes, _ = report[worker.KeyWorkers].(map[string]interface{})
+	}
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	prefix := ""
+	if parent != "" {
+		prefix = parent + "/"
+	}
+	for _, name := range names {
+		node, _ := nodes[name].(map[string]interface{})
+		state, _ := node[dependency.KeyState].(string)
+		errMessage, _ := node[dependency.KeyError].(string)
+		fmt.Fprintf(buf, "    %q [label=%q, color=%q];\n",
+			prefix+name, name+"\n"+state, stateColour(state, errMessage))
+		if parent != "" {
+			fmt.Fprintf(buf, "    %q -> %q;\n", prefix+name, parent)
+		}
+		for _, input := range stringList(node[dependency.KeyInputs]) {
+			fmt.Fprintf(buf, "    %q -> %q;\n", prefix+name, prefix+input)
+		}
+		if nested, ok := node[dependency.KeyReport].(map[string]interface{}); ok {
+			writeDOTNodes(buf, prefix+name, nested)
+		}
+	}
+}
+
+// stateColour returns the colour used to draw a manifold whose worker
+// is in the given state.
+func stateColour(state, errMessage string) string {
+	switch {
+	case state == dependency.StateStarted:
+		return "green"
+	case errMessage != "":
+		return "red"
+	case state == dependency.StateStopped:
+		return "grey"
+	}
+	return "orange"
+}
+
+// stringList returns the strings held in v, which may have been
+// decoded from JSON.
+func stringList(v interface{}) []string {
+	switch v := v.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
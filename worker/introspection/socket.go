@@ -0,0 +1,198 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package introspection provides a worker that serves reports on the
+// internal state of an agent over a local socket, for use by people
+// diagnosing problems with that agent.
+package introspection
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/rpc"
+	"path/filepath"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/juju/sockets"
+	"github.com/juju/juju/version"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
+)
+
+var logger = loggo.GetLogger("juju.worker.introspection")
+
+// ReportEndpoint is the name of the RPC method that returns the
+// agent's dependency engine report.
+const ReportEndpoint = "IntrospectionServer.Report"
+
+// SocketPath returns the path of the introspection socket for the
+// agent with the given tag.
+func SocketPath(dataDir string, tag names.Tag) string {
+	if version.Current.OS == version.Windows {
+		return fmt.Sprintf(`\\.\pipe\%s-introspection`, tag)
+	}
+	return filepath.Join(agent.Dir(dataDir, tag), "introspection.socket")
+}
+
+// Config describes the arguments required to create an introspection
+// worker.
+type Config struct {
+	// SocketPath is the path of the socket to listen on.
+	SocketPath string
+
+	// Reporter supplies the reports served over the socket.
+	Reporter dependency.Reporter
+}
+
+// Validate returns an error if the config cannot be used.
+func (config Config) Validate() error {
+	if config.SocketPath == "" {
+		return errors.NotValidf("empty SocketPath")
+	}
+	if config.Reporter == nil {
+		return errors.NotValidf("nil Reporter")
+	}
+	return nil
+}
+
+// ReportArgs holds the arguments for a Report call.
+type ReportArgs struct{}
+
+// IntrospectionServer holds the methods that are called over the rpc
+// connection.
+type IntrospectionServer struct {
+	reporter dependency.Reporter
+}
+
+// Report returns the JSON encoded report of the agent's dependency
+// engine. The report is encoded so that clients need know nothing of
+// the types it holds.
+func (s *IntrospectionServer) Report(_ ReportArgs, result *string) error {
+	data, err := json.Marshal(s.reporter.Report())
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal report")
+	}
+	*result = string(data)
+	return nil
+}
+
+// socketListener is a worker that serves an IntrospectionServer on a
+// local socket.
+type socketListener struct {
+	tomb     tomb.Tomb
+	listener net.Listener
+	server   *rpc.Server
+	wg       sync.WaitGroup
+
+	// mu guards conns and closed.
+	mu     sync.Mutex
+	conns  map[net.Conn]bool
+	closed bool
+}
+
+// NewWorker returns a worker that serves reports from the configured
+// Reporter on the configured socket until it is killed.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	server := rpc.NewServer()
+	if err := server.Register(&IntrospectionServer{config.Reporter}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	listener, err := sockets.Listen(config.SocketPath)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &socketListener{
+		listener: listener,
+		server:   server,
+		conns:    make(map[net.Conn]bool),
+	}
+	go func() {
+		defer w.tomb.Done()
+		w.tomb.Kill(w.loop())
+	}()
+	return w, nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *socketListener) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *socketListener) Wait() error {
+	return w.tomb.Wait()
+}
+
+func (w *socketListener) loop() error {
+	go func() {
+		<-w.tomb.Dying()
+		w.listener.Close()
+		// Clients may hold connections open indefinitely, so they
+		// are closed rather than waited for.
+		w.closeConns()
+	}()
+	logger.Debugf("introspection listener running on %s", w.listener.Addr())
+	for {
+		conn, err := w.listener.Accept()
+		if err != nil {
+			w.wg.Wait()
+			select {
+			case <-w.tomb.Dying():
+				// The error is a direct result of closing the
+				// listener, and can safely be ignored.
+				return tomb.ErrDying
+			default:
+				return errors.Trace(err)
+			}
+		}
+		if !w.trackConn(conn) {
+			continue
+		}
+		w.wg.Add(1)
+		go func(conn net.Conn) {
+			defer w.wg.Done()
+			defer w.untrackConn(conn)
+			w.server.ServeConn(conn)
+		}(conn)
+	}
+}
+
+// trackConn records conn so that it is closed when the worker is
+// killed. If the worker is already being killed, it closes conn and
+// returns false.
+func (w *socketListener) trackConn(conn net.Conn) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		conn.Close()
+		return false
+	}
+	w.conns[conn] = true
+	return true
+}
+
+func (w *socketListener) untrackConn(conn net.Conn) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.conns, conn)
+}
+
+// closeConns closes all open connections, and any accepted later.
+func (w *socketListener) closeConns() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	for conn := range w.conns {
+		conn.Close()
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package introspection_test
+
+import (
+	"io"
+	"net"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/introspection"
+)
+
+type introspectionSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&introspectionSuite{})
+
+func (s *introspectionSuite) SetUpTest(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("introspection tests use unix sockets")
+	}
+	s.BaseSuite.SetUpTest(c)
+}
+
+type fakeReporter map[string]interface{}
+
+func (r fakeReporter) Report() map[string]interface{} {
+	return r
+}
+
+func (s *introspectionSuite) TestSocketPath(c *gc.C) {
+	path := introspection.SocketPath("/var/lib/juju", names.NewMachineTag("0"))
+	c.Assert(path, gc.Equals, "/var/lib/juju/agents/machine-0/introspection.socket")
+}
+
+func (s *introspectionSuite) TestConfigValidation(c *gc.C) {
+	_, err := introspection.NewWorker(introspection.Config{
+		Reporter: fakeReporter{},
+	})
+	c.Assert(err, gc.ErrorMatches, "empty SocketPath not valid")
+	_, err = introspection.NewWorker(introspection.Config{
+		SocketPath: filepath.Join(c.MkDir(), "introspection.socket"),
+	})
+	c.Assert(err, gc.ErrorMatches, "nil Reporter not valid")
+}
+
+func (s *introspectionSuite) TestReport(c *gc.C) {
+	socketPath := filepath.Join(c.MkDir(), "introspection.socket")
+	w, err := introspection.NewWorker(introspection.Config{
+		SocketPath: socketPath,
+		Reporter: fakeReporter{
+			dependency.KeyState: dependency.StateStarted,
+			dependency.KeyManifolds: map[string]interface{}{
+				"api-caller": map[string]interface{}{
+					dependency.KeyState:      dependency.StateStarted,
+					dependency.KeyStartCount: 1,
+				},
+			},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer worker.Stop(w)
+
+	report, err := introspection.Report(socketPath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report, jc.DeepEquals, map[string]interface{}{
+		"state": "started",
+		"manifolds": map[string]interface{}{
+			"api-caller": map[string]interface{}{
+				"state":       "started",
+				"start-count": float64(1),
+			},
+		},
+	})
+
+	c.Assert(worker.Stop(w), jc.ErrorIsNil)
+	_, err = introspection.Report(socketPath)
+	c.Assert(err, gc.ErrorMatches, "cannot connect to agent: .*")
+}
+
+func (s *introspectionSuite) TestStopClosesIdleConnections(c *gc.C) {
+	socketPath := filepath.Join(c.MkDir(), "introspection.socket")
+	w, err := introspection.NewWorker(introspection.Config{
+		SocketPath: socketPath,
+		Reporter:   fakeReporter{},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer worker.Stop(w)
+
+	// A client that connects and then sends nothing must not stop
+	// the worker shutting down.
+	conn, err := net.Dial("unix", socketPath)
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+	_, err = introspection.Report(socketPath)
+	c.Assert(err, jc.ErrorIsNil)
+
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- worker.Stop(w)
+	}()
+	select {
+	case err := <-stopped:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("worker did not stop")
+	}
+
+	// The idle connection was closed by the worker.
+	conn.SetReadDeadline(time.Now().Add(coretesting.LongWait))
+	_, err = conn.Read(make([]byte, 1))
+	c.Assert(err, gc.Equals, io.EOF)
+}
+
+func (s *introspectionSuite) TestFormatDOT(c *gc.C) {
+	dot := introspection.FormatDOT(map[string]interface{}{
+		dependency.KeyState: dependency.StateStarted,
+		dependency.KeyManifolds: map[string]interface{}{
+			"uniter": map[string]interface{}{
+				dependency.KeyState:  dependency.StateStopped,
+				dependency.KeyError:  "dependency not available",
+				dependency.KeyInputs: []interface{}{"api-caller", "machine-lock"},
+			},
+			"api-caller": map[string]interface{}{
+				dependency.KeyState: dependency.StateStarted,
+			},
+			"machine-lock": map[string]interface{}{
+				dependency.KeyState: dependency.StateStopped,
+			},
+		},
+	})
+	c.Assert(dot, gc.Equals, `digraph dependencies {
+    "api-caller" [label="api-caller\nstarted", color="green"];
+    "machine-lock" [label="machine-lock\nstopped", color="grey"];
+    "uniter" [label="uniter\nstopped", color="red"];
+    "uniter" -> "api-caller";
+    "uniter" -> "machine-lock";
+}
+`)
+}
+
+func (s *introspectionSuite) TestFormatDOTRunnerReport(c *gc.C) {
+	dot := introspection.FormatDOT(map[string]interface{}{
+		worker.KeyState: worker.StateStarted,
+		worker.KeyWorkers: map[string]interface{}{
+			"api": map[string]interface{}{
+				worker.KeyState: worker.StateStarted,
+				worker.KeyReport: map[string]interface{}{
+					worker.KeyState: worker.StateStarted,
+					worker.KeyWorkers: map[string]interface{}{
+						"uniter": map[string]interface{}{
+							worker.KeyState: worker.StateStarting,
+							worker.KeyError: "hook failed",
+						},
+					},
+				},
+			},
+			"introspection": map[string]interface{}{
+				worker.KeyState: worker.StateStarted,
+			},
+		},
+	})
+	c.Assert(dot, gc.Equals, `digraph dependencies {
+    "api" [label="api\nstarted", color="green"];
+    "api/uniter" [label="uniter\nstarting", color="red"];
+    "api/uniter" -> "api";
+    "introspection" [label="introspection\nstarted", color="green"];
+}
+`)
+}
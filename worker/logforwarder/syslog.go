@@ -0,0 +1,154 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/state"
+)
+
+// LogSink is a destination for forwarded log records.
+type LogSink interface {
+	// Send delivers the given record. It returns an error if
+	// the record could not be delivered.
+	Send(rec *state.LogRecord) error
+
+	// Close releases any resources held by the sink.
+	Close() error
+}
+
+// SyslogConfig holds the details of a remote syslog server.
+type SyslogConfig struct {
+	// Address holds the host:port of the server.
+	Address string
+
+	// UseTLS specifies whether to connect to the server over TLS.
+	UseTLS bool
+
+	// CACert holds the PEM encoded certificate of the CA used to
+	// verify the server. If empty, the system roots are used.
+	CACert string
+}
+
+const (
+	// syslogFacility is the syslog facility forwarded records are
+	// reported under (system daemons).
+	syslogFacility = 3
+
+	// maxAppNameLen is the maximum length of the APP-NAME field
+	// allowed by RFC 5424.
+	maxAppNameLen = 48
+
+	// syslogDialTimeout bounds the time spent connecting to the
+	// remote syslog server.
+	syslogDialTimeout = 30 * time.Second
+
+	// syslogWriteTimeout bounds the time spent sending a single
+	// record to the remote syslog server.
+	syslogWriteTimeout = time.Minute
+)
+
+// syslogSink is a LogSink that sends records to a remote syslog
+// server as RFC 5424 messages, framed using octet counting as
+// described in RFC 6587.
+type syslogSink struct {
+	conn net.Conn
+}
+
+// DialSyslog connects to the remote syslog server described by cfg
+// and returns a LogSink that sends records to it.
+func DialSyslog(cfg SyslogConfig) (LogSink, error) {
+	dialer := &net.Dialer{Timeout: syslogDialTimeout}
+	if !cfg.UseTLS {
+		conn, err := dialer.Dial("tcp", cfg.Address)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot connect to syslog server")
+		}
+		return &syslogSink{conn: conn}, nil
+	}
+	host, _, err := net.SplitHostPort(cfg.Address)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tlsConfig := &tls.Config{ServerName: host}
+	if cfg.CACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(cfg.CACert)) {
+			return nil, errors.New("cannot parse syslog CA certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	conn, err := tls.DialWithDialer(dialer, "tcp", cfg.Address, tlsConfig)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot connect to syslog server")
+	}
+	return &syslogSink{conn: conn}, nil
+}
+
+// Send implements LogSink.
+func (s *syslogSink) Send(rec *state.LogRecord) error {
+	msg := formatSyslogMessage(rec)
+	if err := s.conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout)); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := fmt.Fprintf(s.conn, "%d %s", len(msg), msg); err != nil {
+		return errors.Annotate(err, "cannot send to syslog server")
+	}
+	return nil
+}
+
+// Close implements LogSink.
+func (s *syslogSink) Close() error {
+	return s.conn.Close()
+}
+
+// formatSyslogMessage returns the RFC 5424 representation of rec.
+// The entity that logged the record is reported as the HOSTNAME and
+// its logging module as the APP-NAME.
+func formatSyslogMessage(rec *state.LogRecord) string {
+	pri := syslogFacility*8 + syslogSeverity(rec.Level)
+	appName := rec.Module
+	if len(appName) > maxAppNameLen {
+		appName = appName[:maxAppNameLen]
+	}
+	return fmt.Sprintf("<%d>1 %s %s %s - - - %s %s",
+		pri,
+		rec.Time.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		nilValue(rec.Entity),
+		nilValue(appName),
+		rec.Location,
+		rec.Message,
+	)
+}
+
+// syslogSeverity maps a loggo level to a syslog severity.
+func syslogSeverity(level loggo.Level) int {
+	switch level {
+	case loggo.CRITICAL:
+		return 2
+	case loggo.ERROR:
+		return 3
+	case loggo.WARNING:
+		return 4
+	case loggo.INFO:
+		return 6
+	}
+	return 7
+}
+
+// nilValue returns s, or the syslog NILVALUE if s is empty.
+func nilValue(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,115 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/logforwarder"
+)
+
+type syslogSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&syslogSuite{})
+
+func (s *syslogSuite) TestFormatSyslogMessage(c *gc.C) {
+	for i, test := range []struct {
+		level    loggo.Level
+		module   string
+		expected string
+	}{{
+		level:    loggo.INFO,
+		module:   "juju.worker",
+		expected: "<30>1 2015-07-01T12:00:00.250000Z machine-0 juju.worker - - - worker.go:42 hello",
+	}, {
+		level:    loggo.ERROR,
+		module:   "juju.worker",
+		expected: "<27>1 2015-07-01T12:00:00.250000Z machine-0 juju.worker - - - worker.go:42 hello",
+	}, {
+		level:    loggo.TRACE,
+		module:   "",
+		expected: "<31>1 2015-07-01T12:00:00.250000Z machine-0 - - - - worker.go:42 hello",
+	}, {
+		level:    loggo.WARNING,
+		module:   strings.Repeat("m", 60),
+		expected: "<28>1 2015-07-01T12:00:00.250000Z machine-0 " + strings.Repeat("m", 48) + " - - - worker.go:42 hello",
+	}} {
+		c.Logf("test %d", i)
+		msg := logforwarder.FormatSyslogMessage(&state.LogRecord{
+			Time:     time.Date(2015, 7, 1, 12, 0, 0, 250000000, time.UTC),
+			Entity:   "machine-0",
+			Module:   test.module,
+			Location: "worker.go:42",
+			Level:    test.level,
+			Message:  "hello",
+		})
+		c.Check(msg, gc.Equals, test.expected)
+	}
+}
+
+func (s *syslogSuite) TestDialSyslogSendsFramedMessages(c *gc.C) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			close(received)
+			return
+		}
+		defer conn.Close()
+		data, _ := ioutil.ReadAll(conn)
+		received <- string(data)
+	}()
+
+	sink, err := logforwarder.DialSyslog(logforwarder.SyslogConfig{
+		Address: listener.Addr().String(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	rec := &state.LogRecord{
+		Time:     time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC),
+		Entity:   "unit-mysql-0",
+		Module:   "juju.uniter",
+		Location: "uniter.go:1",
+		Level:    loggo.INFO,
+		Message:  "started",
+	}
+	err = sink.Send(rec)
+	c.Assert(err, jc.ErrorIsNil)
+	err = sink.Send(rec)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sink.Close(), jc.ErrorIsNil)
+
+	msg := logforwarder.FormatSyslogMessage(rec)
+	framed := fmt.Sprintf("%d %s", len(msg), msg)
+	select {
+	case data := <-received:
+		c.Assert(data, gc.Equals, framed+framed)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("nothing received")
+	}
+}
+
+func (s *syslogSuite) TestDialSyslogBadCACert(c *gc.C) {
+	_, err := logforwarder.DialSyslog(logforwarder.SyslogConfig{
+		Address: "127.0.0.1:6514",
+		UseTLS:  true,
+		CACert:  "rubbish",
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot parse syslog CA certificate")
+}
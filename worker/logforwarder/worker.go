@@ -0,0 +1,218 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/mgo.v2/bson"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.logforwarder")
+
+// CursorName is the name under which the worker records its
+// progress through the environment's logs.
+const CursorName = "syslog"
+
+// cursorSaveInterval is how often the worker records its progress
+// while it is forwarding records.
+var cursorSaveInterval = 10 * time.Second
+
+// State defines the state methods used by the log forwarder.
+type State interface {
+	state.LoggingState
+	EnvironConfig() (*config.Config, error)
+	WatchForEnvironConfigChanges() state.NotifyWatcher
+	LogForwardingCursor(name string) (bson.ObjectId, error)
+	SetLogForwardingCursor(name string, lastSent bson.ObjectId) error
+}
+
+// backfillId precedes the id of every log record, so that tailing
+// after it returns the whole log history.
+var backfillId = bson.ObjectId(make([]byte, 12))
+
+var (
+	newLogTailer = state.NewLogTailer
+	dialSyslog   = DialSyslog
+)
+
+// New returns a worker which forwards the environment's logs, as
+// stored in the database, to the remote syslog server given in the
+// environment configuration. Records are delivered at least once:
+// the worker periodically records the id of the last record it sent,
+// and resumes after that record when restarted. When forwarding is
+// first enabled only records written from then on are sent, unless
+// logforward-backfill is set. Nothing is forwarded while no syslog
+// server is configured.
+func New(st State) worker.Worker {
+	w := &forwarder{st: st}
+	return worker.NewSimpleWorker(w.loop)
+}
+
+type forwarder struct {
+	st       State
+	tailer   state.LogTailer
+	sink     LogSink
+	backfill bool
+	lastSent bson.ObjectId
+	unsaved  bool
+}
+
+func (w *forwarder) loop(stopCh <-chan struct{}) (err error) {
+	configWatcher := w.st.WatchForEnvironConfigChanges()
+	defer configWatcher.Stop()
+	defer func() {
+		if stopErr := w.stopForwarding(); err == nil {
+			err = stopErr
+		}
+	}()
+
+	var current *SyslogConfig
+	var saveCursor <-chan time.Time
+	for {
+		var logs <-chan *state.LogRecord
+		if w.tailer != nil {
+			logs = w.tailer.Logs()
+		}
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case _, ok := <-configWatcher.Changes():
+			if !ok {
+				return watcher.EnsureErr(configWatcher)
+			}
+			cfg, err := w.st.EnvironConfig()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			syslogConfig := syslogConfigFromEnviron(cfg)
+			w.backfill = cfg.LogForwardBackfill()
+			if sameSyslogConfig(current, syslogConfig) {
+				continue
+			}
+			if err := w.stopForwarding(); err != nil {
+				return errors.Trace(err)
+			}
+			current = syslogConfig
+			if current != nil {
+				if err := w.startForwarding(*current); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		case rec, ok := <-logs:
+			if !ok {
+				return errors.Annotate(w.tailer.Err(), "log tailer stopped")
+			}
+			if err := w.sink.Send(rec); err != nil {
+				return errors.Trace(err)
+			}
+			if rec.Id > w.lastSent {
+				w.lastSent = rec.Id
+				w.unsaved = true
+			}
+			if saveCursor == nil {
+				saveCursor = time.After(cursorSaveInterval)
+			}
+		case <-saveCursor:
+			saveCursor = nil
+			if err := w.saveCursor(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// startForwarding connects to the given syslog server and starts
+// tailing the logs after the last recorded position.
+func (w *forwarder) startForwarding(cfg SyslogConfig) error {
+	lastSent, err := w.st.LogForwardingCursor(CursorName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if lastSent == "" {
+		if w.backfill {
+			logger.Infof("forwarding all recorded logs to %s", cfg.Address)
+			lastSent = backfillId
+		} else {
+			logger.Infof("forwarding logs recorded from now on to %s", cfg.Address)
+			lastSent = bson.NewObjectIdWithTime(time.Now())
+		}
+		// Record the starting point straight away, so that a restart
+		// neither skips records nor falls back to the whole history.
+		if err := w.st.SetLogForwardingCursor(CursorName, lastSent); err != nil {
+			return errors.Trace(err)
+		}
+	} else {
+		logger.Infof("forwarding logs recorded after %s to %s", lastSent.Hex(), cfg.Address)
+	}
+	sink, err := dialSyslog(cfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	w.sink = sink
+	w.lastSent = lastSent
+	w.tailer = newLogTailer(w.st, &state.LogTailerParams{
+		AfterId: lastSent,
+	})
+	return nil
+}
+
+// stopForwarding stops any tailing in progress and records how far
+// it got.
+func (w *forwarder) stopForwarding() error {
+	if w.tailer == nil {
+		return nil
+	}
+	tailerErr := w.tailer.Stop()
+	sinkErr := w.sink.Close()
+	w.tailer, w.sink = nil, nil
+	if err := w.saveCursor(); err != nil {
+		return errors.Trace(err)
+	}
+	if tailerErr != nil {
+		return errors.Trace(tailerErr)
+	}
+	return errors.Trace(sinkErr)
+}
+
+func (w *forwarder) saveCursor() error {
+	if !w.unsaved {
+		return nil
+	}
+	if err := w.st.SetLogForwardingCursor(CursorName, w.lastSent); err != nil {
+		return errors.Trace(err)
+	}
+	w.unsaved = false
+	return nil
+}
+
+// syslogConfigFromEnviron returns the syslog server described by the
+// environment configuration, or nil if none is configured.
+func syslogConfigFromEnviron(cfg *config.Config) *SyslogConfig {
+	addr, ok := cfg.LogForwardSyslogAddress()
+	if !ok {
+		return nil
+	}
+	caCert, _ := cfg.LogForwardSyslogCACert()
+	return &SyslogConfig{
+		Address: addr,
+		UseTLS:  cfg.LogForwardSyslogTLS(),
+		CACert:  caCert,
+	}
+}
+
+func sameSyslogConfig(a, b *SyslogConfig) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,371 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder_test
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/logforwarder"
+)
+
+type workerSuite struct {
+	coretesting.BaseSuite
+	st      *fakeState
+	tailers chan *fakeTailer
+	sinks   chan *fakeSink
+	dialed  chan logforwarder.SyslogConfig
+	sendErr error
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+var baseTime = time.Date(2015, 7, 1, 12, 0, 0, 0, time.UTC)
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.st = &fakeState{
+		cfg:     coretesting.EnvironConfig(c),
+		changes: make(chan struct{}, 1),
+		cursors: make(map[string]bson.ObjectId),
+	}
+	s.tailers = make(chan *fakeTailer, 5)
+	s.sinks = make(chan *fakeSink, 5)
+	s.dialed = make(chan logforwarder.SyslogConfig, 5)
+	s.sendErr = nil
+	s.PatchValue(logforwarder.NewLogTailer, func(_ state.LoggingState, params *state.LogTailerParams) state.LogTailer {
+		tailer := &fakeTailer{
+			params: params,
+			logs:   make(chan *state.LogRecord),
+			dying:  make(chan struct{}),
+		}
+		s.tailers <- tailer
+		return tailer
+	})
+	s.PatchValue(logforwarder.DialSyslogFunc, func(cfg logforwarder.SyslogConfig) (logforwarder.LogSink, error) {
+		s.dialed <- cfg
+		sink := &fakeSink{err: s.sendErr, sent: make(chan *state.LogRecord, 10)}
+		s.sinks <- sink
+		return sink, nil
+	})
+	s.PatchValue(logforwarder.CursorSaveInterval, time.Hour)
+}
+
+func (s *workerSuite) setSyslogConfig(c *gc.C, attrs coretesting.Attrs) {
+	cfg, err := s.st.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err = cfg.Apply(attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	s.st.setConfig(cfg)
+	s.st.changes <- struct{}{}
+}
+
+func (s *workerSuite) startWorker(c *gc.C) worker.Worker {
+	w := logforwarder.New(s.st)
+	s.AddCleanup(func(*gc.C) { worker.Stop(w) })
+	return w
+}
+
+func (s *workerSuite) nextTailer(c *gc.C) *fakeTailer {
+	select {
+	case tailer := <-s.tailers:
+		return tailer
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("log tailer not started")
+	}
+	panic("unreachable")
+}
+
+func (s *workerSuite) nextSink(c *gc.C) *fakeSink {
+	select {
+	case sink := <-s.sinks:
+		return sink
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("syslog server not dialed")
+	}
+	panic("unreachable")
+}
+
+func (s *workerSuite) assertSent(c *gc.C, sink *fakeSink, rec *state.LogRecord) {
+	select {
+	case sent := <-sink.sent:
+		c.Assert(sent, gc.Equals, rec)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("record not sent")
+	}
+}
+
+func logRecord(offset time.Duration, message string) *state.LogRecord {
+	return &state.LogRecord{
+		Id:       bson.NewObjectId(),
+		Time:     baseTime.Add(offset),
+		Entity:   "machine-0",
+		Module:   "juju.worker",
+		Location: "worker.go:42",
+		Level:    loggo.INFO,
+		Message:  message,
+	}
+}
+
+func (s *workerSuite) TestNotConfigured(c *gc.C) {
+	s.st.changes <- struct{}{}
+	w := s.startWorker(c)
+
+	select {
+	case <-s.tailers:
+		c.Fatalf("unexpected log tailer")
+	case <-time.After(coretesting.ShortWait):
+	}
+	c.Assert(worker.Stop(w), jc.ErrorIsNil)
+}
+
+func (s *workerSuite) TestForwardsLogs(c *gc.C) {
+	cursor := bson.NewObjectId()
+	s.st.cursors[logforwarder.CursorName] = cursor
+	s.setSyslogConfig(c, coretesting.Attrs{
+		"logforward-syslog-address": "10.0.0.1:6514",
+		"logforward-syslog-tls":     true,
+	})
+	w := s.startWorker(c)
+
+	c.Assert(<-s.dialed, jc.DeepEquals, logforwarder.SyslogConfig{
+		Address: "10.0.0.1:6514",
+		UseTLS:  true,
+	})
+	sink := s.nextSink(c)
+	tailer := s.nextTailer(c)
+	c.Assert(tailer.params.AfterId, gc.Equals, cursor)
+
+	rec1 := logRecord(time.Second, "one")
+	rec2 := logRecord(2*time.Second, "two")
+	tailer.logs <- rec1
+	s.assertSent(c, sink, rec1)
+	tailer.logs <- rec2
+	s.assertSent(c, sink, rec2)
+
+	c.Assert(worker.Stop(w), jc.ErrorIsNil)
+	c.Assert(tailer.stopped(), jc.IsTrue)
+	c.Assert(sink.closed, jc.IsTrue)
+	c.Assert(s.st.cursor(logforwarder.CursorName), gc.Equals, rec2.Id)
+}
+
+func (s *workerSuite) TestStartsFromNowWhenFirstEnabled(c *gc.C) {
+	s.setSyslogConfig(c, coretesting.Attrs{
+		"logforward-syslog-address": "10.0.0.1:514",
+	})
+	before := time.Now().Add(-time.Second)
+	s.startWorker(c)
+	s.nextSink(c)
+	tailer := s.nextTailer(c)
+
+	start := tailer.params.AfterId
+	c.Assert(start.Valid(), jc.IsTrue)
+	c.Assert(start.Time().Before(before), jc.IsFalse)
+	// The starting point is recorded before anything is sent.
+	c.Assert(s.st.cursor(logforwarder.CursorName), gc.Equals, start)
+}
+
+func (s *workerSuite) TestBackfill(c *gc.C) {
+	s.setSyslogConfig(c, coretesting.Attrs{
+		"logforward-syslog-address": "10.0.0.1:514",
+		"logforward-backfill":       true,
+	})
+	s.startWorker(c)
+	s.nextSink(c)
+	tailer := s.nextTailer(c)
+	c.Assert(tailer.params.AfterId, gc.Equals, bson.ObjectId(make([]byte, 12)))
+}
+
+func (s *workerSuite) TestBackfillIgnoredWithCursor(c *gc.C) {
+	cursor := bson.NewObjectId()
+	s.st.cursors[logforwarder.CursorName] = cursor
+	s.setSyslogConfig(c, coretesting.Attrs{
+		"logforward-syslog-address": "10.0.0.1:514",
+		"logforward-backfill":       true,
+	})
+	s.startWorker(c)
+	s.nextSink(c)
+	tailer := s.nextTailer(c)
+	c.Assert(tailer.params.AfterId, gc.Equals, cursor)
+}
+
+func (s *workerSuite) TestSavesCursorPeriodically(c *gc.C) {
+	s.PatchValue(logforwarder.CursorSaveInterval, time.Millisecond)
+	s.setSyslogConfig(c, coretesting.Attrs{
+		"logforward-syslog-address": "10.0.0.1:514",
+	})
+	s.startWorker(c)
+	sink := s.nextSink(c)
+	tailer := s.nextTailer(c)
+
+	rec := logRecord(time.Second, "one")
+	tailer.logs <- rec
+	s.assertSent(c, sink, rec)
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		if s.st.cursor(logforwarder.CursorName) == rec.Id {
+			return
+		}
+	}
+	c.Fatalf("cursor not saved")
+}
+
+func (s *workerSuite) TestRestartsOnConfigChange(c *gc.C) {
+	s.setSyslogConfig(c, coretesting.Attrs{
+		"logforward-syslog-address": "10.0.0.1:514",
+	})
+	s.startWorker(c)
+	sink := s.nextSink(c)
+	tailer := s.nextTailer(c)
+	rec := logRecord(time.Second, "one")
+	tailer.logs <- rec
+	s.assertSent(c, sink, rec)
+
+	s.setSyslogConfig(c, coretesting.Attrs{
+		"logforward-syslog-address": "10.0.0.2:514",
+	})
+	c.Assert((<-s.dialed).Address, gc.Equals, "10.0.0.1:514")
+	c.Assert((<-s.dialed).Address, gc.Equals, "10.0.0.2:514")
+	s.nextSink(c)
+	newTailer := s.nextTailer(c)
+
+	// The new tailer picks up where the old one left off.
+	c.Assert(newTailer.params.AfterId, gc.Equals, rec.Id)
+	c.Assert(tailer.stopped(), jc.IsTrue)
+	c.Assert(sink.closed, jc.IsTrue)
+}
+
+func (s *workerSuite) TestSendErrorStopsWorker(c *gc.C) {
+	cursor := bson.NewObjectId()
+	s.st.cursors[logforwarder.CursorName] = cursor
+	s.sendErr = errors.New("connection reset")
+	s.setSyslogConfig(c, coretesting.Attrs{
+		"logforward-syslog-address": "10.0.0.1:514",
+	})
+	w := s.startWorker(c)
+	s.nextSink(c)
+	tailer := s.nextTailer(c)
+	tailer.logs <- logRecord(time.Second, "one")
+
+	c.Assert(w.Wait(), gc.ErrorMatches, "connection reset")
+	c.Assert(tailer.stopped(), jc.IsTrue)
+	// Nothing was delivered, so no progress is recorded.
+	c.Assert(s.st.cursor(logforwarder.CursorName), gc.Equals, cursor)
+}
+
+type fakeState struct {
+	state.LoggingState
+
+	mu      sync.Mutex
+	cfg     *config.Config
+	changes chan struct{}
+	cursors map[string]bson.ObjectId
+}
+
+func (st *fakeState) EnvironConfig() (*config.Config, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.cfg, nil
+}
+
+func (st *fakeState) setConfig(cfg *config.Config) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.cfg = cfg
+}
+
+func (st *fakeState) WatchForEnvironConfigChanges() state.NotifyWatcher {
+	return &fakeNotifyWatcher{changes: st.changes}
+}
+
+func (st *fakeState) LogForwardingCursor(name string) (bson.ObjectId, error) {
+	return st.cursor(name), nil
+}
+
+func (st *fakeState) SetLogForwardingCursor(name string, lastSent bson.ObjectId) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.cursors[name] = lastSent
+	return nil
+}
+
+func (st *fakeState) cursor(name string) bson.ObjectId {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.cursors[name]
+}
+
+type fakeNotifyWatcher struct {
+	state.NotifyWatcher
+	changes chan struct{}
+}
+
+func (w *fakeNotifyWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+func (w *fakeNotifyWatcher) Stop() error {
+	return nil
+}
+
+func (w *fakeNotifyWatcher) Err() error {
+	return nil
+}
+
+type fakeTailer struct {
+	state.LogTailer
+	params *state.LogTailerParams
+	logs   chan *state.LogRecord
+	dying  chan struct{}
+}
+
+func (t *fakeTailer) Logs() <-chan *state.LogRecord {
+	return t.logs
+}
+
+func (t *fakeTailer) Stop() error {
+	close(t.dying)
+	return nil
+}
+
+func (t *fakeTailer) Err() error {
+	return nil
+}
+
+func (t *fakeTailer) stopped() bool {
+	select {
+	case <-t.dying:
+		return true
+	default:
+		return false
+	}
+}
+
+type fakeSink struct {
+	err    error
+	sent   chan *state.LogRecord
+	closed bool
+}
+
+func (s *fakeSink) Send(rec *state.LogRecord) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent <- rec
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.closed = true
+	return nil
+}
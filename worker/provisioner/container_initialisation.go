@@ -20,6 +20,7 @@ import (
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/container/lxc"
+	"github.com/juju/juju/container/lxd"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
@@ -150,6 +151,11 @@ func (cs *ContainerSetup) initialiseAndStartProvisioner(containerType instance.C
 	if err := cs.runInitialiser(containerType, initialiser); err != nil {
 		return errors.Annotate(err, "setting up container dependencies on host machine")
 	}
+	if templateBroker, ok := broker.(LxcTemplateBroker); ok {
+		if err := StartLxcTemplateWorker(cs.runner, templateBroker, cs.provisioner, cs.config); err != nil {
+			return errors.Annotate(err, "starting lxc template worker")
+		}
+	}
 	return StartProvisioner(cs.runner, containerType, cs.provisioner, cs.config, broker, toolsFinder)
 }
 
@@ -245,6 +251,12 @@ func (cs *ContainerSetup) getContainerArtifacts(
 		return nil, nil, nil, err
 	}
 
+	// The clone templates to create and the age at which unused
+	// templates are removed are fetched again by the template
+	// worker each time it runs.
+	managerConfig.PopValue(container.ConfigLXCTemplateSeries)
+	managerConfig.PopValue(container.ConfigLXCTemplateMaxAge)
+
 	// Override default MTU for LXC NICs, if needed.
 	if mtu := managerConfig.PopValue(container.ConfigLXCDefaultMTU); mtu != "" {
 		value, err := strconv.Atoi(mtu)
@@ -308,6 +320,28 @@ func (cs *ContainerSetup) getContainerArtifacts(
 			logger.Errorf("failed to create new kvm broker")
 			return nil, nil, nil, err
 		}
+
+	case instance.LXD:
+		series, err := cs.machine.Series()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		initialiser = lxd.NewContainerInitialiser(series)
+		broker, err = NewLxdBroker(
+			cs.provisioner,
+			cs.config,
+			managerConfig,
+			cs.enableNAT,
+		)
+		if err != nil {
+			logger.Errorf("failed to create new lxd broker")
+			return nil, nil, nil, err
+		}
+
+		// LXD containers share the host's kernel, so they must have
+		// the same architecture as the host.
+		toolsFinder = hostArchToolsFinder{toolsFinder}
 	default:
 		return nil, nil, nil, fmt.Errorf("unknown container type: %v", containerType)
 	}
@@ -343,7 +377,8 @@ func containerManagerConfig(
 
 // Override for testing.
 var (
-	StartProvisioner = startProvisionerWorker
+	StartProvisioner       = startProvisionerWorker
+	StartLxcTemplateWorker = startLxcTemplateWorker
 
 	sysctlConfig = "/etc/sysctl.conf"
 )
@@ -373,6 +408,22 @@ func startProvisionerWorker(
 	})
 }
 
+// startLxcTemplateWorker starts a worker which creates LXC clone
+// templates before they are needed and removes unused ones.
+func startLxcTemplateWorker(
+	runner worker.Runner,
+	broker LxcTemplateBroker,
+	provisioner *apiprovisioner.State,
+	config agent.Config,
+) error {
+	getConfig := func() (container.ManagerConfig, error) {
+		return containerManagerConfig(instance.LXC, provisioner, config)
+	}
+	return runner.StartWorker("lxc-templates", func() (worker.Worker, error) {
+		return NewLxcTemplateWorker(broker, getConfig), nil
+	})
+}
+
 // setIPAndARPForwarding enables or disables IP and ARP forwarding on
 // the machine. This is needed when the machine needs to host
 // addressable containers.
@@ -46,6 +46,8 @@ type ContainerSetupSuite struct {
 	initLockDir string
 	initLock    *fslock.Lock
 	fakeLXCNet  string
+	// Record whether the lxc template worker was started.
+	templateWorkerStarted bool
 }
 
 var _ = gc.Suite(&ContainerSetupSuite{})
@@ -88,6 +90,12 @@ func (s *ContainerSetupSuite) SetUpTest(c *gc.C) {
 	// Patch to isolate the test from the host machine.
 	s.fakeLXCNet = filepath.Join(c.MkDir(), "lxc-net")
 	s.PatchValue(provisioner.EtcDefaultLXCNetPath, s.fakeLXCNet)
+	s.templateWorkerStarted = false
+	s.PatchValue(&provisioner.StartLxcTemplateWorker, func(worker.Runner,
+		provisioner.LxcTemplateBroker, *apiprovisioner.State, agent.Config) error {
+		s.templateWorkerStarted = true
+		return nil
+	})
 }
 
 func (s *ContainerSetupSuite) TearDownTest(c *gc.C) {
@@ -167,6 +175,9 @@ func (s *ContainerSetupSuite) assertContainerProvisionerStarted(
 
 	// the container worker should have created the provisioner
 	c.Assert(provisionerStarted, jc.IsTrue)
+	// and, for lxc containers, the clone template worker.
+	c.Assert(s.templateWorkerStarted, gc.Equals, ctype == instance.LXC)
+	s.templateWorkerStarted = false
 }
 
 func (s *ContainerSetupSuite) TestContainerProvisionerStarted(c *gc.C) {
@@ -178,7 +189,7 @@ func (s *ContainerSetupSuite) TestContainerProvisionerStarted(c *gc.C) {
 			Constraints: s.defaultConstraints,
 		})
 		c.Assert(err, jc.ErrorIsNil)
-		err = m.SetSupportedContainers([]instance.ContainerType{instance.LXC, instance.KVM})
+		err = m.SetSupportedContainers(instance.ContainerTypes)
 		c.Assert(err, jc.ErrorIsNil)
 		err = m.SetAgentVersion(version.Current)
 		c.Assert(err, jc.ErrorIsNil)
@@ -200,6 +211,13 @@ func (s *ContainerSetupSuite) TestKvmContainerUsesHostArch(c *gc.C) {
 	s.testContainerConstraintsArch(c, instance.KVM, arch.AMD64)
 }
 
+func (s *ContainerSetupSuite) TestLxdContainerUsesHostArch(c *gc.C) {
+	// LXD should override the architecture in constraints with the
+	// host's architecture, as LXC does.
+	s.PatchValue(&version.Current.Arch, arch.PPC64EL)
+	s.testContainerConstraintsArch(c, instance.LXD, arch.PPC64EL)
+}
+
 func (s *ContainerSetupSuite) testContainerConstraintsArch(c *gc.C, containerType instance.ContainerType, expectArch string) {
 	var called bool
 	s.PatchValue(provisioner.GetToolsFinder, func(*apiprovisioner.State) provisioner.ToolsFinder {
@@ -296,7 +314,7 @@ func (s *ContainerSetupSuite) assertContainerInitialised(c *gc.C, ctype instance
 		Constraints: s.defaultConstraints,
 	})
 	c.Assert(err, jc.ErrorIsNil)
-	err = m.SetSupportedContainers([]instance.ContainerType{instance.LXC, instance.KVM})
+	err = m.SetSupportedContainers(instance.ContainerTypes)
 	c.Assert(err, jc.ErrorIsNil)
 	err = m.SetAgentVersion(version.Current)
 	c.Assert(err, jc.ErrorIsNil)
@@ -341,6 +359,8 @@ func (s *ContainerSetupSuite) TestContainerInitialised(c *gc.C) {
 		{instance.KVM, [][]string{
 			[]string{"uvtool-libvirt"},
 			[]string{"uvtool"}}},
+		{instance.LXD, [][]string{
+			[]string{"lxd"}}},
 	} {
 		s.assertContainerInitialised(c, test.ctype, test.packages, false)
 	}
@@ -507,6 +527,7 @@ func (s *AddressableContainerSetupSuite) TestContainerInitialised(c *gc.C) {
 	}{
 		{instance.LXC, [][]string{{"--target-release", "precise-updates/cloud-tools", "lxc"}, {"--target-release", "precise-updates/cloud-tools", "cloud-image-utils"}}},
 		{instance.KVM, [][]string{{"uvtool-libvirt"}, {"uvtool"}}},
+		{instance.LXD, [][]string{{"lxd"}}},
 	} {
 		s.enableFeatureFlag()
 		s.assertContainerInitialised(c, test.ctype, test.packages, true)
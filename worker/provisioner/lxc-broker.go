@@ -11,6 +11,7 @@ import (
 	"os"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -192,6 +193,42 @@ func (broker *lxcBroker) AllInstances() (result []instance.Instance, err error)
 	return broker.manager.ListContainers()
 }
 
+// EnsureTemplates is specified on the LxcTemplateBroker interface.
+func (broker *lxcBroker) EnsureTemplates(series []string) error {
+	manager, ok := broker.manager.(lxc.TemplateManager)
+	if !ok || len(series) == 0 {
+		return nil
+	}
+	config, err := broker.api.ContainerConfig()
+	if err != nil {
+		return errors.Annotate(err, "failed to get container config")
+	}
+	bridgeDevice := broker.agentConfig.Value(agent.LxcBridge)
+	if bridgeDevice == "" {
+		bridgeDevice = lxc.DefaultLxcBridge
+	}
+	network := container.BridgeNetworkConfig(bridgeDevice, broker.defaultMTU, nil)
+	templateConfig := lxc.TemplateConfig{
+		AuthorizedKeys: config.AuthorizedKeys,
+		AptProxy:       config.AptProxy,
+		AptMirror:      config.AptMirror,
+	}
+	if config.UpdateBehavior != nil {
+		templateConfig.EnablePackageUpdates = config.EnableOSRefreshUpdate
+		templateConfig.EnableOSUpgrades = config.EnableOSUpgrade
+	}
+	return manager.EnsureTemplates(series, network, templateConfig)
+}
+
+// RemoveUnusedTemplates is specified on the LxcTemplateBroker interface.
+func (broker *lxcBroker) RemoveUnusedTemplates(maxAge time.Duration) ([]string, error) {
+	manager, ok := broker.manager.(lxc.TemplateManager)
+	if !ok {
+		return nil, nil
+	}
+	return manager.RemoveUnusedTemplates(maxAge)
+}
+
 type hostArchToolsFinder struct {
 	f ToolsFinder
 }
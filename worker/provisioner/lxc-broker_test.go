@@ -25,6 +25,7 @@ import (
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/lxc"
 	"github.com/juju/juju/container/lxc/mock"
 	lxctesting "github.com/juju/juju/container/lxc/testing"
 	containertesting "github.com/juju/juju/container/testing"
@@ -382,6 +383,44 @@ func (s *lxcBrokerSuite) TestStopInstance(c *gc.C) {
 	s.assertInstances(c)
 }
 
+func (s *lxcBrokerSuite) TestTemplates(c *gc.C) {
+	s.PatchValue(&lxc.TemplateLockDir, c.MkDir())
+	managerConfig := container.ManagerConfig{
+		container.ConfigName: "juju",
+		"log-dir":            c.MkDir(),
+		"use-clone":          "true",
+	}
+	broker, err := provisioner.NewLxcBroker(s.api, s.agentConfig, managerConfig, nil, false, 0)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The template container stops itself once it has been set up.
+	name := "juju-quantal-lxc-template"
+	template := s.ContainerFactory.New(name)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for a := coretesting.LongAttempt.Start(); a.Next(); {
+			if template.IsRunning() {
+				template.Stop()
+				return
+			}
+		}
+	}()
+	templateBroker := broker.(provisioner.LxcTemplateBroker)
+	err = templateBroker.EnsureTemplates([]string{"quantal"})
+	c.Assert(err, jc.ErrorIsNil)
+	<-done
+	c.Assert(template.IsConstructed(), jc.IsTrue)
+	s.api.CheckCalls(c, []gitjujutesting.StubCall{{
+		FuncName: "ContainerConfig",
+	}})
+
+	removed, err := templateBroker.RemoveUnusedTemplates(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(removed, gc.HasLen, 0)
+	c.Assert(template.IsConstructed(), jc.IsTrue)
+}
+
 func (s *lxcBrokerSuite) TestAllInstances(c *gc.C) {
 	lxc0 := s.startInstance(c, "1/lxc/0", nil)
 	lxc1 := s.startInstance(c, "1/lxc/1", nil)
@@ -0,0 +1,73 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/worker"
+)
+
+// LxcTemplateBroker is implemented by the LXC broker to create clone
+// templates before they are needed, and to remove unused ones.
+type LxcTemplateBroker interface {
+	// EnsureTemplates creates the clone templates for the given
+	// series which do not already exist.
+	EnsureTemplates(series []string) error
+
+	// RemoveUnusedTemplates removes the clone templates which have
+	// not been used for longer than maxAge, and returns their names.
+	RemoveUnusedTemplates(maxAge time.Duration) ([]string, error)
+}
+
+var _ LxcTemplateBroker = (*lxcBroker)(nil)
+
+// lxcTemplateInterval is how often clone templates are created for
+// the series used in the environment, and unused ones removed.
+var lxcTemplateInterval = 6 * time.Hour
+
+// NewLxcTemplateWorker returns a worker which periodically creates the
+// clone templates for the series used by LXC containers in the
+// environment, so that containers of those series start quickly, and
+// removes templates that have not been used for longer than the
+// environment's image-cache-max-age. Both are read from the container
+// manager config returned by getConfig.
+func NewLxcTemplateWorker(broker LxcTemplateBroker, getConfig func() (container.ManagerConfig, error)) worker.Worker {
+	return worker.NewPeriodicWorker(func(stop <-chan struct{}) error {
+		managerConfig, err := getConfig()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		var series []string
+		if value := managerConfig.PopValue(container.ConfigLXCTemplateSeries); value != "" {
+			series = strings.Split(value, ",")
+		}
+		if err := broker.EnsureTemplates(series); err != nil {
+			// Any missing templates will be created when they are
+			// first needed.
+			logger.Warningf("cannot create lxc clone templates: %v", err)
+		}
+		value := managerConfig.PopValue(container.ConfigLXCTemplateMaxAge)
+		if value == "" {
+			// The state server does not expire templates.
+			return nil
+		}
+		maxAge, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.Annotatef(err, "invalid %s", container.ConfigLXCTemplateMaxAge)
+		}
+		removed, err := broker.RemoveUnusedTemplates(maxAge)
+		if err != nil {
+			return errors.Annotate(err, "cannot remove unused lxc clone templates")
+		}
+		if len(removed) > 0 {
+			logger.Infof("removed unused lxc clone templates: %s", strings.Join(removed, ", "))
+		}
+		return nil
+	}, lxcTemplateInterval)
+}
@@ -0,0 +1,115 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/provisioner"
+)
+
+type lxcTemplatesSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&lxcTemplatesSuite{})
+
+type fakeTemplateBroker struct {
+	ensured chan []string
+	removed chan time.Duration
+}
+
+func newFakeTemplateBroker() *fakeTemplateBroker {
+	return &fakeTemplateBroker{
+		ensured: make(chan []string, 1),
+		removed: make(chan time.Duration, 1),
+	}
+}
+
+func (b *fakeTemplateBroker) EnsureTemplates(series []string) error {
+	b.ensured <- series
+	return errors.New("ignored")
+}
+
+func (b *fakeTemplateBroker) RemoveUnusedTemplates(maxAge time.Duration) ([]string, error) {
+	b.removed <- maxAge
+	return []string{"juju-precise-lxc-template"}, nil
+}
+
+func (s *lxcTemplatesSuite) TestWorker(c *gc.C) {
+	broker := newFakeTemplateBroker()
+	w := provisioner.NewLxcTemplateWorker(broker, func() (container.ManagerConfig, error) {
+		return container.ManagerConfig{
+			container.ConfigLXCTemplateSeries: "trusty,precise",
+			container.ConfigLXCTemplateMaxAge: "720h0m0s",
+		}, nil
+	})
+	defer w.Kill()
+
+	select {
+	case series := <-broker.ensured:
+		c.Assert(series, jc.DeepEquals, []string{"trusty", "precise"})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("templates not ensured")
+	}
+	select {
+	case maxAge := <-broker.removed:
+		c.Assert(maxAge, gc.Equals, 30*24*time.Hour)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("unused templates not removed")
+	}
+	// A failure to create templates is not fatal.
+	w.Kill()
+	c.Assert(w.Wait(), jc.ErrorIsNil)
+}
+
+func (s *lxcTemplatesSuite) TestWorkerWithoutMaxAge(c *gc.C) {
+	broker := newFakeTemplateBroker()
+	w := provisioner.NewLxcTemplateWorker(broker, func() (container.ManagerConfig, error) {
+		return container.ManagerConfig{}, nil
+	})
+	defer w.Kill()
+
+	select {
+	case series := <-broker.ensured:
+		c.Assert(series, gc.HasLen, 0)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("templates not ensured")
+	}
+	select {
+	case <-broker.removed:
+		c.Fatalf("unexpected template removal")
+	case <-time.After(coretesting.ShortWait):
+	}
+	w.Kill()
+	c.Assert(w.Wait(), jc.ErrorIsNil)
+}
+
+func (s *lxcTemplatesSuite) TestWorkerInvalidMaxAge(c *gc.C) {
+	broker := newFakeTemplateBroker()
+	w := provisioner.NewLxcTemplateWorker(broker, func() (container.ManagerConfig, error) {
+		return container.ManagerConfig{
+			container.ConfigLXCTemplateMaxAge: "a while",
+		}, nil
+	})
+	defer w.Kill()
+	err := w.Wait()
+	c.Assert(err, gc.ErrorMatches, `invalid lxc-template-max-age: .*`)
+}
+
+func (s *lxcTemplatesSuite) TestWorkerConfigError(c *gc.C) {
+	broker := newFakeTemplateBroker()
+	w := provisioner.NewLxcTemplateWorker(broker, func() (container.ManagerConfig, error) {
+		return nil, errors.New("boom")
+	})
+	defer w.Kill()
+	err := w.Wait()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -8,12 +8,36 @@ import (
 	"time"
 
 	"launchpad.net/tomb"
+
+	"github.com/juju/juju/instrumentation"
 )
 
 // RestartDelay holds the length of time that a worker
 // will wait between exiting and restarting.
 var RestartDelay = 3 * time.Second
 
+var workerRestarts = instrumentation.NewCounter(
+	"juju_worker_restarts_total",
+	"Number of times a worker was restarted by its runner.",
+	"worker",
+)
+
+// The keys and states used in the reports produced by a Runner. They
+// match those used by dependency engines, so that the same tools can
+// present both.
+const (
+	KeyState      = "state"
+	KeyError      = "error"
+	KeyWorkers    = "workers"
+	KeyStartCount = "start-count"
+	KeyReport     = "report"
+
+	StateStarting = "starting"
+	StateStarted  = "started"
+	StateStopping = "stopping"
+	StateStopped  = "stopped"
+)
+
 // Worker is implemented by a running worker.
 type Worker interface {
 	// Kill asks the worker to stop without necessarily
@@ -39,6 +63,7 @@ type runner struct {
 	stopc         chan string
 	donec         chan doneInfo
 	startedc      chan startInfo
+	reportc       chan chan runnerReport
 	isFatal       func(error) bool
 	moreImportant func(err0, err1 error) bool
 }
@@ -76,6 +101,7 @@ func NewRunner(isFatal func(error) bool, moreImportant func(err0, err1 error) bo
 		stopc:         make(chan string),
 		donec:         make(chan doneInfo),
 		startedc:      make(chan startInfo),
+		reportc:       make(chan chan runnerReport),
 		isFatal:       isFatal,
 		moreImportant: moreImportant,
 	}
@@ -133,11 +159,98 @@ func Stop(worker Worker) error {
 	return worker.Wait()
 }
 
+// Report returns a map describing the state of the runner and of each
+// of its workers. The reports of workers that are themselves
+// reporters, such as other runners, are included. It implements the
+// same Reporter interface as a dependency engine.
+func (runner *runner) Report() map[string]interface{} {
+	reply := make(chan runnerReport, 1)
+	select {
+	case runner.reportc <- reply:
+	case <-runner.tomb.Dead():
+		report := map[string]interface{}{
+			KeyState: StateStopped,
+		}
+		if err := runner.tomb.Err(); err != nil {
+			report[KeyError] = err.Error()
+		}
+		return report
+	}
+	snapshot := <-reply
+	workers := make(map[string]interface{})
+	for _, info := range snapshot.workers {
+		workers[info.id] = info.report()
+	}
+	return map[string]interface{}{
+		KeyState:   snapshot.state,
+		KeyWorkers: workers,
+	}
+}
+
+// runnerReport holds a snapshot of the state of a runner.
+type runnerReport struct {
+	state   string
+	workers []workerReport
+}
+
+// reporter is implemented by workers that can describe their state.
+type reporter interface {
+	Report() map[string]interface{}
+}
+
+// workerReport holds a snapshot of the state of one of a runner's
+// workers.
+type workerReport struct {
+	id         string
+	state      string
+	startCount int
+	err        error
+	worker     Worker
+}
+
+// report returns a map describing the worker. It may call the worker's
+// own Report method, so it must not be called from the runner's loop.
+func (r workerReport) report() map[string]interface{} {
+	report := map[string]interface{}{
+		KeyState:      r.state,
+		KeyStartCount: r.startCount,
+	}
+	if r.err != nil {
+		report[KeyError] = r.err.Error()
+	}
+	if reporter, ok := r.worker.(reporter); ok {
+		if workerReport := reporter.Report(); len(workerReport) > 0 {
+			report[KeyReport] = workerReport
+		}
+	}
+	return report
+}
+
 type workerInfo struct {
 	start        func() (Worker, error)
 	worker       Worker
 	restartDelay time.Duration
 	stopping     bool
+	startCount   int
+	lastErr      error
+}
+
+// report returns a snapshot of the worker's state.
+func (info *workerInfo) report(id string) workerReport {
+	state := StateStarting
+	switch {
+	case info.stopping:
+		state = StateStopping
+	case info.worker != nil:
+		state = StateStarted
+	}
+	return workerReport{
+		id:         id,
+		state:      state,
+		startCount: info.startCount,
+		err:        info.lastErr,
+		worker:     info.worker,
+	}
 }
 
 func (runner *runner) run() error {
@@ -187,6 +300,15 @@ func (runner *runner) run() error {
 			// the new start function.
 			info.start = req.start
 			info.restartDelay = 0
+		case reply := <-runner.reportc:
+			snapshot := runnerReport{state: StateStarted}
+			if isDying {
+				snapshot.state = StateStopping
+			}
+			for id, info := range workers {
+				snapshot.workers = append(snapshot.workers, info.report(id))
+			}
+			reply <- snapshot
 		case id := <-runner.stopc:
 			logger.Debugf("stop %q", id)
 			if info := workers[id]; info != nil {
@@ -196,12 +318,17 @@ func (runner *runner) run() error {
 			logger.Debugf("%q started", info.id)
 			workerInfo := workers[info.id]
 			workerInfo.worker = info.worker
+			workerInfo.startCount++
 			if isDying || workerInfo.stopping {
 				killWorker(info.id, workerInfo)
 			}
 		case info := <-runner.donec:
 			logger.Debugf("%q done: %v", info.id, info.err)
 			workerInfo := workers[info.id]
+			workerInfo.worker = nil
+			if info.err != nil {
+				workerInfo.lastErr = info.err
+			}
 			if !workerInfo.stopping && info.err == nil {
 				logger.Debugf("removing %q from known workers", info.id)
 				delete(workers, info.id)
@@ -231,6 +358,7 @@ func (runner *runner) run() error {
 				delete(workers, info.id)
 				break
 			}
+			workerRestarts.Inc(info.id)
 			go runner.runWorker(workerInfo.restartDelay, info.id, workerInfo.start)
 			workerInfo.restartDelay = RestartDelay
 		}
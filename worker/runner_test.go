@@ -6,6 +6,7 @@ package worker_test
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"sync/atomic"
 	"time"
 
@@ -67,6 +68,7 @@ func (*runnerSuite) TestOneWorkerFinish(c *gc.C) {
 }
 
 func (*runnerSuite) TestOneWorkerRestart(c *gc.C) {
+	restarts := worker.WorkerRestarts.Value("id")
 	runner := worker.NewRunner(noneFatal, noImportance)
 	starter := newTestWorkerStarter()
 	err := runner.StartWorker("id", testWorkerStart(starter))
@@ -79,6 +81,7 @@ func (*runnerSuite) TestOneWorkerRestart(c *gc.C) {
 		starter.assertStarted(c, false)
 		starter.assertStarted(c, true)
 	}
+	c.Assert(worker.WorkerRestarts.Value("id"), gc.Equals, restarts+3)
 
 	c.Assert(worker.Stop(runner), gc.IsNil)
 	starter.assertStarted(c, false)
@@ -342,6 +345,85 @@ type testWorkerStarter struct {
 	hook func()
 }
 
+type reporter interface {
+	Report() map[string]interface{}
+}
+
+// assertReport waits for the runner to report the expected state.
+func assertReport(c *gc.C, runner worker.Runner, expect map[string]interface{}) {
+	var report map[string]interface{}
+	for a := testing.LongAttempt.Start(); a.Next(); {
+		report = runner.(reporter).Report()
+		if reflect.DeepEqual(report, expect) {
+			return
+		}
+	}
+	c.Fatalf("unexpected report: %#v", report)
+}
+
+func (*runnerSuite) TestReport(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance)
+	defer worker.Stop(runner)
+	starter := newTestWorkerStarter()
+	err := runner.StartWorker("id", testWorkerStart(starter))
+	c.Assert(err, jc.ErrorIsNil)
+	starter.assertStarted(c, true)
+	assertReport(c, runner, map[string]interface{}{
+		"state": "started",
+		"workers": map[string]interface{}{
+			"id": map[string]interface{}{
+				"state":       "started",
+				"start-count": 1,
+			},
+		},
+	})
+
+	starter.die <- fmt.Errorf("worker died")
+	starter.assertStarted(c, false)
+	starter.assertStarted(c, true)
+	assertReport(c, runner, map[string]interface{}{
+		"state": "started",
+		"workers": map[string]interface{}{
+			"id": map[string]interface{}{
+				"state":       "started",
+				"start-count": 2,
+				"error":       "worker died",
+			},
+		},
+	})
+}
+
+func (*runnerSuite) TestReportIncludesWorkerReports(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance)
+	defer worker.Stop(runner)
+	inner := worker.NewRunner(noneFatal, noImportance)
+	err := runner.StartWorker("inner", func() (worker.Worker, error) {
+		return inner, nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	assertReport(c, runner, map[string]interface{}{
+		"state": "started",
+		"workers": map[string]interface{}{
+			"inner": map[string]interface{}{
+				"state":       "started",
+				"start-count": 1,
+				"report": map[string]interface{}{
+					"state":   "started",
+					"workers": map[string]interface{}{},
+				},
+			},
+		},
+	})
+}
+
+func (*runnerSuite) TestReportWhenDead(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance)
+	c.Assert(worker.Stop(runner), jc.ErrorIsNil)
+	c.Assert(runner.(reporter).Report(), jc.DeepEquals, map[string]interface{}{
+		"state": "stopped",
+	})
+}
+
 func newTestWorkerStarter() *testWorkerStarter {
 	return &testWorkerStarter{
 		die:         make(chan error, 1),
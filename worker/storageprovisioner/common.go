@@ -102,6 +102,18 @@ func removeAttachments(ctx *context, ids []params.MachineStorageId) error {
 	return nil
 }
 
+// attachedFromMachine reports whether or not the storage provider
+// with the given type requires that attachments of environment-scoped
+// storage be made from within the machine.
+func attachedFromMachine(providerType storage.ProviderType) (bool, error) {
+	provider, err := registry.StorageProvider(providerType)
+	if err != nil {
+		return false, errors.Annotate(err, "getting provider")
+	}
+	scoper, ok := provider.(storage.AttachmentScoper)
+	return ok && scoper.AttachmentScope() == storage.ScopeMachine, nil
+}
+
 var errNonDynamic = errors.New("non-dynamic storage provider")
 
 // volumeSource returns a volume source given a name, provider type,
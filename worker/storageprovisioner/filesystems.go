@@ -208,6 +208,10 @@ func processDyingFilesystemAttachments(
 		if err != nil {
 			return errors.Trace(err)
 		}
+		detach, attachmentParams, err = filesystemAttachmentsInScope(ctx, detach, attachmentParams)
+		if err != nil {
+			return errors.Trace(err)
+		}
 		for i, params := range attachmentParams {
 			ctx.pendingDyingFilesystemAttachments[detach[i]] = params
 		}
@@ -396,6 +400,10 @@ func processAliveFilesystemAttachments(
 	if err != nil {
 		return errors.Trace(err)
 	}
+	pending, params, err = filesystemAttachmentsInScope(ctx, pending, params)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	for i, params := range params {
 		if params.InstanceId == "" {
 			watchMachine(ctx, params.Machine)
@@ -427,6 +435,72 @@ func filesystemAttachmentParams(
 	return attachmentParams, nil
 }
 
+// filesystemAttachmentsInScope returns the IDs and parameters of those
+// of the specified filesystem attachments that the worker is responsible
+// for making.
+//
+// Attachments of machine-scoped filesystems are made by the machine's
+// storage provisioner. Attachments of environment-scoped filesystems are
+// made by the environment storage provisioner, unless the storage
+// provider requires that they be made from within each machine (see
+// storage.AttachmentScoper). The machine storage provisioner does not
+// watch environment-scoped filesystems, so in the latter case we record
+// the filesystem if the attachment parameters show it is provisioned.
+func filesystemAttachmentsInScope(
+	ctx *context,
+	ids []params.MachineStorageId,
+	attachmentParams []storage.FilesystemAttachmentParams,
+) ([]params.MachineStorageId, []storage.FilesystemAttachmentParams, error) {
+	_, machineScoped := ctx.scope.(names.MachineTag)
+	inScopeIds := make([]params.MachineStorageId, 0, len(ids))
+	inScopeParams := make([]storage.FilesystemAttachmentParams, 0, len(ids))
+	for i, params := range attachmentParams {
+		if _, ok := names.FilesystemMachine(params.Filesystem); !ok {
+			attachedFromMachine, err := attachedFromMachine(params.Provider)
+			if err != nil {
+				return nil, nil, errors.Annotatef(err, "getting attachment scope for %v", ids[i])
+			}
+			if attachedFromMachine != machineScoped {
+				logger.Debugf("%v is not managed by this worker, ignoring", ids[i])
+				continue
+			}
+			if machineScoped && params.FilesystemId != "" {
+				ctx.filesystems[params.Filesystem] = storage.Filesystem{
+					Tag: params.Filesystem,
+					FilesystemInfo: storage.FilesystemInfo{
+						FilesystemId: params.FilesystemId,
+					},
+				}
+			}
+		}
+		inScopeIds = append(inScopeIds, ids[i])
+		inScopeParams = append(inScopeParams, params)
+	}
+	return inScopeIds, inScopeParams, nil
+}
+
+// filesystemAttachmentsAwaitingFilesystems returns the IDs of pending
+// attachments, to be made by a machine storage provisioner, of
+// environment-scoped filesystems that are not yet known to have been
+// provisioned. The machine storage provisioner does not watch
+// environment-scoped filesystems, so it must enquire about such
+// attachments periodically.
+func filesystemAttachmentsAwaitingFilesystems(ctx *context) []params.MachineStorageId {
+	if _, ok := ctx.scope.(names.MachineTag); !ok {
+		return nil
+	}
+	var ids []params.MachineStorageId
+	for id, params := range ctx.pendingFilesystemAttachments {
+		if _, ok := names.FilesystemMachine(params.Filesystem); ok {
+			continue
+		}
+		if _, ok := ctx.filesystems[params.Filesystem]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 func processPendingFilesystemAttachments(ctx *context) error {
 	if len(ctx.pendingFilesystemAttachments) == 0 {
 		logger.Tracef("no pending filesystem attachments")
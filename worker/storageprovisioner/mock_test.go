@@ -271,6 +271,7 @@ type mockFilesystemAccessor struct {
 
 	setFilesystemInfo           func([]params.Filesystem) ([]params.ErrorResult, error)
 	setFilesystemAttachmentInfo func([]params.FilesystemAttachment) ([]params.ErrorResult, error)
+	filesystemAttachmentParams  func([]params.MachineStorageId) ([]params.FilesystemAttachmentParamsResult, error)
 }
 
 func (m *mockFilesystemAccessor) provisionFilesystem(tag names.FilesystemTag) params.Filesystem {
@@ -348,15 +349,20 @@ func (v *mockFilesystemAccessor) FilesystemParams(filesystems []names.Filesystem
 }
 
 func (f *mockFilesystemAccessor) FilesystemAttachmentParams(ids []params.MachineStorageId) ([]params.FilesystemAttachmentParamsResult, error) {
+	if f.filesystemAttachmentParams != nil {
+		return f.filesystemAttachmentParams(ids)
+	}
 	var result []params.FilesystemAttachmentParamsResult
 	for _, id := range ids {
 		// Parameters are returned regardless of whether the attachment
 		// exists; this is to support reattachment.
 		instanceId := f.provisionedMachines[id.MachineTag]
+		filesystem := f.provisionedFilesystems[id.AttachmentTag]
 		result = append(result, params.FilesystemAttachmentParamsResult{Result: params.FilesystemAttachmentParams{
 			MachineTag:    id.MachineTag,
 			FilesystemTag: id.AttachmentTag,
 			InstanceId:    string(instanceId),
+			FilesystemId:  filesystem.Info.FilesystemId,
 			Provider:      "dummy",
 			ReadOnly:      true,
 		}})
@@ -454,6 +460,16 @@ type dummyProvider struct {
 	destroyVolumesFunc    func([]string) []error
 }
 
+// machineAttachedProvider is a dummyProvider whose attachments
+// are made from within each machine.
+type machineAttachedProvider struct {
+	*dummyProvider
+}
+
+func (*machineAttachedProvider) AttachmentScope() storage.Scope {
+	return storage.ScopeMachine
+}
+
 type dummyVolumeSource struct {
 	storage.VolumeSource
 	provider          *dummyProvider
@@ -4,6 +4,8 @@
 package storageprovisioner
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
@@ -23,6 +25,11 @@ var logger = loggo.GetLogger("juju.worker.storageprovisioner")
 
 var newManagedFilesystemSource = provider.NewManagedFilesystemSource
 
+// filesystemRefreshInterval is the interval at which a machine storage
+// provisioner enquires about attachments of environment-scoped
+// filesystems that it is waiting to be provisioned.
+var filesystemRefreshInterval = 30 * time.Second
+
 // VolumeAccessor defines an interface used to allow a storage provisioner
 // worker to perform volume related operations.
 type VolumeAccessor interface {
@@ -297,6 +304,12 @@ func (w *storageprovisioner) loop() error {
 			return errors.Trace(err)
 		}
 
+		var filesystemRefresh <-chan time.Time
+		awaitingFilesystems := filesystemAttachmentsAwaitingFilesystems(&ctx)
+		if len(awaitingFilesystems) > 0 {
+			filesystemRefresh = time.After(filesystemRefreshInterval)
+		}
+
 		select {
 		case <-w.tomb.Dying():
 			return tomb.ErrDying
@@ -355,6 +368,10 @@ func (w *storageprovisioner) loop() error {
 			if err := refreshMachine(&ctx, machineTag); err != nil {
 				return errors.Trace(err)
 			}
+		case <-filesystemRefresh:
+			if err := filesystemAttachmentsChanged(&ctx, awaitingFilesystems); err != nil {
+				return errors.Trace(err)
+			}
 		}
 	}
 }
@@ -376,6 +376,90 @@ func (s *storageProvisionerSuite) TestFilesystemAttachmentAdded(c *gc.C) {
 	assertNoEvent(c, filesystemAttachmentInfoSet, "filesystem attachment info set")
 }
 
+func (s *storageProvisionerSuite) TestFilesystemAttachmentFromMachine(c *gc.C) {
+	registry.RegisterProvider("dummy", nil)
+	registry.RegisterProvider("dummy", &machineAttachedProvider{s.provider})
+	s.PatchValue(storageprovisioner.FilesystemRefreshInterval, time.Millisecond)
+
+	filesystemAttachmentInfoSet := make(chan interface{})
+	filesystemAccessor := newMockFilesystemAccessor()
+	filesystemAccessor.setFilesystemAttachmentInfo = func(filesystemAttachments []params.FilesystemAttachment) ([]params.ErrorResult, error) {
+		filesystemAttachmentInfoSet <- filesystemAttachments
+		return make([]params.ErrorResult, len(filesystemAttachments)), nil
+	}
+
+	// The environment-scoped filesystem is not provisioned until the
+	// machine storage provisioner has enquired about it once already;
+	// the worker must keep enquiring until it is.
+	var paramsCalls int
+	filesystemAccessor.filesystemAttachmentParams = func(ids []params.MachineStorageId) ([]params.FilesystemAttachmentParamsResult, error) {
+		paramsCalls++
+		results := make([]params.FilesystemAttachmentParamsResult, len(ids))
+		for i, id := range ids {
+			results[i].Result = params.FilesystemAttachmentParams{
+				MachineTag:    id.MachineTag,
+				FilesystemTag: id.AttachmentTag,
+				InstanceId:    "already-provisioned-0",
+				Provider:      "dummy",
+			}
+			if paramsCalls > 1 {
+				results[i].Result.FilesystemId = "nfs-1"
+			}
+		}
+		return results, nil
+	}
+
+	args := &workerArgs{
+		scope:       names.NewMachineTag("0"),
+		filesystems: filesystemAccessor,
+	}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	filesystemAccessor.attachmentsWatcher.changes <- []params.MachineStorageId{{
+		MachineTag: "machine-0", AttachmentTag: "filesystem-1",
+	}}
+	args.environ.watcher.changes <- struct{}{}
+	attachments := waitChannel(
+		c, filesystemAttachmentInfoSet,
+		"waiting for filesystem attachments to be set",
+	).([]params.FilesystemAttachment)
+	c.Assert(attachments, jc.DeepEquals, []params.FilesystemAttachment{{
+		FilesystemTag: "filesystem-1",
+		MachineTag:    "machine-0",
+		Info: params.FilesystemAttachmentInfo{
+			MountPoint: "/srv/nfs-1",
+		},
+	}})
+}
+
+func (s *storageProvisionerSuite) TestFilesystemAttachmentFromMachineIgnoredByEnviron(c *gc.C) {
+	registry.RegisterProvider("dummy", nil)
+	registry.RegisterProvider("dummy", &machineAttachedProvider{s.provider})
+
+	filesystemAttachmentInfoSet := make(chan interface{})
+	filesystemAccessor := newMockFilesystemAccessor()
+	filesystemAccessor.setFilesystemAttachmentInfo = func(filesystemAttachments []params.FilesystemAttachment) ([]params.ErrorResult, error) {
+		filesystemAttachmentInfoSet <- filesystemAttachments
+		return make([]params.ErrorResult, len(filesystemAttachments)), nil
+	}
+	filesystemAccessor.provisionFilesystem(names.NewFilesystemTag("1"))
+	filesystemAccessor.provisionedMachines["machine-0"] = instance.Id("already-provisioned-0")
+
+	args := &workerArgs{filesystems: filesystemAccessor}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	filesystemAccessor.attachmentsWatcher.changes <- []params.MachineStorageId{{
+		MachineTag: "machine-0", AttachmentTag: "filesystem-1",
+	}}
+	filesystemAccessor.filesystemsWatcher.changes <- []string{"1"}
+	args.environ.watcher.changes <- struct{}{}
+	assertNoEvent(c, filesystemAttachmentInfoSet, "filesystem attachment info set")
+}
+
 func (s *storageProvisionerSuite) TestCreateVolumeBackedFilesystem(c *gc.C) {
 	filesystemInfoSet := make(chan interface{})
 	filesystemAccessor := newMockFilesystemAccessor()
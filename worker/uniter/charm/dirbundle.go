@@ -0,0 +1,87 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/utils/set"
+	"github.com/juju/utils/symlink"
+)
+
+// dirBundle is a Bundle whose content is held, unpacked, in a directory.
+// Only the entries in its manifest are part of the bundle.
+type dirBundle struct {
+	path     string
+	manifest set.Strings
+}
+
+// Manifest is part of the Bundle interface.
+func (b *dirBundle) Manifest() (set.Strings, error) {
+	return set.NewStrings(b.manifest.Values()...), nil
+}
+
+// ExpandTo is part of the Bundle interface. It copies every entry in
+// the manifest into dir, replacing whatever is present at the same
+// path; it fails if any entry is missing from the bundle's directory.
+func (b *dirBundle) ExpandTo(dir string) error {
+	// Sorting ensures that directories are created before their contents.
+	for _, path := range b.manifest.SortedValues() {
+		src := filepath.Join(b.path, filepath.FromSlash(path))
+		dst := filepath.Join(dir, filepath.FromSlash(path))
+		if err := copyEntry(src, dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyEntry copies the file, directory or symlink at src to dst. The
+// contents of directories are not copied.
+func copyEntry(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if info.IsDir() {
+		if dstInfo, err := os.Lstat(dst); err == nil && !dstInfo.IsDir() {
+			if err := os.Remove(dst); err != nil {
+				return err
+			}
+		}
+		if err := os.MkdirAll(dst, info.Mode().Perm()); err != nil {
+			return err
+		}
+		return os.Chmod(dst, info.Mode().Perm())
+	}
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := symlink.Read(src)
+		if err != nil {
+			return err
+		}
+		return symlink.New(target, dst)
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
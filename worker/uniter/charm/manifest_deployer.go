@@ -5,6 +5,7 @@ package charm
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 
@@ -21,6 +22,11 @@ const (
 	// manifestsDataPath holds the path in the data dir where the manifest
 	// deployer stores the manifests for its charms.
 	manifestsDataPath = "manifests"
+
+	// keptCharmsDataPath holds the path in the data dir where the manifest
+	// deployer keeps a copy of the charm it last upgraded from, so that
+	// the charm can be restored without reading its bundle again.
+	keptCharmsDataPath = "kept-charms"
 )
 
 // NewManifestDeployer returns a Deployer that installs bundles from the
@@ -32,6 +38,10 @@ const (
 // that base charm. It thus leaves user files in place, with the exception of
 // those in directories referenced only in the original charm, which will be
 // deleted.
+//
+// When upgrading, it keeps a copy of the charm being replaced; if that charm
+// is staged again, for instance to roll back a failed upgrade, the copy is
+// deployed in place of the bundle read from the BundleReader.
 func NewManifestDeployer(charmPath, dataPath string, bundles BundleReader) Deployer {
 	return &manifestDeployer{
 		charmPath: charmPath,
@@ -52,15 +62,21 @@ type manifestDeployer struct {
 }
 
 func (d *manifestDeployer) Stage(info BundleInfo, abort <-chan struct{}) error {
-	bundle, err := d.bundles.Read(info, abort)
+	url := info.URL()
+	bundle, err := d.keptBundle(url)
 	if err != nil {
 		return err
 	}
+	if bundle == nil {
+		bundle, err = d.bundles.Read(info, abort)
+		if err != nil {
+			return err
+		}
+	}
 	manifest, err := bundle.Manifest()
 	if err != nil {
 		return err
 	}
-	url := info.URL()
 	if err := d.storeManifest(url, manifest); err != nil {
 		return err
 	}
@@ -82,9 +98,13 @@ func (d *manifestDeployer) Deploy() (err error) {
 	}
 	upgrading := baseURL != nil
 	defer manifestDeployError(&err, upgrading)
+	replacing := upgrading && *baseURL != *d.staged.url
 	if err := d.ensureBaseFiles(baseManifest); err != nil {
 		return err
 	}
+	if replacing {
+		d.keepCharm(baseURL, baseManifest)
+	}
 
 	// Write or overwrite the deploying URL to point to the staged one.
 	if err := d.startDeploy(); err != nil {
@@ -105,7 +125,13 @@ func (d *manifestDeployer) Deploy() (err error) {
 	}
 
 	// Move the deploying file over the charm URL file, and we're done.
-	return d.finishDeploy()
+	if err := d.finishDeploy(); err != nil {
+		return err
+	}
+	if replacing {
+		d.removeKeptCharms(baseURL)
+	}
+	return nil
 }
 
 func (d *manifestDeployer) NotifyResolved() error {
@@ -176,6 +202,96 @@ func (d *manifestDeployer) ensureBaseFiles(baseManifest set.Strings) error {
 	return err
 }
 
+// keepCharm copies the files of the deployed charm, identified by url and
+// manifest, into dataPath, unless a copy is already kept. The copy is not
+// made if a deploy was interrupted, because the charm's files may have
+// been overwritten; but in that case the copy will have been made before
+// the interrupted deploy started. Failure to keep a copy is not fatal:
+// the charm's bundle can still be read if it is needed again.
+func (d *manifestDeployer) keepCharm(url *charm.URL, manifest set.Strings) {
+	if manifest.Size() == 0 {
+		// Without a manifest, the charm's files are unknown.
+		return
+	}
+	keptPath := d.keptCharmPath(url)
+	if _, err := os.Stat(keptPath); err == nil {
+		return
+	}
+	if _, err := os.Stat(d.CharmPath(deployingURLPath)); err == nil {
+		return
+	}
+	logger.Debugf("keeping a copy of charm %q", url)
+	err := func() error {
+		if err := os.MkdirAll(d.DataPath(keptCharmsDataPath), 0755); err != nil {
+			return err
+		}
+		tmpPath, err := ioutil.TempDir(d.DataPath(keptCharmsDataPath), "tmp-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpPath)
+		source := &dirBundle{path: d.charmPath, manifest: manifest}
+		if err := source.ExpandTo(tmpPath); err != nil {
+			return err
+		}
+		if err := WriteCharmURL(filepath.Join(tmpPath, charmURLPath), url); err != nil {
+			return err
+		}
+		return os.Rename(tmpPath, keptPath)
+	}()
+	if err != nil {
+		logger.Warningf("cannot keep a copy of charm %q: %v", url, err)
+	}
+}
+
+// keptBundle returns a Bundle holding the kept copy of the charm with the
+// supplied url, or nil if no copy of that charm is kept.
+func (d *manifestDeployer) keptBundle(url *charm.URL) (Bundle, error) {
+	keptPath := d.keptCharmPath(url)
+	keptURL, err := ReadCharmURL(filepath.Join(keptPath, charmURLPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if *keptURL != *url {
+		return nil, nil
+	}
+	path := filepath.Join(d.DataPath(manifestsDataPath), charm.Quote(url.String()))
+	manifest := []string{}
+	if err := utils.ReadYaml(path, &manifest); os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	logger.Infof("using kept copy of charm %q", url)
+	return &dirBundle{path: keptPath, manifest: set.NewStrings(manifest...)}, nil
+}
+
+// removeKeptCharms removes every kept charm copy, and any partial copy,
+// except that of the charm with the supplied url.
+func (d *manifestDeployer) removeKeptCharms(url *charm.URL) {
+	paths, err := filepath.Glob(filepath.Join(d.DataPath(keptCharmsDataPath), "*"))
+	if err != nil {
+		return
+	}
+	keptPath := d.keptCharmPath(url)
+	for _, path := range paths {
+		if path == keptPath {
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			logger.Warningf("cannot remove kept charm copy at %s: %v", path, err)
+		}
+	}
+}
+
+// keptCharmPath returns the path in dataPath at which a copy of the charm
+// with the supplied url is kept.
+func (d *manifestDeployer) keptCharmPath(url *charm.URL) string {
+	return filepath.Join(d.DataPath(keptCharmsDataPath), charm.Quote(url.String()))
+}
+
 // storeManifest stores, into dataPath, the supplied manifest for the supplied charm.
 func (d *manifestDeployer) storeManifest(url *charm.URL, manifest set.Strings) error {
 	if err := os.MkdirAll(d.DataPath(manifestsDataPath), 0755); err != nil {
@@ -264,3 +264,49 @@ func (s *ManifestDeployerSuite) TestUpgradeConflictRevertRetryDifferentCharm(c *
 	ft.Removed{"old-file"}.Check(c, s.targetPath)
 	ft.Removed{"bad-file"}.Check(c, s.targetPath)
 }
+
+func (s *ManifestDeployerSuite) TestRollbackUsesKeptCharm(c *gc.C) {
+	//TODO(bogdanteleaga): Fix this on windows
+	if runtime.GOOS == "windows" {
+		c.Skip("bug 1403084: cannot symlink to relative paths on windows")
+	}
+	originalContent := ft.Entries{
+		ft.File{"shared-file", "old", 0755},
+		ft.File{"old-file", "old", 0644},
+		ft.Dir{"old-dir", 0755},
+		ft.Symlink{"old-dir/some-link", "../old-file"},
+	}
+	info := s.deployCharm(c, 1, originalContent...)
+	userFile := ft.File{"user-file", "user", 0644}.Create(c, s.targetPath)
+	s.deployCharm(c, 2,
+		ft.File{"shared-file", "new", 0644},
+		ft.File{"new-file", "new", 0644},
+	)
+
+	// The original charm is restored without reading its bundle.
+	delete(s.bundles.bundles, charmURL(1).String())
+	err := s.deployer.Stage(info, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.deployer.Deploy()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCharm(c, 1, originalContent...)
+	ft.Removed{"new-file"}.Check(c, s.targetPath)
+	userFile.Check(c, s.targetPath)
+}
+
+func (s *ManifestDeployerSuite) TestOnlyLastCharmKept(c *gc.C) {
+	info1 := s.deployCharm(c, 1, ft.File{"some-file", "one", 0644})
+	info2 := s.deployCharm(c, 2, ft.File{"some-file", "two", 0644})
+	s.deployCharm(c, 3, ft.File{"some-file", "three", 0644})
+	delete(s.bundles.bundles, charmURL(1).String())
+	delete(s.bundles.bundles, charmURL(2).String())
+
+	err := s.deployer.Stage(info1, nil)
+	c.Assert(err, gc.ErrorMatches, "no such charm!")
+
+	err = s.deployer.Stage(info2, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.deployer.Deploy()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCharm(c, 2, ft.File{"some-file", "two", 0644})
+}
@@ -18,6 +18,7 @@ var (
 	ActiveCollectMetricsTimer = &activeCollectMetricsTimer
 	ActiveSendMetricsTimer    = &activeSendMetricsTimer
 	IdleWaitTime              = &idleWaitTime
+	HookRetryInitialDelay     = &hookRetryInitialDelay
 	LeadershipGuarantee       = &leadershipGuarantee
 )
 
@@ -63,3 +64,7 @@ func UpdateStatusSignal(now, lastSignal time.Time, interval time.Duration) <-cha
 func ActiveCollectMetricsSignal(now, lastSignal time.Time, interval time.Duration) <-chan time.Time {
 	return activeCollectMetricsTimer(now, lastSignal, interval)
 }
+
+func HookRetryDelay(maxAttempts int, maxDelay time.Duration, attempt int) (time.Duration, bool) {
+	return hookRetryStrategy{maxAttempts, maxDelay}.delay(attempt)
+}
@@ -0,0 +1,55 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// hookRetryInitialDelay is how long the uniter waits before first
+// retrying a failed hook automatically. The delay doubles after each
+// further failure, up to the environment's hook-retry-max-delay.
+var hookRetryInitialDelay = 5 * time.Second
+
+// hookRetryStrategy determines when a failed hook is retried without
+// waiting for the user to resolve it.
+type hookRetryStrategy struct {
+	maxAttempts int
+	maxDelay    time.Duration
+}
+
+// hookRetryStrategy returns the environment's strategy for retrying
+// failed hooks. It allows no attempts when automatic retries are not
+// enabled.
+func (u *Uniter) hookRetryStrategy() (hookRetryStrategy, error) {
+	cfg, err := u.st.EnvironConfig()
+	if err != nil {
+		return hookRetryStrategy{}, errors.Annotate(err, "cannot read environment config")
+	}
+	if !cfg.AutomaticallyRetryHooks() {
+		return hookRetryStrategy{}, nil
+	}
+	return hookRetryStrategy{
+		maxAttempts: cfg.HookRetryMaxAttempts(),
+		maxDelay:    cfg.HookRetryMaxDelay(),
+	}, nil
+}
+
+// delay returns how long to wait before the given retry attempt,
+// counting from zero, and whether that attempt should be made at all.
+func (s hookRetryStrategy) delay(attempt int) (time.Duration, bool) {
+	if attempt >= s.maxAttempts {
+		return 0, false
+	}
+	delay := hookRetryInitialDelay
+	for i := 0; i < attempt && delay < s.maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > s.maxDelay {
+		delay = s.maxDelay
+	}
+	return delay, true
+}
@@ -0,0 +1,45 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter"
+)
+
+type HookRetrySuite struct{}
+
+var _ = gc.Suite(&HookRetrySuite{})
+
+func (s *HookRetrySuite) TestDelay(c *gc.C) {
+	for attempt, expect := range []time.Duration{
+		5 * time.Second,
+		10 * time.Second,
+		20 * time.Second,
+		40 * time.Second,
+		time.Minute,
+		time.Minute,
+	} {
+		delay, ok := uniter.HookRetryDelay(6, time.Minute, attempt)
+		c.Check(ok, jc.IsTrue)
+		c.Check(delay, gc.Equals, expect)
+	}
+	_, ok := uniter.HookRetryDelay(6, time.Minute, 6)
+	c.Check(ok, jc.IsFalse)
+}
+
+func (s *HookRetrySuite) TestDelayNotEnabled(c *gc.C) {
+	_, ok := uniter.HookRetryDelay(0, time.Minute, 0)
+	c.Check(ok, jc.IsFalse)
+}
+
+func (s *HookRetrySuite) TestDelayShorterThanInitial(c *gc.C) {
+	delay, ok := uniter.HookRetryDelay(1, time.Second, 0)
+	c.Check(ok, jc.IsTrue)
+	c.Check(delay, gc.Equals, time.Second)
+}
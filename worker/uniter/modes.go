@@ -470,6 +470,33 @@ func ModeHookError(u *Uniter) (next Mode, err error) {
 	statusData["hook"] = hookName
 	statusMessage := fmt.Sprintf("hook failed: %q", hookName)
 
+	// If the environment allows it, retry the hook automatically, waiting
+	// longer after each failure; once the attempts are exhausted we wait
+	// for the user to resolve the error as usual. The attempts made are
+	// counted in the operation state, so a restart does not reset them.
+	// The strategy is read again after every failure, so changes to the
+	// environment's settings apply to the next attempt.
+	var retry hookRetryStrategy
+	var retryHook <-chan time.Time
+	scheduleRetry := func() error {
+		retryHook = nil
+		strategy, err := u.hookRetryStrategy()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		retry = strategy
+		if delay, ok := retry.delay(u.operationState().HookRetries); ok {
+			retryHook = time.After(delay)
+			statusMessage = fmt.Sprintf("hook failed: %q, retrying in %v", hookName, delay)
+		} else {
+			statusMessage = fmt.Sprintf("hook failed: %q", hookName)
+		}
+		return nil
+	}
+	if err := scheduleRetry(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	// Run the select loop.
 	u.f.WantResolvedEvent()
 	u.f.WantUpgradeEvent(true)
@@ -502,6 +529,24 @@ func ModeHookError(u *Uniter) (next Mode, err error) {
 			}
 			err := u.runOperation(creator)
 			if errors.Cause(err) == operation.ErrHookFailed {
+				// A manual retry starts a fresh run of automatic
+				// attempts.
+				if err := scheduleRetry(); err != nil {
+					return nil, errors.Trace(err)
+				}
+				continue
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return ModeContinue, nil
+		case <-retryHook:
+			attempt := u.operationState().HookRetries + 1
+			logger.Infof("retrying hook %q (attempt %d of %d)", hookName, attempt, retry.maxAttempts)
+			err := u.runOperation(newAutoRetryHookOp(hookInfo))
+			if errors.Cause(err) == operation.ErrHookFailed {
+				if err := scheduleRetry(); err != nil {
+					return nil, errors.Trace(err)
+				}
 				continue
 			} else if err != nil {
 				return nil, errors.Trace(err)
@@ -110,6 +110,44 @@ func (opc *operationCallbacks) FailAction(actionId, message string) error {
 	return err
 }
 
+// WatchActionCancellation is part of the operation.Callbacks interface.
+func (opc *operationCallbacks) WatchActionCancellation(actionId string, abort <-chan struct{}) (<-chan struct{}, error) {
+	w, err := opc.u.unit.WatchActionCancellations()
+	if errors.IsNotImplemented(err) {
+		// The API server cannot report cancellations, so the action
+		// can only be stopped by its timeout.
+		logger.Warningf("cannot watch for cancellation of action %q: %v", actionId, err)
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cancelled := make(chan struct{})
+	go func() {
+		defer func() {
+			if err := w.Stop(); err != nil {
+				logger.Errorf("stopping action cancellation watcher: %v", err)
+			}
+		}()
+		for {
+			select {
+			case <-abort:
+				return
+			case ids, ok := <-w.Changes():
+				if !ok {
+					return
+				}
+				for _, id := range ids {
+					if id == actionId {
+						close(cancelled)
+						return
+					}
+				}
+			}
+		}
+	}()
+	return cancelled, nil
+}
+
 // GetArchiveInfo is part of the operation.Callbacks interface.
 func (opc *operationCallbacks) GetArchiveInfo(charmURL *corecharm.URL) (charm.BundleInfo, error) {
 	ch, err := opc.u.st.Charm(charmURL)
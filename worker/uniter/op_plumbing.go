@@ -64,6 +64,12 @@ func newRetryHookOp(hookInfo hook.Info) creator {
 	}
 }
 
+func newAutoRetryHookOp(hookInfo hook.Info) creator {
+	return func(factory operation.Factory) (operation.Operation, error) {
+		return factory.NewAutoRetryHook(hookInfo)
+	}
+}
+
 func newSkipHookOp(hookInfo hook.Info) creator {
 	return func(factory operation.Factory) (operation.Operation, error) {
 		return factory.NewSkipHook(hookInfo)
@@ -115,6 +115,15 @@ func (f *factory) NewRetryHook(hookInfo hook.Info) (Operation, error) {
 	return f.newResolved(hookOp)
 }
 
+// NewAutoRetryHook is part of the Factory interface.
+func (f *factory) NewAutoRetryHook(hookInfo hook.Info) (Operation, error) {
+	hookOp, err := f.NewRunHook(hookInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &autoRetryOperation{hookOp}, nil
+}
+
 // NewSkipHook is part of the Factory interface.
 func (f *factory) NewSkipHook(hookInfo hook.Info) (Operation, error) {
 	hookOp, err := f.NewRunHook(hookInfo)
@@ -183,6 +183,10 @@ func (s *FactorySuite) TestNewHookError_Retry(c *gc.C) {
 	s.testNewHookError(c, (operation.Factory).NewRetryHook)
 }
 
+func (s *FactorySuite) TestNewHookError_AutoRetry(c *gc.C) {
+	s.testNewHookError(c, (operation.Factory).NewAutoRetryHook)
+}
+
 func (s *FactorySuite) TestNewHookError_Skip(c *gc.C) {
 	s.testNewHookError(c, (operation.Factory).NewSkipHook)
 }
@@ -202,6 +206,12 @@ func (s *FactorySuite) TestNewHookString_Retry(c *gc.C) {
 	c.Check(op.String(), gc.Equals, "clear resolved flag and run relation-broken (123) hook")
 }
 
+func (s *FactorySuite) TestNewHookString_AutoRetry(c *gc.C) {
+	op, err := s.factory.NewAutoRetryHook(hook.Info{Kind: hooks.Install})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(op.String(), gc.Equals, "automatically retry run install hook")
+}
+
 func (s *FactorySuite) TestNewHookString_Skip(c *gc.C) {
 	op, err := s.factory.NewSkipHook(hook.Info{
 		Kind:       hooks.RelationJoined,
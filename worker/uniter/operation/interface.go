@@ -87,6 +87,11 @@ type Factory interface {
 	// re-execute the supplied hook.
 	NewRetryHook(hookInfo hook.Info) (Operation, error)
 
+	// NewAutoRetryHook creates an operation to re-execute the supplied hook
+	// without the user resolving its failure, counting the attempt in the
+	// unit's state.
+	NewAutoRetryHook(hookInfo hook.Info) (Operation, error)
+
 	// NewSkipHook creates an operation to clear the unit's resolved flag, and
 	// mark the supplied hook as completed successfully.
 	NewSkipHook(hookInfo hook.Info) (Operation, error)
@@ -169,6 +174,11 @@ type Callbacks interface {
 	// RunActions operations.
 	FailAction(actionId, message string) error
 
+	// WatchActionCancellation returns a channel that is closed if the
+	// supplied action is cancelled, until abort is closed. It's only used
+	// by RunAction operations.
+	WatchActionCancellation(actionId string, abort <-chan struct{}) (<-chan struct{}, error)
+
 	// GetArchiveInfo is used to find out how to download a charm archive. It's
 	// only used by Deploy operations.
 	GetArchiveInfo(charmURL *corecharm.URL) (charm.BundleInfo, error)
@@ -0,0 +1,28 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operation
+
+import (
+	"fmt"
+)
+
+type autoRetryOperation struct {
+	Operation
+}
+
+// String is part of the Operation interface.
+func (op *autoRetryOperation) String() string {
+	return fmt.Sprintf("automatically retry %s", op.Operation)
+}
+
+// Prepare counts the attempt in the returned state, so that the count
+// survives a restart of the uniter.
+// Prepare is part of the Operation interface.
+func (op *autoRetryOperation) Prepare(state State) (*State, error) {
+	newState, err := op.Operation.Prepare(state)
+	if newState != nil {
+		newState.HookRetries = state.HookRetries + 1
+	}
+	return newState, err
+}
@@ -5,6 +5,7 @@ package operation
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 
@@ -17,8 +18,9 @@ type runAction struct {
 	callbacks     Callbacks
 	runnerFactory runner.Factory
 
-	name   string
-	runner runner.Runner
+	name    string
+	timeout time.Duration
+	runner  runner.Runner
 
 	RequiresMachineLock
 }
@@ -50,6 +52,7 @@ func (ra *runAction) Prepare(state State) (*State, error) {
 		return nil, errors.Trace(err)
 	}
 	ra.name = actionData.Name
+	ra.timeout = actionData.Timeout
 	ra.runner = rnr
 	return stateChange{
 		Kind:     RunAction,
@@ -68,7 +71,7 @@ func (ra *runAction) Execute(state State) (*State, error) {
 		return nil, err
 	}
 
-	err := ra.runner.RunAction(ra.name)
+	err := ra.run()
 	if err != nil {
 		// This indicates an actual error -- an action merely failing should
 		// be handled inside the Runner, and returned as nil.
@@ -82,6 +85,44 @@ func (ra *runAction) Execute(state State) (*State, error) {
 	}.apply(state), nil
 }
 
+// run runs the action, stopping it if it is cancelled or runs for
+// longer than its timeout.
+func (ra *runAction) run() error {
+	abort := make(chan struct{})
+	defer close(abort)
+	cancelled, err := ra.callbacks.WatchActionCancellation(ra.actionId, abort)
+	if err != nil {
+		return errors.Annotate(err, "cannot watch for cancellation")
+	}
+	var timeout <-chan time.Time
+	if ra.timeout > 0 {
+		timeout = time.After(ra.timeout)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- ra.runner.RunAction(ra.name)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-cancelled:
+		return ra.stop(runner.ErrActionCancelled, done)
+	case <-timeout:
+		return ra.stop(errors.Errorf("action timed out after %v", ra.timeout), done)
+	}
+}
+
+// stop kills the running action for the supplied reason, and waits
+// for it to finish.
+func (ra *runAction) stop(reason error, done <-chan error) error {
+	logger.Infof("stopping action %s: %v", ra.actionId, reason)
+	if err := ra.runner.Context().StopAction(reason); err != nil {
+		logger.Warningf("cannot stop action %s: %v", ra.actionId, err)
+	}
+	return <-done
+}
+
 // Commit preserves the recorded hook, and returns a neutral state.
 // Commit is part of the Operation interface.
 func (ra *runAction) Commit(state State) (*State, error) {
@@ -4,6 +4,8 @@
 package operation_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -187,6 +189,67 @@ func (s *RunActionSuite) TestExecuteSuccess(c *gc.C) {
 	}
 }
 
+func (s *RunActionSuite) newStoppableAction(c *gc.C, timeout time.Duration, callbacks *RunActionCallbacks) (operation.Operation, *MockContext) {
+	stopped := make(chan struct{})
+	ctx := &MockContext{
+		actionData: &runner.ActionData{Name: "some-action-name", Timeout: timeout},
+		stopped:    stopped,
+	}
+	runnerFactory := &MockRunnerFactory{
+		MockNewActionRunner: &MockNewActionRunner{
+			runner: &MockRunner{
+				MockRunAction: &MockRunAction{block: stopped},
+				context:       ctx,
+			},
+		},
+	}
+	factory := operation.NewFactory(operation.FactoryParams{
+		RunnerFactory: runnerFactory,
+		Callbacks:     callbacks,
+	})
+	op, err := factory.NewAction(someActionId)
+	c.Assert(err, jc.ErrorIsNil)
+	return op, ctx
+}
+
+func (s *RunActionSuite) TestExecuteCancelled(c *gc.C) {
+	callbacks := &RunActionCallbacks{cancelled: make(chan struct{})}
+	op, ctx := s.newStoppableAction(c, 0, callbacks)
+	midState, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	close(callbacks.cancelled)
+	newState, err := op.Execute(*midState)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newState, jc.DeepEquals, &operation.State{
+		Kind:     operation.RunAction,
+		Step:     operation.Done,
+		ActionId: &someActionId,
+	})
+	c.Assert(ctx.stopReason, gc.Equals, runner.ErrActionCancelled)
+}
+
+func (s *RunActionSuite) TestExecuteTimeout(c *gc.C) {
+	callbacks := &RunActionCallbacks{}
+	op, ctx := s.newStoppableAction(c, time.Millisecond, callbacks)
+	midState, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = op.Execute(*midState)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.stopReason, gc.ErrorMatches, "action timed out after 1ms")
+}
+
+func (s *RunActionSuite) TestExecuteWatchError(c *gc.C) {
+	callbacks := &RunActionCallbacks{watchErr: errors.New("blam")}
+	op, _ := s.newStoppableAction(c, 0, callbacks)
+	midState, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = op.Execute(*midState)
+	c.Assert(err, gc.ErrorMatches, `running action "some-action-name": cannot watch for cancellation: blam`)
+}
+
 func (s *RunActionSuite) TestCommit(c *gc.C) {
 	var stateChangeTests = []struct {
 		description string
@@ -58,11 +58,13 @@ func (rh *runHook) Prepare(state State) (*State, error) {
 	rh.name = name
 	rh.runner = rnr
 
-	return stateChange{
+	newState := stateChange{
 		Kind: RunHook,
 		Step: Pending,
 		Hook: &rh.info,
-	}.apply(state), nil
+	}.apply(state)
+	newState.HookRetries = 0
+	return newState, nil
 }
 
 // RunningHookMessage returns the info message to print when running a hook.
@@ -207,6 +209,7 @@ func (rh *runHook) Commit(state State) (*State, error) {
 	}
 
 	newState := change.apply(state)
+	newState.HookRetries = 0
 
 	switch rh.info.Kind {
 	case hooks.Start:
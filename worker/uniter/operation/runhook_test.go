@@ -152,6 +152,47 @@ func (s *RunHookSuite) TestPrepareSuccess_BlankSlate(c *gc.C) {
 	}
 }
 
+func (s *RunHookSuite) TestPrepareSuccess_AutoRetry(c *gc.C) {
+	s.testPrepareSuccess(c,
+		(operation.Factory).NewAutoRetryHook,
+		operation.State{
+			Kind:        operation.RunHook,
+			Step:        operation.Pending,
+			Hook:        &hook.Info{Kind: hooks.ConfigChanged},
+			HookRetries: 2,
+		},
+		operation.State{
+			Kind:        operation.RunHook,
+			Step:        operation.Pending,
+			Hook:        &hook.Info{Kind: hooks.ConfigChanged},
+			HookRetries: 3,
+		},
+	)
+}
+
+func (s *RunHookSuite) TestPrepareSuccess_ResetsHookRetries(c *gc.C) {
+	for i, newHook := range []newHook{
+		(operation.Factory).NewRunHook,
+		(operation.Factory).NewRetryHook,
+	} {
+		c.Logf("variant %d", i)
+		s.testPrepareSuccess(c,
+			newHook,
+			operation.State{
+				Kind:        operation.RunHook,
+				Step:        operation.Pending,
+				Hook:        &hook.Info{Kind: hooks.ConfigChanged},
+				HookRetries: 2,
+			},
+			operation.State{
+				Kind: operation.RunHook,
+				Step: operation.Pending,
+				Hook: &hook.Info{Kind: hooks.ConfigChanged},
+			},
+		)
+	}
+}
+
 func (s *RunHookSuite) TestPrepareSuccess_Preserve(c *gc.C) {
 	for i, newHook := range []newHook{
 		(operation.Factory).NewRunHook,
@@ -83,6 +83,11 @@ type State struct {
 	// upgrade is complete (instead of running an upgrade-charm hook).
 	Hook *hook.Info `yaml:"hook,omitempty"`
 
+	// HookRetries counts the automatic retries made of the hook held in
+	// Hook since it first failed. It is reset whenever a hook is prepared
+	// or committed by any other means.
+	HookRetries int `yaml:"hook-retries,omitempty"`
+
 	// ActionId holds action information relevant to the current operation. If
 	// Kind is Continue, it holds the last action that was executed; if Kind is
 	// RunAction, it holds the running action.
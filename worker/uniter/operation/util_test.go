@@ -127,6 +127,8 @@ type RunActionCallbacks struct {
 	operation.Callbacks
 	*MockFailAction
 	executingMessage string
+	cancelled        chan struct{}
+	watchErr         error
 }
 
 func (cb *RunActionCallbacks) FailAction(actionId, message string) error {
@@ -138,6 +140,10 @@ func (cb *RunActionCallbacks) SetExecutingStatus(message string) error {
 	return nil
 }
 
+func (cb *RunActionCallbacks) WatchActionCancellation(actionId string, abort <-chan struct{}) (<-chan struct{}, error) {
+	return cb.cancelled, cb.watchErr
+}
+
 type RunCommandsCallbacks struct {
 	operation.Callbacks
 	executingMessage string
@@ -297,6 +303,8 @@ type MockContext struct {
 	actionData      *runner.ActionData
 	setStatusCalled bool
 	status          jujuc.StatusInfo
+	stopReason      error
+	stopped         chan struct{}
 }
 
 func (mock *MockContext) ActionData() (*runner.ActionData, error) {
@@ -306,6 +314,14 @@ func (mock *MockContext) ActionData() (*runner.ActionData, error) {
 	return mock.actionData, nil
 }
 
+func (mock *MockContext) StopAction(reason error) error {
+	mock.stopReason = reason
+	if mock.stopped != nil {
+		close(mock.stopped)
+	}
+	return nil
+}
+
 func (mock *MockContext) HasExecutionSetUnitStatus() bool {
 	return mock.setStatusCalled
 }
@@ -327,10 +343,14 @@ func (mock *MockContext) UnitStatus() (*jujuc.StatusInfo, error) {
 type MockRunAction struct {
 	gotName *string
 	err     error
+	block   <-chan struct{}
 }
 
 func (mock *MockRunAction) Call(actionName string) error {
 	mock.gotName = &actionName
+	if mock.block != nil {
+		<-mock.block
+	}
 	return mock.err
 }
 
@@ -4,6 +4,8 @@
 package runner
 
 import (
+	"time"
+
 	"github.com/juju/names"
 )
 
@@ -12,6 +14,7 @@ type ActionData struct {
 	Name           string
 	Tag            names.ActionTag
 	Params         map[string]interface{}
+	Timeout        time.Duration
 	Failed         bool
 	ResultsMessage string
 	ResultsMap     map[string]interface{}
@@ -144,6 +144,9 @@ type HookContext struct {
 	// like a juju-run command or a hook
 	process *os.Process
 
+	// stopReason records why a running action was stopped, if it was.
+	stopReason error
+
 	// rebootPriority tells us when the hook wants to reboot. If rebootPriority is jujuc.RebootNow
 	// the hook will be killed and requeued
 	rebootPriority jujuc.RebootPriority
@@ -205,6 +208,30 @@ func (ctx *HookContext) SetProcess(process *os.Process) {
 	mutex.Lock()
 	defer mutex.Unlock()
 	ctx.process = process
+	if process != nil && ctx.stopReason != nil {
+		// The action was stopped before its process started.
+		if err := killProcessTree(process); err != nil {
+			logger.Warningf("cannot kill action process %d: %v", process.Pid, err)
+		}
+	}
+}
+
+// StopAction kills the running action's process and any processes it
+// started, and records the reason it was stopped, which determines how
+// the action's results are reported. If the action's process has not
+// yet started, it will be killed as soon as it does.
+func (ctx *HookContext) StopAction(reason error) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if ctx.actionData == nil {
+		return errors.New("not running an action")
+	}
+	ctx.stopReason = reason
+	if ctx.process == nil {
+		return nil
+	}
+	logger.Infof("stopping action process %d: %v", ctx.process.Pid, reason)
+	return killProcessTree(ctx.process)
 }
 
 func (ctx *HookContext) Id() string {
@@ -693,6 +720,19 @@ func (ctx *HookContext) finalizeAction(err, unhandledErr error) error {
 		status = params.ActionFailed
 	}
 
+	mutex.Lock()
+	stopReason := ctx.stopReason
+	mutex.Unlock()
+	switch stopReason {
+	case nil:
+	case ErrActionCancelled:
+		// The cancellation has already been recorded in state.
+		return unhandledErr
+	default:
+		message = stopReason.Error()
+		status = params.ActionFailed
+	}
+
 	callErr := ctx.state.ActionFinish(tag, status, results, message)
 	if callErr != nil {
 		unhandledErr = errors.Wrap(unhandledErr, callErr)
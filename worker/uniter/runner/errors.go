@@ -13,6 +13,7 @@ var ErrRequeueAndReboot = errors.New("reboot now")
 var ErrReboot = errors.New("reboot after hook")
 var ErrNoProcess = errors.New("no process to kill")
 var ErrActionNotAvailable = errors.New("action no longer available")
+var ErrActionCancelled = errors.New("action cancelled")
 
 type missingHookError struct {
 	hookName string
@@ -243,7 +243,10 @@ func (f *factory) NewActionRunner(actionId string) (Runner, error) {
 	}
 
 	err = f.state.ActionBegin(tag)
-	if err != nil {
+	if params.IsCodeActionNotAvailable(err) {
+		// The action was cancelled after we fetched it.
+		return nil, ErrActionNotAvailable
+	} else if err != nil {
 		return nil, errors.Trace(err)
 	}
 
@@ -262,6 +265,7 @@ func (f *factory) NewActionRunner(actionId string) (Runner, error) {
 		return nil, errors.Trace(err)
 	}
 	ctx.actionData = newActionData(name, &tag, params)
+	ctx.actionData.Timeout = action.Timeout()
 	ctx.id = f.newId(name)
 	runner := NewRunner(ctx, f.paths)
 	return runner, nil
@@ -17,6 +17,8 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v5/hooks"
 
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/storage"
@@ -624,6 +626,46 @@ func (s *FactorySuite) TestNewActionRunnerMissingAction(c *gc.C) {
 	c.Check(err, gc.Equals, runner.ErrActionNotAvailable)
 }
 
+func (s *FactorySuite) TestNewActionRunnerCancelledBeforeBegin(c *gc.C) {
+	s.SetCharm(c, "dummy")
+	action, err := s.State.EnqueueAction(s.unit.Tag(), "snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	caller := &beginInterceptor{
+		APICaller: s.st,
+		beforeBegin: func() {
+			_, err := s.unit.CancelAction(action)
+			c.Assert(err, jc.ErrorIsNil)
+		},
+	}
+	factory, err := runner.NewFactory(
+		uniter.NewState(caller, s.unit.UnitTag()),
+		s.unit.UnitTag(),
+		fakeTracker{},
+		s.getRelationInfos,
+		s.storage,
+		s.paths,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	rnr, err := factory.NewActionRunner(action.Id())
+	c.Check(rnr, gc.IsNil)
+	c.Check(err, gc.Equals, runner.ErrActionNotAvailable)
+}
+
+// beginInterceptor calls beforeBegin before passing on a BeginActions
+// call, so that tests can change an action after the factory has read it.
+type beginInterceptor struct {
+	base.APICaller
+	beforeBegin func()
+}
+
+func (i *beginInterceptor) APICall(objType string, version int, id, request string, args, response interface{}) error {
+	if request == "BeginActions" {
+		i.beforeBegin()
+	}
+	return i.APICaller.APICall(objType, version, id, request, args, response)
+}
+
 func (s *FactorySuite) TestNewActionRunnerUnauthAction(c *gc.C) {
 	s.SetCharm(c, "dummy")
 	otherUnit, err := s.service.AddUnit()
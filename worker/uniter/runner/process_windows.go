@@ -0,0 +1,23 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package runner
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// setProcessGroup does nothing on windows, where killProcessTree finds
+// a process's children without the help of a process group.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessTree kills the supplied process and all of its children.
+func killProcessTree(proc *os.Process) error {
+	err := exec.Command("taskkill", "/F", "/T", "/PID", strconv.Itoa(proc.Pid)).Run()
+	if err != nil {
+		return proc.Kill()
+	}
+	return nil
+}
@@ -212,6 +212,69 @@ func (s *UniterSuite) TestUniterInstallHook(c *gc.C) {
 	})
 }
 
+func (s *UniterSuite) TestUniterHookRetries(c *gc.C) {
+	s.PatchValue(uniter.HookRetryInitialDelay, 10*time.Millisecond)
+	s.runUniterTests(c, []uniterTest{
+		ut(
+			"install hook fail, retry automatically until attempts run out, and resolve",
+			setHookRetries{maxAttempts: 2, maxDelay: "20ms"},
+			createCharm{badHooks: []string{"install"}},
+			serveCharm{},
+			createUniter{},
+			waitHooks{"fail-install", "fail-install", "fail-install"},
+			waitUnitAgent{
+				statusGetter: unitStatusGetter,
+				status:       params.StatusError,
+				info:         `hook failed: "install"`,
+				data: map[string]interface{}{
+					"hook": "install",
+				},
+			},
+			waitHooks{},
+			fixHook{"install"},
+
+			resolveError{state.ResolvedRetryHooks},
+			waitUnitAgent{
+				status: params.StatusIdle,
+			},
+			waitHooks{"install", "leader-elected", "config-changed", "start"},
+		), ut(
+			"install hook fail, enable retries, retry manually and then automatically",
+			createCharm{badHooks: []string{"install"}},
+			serveCharm{},
+			createUniter{},
+			waitHooks{"fail-install"},
+			waitUnitAgent{
+				statusGetter: unitStatusGetter,
+				status:       params.StatusError,
+				info:         `hook failed: "install"`,
+				data: map[string]interface{}{
+					"hook": "install",
+				},
+			},
+			setHookRetries{maxAttempts: 2, maxDelay: "20ms"},
+			resolveError{state.ResolvedRetryHooks},
+			waitHooks{"fail-install", "fail-install", "fail-install"},
+			waitUnitAgent{
+				statusGetter: unitStatusGetter,
+				status:       params.StatusError,
+				info:         `hook failed: "install"`,
+				data: map[string]interface{}{
+					"hook": "install",
+				},
+			},
+			waitHooks{},
+			fixHook{"install"},
+
+			resolveError{state.ResolvedRetryHooks},
+			waitUnitAgent{
+				status: params.StatusIdle,
+			},
+			waitHooks{"install", "leader-elected", "config-changed", "start"},
+		),
+	})
+}
+
 func (s *UniterSuite) TestUniterUpdateStatusHook(c *gc.C) {
 	s.runUniterTests(c, []uniterTest{
 		ut(
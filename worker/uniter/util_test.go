@@ -1454,6 +1454,21 @@ func (s setProxySettings) step(c *gc.C, ctx *context) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+type setHookRetries struct {
+	maxAttempts int
+	maxDelay    string
+}
+
+func (s setHookRetries) step(c *gc.C, ctx *context) {
+	attrs := map[string]interface{}{
+		"automatically-retry-hooks": true,
+		"hook-retry-max-attempts":   s.maxAttempts,
+		"hook-retry-max-delay":      s.maxDelay,
+	}
+	err := ctx.st.UpdateEnvironConfig(attrs, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 type relationRunCommands []string
 
 func (cmds relationRunCommands) step(c *gc.C, ctx *context) {